@@ -0,0 +1,306 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/nuget"
+)
+
+// shells 支持生成补全脚本的 shell
+var shells = []string{"bash", "zsh", "fish"}
+
+// 选项值的补全方式，未列出的选项不补全取值
+var (
+	fileFlags   = []string{"file", "configfile", "output"}
+	dirFlags    = []string{"dir"}
+	flagChoices = map[string][]string{
+		"level": {"project", "user", "machine"},
+		"to":    formats,
+		"from":  formats,
+	}
+)
+
+// completion 命令在 init 中注册，因为生成补全脚本需要遍历 commands
+func init() {
+	commands["completion"] = command{
+		summary:     "Generate a shell completion script for bash, zsh or fish",
+		subcommands: shells,
+		run:         (*app).runCompletion,
+	}
+}
+
+// completionSpec 一个命令或子命令可以补全的选项
+type completionSpec struct {
+	command    string
+	subcommand string
+	flags      []*flag.Flag
+}
+
+// runCompletion 执行 completion 命令
+//
+// 生成的脚本包含所有命令、子命令和选项，选项直接从各命令的参数集中读取，
+// 因此与当前版本的命令行保持一致。
+func (a *app) runCompletion(args []string) error {
+	shell, args, err := subcommand(args, shells...)
+	if err != nil {
+		return err
+	}
+	fs := a.newFlagSet("completion " + shell)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return usagef("usage: completion bash|zsh|fish")
+	}
+
+	specs := completionSpecs()
+	switch shell {
+	case "bash":
+		_, err = io.WriteString(a.stdout, bashCompletion(specs))
+	case "zsh":
+		_, err = io.WriteString(a.stdout, zshCompletion(specs))
+	default:
+		_, err = io.WriteString(a.stdout, fishCompletion(specs))
+	}
+	return err
+}
+
+// completionSpecs 返回所有命令和子命令的选项，按命令名称排列
+func completionSpecs() []completionSpec {
+	var specs []completionSpec
+	for _, name := range commandNames() {
+		subcommands := commands[name].subcommands
+		if len(subcommands) == 0 {
+			specs = append(specs, completionSpec{command: name, flags: commandFlags(name)})
+			continue
+		}
+		for _, sub := range subcommands {
+			specs = append(specs, completionSpec{command: name, subcommand: sub, flags: commandFlags(name, sub)})
+		}
+	}
+	return specs
+}
+
+// commandFlags 以 -h 执行命令，读取其参数集中定义的选项
+//
+// 各命令在解析参数之前不做任何操作，-h 使解析立即返回 flag.ErrHelp。
+func commandFlags(name string, args ...string) []*flag.Flag {
+	probe := &app{api: nuget.NewAPI(), stdin: strings.NewReader(""), stdout: io.Discard, stderr: io.Discard}
+	commands[name].run(probe, append(args, "-h"))
+
+	var flags []*flag.Flag
+	if probe.flagSet != nil {
+		probe.flagSet.VisitAll(func(f *flag.Flag) {
+			flags = append(flags, f)
+		})
+	}
+	return flags
+}
+
+// flagNames 返回带 -- 前缀的选项名称
+func flagNames(flags []*flag.Flag) []string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "--"+f.Name)
+	}
+	return names
+}
+
+// choiceFlags 返回 flagChoices 中的选项名称，按名称排序
+func choiceFlags() []string {
+	names := make([]string, 0, len(flagChoices))
+	for name := range flagChoices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isBoolFlag 判断选项是否不需要取值
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// quote 用单引号包围字符串，适用于 bash、zsh 和 fish
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// prefixed 为每个名称加上 -- 前缀，并用 | 连接为 case 模式
+func prefixed(names []string) string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = "--" + name
+	}
+	return strings.Join(out, "|")
+}
+
+// bashCompletion 生成 bash 补全脚本
+func bashCompletion(specs []completionSpec) string {
+	var b strings.Builder
+	b.WriteString("# bash completion for nuget-config, generated by \"nuget-config completion bash\"\n")
+	b.WriteString("# Load it with: source <(nuget-config completion bash)\n\n")
+	b.WriteString("_nuget_config() {\n")
+	b.WriteString("    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]} flags=\n")
+	b.WriteString("    COMPREPLY=()\n\n")
+
+	b.WriteString("    case $prev in\n")
+	fmt.Fprintf(&b, "    %s)\n        COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", prefixed(fileFlags))
+	fmt.Fprintf(&b, "    %s)\n        COMPREPLY=($(compgen -d -- \"$cur\")); return ;;\n", prefixed(dirFlags))
+	for _, name := range choiceFlags() {
+		fmt.Fprintf(&b, "    --%s)\n        COMPREPLY=($(compgen -W %s -- \"$cur\")); return ;;\n",
+			name, quote(strings.Join(flagChoices[name], " ")))
+	}
+	b.WriteString("    esac\n\n")
+
+	fmt.Fprintf(&b, "    if [[ $COMP_CWORD -eq 1 ]]; then\n        COMPREPLY=($(compgen -W %s -- \"$cur\")); return\n    fi\n\n",
+		quote(strings.Join(commandNames(), " ")))
+
+	b.WriteString("    case ${COMP_WORDS[1]} in\n")
+	for i := 0; i < len(specs); {
+		name := specs[i].command
+		subcommands := commands[name].subcommands
+		if len(subcommands) == 0 {
+			fmt.Fprintf(&b, "    %s)\n        flags=%s ;;\n", name, quote(strings.Join(flagNames(specs[i].flags), " ")))
+			i++
+			continue
+		}
+
+		fmt.Fprintf(&b, "    %s)\n", name)
+		fmt.Fprintf(&b, "        if [[ $COMP_CWORD -eq 2 ]]; then\n            COMPREPLY=($(compgen -W %s -- \"$cur\")); return\n        fi\n",
+			quote(strings.Join(subcommands, " ")))
+		b.WriteString("        case ${COMP_WORDS[2]} in\n")
+		for ; i < len(specs) && specs[i].command == name; i++ {
+			fmt.Fprintf(&b, "        %s) flags=%s ;;\n", specs[i].subcommand, quote(strings.Join(flagNames(specs[i].flags), " ")))
+		}
+		b.WriteString("        esac ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+
+	b.WriteString("    if [[ $cur == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n")
+	b.WriteString("    else\n")
+	b.WriteString("        COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+	b.WriteString("complete -o filenames -F _nuget_config nuget-config\n")
+	return b.String()
+}
+
+// zshCompletion 生成 zsh 补全脚本
+func zshCompletion(specs []completionSpec) string {
+	var b strings.Builder
+	b.WriteString("#compdef nuget-config\n")
+	b.WriteString("# zsh completion for nuget-config, generated by \"nuget-config completion zsh\"\n")
+	b.WriteString("# Load it with: source <(nuget-config completion zsh)\n\n")
+	b.WriteString("_nuget_config() {\n")
+	b.WriteString("    local cur=${words[CURRENT]} prev=${words[CURRENT-1]}\n")
+	b.WriteString("    local -a flags\n\n")
+
+	b.WriteString("    case $prev in\n")
+	fmt.Fprintf(&b, "    %s) _files; return ;;\n", prefixed(fileFlags))
+	fmt.Fprintf(&b, "    %s) _files -/; return ;;\n", prefixed(dirFlags))
+	for _, name := range choiceFlags() {
+		fmt.Fprintf(&b, "    --%s) compadd -- %s; return ;;\n", name, strings.Join(flagChoices[name], " "))
+	}
+	b.WriteString("    esac\n\n")
+
+	b.WriteString("    if (( CURRENT == 2 )); then\n")
+	b.WriteString("        local -a commands=(\n")
+	for _, name := range commandNames() {
+		fmt.Fprintf(&b, "            %s\n", quote(name+":"+commands[name].summary))
+	}
+	b.WriteString("        )\n")
+	b.WriteString("        _describe -t commands 'nuget-config command' commands; return\n")
+	b.WriteString("    fi\n\n")
+
+	b.WriteString("    case ${words[2]} in\n")
+	for i := 0; i < len(specs); {
+		name := specs[i].command
+		subcommands := commands[name].subcommands
+		if len(subcommands) == 0 {
+			fmt.Fprintf(&b, "    %s) flags=(%s) ;;\n", name, strings.Join(flagNames(specs[i].flags), " "))
+			i++
+			continue
+		}
+
+		fmt.Fprintf(&b, "    %s)\n", name)
+		fmt.Fprintf(&b, "        if (( CURRENT == 3 )); then\n            compadd -- %s; return\n        fi\n", strings.Join(subcommands, " "))
+		b.WriteString("        case ${words[3]} in\n")
+		for ; i < len(specs) && specs[i].command == name; i++ {
+			fmt.Fprintf(&b, "        %s) flags=(%s) ;;\n", specs[i].subcommand, strings.Join(flagNames(specs[i].flags), " "))
+		}
+		b.WriteString("        esac ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+
+	b.WriteString("    if [[ $cur == -* ]]; then\n")
+	b.WriteString("        compadd -- $flags\n")
+	b.WriteString("    else\n")
+	b.WriteString("        _files\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+	b.WriteString("compdef _nuget_config nuget-config\n")
+	return b.String()
+}
+
+// fishCompletion 生成 fish 补全脚本
+func fishCompletion(specs []completionSpec) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for nuget-config, generated by \"nuget-config completion fish\"\n")
+	b.WriteString("# Load it with: nuget-config completion fish | source\n\n")
+
+	for _, name := range commandNames() {
+		fmt.Fprintf(&b, "complete -c nuget-config -n __fish_use_subcommand -f -a %s -d %s\n", name, quote(commands[name].summary))
+	}
+
+	for _, spec := range specs {
+		condition := "__fish_seen_subcommand_from " + spec.command
+		if spec.subcommand != "" {
+			subcommands := strings.Join(commands[spec.command].subcommands, " ")
+			if spec.subcommand == commands[spec.command].subcommands[0] {
+				fmt.Fprintf(&b, "\ncomplete -c nuget-config -n %s -f -a %s\n",
+					quote(condition+"; and not __fish_seen_subcommand_from "+subcommands), quote(subcommands))
+			}
+			condition += "; and __fish_seen_subcommand_from " + spec.subcommand
+		} else {
+			b.WriteString("\n")
+		}
+
+		for _, f := range spec.flags {
+			fmt.Fprintf(&b, "complete -c nuget-config -n %s -l %s%s -d %s\n", quote(condition), f.Name, fishFlagValue(f), quote(f.Usage))
+		}
+	}
+	return b.String()
+}
+
+// fishFlagValue 返回 fish 补全选项值的参数
+func fishFlagValue(f *flag.Flag) string {
+	switch {
+	case isBoolFlag(f):
+		return ""
+	case flagChoices[f.Name] != nil:
+		return " -x -a " + quote(strings.Join(flagChoices[f.Name], " "))
+	case containsString(dirFlags, f.Name):
+		return " -x -a '(__fish_complete_directories)'"
+	case containsString(fileFlags, f.Name):
+		return " -r -F"
+	}
+	return " -x"
+}
+
+// containsString 判断列表中是否包含 s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
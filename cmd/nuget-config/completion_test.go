@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"complete -o filenames -F _nuget_config nuget-config",
+			"'completion config convert credential diff effective lint map merge source'",
+			"add) flags='--configfile --file --json --name --protocol-version' ;;",
+		}},
+		{"zsh", []string{
+			"compdef _nuget_config nuget-config",
+			"'lint:Check configuration files for problems'",
+			"check) flags=(--configfile --file --json) ;;",
+		}},
+		{"fish", []string{
+			"-n '__fish_seen_subcommand_from map; and not __fish_seen_subcommand_from list add remove check' -f -a 'list add remove check'",
+			"-n '__fish_seen_subcommand_from credential; and __fish_seen_subcommand_from set' -l store-password-in-clear-text -d",
+			"-n '__fish_seen_subcommand_from effective' -l dir -x -a '(__fish_complete_directories)'",
+		}},
+	}
+
+	for _, tt := range tests {
+		code, stdout, stderr := runCLI("completion", tt.shell)
+		if code != 0 {
+			t.Fatalf("completion %s exit code = %d, stderr %q", tt.shell, code, stderr)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("completion %s missing %q", tt.shell, want)
+			}
+		}
+
+		// 本机安装了对应的 shell 时检查脚本语法
+		if shell, err := exec.LookPath(tt.shell); err == nil {
+			script := filepath.Join(t.TempDir(), "completion")
+			if err := os.WriteFile(script, []byte(stdout), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(shell, "-n", script).CombinedOutput(); err != nil {
+				t.Errorf("completion %s has syntax errors: %v\n%s", tt.shell, err, out)
+			}
+		}
+	}
+
+	if code, _, _ := runCLI("completion", "powershell"); code != 2 {
+		t.Errorf("completion powershell exit code = %d, want 2", code)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// runConfig 执行 config 命令
+func (a *app) runConfig(args []string) error {
+	name, args, err := subcommand(args, configSubcommands...)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "get":
+		return a.configGet(args)
+	case "set":
+		return a.configSet(args)
+	}
+	return a.configUnset(args)
+}
+
+// configGet 输出配置选项的值
+//
+// 指定了 --file 或 --level 时只读取该配置文件，否则按配置层级从近到远查找，
+// 使用第一个设置了该选项的配置文件中的值。http_proxy.password 等敏感值和 URL 中的密码
+// 显示为 types.RedactedValue，指定 --show-secrets 时才输出原值。
+func (a *app) configGet(args []string) error {
+	fs := a.newFlagSet("config get")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	showPath := fs.Bool("show-path", false, "also print the file the value comes from")
+	showSecrets := fs.Bool("show-secrets", false, "print passwords and tokens instead of "+types.RedactedValue)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: config get <key> [--file <path> | --level <level>]")
+	}
+	key := positional[0]
+
+	var paths []string
+	if *file != "" || *level != "" {
+		path, err := a.targetFile(*file, *level, false)
+		if err != nil {
+			return err
+		}
+		paths = []string{path}
+	} else {
+		hierarchy, err := a.api.FindConfigHierarchy(".")
+		if err != nil {
+			return err
+		}
+		for i := len(hierarchy) - 1; i >= 0; i-- {
+			paths = append(paths, hierarchy[i].Path)
+		}
+	}
+
+	for _, path := range paths {
+		config, err := a.api.ParseFromFile(path)
+		if err != nil {
+			return err
+		}
+		if !*showSecrets {
+			config = config.Redacted()
+		}
+		if value, ok := lookupConfigOption(config, key); ok {
+			if *asJSON {
+				return a.printJSON(configValue{Key: key, Value: value, File: path})
+			}
+			if *showPath {
+				fmt.Fprintf(a.stdout, "%s\t%s\n", value, path)
+			} else {
+				fmt.Fprintln(a.stdout, value)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("config option '%s' not found%s", key, didYouMean(key))
+}
+
+// didYouMean 返回与未知选项键名最接近的已知选项的提示，没有时返回空字符串
+func didYouMean(key string) string {
+	if suggestion := types.SuggestConfigOption(key); suggestion != "" {
+		return fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+	return ""
+}
+
+// configValue config get 在 --json 模式下的输出
+type configValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// File 定义该选项的配置文件
+	File string `json:"file"`
+}
+
+// configSet 设置配置选项，目标配置文件不存在时会被创建
+func (a *app) configSet(args []string) error {
+	fs := a.newFlagSet("config set")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return usagef("usage: config set <key> <value> [--file <path> | --level <level>]")
+	}
+	key, value := positional[0], positional[1]
+	if spec, known := types.LookupConfigOption(key); known {
+		if err := spec.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	path, err := a.targetFile(*file, *level, true)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		return ed.SetConfigOption(key, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "set", Section: "config", Key: key},
+		"Config option '%s' set in %s.", key, path)
+}
+
+// configUnset 删除配置选项
+func (a *app) configUnset(args []string) error {
+	fs := a.newFlagSet("config unset")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: config unset <key> [--file <path> | --level <level>]")
+	}
+	key := positional[0]
+
+	path, err := a.targetFile(*file, *level, false)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		if _, ok := lookupConfigOption(ed.GetConfig(), key); !ok {
+			return fmt.Errorf("config option '%s' not found in %s%s", key, path, didYouMean(key))
+		}
+		return ed.RemoveConfigOption(key)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "unset", Section: "config", Key: key},
+		"Config option '%s' removed from %s.", key, path)
+}
+
+// lookupConfigOption 查找配置中的选项，返回其值和是否存在
+func lookupConfigOption(config *types.NuGetConfig, key string) (string, bool) {
+	if config == nil || config.Config == nil {
+		return "", false
+	}
+	for _, option := range config.Config.Add {
+		if option.Key == key {
+			return option.Value, true
+		}
+	}
+	return "", false
+}
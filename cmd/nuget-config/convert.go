@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// formats convert 命令支持的格式
+var formats = []string{"xml", "json", "yaml", "toml"}
+
+// runConvert 执行 convert 命令
+//
+// 输入格式由 --from 指定，未指定时根据文件扩展名判断，从标准输入读取时根据内容的
+// 第一个字符区分 XML 和 JSON。输出为 XML 时生成可直接使用的 NuGet.Config。
+// 指定 --redact 时密码、API 密钥和令牌被替换为 types.RedactedValue，便于附在问题报告中。
+func (a *app) runConvert(args []string) error {
+	fs := a.newFlagSet("convert")
+	to := fs.String("to", "", "output format: xml, json, yaml or toml")
+	from := fs.String("from", "", "input format, detected from the file extension or content if omitted")
+	output := fs.String("output", "", "write the result to this file instead of standard output")
+	asJSON := fs.Bool("json", false, "shorthand for --to json")
+	redact := fs.Bool("redact", false, "replace passwords, API keys and tokens with "+types.RedactedValue)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		if *to != "" && *to != "json" {
+			return usagef("--json and --to %s cannot be used together", *to)
+		}
+		*to = "json"
+	}
+	if len(positional) > 1 {
+		return usagef("usage: convert [<file> | -] --to <format>")
+	}
+	if !isFormat(*to) {
+		return usagef("--to must be one of: %s", strings.Join(formats, ", "))
+	}
+	if *from != "" && !isFormat(*from) {
+		return usagef("--from must be one of: %s", strings.Join(formats, ", "))
+	}
+
+	input := "-"
+	if len(positional) == 1 {
+		input = positional[0]
+	}
+	var content []byte
+	if input == "-" {
+		content, err = io.ReadAll(a.stdin)
+	} else {
+		content, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	format := *from
+	if format == "" {
+		if format = detectFormat(input, content); format == "" {
+			return usagef("cannot detect the input format, use --from")
+		}
+	}
+
+	config, err := a.parseAs(format, content)
+	if err != nil {
+		return err
+	}
+	if *redact {
+		config = config.Redacted()
+	}
+	result, err := a.serializeAs(*to, config)
+	if err != nil {
+		return err
+	}
+	return a.writeOutput(*output, result)
+}
+
+// isFormat 判断是否为支持的格式
+func isFormat(format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// detectFormat 根据文件扩展名或内容判断输入格式，无法判断时返回空字符串
+func detectFormat(path string, content []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	case ".config", ".xml":
+		return "xml"
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return "xml"
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return "json"
+	}
+	return ""
+}
+
+// parseAs 按指定格式解析配置
+func (a *app) parseAs(format string, content []byte) (*types.NuGetConfig, error) {
+	switch format {
+	case "json":
+		return a.api.ParseFromJSON(string(content))
+	case "yaml":
+		return a.api.ParseFromYAML(string(content))
+	case "toml":
+		return a.api.ParseFromTOML(string(content))
+	}
+	return a.api.ParseFromReader(bytes.NewReader(content))
+}
+
+// serializeAs 按指定格式序列化配置，结果以换行结尾
+func (a *app) serializeAs(format string, config *types.NuGetConfig) (string, error) {
+	var result string
+	var err error
+	switch format {
+	case "xml":
+		return a.configXML(config)
+	case "json":
+		result, err = a.api.SerializeToJSON(config)
+	case "yaml":
+		result, err = a.api.SerializeToYAML(config)
+	case "toml":
+		result, err = a.api.SerializeToTOML(config)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	return result, nil
+}
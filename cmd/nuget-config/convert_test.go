@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+	dir := t.TempDir()
+
+	for _, format := range []string{"json", "yaml", "toml"} {
+		converted := filepath.Join(dir, "NuGet."+format)
+		if code, _, stderr := runCLI("convert", path, "--to", format, "--output", converted); code != 0 {
+			t.Fatalf("convert --to %s exit code = %d, stderr %q", format, code, stderr)
+		}
+
+		code, stdout, stderr := runCLI("convert", converted, "--to", "xml")
+		if code != 0 {
+			t.Fatalf("convert %s --to xml exit code = %d, stderr %q", format, code, stderr)
+		}
+		if !strings.HasPrefix(stdout, "<?xml") || !strings.Contains(stdout, "<configuration>") ||
+			!strings.Contains(stdout, `<add key="local" value="/tmp/packages" />`) ||
+			!strings.Contains(stdout, `<add key="local" value="true" />`) {
+			t.Errorf("convert %s --to xml output:\n%s", format, stdout)
+		}
+	}
+}
+
+func TestConvertStdin(t *testing.T) {
+	code, stdout, stderr := runCLIWithInput(testConfig, "convert", "--to", "yaml")
+	if code != 0 {
+		t.Fatalf("convert from stdin exit code = %d, stderr %q", code, stderr)
+	}
+	if !strings.Contains(stdout, "    - key: nuget.org\n      value: https://api.nuget.org/v3/index.json\n") {
+		t.Errorf("convert --to yaml output:\n%s", stdout)
+	}
+
+	yamlInput := "packageSources:\n  add:\n    - key: feed\n      value: https://feed.example.com/v3/index.json\n"
+	code, stdout, stderr = runCLIWithInput(yamlInput, "convert", "-", "--from", "yaml", "--to", "json")
+	if code != 0 || !strings.Contains(stdout, `"key": "feed"`) {
+		t.Errorf("convert --from yaml --to json = %d, %q, stderr %q", code, stdout, stderr)
+	}
+
+	if code, _, _ := runCLIWithInput(yamlInput, "convert", "--to", "json"); code != 2 {
+		t.Errorf("convert of undetectable input = %d, want 2", code)
+	}
+}
+
+func TestConvertRedact(t *testing.T) {
+	config := strings.Replace(testConfig, "</configuration>", `  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="ci" />
+      <add key="ClearTextPassword" value="s3cret" />
+    </nuget.org>
+  </packageSourceCredentials>
+</configuration>`, 1)
+	code, stdout, stderr := runCLIWithInput(config, "convert", "--to", "xml", "--redact")
+	if code != 0 {
+		t.Fatalf("convert --redact exit code = %d, stderr %q", code, stderr)
+	}
+	if strings.Contains(stdout, "s3cret") || !strings.Contains(stdout, `<add key="ClearTextPassword" value="***" />`) ||
+		!strings.Contains(stdout, `<add key="Username" value="ci" />`) {
+		t.Errorf("convert --redact output:\n%s", stdout)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	if code, _, _ := runCLI("convert", path); code != 2 {
+		t.Errorf("convert without --to = %d, want 2", code)
+	}
+	if code, _, _ := runCLI("convert", path, "--to", "ini"); code != 2 {
+		t.Errorf("convert --to ini = %d, want 2", code)
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.yaml")
+	os.WriteFile(broken, []byte("packageSources:\n\tadd: []\n"), 0644)
+	code, _, stderr := runCLI("convert", broken, "--to", "xml")
+	if code != 1 || !strings.Contains(stderr, "line 2") {
+		t.Errorf("convert of invalid YAML = %d, stderr %q", code, stderr)
+	}
+}
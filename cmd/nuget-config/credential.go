@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/credentials"
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+)
+
+// errEncryptionUnsupported 当前平台无法加密存储密码
+var errEncryptionUnsupported = fmt.Errorf("%w, use --store-password-in-clear-text", credentials.ErrEncryptionUnsupported)
+
+// runCredential 执行 credential 命令
+func (a *app) runCredential(args []string) error {
+	name, args, err := subcommand(args, credentialSubcommands...)
+	if err != nil {
+		return err
+	}
+
+	if name == "set" {
+		return a.credentialSet(args)
+	}
+	return a.credentialRemove(args)
+}
+
+// credentialSet 设置包源的用户名和密码
+//
+// 密码依次从 --password-env 指定的环境变量、--password-stdin 指定的标准输入第一行读取，
+// 都未指定时在终端中不回显地提示输入。默认使用 credentials.DefaultCipher 加密后以 Password 存储，
+// 不支持加密的平台上需要指定 --store-password-in-clear-text。
+func (a *app) credentialSet(args []string) error {
+	fs := a.newFlagSet("credential set")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	username := fs.String("username", "", "user name for the package source")
+	passwordEnv := fs.String("password-env", "", "read the password from this environment variable")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of standard input")
+	clearText := fs.Bool("store-password-in-clear-text", false, "store the password as ClearTextPassword instead of encrypting it")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: credential set <source> --username <name> [--password-env <var> | --password-stdin]")
+	}
+	if *username == "" {
+		return usagef("--username is required")
+	}
+	if *passwordEnv != "" && *passwordStdin {
+		return usagef("--password-env and --password-stdin cannot be used together")
+	}
+	source := positional[0]
+	var cipher credentials.Cipher
+	if !*clearText {
+		if cipher, err = credentials.DefaultCipher(); err != nil {
+			return errEncryptionUnsupported
+		}
+	}
+
+	password, err := a.readPassword(source, *passwordEnv, *passwordStdin)
+	if err != nil {
+		return err
+	}
+	if password == "" {
+		return fmt.Errorf("password must not be empty")
+	}
+	add, update := (*editor.ConfigEditor).AddCredential, (*editor.ConfigEditor).UpdateCredential
+	if cipher != nil {
+		if password, err = cipher.Encrypt(password); err != nil {
+			return err
+		}
+		add, update = (*editor.ConfigEditor).AddEncryptedCredential, (*editor.ConfigEditor).UpdateEncryptedCredential
+	}
+
+	path, err := a.targetFile(*file, *level, true)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		if hasCredential(ed, source) {
+			return update(ed, source, *username, password)
+		}
+		return add(ed, source, *username, password)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "set", Section: "packageSourceCredentials", Key: source},
+		"Credentials for package source '%s' saved to %s.", source, path)
+}
+
+// credentialRemove 删除包源的凭证
+func (a *app) credentialRemove(args []string) error {
+	fs := a.newFlagSet("credential remove")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: credential remove <source>")
+	}
+	source := positional[0]
+
+	path, err := a.targetFile(*file, *level, false)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		if !hasCredential(ed, source) {
+			return fmt.Errorf("no credentials for package source '%s' in %s", source, path)
+		}
+		return ed.RemoveCredential(source)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "remove", Section: "packageSourceCredentials", Key: source},
+		"Credentials for package source '%s' removed from %s.", source, path)
+}
+
+// readPassword 从环境变量、标准输入或终端读取密码
+func (a *app) readPassword(source, env string, fromStdin bool) (string, error) {
+	switch {
+	case env != "":
+		password, ok := os.LookupEnv(env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", env)
+		}
+		return password, nil
+	case fromStdin:
+		line, err := bufio.NewReader(a.stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read password from standard input: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	terminal, ok := a.stdin.(*os.File)
+	if !ok || !isTerminal(terminal) {
+		return "", usagef("standard input is not a terminal, use --password-env or --password-stdin")
+	}
+	fmt.Fprintf(a.stderr, "Password for '%s': ", source)
+	password, err := readNoEcho(terminal)
+	fmt.Fprintln(a.stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return password, nil
+}
+
+// hasCredential 判断编辑中的配置是否包含指定包源的凭证
+func hasCredential(ed *editor.ConfigEditor, source string) bool {
+	creds := ed.GetConfig().PackageSourceCredentials
+	if creds == nil {
+		return false
+	}
+	_, exists := creds.Sources[source]
+	return exists
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// readLine 从终端读取一行，不含换行符
+func readLine(f *os.File) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return strings.TrimRight(string(line), "\r"), nil
+}
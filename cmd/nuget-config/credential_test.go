@@ -0,0 +1,81 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCredentialSet(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	code, _, stderr := runCLIWithInput("s3cret\n", "credential", "set", "nuget.org", "--username", "ci",
+		"--password-stdin", "--store-password-in-clear-text", "--file", path)
+	if code != 0 {
+		t.Fatalf("credential set exit code = %d, stderr %q", code, stderr)
+	}
+	content := readFile(t, path)
+	for _, want := range []string{
+		"  <packageSourceCredentials>\n    <nuget.org>\n",
+		`<add key="Username" value="ci" />`,
+		`<add key="ClearTextPassword" value="s3cret" />`,
+		"<!-- 公司包源 -->",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("credential set result missing %q:\n%s", want, content)
+		}
+	}
+
+	// 已有凭证时只修改其中的值
+	t.Setenv("FEED_PASSWORD", "rotated")
+	code, _, stderr = runCLI("credential", "set", "nuget.org", "--username", "ci2",
+		"--password-env", "FEED_PASSWORD", "--store-password-in-clear-text", "--file", path)
+	if code != 0 {
+		t.Fatalf("credential set with --password-env exit code = %d, stderr %q", code, stderr)
+	}
+	updated := readFile(t, path)
+	if !strings.Contains(updated, `<add key="ClearTextPassword" value="rotated" />`) || strings.Count(updated, "<nuget.org>") != 1 {
+		t.Errorf("credential update result:\n%s", updated)
+	}
+	if strings.Replace(strings.Replace(updated, "rotated", "s3cret", 1), `"ci2"`, `"ci"`, 1) != content {
+		t.Errorf("credential update changed more than the values:\n%s", updated)
+	}
+
+	if code, _, stderr := runCLI("credential", "remove", "nuget.org", "--file", path); code != 0 {
+		t.Fatalf("credential remove exit code = %d, stderr %q", code, stderr)
+	}
+	if strings.Contains(readFile(t, path), "rotated") {
+		t.Error("credential remove left the password in the file")
+	}
+}
+
+func TestCredentialErrors(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"缺少用户名", []string{"credential", "set", "nuget.org", "--password-stdin", "--store-password-in-clear-text"}, 2},
+		{"不支持加密", []string{"credential", "set", "nuget.org", "--username", "u", "--password-stdin"}, 1},
+		{"标准输入不是终端", []string{"credential", "set", "nuget.org", "--username", "u", "--store-password-in-clear-text"}, 2},
+		{"环境变量未设置", []string{"credential", "set", "nuget.org", "--username", "u", "--password-env", "NUGET_CONFIG_TEST_UNSET", "--store-password-in-clear-text"}, 1},
+		{"没有凭证", []string{"credential", "remove", "nuget.org"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "不支持加密" && runtime.GOOS == "windows" {
+				t.Skip("Windows 支持 DPAPI 加密")
+			}
+			args := append(tt.args, "--file", path)
+			if code, _, stderr := runCLIWithInput("pw\n", args...); code != tt.code {
+				t.Errorf("exit code = %d, want %d, stderr %q", code, tt.code, stderr)
+			}
+		})
+	}
+
+	if content := readFile(t, path); content != testConfig {
+		t.Errorf("failed commands modified the file:\n%s", content)
+	}
+}
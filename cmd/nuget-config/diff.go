@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/diff"
+)
+
+// jsonChange JSON 输出中的一处差异
+type jsonChange struct {
+	Section string `json:"section"`
+	Key     string `json:"key,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Kind    string `json:"kind"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+// runDiff 执行 diff 命令
+//
+// 密码等敏感值显示为 diff.MaskedValue。指定 --exit-code 时，存在差异的退出码为 1。
+func (a *app) runDiff(args []string) error {
+	fs := a.newFlagSet("diff")
+	format := fs.String("format", "text", "output format: text or json")
+	exitCode := fs.Bool("exit-code", false, "exit with status 1 if the files differ")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format, err = formatWithJSON(*asJSON, *format, "text"); err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return usagef("usage: diff <old.config> <new.config>")
+	}
+	if *format != "text" && *format != "json" {
+		return usagef("unknown format %q, expected text or json", *format)
+	}
+
+	oldConfig, err := a.api.ParseFromFile(positional[0])
+	if err != nil {
+		return err
+	}
+	newConfig, err := a.api.ParseFromFile(positional[1])
+	if err != nil {
+		return err
+	}
+	changes := diff.Compare(oldConfig, newConfig)
+
+	if *format == "json" {
+		out := make([]jsonChange, 0, len(changes))
+		for _, c := range changes {
+			out = append(out, jsonChange{Section: c.Section, Key: c.Key, Field: c.Field, Kind: c.Kind.String(), Old: c.Old, New: c.New})
+		}
+		if err := a.printJSON(out); err != nil {
+			return err
+		}
+	} else {
+		if len(changes) == 0 {
+			fmt.Fprintln(a.stdout, "No differences.")
+		}
+		marks := map[diff.Kind]string{diff.Added: "+", diff.Removed: "-", diff.Modified: "~"}
+		for _, c := range changes {
+			fmt.Fprintf(a.stdout, "%s %s\n", marks[c.Kind], c)
+		}
+	}
+
+	if *exitCode && len(changes) > 0 {
+		return exitStatus(1)
+	}
+	return nil
+}
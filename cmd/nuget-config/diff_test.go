@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const diffNewConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="company" value="https://nuget.company.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <company>
+      <add key="Username" value="ci" />
+      <add key="ClearTextPassword" value="hunter2" />
+    </company>
+  </packageSourceCredentials>
+</configuration>
+`
+
+func TestDiffText(t *testing.T) {
+	oldPath := writeTestConfig(t, testConfig)
+	newPath := writeTestConfig(t, diffNewConfig)
+
+	code, stdout, stderr := runCLI("diff", oldPath, newPath)
+	if code != 0 {
+		t.Fatalf("diff exit code = %d, stderr %q", code, stderr)
+	}
+	for _, want := range []string{
+		`- packageSources "local" removed: "/tmp/packages"`,
+		`+ packageSources "company" added: "https://nuget.company.com/v3/index.json"`,
+		`+ packageSourceCredentials "company" added`,
+		`- disabledPackageSources "local" removed: "true"`,
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("diff output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	if code, stdout, _ := runCLI("diff", oldPath, oldPath); code != 0 || stdout != "No differences.\n" {
+		t.Errorf("diff of identical files = %d, %q", code, stdout)
+	}
+}
+
+func TestDiffMasksSecrets(t *testing.T) {
+	oldPath := writeTestConfig(t, diffNewConfig)
+	newPath := writeTestConfig(t, strings.Replace(diffNewConfig, "hunter2", "correct horse", 1))
+
+	code, stdout, _ := runCLI("diff", "--format", "json", "--exit-code", oldPath, newPath)
+	if code != 1 {
+		t.Errorf("diff --exit-code with differences = %d, want 1", code)
+	}
+	if strings.Contains(stdout, "hunter2") || strings.Contains(stdout, "correct horse") {
+		t.Errorf("diff output leaks a password:\n%s", stdout)
+	}
+
+	var changes []jsonChange
+	if err := json.Unmarshal([]byte(stdout), &changes); err != nil {
+		t.Fatalf("diff --format json output is not valid JSON: %v\n%s", err, stdout)
+	}
+	want := jsonChange{Section: "packageSourceCredentials", Key: "company", Field: "ClearTextPassword",
+		Kind: "modified", Old: "********", New: "********"}
+	if len(changes) != 1 || changes[0] != want {
+		t.Errorf("diff --format json changes = %+v", changes)
+	}
+
+	if code, _, _ := runCLI("diff", oldPath); code != 2 {
+		t.Errorf("diff with one file = %d, want 2", code)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/diff"
+	"github.com/scagogogo/nuget-config-parser/pkg/settings"
+)
+
+// effectiveEntry 一个生效的配置项及其来源，以及被它覆盖的同名配置项
+type effectiveEntry struct {
+	settings.ReportEntry
+	Origin     string
+	Overridden []shadowedEntry
+}
+
+// shadowedEntry 被覆盖或清除的配置项及其所在的配置文件
+type shadowedEntry struct {
+	settings.ReportEntry
+	Origin string
+}
+
+// effectiveReport effective 命令在 --json 模式下的输出
+type effectiveReport struct {
+	// Files 配置层级中的文件，按优先级从高到低排列
+	Files    []string           `json:"files"`
+	Sections []effectiveSection `json:"sections"`
+	Cleared  []clearedSetting   `json:"cleared"`
+}
+
+// effectiveSection 一个配置节中生效的配置项
+type effectiveSection struct {
+	Name     string             `json:"name"`
+	Settings []effectiveSetting `json:"settings"`
+}
+
+// effectiveSetting 一个生效的配置项及被它覆盖的配置项
+type effectiveSetting struct {
+	Key       string              `json:"key"`
+	Value     string              `json:"value"`
+	File      string              `json:"file"`
+	Overrides []overriddenSetting `json:"overrides"`
+}
+
+// overriddenSetting 被覆盖的配置项
+type overriddenSetting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	File  string `json:"file"`
+}
+
+// clearedSetting 被 <clear /> 清除的配置项
+type clearedSetting struct {
+	Section   string `json:"section"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	File      string `json:"file"`
+	ClearedBy string `json:"clearedBy"`
+}
+
+// runEffective 执行 effective 命令
+//
+// 对 --dir 所在的配置层级调用 settings.HierarchySettings.Explain，按配置节列出每个生效的
+// 配置项、定义它的文件以及被它覆盖的值，最后列出被 <clear /> 清除的配置项。
+// 凭证中的密码等敏感值显示为 diff.MaskedValue。
+func (a *app) runEffective(args []string) error {
+	fs := a.newFlagSet("effective")
+	dir := fs.String("dir", ".", "directory whose configuration hierarchy is shown")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return usagef("usage: effective [--dir <dir>] [--json]")
+	}
+
+	paths, err := a.hierarchyPaths(*dir)
+	if err != nil {
+		return err
+	}
+	// hierarchyPaths 按优先级从低到高排列，Explain 需要从高到低
+	for i, j := 0, len(paths)-1; i < j; i, j = i+1, j-1 {
+		paths[i], paths[j] = paths[j], paths[i]
+	}
+	s, err := settings.LoadHierarchySettings(paths...)
+	if err != nil {
+		return err
+	}
+	sections, entries, cleared := collectEffective(s.Explain())
+	if *asJSON {
+		return a.printJSON(effectiveJSON(paths, sections, entries, cleared))
+	}
+
+	fmt.Fprintln(a.stdout, "Config files (highest priority first):")
+	for i, path := range paths {
+		fmt.Fprintf(a.stdout, "  %d. %s\n", i+1, path)
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(a.stdout, "\n[%s]\n", section)
+		for _, entry := range entries[section] {
+			fmt.Fprintf(a.stdout, "  %s = %s  (%s)\n", entry.Key, maskEntry(entry.ReportEntry), entry.Origin)
+			for _, shadowed := range entry.Overridden {
+				fmt.Fprintf(a.stdout, "    overrides %s = %s  (%s)\n", shadowed.Key, maskEntry(shadowed.ReportEntry), shadowed.Origin)
+			}
+		}
+	}
+
+	if len(cleared) > 0 {
+		fmt.Fprintln(a.stdout, "\nCleared:")
+		for _, entry := range cleared {
+			fmt.Fprintf(a.stdout, "  [%s] %s = %s  (%s, cleared by %s)\n",
+				entry.Section, entry.Key, maskEntry(entry.ReportEntry), entry.Origin, entry.ShadowedBy)
+		}
+	}
+	return nil
+}
+
+// effectiveJSON 将 collectEffective 的结果转换为 JSON 输出的结构，敏感值同样被屏蔽
+func effectiveJSON(paths, sections []string, entries map[string][]*effectiveEntry, cleared []shadowedEntry) effectiveReport {
+	report := effectiveReport{Files: paths, Sections: []effectiveSection{}, Cleared: []clearedSetting{}}
+	for _, section := range sections {
+		out := effectiveSection{Name: section, Settings: []effectiveSetting{}}
+		for _, entry := range entries[section] {
+			setting := effectiveSetting{Key: entry.Key, Value: maskEntry(entry.ReportEntry), File: entry.Origin, Overrides: []overriddenSetting{}}
+			for _, shadowed := range entry.Overridden {
+				setting.Overrides = append(setting.Overrides, overriddenSetting{Key: shadowed.Key, Value: maskEntry(shadowed.ReportEntry), File: shadowed.Origin})
+			}
+			out.Settings = append(out.Settings, setting)
+		}
+		report.Sections = append(report.Sections, out)
+	}
+	for _, entry := range cleared {
+		report.Cleared = append(report.Cleared, clearedSetting{Section: entry.Section, Key: entry.Key,
+			Value: maskEntry(entry.ReportEntry), File: entry.Origin, ClearedBy: entry.ShadowedBy})
+	}
+	return report
+}
+
+// collectEffective 将按文件排列的报告整理为按配置节排列的生效配置项
+//
+// 返回配置节名称（按第一次出现的顺序）、每个配置节中生效的配置项，以及被清除的配置项。
+// 被覆盖的配置项归入覆盖它的文件中同一配置节、同名的生效项；整体覆盖的配置节
+// （如 activePackageSource）中没有同名项时，归入该文件中这一配置节的第一个生效项。
+func collectEffective(report *settings.ConfigChainReport) ([]string, map[string][]*effectiveEntry, []shadowedEntry) {
+	var sections []string
+	entries := make(map[string][]*effectiveEntry)
+	var cleared []shadowedEntry
+
+	for _, file := range report.Files {
+		for _, entry := range file.Entries {
+			switch entry.Status {
+			case settings.EntryEffective:
+				if _, exists := entries[entry.Section]; !exists {
+					sections = append(sections, entry.Section)
+				}
+				entries[entry.Section] = append(entries[entry.Section], &effectiveEntry{ReportEntry: entry, Origin: file.Path})
+			case settings.EntryOverridden:
+				if winner := findWinner(entries[entry.Section], entry); winner != nil {
+					winner.Overridden = append(winner.Overridden, shadowedEntry{ReportEntry: entry, Origin: file.Path})
+				}
+			case settings.EntryCleared:
+				cleared = append(cleared, shadowedEntry{ReportEntry: entry, Origin: file.Path})
+			}
+		}
+	}
+	return sections, entries, cleared
+}
+
+// findWinner 查找覆盖了指定配置项的生效项
+func findWinner(candidates []*effectiveEntry, shadowed settings.ReportEntry) *effectiveEntry {
+	var first *effectiveEntry
+	for _, candidate := range candidates {
+		if candidate.Origin != shadowed.ShadowedBy {
+			continue
+		}
+		if candidate.Key == shadowed.Key {
+			return candidate
+		}
+		if first == nil {
+			first = candidate
+		}
+	}
+	return first
+}
+
+// maskEntry 返回配置项的值，凭证和配置选项中的敏感值显示为 diff.MaskedValue
+func maskEntry(entry settings.ReportEntry) string {
+	secret := strings.HasPrefix(entry.Section, settings.SectionPackageSourceCredentials) || entry.Section == settings.SectionConfig
+	if secret && entry.Value != "" && diff.IsSecretKey(entry.Key) {
+		return diff.MaskedValue
+	}
+	return entry.Value
+}
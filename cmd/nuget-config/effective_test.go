@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEffective(t *testing.T) {
+	userConfig := isolateHome(t)
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(userConfig, []byte(`<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://old.example.com/v3/index.json" />
+    <add key="legacy" value="https://legacy.example.com/nuget" />
+  </packageSources>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="me" />
+      <add key="ClearTextPassword" value="hunter2" />
+    </nuget.org>
+  </packageSourceCredentials>
+</configuration>`), 0644)
+
+	root := t.TempDir()
+	project := filepath.Join(root, "src")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(root, "NuGet.Config"), []byte(testConfig), 0644)
+	os.WriteFile(filepath.Join(project, "NuGet.Config"), []byte(`<configuration>
+  <packageSources>
+    <add key="company" value="https://nuget.company.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <clear />
+  </disabledPackageSources>
+</configuration>`), 0644)
+
+	code, stdout, stderr := runCLI("effective", "--dir", project)
+	if code != 0 {
+		t.Fatalf("effective exit code = %d, stderr %q", code, stderr)
+	}
+
+	projectConfig := filepath.Join(project, "NuGet.Config")
+	rootConfig := filepath.Join(root, "NuGet.Config")
+	for _, want := range []string{
+		"  1. " + projectConfig + "\n  2. " + rootConfig + "\n  3. " + userConfig + "\n",
+		"  company = https://nuget.company.com/v3/index.json  (" + projectConfig + ")\n",
+		"  nuget.org = https://api.nuget.org/v3/index.json  (" + rootConfig + ")\n" +
+			"    overrides nuget.org = https://old.example.com/v3/index.json  (" + userConfig + ")\n",
+		"  legacy = https://legacy.example.com/nuget  (" + userConfig + ")\n",
+		"[packageSourceCredentials/nuget.org]\n  Username = me  (" + userConfig + ")\n  ClearTextPassword = ********",
+		"Cleared:\n  [disabledPackageSources] local = true  (" + rootConfig + ", cleared by " + projectConfig + ")\n",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("effective output missing %q:\n%s", want, stdout)
+		}
+	}
+	if strings.Contains(stdout, "hunter2") {
+		t.Errorf("effective output leaks a password:\n%s", stdout)
+	}
+
+	_, stdout, _ = runCLI("effective", "--dir", project, "--json")
+	var report effectiveReport
+	decodeJSON(t, stdout, &report)
+	if len(report.Files) != 3 || report.Files[0] != projectConfig {
+		t.Errorf("effective --json files = %v", report.Files)
+	}
+	if len(report.Cleared) != 1 || report.Cleared[0] != (clearedSetting{Section: "disabledPackageSources", Key: "local",
+		Value: "true", File: rootConfig, ClearedBy: projectConfig}) {
+		t.Errorf("effective --json cleared = %+v", report.Cleared)
+	}
+	if strings.Contains(stdout, "hunter2") {
+		t.Errorf("effective --json leaks a password:\n%s", stdout)
+	}
+
+	if code, _, _ := runCLI("effective", "extra"); code != 2 {
+		t.Errorf("effective with arguments = %d, want 2", code)
+	}
+}
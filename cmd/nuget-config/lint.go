@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	nugeterrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/validate"
+)
+
+// finding 检查发现的一个问题及其在文件中的位置
+type finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Section  string `json:"section,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// runLint 执行 lint 命令
+//
+// 使用 validate.DefaultRules 检查配置文件，--disable 和 --severity 调整规则。
+// 发现错误时退出码为 1，只有警告时为 0，指定 --warnings-as-errors 后警告也会导致退出码为 1。
+func (a *app) runLint(args []string) error {
+	fs := a.newFlagSet("lint")
+	format := fs.String("format", "text", "output format: text, json or github")
+	warningsAsErrors := fs.Bool("warnings-as-errors", false, "exit with status 1 if any warning is found")
+	disable := fs.String("disable", "", "comma-separated rules to disable")
+	severities := fs.String("severity", "", "comma-separated rule=severity overrides, severity is error, warning or info")
+	listRules := fs.Bool("list-rules", false, "list the available rules and exit")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format, err = formatWithJSON(*asJSON, *format, "text"); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" && *format != "github" {
+		return usagef("unknown format %q, expected text, json or github", *format)
+	}
+	validator, err := newLintValidator(*disable, *severities)
+	if err != nil {
+		return err
+	}
+	if *listRules {
+		for _, rule := range validator.Rules() {
+			severity, _ := validator.Severity(rule.Name())
+			fmt.Fprintf(a.stdout, "%-28s %-8s %s\n", rule.Name(), severity, rule.Description())
+		}
+		return nil
+	}
+
+	paths := positional
+	if len(paths) == 0 {
+		path, err := a.configFile("")
+		if err != nil {
+			return err
+		}
+		paths = []string{path}
+	}
+
+	findings := []finding{}
+	for _, path := range paths {
+		findings = append(findings, a.lintFile(validator, path)...)
+	}
+
+	switch *format {
+	case "json":
+		if err := a.printJSON(findings); err != nil {
+			return err
+		}
+	case "github":
+		for _, f := range findings {
+			fmt.Fprintf(a.stdout, "::%s file=%s,line=%d,col=%d,title=%s::%s\n",
+				githubLevel(f.Severity), escapeProperty(f.File), f.Line, f.Column,
+				escapeProperty("nuget-config lint"), escapeData(f.describe()+hintSuffix(f.Hint)))
+		}
+	default:
+		for _, f := range findings {
+			fmt.Fprintf(a.stdout, "%s:%d:%d: %s: %s\n", f.File, f.Line, f.Column, f.Severity, f.describe())
+			if f.Hint != "" {
+				fmt.Fprintf(a.stdout, "    hint: %s\n", f.Hint)
+			}
+		}
+		errorCount, warningCount := countFindings(findings)
+		fmt.Fprintf(a.stdout, "%d error(s), %d warning(s) in %d file(s)\n", errorCount, warningCount, len(paths))
+	}
+
+	errorCount, warningCount := countFindings(findings)
+	if errorCount > 0 || (*warningsAsErrors && warningCount > 0) {
+		return exitStatus(1)
+	}
+	return nil
+}
+
+// newLintValidator 创建校验器并应用 --disable 和 --severity 选项
+func newLintValidator(disable, severities string) (*validate.Validator, error) {
+	validator := validate.NewValidator()
+	for _, name := range splitList(disable) {
+		if err := validator.Disable(name); err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+	}
+	for _, item := range splitList(severities) {
+		name, level, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, usagef("invalid --severity %q, expected rule=severity", item)
+		}
+		severity, err := validate.ParseSeverity(level)
+		if err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+		if err := validator.SetSeverity(strings.TrimSpace(name), severity); err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+	}
+	return validator, nil
+}
+
+// lintFile 检查一个配置文件，无法解析时返回一个指向出错位置的错误
+func (a *app) lintFile(validator *validate.Validator, path string) []finding {
+	result, err := a.api.ParseFromFileWithPositions(path)
+	if err != nil {
+		// 问题已经带有文件路径，消息中不再重复
+		var configErr *nugeterrors.ConfigError
+		if errors.As(err, &configErr) {
+			err = configErr.Err
+		}
+		f := finding{File: path, Line: 1, Column: 1, Severity: validate.SeverityError.String(),
+			Code: string(nugeterrors.CodeOf(err)), Message: err.Error()}
+		var parseErr *nugeterrors.ParseError
+		if errors.As(err, &parseErr) {
+			f.Rule, f.Section, f.Key = parseErr.Rule, parseErr.Section, parseErr.Key
+			if parseErr.Line > 0 {
+				f.Line, f.Column = parseErr.Line, parseErr.Position
+			}
+		}
+		return []finding{f}
+	}
+
+	var findings []finding
+	for _, issue := range validator.ValidateResult(path, result).Issues {
+		f := finding{File: path, Line: issue.Line, Column: issue.Column, Severity: issue.Severity.String(),
+			Rule: issue.Rule, Code: string(issue.Code), Section: issue.Section, Key: issue.Key, Message: issue.Message, Hint: issue.Hint}
+		if f.Line == 0 {
+			f.Line, f.Column = 1, 1
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// describe 返回不含位置信息的问题描述
+func (f finding) describe() string {
+	if f.Key == "" {
+		if f.Section == "" {
+			return f.Message
+		}
+		return fmt.Sprintf("<%s>: %s", f.Section, f.Message)
+	}
+	return fmt.Sprintf("<%s> '%s': %s", f.Section, f.Key, f.Message)
+}
+
+// hintSuffix 返回附加在单行消息后的修复建议
+func hintSuffix(hint string) string {
+	if hint == "" {
+		return ""
+	}
+	return "\nhint: " + hint
+}
+
+// countFindings 统计错误和警告的数量，info 级别的问题不计入
+func countFindings(findings []finding) (errorCount, warningCount int) {
+	for _, f := range findings {
+		switch f.Severity {
+		case validate.SeverityError.String():
+			errorCount++
+		case validate.SeverityWarning.String():
+			warningCount++
+		}
+	}
+	return errorCount, warningCount
+}
+
+// githubLevel 返回严重程度对应的 GitHub Actions 工作流命令
+func githubLevel(severity string) string {
+	if severity == validate.SeverityInfo.String() {
+		return "notice"
+	}
+	return severity
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// escapeData 转义 GitHub Actions 工作流命令的消息内容
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty 转义 GitHub Actions 工作流命令的属性值
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
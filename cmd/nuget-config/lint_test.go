@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const lintConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="broken" value="https:///index.json" />
+    <add key="NuGet.org" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <ghost>
+      <add key="Username" value="user" />
+    </ghost>
+  </packageSourceCredentials>
+</configuration>
+`
+
+func TestLintText(t *testing.T) {
+	path := writeTestConfig(t, lintConfig)
+
+	code, stdout, _ := runCLI("lint", path)
+	if code != 1 {
+		t.Errorf("lint exit code = %d, want 1", code)
+	}
+	for _, want := range []string{
+		path + ":5:5: error: <packageSources> 'broken': invalid URL",
+		path + ":6:5: error: <packageSources> 'NuGet.org': duplicate package source key",
+		path + ":9:5: warning: <packageSourceCredentials> 'ghost': credentials for unknown package source",
+		"2 error(s), 1 warning(s) in 1 file(s)",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("lint output missing %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestLintFormats(t *testing.T) {
+	path := writeTestConfig(t, lintConfig)
+
+	_, stdout, _ := runCLI("lint", "--format", "json", path)
+	var findings []finding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("lint --format json output is not valid JSON: %v\n%s", err, stdout)
+	}
+	if len(findings) != 4 || findings[0].Line != 5 || findings[0].Key != "broken" || findings[0].Code != "NUGETCFG003" {
+		t.Errorf("lint --format json findings = %+v", findings)
+	}
+
+	_, stdout, _ = runCLI("lint", "--format", "github", path)
+	if !strings.Contains(stdout, "::error file=") || !strings.Contains(stdout, ",line=5,col=5,title=nuget-config lint::") ||
+		!strings.Contains(stdout, "::warning file=") {
+		t.Errorf("lint --format github output:\n%s", stdout)
+	}
+
+	if code, _, _ := runCLI("lint", "--format", "xml", path); code != 2 {
+		t.Errorf("lint with unknown format exit code = %d, want 2", code)
+	}
+}
+
+func TestLintExitCodes(t *testing.T) {
+	clean := writeTestConfig(t, testConfig)
+	if code, stdout, _ := runCLI("lint", clean); code != 0 || !strings.Contains(stdout, "0 error(s), 0 warning(s)") {
+		t.Errorf("lint of a clean file = %d, %q", code, stdout)
+	}
+
+	warnings := writeTestConfig(t, strings.Replace(testConfig, "</configuration>",
+		"  <packageSourceCredentials>\n    <ghost>\n      <add key=\"Username\" value=\"u\" />\n    </ghost>\n  </packageSourceCredentials>\n</configuration>", 1))
+	if code, _, _ := runCLI("lint", warnings); code != 0 {
+		t.Errorf("lint with only warnings = %d, want 0", code)
+	}
+	if code, _, _ := runCLI("lint", "--warnings-as-errors", warnings); code != 1 {
+		t.Errorf("lint --warnings-as-errors with warnings = %d, want 1", code)
+	}
+
+	malformed := writeTestConfig(t, "<configuration>\n  <packageSources>\n</configuration>\n")
+	code, stdout, _ := runCLI("lint", malformed)
+	if code != 1 || !strings.Contains(stdout, malformed+":") || !strings.Contains(stdout, ": error: ") {
+		t.Errorf("lint of a malformed file = %d, %q", code, stdout)
+	}
+	if strings.Count(stdout, malformed) != 1 {
+		t.Errorf("lint of a malformed file repeats the path: %q", stdout)
+	}
+}
+
+func TestLintRuleOptions(t *testing.T) {
+	path := writeTestConfig(t, lintConfig)
+
+	code, stdout, _ := runCLI("lint", "--disable", "duplicate-source-key,invalid-source-value", path)
+	if code != 0 || !strings.Contains(stdout, "0 error(s), 1 warning(s)") {
+		t.Errorf("lint --disable = %d, %q", code, stdout)
+	}
+
+	code, stdout, _ = runCLI("lint", "--disable", "duplicate-source-key,invalid-source-value",
+		"--severity", "orphaned-entry=info", "--format", "github", path)
+	if code != 0 || !strings.Contains(stdout, "::notice file=") {
+		t.Errorf("lint --severity info = %d, %q", code, stdout)
+	}
+
+	_, stdout, _ = runCLI("lint", "--json", path)
+	var findings []finding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil || len(findings) == 0 || findings[0].Rule != "invalid-source-value" {
+		t.Errorf("lint --json findings = %+v, %v", findings, err)
+	}
+
+	_, stdout, _ = runCLI("lint", "--list-rules")
+	if !strings.Contains(stdout, "duplicate-source-key") || !strings.Contains(stdout, "warning") {
+		t.Errorf("lint --list-rules output:\n%s", stdout)
+	}
+
+	for _, args := range [][]string{{"--disable", "no-such-rule"}, {"--severity", "duplicate-source-key=fatal"}, {"--severity", "duplicate-source-key"}} {
+		if code, _, _ := runCLI(append(append([]string{"lint"}, args...), path)...); code != 2 {
+			t.Errorf("lint %v exit code = %d, want 2", args, code)
+		}
+	}
+}
+
+func TestLintCredentials(t *testing.T) {
+	path := writeTestConfig(t, strings.Replace(testConfig, "</configuration>", `  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="me" />
+      <add key="ClearTextPassword" value="hunter2" />
+      <add key="ValidAuthenticationTypes" value="basic" />
+    </nuget.org>
+  </packageSourceCredentials>
+</configuration>`, 1))
+
+	code, stdout, _ := runCLI("lint", path)
+	for _, want := range []string{
+		path + ":14:7: warning: <packageSourceCredentials> 'nuget.org': password is stored in clear text\n    hint: ",
+		path + ":14:7: error: <packageSourceCredentials> 'nuget.org': password is stored in a project-level config",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("lint output missing %q:\n%s", want, stdout)
+		}
+	}
+	if code != 1 {
+		t.Errorf("lint exit code = %d, want 1", code)
+	}
+}
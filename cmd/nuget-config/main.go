@@ -0,0 +1,308 @@
+// Command nuget-config 是基于本库的 NuGet 配置文件命令行工具
+//
+// 修改配置文件时使用位置感知编辑器，只改写发生变化的部分，保留原有的格式和注释。
+// 各命令支持 --json 输出结构稳定的 JSON，便于在脚本中使用，错误信息始终输出到标准错误；
+// completion 命令生成 bash、zsh 和 fish 的补全脚本。
+//
+// 用法:
+//
+//	nuget-config <command> [arguments]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/nuget"
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// command 一个顶层命令
+type command struct {
+	summary string
+	// subcommands 命令的子命令，没有子命令时为空
+	subcommands []string
+	run         func(a *app, args []string) error
+}
+
+// 各命令的子命令，也用于生成补全脚本
+var (
+	configSubcommands     = []string{"get", "set", "unset"}
+	credentialSubcommands = []string{"set", "remove"}
+	mapSubcommands        = []string{"list", "add", "remove", "check"}
+	sourceSubcommands     = []string{"list", "add", "remove", "enable", "disable", "update"}
+)
+
+// commands 所有顶层命令，按名称查找，completion 命令在 completion.go 中注册
+var commands = map[string]command{
+	"config":     {summary: "Get, set or unset global configuration options", subcommands: configSubcommands, run: (*app).runConfig},
+	"convert":    {summary: "Convert a config between XML, JSON, YAML and TOML", run: (*app).runConvert},
+	"credential": {summary: "Set or remove package source credentials", subcommands: credentialSubcommands, run: (*app).runCredential},
+	"diff":       {summary: "Show the semantic differences between two config files", run: (*app).runDiff},
+	"effective":  {summary: "Show the effective settings for a directory and where they come from", run: (*app).runEffective},
+	"lint":       {summary: "Check configuration files for problems", run: (*app).runLint},
+	"map":        {summary: "List, add, remove or check package source mapping patterns", subcommands: mapSubcommands, run: (*app).runMap},
+	"merge":      {summary: "Print the effective config produced by merging config files", run: (*app).runMerge},
+	"source":     {summary: "List, add, remove, enable, disable or update package sources", subcommands: sourceSubcommands, run: (*app).runSource},
+}
+
+// app 命令行工具的运行环境
+type app struct {
+	api    *nuget.API
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// flagSet 最近创建的参数集，生成补全脚本时用于读取命令的选项
+	flagSet *flag.FlagSet
+}
+
+// usageError 命令行参数错误，退出码为 2
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// exitStatus 命令已经输出了结果，只需以指定的退出码结束
+type exitStatus int
+
+func (e exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// usagef 创建命令行参数错误
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回退出码：0 成功，1 执行失败或检查未通过，2 参数错误
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	a := &app{api: nuget.NewAPI(), stdin: stdin, stdout: stdout, stderr: stderr}
+	// 用户级和机器级配置文件通常只包含配置选项，不定义包源
+	a.api.Parser.AllowEmptyPackageSources = true
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.printUsage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "nuget-config: unknown command %q\n", args[0])
+		a.printUsage()
+		return 2
+	}
+
+	err := cmd.run(a, args[1:])
+	var usageErr *usageError
+	var status exitStatus
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &status):
+		return int(status)
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "nuget-config %s: %v\n", args[0], err)
+		return 2
+	default:
+		fmt.Fprintf(stderr, "nuget-config %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+// printUsage 输出顶层命令列表
+func (a *app) printUsage() {
+	fmt.Fprintln(a.stderr, "Usage: nuget-config <command> [arguments]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+
+	for _, name := range commandNames() {
+		fmt.Fprintf(a.stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// commandNames 返回按名称排序的顶层命令
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newFlagSet 创建子命令的参数集，错误由调用方输出
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.flagSet = fs
+	return fs
+}
+
+// parseFlags 解析参数，允许选项出现在位置参数之后，返回所有位置参数
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// configFile 返回要操作的配置文件，未指定时使用查找到的第一个配置文件
+func (a *app) configFile(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	found, err := a.api.FindConfigFile()
+	if err != nil {
+		return "", fmt.Errorf("no NuGet.Config found, use --file to specify one")
+	}
+	return found, nil
+}
+
+// fileFlag 为参数集添加 --file 选项及其别名 --configfile
+func fileFlag(fs *flag.FlagSet) *string {
+	file := fs.String("file", "", "path to the NuGet.Config file to use")
+	fs.StringVar(file, "configfile", "", "alias for --file")
+	return file
+}
+
+// levelFlag 为参数集添加 --level 选项
+func levelFlag(fs *flag.FlagSet) *string {
+	return fs.String("level", "", "configuration level to use: project, user or machine")
+}
+
+// levelFile 返回指定级别的配置文件路径
+//
+// project 级别使用当前目录或上层目录中最近的配置文件，都不存在时使用当前目录中的
+// NuGet.Config；user 和 machine 级别使用查找器给出的默认路径，文件不一定存在。
+func (a *app) levelFile(level string) (string, error) {
+	switch level {
+	case "project":
+		if found, err := a.api.FindProjectConfig("."); err == nil {
+			return found, nil
+		}
+		return constants.DefaultNuGetConfigFilename, nil
+	case "user":
+		if path := a.api.Finder.GetUserConfigFile(); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("cannot determine the user configuration file")
+	case "machine":
+		if path := a.api.Finder.GetMachineConfigFile(); path != "" {
+			return path, nil
+		}
+		return "", fmt.Errorf("cannot determine the machine configuration file")
+	}
+	return "", usagef("unknown level %q, expected project, user or machine", level)
+}
+
+// targetFile 根据 --file 和 --level 返回要修改的配置文件
+//
+// 两者都未指定时使用查找到的第一个配置文件。create 为 true 且文件不存在时，
+// 创建一个只包含 <configuration> 元素的配置文件。
+func (a *app) targetFile(file, level string, create bool) (string, error) {
+	if file != "" && level != "" {
+		return "", usagef("--file and --level cannot be used together")
+	}
+
+	var path string
+	var err error
+	if level != "" {
+		path, err = a.levelFile(level)
+	} else {
+		path, err = a.configFile(file)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if create {
+		if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return "", fmt.Errorf("failed to create directory for %s: %w", path, err)
+			}
+			if err := os.WriteFile(path, []byte(emptyConfig), 0644); err != nil {
+				return "", fmt.Errorf("failed to create %s: %w", path, err)
+			}
+		}
+	}
+	return path, nil
+}
+
+// emptyConfig 新建配置文件的内容
+const emptyConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+</configuration>
+`
+
+// configXML 按 NuGet 官方格式序列化配置：空元素使用自闭合标签，以换行结尾
+func (a *app) configXML(config *types.NuGetConfig) (string, error) {
+	opts := parser.DefaultSerializeOptions()
+	style := parser.DefaultElementStyle()
+	opts.ElementStyle = &style
+
+	content, err := a.api.SerializeToXMLWithOptions(config, opts)
+	if err != nil {
+		return "", err
+	}
+	return content + "\n", nil
+}
+
+// writeOutput 将内容写入文件，path 为空时输出到标准输出
+func (a *app) writeOutput(path, content string) error {
+	if path == "" {
+		_, err := io.WriteString(a.stdout, content)
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// subcommand 返回第一个参数作为子命令名，并检查是否在允许的范围内
+func subcommand(args []string, allowed ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, usagef("missing subcommand, expected one of: %s", strings.Join(allowed, ", "))
+	}
+	for _, name := range allowed {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+	return "", nil, usagef("unknown subcommand %q, expected one of: %s", args[0], strings.Join(allowed, ", "))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <!-- 公司包源 -->
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="local" value="/tmp/packages" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="local" value="true" />
+  </disabledPackageSources>
+</configuration>
+`
+
+// writeTestConfig 在临时目录中写入配置文件并返回其路径
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "NuGet.Config")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+// runCLI 执行命令并返回退出码和输出
+func runCLI(args ...string) (int, string, string) {
+	return runCLIWithInput("", args...)
+}
+
+// runCLIWithInput 以 input 作为标准输入执行命令
+func runCLIWithInput(input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// readFile 读取文件内容
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRunUsage(t *testing.T) {
+	if code, _, stderr := runCLI(); code != 2 || !strings.Contains(stderr, "source") {
+		t.Errorf("run() without arguments = %d, stderr %q", code, stderr)
+	}
+	if code, _, _ := runCLI("help"); code != 0 {
+		t.Errorf("run(help) = %d, want 0", code)
+	}
+	if code, _, stderr := runCLI("bogus"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("run(bogus) = %d, stderr %q", code, stderr)
+	}
+	if code, _, _ := runCLI("source", "frobnicate"); code != 2 {
+		t.Errorf("run(source frobnicate) = %d, want 2", code)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// mappingGroup map list 在 --json 模式下输出的一个包源映射分组
+type mappingGroup struct {
+	Source   string   `json:"source"`
+	Patterns []string `json:"patterns"`
+}
+
+// mappingCheck map check 在 --json 模式下的输出
+type mappingCheck struct {
+	PackageID      string `json:"packageId"`
+	MappingEnabled bool   `json:"mappingEnabled"`
+	// Pattern 匹配包ID的模式，没有模式匹配或未启用包源映射时为空
+	Pattern string         `json:"pattern,omitempty"`
+	Sources []mappedSource `json:"sources"`
+}
+
+// mappedSource 可以提供包的一个包源，Status 为 available、disabled 或 undefined
+type mappedSource struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// runMap 执行 map 命令
+func (a *app) runMap(args []string) error {
+	name, args, err := subcommand(args, mapSubcommands...)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		return a.mapList(args)
+	case "add":
+		return a.mapAdd(args)
+	case "remove":
+		return a.mapRemove(args)
+	}
+	return a.mapCheck(args)
+}
+
+// mapList 列出配置文件中的包源映射
+func (a *app) mapList(args []string) error {
+	fs := a.newFlagSet("map list")
+	file := fileFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return usagef("map list takes no arguments")
+	}
+
+	path, err := a.configFile(*file)
+	if err != nil {
+		return err
+	}
+	config, err := a.api.ParseFromFile(path)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		groups := []mappingGroup{}
+		if config.PackageSourceMapping != nil {
+			for _, group := range config.PackageSourceMapping.PackageSource {
+				patterns := make([]string, 0, len(group.Package))
+				for _, pkg := range group.Package {
+					patterns = append(patterns, pkg.Pattern)
+				}
+				groups = append(groups, mappingGroup{Source: group.Key, Patterns: patterns})
+			}
+		}
+		return a.printJSON(groups)
+	}
+	if config.PackageSourceMapping == nil || len(config.PackageSourceMapping.PackageSource) == 0 {
+		fmt.Fprintln(a.stdout, "No package source mapping defined.")
+		return nil
+	}
+	fmt.Fprintln(a.stdout, "Package source mapping:")
+	for _, group := range config.PackageSourceMapping.PackageSource {
+		fmt.Fprintf(a.stdout, "  %s\n", group.Key)
+		for _, pkg := range group.Package {
+			fmt.Fprintf(a.stdout, "    %s\n", pkg.Pattern)
+		}
+	}
+	return nil
+}
+
+// mapAdd 为包源添加包ID模式，目标配置文件不存在时会被创建
+func (a *app) mapAdd(args []string) error {
+	fs := a.newFlagSet("map add")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return usagef("usage: map add <source> <pattern>... [--file <path> | --level <level>]")
+	}
+	source, patterns := positional[0], positional[1:]
+	for _, pattern := range patterns {
+		if err := validatePattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	path, err := a.targetFile(*file, *level, true)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		return ed.AddPackageSourceMapping(source, patterns...)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "add", Section: "packageSourceMapping", Key: source, Patterns: patterns},
+		"Package source mapping for '%s' updated in %s.", source, path)
+}
+
+// mapRemove 删除包源的指定模式，未指定模式时删除该包源的整个映射
+func (a *app) mapRemove(args []string) error {
+	fs := a.newFlagSet("map remove")
+	file := fileFlag(fs)
+	level := levelFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 {
+		return usagef("usage: map remove <source> [<pattern>...] [--file <path> | --level <level>]")
+	}
+	source, patterns := positional[0], positional[1:]
+
+	path, err := a.targetFile(*file, *level, false)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		group := findMappingGroup(ed.GetConfig(), source)
+		if group == nil {
+			return fmt.Errorf("package source mapping for '%s' not found", source)
+		}
+		if len(patterns) == 0 {
+			return ed.RemovePackageSourceMapping(source)
+		}
+
+		for _, pattern := range patterns {
+			if !hasPattern(group, pattern) {
+				return fmt.Errorf("pattern '%s' not found in package source mapping for '%s'", pattern, source)
+			}
+		}
+		for _, pattern := range patterns {
+			if err := ed.RemovePackagePattern(source, pattern); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result := changeResult{File: path, Action: "remove", Section: "packageSourceMapping", Key: source, Patterns: patterns}
+	if len(patterns) == 0 {
+		return a.reportChange(*asJSON, result, "Package source mapping for '%s' removed from %s.", source, path)
+	}
+	return a.reportChange(*asJSON, result, "Removed %d pattern(s) for '%s' from %s.", len(patterns), source, path)
+}
+
+// mapCheck 报告哪些包源会提供指定的包
+//
+// 指定了 --file 时只使用该配置文件，否则使用从当前目录查找到的配置层级合并后的结果。
+// 没有可用的包源能提供该包时退出码为 1。
+func (a *app) mapCheck(args []string) error {
+	fs := a.newFlagSet("map check")
+	file := fileFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: map check <packageId> [--file <path>] [--json]")
+	}
+	packageID := positional[0]
+
+	var config *types.NuGetConfig
+	if *file != "" {
+		if config, err = a.api.ParseFromFile(*file); err != nil {
+			return err
+		}
+	} else {
+		paths, err := a.hierarchyPaths(".")
+		if err != nil {
+			return err
+		}
+		if config, _, err = a.mergeFiles(paths); err != nil {
+			return err
+		}
+	}
+
+	match := a.api.FindSourcesForPackage(config, packageID)
+	result := mappingCheck{PackageID: packageID, MappingEnabled: match.MappingEnabled, Pattern: match.Pattern, Sources: []mappedSource{}}
+	available := 0
+	for _, name := range match.Sources {
+		status := "available"
+		switch {
+		case a.api.GetPackageSource(config, name) == nil:
+			status = "undefined"
+		case a.api.IsPackageSourceDisabled(config, name):
+			status = "disabled"
+		default:
+			available++
+		}
+		result.Sources = append(result.Sources, mappedSource{Name: name, Status: status})
+	}
+
+	if *asJSON {
+		if err := a.printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		a.printMappingCheck(result)
+	}
+	if available == 0 {
+		return exitStatus(1)
+	}
+	return nil
+}
+
+// printMappingCheck 输出 map check 的文本结果
+func (a *app) printMappingCheck(result mappingCheck) {
+	switch {
+	case !result.MappingEnabled:
+		fmt.Fprintf(a.stdout, "Package source mapping is not enabled, '%s' can come from any enabled source:\n", result.PackageID)
+	case result.Pattern == "":
+		fmt.Fprintf(a.stdout, "No pattern matches '%s', the package cannot be restored.\n", result.PackageID)
+		return
+	default:
+		fmt.Fprintf(a.stdout, "Package '%s' matches pattern '%s':\n", result.PackageID, result.Pattern)
+	}
+
+	notes := map[string]string{"undefined": " (not defined)", "disabled": " (disabled)"}
+	available := false
+	for _, source := range result.Sources {
+		fmt.Fprintf(a.stdout, "  %s%s\n", source.Name, notes[source.Status])
+		available = available || source.Status == "available"
+	}
+	if !available {
+		fmt.Fprintln(a.stdout, "No available source can serve the package.")
+	}
+}
+
+// validatePattern 检查包ID模式，* 只能出现在模式末尾
+func validatePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return usagef("pattern must not be empty")
+	}
+	if i := strings.Index(pattern, "*"); i >= 0 && i != len(pattern)-1 {
+		return usagef("invalid pattern %q, '*' is only allowed at the end", pattern)
+	}
+	return nil
+}
+
+// findMappingGroup 查找包源的映射分组
+func findMappingGroup(config *types.NuGetConfig, source string) *types.PackageSourceMappingSource {
+	if config.PackageSourceMapping == nil {
+		return nil
+	}
+	for i, group := range config.PackageSourceMapping.PackageSource {
+		if group.Key == source {
+			return &config.PackageSourceMapping.PackageSource[i]
+		}
+	}
+	return nil
+}
+
+// hasPattern 判断映射分组中是否包含指定模式
+func hasPattern(group *types.PackageSourceMappingSource, pattern string) bool {
+	for _, pkg := range group.Package {
+		if pkg.Pattern == pattern {
+			return true
+		}
+	}
+	return false
+}
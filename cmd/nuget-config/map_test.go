@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMapEditing(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	steps := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"map", "add", "nuget.org", "*"}, `<package pattern="*" />`},
+		{[]string{"map", "add", "local", "Contoso.*", "Fabrikam.Core"}, `<package pattern="Fabrikam.Core" />`},
+		{[]string{"map", "remove", "local", "Fabrikam.Core"}, `<package pattern="Contoso.*" />`},
+	}
+	for _, step := range steps {
+		args := append(step.args, "--file", path)
+		if code, _, stderr := runCLI(args...); code != 0 {
+			t.Fatalf("%v exit code = %d, stderr %q", step.args, code, stderr)
+		}
+		if content := readFile(t, path); !strings.Contains(content, step.want) {
+			t.Errorf("%v result missing %q:\n%s", step.args, step.want, content)
+		}
+	}
+
+	_, stdout, _ := runCLI("map", "list", "--file", path)
+	if want := "Package source mapping:\n  nuget.org\n    *\n  local\n    Contoso.*\n"; stdout != want {
+		t.Errorf("map list = %q, want %q", stdout, want)
+	}
+
+	if code, _, stderr := runCLI("map", "remove", "local", "--file", path); code != 0 {
+		t.Fatalf("map remove exit code = %d, stderr %q", code, stderr)
+	}
+	content := readFile(t, path)
+	if strings.Contains(content, `key="local"`) && strings.Contains(content, "Contoso.*") {
+		t.Errorf("map remove left the local mapping:\n%s", content)
+	}
+	if !strings.Contains(content, "<!-- 公司包源 -->") {
+		t.Errorf("editing lost comment:\n%s", content)
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"map", "add", "nuget.org"}, 2},
+		{[]string{"map", "add", "nuget.org", "Contoso.*.Core"}, 2},
+		{[]string{"map", "remove", "nuget.org"}, 1},
+		{[]string{"map", "check"}, 2},
+		{[]string{"map", "list", "extra"}, 2},
+	}
+	for _, tt := range tests {
+		args := append(tt.args, "--file", path)
+		if code, _, _ := runCLI(args...); code != tt.code {
+			t.Errorf("%v exit code = %d, want %d", tt.args, code, tt.code)
+		}
+	}
+}
+
+func TestMapCheck(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	code, stdout, _ := runCLI("map", "check", "Newtonsoft.Json", "--file", path)
+	if code != 0 || stdout != "Package source mapping is not enabled, 'Newtonsoft.Json' can come from any enabled source:\n  nuget.org\n" {
+		t.Errorf("map check without mapping = %d, %q", code, stdout)
+	}
+
+	for _, args := range [][]string{{"nuget.org", "*"}, {"local", "Contoso.*"}} {
+		runCLI(append([]string{"map", "add", "--file", path}, args...)...)
+	}
+
+	code, stdout, _ = runCLI("map", "check", "Newtonsoft.Json", "--file", path)
+	if code != 0 || stdout != "Package 'Newtonsoft.Json' matches pattern '*':\n  nuget.org\n" {
+		t.Errorf("map check Newtonsoft.Json = %d, %q", code, stdout)
+	}
+
+	// local 已被禁用，没有可用的包源
+	code, stdout, _ = runCLI("map", "check", "contoso.core", "--file", path)
+	if code != 1 || !strings.Contains(stdout, "  local (disabled)\n") {
+		t.Errorf("map check contoso.core = %d, %q", code, stdout)
+	}
+
+	runCLI("map", "remove", "nuget.org", "--file", path)
+	code, stdout, _ = runCLI("map", "check", "Newtonsoft.Json", "--file", path)
+	if code != 1 || !strings.Contains(stdout, "No pattern matches 'Newtonsoft.Json'") {
+		t.Errorf("map check without matching pattern = %d, %q", code, stdout)
+	}
+}
+
+func TestMapCheckHierarchy(t *testing.T) {
+	isolateHome(t)
+	root := t.TempDir()
+	project := filepath.Join(root, "src")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "NuGet.Config"), []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mapping := `<configuration>
+  <packageSourceMapping>
+    <packageSource key="nuget.org">
+      <package pattern="Contoso.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>
+`
+	if err := os.WriteFile(filepath.Join(project, "NuGet.Config"), []byte(mapping), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, project)
+
+	// 映射定义在项目目录中，包源定义在上级目录中
+	code, stdout, stderr := runCLI("map", "check", "Contoso.Core")
+	if code != 0 || stdout != "Package 'Contoso.Core' matches pattern 'Contoso.*':\n  nuget.org\n" {
+		t.Errorf("map check in hierarchy = %d, %q, stderr %q", code, stdout, stderr)
+	}
+}
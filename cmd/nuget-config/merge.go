@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// runMerge 执行 merge 命令
+//
+// 配置文件按优先级从低到高排列，后面的文件覆盖前面的文件，包含 <clear /> 的配置节
+// 丢弃之前所有文件中的同名配置节。指定 --auto 时合并从 --dir 向上查找到的配置层级，
+// 顺序为机器级、用户级，再到离目录最近的项目级配置文件。
+func (a *app) runMerge(args []string) error {
+	fs := a.newFlagSet("merge")
+	auto := fs.Bool("auto", false, "merge the configuration hierarchy discovered from --dir")
+	dir := fs.String("dir", ".", "directory to discover the configuration hierarchy from")
+	withOrigins := fs.Bool("origins", false, "annotate each entry with a comment naming the file it comes from")
+	output := fs.String("output", "", "write the merged config to this file instead of standard output")
+	asJSON := fs.Bool("json", false, "print the merged config as JSON instead of XML")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *asJSON && *withOrigins {
+		return usagef("--json and --origins cannot be used together")
+	}
+	if *auto == (len(positional) > 0) {
+		return usagef("usage: merge <lowest-priority.config> ... <highest-priority.config> | merge --auto [--dir <dir>]")
+	}
+
+	paths := positional
+	if *auto {
+		if paths, err = a.hierarchyPaths(*dir); err != nil {
+			return err
+		}
+	}
+
+	merged, origin, err := a.mergeFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	format := "xml"
+	if *asJSON {
+		format = "json"
+	}
+	content, err := a.serializeAs(format, merged)
+	if err != nil {
+		return err
+	}
+	if *withOrigins {
+		if content, err = origin.annotate(content, paths); err != nil {
+			return err
+		}
+	}
+	return a.writeOutput(*output, content)
+}
+
+// hierarchyPaths 返回从目录向上查找到的配置文件，按优先级从低到高排列
+func (a *app) hierarchyPaths(dir string) ([]string, error) {
+	hierarchy, err := a.api.FindConfigHierarchy(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(hierarchy) == 0 {
+		return nil, fmt.Errorf("no NuGet.Config found for %s", dir)
+	}
+
+	paths := make([]string, len(hierarchy))
+	for i, found := range hierarchy {
+		paths[i] = found.Path
+	}
+	return paths, nil
+}
+
+// mergeFiles 按优先级从低到高合并配置文件，同时记录每一项的来源
+func (a *app) mergeFiles(paths []string) (*types.NuGetConfig, origins, error) {
+	merged := &types.NuGetConfig{}
+	origin := make(origins)
+	for _, path := range paths {
+		config, err := a.api.ParseFromFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if merged, err = manager.MergeConfigs(merged, config, manager.MergeOverlayWins); err != nil {
+			return nil, nil, err
+		}
+		origin.record(path, config)
+	}
+	return merged, origin, nil
+}
+
+// origins 记录合并结果中每一项来自哪个配置文件，键依次为配置节名称和项的键
+type origins map[string]map[string]string
+
+// record 记录配置中各项的来源，配置节包含 <clear /> 时先丢弃该配置节之前的记录
+func (o origins) record(path string, config *types.NuGetConfig) {
+	set := func(section string, cleared bool, keys ...string) {
+		if cleared || o[section] == nil {
+			o[section] = make(map[string]string)
+		}
+		for _, key := range keys {
+			o[section][key] = path
+		}
+	}
+
+	var keys []string
+	for _, source := range config.PackageSources.Add {
+		keys = append(keys, source.Key)
+	}
+	set("packageSources", config.PackageSources.IsCleared(), keys...)
+
+	if creds := config.PackageSourceCredentials; creds != nil {
+		keys = keys[:0]
+		for name := range creds.Sources {
+			keys = append(keys, name)
+		}
+		set("packageSourceCredentials", false, keys...)
+	}
+	if c := config.Config; c != nil {
+		keys = keys[:0]
+		for _, option := range c.Add {
+			keys = append(keys, option.Key)
+		}
+		set("config", c.IsCleared(), keys...)
+	}
+	if disabled := config.DisabledPackageSources; disabled != nil {
+		keys = keys[:0]
+		for _, source := range disabled.Add {
+			keys = append(keys, source.Key)
+		}
+		set("disabledPackageSources", disabled.IsCleared(), keys...)
+	}
+	if active := config.ActivePackageSource; active != nil && active.Add.Key != "" {
+		set("activePackageSource", true, active.Add.Key)
+	}
+	if mapping := config.PackageSourceMapping; mapping != nil {
+		keys = keys[:0]
+		for _, source := range mapping.PackageSource {
+			keys = append(keys, source.Key)
+		}
+		set("packageSourceMapping", mapping.IsCleared(), keys...)
+	}
+}
+
+// annotate 在合并结果的每一项前插入注明来源文件的注释，并在根元素前列出合并的文件
+func (o origins) annotate(content string, paths []string) (string, error) {
+	type insertion struct {
+		offset int
+		text   string
+	}
+	var insertions []insertion
+
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	var stack []string
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to annotate merged config: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 {
+				var header strings.Builder
+				header.WriteString("<!-- Merged from (lowest to highest priority):\n")
+				for _, path := range paths {
+					fmt.Fprintf(&header, "       %s\n", escapeComment(path))
+				}
+				header.WriteString("-->\n")
+				insertions = append(insertions, insertion{offset, header.String()})
+			}
+			if len(stack) == 2 {
+				// 凭证元素名是编码后的包源名称，需要解码后查找
+				key := types.DecodeElementName(t.Name.Local)
+				if stack[1] != "packageSourceCredentials" {
+					key = attrValue(t, "key")
+				}
+				if path, ok := o[stack[1]][key]; ok {
+					indent := content[strings.LastIndex(content[:offset], "\n")+1 : offset]
+					insertions = append(insertions, insertion{offset, fmt.Sprintf("<!-- from %s -->\n%s", escapeComment(path), indent)})
+				}
+			}
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, ins := range insertions {
+		b.WriteString(content[last:ins.offset])
+		b.WriteString(ins.text)
+		last = ins.offset
+	}
+	b.WriteString(content[last:])
+	return b.String(), nil
+}
+
+// attrValue 返回元素中指定属性的值
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// escapeComment 避免路径中的 "--" 破坏XML注释
+func escapeComment(s string) string {
+	return strings.ReplaceAll(s, "--", "- -")
+}
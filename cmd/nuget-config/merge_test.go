@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const overlayConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="company" value="https://nuget.company.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <add key="globalPackagesFolder" value="/cache" />
+  </config>
+</configuration>
+`
+
+func TestMerge(t *testing.T) {
+	base := writeTestConfig(t, testConfig)
+	overlay := writeTestConfig(t, overlayConfig)
+
+	code, stdout, stderr := runCLI("merge", base, overlay)
+	if code != 0 {
+		t.Fatalf("merge exit code = %d, stderr %q", code, stderr)
+	}
+	if !strings.Contains(stdout, "<configuration>") || strings.Contains(stdout, "<!--") {
+		t.Errorf("merge output:\n%s", stdout)
+	}
+	for _, key := range []string{`key="nuget.org"`, `key="local"`, `key="company"`, `key="globalPackagesFolder"`} {
+		if !strings.Contains(stdout, key) {
+			t.Errorf("merge output missing %s:\n%s", key, stdout)
+		}
+	}
+
+	// 合并结果本身是合法的配置文件
+	merged := writeTestConfig(t, stdout)
+	if code, _, stderr := runCLI("source", "list", "--file", merged); code != 0 {
+		t.Errorf("merged config cannot be read back: %s", stderr)
+	}
+}
+
+func TestMergeClearAndOrigins(t *testing.T) {
+	base := writeTestConfig(t, testConfig)
+	cleared := writeTestConfig(t, strings.Replace(overlayConfig, "<packageSources>", "<packageSources>\n    <clear />", 1))
+
+	_, stdout, _ := runCLI("merge", "--origins", base, cleared)
+	if strings.Contains(stdout, `<add key="nuget.org"`) {
+		t.Errorf("merge kept sources cleared by a higher priority file:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "<clear />") {
+		t.Errorf("merge dropped <clear />:\n%s", stdout)
+	}
+	for _, want := range []string{
+		"<!-- Merged from (lowest to highest priority):",
+		"<!-- from " + cleared + " -->\n    <add key=\"company\"",
+		"<!-- from " + base + " -->\n    <add key=\"local\" value=\"true\" />",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("merge --origins output missing %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestMergeOriginsEncodedCredentials(t *testing.T) {
+	base := writeTestConfig(t, testConfig)
+	overlay := writeTestConfig(t, `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="My Feed" value="https://feed.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <My_x0020_Feed>
+      <add key="Username" value="user" />
+    </My_x0020_Feed>
+  </packageSourceCredentials>
+</configuration>
+`)
+
+	code, stdout, stderr := runCLI("merge", "--origins", base, overlay)
+	if code != 0 {
+		t.Fatalf("merge exit code = %d, stderr %q", code, stderr)
+	}
+	if want := "<!-- from " + overlay + " -->\n    <My_x0020_Feed>"; !strings.Contains(stdout, want) {
+		t.Errorf("merge --origins output missing %q:\n%s", want, stdout)
+	}
+}
+
+func TestMergeAuto(t *testing.T) {
+	isolateHome(t)
+	root := t.TempDir()
+	project := filepath.Join(root, "src", "app")
+	if err := os.MkdirAll(project, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "NuGet.Config"), []byte(testConfig), 0644)
+	os.WriteFile(filepath.Join(project, "NuGet.Config"), []byte(overlayConfig), 0644)
+
+	output := filepath.Join(t.TempDir(), "merged.config")
+	if code, _, stderr := runCLI("merge", "--auto", "--dir", project, "--output", output); code != 0 {
+		t.Fatalf("merge --auto exit code = %d, stderr %q", code, stderr)
+	}
+	content := readFile(t, output)
+	if !strings.Contains(content, `key="nuget.org"`) || !strings.Contains(content, `key="company"`) {
+		t.Errorf("merge --auto output:\n%s", content)
+	}
+
+	if code, _, _ := runCLI("merge"); code != 2 {
+		t.Errorf("merge without files = %d, want 2", code)
+	}
+	if code, _, _ := runCLI("merge", "--auto", output); code != 2 {
+		t.Errorf("merge with --auto and files = %d, want 2", code)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// changeResult 修改配置文件的命令在 --json 模式下的输出
+type changeResult struct {
+	// File 被修改的配置文件
+	File string `json:"file"`
+	// Action 执行的操作，例如 "add"、"remove"、"set"
+	Action string `json:"action"`
+	// Section 被修改的配置节
+	Section string `json:"section"`
+	// Key 被修改的包源名称或选项键名
+	Key string `json:"key"`
+	// Patterns map add 和 map remove 涉及的包ID模式
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// jsonFlag 为参数集添加 --json 选项
+func jsonFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("json", false, "print machine-readable JSON instead of text")
+}
+
+// printJSON 以两个空格缩进输出 JSON，nil 切片应由调用方替换为空切片以输出 []
+func (a *app) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// reportChange 输出修改结果，指定了 --json 时输出 changeResult，否则输出文本消息
+func (a *app) reportChange(asJSON bool, result changeResult, format string, args ...interface{}) error {
+	if asJSON {
+		return a.printJSON(result)
+	}
+	fmt.Fprintf(a.stdout, format+"\n", args...)
+	return nil
+}
+
+// formatWithJSON 处理 --json 与 --format 同时出现的情况，返回最终使用的输出格式
+//
+// --json 等价于 --format json，与其他显式指定的格式冲突。
+func formatWithJSON(asJSON bool, format, defaultFormat string) (string, error) {
+	if !asJSON {
+		return format, nil
+	}
+	if format != defaultFormat && format != "json" {
+		return "", usagef("--json and --format %s cannot be used together", format)
+	}
+	return "json", nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// decodeJSON 解析命令的 JSON 输出
+func decodeJSON(t *testing.T, stdout string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(stdout), v); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+}
+
+func TestJSONOutput(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	code, stdout, stderr := runCLI("source", "list", "--json", "--file", path)
+	if code != 0 {
+		t.Fatalf("source list --json exit code = %d, stderr %q", code, stderr)
+	}
+	var sources []sourceInfo
+	decodeJSON(t, stdout, &sources)
+	want := []sourceInfo{
+		{Name: "nuget.org", Source: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3", Enabled: true},
+		{Name: "local", Source: "/tmp/packages"},
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("source list --json = %+v, want %+v", sources, want)
+	}
+
+	changes := []struct {
+		args []string
+		want changeResult
+	}{
+		{[]string{"source", "add", "https://example.com/v3/index.json", "--name", "example"},
+			changeResult{File: path, Action: "add", Section: "packageSources", Key: "example"}},
+		{[]string{"source", "disable", "example"},
+			changeResult{File: path, Action: "disable", Section: "packageSources", Key: "example"}},
+		{[]string{"config", "set", "globalPackagesFolder", "/tmp/gpf"},
+			changeResult{File: path, Action: "set", Section: "config", Key: "globalPackagesFolder"}},
+		{[]string{"map", "add", "example", "Example.*"},
+			changeResult{File: path, Action: "add", Section: "packageSourceMapping", Key: "example", Patterns: []string{"Example.*"}}},
+	}
+	for _, c := range changes {
+		code, stdout, stderr := runCLI(append(c.args, "--json", "--file", path)...)
+		if code != 0 {
+			t.Fatalf("%v exit code = %d, stderr %q", c.args, code, stderr)
+		}
+		var got changeResult
+		decodeJSON(t, stdout, &got)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%v --json = %+v, want %+v", c.args, got, c.want)
+		}
+	}
+
+	_, stdout, _ = runCLI("config", "get", "globalPackagesFolder", "--json", "--file", path)
+	var value configValue
+	decodeJSON(t, stdout, &value)
+	if value != (configValue{Key: "globalPackagesFolder", Value: "/tmp/gpf", File: path}) {
+		t.Errorf("config get --json = %+v", value)
+	}
+
+	code, stdout, _ = runCLI("map", "check", "Example.Core", "--json", "--file", path)
+	var check mappingCheck
+	decodeJSON(t, stdout, &check)
+	wantCheck := mappingCheck{PackageID: "Example.Core", MappingEnabled: true, Pattern: "Example.*",
+		Sources: []mappedSource{{Name: "example", Status: "disabled"}}}
+	if code != 1 || !reflect.DeepEqual(check, wantCheck) {
+		t.Errorf("map check --json = %d, %+v, want %+v", code, check, wantCheck)
+	}
+
+	_, stdout, _ = runCLI("map", "list", "--json", "--file", writeTestConfig(t, testConfig))
+	if stdout != "[]\n" {
+		t.Errorf("map list --json without mapping = %q, want []", stdout)
+	}
+}
+
+func TestJSONConflicts(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	tests := [][]string{
+		{"source", "list", "--json", "--format", "short", "--file", path},
+		{"lint", "--json", "--format", "github", path},
+		{"convert", "--json", "--to", "yaml", path},
+		{"merge", "--json", "--origins", path},
+	}
+	for _, args := range tests {
+		if code, _, _ := runCLI(args...); code != 2 {
+			t.Errorf("%v exit code = %d, want 2", args, code)
+		}
+	}
+
+	// --json 与 --format json 等价
+	code, stdout, _ := runCLI("lint", "--json", "--format", "json", path)
+	if code != 0 || stdout != "[]\n" {
+		t.Errorf("lint --json --format json = %d, %q", code, stdout)
+	}
+}
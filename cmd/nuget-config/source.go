@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+)
+
+// runSource 执行 source 命令
+func (a *app) runSource(args []string) error {
+	name, args, err := subcommand(args, sourceSubcommands...)
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "list":
+		return a.sourceList(args)
+	case "add":
+		return a.sourceAdd(args)
+	case "update":
+		return a.sourceUpdate(args)
+	}
+	return a.sourceToggle(name, args)
+}
+
+// sourceInfo source list 在 --json 模式下输出的一个包源
+type sourceInfo struct {
+	Name            string `json:"name"`
+	Source          string `json:"source"`
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	Enabled         bool   `json:"enabled"`
+	HasCredentials  bool   `json:"hasCredentials"`
+	Active          bool   `json:"active"`
+}
+
+// sourceList 列出配置文件中的包源
+func (a *app) sourceList(args []string) error {
+	fs := a.newFlagSet("source list")
+	file := fileFlag(fs)
+	format := fs.String("format", "detailed", "output format: detailed or short")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return usagef("source list takes no arguments")
+	}
+	if *format != "detailed" && *format != "short" {
+		return usagef("unknown format %q, expected detailed or short", *format)
+	}
+
+	path, err := a.configFile(*file)
+	if err != nil {
+		return err
+	}
+	config, err := a.api.ParseFromFile(path)
+	if err != nil {
+		return err
+	}
+
+	statuses := a.api.GetPackageSourceStatuses(config)
+	if *asJSON {
+		if *format != "detailed" {
+			return usagef("--json and --format %s cannot be used together", *format)
+		}
+		infos := make([]sourceInfo, 0, len(statuses))
+		for _, status := range statuses {
+			infos = append(infos, sourceInfo{Name: status.Key, Source: status.Value, ProtocolVersion: status.ProtocolVersion,
+				Enabled: status.Enabled, HasCredentials: status.HasCredentials, Active: status.Active})
+		}
+		return a.printJSON(infos)
+	}
+	if *format == "short" {
+		for _, status := range statuses {
+			state := "E"
+			if !status.Enabled {
+				state = "D"
+			}
+			fmt.Fprintf(a.stdout, "%s %s\n", state, status.Value)
+		}
+		return nil
+	}
+
+	if len(statuses) == 0 {
+		fmt.Fprintln(a.stdout, "No sources found.")
+		return nil
+	}
+	fmt.Fprintln(a.stdout, "Registered Sources:")
+	for i, status := range statuses {
+		state := "Enabled"
+		if !status.Enabled {
+			state = "Disabled"
+		}
+		fmt.Fprintf(a.stdout, "  %d.  %s [%s]\n", i+1, status.Key, state)
+		fmt.Fprintf(a.stdout, "      %s\n", status.Value)
+	}
+	return nil
+}
+
+// sourceAdd 添加包源
+func (a *app) sourceAdd(args []string) error {
+	fs := a.newFlagSet("source add")
+	file := fileFlag(fs)
+	name := fs.String("name", "", "name of the package source")
+	protocolVersion := fs.String("protocol-version", "", "protocol version of the package source: 2 or 3")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: source add <url> --name <name>")
+	}
+	if *name == "" {
+		return usagef("--name is required")
+	}
+	value := positional[0]
+	if err := validateProtocolVersion(*protocolVersion); err != nil {
+		return err
+	}
+	if err := manager.ValidateSourceURL(value); err != nil {
+		return err
+	}
+
+	path, err := a.configFile(*file)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		if a.api.GetPackageSource(ed.GetConfig(), *name) != nil {
+			return fmt.Errorf("package source with name '%s' already exists", *name)
+		}
+		return ed.AddPackageSource(*name, value, *protocolVersion)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "add", Section: "packageSources", Key: *name},
+		"Package source with name '%s' added successfully.", *name)
+}
+
+// sourceUpdate 更新包源的地址或协议版本
+func (a *app) sourceUpdate(args []string) error {
+	fs := a.newFlagSet("source update")
+	file := fileFlag(fs)
+	value := fs.String("source", "", "new URL or path of the package source")
+	protocolVersion := fs.String("protocol-version", "", "new protocol version of the package source: 2 or 3")
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: source update <name> [--source <url>] [--protocol-version <version>]")
+	}
+	if *value == "" && *protocolVersion == "" {
+		return usagef("nothing to update, specify --source or --protocol-version")
+	}
+	name := positional[0]
+	if err := validateProtocolVersion(*protocolVersion); err != nil {
+		return err
+	}
+	if *value != "" {
+		if err := manager.ValidateSourceURL(*value); err != nil {
+			return err
+		}
+	}
+
+	path, err := a.configFile(*file)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		if a.api.GetPackageSource(ed.GetConfig(), name) == nil {
+			return fmt.Errorf("package source with name '%s' not found", name)
+		}
+		if *value != "" {
+			if err := ed.UpdatePackageSourceURL(name, *value); err != nil {
+				return err
+			}
+		}
+		if *protocolVersion != "" {
+			return ed.UpdatePackageSourceVersion(name, *protocolVersion)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.reportChange(*asJSON, changeResult{File: path, Action: "update", Section: "packageSources", Key: name},
+		"Package source with name '%s' updated successfully.", name)
+}
+
+// sourceToggle 执行 remove、enable 和 disable 子命令
+func (a *app) sourceToggle(action string, args []string) error {
+	fs := a.newFlagSet("source " + action)
+	file := fileFlag(fs)
+	asJSON := jsonFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usagef("usage: source %s <name>", action)
+	}
+	name := positional[0]
+
+	path, err := a.configFile(*file)
+	if err != nil {
+		return err
+	}
+	err = a.api.UpdateConfigFile(path, func(ed *editor.ConfigEditor) error {
+		config := ed.GetConfig()
+		if a.api.GetPackageSource(config, name) == nil {
+			return fmt.Errorf("package source with name '%s' not found", name)
+		}
+
+		switch action {
+		case "remove":
+			return ed.RemovePackageSource(name)
+		case "enable":
+			if !a.api.IsPackageSourceDisabled(config, name) {
+				return nil
+			}
+			return ed.EnablePackageSource(name)
+		default:
+			return ed.DisablePackageSource(name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	past := map[string]string{"remove": "removed", "enable": "enabled", "disable": "disabled"}[action]
+	return a.reportChange(*asJSON, changeResult{File: path, Action: action, Section: "packageSources", Key: name},
+		"Package source with name '%s' %s successfully.", name, past)
+}
+
+// validateProtocolVersion 检查协议版本是否为空、2 或 3
+func validateProtocolVersion(version string) error {
+	switch version {
+	case "", constants.NuGetV2APIProtocolVersion, constants.NuGetV3APIProtocolVersion:
+		return nil
+	}
+	return usagef("unsupported protocol version %q, expected 2 or 3", version)
+}
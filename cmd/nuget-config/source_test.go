@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSourceList(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	code, stdout, stderr := runCLI("source", "list", "--file", path)
+	if code != 0 {
+		t.Fatalf("source list exit code = %d, stderr %q", code, stderr)
+	}
+	for _, want := range []string{"1.  nuget.org [Enabled]", "2.  local [Disabled]", "/tmp/packages"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("source list output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	_, stdout, _ = runCLI("source", "list", "--format", "short", "--configfile", path)
+	if stdout != "E https://api.nuget.org/v3/index.json\nD /tmp/packages\n" {
+		t.Errorf("source list --format short = %q", stdout)
+	}
+}
+
+func TestSourceEditing(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	steps := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"source", "add", "https://nuget.company.com/v3/index.json", "--name", "company", "--protocol-version", "3"},
+			`<add key="company" value="https://nuget.company.com/v3/index.json" protocolVersion="3" />`},
+		{[]string{"source", "update", "company", "--source", "https://feed.company.com/v3/index.json"},
+			`<add key="company" value="https://feed.company.com/v3/index.json" protocolVersion="3" />`},
+		{[]string{"source", "disable", "nuget.org"}, `<add key="nuget.org" value="true" />`},
+		{[]string{"source", "enable", "local"}, ``},
+		{[]string{"source", "remove", "local"}, ``},
+	}
+	for _, step := range steps {
+		args := append(step.args, "--file", path)
+		if code, _, stderr := runCLI(args...); code != 0 {
+			t.Fatalf("%v exit code = %d, stderr %q", step.args, code, stderr)
+		}
+		if content := readFile(t, path); !strings.Contains(content, step.want) {
+			t.Errorf("%v result missing %q:\n%s", step.args, step.want, content)
+		}
+	}
+
+	content := readFile(t, path)
+	if !strings.Contains(content, "<!-- 公司包源 -->") {
+		t.Errorf("editing lost comment:\n%s", content)
+	}
+	if strings.Contains(content, `key="local"`) {
+		t.Errorf("source remove left local source:\n%s", content)
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	path := writeTestConfig(t, testConfig)
+
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"source", "add", "https://example.com/v3/index.json"}, 2},
+		{[]string{"source", "add", "https://example.com/v3/index.json", "--name", "x", "--protocol-version", "4"}, 2},
+		{[]string{"source", "add", "https:///index.json", "--name", "x"}, 1},
+		{[]string{"source", "add", "https://example.com/v3/index.json", "--name", "nuget.org"}, 1},
+		{[]string{"source", "remove", "missing"}, 1},
+		{[]string{"source", "update", "nuget.org"}, 2},
+		{[]string{"source", "disable"}, 2},
+	}
+	for _, tt := range tests {
+		args := append(tt.args, "--file", path)
+		if code, _, _ := runCLI(args...); code != tt.code {
+			t.Errorf("%v exit code = %d, want %d", tt.args, code, tt.code)
+		}
+	}
+
+	if content := readFile(t, path); content != testConfig {
+		t.Errorf("failed commands modified the file:\n%s", content)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// readNoEcho 关闭终端回显后读取一行，读取完成后恢复回显
+func readNoEcho(terminal *os.File) (string, error) {
+	if err := stty(terminal, "-echo"); err != nil {
+		return "", err
+	}
+	defer stty(terminal, "echo")
+	return readLine(terminal)
+}
+
+// stty 使用 stty 修改终端设置
+func stty(terminal *os.File, arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = terminal
+	return cmd.Run()
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// enableEchoInput 控制台输入模式中的回显标志
+const enableEchoInput = 0x0004
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// readNoEcho 关闭控制台回显后读取一行，读取完成后恢复原来的输入模式
+func readNoEcho(terminal *os.File) (string, error) {
+	handle := syscall.Handle(terminal.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return "", err
+	}
+	if r, _, err := setConsoleMode.Call(uintptr(handle), uintptr(mode&^enableEchoInput)); r == 0 {
+		return "", err
+	}
+	defer setConsoleMode.Call(uintptr(handle), uintptr(mode))
+	return readLine(terminal)
+}
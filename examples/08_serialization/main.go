@@ -27,72 +27,23 @@ func main() {
 	// -----------------------------------------------
 	fmt.Println("创建示例配置:")
 
-	// 创建完整的配置对象，包含各种元素
-	config := &types.NuGetConfig{
-		// 包源设置
-		PackageSources: types.PackageSources{
-			Add: []types.PackageSource{
-				{
-					Key:             "nuget.org",
-					Value:           "https://api.nuget.org/v3/index.json",
-					ProtocolVersion: "3",
-				},
-				{
-					Key:   "local",
-					Value: "C:\\LocalPackages",
-				},
-			},
-		},
-
+	// 使用构建器创建完整的配置对象，包含各种元素，每一步都会检查参数
+	config, err := nuget.NewConfigBuilder().
+		// 包源设置，协议版本根据地址推断
+		AddSource("nuget.org", "https://api.nuget.org/v3/index.json").
+		AddSource("local", "C:\\LocalPackages").
 		// 活跃包源
-		ActivePackageSource: &types.ActivePackageSource{
-			Add: types.PackageSource{
-				Key:   "nuget.org",
-				Value: "https://api.nuget.org/v3/index.json",
-			},
-		},
-
+		SetActiveSource("nuget.org").
 		// 禁用的包源
-		DisabledPackageSources: &types.DisabledPackageSources{
-			Add: []types.DisabledSource{
-				{
-					Key:   "local",
-					Value: "true",
-				},
-			},
-		},
-
+		DisableSource("local").
 		// 凭证
-		PackageSourceCredentials: &types.PackageSourceCredentials{
-			Sources: map[string]types.SourceCredential{
-				"nuget.org": {
-					Add: []types.Credential{
-						{
-							Key:   "Username",
-							Value: "user@example.com",
-						},
-						{
-							Key:   "ClearTextPassword",
-							Value: "P@ssw0rd",
-						},
-					},
-				},
-			},
-		},
-
+		WithCredential("nuget.org", "user@example.com", "P@ssw0rd").
 		// 配置选项
-		Config: &types.Config{
-			Add: []types.ConfigOption{
-				{
-					Key:   "globalPackagesFolder",
-					Value: "%USERPROFILE%\\.nuget\\packages",
-				},
-				{
-					Key:   "dependencyVersion",
-					Value: "Highest",
-				},
-			},
-		},
+		SetOption("globalPackagesFolder", "%USERPROFILE%\\.nuget\\packages").
+		SetOption("dependencyVersion", "Highest").
+		Build()
+	if err != nil {
+		log.Fatalf("创建配置失败: %v", err)
 	}
 
 	// 3. 将配置序列化为XML字符串
@@ -124,7 +75,7 @@ func main() {
 	//     <add key="local" value="true" />
 	//   </disabledPackageSources>
 	//   <activePackageSource>
-	//     <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+	//     <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
 	//   </activePackageSource>
 	//   <config>
 	//     <add key="globalPackagesFolder" value="%USERPROFILE%\.nuget\packages" />
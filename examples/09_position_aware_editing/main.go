@@ -7,11 +7,13 @@ import (
 	"path/filepath"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+	"github.com/scagogogo/nuget-config-parser/pkg/nuget"
 	"github.com/scagogogo/nuget-config-parser/pkg/parser"
 )
 
 func main() {
-	fmt.Println("=== NuGet配置位置感知编辑示例 ===\n")
+	fmt.Println("=== NuGet配置位置感知编辑示例 ===")
+	fmt.Println()
 
 	// 创建临时目录
 	tempDir, err := os.MkdirTemp("", "nuget-position-aware-example-")
@@ -138,6 +140,25 @@ func main() {
 		fmt.Println()
 	}
 
+	// 使用 UpdateConfigFile 一次完成解析、编辑、校验和原子写回
+	fmt.Println("\n使用 UpdateConfigFile 直接修改原文件:")
+	api := nuget.NewAPI()
+	err = api.UpdateConfigFile(configPath, func(ed *editor.ConfigEditor) error {
+		if err := ed.AddPackageSource("company-internal", "https://nuget.company.com/v3/index.json", "3"); err != nil {
+			return err
+		}
+		return ed.UpdatePackageSourceURL("localSource", "/new/path/to/packages")
+	})
+	if err != nil {
+		log.Fatalf("修改配置文件失败: %v", err)
+	}
+
+	updatedContent, err := os.ReadFile(configPath)
+	if err != nil {
+		log.Fatalf("读取配置文件失败: %v", err)
+	}
+	fmt.Println(string(updatedContent))
+
 	fmt.Println("\n=== 位置感知编辑示例完成 ===")
 	fmt.Println("\n优势:")
 	fmt.Println("- 保持原始文件格式和缩进")
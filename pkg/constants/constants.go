@@ -27,6 +27,48 @@ const (
 	NuGetV2APIProtocolVersion = "2"
 )
 
+const (
+	// EnvNuGetPackages 覆盖全局包文件夹位置的环境变量
+	EnvNuGetPackages = "NUGET_PACKAGES"
+
+	// EnvNuGetHTTPCachePath 覆盖HTTP缓存位置的环境变量
+	EnvNuGetHTTPCachePath = "NUGET_HTTP_CACHE_PATH"
+
+	// EnvNuGetPluginPaths 指定凭证提供程序插件路径的环境变量，多个路径以路径列表分隔符分隔
+	EnvNuGetPluginPaths = "NUGET_PLUGIN_PATHS"
+)
+
+// ConfigFileNames 同一目录中存在多个大小写不同的配置文件时的优先顺序，与 NuGet 客户端一致
+var ConfigFileNames = []string{"nuget.config", "NuGet.config", DefaultNuGetConfigFilename}
+
+// GetDefaultGlobalPackagesFolder 返回平台默认的全局包文件夹路径（~/.nuget/packages）
+func GetDefaultGlobalPackagesFolder() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".nuget", "packages")
+}
+
+// GetDefaultHTTPCachePath 返回平台默认的HTTP缓存路径
+//
+// Windows 上为 %LocalAppData%\NuGet\v3-cache，其他平台为 ~/.local/share/NuGet/http-cache。
+func GetDefaultHTTPCachePath() string {
+	if runtime.GOOS == "windows" {
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			return ""
+		}
+		return filepath.Join(localAppData, GlobalFolderName, "v3-cache")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".local", "share", GlobalFolderName, "http-cache")
+}
+
 // GetDefaultConfigLocations 返回默认的NuGet配置文件可能的位置列表
 //
 // GetDefaultConfigLocations 按照 NuGet 的配置文件查找规则，返回一个包含所有可能的配置文件
@@ -0,0 +1,82 @@
+// Package credentials 处理 <packageSourceCredentials> 中的密码
+//
+// NuGet 在 Windows 上使用 DPAPI 加密 Password 项，加密结果只能由同一台机器上的同一用户解密；
+// 其他平台不支持加密，只能使用 ClearTextPassword。Cipher 抽象了加密方式，DefaultCipher
+// 返回当前平台上与 nuget.exe 和 Visual Studio 兼容的实现。
+//
+// Store 是另一种选择：密码保存在 macOS 钥匙串、Windows 凭据管理器或 Secret Service 中，
+// 配置文件中只保留 Username。
+package credentials
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// 凭证项的键名
+const (
+	// KeyUsername 用户名
+	KeyUsername = "Username"
+	// KeyPassword 加密后的密码
+	KeyPassword = "Password"
+	// KeyClearTextPassword 明文密码
+	KeyClearTextPassword = "ClearTextPassword"
+)
+
+// ErrEncryptionUnsupported 表示当前平台不支持加密存储密码
+var ErrEncryptionUnsupported = errors.New("password encryption is not supported on this platform")
+
+// Cipher 加密和解密凭证中 Password 项的值
+type Cipher interface {
+	// Encrypt 加密密码，返回写入 Password 项的字符串
+	Encrypt(password string) (string, error)
+	// Decrypt 解密 Password 项的值
+	Decrypt(encrypted string) (string, error)
+}
+
+// DefaultCipher 返回当前平台上与 NuGet 兼容的加密方式
+//
+// Windows 上返回基于 DPAPI（CryptProtectData）的实现，与 nuget.exe 和 Visual Studio
+// 写入的加密密码互通；其他平台上 NuGet 本身也不支持加密密码，返回 ErrEncryptionUnsupported。
+func DefaultCipher() (Cipher, error) {
+	return defaultCipher()
+}
+
+// Password 返回凭证中的密码
+//
+// 有 ClearTextPassword 项时直接返回其值，否则用 cipher 解密 Password 项的值。
+// 凭证中没有密码时返回 false。需要解密而 cipher 为 nil 时返回 ErrEncryptionUnsupported。
+func Password(cred types.SourceCredential, cipher Cipher) (string, bool, error) {
+	if value, ok := lookup(cred, KeyClearTextPassword); ok {
+		return value, true, nil
+	}
+	encrypted, ok := lookup(cred, KeyPassword)
+	if !ok {
+		return "", false, nil
+	}
+	if cipher == nil {
+		return "", true, ErrEncryptionUnsupported
+	}
+	password, err := cipher.Decrypt(encrypted)
+	if err != nil {
+		return "", true, err
+	}
+	return password, true, nil
+}
+
+// Username 返回凭证中的用户名，没有 Username 项时返回 false
+func Username(cred types.SourceCredential) (string, bool) {
+	return lookup(cred, KeyUsername)
+}
+
+// lookup 按键名查找凭证项，键名不区分大小写
+func lookup(cred types.SourceCredential, key string) (string, bool) {
+	for _, item := range cred.Add {
+		if strings.EqualFold(item.Key, key) {
+			return item.Value, true
+		}
+	}
+	return "", false
+}
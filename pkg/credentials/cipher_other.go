@@ -0,0 +1,8 @@
+//go:build !windows
+
+package credentials
+
+// defaultCipher 非 Windows 平台不支持加密密码
+func defaultCipher() (Cipher, error) {
+	return nil, ErrEncryptionUnsupported
+}
@@ -0,0 +1,64 @@
+package credentials
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// upperCipher 测试用的加密方式，加密结果为大写的密码
+type upperCipher struct{}
+
+func (upperCipher) Encrypt(password string) (string, error) { return strings.ToUpper(password), nil }
+
+func (upperCipher) Decrypt(encrypted string) (string, error) {
+	if encrypted == "" {
+		return "", errors.New("empty")
+	}
+	return strings.ToLower(encrypted), nil
+}
+
+func TestPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []types.Credential
+		cipher  Cipher
+		want    string
+		found   bool
+		wantErr error
+	}{
+		{"clear text", []types.Credential{{Key: "Username", Value: "u"}, {Key: "ClearTextPassword", Value: "secret"}}, nil, "secret", true, nil},
+		{"clear text wins", []types.Credential{{Key: "Password", Value: "OTHER"}, {Key: "cleartextpassword", Value: "secret"}}, upperCipher{}, "secret", true, nil},
+		{"encrypted", []types.Credential{{Key: "Password", Value: "SECRET"}}, upperCipher{}, "secret", true, nil},
+		{"no cipher", []types.Credential{{Key: "Password", Value: "SECRET"}}, nil, "", true, ErrEncryptionUnsupported},
+		{"no password", []types.Credential{{Key: "Username", Value: "u"}}, upperCipher{}, "", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := Password(types.SourceCredential{Add: tt.items}, tt.cipher)
+			if got != tt.want || found != tt.found || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Password() = %q, %v, %v, want %q, %v, %v", got, found, err, tt.want, tt.found, tt.wantErr)
+			}
+		})
+	}
+
+	if _, _, err := Password(types.SourceCredential{Add: []types.Credential{{Key: "Password"}}}, upperCipher{}); err == nil {
+		t.Error("Password() should return the decryption error")
+	}
+}
+
+func TestDefaultCipher(t *testing.T) {
+	cipher, err := DefaultCipher()
+	if runtime.GOOS != "windows" {
+		if cipher != nil || !errors.Is(err, ErrEncryptionUnsupported) {
+			t.Errorf("DefaultCipher() = %v, %v, want ErrEncryptionUnsupported", cipher, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("DefaultCipher() error = %v", err)
+	}
+}
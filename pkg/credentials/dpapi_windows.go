@@ -0,0 +1,90 @@
+//go:build windows
+
+package credentials
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// cryptProtectUIForbidden 禁止 DPAPI 显示任何界面
+const cryptProtectUIForbidden = 0x1
+
+var (
+	crypt32                = syscall.NewLazyDLL("crypt32.dll")
+	procCryptProtectData   = crypt32.NewProc("CryptProtectData")
+	procCryptUnprotectData = crypt32.NewProc("CryptUnprotectData")
+)
+
+// nugetEntropy NuGet 加密密码时使用的附加熵，与 NuGet.Configuration.EncryptionUtility 相同
+var nugetEntropy = []byte("NuGet")
+
+// dataBlob 对应 Win32 的 DATA_BLOB 结构
+type dataBlob struct {
+	size uint32
+	data *byte
+}
+
+// newDataBlob 创建指向 b 的 DATA_BLOB
+func newDataBlob(b []byte) *dataBlob {
+	if len(b) == 0 {
+		return &dataBlob{}
+	}
+	return &dataBlob{size: uint32(len(b)), data: &b[0]}
+}
+
+// dpapiCipher 使用当前用户的 DPAPI 密钥加密密码
+//
+// 与 NuGet 相同，密码按 UTF-8 编码后以 "NuGet" 为附加熵调用 CryptProtectData，
+// 结果以标准 Base64 编码写入配置文件。
+type dpapiCipher struct{}
+
+// defaultCipher Windows 上使用 DPAPI
+func defaultCipher() (Cipher, error) {
+	return dpapiCipher{}, nil
+}
+
+// Encrypt 加密密码
+func (dpapiCipher) Encrypt(password string) (string, error) {
+	data, err := cryptData(procCryptProtectData, []byte(password))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt password: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt 解密 Password 项的值，只能解密同一用户在同一台机器上加密的密码
+func (dpapiCipher) Decrypt(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted password: %w", err)
+	}
+	data, err = cryptData(procCryptUnprotectData, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password: %w", err)
+	}
+	return string(data), nil
+}
+
+// cryptData 调用 CryptProtectData 或 CryptUnprotectData，两者的参数布局相同
+func cryptData(proc *syscall.LazyProc, input []byte) ([]byte, error) {
+	var out dataBlob
+	r, _, err := proc.Call(
+		uintptr(unsafe.Pointer(newDataBlob(input))),
+		0,
+		uintptr(unsafe.Pointer(newDataBlob(nugetEntropy))),
+		0,
+		0,
+		cryptProtectUIForbidden,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if r == 0 {
+		return nil, err
+	}
+	defer syscall.LocalFree(syscall.Handle(unsafe.Pointer(out.data)))
+
+	return append([]byte(nil), unsafe.Slice(out.data, out.size)...), nil
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package credentials
+
+import "testing"
+
+func TestDPAPIRoundTrip(t *testing.T) {
+	cipher := dpapiCipher{}
+	for _, password := range []string{"p@ssw0rd", "密码", ""} {
+		encrypted, err := cipher.Encrypt(password)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) error = %v", password, err)
+		}
+		if password != "" && encrypted == password {
+			t.Errorf("Encrypt(%q) returned the password unchanged", password)
+		}
+		decrypted, err := cipher.Decrypt(encrypted)
+		if err != nil || decrypted != password {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q, %v", password, decrypted, err)
+		}
+	}
+	if _, err := cipher.Decrypt("not base64!"); err == nil {
+		t.Error("Decrypt() of invalid Base64 should fail")
+	}
+}
@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// keychainItemNotFound security 命令在钥匙串中找不到项目时的退出状态（errSecItemNotFound）
+const keychainItemNotFound = 44
+
+// keychainStore 通过 security 命令使用 macOS 登录钥匙串中的通用密码项目
+type keychainStore struct {
+	run commandRunner
+}
+
+// Get 读取密码
+func (s keychainStore) Get(service, account string) (string, error) {
+	result, err := s.run("", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	if err != nil {
+		return "", fmt.Errorf("failed to read from keychain: %w", err)
+	}
+	switch result.exitCode {
+	case 0:
+		return strings.TrimSuffix(result.stdout, "\n"), nil
+	case keychainItemNotFound:
+		return "", ErrSecretNotFound
+	}
+	return "", commandError("failed to read from keychain", result)
+}
+
+// Set 保存密码
+//
+// 密码以十六进制（-X）通过 security -i 的标准输入传入，不会出现在进程的命令行参数中。
+func (s keychainStore) Set(service, account, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		shellQuote(service), shellQuote(account), hex.EncodeToString([]byte(secret)))
+	result, err := s.run(command, "security", "-i")
+	if err != nil {
+		return fmt.Errorf("failed to write to keychain: %w", err)
+	}
+	// security -i 中命令失败时退出状态仍可能为 0，错误信息输出到标准错误
+	if result.exitCode != 0 || strings.TrimSpace(result.stderr) != "" {
+		return commandError("failed to write to keychain", result)
+	}
+	return nil
+}
+
+// Delete 删除密码
+func (s keychainStore) Delete(service, account string) error {
+	result, err := s.run("", "security", "delete-generic-password", "-s", service, "-a", account)
+	if err != nil {
+		return fmt.Errorf("failed to delete from keychain: %w", err)
+	}
+	if result.exitCode != 0 && result.exitCode != keychainItemNotFound {
+		return commandError("failed to delete from keychain", result)
+	}
+	return nil
+}
+
+// shellQuote 用单引号引用参数，供 security -i 解析
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// commandError 根据外部命令的退出状态和标准错误生成错误
+func commandError(message string, result commandResult) error {
+	if stderr := strings.TrimSpace(result.stderr); stderr != "" {
+		return fmt.Errorf("%s: %s", message, stderr)
+	}
+	return fmt.Errorf("%s: exit status %d", message, result.exitCode)
+}
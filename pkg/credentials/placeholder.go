@@ -0,0 +1,96 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	nugeterrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// ErrUnresolvedPlaceholder 表示凭证引用的环境变量没有定义
+var ErrUnresolvedPlaceholder = errors.New("environment variable referenced by credential is not set")
+
+// Placeholder 判断凭证值是否为环境变量占位符，返回变量名
+//
+// 只有整个值为 %NAME%、$NAME 或 ${NAME} 时才是占位符，NAME 由字母、数字和下划线组成且不以数字开头。
+// 密码中经常出现 $ 和 %，因此值中间的 $ 和 % 不会被当作变量引用。
+func Placeholder(value string) (string, bool) {
+	var name string
+	switch {
+	case len(value) > 2 && value[0] == '%' && value[len(value)-1] == '%':
+		name = value[1 : len(value)-1]
+	case len(value) > 3 && value[0] == '$' && value[1] == '{' && value[len(value)-1] == '}':
+		name = value[2 : len(value)-1]
+	case len(value) > 1 && value[0] == '$':
+		name = value[1:]
+	default:
+		return "", false
+	}
+	if !isEnvName(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// isEnvName 判断是否为合法的环境变量名
+func isEnvName(name string) bool {
+	for i, c := range name {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// Resolve 返回替换了环境变量占位符的凭证副本，cred 本身不会被修改
+//
+// lookup 通常为 os.LookupEnv。引用了未定义变量的项保持原样，并返回包装
+// ErrUnresolvedPlaceholder 的错误，其中列出所有未定义的变量。
+func Resolve(cred types.SourceCredential, lookup func(string) (string, bool)) (types.SourceCredential, error) {
+	resolved := types.SourceCredential{Add: make([]types.Credential, len(cred.Add))}
+	var errs nugeterrors.ErrorList
+	for i, item := range cred.Add {
+		resolved.Add[i] = item
+		name, ok := Placeholder(item.Value)
+		if !ok {
+			continue
+		}
+		if value, defined := lookup(name); defined {
+			resolved.Add[i].Value = value
+		} else {
+			errs.Add(fmt.Errorf("%w: %s (%s)", ErrUnresolvedPlaceholder, name, item.Key))
+		}
+	}
+	return resolved, errs.Err()
+}
+
+// ResolveAll 对所有包源的凭证调用 Resolve，返回新的 PackageSourceCredentials
+//
+// creds 为 nil 时返回 nil。出错时仍返回完整的结果，错误按包源名称排序并注明包源。
+func ResolveAll(creds *types.PackageSourceCredentials, lookup func(string) (string, bool)) (*types.PackageSourceCredentials, error) {
+	if creds == nil {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(creds.Sources))
+	for key := range creds.Sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resolved := &types.PackageSourceCredentials{Sources: make(map[string]types.SourceCredential, len(keys))}
+	var errs nugeterrors.ErrorList
+	for _, key := range keys {
+		cred, err := Resolve(creds.Sources[key], lookup)
+		resolved.Sources[key] = cred
+		if err != nil {
+			errs.Add(fmt.Errorf("package source '%s': %w", key, err))
+		}
+	}
+	return resolved, errs.Err()
+}
@@ -0,0 +1,77 @@
+package credentials
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestPlaceholder(t *testing.T) {
+	tests := []struct {
+		value string
+		name  string
+		ok    bool
+	}{
+		{"%NUGET_FEED_PAT%", "NUGET_FEED_PAT", true},
+		{"$NUGET_FEED_PAT", "NUGET_FEED_PAT", true},
+		{"${NUGET_FEED_PAT}", "NUGET_FEED_PAT", true},
+		{"$_token1", "_token1", true},
+		{"p@$$w0rd", "", false},
+		{"100%", "", false},
+		{"%%", "", false},
+		{"$", "", false},
+		{"${}", "", false},
+		{"%1TOKEN%", "", false},
+		{"%NUGET FEED%", "", false},
+		{"prefix-%TOKEN%", "", false},
+		{"$TOKEN-suffix", "", false},
+	}
+	for _, tt := range tests {
+		name, ok := Placeholder(tt.value)
+		if name != tt.name || ok != tt.ok {
+			t.Errorf("Placeholder(%q) = %q, %v, want %q, %v", tt.value, name, ok, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	env := map[string]string{"FEED_USER": "ci", "FEED_PAT": "s3cret"}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cred := types.SourceCredential{Add: []types.Credential{
+		{Key: "Username", Value: "$FEED_USER"},
+		{Key: "ClearTextPassword", Value: "%FEED_PAT%"},
+	}}
+	resolved, err := Resolve(cred, lookup)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []types.Credential{{Key: "Username", Value: "ci"}, {Key: "ClearTextPassword", Value: "s3cret"}}
+	if !reflect.DeepEqual(resolved.Add, want) {
+		t.Errorf("Resolve() = %+v, want %+v", resolved.Add, want)
+	}
+	if cred.Add[1].Value != "%FEED_PAT%" {
+		t.Error("Resolve() modified the original credential")
+	}
+
+	creds := &types.PackageSourceCredentials{Sources: map[string]types.SourceCredential{
+		"a": {Add: []types.Credential{{Key: "ClearTextPassword", Value: "${MISSING_A}"}}},
+		"b": cred,
+	}}
+	all, err := ResolveAll(creds, lookup)
+	if !errors.Is(err, ErrUnresolvedPlaceholder) || !strings.Contains(err.Error(), "package source 'a'") || !strings.Contains(err.Error(), "MISSING_A") {
+		t.Errorf("ResolveAll() error = %v, want ErrUnresolvedPlaceholder for MISSING_A", err)
+	}
+	if all.Sources["a"].Add[0].Value != "${MISSING_A}" || all.Sources["b"].Add[1].Value != "s3cret" {
+		t.Errorf("ResolveAll() = %+v", all.Sources)
+	}
+	if all, err := ResolveAll(nil, lookup); all != nil || err != nil {
+		t.Errorf("ResolveAll(nil) = %v, %v", all, err)
+	}
+}
@@ -0,0 +1,54 @@
+package credentials
+
+import (
+	"fmt"
+	"strings"
+)
+
+// secretServiceStore 通过 libsecret 的 secret-tool 命令使用 Secret Service（GNOME 密钥环、KWallet 等）
+//
+// 密码以 service 和 account 两个属性标识，写入时从标准输入传入。
+type secretServiceStore struct {
+	run commandRunner
+}
+
+// Get 读取密码
+func (s secretServiceStore) Get(service, account string) (string, error) {
+	result, err := s.run("", "secret-tool", "lookup", "service", service, "account", account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from secret service: %w", err)
+	}
+	if result.exitCode == 0 {
+		return result.stdout, nil
+	}
+	// secret-tool 找不到密码时以状态 1 退出且没有错误信息
+	if result.exitCode == 1 && strings.TrimSpace(result.stderr) == "" {
+		return "", ErrSecretNotFound
+	}
+	return "", commandError("failed to read from secret service", result)
+}
+
+// Set 保存密码
+func (s secretServiceStore) Set(service, account, secret string) error {
+	result, err := s.run(secret, "secret-tool", "store", "--label="+service+" ("+account+")", "service", service, "account", account)
+	if err != nil {
+		return fmt.Errorf("failed to write to secret service: %w", err)
+	}
+	if result.exitCode != 0 {
+		return commandError("failed to write to secret service", result)
+	}
+	return nil
+}
+
+// Delete 删除密码
+func (s secretServiceStore) Delete(service, account string) error {
+	result, err := s.run("", "secret-tool", "clear", "service", service, "account", account)
+	if err != nil {
+		return fmt.Errorf("failed to delete from secret service: %w", err)
+	}
+	// 与 Get 相同，状态 1 且没有错误信息表示密码不存在
+	if result.exitCode != 0 && (result.exitCode != 1 || strings.TrimSpace(result.stderr) != "") {
+		return commandError("failed to delete from secret service", result)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// 系统凭证存储相关的错误
+var (
+	// ErrStoreUnavailable 表示当前平台没有可用的系统凭证存储
+	ErrStoreUnavailable = errors.New("no system credential store is available on this platform")
+	// ErrSecretNotFound 表示凭证存储中没有对应的密码
+	ErrSecretNotFound = errors.New("secret not found in the credential store")
+)
+
+// Store 将密码保存在配置文件之外的系统凭证存储中
+//
+// 密码由 service 和 account 唯一确定。配置文件中只保留 Username，读取时用 ServiceName
+// 和用户名到凭证存储中查找密码。
+type Store interface {
+	// Get 读取密码，不存在时返回 ErrSecretNotFound
+	Get(service, account string) (string, error)
+	// Set 保存密码，已存在时覆盖
+	Set(service, account, secret string) error
+	// Delete 删除密码，密码不存在时不返回错误
+	Delete(service, account string) error
+}
+
+// DefaultStore 返回当前平台的系统凭证存储
+//
+// macOS 上使用钥匙串（通过 security 命令），Windows 上使用凭据管理器，其他平台上使用
+// Secret Service（通过 libsecret 的 secret-tool 命令）。没有可用的实现时返回 ErrStoreUnavailable。
+func DefaultStore() (Store, error) {
+	return defaultStore()
+}
+
+// ServiceName 返回包源密码在凭证存储中使用的服务名，如 "nuget:private-feed"
+func ServiceName(sourceKey string) string {
+	return "nuget:" + sourceKey
+}
+
+// commandResult 外部命令的执行结果
+type commandResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// commandRunner 以 stdin 为标准输入执行外部命令，只有命令无法启动时才返回错误
+type commandRunner func(stdin string, name string, args ...string) (commandResult, error)
+
+// runCommand 执行外部命令
+func runCommand(stdin string, name string, args ...string) (commandResult, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return commandResult{}, err
+	}
+	return commandResult{stdout: stdout.String(), stderr: stderr.String(), exitCode: cmd.ProcessState.ExitCode()}, nil
+}
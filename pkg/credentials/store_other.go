@@ -0,0 +1,22 @@
+//go:build !windows
+
+package credentials
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// defaultStore macOS 上使用钥匙串，其他平台上使用 Secret Service
+func defaultStore() (Store, error) {
+	if runtime.GOOS == "darwin" {
+		if _, err := exec.LookPath("security"); err == nil {
+			return keychainStore{run: runCommand}, nil
+		}
+		return nil, ErrStoreUnavailable
+	}
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		return secretServiceStore{run: runCommand}, nil
+	}
+	return nil, ErrStoreUnavailable
+}
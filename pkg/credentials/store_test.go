@@ -0,0 +1,102 @@
+package credentials
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner 记录执行的命令并返回预设的结果
+type fakeRunner struct {
+	result commandResult
+	stdin  string
+	args   []string
+}
+
+func (f *fakeRunner) run(stdin string, name string, args ...string) (commandResult, error) {
+	f.stdin = stdin
+	f.args = append([]string{name}, args...)
+	return f.result, nil
+}
+
+func TestKeychainStore(t *testing.T) {
+	runner := &fakeRunner{}
+	store := keychainStore{run: runner.run}
+
+	runner.result = commandResult{stdout: "s3cret\n"}
+	if secret, err := store.Get("nuget:feed", "ci"); err != nil || secret != "s3cret" {
+		t.Errorf("Get() = %q, %v", secret, err)
+	}
+	want := []string{"security", "find-generic-password", "-s", "nuget:feed", "-a", "ci", "-w"}
+	if !reflect.DeepEqual(runner.args, want) {
+		t.Errorf("Get() ran %v, want %v", runner.args, want)
+	}
+
+	runner.result = commandResult{exitCode: keychainItemNotFound}
+	if _, err := store.Get("nuget:feed", "ci"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get() of a missing item error = %v, want ErrSecretNotFound", err)
+	}
+	if err := store.Delete("nuget:feed", "ci"); err != nil {
+		t.Errorf("Delete() of a missing item error = %v", err)
+	}
+
+	runner.result = commandResult{}
+	if err := store.Set("nuget:it's", "ci", "pw"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if want := "add-generic-password -U -s 'nuget:it'\"'\"'s' -a 'ci' -X 7077\n"; runner.stdin != want {
+		t.Errorf("Set() wrote %q, want %q", runner.stdin, want)
+	}
+	if strings.Contains(strings.Join(runner.args, " "), "pw") {
+		t.Errorf("Set() passed the secret on the command line: %v", runner.args)
+	}
+
+	runner.result = commandResult{stderr: "security: SecKeychainItemCreateFromContent: User interaction is not allowed.\n"}
+	if err := store.Set("nuget:feed", "ci", "pw"); err == nil || !strings.Contains(err.Error(), "User interaction") {
+		t.Errorf("Set() error = %v, want the security error message", err)
+	}
+}
+
+func TestSecretServiceStore(t *testing.T) {
+	runner := &fakeRunner{}
+	store := secretServiceStore{run: runner.run}
+
+	if err := store.Set("nuget:feed", "ci", "s3cret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if runner.stdin != "s3cret" {
+		t.Errorf("Set() wrote %q to stdin", runner.stdin)
+	}
+	want := []string{"secret-tool", "store", "--label=nuget:feed (ci)", "service", "nuget:feed", "account", "ci"}
+	if !reflect.DeepEqual(runner.args, want) {
+		t.Errorf("Set() ran %v, want %v", runner.args, want)
+	}
+
+	runner.result = commandResult{stdout: "s3cret"}
+	if secret, err := store.Get("nuget:feed", "ci"); err != nil || secret != "s3cret" {
+		t.Errorf("Get() = %q, %v", secret, err)
+	}
+
+	runner.result = commandResult{exitCode: 1}
+	if _, err := store.Get("nuget:feed", "ci"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get() of a missing secret error = %v, want ErrSecretNotFound", err)
+	}
+	if err := store.Delete("nuget:feed", "ci"); err != nil {
+		t.Errorf("Delete() of a missing secret error = %v", err)
+	}
+
+	runner.result = commandResult{exitCode: 1, stderr: "secret-tool: Cannot autolaunch D-Bus without X11 $DISPLAY\n"}
+	if _, err := store.Get("nuget:feed", "ci"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get() error = %v, want the D-Bus error", err)
+	}
+	if err := store.Delete("nuget:feed", "ci"); err == nil {
+		t.Error("Delete() should report the D-Bus error")
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	if got := ServiceName("private-feed"); got != "nuget:private-feed" {
+		t.Errorf("ServiceName() = %q", got)
+	}
+}
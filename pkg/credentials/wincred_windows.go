@@ -0,0 +1,116 @@
+//go:build windows
+
+package credentials
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Windows 凭据管理器的常量
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32        = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW   = advapi32.NewProc("CredReadW")
+	procCredWriteW  = advapi32.NewProc("CredWriteW")
+	procCredDeleteW = advapi32.NewProc("CredDeleteW")
+	procCredFree    = advapi32.NewProc("CredFree")
+)
+
+// credential 对应 Win32 的 CREDENTIALW 结构
+type credential struct {
+	flags              uint32
+	credType           uint32
+	targetName         *uint16
+	comment            *uint16
+	lastWritten        syscall.Filetime
+	credentialBlobSize uint32
+	credentialBlob     *byte
+	persist            uint32
+	attributeCount     uint32
+	attributes         uintptr
+	targetAlias        *uint16
+	userName           *uint16
+}
+
+// wincredStore 使用 Windows 凭据管理器中的普通凭据
+//
+// 目标名称为 "service:account"，密码以 UTF-8 编码保存，持久化范围为当前用户在本机的所有登录会话。
+type wincredStore struct{}
+
+// defaultStore Windows 上使用凭据管理器
+func defaultStore() (Store, error) {
+	return wincredStore{}, nil
+}
+
+// Get 读取密码
+func (wincredStore) Get(service, account string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(targetName(service, account))
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if err == errorNotFound {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("failed to read from credential manager: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.credentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.credentialBlob, cred.credentialBlobSize)), nil
+}
+
+// Set 保存密码
+func (wincredStore) Set(service, account, secret string) error {
+	target, err := syscall.UTF16PtrFromString(targetName(service, account))
+	if err != nil {
+		return err
+	}
+	userName, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	cred := credential{
+		credType:   credTypeGeneric,
+		targetName: target,
+		persist:    credPersistLocalMachine,
+		userName:   userName,
+	}
+	if secret != "" {
+		blob := []byte(secret)
+		cred.credentialBlobSize = uint32(len(blob))
+		cred.credentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("failed to write to credential manager: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除密码
+func (wincredStore) Delete(service, account string) error {
+	target, err := syscall.UTF16PtrFromString(targetName(service, account))
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDeleteW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); r == 0 && err != errorNotFound {
+		return fmt.Errorf("failed to delete from credential manager: %w", err)
+	}
+	return nil
+}
+
+// targetName 返回凭据的目标名称
+func targetName(service, account string) string {
+	return service + ":" + account
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package credentials
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWincredRoundTrip(t *testing.T) {
+	store := wincredStore{}
+	service := ServiceName("nuget-config-parser-test")
+	t.Cleanup(func() { store.Delete(service, "ci") })
+
+	if err := store.Set(service, "ci", "密码"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if secret, err := store.Get(service, "ci"); err != nil || secret != "密码" {
+		t.Errorf("Get() = %q, %v", secret, err)
+	}
+	if err := store.Delete(service, "ci"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(service, "ci"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrSecretNotFound", err)
+	}
+	if err := store.Delete(service, "ci"); err != nil {
+		t.Errorf("Delete() of a missing credential error = %v", err)
+	}
+}
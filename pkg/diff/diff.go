@@ -0,0 +1,380 @@
+// Package diff 比较两个 NuGet 配置并给出结构化的差异，
+// 可用于命令行工具和 CI 检查中说明一次修改改变了哪些配置
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// MaskedValue 差异中代替密码等敏感值显示的内容
+const MaskedValue = "********"
+
+// 配置节名称，与XML中的元素名一致
+const (
+	SectionPackageSources           = "packageSources"
+	SectionPackageSourceCredentials = "packageSourceCredentials"
+	SectionConfig                   = "config"
+	SectionDisabledPackageSources   = "disabledPackageSources"
+	SectionActivePackageSource      = "activePackageSource"
+	SectionPackageSourceMapping     = "packageSourceMapping"
+)
+
+// Kind 变化的类型
+type Kind int
+
+const (
+	// Added 新增的项
+	Added Kind = iota
+	// Removed 删除的项
+	Removed
+	// Modified 修改的项
+	Modified
+)
+
+// String 返回变化类型的名称
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("kind(%d)", int(k))
+	}
+}
+
+// Change 两个配置之间的一处差异
+type Change struct {
+	// Section 发生变化的配置节，如 SectionPackageSources
+	Section string
+
+	// Key 发生变化的项，包源和配置选项为其 key，凭证和包源映射为包源名称，
+	// 配置节本身的变化（如 clear）为空
+	Key string
+
+	// Field 发生变化的属性、凭证项或包ID模式，整项新增或删除时为空
+	Field string
+
+	// Kind 变化的类型
+	Kind Kind
+
+	// Old 修改前的值，新增时为空，敏感值显示为 MaskedValue
+	Old string
+
+	// New 修改后的值，删除时为空，敏感值显示为 MaskedValue
+	New string
+}
+
+// String 格式化差异，如 `packageSources "nuget.org" value modified: "a" -> "b"`
+func (c Change) String() string {
+	target := c.Section
+	if c.Key != "" {
+		target += fmt.Sprintf(" %q", c.Key)
+	}
+	if c.Field != "" {
+		target += " " + c.Field
+	}
+
+	switch c.Kind {
+	case Added:
+		if c.New == "" {
+			return target + " added"
+		}
+		return fmt.Sprintf("%s added: %q", target, c.New)
+	case Removed:
+		if c.Old == "" {
+			return target + " removed"
+		}
+		return fmt.Sprintf("%s removed: %q", target, c.Old)
+	default:
+		return fmt.Sprintf("%s modified: %q -> %q", target, c.Old, c.New)
+	}
+}
+
+// IsSecretKey 判断凭证项或配置选项的 key 是否为敏感信息，如 ClearTextPassword、http_proxy.password
+//
+// 与 types.IsSecretKey 相同，config.Redacted() 使用同一规则。
+func IsSecretKey(key string) bool {
+	return types.IsSecretKey(key)
+}
+
+// Compare 比较两个配置，返回从 old 到 new 的所有差异
+//
+// 各配置节按 key 比较，同一配置节中 key 重复时与 NuGet 一样以第一个定义为准。
+// 差异按配置节排列，同一配置节中先列出修改和删除的项（按 old 中的顺序），
+// 再列出新增的项（按 new 中的顺序）。凭证和配置选项中的敏感值会被替换为 MaskedValue。
+// 任一参数为 nil 时视为空配置。
+func Compare(old, new *types.NuGetConfig) []Change {
+	if old == nil {
+		old = &types.NuGetConfig{}
+	}
+	if new == nil {
+		new = &types.NuGetConfig{}
+	}
+
+	var changes []Change
+	changes = append(changes, comparePackageSources(old, new)...)
+	changes = append(changes, compareCredentials(old.PackageSourceCredentials, new.PackageSourceCredentials)...)
+	changes = append(changes, compareConfig(old.Config, new.Config)...)
+	changes = append(changes, compareDisabledSources(old.DisabledPackageSources, new.DisabledPackageSources)...)
+	changes = append(changes, compareActiveSource(old.ActivePackageSource, new.ActivePackageSource)...)
+	changes = append(changes, compareMapping(old.PackageSourceMapping, new.PackageSourceMapping)...)
+	return changes
+}
+
+// entry 配置节中一项的属性，按属性名称索引
+type entry map[string]string
+
+// entries 按 key 索引的配置节中的项，keys 保持第一次出现的顺序
+type entries struct {
+	keys   []string
+	values map[string]entry
+}
+
+// add 添加一项，key 重复时忽略之后的定义
+func (e *entries) add(key string, attrs entry) {
+	if e.values == nil {
+		e.values = make(map[string]entry)
+	}
+	if _, exists := e.values[key]; exists {
+		return
+	}
+	e.keys = append(e.keys, key)
+	e.values[key] = attrs
+}
+
+// compareEntries 比较两组项，fields 为需要比较的属性，secret 判断属性值是否需要隐藏
+func compareEntries(section string, old, new entries, fields []string, secret func(key string) bool) []Change {
+	mask := func(key, value string) string {
+		if value != "" && secret != nil && secret(key) {
+			return MaskedValue
+		}
+		return value
+	}
+
+	var changes []Change
+	for _, key := range old.keys {
+		oldAttrs := old.values[key]
+		newAttrs, exists := new.values[key]
+		if !exists {
+			changes = append(changes, Change{Section: section, Key: key, Kind: Removed, Old: mask(key, oldAttrs[fields[0]])})
+			continue
+		}
+		for _, field := range fields {
+			if oldAttrs[field] != newAttrs[field] {
+				changes = append(changes, Change{
+					Section: section,
+					Key:     key,
+					Field:   field,
+					Kind:    Modified,
+					Old:     mask(key, oldAttrs[field]),
+					New:     mask(key, newAttrs[field]),
+				})
+			}
+		}
+	}
+
+	for _, key := range new.keys {
+		if _, exists := old.values[key]; !exists {
+			changes = append(changes, Change{Section: section, Key: key, Kind: Added, New: mask(key, new.values[key][fields[0]])})
+		}
+	}
+	return changes
+}
+
+// compareClear 比较配置节是否清除了继承的项
+func compareClear(section string, old, new bool) []Change {
+	switch {
+	case !old && new:
+		return []Change{{Section: section, Field: "clear", Kind: Added}}
+	case old && !new:
+		return []Change{{Section: section, Field: "clear", Kind: Removed}}
+	}
+	return nil
+}
+
+// comparePackageSources 比较包源
+func comparePackageSources(old, new *types.NuGetConfig) []Change {
+	collect := func(sources types.PackageSources) entries {
+		var e entries
+		for _, source := range sources.Add {
+			e.add(source.Key, entry{"value": source.Value, "protocolVersion": source.ProtocolVersion})
+		}
+		return e
+	}
+
+	changes := compareClear(SectionPackageSources, old.PackageSources.IsCleared(), new.PackageSources.IsCleared())
+	return append(changes, compareEntries(SectionPackageSources, collect(old.PackageSources), collect(new.PackageSources),
+		[]string{"value", "protocolVersion"}, nil)...)
+}
+
+// compareCredentials 比较包源凭证，凭证项的值为敏感信息时会被隐藏
+func compareCredentials(old, new *types.PackageSourceCredentials) []Change {
+	sources := func(creds *types.PackageSourceCredentials) map[string]types.SourceCredential {
+		if creds == nil {
+			return nil
+		}
+		return creds.Sources
+	}
+	oldSources, newSources := sources(old), sources(new)
+
+	var changes []Change
+	for _, name := range sortedKeys(oldSources) {
+		newCred, exists := newSources[name]
+		if !exists {
+			changes = append(changes, Change{Section: SectionPackageSourceCredentials, Key: name, Kind: Removed})
+			continue
+		}
+		changes = append(changes, compareCredentialItems(name, oldSources[name], newCred)...)
+	}
+	for _, name := range sortedKeys(newSources) {
+		if _, exists := oldSources[name]; !exists {
+			changes = append(changes, Change{Section: SectionPackageSourceCredentials, Key: name, Kind: Added})
+		}
+	}
+	return changes
+}
+
+// compareCredentialItems 比较一个包源的凭证项，差异的 Field 为凭证项的 key
+func compareCredentialItems(source string, old, new types.SourceCredential) []Change {
+	collect := func(cred types.SourceCredential) entries {
+		var e entries
+		for _, item := range cred.Add {
+			e.add(item.Key, entry{"value": item.Value})
+		}
+		return e
+	}
+
+	var changes []Change
+	for _, change := range compareEntries(SectionPackageSourceCredentials, collect(old), collect(new), []string{"value"}, IsSecretKey) {
+		change.Field = change.Key
+		change.Key = source
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// compareConfig 比较配置选项，敏感选项的值会被隐藏
+func compareConfig(old, new *types.Config) []Change {
+	collect := func(config *types.Config) entries {
+		var e entries
+		if config != nil {
+			for _, option := range config.Add {
+				e.add(option.Key, entry{"value": option.Value})
+			}
+		}
+		return e
+	}
+
+	changes := compareClear(SectionConfig, old.IsCleared(), new.IsCleared())
+	return append(changes, compareEntries(SectionConfig, collect(old), collect(new), []string{"value"}, IsSecretKey)...)
+}
+
+// compareDisabledSources 比较禁用的包源
+func compareDisabledSources(old, new *types.DisabledPackageSources) []Change {
+	collect := func(disabled *types.DisabledPackageSources) entries {
+		var e entries
+		if disabled != nil {
+			for _, source := range disabled.Add {
+				e.add(source.Key, entry{"value": source.Value})
+			}
+		}
+		return e
+	}
+
+	changes := compareClear(SectionDisabledPackageSources, old.IsCleared(), new.IsCleared())
+	return append(changes, compareEntries(SectionDisabledPackageSources, collect(old), collect(new), []string{"value"}, nil)...)
+}
+
+// compareActiveSource 比较活跃包源
+func compareActiveSource(old, new *types.ActivePackageSource) []Change {
+	collect := func(active *types.ActivePackageSource) entries {
+		var e entries
+		if active != nil && active.Add.Key != "" {
+			e.add(active.Add.Key, entry{"value": active.Add.Value})
+		}
+		return e
+	}
+	return compareEntries(SectionActivePackageSource, collect(old), collect(new), []string{"value"}, nil)
+}
+
+// compareMapping 比较包源映射
+//
+// 整个包源的映射新增或删除时，值为以逗号分隔的包ID模式；
+// 否则每个新增或删除的模式各为一处差异，Field 为 "package"。
+func compareMapping(old, new *types.PackageSourceMapping) []Change {
+	collect := func(mapping *types.PackageSourceMapping) (keys []string, patterns map[string][]string) {
+		patterns = make(map[string][]string)
+		if mapping == nil {
+			return nil, patterns
+		}
+		for _, source := range mapping.PackageSource {
+			if _, exists := patterns[source.Key]; !exists {
+				keys = append(keys, source.Key)
+			}
+			list := []string{}
+			for _, pkg := range source.Package {
+				list = append(list, pkg.Pattern)
+			}
+			patterns[source.Key] = list
+		}
+		return keys, patterns
+	}
+	oldKeys, oldPatterns := collect(old)
+	newKeys, newPatterns := collect(new)
+
+	changes := compareClear(SectionPackageSourceMapping, old.IsCleared(), new.IsCleared())
+	for _, key := range oldKeys {
+		newList, exists := newPatterns[key]
+		if !exists {
+			changes = append(changes, Change{Section: SectionPackageSourceMapping, Key: key, Kind: Removed,
+				Old: strings.Join(oldPatterns[key], ", ")})
+			continue
+		}
+		for _, pattern := range difference(oldPatterns[key], newList) {
+			changes = append(changes, Change{Section: SectionPackageSourceMapping, Key: key, Field: "package", Kind: Removed, Old: pattern})
+		}
+		for _, pattern := range difference(newList, oldPatterns[key]) {
+			changes = append(changes, Change{Section: SectionPackageSourceMapping, Key: key, Field: "package", Kind: Added, New: pattern})
+		}
+	}
+	for _, key := range newKeys {
+		if _, exists := oldPatterns[key]; !exists {
+			changes = append(changes, Change{Section: SectionPackageSourceMapping, Key: key, Kind: Added,
+				New: strings.Join(newPatterns[key], ", ")})
+		}
+	}
+	return changes
+}
+
+// difference 返回 a 中存在而 b 中不存在的元素，保持 a 中的顺序
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !in[s] {
+			out = append(out, s)
+			in[s] = true
+		}
+	}
+	return out
+}
+
+// sortedKeys 返回按名称排序的凭证包源名称
+func sortedKeys(m map[string]types.SourceCredential) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
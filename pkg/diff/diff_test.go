@@ -0,0 +1,160 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func parse(t *testing.T, content string) *types.NuGetConfig {
+	t.Helper()
+	p := parser.NewConfigParser()
+	p.AllowEmptyPackageSources = true
+	config, err := p.ParseFromString(content)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+	return config
+}
+
+func TestCompare(t *testing.T) {
+	old := parse(t, `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="legacy" value="https://legacy.example.com/nuget" />
+    <add key="team" value="https://team.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <team>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="old-secret" />
+    </team>
+    <legacy>
+      <add key="Username" value="bob" />
+    </legacy>
+  </packageSourceCredentials>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+    <add key="http_proxy.password" value="proxy-old" />
+  </config>
+  <packageSourceMapping>
+    <packageSource key="team">
+      <package pattern="Team.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`)
+
+	new := parse(t, `<configuration>
+  <packageSources>
+    <clear />
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="team" value="https://team.example.com/v3/index.json" protocolVersion="3" />
+    <add key="local" value="/feed" />
+  </packageSources>
+  <packageSourceCredentials>
+    <team>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="new-secret" />
+    </team>
+  </packageSourceCredentials>
+  <config>
+    <add key="globalPackagesFolder" value="/cache" />
+    <add key="http_proxy.password" value="proxy-new" />
+  </config>
+  <disabledPackageSources>
+    <add key="local" value="true" />
+  </disabledPackageSources>
+  <packageSourceMapping>
+    <packageSource key="team">
+      <package pattern="Team.*" />
+      <package pattern="Shared.*" />
+    </packageSource>
+    <packageSource key="nuget.org">
+      <package pattern="*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`)
+
+	expected := []Change{
+		{Section: SectionPackageSources, Field: "clear", Kind: Added},
+		{Section: SectionPackageSources, Key: "legacy", Kind: Removed, Old: "https://legacy.example.com/nuget"},
+		{Section: SectionPackageSources, Key: "team", Field: "protocolVersion", Kind: Modified, New: "3"},
+		{Section: SectionPackageSources, Key: "local", Kind: Added, New: "/feed"},
+		{Section: SectionPackageSourceCredentials, Key: "legacy", Kind: Removed},
+		{Section: SectionPackageSourceCredentials, Key: "team", Field: "ClearTextPassword", Kind: Modified, Old: MaskedValue, New: MaskedValue},
+		{Section: SectionConfig, Key: "globalPackagesFolder", Field: "value", Kind: Modified, Old: "/packages", New: "/cache"},
+		{Section: SectionConfig, Key: "http_proxy.password", Field: "value", Kind: Modified, Old: MaskedValue, New: MaskedValue},
+		{Section: SectionDisabledPackageSources, Key: "local", Kind: Added, New: "true"},
+		{Section: SectionPackageSourceMapping, Key: "team", Field: "package", Kind: Added, New: "Shared.*"},
+		{Section: SectionPackageSourceMapping, Key: "nuget.org", Kind: Added, New: "*"},
+	}
+
+	changes := Compare(old, new)
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Compare() =\n%v\nwant\n%v", changes, expected)
+	}
+
+	for _, change := range changes {
+		if s := change.String(); strings.Contains(s, "secret") || strings.Contains(s, "proxy-") {
+			t.Errorf("change leaks a secret: %s", s)
+		}
+	}
+}
+
+func TestCompareIdenticalAndNil(t *testing.T) {
+	config := parse(t, `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`)
+
+	if changes := Compare(config, config); len(changes) != 0 {
+		t.Errorf("Compare() of identical configs = %v", changes)
+	}
+
+	changes := Compare(nil, config)
+	if len(changes) != 1 || changes[0].Kind != Added || changes[0].Key != "a" {
+		t.Errorf("Compare(nil, config) = %v", changes)
+	}
+}
+
+func TestCompareDuplicateKeys(t *testing.T) {
+	// 重复的 key 以第一个定义为准，只修改之后的定义不产生差异
+	old := parse(t, `<configuration>
+  <packageSources>
+    <add key="a" value="https://first.example.com" />
+    <add key="a" value="https://second.example.com" />
+  </packageSources>
+</configuration>`)
+	new := parse(t, `<configuration>
+  <packageSources>
+    <add key="a" value="https://first.example.com" />
+    <add key="a" value="https://changed.example.com" />
+  </packageSources>
+</configuration>`)
+
+	if changes := Compare(old, new); len(changes) != 0 {
+		t.Errorf("Compare() = %v, want no changes", changes)
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	tests := []struct {
+		change   Change
+		expected string
+	}{
+		{Change{Section: SectionPackageSources, Key: "a", Field: "value", Kind: Modified, Old: "x", New: "y"}, `packageSources "a" value modified: "x" -> "y"`},
+		{Change{Section: SectionPackageSources, Key: "a", Kind: Added, New: "x"}, `packageSources "a" added: "x"`},
+		{Change{Section: SectionPackageSourceCredentials, Key: "a", Kind: Removed}, `packageSourceCredentials "a" removed`},
+		{Change{Section: SectionConfig, Field: "clear", Kind: Added}, `config clear added`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const activePackageSourcePath = "configuration/activePackageSource"
+
+// SetActivePackageSource 设置活跃包源
+//
+// 包源的URL取自 <packageSources> 中的同名条目。<activePackageSource> 已存在时
+// 原地更新其中的 key 和 value 属性，否则按文件现有缩进创建该节。
+func (e *ConfigEditor) SetActivePackageSource(key string) error {
+	source, exists := e.findSourceInConfig(key)
+	if !exists {
+		return fmt.Errorf("未找到包源: %s", key)
+	}
+
+	var entry *parser.ElementPosition
+	if section, exists := e.findElement(activePackageSourcePath); exists {
+		if adds := e.childElements(section, "add"); len(adds) > 0 {
+			entry = adds[0]
+		}
+	}
+
+	if entry != nil {
+		for _, a := range []attr{{"key", source.Key}, {"value", source.Value}} {
+			if entry.Attributes[a.name] == a.value {
+				continue
+			}
+			if err := e.setAttribute(entry, a.name, a.value); err != nil {
+				return err
+			}
+		}
+	} else {
+		entryXML := e.formatElement("add", attr{"key", source.Key}, attr{"value", source.Value})
+		build := func(string) string { return entryXML }
+		if !e.replacePending("activePackageSource", "", build) {
+			if err := e.addToSection("activePackageSource", "", build); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 同时更新内存中的配置对象
+	e.parseResult.Config.ActivePackageSource = &types.ActivePackageSource{
+		Add: types.PackageSource{Key: source.Key, Value: source.Value},
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetActivePackageSourceUpdatesInPlace(t *testing.T) {
+	content := strings.Replace(testConfig, `  </config>
+`, `  </config>
+  <activePackageSource>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </activePackageSource>
+`, 1)
+	editor := newTestEditor(t, content)
+
+	if err := editor.SetActivePackageSource("local"); err != nil {
+		t.Fatalf("设置活跃包源失败: %v", err)
+	}
+
+	expected := strings.Replace(content,
+		`<add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </activePackageSource>`,
+		`<add key="local" value="C:\LocalPackages" />
+  </activePackageSource>`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	active := editor.GetConfig().ActivePackageSource
+	if active == nil || active.Add.Key != "local" || active.Add.Value != "C:\\LocalPackages" {
+		t.Errorf("内存中的活跃包源未更新: %+v", active)
+	}
+}
+
+func TestSetActivePackageSourceCreatesSection(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.SetActivePackageSource("nuget.org"); err != nil {
+		t.Fatalf("设置活跃包源失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, `  </config>
+`, `  </config>
+  <activePackageSource>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </activePackageSource>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if err := editor.SetActivePackageSource("missing"); err == nil {
+		t.Error("设置不存在的包源应返回错误")
+	}
+}
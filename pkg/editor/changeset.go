@@ -0,0 +1,195 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// ChangeSet 描述对配置文件的一组期望变更
+//
+// 适用于先计算出期望状态、再由编辑器以最小差异写回文件的工具。
+// 所有变更都是幂等的：已经满足期望状态的项不会产生任何编辑。
+type ChangeSet struct {
+	// AddSources 要添加的包源，同名包源已存在时按新的值更新
+	AddSources []types.PackageSource
+
+	// UpdateSources 要更新的包源，按键匹配，Value 或 ProtocolVersion 为空时保持原值
+	UpdateSources []types.PackageSource
+
+	// RemoveSources 要删除的包源键，不存在的包源会被忽略
+	RemoveSources []string
+
+	// SetOptions 要设置的 <config> 配置选项
+	SetOptions map[string]string
+
+	// RemoveOptions 要删除的 <config> 配置选项键，不存在的选项会被忽略
+	RemoveOptions []string
+
+	// UpsertCredentials 要添加或更新的包源凭证
+	UpsertCredentials []CredentialChange
+
+	// RemoveCredentials 要删除凭证的包源键，不存在的凭证会被忽略
+	RemoveCredentials []string
+}
+
+// CredentialChange 描述单个包源凭证的期望状态
+type CredentialChange struct {
+	SourceKey string
+	Username  string
+	Password  string
+}
+
+// ApplyChangeSet 将变更集转换为按顺序排列的位置编辑
+//
+// 变更按删除、更新、添加的顺序生成，全部在一个事务中完成：
+// 任一变更失败时之前生成的编辑都会回滚，内存配置保持不变。
+// 已有状态以内存配置为准，之前尚未应用的编辑同样计算在内；多项变更需要新建同一个
+// 配置节时只新建一次。
+func (e *ConfigEditor) ApplyChangeSet(cs *ChangeSet) error {
+	if cs == nil {
+		return nil
+	}
+
+	return e.Transaction(func(e *ConfigEditor) error {
+		for _, sourceKey := range cs.RemoveCredentials {
+			if !e.hasCredentialInConfig(sourceKey) {
+				continue
+			}
+			if err := e.RemoveCredential(sourceKey); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range cs.RemoveOptions {
+			if _, exists := e.configOptionValue(key); !exists {
+				continue
+			}
+			if err := e.RemoveConfigOption(key); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range cs.RemoveSources {
+			if _, exists := e.findSourceInConfig(key); !exists {
+				continue
+			}
+			if err := e.RemovePackageSource(key); err != nil {
+				return err
+			}
+		}
+
+		for _, source := range cs.UpdateSources {
+			if _, exists := e.findSourceInConfig(source.Key); !exists {
+				return fmt.Errorf("未找到包源: %s", source.Key)
+			}
+			if err := e.reconcileSource(source); err != nil {
+				return err
+			}
+		}
+
+		for _, source := range cs.AddSources {
+			if _, exists := e.findSourceInConfig(source.Key); exists {
+				if err := e.reconcileSource(source); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := e.AddPackageSource(source.Key, source.Value, source.ProtocolVersion); err != nil {
+				return err
+			}
+		}
+
+		// 按键排序，保证生成的编辑顺序稳定
+		keys := make([]string, 0, len(cs.SetOptions))
+		for key := range cs.SetOptions {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := cs.SetOptions[key]
+			if current, exists := e.configOptionValue(key); exists && current == value {
+				continue
+			}
+			if err := e.SetConfigOption(key, value); err != nil {
+				return err
+			}
+		}
+
+		for _, change := range cs.UpsertCredentials {
+			if err := e.reconcileCredential(change); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// reconcileSource 将已有包源更新为期望的值，值为空的属性保持不变
+func (e *ConfigEditor) reconcileSource(desired types.PackageSource) error {
+	current, _ := e.findSourceInConfig(desired.Key)
+
+	if desired.Value != "" && desired.Value != current.Value {
+		if err := e.UpdatePackageSourceURL(desired.Key, desired.Value); err != nil {
+			return err
+		}
+	}
+	if desired.ProtocolVersion != "" && desired.ProtocolVersion != current.ProtocolVersion {
+		if err := e.UpdatePackageSourceVersion(desired.Key, desired.ProtocolVersion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcileCredential 添加凭证，或在凭证与期望不一致时更新凭证
+func (e *ConfigEditor) reconcileCredential(change CredentialChange) error {
+	if !e.hasCredentialInConfig(change.SourceKey) {
+		return e.AddCredential(change.SourceKey, change.Username, change.Password)
+	}
+
+	var username, password string
+	var hasPassword bool
+	if creds := e.parseResult.Config.PackageSourceCredentials; creds != nil {
+		for _, cred := range creds.Sources[change.SourceKey].Add {
+			switch cred.Key {
+			case "Username":
+				username = cred.Value
+			case "ClearTextPassword":
+				password = cred.Value
+				hasPassword = true
+			}
+		}
+	}
+	if username == change.Username && hasPassword && password == change.Password {
+		return nil
+	}
+
+	return e.UpdateCredential(change.SourceKey, change.Username, change.Password)
+}
+
+// findSourceInConfig 在内存配置中查找包源
+func (e *ConfigEditor) findSourceInConfig(key string) (types.PackageSource, bool) {
+	for _, source := range e.parseResult.Config.PackageSources.Add {
+		if source.Key == key {
+			return source, true
+		}
+	}
+	return types.PackageSource{}, false
+}
+
+// configOptionValue 在内存配置中查找配置选项的值
+func (e *ConfigEditor) configOptionValue(key string) (string, bool) {
+	if e.parseResult.Config.Config == nil {
+		return "", false
+	}
+	for _, option := range e.parseResult.Config.Config.Add {
+		if option.Key == key {
+			return option.Value, true
+		}
+	}
+	return "", false
+}
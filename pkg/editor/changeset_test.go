@@ -0,0 +1,145 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestApplyChangeSet(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	cs := &ChangeSet{
+		AddSources: []types.PackageSource{
+			{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+			{Key: "local", Value: "/opt/packages"},
+		},
+		RemoveSources:     []string{"missing"},
+		SetOptions:        map[string]string{"globalPackagesFolder": "/opt/global"},
+		UpsertCredentials: []CredentialChange{{SourceKey: "private", Username: "alice", Password: "secret"}},
+	}
+	if err := editor.ApplyChangeSet(cs); err != nil {
+		t.Fatalf("应用变更集失败: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+    <config>
+        <add key="globalPackagesFolder" value="/opt/global" />
+    </config>
+    <packageSources>
+        <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+        <add key="private" value="https://private.example.com/v3/index.json" />
+        <add key="local" value="/opt/packages" />
+    </packageSources>
+    <!-- credentials -->
+    <packageSourceCredentials>
+        <private>
+            <add key="Username" value="alice" />
+            <add key="ClearTextPassword" value="secret" />
+        </private>
+    </packageSourceCredentials>
+</configuration>`
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	// 再次应用同一变更集不应产生新的编辑
+	editCount := len(editor.edits)
+	if err := editor.ApplyChangeSet(cs); err != nil {
+		t.Fatalf("再次应用变更集失败: %v", err)
+	}
+	if len(editor.edits) != editCount {
+		t.Errorf("已满足期望状态时不应产生编辑，新增编辑数: %d", len(editor.edits)-editCount)
+	}
+}
+
+func TestApplyChangeSetRollback(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	cs := &ChangeSet{
+		RemoveSources: []string{"local"},
+		UpdateSources: []types.PackageSource{{Key: "missing", Value: "https://example.com"}},
+	}
+	err := editor.ApplyChangeSet(cs)
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("更新不存在的包源应返回错误，实际: %v", err)
+	}
+
+	if len(editor.edits) != 0 {
+		t.Errorf("失败的变更集不应留下编辑，实际: %d", len(editor.edits))
+	}
+	if len(editor.GetConfig().PackageSources.Add) != 2 {
+		t.Error("失败的变更集不应修改内存配置")
+	}
+}
+
+func TestApplyChangeSetCreatesSectionsOnce(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com/v3/index.json" />
+    <add key="b" value="https://b.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	cs := &ChangeSet{
+		SetOptions: map[string]string{"globalPackagesFolder": "/opt/global", "http_proxy": "http://proxy"},
+		UpsertCredentials: []CredentialChange{
+			{SourceKey: "a", Username: "alice", Password: "secret-a"},
+			{SourceKey: "b", Username: "bob", Password: "secret-b"},
+		},
+	}
+	if err := editor.ApplyChangeSet(cs); err != nil {
+		t.Fatalf("应用变更集失败: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <config>
+    <add key="globalPackagesFolder" value="/opt/global" />
+    <add key="http_proxy" value="http://proxy" />
+  </config>
+  <packageSources>
+    <add key="a" value="https://a.example.com/v3/index.json" />
+    <add key="b" value="https://b.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <a>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="secret-a" />
+    </a>
+    <b>
+      <add key="Username" value="bob" />
+      <add key="ClearTextPassword" value="secret-b" />
+    </b>
+  </packageSourceCredentials>
+</configuration>`
+	if got := applyValidated(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	// 尚未应用的变更同样计算在已有状态中
+	editCount := len(editor.edits)
+	if err := editor.ApplyChangeSet(cs); err != nil {
+		t.Fatalf("再次应用变更集失败: %v", err)
+	}
+	if len(editor.edits) != editCount {
+		t.Errorf("已满足期望状态时不应产生编辑，新增编辑数: %d", len(editor.edits)-editCount)
+	}
+
+	err := editor.ApplyChangeSet(&ChangeSet{
+		RemoveOptions:     []string{"http_proxy"},
+		RemoveCredentials: []string{"a"},
+		UpsertCredentials: []CredentialChange{{SourceKey: "b", Username: "bob", Password: "rotated"}},
+	})
+	if err != nil {
+		t.Fatalf("应用变更集失败: %v", err)
+	}
+	got := applyValidated(t, editor)
+	if strings.Contains(got, "http_proxy") || strings.Contains(got, "alice") || !strings.Contains(got, `value="rotated"`) {
+		t.Errorf("修改后的内容不符合预期:\n%s", got)
+	}
+}
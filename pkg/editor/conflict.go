@@ -0,0 +1,143 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EditConflict 描述两个相互冲突的编辑操作，First 为先加入队列的编辑
+type EditConflict struct {
+	First  Edit
+	Second Edit
+}
+
+// EditConflictError 表示待应用的编辑之间存在无法合并的范围重叠
+type EditConflictError struct {
+	Conflicts []EditConflict
+}
+
+// Error 列出所有冲突的编辑操作
+func (e *EditConflictError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("%s 与 %s", describeEdit(c.First), describeEdit(c.Second)))
+	}
+	return fmt.Sprintf("编辑操作冲突: %s", strings.Join(parts, "; "))
+}
+
+// describeEdit 返回编辑操作的简短描述
+func describeEdit(edit Edit) string {
+	return fmt.Sprintf("%s[%d,%d) %q", edit.Type, edit.Range.Start.Offset, edit.Range.End.Offset, edit.NewText)
+}
+
+// resolveEdits 检查编辑之间的冲突并合并兼容的编辑，返回按应用顺序（从后往前）排列的编辑
+//
+// 兼容的情况包括：
+//   - 范围互不重叠，或插入点位于其他编辑范围的边界上
+//   - 完全相同的编辑，只保留一个
+//   - 同一范围上的多次更新，以最后一次为准
+//   - 位于删除范围内的更新或删除，被外层删除覆盖
+//
+// 其余重叠（例如插入点落在被删除或替换的范围内部）会返回 EditConflictError。
+func resolveEdits(edits []Edit) ([]Edit, error) {
+	dropped := make([]bool, len(edits))
+	var conflicts []EditConflict
+
+	for i := range edits {
+		for j := i + 1; j < len(edits); j++ {
+			if dropped[i] || dropped[j] {
+				continue
+			}
+
+			switch drop, ok := mergeEdits(edits[i], edits[j]); {
+			case !ok:
+				conflicts = append(conflicts, EditConflict{First: edits[i], Second: edits[j]})
+			case drop == 1:
+				dropped[i] = true
+			case drop == 2:
+				dropped[j] = true
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, &EditConflictError{Conflicts: conflicts}
+	}
+
+	// 记录队列顺序，用于同一位置多次插入时保持加入的先后顺序
+	type queued struct {
+		edit  Edit
+		index int
+	}
+	resolved := make([]queued, 0, len(edits))
+	for i, edit := range edits {
+		if !dropped[i] {
+			resolved = append(resolved, queued{edit, i})
+		}
+	}
+
+	// 按起始位置倒序应用；起始位置相同时先应用非空范围，再按加入顺序倒序应用插入，
+	// 这样同一位置的多个插入在结果中仍按加入顺序排列
+	sort.Slice(resolved, func(a, b int) bool {
+		ra, rb := resolved[a].edit.Range, resolved[b].edit.Range
+		if ra.Start.Offset != rb.Start.Offset {
+			return ra.Start.Offset > rb.Start.Offset
+		}
+		if ra.End.Offset != rb.End.Offset {
+			return ra.End.Offset > rb.End.Offset
+		}
+		return resolved[a].index > resolved[b].index
+	})
+
+	result := make([]Edit, len(resolved))
+	for i, q := range resolved {
+		result[i] = q.edit
+	}
+	return result, nil
+}
+
+// mergeEdits 判断两个编辑（first 先于 second 加入）是否兼容
+//
+// 兼容时返回需要丢弃的编辑：0 表示都保留，1 表示丢弃 first，2 表示丢弃 second。
+func mergeEdits(first, second Edit) (int, bool) {
+	fs, fe := first.Range.Start.Offset, first.Range.End.Offset
+	ss, se := second.Range.Start.Offset, second.Range.End.Offset
+
+	firstInsert := fs == fe
+	secondInsert := ss == se
+
+	switch {
+	case firstInsert && secondInsert:
+		// 多个插入总是可以按顺序共存
+		return 0, true
+	case firstInsert:
+		return 0, fs <= ss || fs >= se
+	case secondInsert:
+		return 0, ss <= fs || ss >= fe
+	}
+
+	// 两个非空范围
+	if fe <= ss || se <= fs {
+		return 0, true
+	}
+
+	if fs == ss && fe == se {
+		if first.NewText == second.NewText && first.Type == second.Type {
+			return 2, true
+		}
+		if first.Type == "update" && second.Type == "update" {
+			return 1, true
+		}
+	}
+
+	// 删除范围覆盖的编辑不再需要
+	if first.Type == "delete" && fs <= ss && se <= fe {
+		return 2, true
+	}
+	if second.Type == "delete" && ss <= fs && fe <= se {
+		return 1, true
+	}
+
+	return 0, false
+}
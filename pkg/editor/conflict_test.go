@@ -0,0 +1,100 @@
+package editor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyEditsUpdateThenRemove(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.UpdatePackageSourceURL("local", "D:\\Packages"); err != nil {
+		t.Fatalf("更新包源失败: %v", err)
+	}
+	if err := editor.RemovePackageSource("local"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, "\n    <add key=\"local\" value=\"C:\\LocalPackages\" />", "", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestApplyEditsRepeatedUpdate(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	for _, url := range []string{"https://first.example.com", "https://second.example.com"} {
+		if err := editor.UpdatePackageSourceURL("nuget.org", url); err != nil {
+			t.Fatalf("更新包源失败: %v", err)
+		}
+	}
+
+	expected := strings.Replace(testConfig, "https://api.nuget.org/v3/index.json", "https://second.example.com", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestApplyEditsInsertOrder(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources />
+</configuration>`
+	editor := newLenientTestEditor(t, content)
+
+	for _, key := range []string{"first", "second", "third"} {
+		if err := editor.AddPackageSource(key, "https://"+key+".example.com", ""); err != nil {
+			t.Fatalf("添加包源失败: %v", err)
+		}
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="first" value="https://first.example.com" />
+    <add key="second" value="https://second.example.com" />
+    <add key="third" value="https://third.example.com" />
+  </packageSources>
+</configuration>`
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestApplyEditsConflict(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key="b" value="https://b.example.com" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="a" value="true" />
+  </disabledPackageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	// 禁用 b 会在节中插入新项，随后直接删除整个节
+	if err := editor.DisablePackageSource("b"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	section, _ := editor.findElement(disabledPackageSourcesPath)
+	editor.removeElement(section)
+
+	_, err := editor.ApplyEdits()
+	var conflictErr *EditConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("期望返回 EditConflictError，实际: %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 {
+		t.Fatalf("期望 1 个冲突，实际: %d", len(conflictErr.Conflicts))
+	}
+	if c := conflictErr.Conflicts[0]; c.First.Type != "add" || c.Second.Type != "delete" {
+		t.Errorf("冲突的编辑不符合预期: %+v", c)
+	}
+	if !strings.Contains(err.Error(), "add[") || !strings.Contains(err.Error(), "delete[") {
+		t.Errorf("错误信息应列出冲突的操作: %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const packageSourceCredentialsPath = "configuration/packageSourceCredentials"
+
+// AddCredential 为包源添加凭证
+//
+// 凭证写入 <packageSourceCredentials> 节中，该节不存在时会按文件现有缩进创建，
+// 密码以 ClearTextPassword 明文存储。如果包源已有凭证（包括尚未应用的添加），请使用 UpdateCredential。
+func (e *ConfigEditor) AddCredential(sourceKey, username, password string) error {
+	return e.addCredential(sourceKey, username, "ClearTextPassword", password)
+}
+
+// AddEncryptedCredential 为包源添加凭证，密码为已加密的值
+//
+// encryptedPassword 通常由 credentials.Cipher 加密得到，以 Password 项存储，
+// 与 nuget.exe 在 Windows 上写入的格式相同。
+func (e *ConfigEditor) AddEncryptedCredential(sourceKey, username, encryptedPassword string) error {
+	return e.addCredential(sourceKey, username, "Password", encryptedPassword)
+}
+
+// addCredential 为包源添加用户名和 passwordKey 对应的密码项
+func (e *ConfigEditor) addCredential(sourceKey, username, passwordKey, password string) error {
+	if e.hasCredentialInConfig(sourceKey) {
+		return fmt.Errorf("包源凭证已存在: %s", sourceKey)
+	}
+
+	credentials := []types.Credential{
+		{Key: "Username", Value: username},
+		{Key: passwordKey, Value: password},
+	}
+
+	err := e.addToSection("packageSourceCredentials", sourceKey, func(unit string) string {
+		return e.buildCredentialXML(sourceKey, credentials, unit)
+	})
+	if err != nil {
+		return err
+	}
+
+	// 同时更新内存中的配置对象
+	config := e.parseResult.Config
+	if config.PackageSourceCredentials == nil {
+		config.PackageSourceCredentials = &types.PackageSourceCredentials{}
+	}
+	if config.PackageSourceCredentials.Sources == nil {
+		config.PackageSourceCredentials.Sources = make(map[string]types.SourceCredential)
+	}
+	config.PackageSourceCredentials.Sources[sourceKey] = types.SourceCredential{Add: credentials}
+
+	return nil
+}
+
+// UpdateCredential 更新包源已有凭证的用户名和密码
+//
+// 原有的加密 Password 项会被替换为 ClearTextPassword，其余内容保持不变。
+func (e *ConfigEditor) UpdateCredential(sourceKey, username, password string) error {
+	return e.updateCredential(sourceKey, username, "ClearTextPassword", password)
+}
+
+// UpdateEncryptedCredential 更新包源已有凭证的用户名和已加密的密码
+//
+// 原有的 ClearTextPassword 项会被替换为 Password，其余内容保持不变。
+func (e *ConfigEditor) UpdateEncryptedCredential(sourceKey, username, encryptedPassword string) error {
+	return e.updateCredential(sourceKey, username, "Password", encryptedPassword)
+}
+
+// updateCredential 更新包源的用户名，并将密码项替换为 passwordKey 对应的项
+func (e *ConfigEditor) updateCredential(sourceKey, username, passwordKey, password string) error {
+	sourceElem, exists := e.findCredentialElement(sourceKey)
+	if !exists {
+		return e.updatePendingCredential(sourceKey, username, passwordKey, password)
+	}
+
+	var usernameElem, passwordElem *parser.ElementPosition
+	for _, add := range e.childElements(sourceElem, "add") {
+		switch add.Attributes["key"] {
+		case "Username":
+			usernameElem = add
+		case "ClearTextPassword", "Password":
+			passwordElem = add
+		}
+	}
+
+	if err := e.setCredentialItem(sourceElem, usernameElem, "Username", username); err != nil {
+		return err
+	}
+	if err := e.setCredentialItem(sourceElem, passwordElem, passwordKey, password); err != nil {
+		return err
+	}
+
+	// 同时更新内存中的配置对象
+	if creds := e.parseResult.Config.PackageSourceCredentials; creds != nil {
+		cred := creds.Sources[sourceKey]
+		cred.Add = upsertCredential(cred.Add, "Username", username)
+		cred.Add = removeCredentialKey(cred.Add, otherPasswordKey(passwordKey))
+		cred.Add = upsertCredential(cred.Add, passwordKey, password)
+		creds.Sources[sourceKey] = cred
+	}
+
+	return nil
+}
+
+// RemoveCredential 删除包源的凭证
+//
+// 凭证是由尚未应用的编辑添加的时撤销该添加。删除后 <packageSourceCredentials> 中
+// 没有其他凭证时，一并删除该节，是否还有其他凭证以内存配置为准。
+func (e *ConfigEditor) RemoveCredential(sourceKey string) error {
+	if !e.hasCredentialInConfig(sourceKey) {
+		return fmt.Errorf("未找到包源凭证: %s", sourceKey)
+	}
+
+	sourceElem, inFile := e.findCredentialElement(sourceKey)
+	if !inFile && !e.cancelPending("packageSourceCredentials", sourceKey) {
+		return fmt.Errorf("未找到包源凭证: %s", sourceKey)
+	}
+
+	// 同时更新内存中的配置对象
+	creds := e.parseResult.Config.PackageSourceCredentials
+	delete(creds.Sources, sourceKey)
+
+	if len(creds.Sources) > 0 {
+		if inFile {
+			e.removeElement(sourceElem)
+		}
+		return nil
+	}
+
+	if section, exists := e.findElement(packageSourceCredentialsPath); exists {
+		e.removeElement(section)
+	}
+	e.parseResult.Config.PackageSourceCredentials = nil
+	return nil
+}
+
+// ClearAllCredentials 删除所有包源凭证
+//
+// 整个 <packageSourceCredentials> 节会被删除，适用于提交到版本库前清理配置。
+// 配置中没有凭证时不做任何修改。
+func (e *ConfigEditor) ClearAllCredentials() {
+	if e.parseResult.Config.PackageSourceCredentials == nil {
+		return
+	}
+	e.discardPendingSection("packageSourceCredentials")
+	if section, exists := e.findElement(packageSourceCredentialsPath); exists {
+		e.removeElement(section)
+	}
+
+	// 同时更新内存中的配置对象
+	e.parseResult.Config.PackageSourceCredentials = nil
+}
+
+// hasCredentialInConfig 判断内存配置中包源是否有凭证，包括尚未应用的编辑添加的凭证
+func (e *ConfigEditor) hasCredentialInConfig(sourceKey string) bool {
+	creds := e.parseResult.Config.PackageSourceCredentials
+	if creds == nil {
+		return false
+	}
+	_, exists := creds.Sources[sourceKey]
+	return exists
+}
+
+// updatePendingCredential 更新尚未应用的编辑添加的凭证，重新生成其插入的文本
+func (e *ConfigEditor) updatePendingCredential(sourceKey, username, passwordKey, password string) error {
+	if !e.hasCredentialInConfig(sourceKey) {
+		return fmt.Errorf("未找到包源凭证: %s", sourceKey)
+	}
+
+	creds := e.parseResult.Config.PackageSourceCredentials
+	cred := creds.Sources[sourceKey]
+	add := append([]types.Credential(nil), cred.Add...)
+	add = upsertCredential(add, "Username", username)
+	add = removeCredentialKey(add, otherPasswordKey(passwordKey))
+	add = upsertCredential(add, passwordKey, password)
+
+	build := func(unit string) string { return e.buildCredentialXML(sourceKey, add, unit) }
+	if !e.replacePending("packageSourceCredentials", sourceKey, build) {
+		return fmt.Errorf("未找到包源凭证: %s", sourceKey)
+	}
+
+	cred.Add = add
+	creds.Sources[sourceKey] = cred
+	return nil
+}
+
+// findCredentialElement 查找包源凭证元素，元素名为编码后的包源名称
+func (e *ConfigEditor) findCredentialElement(sourceKey string) (*parser.ElementPosition, bool) {
+	return e.findElement(packageSourceCredentialsPath + "/" + types.EncodeElementName(sourceKey))
+}
+
+// setCredentialItem 更新已有的凭证项，或在包源凭证元素中追加新的凭证项
+func (e *ConfigEditor) setCredentialItem(sourceElem, itemElem *parser.ElementPosition, key, value string) error {
+	if itemElem == nil {
+		return e.insertChild(sourceElem, e.formatElement("add", attr{"key", key}, attr{"value", value}))
+	}
+
+	if itemElem.Attributes["key"] != key {
+		keyRange, exists := itemElem.AttrRanges["key"]
+		if !exists {
+			return fmt.Errorf("凭证项缺少key属性")
+		}
+		e.edits = append(e.edits, Edit{Range: keyRange, NewText: key, Type: "update"})
+	}
+
+	valueRange, exists := itemElem.AttrRanges["value"]
+	if !exists {
+		return fmt.Errorf("凭证项%s缺少value属性", key)
+	}
+	e.edits = append(e.edits, Edit{Range: valueRange, NewText: e.escapeValue(value), Type: "update"})
+	return nil
+}
+
+// buildCredentialXML 构建单个包源的凭证元素文本，后续行的缩进相对于凭证元素
+func (e *ConfigEditor) buildCredentialXML(sourceKey string, credentials []types.Credential, unit string) string {
+	var sb strings.Builder
+	name := types.EncodeElementName(sourceKey)
+	fmt.Fprintf(&sb, "<%s>", name)
+	for _, cred := range credentials {
+		fmt.Fprintf(&sb, "\n%s%s", unit, e.formatElement("add", attr{"key", cred.Key}, attr{"value", cred.Value}))
+	}
+	fmt.Fprintf(&sb, "\n</%s>", name)
+	return sb.String()
+}
+
+// upsertCredential 更新或追加凭证项
+func upsertCredential(creds []types.Credential, key, value string) []types.Credential {
+	for i, cred := range creds {
+		if cred.Key == key {
+			creds[i].Value = value
+			return creds
+		}
+	}
+	return append(creds, types.Credential{Key: key, Value: value})
+}
+
+// otherPasswordKey 返回另一种密码项的键名，两种密码项不会同时出现
+func otherPasswordKey(passwordKey string) string {
+	if passwordKey == "Password" {
+		return "ClearTextPassword"
+	}
+	return "Password"
+}
+
+// removeCredentialKey 移除指定键的凭证项
+func removeCredentialKey(creds []types.Credential, key string) []types.Credential {
+	for i, cred := range creds {
+		if cred.Key == key {
+			return append(creds[:i], creds[i+1:]...)
+		}
+	}
+	return creds
+}
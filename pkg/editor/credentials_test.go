@@ -0,0 +1,302 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+)
+
+const credentialConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+    <packageSources>
+        <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+        <add key="private" value="https://private.example.com/v3/index.json" />
+    </packageSources>
+    <!-- credentials -->
+    <packageSourceCredentials>
+        <private>
+            <add key="Username" value="alice" />
+            <add key="Password" value="ENCRYPTED" />
+        </private>
+    </packageSourceCredentials>
+</configuration>`
+
+// newTestEditor 解析内容并创建编辑器
+func newTestEditor(t *testing.T, content string) *ConfigEditor {
+	parseResult, err := parser.NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	return NewConfigEditor(parseResult)
+}
+
+// applyEdits 应用编辑并返回结果字符串
+func applyEdits(t *testing.T, editor *ConfigEditor) string {
+	modified, err := editor.ApplyEdits()
+	if err != nil {
+		t.Fatalf("应用编辑失败: %v", err)
+	}
+	return string(modified)
+}
+
+func TestAddCredentialToExistingSection(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.AddCredential("nuget.org", "bob", "p&ss"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+
+	expected := strings.Replace(credentialConfig, `        </private>
+`, `        </private>
+        <nuget.org>
+            <add key="Username" value="bob" />
+            <add key="ClearTextPassword" value="p&amp;ss" />
+        </nuget.org>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	cred := editor.GetConfig().PackageSourceCredentials.Sources["nuget.org"]
+	if len(cred.Add) != 2 || cred.Add[1].Value != "p&ss" {
+		t.Errorf("内存中的凭证未更新: %+v", cred)
+	}
+
+	if err := editor.AddCredential("private", "x", "y"); err == nil {
+		t.Error("重复添加凭证应返回错误")
+	}
+}
+
+func TestAddCredentialCreatesSection(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.AddCredential("nuget.org", "bob", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, `  </config>
+`, `  </config>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="bob" />
+      <add key="ClearTextPassword" value="secret" />
+    </nuget.org>
+  </packageSourceCredentials>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if editor.GetConfig().PackageSourceCredentials == nil {
+		t.Error("内存中的凭证节未创建")
+	}
+}
+
+func TestUpdateCredential(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.UpdateCredential("private", "carol", "new-secret"); err != nil {
+		t.Fatalf("更新凭证失败: %v", err)
+	}
+
+	expected := strings.Replace(credentialConfig,
+		`<add key="Username" value="alice" />
+            <add key="Password" value="ENCRYPTED" />`,
+		`<add key="Username" value="carol" />
+            <add key="ClearTextPassword" value="new-secret" />`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	cred := editor.GetConfig().PackageSourceCredentials.Sources["private"]
+	if len(cred.Add) != 2 || cred.Add[1].Key != "ClearTextPassword" {
+		t.Errorf("内存中的凭证未更新: %+v", cred)
+	}
+
+	if err := editor.UpdateCredential("missing", "a", "b"); err == nil {
+		t.Error("更新不存在的凭证应返回错误")
+	}
+}
+
+func TestEncryptedCredential(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.AddEncryptedCredential("nuget.org", "bob", "AQAAANCMnd8B"); err != nil {
+		t.Fatalf("添加加密凭证失败: %v", err)
+	}
+	if err := editor.UpdateEncryptedCredential("private", "carol", "AQAAANCMnd8C"); err != nil {
+		t.Fatalf("更新加密凭证失败: %v", err)
+	}
+
+	expected := strings.Replace(credentialConfig, `            <add key="Username" value="alice" />
+            <add key="Password" value="ENCRYPTED" />
+        </private>
+`, `            <add key="Username" value="carol" />
+            <add key="Password" value="AQAAANCMnd8C" />
+        </private>
+        <nuget.org>
+            <add key="Username" value="bob" />
+            <add key="Password" value="AQAAANCMnd8B" />
+        </nuget.org>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	// 明文密码更新为加密密码时替换原有的 ClearTextPassword 项
+	editor = newTestEditor(t, strings.Replace(credentialConfig, `key="Password" value="ENCRYPTED"`, `key="ClearTextPassword" value="plain"`, 1))
+	if err := editor.UpdateEncryptedCredential("private", "alice", "AQAAANCMnd8D"); err != nil {
+		t.Fatalf("更新加密凭证失败: %v", err)
+	}
+	if got := applyEdits(t, editor); !strings.Contains(got, `<add key="Password" value="AQAAANCMnd8D" />`) || strings.Contains(got, "plain") {
+		t.Errorf("ClearTextPassword 未被替换:\n%s", got)
+	}
+	cred := editor.GetConfig().PackageSourceCredentials.Sources["private"]
+	if len(cred.Add) != 2 || cred.Add[1].Key != "Password" || cred.Add[1].Value != "AQAAANCMnd8D" {
+		t.Errorf("内存中的凭证未更新: %+v", cred)
+	}
+}
+
+func TestRemoveCredential(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.RemoveCredential("private"); err != nil {
+		t.Fatalf("删除凭证失败: %v", err)
+	}
+
+	// 删除最后一个凭证时一并删除空的配置节，与 EnablePackageSource 一致
+	got := applyValidated(t, editor)
+	if strings.Contains(got, "<private>") || strings.Contains(got, "alice") || strings.Contains(got, "packageSourceCredentials") {
+		t.Errorf("修改后的内容中仍包含已删除的凭证:\n%s", got)
+	}
+	if !strings.Contains(got, `<add key="private"`) {
+		t.Errorf("删除凭证不应影响其余内容:\n%s", got)
+	}
+	if editor.GetConfig().PackageSourceCredentials != nil {
+		t.Error("内存中的凭证未删除")
+	}
+
+	if err := editor.RemoveCredential("missing"); err == nil {
+		t.Error("删除不存在的凭证应返回错误")
+	}
+}
+
+func TestRemoveCredentialKeepsOtherCredentials(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.AddCredential("nuget.org", "bob", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+	if err := editor.RemoveCredential("private"); err != nil {
+		t.Fatalf("删除凭证失败: %v", err)
+	}
+
+	got := applyValidated(t, editor)
+	if strings.Contains(got, "<private>") || !strings.Contains(got, "<!-- credentials -->") ||
+		strings.Count(got, "<packageSourceCredentials>") != 1 || !strings.Contains(got, "<nuget.org>") {
+		t.Errorf("修改后的内容不符合预期:\n%s", got)
+	}
+}
+
+func TestPendingCredentials(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.AddCredential("nuget.org", "alice", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+	if err := editor.AddCredential("local", "bob", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+	if err := editor.AddCredential("local", "bob", "secret"); err == nil {
+		t.Error("重复添加尚未应用的凭证应返回错误")
+	}
+	if err := editor.UpdateCredential("local", "carol", "secret2"); err != nil {
+		t.Fatalf("更新尚未应用的凭证失败: %v", err)
+	}
+
+	got := applyValidated(t, editor)
+	if strings.Count(got, "<packageSourceCredentials>") != 1 || !strings.Contains(got, `value="carol"`) || strings.Contains(got, `value="bob"`) {
+		t.Errorf("修改后的内容不符合预期:\n%s", got)
+	}
+
+	for _, key := range []string{"local", "nuget.org"} {
+		if err := editor.RemoveCredential(key); err != nil {
+			t.Fatalf("删除尚未应用的凭证失败: %v", err)
+		}
+	}
+	if got := applyValidated(t, editor); got != testConfig {
+		t.Errorf("撤销后的内容不符合预期:\n%s", got)
+	}
+	if editor.GetConfig().PackageSourceCredentials != nil {
+		t.Error("内存中的凭证未删除")
+	}
+
+	// 清除所有凭证时撤销尚未应用的添加
+	if err := editor.AddCredential("local", "bob", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+	editor.ClearAllCredentials()
+	if got := applyValidated(t, editor); got != testConfig {
+		t.Errorf("清除后的内容不符合预期:\n%s", got)
+	}
+}
+
+func TestClearAllCredentials(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+	editor.ClearAllCredentials()
+
+	result := applyEdits(t, editor)
+	if strings.Contains(result, "packageSourceCredentials") || strings.Contains(result, "alice") {
+		t.Errorf("凭证未被删除:\n%s", result)
+	}
+	if !strings.Contains(result, `<add key="private"`) {
+		t.Errorf("包源不应被删除:\n%s", result)
+	}
+	if editor.GetConfig().PackageSourceCredentials != nil {
+		t.Error("内存中的凭证未被清除")
+	}
+
+	// 重复清除不产生额外的编辑
+	editor.ClearAllCredentials()
+	if got := applyEdits(t, editor); got != result {
+		t.Errorf("重复清除后结果不同:\n%s", got)
+	}
+}
+
+func TestCredentialWithEncodedSourceName(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.AddCredential("My Feed", "bob", "secret"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+	got := applyEdits(t, editor)
+	if !strings.Contains(got, "<My_x0020_Feed>") || !strings.Contains(got, "</My_x0020_Feed>") {
+		t.Fatalf("包源名称中的空格应编码为 _x0020_:\n%s", got)
+	}
+
+	// 重新解析后可以按原始名称查找、更新和重命名
+	editor = newTestEditor(t, got)
+	if _, exists := editor.GetConfig().PackageSourceCredentials.Sources["My Feed"]; !exists {
+		t.Fatalf("解析后的凭证应使用解码后的包源名称: %v", editor.GetConfig().PackageSourceCredentials.Sources)
+	}
+	if err := editor.UpdateCredential("My Feed", "carol", "secret2"); err != nil {
+		t.Fatalf("更新凭证失败: %v", err)
+	}
+	got = applyEdits(t, editor)
+	if !strings.Contains(got, `<add key="Username" value="carol" />`) {
+		t.Errorf("凭证未更新:\n%s", got)
+	}
+
+	content := strings.Replace(got, `<add key="private" value="https://private.example.com/v3/index.json" />`,
+		`<add key="private" value="https://private.example.com/v3/index.json" />
+        <add key="My Feed" value="https://feed.example.com/v3/index.json" />`, 1)
+	editor = newTestEditor(t, content)
+	if err := editor.RenamePackageSource("My Feed", "Team: Feed"); err != nil {
+		t.Fatalf("重命名包源失败: %v", err)
+	}
+	got = applyEdits(t, editor)
+	if !strings.Contains(got, "<Team_x003A__x0020_Feed>") || !strings.Contains(got, "</Team_x003A__x0020_Feed>") {
+		t.Errorf("重命名后的凭证元素名应编码:\n%s", got)
+	}
+}
@@ -0,0 +1,185 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines 统一差异格式中每个变更块前后保留的上下文行数
+const diffContextLines = 3
+
+// diffOp 表示行级差异中的一个操作
+type diffOp struct {
+	kind byte // ' ' 表示未变，'-' 表示删除，'+' 表示新增
+	line string
+}
+
+// Diff 返回待应用编辑的统一差异格式（unified diff）预览
+//
+// 差异比较原始内容与应用所有编辑后的内容，不会修改编辑器状态，
+// 没有任何变化时返回空字符串。
+func (e *ConfigEditor) Diff() (string, error) {
+	original := string(e.parseResult.Content)
+
+	modified, err := e.ApplyEdits()
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff("original", "modified", original, string(modified)), nil
+}
+
+// unifiedDiff 生成两段文本的统一差异格式
+func unifiedDiff(fromLabel, toLabel, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	i := 0
+	for i < len(ops) {
+		// 查找下一个变更
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i >= len(ops) {
+			break
+		}
+
+		// 变更块起点包含前面的上下文
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// 向后扩展，直到连续未变的行超过两倍上下文
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContextLines {
+				end += min(run-end, diffContextLines)
+				break
+			}
+			end = run
+		}
+
+		writeHunk(&sb, ops, start, end)
+		i = end
+	}
+
+	return sb.String()
+}
+
+// writeHunk 输出 ops[start:end] 组成的变更块
+func writeHunk(sb *strings.Builder, ops []diffOp, start, end int) {
+	fromStart, toStart := 1, 1
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			fromStart++
+		}
+		if op.kind != '-' {
+			toStart++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			fromCount++
+		}
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	// 按统一差异格式约定，行数为 0 时起始行号为变更位置之前的行
+	if fromCount == 0 {
+		fromStart--
+	}
+	if toCount == 0 {
+		toStart--
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount))
+	for _, op := range ops[start:end] {
+		line := strings.TrimSuffix(op.line, "\n")
+		fmt.Fprintf(sb, "%c%s\n", op.kind, strings.TrimSuffix(line, "\r"))
+		if !strings.HasSuffix(op.line, "\n") {
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange 格式化变更块的行范围
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines 将文本拆分为保留换行符的行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines 基于最长公共子序列计算两组行之间的差异
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	diff, err := editor.Diff()
+	if err != nil {
+		t.Fatalf("生成差异失败: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("没有编辑时差异应为空，实际: %q", diff)
+	}
+
+	if err := editor.AddPackageSource("extra", "/tmp/packages", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	diff, err = editor.Diff()
+	if err != nil {
+		t.Fatalf("生成差异失败: %v", err)
+	}
+
+	if !strings.HasPrefix(diff, "--- original\n+++ modified\n@@ ") {
+		t.Errorf("差异缺少文件头或变更块:\n%s", diff)
+	}
+	if !strings.Contains(diff, "\n+    <add key=\"extra\" value=\"/tmp/packages\" />\n") {
+		t.Errorf("差异中缺少新增的包源:\n%s", diff)
+	}
+	if strings.Contains(diff, "\n-") {
+		t.Errorf("仅新增内容时不应有删除行:\n%s", diff)
+	}
+
+	// Diff 不应修改编辑器状态
+	if got := applyEdits(t, editor); !strings.Contains(got, `<add key="extra"`) {
+		t.Errorf("Diff 之后编辑应仍可应用:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\n"
+
+	expected := `--- x
++++ y
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,5 +8,5 @@
+ h
+ i
+ j
+-k
++K
+ l
+`
+	if got := unifiedDiff("x", "y", from, to); got != expected {
+		t.Errorf("差异不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	// 末尾缺少换行的行需要标注
+	got := unifiedDiff("x", "y", "a\nb", "a\nc")
+	if !strings.Contains(got, "-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n") {
+		t.Errorf("缺少末尾无换行标注:\n%s", got)
+	}
+}
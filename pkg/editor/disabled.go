@@ -0,0 +1,157 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const disabledPackageSourcesPath = "configuration/disabledPackageSources"
+
+// DisablePackageSource 禁用包源
+//
+// 在 <disabledPackageSources> 节中添加该包源，该节不存在时会按文件现有缩进创建。
+// 如果包源已在该节中但值不为 true，则原地将值更新为 true。是否已禁用以内存配置为准，
+// 包括尚未应用的编辑：重复禁用不做任何修改，禁用尚未应用的 EnablePackageSource
+// 删除的包源时撤销该删除。
+func (e *ConfigEditor) DisablePackageSource(sourceKey string) error {
+	value, disabled := e.disabledValue(sourceKey)
+	if disabled && value == "true" {
+		return nil
+	}
+
+	if entry, exists := e.findDisabledEntry(sourceKey); exists {
+		if !disabled && !e.restoreDisabledEntry(entry) {
+			return fmt.Errorf("无法恢复禁用包源项: %s", sourceKey)
+		}
+		if entry.Attributes["value"] != "true" {
+			valueRange, hasValue := entry.AttrRanges["value"]
+			if !hasValue {
+				return fmt.Errorf("禁用包源项缺少value属性: %s", sourceKey)
+			}
+			e.edits = append(e.edits, Edit{Range: valueRange, NewText: "true", Type: "update"})
+		}
+		e.setDisabledInConfig(sourceKey)
+		return nil
+	}
+
+	entryXML := e.formatElement("add", attr{"key", sourceKey}, attr{"value", "true"})
+	build := func(string) string { return entryXML }
+	if !disabled || !e.replacePending("disabledPackageSources", sourceKey, build) {
+		if err := e.addToSection("disabledPackageSources", sourceKey, build); err != nil {
+			return err
+		}
+	}
+
+	e.setDisabledInConfig(sourceKey)
+	return nil
+}
+
+// EnablePackageSource 启用包源
+//
+// 从 <disabledPackageSources> 节中删除该包源，如果删除后该节为空，则一并删除该节。
+// 禁用是由尚未应用的 DisablePackageSource 添加的时，撤销该添加。
+func (e *ConfigEditor) EnablePackageSource(sourceKey string) error {
+	if _, disabled := e.disabledValue(sourceKey); !disabled {
+		return fmt.Errorf("包源未被禁用: %s", sourceKey)
+	}
+
+	entry, inFile := e.findDisabledEntry(sourceKey)
+	if !inFile && !e.cancelPending("disabledPackageSources", sourceKey) {
+		return fmt.Errorf("未找到禁用包源项: %s", sourceKey)
+	}
+
+	// 同时更新内存中的配置对象，是否删除整个节以更新后的内存配置为准
+	disabled := e.parseResult.Config.DisabledPackageSources
+	for i, source := range disabled.Add {
+		if source.Key == sourceKey {
+			disabled.Add = append(disabled.Add[:i], disabled.Add[i+1:]...)
+			break
+		}
+	}
+
+	if len(disabled.Add) > 0 {
+		if inFile {
+			e.removeElement(entry)
+		}
+		return nil
+	}
+
+	if section, exists := e.findElement(disabledPackageSourcesPath); exists {
+		e.removeElement(section)
+	}
+	e.parseResult.Config.DisabledPackageSources = nil
+	return nil
+}
+
+// disabledValue 返回内存配置中包源的禁用值，第二个返回值表示包源是否在禁用列表中
+func (e *ConfigEditor) disabledValue(sourceKey string) (string, bool) {
+	disabled := e.parseResult.Config.DisabledPackageSources
+	if disabled == nil {
+		return "", false
+	}
+
+	for _, source := range disabled.Add {
+		if source.Key == sourceKey {
+			return source.Value, true
+		}
+	}
+	return "", false
+}
+
+// restoreDisabledEntry 撤销对文件中已有禁用项尚未应用的删除，返回是否找到了该删除
+//
+// 删除的是整个配置节时撤销配置节的删除，节中其他已从内存配置移除的项改为逐个删除。
+func (e *ConfigEditor) restoreDisabledEntry(entry *parser.ElementPosition) bool {
+	if e.cancelRemoval(entry) {
+		return true
+	}
+
+	section, exists := e.findElement(disabledPackageSourcesPath)
+	if !exists || !e.cancelRemoval(section) {
+		return false
+	}
+	for _, add := range e.childElements(section, "add") {
+		if add != entry {
+			e.removeElement(add)
+		}
+	}
+	return true
+}
+
+// findDisabledEntry 查找 <disabledPackageSources> 中指定包源的元素
+func (e *ConfigEditor) findDisabledEntry(sourceKey string) (*parser.ElementPosition, bool) {
+	section, exists := e.findElement(disabledPackageSourcesPath)
+	if !exists {
+		return nil, false
+	}
+
+	for _, add := range e.childElements(section, "add") {
+		if add.Attributes["key"] == sourceKey {
+			return add, true
+		}
+	}
+
+	return nil, false
+}
+
+// setDisabledInConfig 在内存配置中将包源标记为禁用
+func (e *ConfigEditor) setDisabledInConfig(sourceKey string) {
+	config := e.parseResult.Config
+	if config.DisabledPackageSources == nil {
+		config.DisabledPackageSources = &types.DisabledPackageSources{}
+	}
+
+	for i, source := range config.DisabledPackageSources.Add {
+		if source.Key == sourceKey {
+			config.DisabledPackageSources.Add[i].Value = "true"
+			return
+		}
+	}
+
+	config.DisabledPackageSources.Add = append(config.DisabledPackageSources.Add, types.DisabledSource{
+		Key:   sourceKey,
+		Value: "true",
+	})
+}
@@ -0,0 +1,197 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+const disabledConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="local" value="/packages" />
+    <add key="team" value="https://team.example.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <!-- temporarily disabled -->
+    <add key="local" value="true" />
+    <add key="team" value="false" />
+  </disabledPackageSources>
+</configuration>`
+
+func TestDisablePackageSourceCreatesSection(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.DisablePackageSource("local"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, `  </config>
+`, `  </config>
+  <disabledPackageSources>
+    <add key="local" value="true" />
+  </disabledPackageSources>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	disabled := editor.GetConfig().DisabledPackageSources
+	if disabled == nil || len(disabled.Add) != 1 || disabled.Add[0].Key != "local" {
+		t.Errorf("内存中的禁用包源未更新: %+v", disabled)
+	}
+}
+
+func TestDisablePackageSourceExistingSection(t *testing.T) {
+	editor := newTestEditor(t, disabledConfig)
+
+	if err := editor.DisablePackageSource("nuget.org"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.DisablePackageSource("team"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.DisablePackageSource("local"); err != nil {
+		t.Fatalf("重复禁用包源不应失败: %v", err)
+	}
+
+	expected := strings.Replace(disabledConfig, `    <add key="team" value="false" />
+`, `    <add key="team" value="true" />
+    <add key="nuget.org" value="true" />
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if len(editor.GetConfig().DisabledPackageSources.Add) != 3 {
+		t.Errorf("内存中的禁用包源数量不正确: %+v", editor.GetConfig().DisabledPackageSources.Add)
+	}
+}
+
+func TestEnablePackageSource(t *testing.T) {
+	editor := newTestEditor(t, disabledConfig)
+
+	if err := editor.EnablePackageSource("local"); err != nil {
+		t.Fatalf("启用包源失败: %v", err)
+	}
+
+	// 紧邻的注释随禁用项一起删除
+	expected := strings.Replace(disabledConfig, `
+    <!-- temporarily disabled -->
+    <add key="local" value="true" />`, "", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if err := editor.EnablePackageSource("nuget.org"); err == nil {
+		t.Error("启用未禁用的包源应返回错误")
+	}
+}
+
+func TestEnablePackageSourcePrunesSection(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="local" value="/packages" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="local" value="true" />
+  </disabledPackageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	if err := editor.EnablePackageSource("local"); err != nil {
+		t.Fatalf("启用包源失败: %v", err)
+	}
+
+	got := applyEdits(t, editor)
+	if strings.Contains(got, "disabledPackageSources") {
+		t.Errorf("空的disabledPackageSources节应被删除:\n%s", got)
+	}
+	if editor.GetConfig().DisabledPackageSources != nil {
+		t.Error("内存中的disabledPackageSources节应被删除")
+	}
+}
+
+func TestDisablePackageSourceIdempotent(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	for i := 0; i < 2; i++ {
+		if err := editor.DisablePackageSource("local"); err != nil {
+			t.Fatalf("禁用包源失败: %v", err)
+		}
+	}
+
+	got := applyValidated(t, editor)
+	if n := strings.Count(got, `<add key="local" value="true" />`); n != 1 {
+		t.Errorf("禁用项出现了 %d 次:\n%s", n, got)
+	}
+}
+
+func TestEnablePendingDisable(t *testing.T) {
+	// 节不存在时撤销新建的节
+	editor := newTestEditor(t, testConfig)
+	if err := editor.DisablePackageSource("local"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.EnablePackageSource("local"); err != nil {
+		t.Fatalf("启用尚未应用禁用的包源失败: %v", err)
+	}
+	if got := applyValidated(t, editor); got != testConfig {
+		t.Errorf("撤销后的内容不符合预期:\n%s", got)
+	}
+	if editor.GetConfig().DisabledPackageSources != nil {
+		t.Error("内存配置中不应保留空的禁用列表")
+	}
+	if err := editor.EnablePackageSource("local"); err == nil {
+		t.Error("启用未禁用的包源应该返回错误")
+	}
+
+	// 节已存在时只撤销新增的项，节中原有的项不受影响
+	editor = newTestEditor(t, disabledConfig)
+	if err := editor.DisablePackageSource("nuget.org"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.EnablePackageSource("nuget.org"); err != nil {
+		t.Fatalf("启用尚未应用禁用的包源失败: %v", err)
+	}
+	if got := applyValidated(t, editor); got != disabledConfig {
+		t.Errorf("撤销后的内容不符合预期:\n%s", got)
+	}
+}
+
+func TestEnableKeepsSectionWithPendingDisable(t *testing.T) {
+	editor := newTestEditor(t, disabledConfig)
+
+	if err := editor.DisablePackageSource("nuget.org"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	for _, key := range []string{"local", "team"} {
+		if err := editor.EnablePackageSource(key); err != nil {
+			t.Fatalf("启用包源失败: %v", err)
+		}
+	}
+
+	got := applyValidated(t, editor)
+	if !strings.Contains(got, `<add key="nuget.org" value="true" />`) || strings.Contains(got, `key="local" value="true"`) {
+		t.Errorf("修改后的内容不符合预期:\n%s", got)
+	}
+}
+
+func TestDisableAfterPendingEnable(t *testing.T) {
+	editor := newTestEditor(t, disabledConfig)
+
+	for _, key := range []string{"team", "local"} {
+		if err := editor.EnablePackageSource(key); err != nil {
+			t.Fatalf("启用包源失败: %v", err)
+		}
+	}
+	if err := editor.DisablePackageSource("local"); err != nil {
+		t.Fatalf("重新禁用包源失败: %v", err)
+	}
+
+	expected := strings.Replace(disabledConfig, "    <add key=\"team\" value=\"false\" />\n", "", 1)
+	if got := applyValidated(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
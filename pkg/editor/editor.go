@@ -8,12 +8,30 @@ import (
 
 	"github.com/scagogogo/nuget-config-parser/pkg/parser"
 	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
 )
 
 // ConfigEditor 基于Parser位置信息的配置编辑器
 type ConfigEditor struct {
-	parseResult *parser.ParseResult
-	edits       []Edit
+	// CleanRemoval 删除元素时是否一并删除其前导换行、缩进以及紧邻的注释，默认开启
+	CleanRemoval bool
+
+	// PreserveEncoding 写入文件时是否沿用原始文件的编码和BOM，默认开启，关闭时写入不带BOM的UTF-8
+	PreserveEncoding bool
+
+	// ElementStyle 生成新元素时使用的风格，为 nil 时沿用原始文件的风格
+	ElementStyle *parser.ElementStyle
+
+	parseResult   *parser.ParseResult
+	edits         []Edit
+	detectedStyle *xmlStyle
+
+	// original 解析时的配置对象副本，用于 Reset
+	original *types.NuGetConfig
+	// snapshots 进行中的编辑事务快照，最内层事务在末尾
+	snapshots []editSnapshot
+	// pendingSections 尚未应用的编辑向各配置节插入的子元素，键为配置节名称
+	pendingSections map[string]*pendingSection
 }
 
 // Edit 表示一个文本编辑操作
@@ -26,8 +44,11 @@ type Edit struct {
 // NewConfigEditor 创建基于Parser的配置编辑器
 func NewConfigEditor(parseResult *parser.ParseResult) *ConfigEditor {
 	return &ConfigEditor{
-		parseResult: parseResult,
-		edits:       make([]Edit, 0),
+		CleanRemoval:     true,
+		PreserveEncoding: true,
+		parseResult:      parseResult,
+		edits:            make([]Edit, 0),
+		original:         parseResult.Config.Clone(),
 	}
 }
 
@@ -43,35 +64,16 @@ func (e *ConfigEditor) GetPositions() map[string]*parser.ElementPosition {
 
 // AddPackageSource 添加新的包源
 func (e *ConfigEditor) AddPackageSource(key, value, protocolVersion string) error {
-	// 查找packageSources元素
-	packageSourcesPath := "configuration/packageSources"
-	elemPos, exists := e.parseResult.Positions[packageSourcesPath]
-	if !exists {
-		return fmt.Errorf("未找到packageSources元素")
-	}
-
-	// 构建新的包源XML
-	indent := "    " // 4个空格缩进
-	newSourceXML := fmt.Sprintf("\n%s<add key=\"%s\" value=\"%s\"", indent, key, value)
+	// 按文件现有风格构建新的包源XML，并插入到最后一个包源之后，packageSources 不存在时自动创建
+	attrs := []attr{{"key", key}, {"value", value}}
 	if protocolVersion != "" {
-		newSourceXML += fmt.Sprintf(" protocolVersion=\"%s\"", protocolVersion)
+		attrs = append(attrs, attr{"protocolVersion", protocolVersion})
 	}
-	newSourceXML += " />"
-
-	// 在packageSources结束标签前插入
-	insertPos := e.findInsertPositionBeforeEndTag(elemPos)
-
-	edit := Edit{
-		Range: parser.Range{
-			Start: insertPos,
-			End:   insertPos,
-		},
-		NewText: newSourceXML,
-		Type:    "add",
+	entryXML := e.formatElement("add", attrs...)
+	if err := e.addToSection("packageSources", key, func(string) string { return entryXML }); err != nil {
+		return err
 	}
 
-	e.edits = append(e.edits, edit)
-
 	// 同时更新内存中的配置对象
 	newSource := types.PackageSource{
 		Key:             key,
@@ -93,12 +95,7 @@ func (e *ConfigEditor) RemovePackageSource(sourceKey string) error {
 			elemPos.TagName == "add" {
 			if key, exists := elemPos.Attributes["key"]; exists && key == sourceKey {
 				// 删除整个元素
-				edit := Edit{
-					Range:   elemPos.Range,
-					NewText: "",
-					Type:    "delete",
-				}
-				e.edits = append(e.edits, edit)
+				e.removeElement(elemPos)
 
 				// 同时更新内存中的配置对象
 				e.removePackageSourceFromConfig(sourceKey)
@@ -107,6 +104,12 @@ func (e *ConfigEditor) RemovePackageSource(sourceKey string) error {
 		}
 	}
 
+	// 尚未应用的新增包源直接撤销其插入
+	if _, exists := e.findSourceInConfig(sourceKey); exists && e.cancelPending("packageSources", sourceKey) {
+		e.removePackageSourceFromConfig(sourceKey)
+		return nil
+	}
+
 	return fmt.Errorf("未找到包源: %s", sourceKey)
 }
 
@@ -132,13 +135,13 @@ func (e *ConfigEditor) updatePackageSourceAttribute(sourceKey, attrName, newValu
 				if attrRange, attrExists := elemPos.AttrRanges[attrName]; attrExists {
 					edit := Edit{
 						Range:   attrRange,
-						NewText: newValue,
+						NewText: e.escapeValue(newValue),
 						Type:    "update",
 					}
 					e.edits = append(e.edits, edit)
-				} else {
+				} else if err := e.addAttributeToElement(elemPos, attrName, newValue); err != nil {
 					// 属性不存在，需要添加
-					return e.addAttributeToElement(elemPos, attrName, newValue)
+					return err
 				}
 
 				// 更新内存中的配置对象
@@ -148,75 +151,215 @@ func (e *ConfigEditor) updatePackageSourceAttribute(sourceKey, attrName, newValu
 		}
 	}
 
+	// 尚未应用的新增包源重新生成其插入的文本
+	if source, exists := e.findSourceInConfig(sourceKey); exists {
+		switch attrName {
+		case "value":
+			source.Value = newValue
+		case "protocolVersion":
+			source.ProtocolVersion = newValue
+		}
+		if e.replacePending("packageSources", sourceKey, func(string) string { return e.formatSource(source) }) {
+			e.updatePackageSourceInConfig(sourceKey, attrName, newValue)
+			return nil
+		}
+	}
+
 	return fmt.Errorf("未找到包源: %s", sourceKey)
 }
 
 // ApplyEdits 应用所有编辑操作，返回修改后的内容
+//
+// 兼容的重叠编辑会被合并（例如先更新再删除同一包源时以删除为准），
+// 无法合并的冲突编辑返回 *EditConflictError，编辑队列保持不变。
 func (e *ConfigEditor) ApplyEdits() ([]byte, error) {
 	if len(e.edits) == 0 {
 		return e.parseResult.Content, nil
 	}
 
-	// 按位置倒序排序，从后往前应用编辑，避免位置偏移问题
-	sort.Slice(e.edits, func(i, j int) bool {
-		return e.edits[i].Range.Start.Offset > e.edits[j].Range.Start.Offset
-	})
+	// 检查冲突并按位置倒序排序，从后往前应用编辑，避免位置偏移问题
+	edits, err := resolveEdits(e.edits)
+	if err != nil {
+		return nil, err
+	}
 
+	content, err := e.applyEditList(edits)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(content), nil
+}
+
+// applyEditList 在原始内容上依次应用已按倒序排列的编辑
+func (e *ConfigEditor) applyEditList(edits []Edit) (string, error) {
 	content := string(e.parseResult.Content)
 
-	for _, edit := range e.edits {
+	for _, edit := range edits {
 		start := edit.Range.Start.Offset
 		end := edit.Range.End.Offset
 
 		if start < 0 || end > len(content) || start > end {
-			return nil, fmt.Errorf("无效的编辑范围: start=%d, end=%d, content_len=%d", start, end, len(content))
+			return "", fmt.Errorf("无效的编辑范围: start=%d, end=%d, content_len=%d", start, end, len(content))
 		}
 
 		// 应用编辑
 		content = content[:start] + edit.NewText + content[end:]
 	}
 
-	return []byte(content), nil
+	// 保留原始文件末尾的换行
+	if original := e.parseResult.Content; len(original) > 0 && original[len(original)-1] == '\n' &&
+		!strings.HasSuffix(content, "\n") {
+		content += e.style().eol
+	}
+
+	return content, nil
 }
 
-// findInsertPositionBeforeEndTag 查找在结束标签前的插入位置
-func (e *ConfigEditor) findInsertPositionBeforeEndTag(elemPos *parser.ElementPosition) parser.Position {
-	// 查找结束标签的位置
-	content := string(e.parseResult.Content)
+// ApplyEditsToFile 应用所有编辑操作，并以原子方式写入文件
+//
+// 编辑结果在写入前会经过 ApplyEditsValidated 的校验，写入时先写临时文件再重命名，
+// 并保留原文件的权限位。写入成功后编辑器基于新内容重新建立位置信息并清空已应用的编辑，
+// 进行中的事务随之结束，之后可以继续在同一个编辑器上进行编辑，GetConfig 返回的配置对象保持不变。
+func (e *ConfigEditor) ApplyEditsToFile(filePath string) error {
+	content, result, err := e.applyEditsValidated()
+	if err != nil {
+		return err
+	}
 
-	// 从元素开始位置向后查找结束标签
-	startOffset := elemPos.Range.Start.Offset
-	endOffset := elemPos.Range.End.Offset
-
-	// 在内容中查找 </tagName> 的位置
-	tagName := elemPos.TagName
-	endTag := fmt.Sprintf("</%s>", tagName)
-
-	// 从后往前查找结束标签
-	for i := endOffset - 1; i >= startOffset; i-- {
-		if i+len(endTag) <= len(content) && content[i:i+len(endTag)] == endTag {
-			// 找到结束标签，在其前面插入
-			return parser.Position{
-				Line:   elemPos.Range.End.Line,
-				Column: elemPos.Range.End.Column,
-				Offset: i,
-			}
-		}
+	encoding := parser.EncodingUTF8
+	if e.PreserveEncoding {
+		encoding = e.parseResult.Encoding
+	}
+	if err := utils.WriteFileAtomic(filePath, parser.EncodeContent(content, encoding)); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
 	}
+	result.Encoding = encoding
+
+	// 保持 GetConfig 返回的配置对象不变，调用方持有的引用在写入后仍然有效
+	*e.parseResult.Config = *result.Config
+	result.Config = e.parseResult.Config
+	e.parseResult = result
+	e.edits = make([]Edit, 0)
+	e.detectedStyle = nil
+	e.original = result.Config.Clone()
+	e.snapshots = nil
+	e.pendingSections = nil
+	return nil
+}
 
-	// 如果没找到结束标签，可能是自闭合标签，在结束位置前插入
-	return parser.Position{
-		Line:   elemPos.Range.End.Line,
-		Column: elemPos.Range.End.Column,
-		Offset: elemPos.Range.End.Offset - 1,
+// setAttribute 记录更新元素属性值的编辑，属性不存在时添加该属性
+func (e *ConfigEditor) setAttribute(elemPos *parser.ElementPosition, attrName, attrValue string) error {
+	attrRange, exists := elemPos.AttrRanges[attrName]
+	if !exists {
+		return e.addAttributeToElement(elemPos, attrName, attrValue)
 	}
+
+	e.edits = append(e.edits, Edit{Range: attrRange, NewText: e.escapeValue(attrValue), Type: "update"})
+	return nil
+}
+
+// removeAttribute 记录删除元素属性（包括属性名和前导空白）的编辑，属性不存在时不做任何操作
+func (e *ConfigEditor) removeAttribute(elemPos *parser.ElementPosition, attrName string) error {
+	valueRange, exists := elemPos.AttrRanges[attrName]
+	if !exists {
+		return nil
+	}
+
+	// 从属性值的开引号向前依次跳过等号、属性名和前导空白
+	content := e.parseResult.Content
+	i := valueRange.Start.Offset - 1
+	for i > 0 && isWhitespace(content[i-1]) {
+		i--
+	}
+	if i == 0 || content[i-1] != '=' {
+		return fmt.Errorf("无法定位属性%s的位置", attrName)
+	}
+	i--
+	for i > 0 && isWhitespace(content[i-1]) {
+		i--
+	}
+	if i < len(attrName) || string(content[i-len(attrName):i]) != attrName {
+		return fmt.Errorf("无法定位属性%s的位置", attrName)
+	}
+	i -= len(attrName)
+	for i > 0 && isWhitespace(content[i-1]) {
+		i--
+	}
+
+	e.edits = append(e.edits, Edit{
+		Range: parser.Range{
+			Start: parser.Position{Offset: i},
+			End:   parser.Position{Offset: valueRange.End.Offset + 1},
+		},
+		NewText: "",
+		Type:    "delete",
+	})
+	return nil
 }
 
 // addAttributeToElement 向元素添加新属性
+//
+// 新属性插入在开始标签最后一个属性之后、"/>" 或 ">" 之前。
+// 如果已有属性分行书写，新属性也另起一行并沿用最后一个属性的缩进。
 func (e *ConfigEditor) addAttributeToElement(elemPos *parser.ElementPosition, attrName, attrValue string) error {
-	// 这里需要更复杂的逻辑来在正确位置插入属性
-	// 简化实现：暂时返回错误
-	return fmt.Errorf("添加新属性功能尚未实现")
+	content := e.parseResult.Content
+	start := elemPos.Range.Start.Offset
+
+	// 查找开始标签的结束位置
+	tagEnd := -1
+	var quote byte
+	for i := start + 1; i < len(content); i++ {
+		c := content[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+		} else if c == '>' {
+			tagEnd = i
+			break
+		}
+	}
+	if tagEnd < 0 {
+		return fmt.Errorf("未找到%s元素开始标签的结束位置", elemPos.TagName)
+	}
+
+	closing := tagEnd
+	if content[tagEnd-1] == '/' {
+		closing = tagEnd - 1
+	}
+
+	// 跳过标签末尾的空白，紧跟在最后一个属性之后插入
+	insertOffset := closing
+	for insertOffset > start && isWhitespace(content[insertOffset-1]) {
+		insertOffset--
+	}
+
+	separator := " "
+	tagText := string(content[start:insertOffset])
+	if lastBreak := strings.LastIndex(tagText, "\n"); lastBreak >= 0 {
+		// 属性分行书写时，新属性沿用最后一行的缩进
+		separator = e.style().eol + e.lineIndent(insertOffset)
+	}
+
+	style := e.style()
+	newText := fmt.Sprintf("%s%s=%c%s%c", separator, attrName, style.quote, e.escapeValue(attrValue), style.quote)
+	if insertOffset == closing && content[closing] == '/' && style.selfClose == " />" {
+		// 原本没有空格分隔 "/>" 而文件整体风格带空格时补上空格
+		newText += " "
+	}
+
+	pos := parser.Position{Offset: insertOffset}
+	e.edits = append(e.edits, Edit{
+		Range:   parser.Range{Start: pos, End: pos},
+		NewText: newText,
+		Type:    "add",
+	})
+	return nil
 }
 
 // removePackageSourceFromConfig 从配置对象中移除包源
@@ -244,3 +387,310 @@ func (e *ConfigEditor) updatePackageSourceInConfig(sourceKey, attrName, newValue
 		}
 	}
 }
+
+// findElement 按路径查找元素位置
+func (e *ConfigEditor) findElement(path string) (*parser.ElementPosition, bool) {
+	elemPos, exists := e.parseResult.Positions[path]
+	return elemPos, exists
+}
+
+// childElements 返回位于父元素范围内、指定标签名的直接或间接子元素，按出现顺序排列
+func (e *ConfigEditor) childElements(parent *parser.ElementPosition, tagName string) []*parser.ElementPosition {
+	var children []*parser.ElementPosition
+	for _, elemPos := range e.parseResult.Positions {
+		if elemPos == parent || elemPos.TagName != tagName {
+			continue
+		}
+		if elemPos.Range.Start.Offset > parent.Range.Start.Offset &&
+			elemPos.Range.End.Offset <= parent.Range.End.Offset {
+			children = append(children, elemPos)
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Range.Start.Offset < children[j].Range.Start.Offset
+	})
+	return children
+}
+
+// lineIndent 返回指定偏移量所在行的前导空白
+func (e *ConfigEditor) lineIndent(offset int) string {
+	content := e.parseResult.Content
+	lineStart := offset
+	for lineStart > 0 && content[lineStart-1] != '\n' {
+		lineStart--
+	}
+
+	end := lineStart
+	for end < len(content) && (content[end] == ' ' || content[end] == '\t') {
+		end++
+	}
+	return string(content[lineStart:end])
+}
+
+// childIndent 推断父元素的子元素应使用的缩进
+func (e *ConfigEditor) childIndent(parent *parser.ElementPosition) string {
+	parentIndent := e.lineIndent(parent.Range.Start.Offset)
+
+	// 优先沿用已有子元素的缩进，后代元素中缩进最浅的即为直接子元素
+	best := ""
+	for _, elemPos := range e.parseResult.Positions {
+		if elemPos == parent {
+			continue
+		}
+		if elemPos.Range.Start.Offset > parent.Range.Start.Offset &&
+			elemPos.Range.End.Offset <= parent.Range.End.Offset {
+			indent := e.lineIndent(elemPos.Range.Start.Offset)
+			if len(indent) > len(parentIndent) && strings.HasPrefix(indent, parentIndent) &&
+				(best == "" || len(indent) < len(best)) {
+				best = indent
+			}
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	return parentIndent + e.style().indent
+}
+
+// indentUnit 推断文件使用的单级缩进，默认为两个空格
+func (e *ConfigEditor) indentUnit() string {
+	root, exists := e.findElement("configuration")
+	if !exists {
+		return "  "
+	}
+
+	rootIndent := e.lineIndent(root.Range.Start.Offset)
+	unit := ""
+	for path, elemPos := range e.parseResult.Positions {
+		if strings.Count(path, "/") != 1 {
+			continue
+		}
+		indent := e.lineIndent(elemPos.Range.Start.Offset)
+		if len(indent) > len(rootIndent) && strings.HasPrefix(indent, rootIndent) &&
+			(unit == "" || len(indent)-len(rootIndent) < len(unit)) {
+			unit = indent[len(rootIndent):]
+		}
+	}
+	if unit == "" {
+		return "  "
+	}
+
+	return unit
+}
+
+// findEndTagOffset 查找元素结束标签 </tagName> 的起始偏移量，自闭合元素返回 -1
+func (e *ConfigEditor) findEndTagOffset(elemPos *parser.ElementPosition) int {
+	if elemPos.SelfClose {
+		return -1
+	}
+
+	content := string(e.parseResult.Content)
+	endTag := fmt.Sprintf("</%s", elemPos.TagName)
+	start := elemPos.Range.Start.Offset
+	end := elemPos.Range.End.Offset
+	if end > len(content) {
+		end = len(content)
+	}
+
+	idx := strings.LastIndex(content[start:end], endTag)
+	if idx < 0 {
+		return -1
+	}
+	return start + idx
+}
+
+// insertChild 在父元素最后一个子节点之后插入新的子元素文本，使其独占一行
+//
+// childXML 不包含前导换行和缩进，多行文本中的后续行需自行包含相对缩进。
+// 如果父元素是自闭合的，会将其展开为成对标签。
+func (e *ConfigEditor) insertChild(parent *parser.ElementPosition, childXML string) error {
+	indent := e.childIndent(parent)
+	parentIndent := e.lineIndent(parent.Range.Start.Offset)
+
+	if parent.SelfClose {
+		// 将 <tag ... /> 展开为 <tag ...>...</tag>
+		content := string(e.parseResult.Content)
+		start := parent.Range.Start.Offset
+		end := parent.Range.End.Offset
+		openTag := strings.TrimRight(strings.TrimSuffix(strings.TrimSuffix(content[start:end], ">"), "/"), " \t")
+		closeTag := fmt.Sprintf("\n%s</%s>", parentIndent, parent.TagName)
+
+		// 同一元素已在展开时，把新的子元素追加到已有的展开文本中
+		for i, edit := range e.edits {
+			if edit.Type == "update" && edit.Range == parent.Range {
+				expanded := strings.TrimSuffix(edit.NewText, e.normalizeEOL(closeTag))
+				e.edits[i].NewText = expanded + e.normalizeEOL(fmt.Sprintf("\n%s%s%s", indent, childXML, closeTag))
+				return nil
+			}
+		}
+
+		newText := fmt.Sprintf("%s>\n%s%s%s", openTag, indent, childXML, closeTag)
+		e.edits = append(e.edits, Edit{
+			Range:   parent.Range,
+			NewText: e.normalizeEOL(newText),
+			Type:    "update",
+		})
+		return nil
+	}
+
+	endTagOffset := e.findEndTagOffset(parent)
+	if endTagOffset < 0 {
+		return fmt.Errorf("未找到%s元素的结束标签", parent.TagName)
+	}
+
+	// 跳过结束标签前的空白，插入到最后一个子节点之后
+	content := e.parseResult.Content
+	insertOffset := endTagOffset
+	for insertOffset > parent.Range.Start.Offset && isWhitespace(content[insertOffset-1]) {
+		insertOffset--
+	}
+
+	newText := fmt.Sprintf("\n%s%s", indent, childXML)
+	if insertOffset == endTagOffset || !strings.Contains(string(content[insertOffset:endTagOffset]), "\n") {
+		// 原本没有换行分隔时，补上结束标签前的换行和缩进
+		newText += "\n" + parentIndent
+		insertOffset = endTagOffset
+	}
+
+	pos := parser.Position{Offset: insertOffset}
+	e.edits = append(e.edits, Edit{
+		Range:   parser.Range{Start: pos, End: pos},
+		NewText: e.normalizeEOL(newText),
+		Type:    "add",
+	})
+	return nil
+}
+
+// isWhitespace 判断字节是否为XML空白字符
+func isWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// escapeAttr 转义XML属性值中的特殊字符
+func escapeAttr(value string) string {
+	return attrEscaper.Replace(value)
+}
+
+var attrEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+// removeElement 记录删除元素的编辑操作
+func (e *ConfigEditor) removeElement(elemPos *parser.ElementPosition) {
+	e.edits = append(e.edits, Edit{
+		Range:   e.removalRange(elemPos),
+		NewText: "",
+		Type:    "delete",
+	})
+}
+
+// cancelRemoval 撤销尚未应用的删除元素的编辑，返回是否找到了该编辑
+func (e *ConfigEditor) cancelRemoval(elemPos *parser.ElementPosition) bool {
+	r := e.removalRange(elemPos)
+	for i := len(e.edits) - 1; i >= 0; i-- {
+		if edit := e.edits[i]; edit.Type == "delete" && edit.Range == r && edit.NewText == "" {
+			e.edits = append(e.edits[:i], e.edits[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// removalRange 计算删除元素时实际要删除的范围
+//
+// 开启 CleanRemoval 且元素独占一行时，范围向前扩展到上一行行尾（包括换行符和缩进），
+// 向后扩展到本行行尾的空白；如果元素上一行是紧邻的独占一行的注释，则一并删除该注释。
+func (e *ConfigEditor) removalRange(elemPos *parser.ElementPosition) parser.Range {
+	r := elemPos.Range
+	if !e.CleanRemoval {
+		return r
+	}
+
+	content := e.parseResult.Content
+	start := r.Start.Offset
+	end := r.End.Offset
+
+	// 元素之后到行尾只能是空白
+	after := end
+	for after < len(content) && (content[after] == ' ' || content[after] == '\t') {
+		after++
+	}
+	lineBreakLen := 0
+	if after < len(content) {
+		switch {
+		case content[after] == '\n':
+			lineBreakLen = 1
+		case content[after] == '\r' && after+1 < len(content) && content[after+1] == '\n':
+			lineBreakLen = 2
+		default:
+			return r
+		}
+	}
+
+	// 元素之前到行首只能是空白
+	lineStart, ok := e.precedingLineBreak(start)
+	if !ok {
+		return r
+	}
+
+	// 紧邻的注释独占一行时一并删除
+	if commentStart, ok := e.attachedCommentStart(lineStart); ok {
+		lineStart, _ = e.precedingLineBreak(commentStart)
+	}
+
+	r.Start = parser.Position{Offset: lineStart}
+	r.End = parser.Position{Offset: after}
+	if lineStart == 0 {
+		// 文件开头没有可删除的换行，改为删除行尾换行
+		r.End.Offset = after + lineBreakLen
+	}
+	return r
+}
+
+// attachedCommentStart 如果换行符 lineBreak 所在行之上是紧邻的独占一行的注释，返回该注释的起始偏移量
+func (e *ConfigEditor) attachedCommentStart(lineBreak int) (int, bool) {
+	content := e.parseResult.Content
+	before := lineBreak
+	for before > 0 && (content[before-1] == ' ' || content[before-1] == '\t' || content[before-1] == '\r') {
+		before--
+	}
+	if before < 3 || string(content[before-3:before]) != "-->" {
+		return 0, false
+	}
+
+	commentStart := strings.LastIndex(string(content[:before]), "<!--")
+	if commentStart < 0 {
+		return 0, false
+	}
+	if _, ok := e.precedingLineBreak(commentStart); !ok {
+		return 0, false
+	}
+	return commentStart, true
+}
+
+// precedingLineBreak 如果偏移量之前到行首只有空白，返回该行之前换行符（包括 \r\n 中的 \r）的偏移量
+func (e *ConfigEditor) precedingLineBreak(offset int) (int, bool) {
+	content := e.parseResult.Content
+	i := offset
+	for i > 0 && (content[i-1] == ' ' || content[i-1] == '\t') {
+		i--
+	}
+
+	if i == 0 {
+		return 0, true
+	}
+	if content[i-1] != '\n' {
+		return 0, false
+	}
+
+	i--
+	if i > 0 && content[i-1] == '\r' {
+		i--
+	}
+	return i, true
+}
@@ -1,6 +1,9 @@
 package editor
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -272,3 +275,261 @@ func TestMultipleEdits(t *testing.T) {
 		t.Error("修改后的内容中仍包含已删除的包源")
 	}
 }
+
+func TestUpdatePackageSourceVersionAddsAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		expected string
+	}{
+		{
+			name:     "自闭合标签",
+			original: `<add key="local" value="C:\LocalPackages" />`,
+			expected: `<add key="local" value="C:\LocalPackages" protocolVersion="3" />`,
+		},
+		{
+			name:     "自闭合标签无空格",
+			original: `<add key="local" value="C:\LocalPackages"/>`,
+			expected: `<add key="local" value="C:\LocalPackages" protocolVersion="3" />`,
+		},
+		{
+			name:     "成对标签",
+			original: `<add key="local" value="C:\LocalPackages"></add>`,
+			expected: `<add key="local" value="C:\LocalPackages" protocolVersion="3"></add>`,
+		},
+		{
+			name: "属性分行书写",
+			original: `<add key="local"
+         value="C:\LocalPackages" />`,
+			expected: `<add key="local"
+         value="C:\LocalPackages"
+         protocolVersion="3" />`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := strings.Replace(testConfig, `<add key="local" value="C:\LocalPackages" />`, tt.original, 1)
+			parseResult, err := parser.NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+			if err != nil {
+				t.Fatalf("解析配置失败: %v", err)
+			}
+
+			editor := NewConfigEditor(parseResult)
+			if err := editor.UpdatePackageSourceVersion("local", "3"); err != nil {
+				t.Fatalf("更新协议版本失败: %v", err)
+			}
+
+			modifiedContent, err := editor.ApplyEdits()
+			if err != nil {
+				t.Fatalf("应用编辑失败: %v", err)
+			}
+
+			expected := strings.Replace(content, tt.original, tt.expected, 1)
+			if string(modifiedContent) != expected {
+				t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", modifiedContent, expected)
+			}
+
+			for _, source := range editor.GetConfig().PackageSources.Add {
+				if source.Key == "local" && source.ProtocolVersion != "3" {
+					t.Errorf("内存中的协议版本未更新: %q", source.ProtocolVersion)
+				}
+			}
+		})
+	}
+}
+
+func TestRemovePackageSourceCleanRemoval(t *testing.T) {
+	content := "<?xml version=\"1.0\" encoding=\"utf-8\"?>\r\n" +
+		"<configuration>\r\n" +
+		"  <packageSources>\r\n" +
+		"    <add key=\"nuget.org\" value=\"https://api.nuget.org/v3/index.json\" />\r\n" +
+		"\r\n" +
+		"    <!-- local feed -->\r\n" +
+		"    <add key=\"local\" value=\"C:\\LocalPackages\" />  \r\n" +
+		"  </packageSources>\r\n" +
+		"</configuration>"
+
+	tests := []struct {
+		name         string
+		cleanRemoval bool
+		expected     string
+	}{
+		{
+			name:         "默认清理空白和注释",
+			cleanRemoval: true,
+			expected: "<?xml version=\"1.0\" encoding=\"utf-8\"?>\r\n" +
+				"<configuration>\r\n" +
+				"  <packageSources>\r\n" +
+				"    <add key=\"nuget.org\" value=\"https://api.nuget.org/v3/index.json\" />\r\n" +
+				"\r\n" +
+				"  </packageSources>\r\n" +
+				"</configuration>",
+		},
+		{
+			name:         "关闭清理时只删除元素",
+			cleanRemoval: false,
+			expected: "<?xml version=\"1.0\" encoding=\"utf-8\"?>\r\n" +
+				"<configuration>\r\n" +
+				"  <packageSources>\r\n" +
+				"    <add key=\"nuget.org\" value=\"https://api.nuget.org/v3/index.json\" />\r\n" +
+				"\r\n" +
+				"    <!-- local feed -->\r\n" +
+				"      \r\n" +
+				"  </packageSources>\r\n" +
+				"</configuration>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parseResult, err := parser.NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+			if err != nil {
+				t.Fatalf("解析配置失败: %v", err)
+			}
+
+			editor := NewConfigEditor(parseResult)
+			editor.CleanRemoval = tt.cleanRemoval
+			if err := editor.RemovePackageSource("local"); err != nil {
+				t.Fatalf("删除包源失败: %v", err)
+			}
+
+			modifiedContent, err := editor.ApplyEdits()
+			if err != nil {
+				t.Fatalf("应用编辑失败: %v", err)
+			}
+
+			if string(modifiedContent) != tt.expected {
+				t.Errorf("修改后的内容不符合预期:\n%q\n期望:\n%q", modifiedContent, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyEditsToFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "nuget-editor-test-*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	if err := os.WriteFile(configPath, []byte(testConfig), 0600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	parseResult, err := parser.NewPositionAwareParser().ParseFromFileWithPositions(configPath)
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+
+	editor := NewConfigEditor(parseResult)
+	if err := editor.AddPackageSource("first", "https://first.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.ApplyEditsToFile(configPath); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	// 写入后可以继续编辑
+	if err := editor.RemovePackageSource("local"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+	if err := editor.ApplyEditsToFile(configPath); err != nil {
+		t.Fatalf("再次写入文件失败: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("读取配置文件失败: %v", err)
+	}
+	expected := strings.Replace(testConfig, `    <add key="local" value="C:\LocalPackages" />`,
+		`    <add key="first" value="https://first.example.com" />`, 1)
+	if string(content) != expected {
+		t.Errorf("文件内容不符合预期:\n%s\n期望:\n%s", content, expected)
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatalf("获取文件信息失败: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("文件权限为%v，期望0600", info.Mode().Perm())
+	}
+
+	// 无效的编辑结果不会写入文件
+	editor.edits = append(editor.edits, Edit{
+		Range:   parser.Range{Start: parser.Position{Offset: 0}, End: parser.Position{Offset: 10}},
+		NewText: "<broken",
+		Type:    "update",
+	})
+	if err := editor.ApplyEditsToFile(configPath); err == nil {
+		t.Error("无效的编辑结果应返回错误")
+	}
+	unchanged, _ := os.ReadFile(configPath)
+	if string(unchanged) != expected {
+		t.Error("编辑结果无效时不应修改文件")
+	}
+}
+
+func TestApplyEditsToFilePreservesEncoding(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "nuget-editor-test-*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	content := "<?xml version=\"1.0\" encoding=\"utf-16\"?>\r\n" +
+		"<configuration>\r\n" +
+		"  <packageSources>\r\n" +
+		"    <add key=\"nuget.org\" value=\"https://api.nuget.org/v3/index.json\" />\r\n" +
+		"  </packageSources>\r\n" +
+		"</configuration>\r\n"
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	if err := os.WriteFile(configPath, parser.EncodeContent([]byte(content), parser.EncodingUTF16LE), 0600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	parseResult, err := parser.NewPositionAwareParser().ParseFromFileWithPositions(configPath)
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+
+	editor := NewConfigEditor(parseResult)
+	if err := editor.AddPackageSource("本地", `C:\包`, ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.ApplyEditsToFile(configPath); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	written, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("读取配置文件失败: %v", err)
+	}
+	if parser.DetectEncoding(written) != parser.EncodingUTF16LE {
+		t.Fatalf("写入的文件应保持UTF-16LE编码")
+	}
+	decoded, _, err := parser.DecodeContent(written)
+	if err != nil {
+		t.Fatalf("解码文件失败: %v", err)
+	}
+	expected := strings.Replace(content, "  </packageSources>\r\n",
+		"    <add key=\"本地\" value=\"C:\\包\" />\r\n  </packageSources>\r\n", 1)
+	if string(decoded) != expected {
+		t.Errorf("文件内容不符合预期:\n%q\n期望:\n%q", decoded, expected)
+	}
+
+	// 关闭 PreserveEncoding 后写入不带BOM的UTF-8
+	editor.PreserveEncoding = false
+	if err := editor.RemovePackageSource("本地"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+	if err := editor.ApplyEditsToFile(configPath); err != nil {
+		t.Fatalf("再次写入文件失败: %v", err)
+	}
+	written, _ = os.ReadFile(configPath)
+	if string(written) != strings.Replace(content, "utf-16", "utf-8", 1) {
+		t.Errorf("文件内容不符合预期:\n%q", written)
+	}
+}
@@ -0,0 +1,295 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const packageSourceMappingPath = "configuration/packageSourceMapping"
+
+// AddPackageSourceMapping 为包源添加包ID模式映射
+//
+// 包源已有映射时只追加尚不存在的模式，否则在 <packageSourceMapping> 中新建
+// <packageSource> 分组，该节不存在时会按文件现有缩进创建。分组由尚未应用的编辑新建时，
+// 模式合并到该分组中，不会重复新建。
+func (e *ConfigEditor) AddPackageSourceMapping(sourceKey string, patterns ...string) error {
+	if len(patterns) == 0 {
+		return fmt.Errorf("至少需要一个包ID模式")
+	}
+
+	if group, exists := e.findMappingGroup(sourceKey); exists {
+		existing := make(map[string]bool)
+		for _, pkg := range e.childElements(group, "package") {
+			existing[pkg.Attributes["pattern"]] = true
+		}
+
+		for _, pattern := range patterns {
+			if existing[pattern] {
+				continue
+			}
+			existing[pattern] = true
+			if err := e.insertChild(group, e.formatElement("package", attr{"pattern", pattern})); err != nil {
+				return err
+			}
+			e.addPatternInConfig(sourceKey, pattern)
+		}
+		return nil
+	}
+
+	// 分组由尚未应用的编辑新建时，合并模式后重新生成该分组
+	if current, exists := e.mappingPatterns(sourceKey); exists {
+		merged := current
+		for _, pattern := range patterns {
+			if !containsString(merged, pattern) {
+				merged = append(merged, pattern)
+			}
+		}
+		build := func(unit string) string { return e.buildMappingGroupXML(sourceKey, merged, unit) }
+		if e.replacePending("packageSourceMapping", sourceKey, build) {
+			for _, pattern := range merged[len(current):] {
+				e.addPatternInConfig(sourceKey, pattern)
+			}
+			return nil
+		}
+	}
+
+	err := e.addToSection("packageSourceMapping", sourceKey, func(unit string) string {
+		return e.buildMappingGroupXML(sourceKey, patterns, unit)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pattern := range patterns {
+		e.addPatternInConfig(sourceKey, pattern)
+	}
+	return nil
+}
+
+// RemovePackageSourceMapping 删除包源的整个映射分组
+//
+// 删除后 <packageSourceMapping> 中没有其他分组时，一并删除该节。
+func (e *ConfigEditor) RemovePackageSourceMapping(sourceKey string) error {
+	if group, exists := e.findMappingGroup(sourceKey); exists {
+		e.removeMappingGroup(group)
+		return nil
+	}
+
+	if _, exists := e.mappingPatterns(sourceKey); exists && e.cancelPending("packageSourceMapping", sourceKey) {
+		e.removePendingMappingGroup(sourceKey)
+		return nil
+	}
+
+	return fmt.Errorf("未找到包源映射: %s", sourceKey)
+}
+
+// RemovePackagePattern 从包源映射中删除单个包ID模式
+//
+// 删除的是分组中最后一个模式时，一并删除该分组。
+func (e *ConfigEditor) RemovePackagePattern(sourceKey, pattern string) error {
+	group, exists := e.findMappingGroup(sourceKey)
+	if !exists {
+		return e.removePendingPattern(sourceKey, pattern)
+	}
+
+	packages := e.childElements(group, "package")
+	for _, pkg := range packages {
+		if pkg.Attributes["pattern"] != pattern {
+			continue
+		}
+
+		if len(packages) == 1 {
+			e.removeMappingGroup(group)
+			return nil
+		}
+
+		e.removeElement(pkg)
+		if mapping := e.parseResult.Config.PackageSourceMapping; mapping != nil {
+			for i, source := range mapping.PackageSource {
+				if source.Key != sourceKey {
+					continue
+				}
+				for j, p := range source.Package {
+					if p.Pattern == pattern {
+						mapping.PackageSource[i].Package = append(source.Package[:j], source.Package[j+1:]...)
+						break
+					}
+				}
+				break
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("包源%s的映射中未找到模式: %s", sourceKey, pattern)
+}
+
+// findMappingGroup 查找 <packageSourceMapping> 中指定包源的分组元素
+func (e *ConfigEditor) findMappingGroup(sourceKey string) (*parser.ElementPosition, bool) {
+	section, exists := e.findElement(packageSourceMappingPath)
+	if !exists {
+		return nil, false
+	}
+
+	for _, group := range e.childElements(section, "packageSource") {
+		if group.Attributes["key"] == sourceKey {
+			return group, true
+		}
+	}
+
+	return nil, false
+}
+
+// removeMappingGroup 删除映射分组，是最后一个分组时删除整个节
+//
+// 是否为最后一个分组以内存配置为准，之前已删除但尚未应用的分组不计算在内。
+func (e *ConfigEditor) removeMappingGroup(group *parser.ElementPosition) {
+	sourceKey := group.Attributes["key"]
+	mapping := e.parseResult.Config.PackageSourceMapping
+
+	remaining := 0
+	if mapping != nil {
+		for i := 0; i < len(mapping.PackageSource); i++ {
+			if mapping.PackageSource[i].Key == sourceKey {
+				mapping.PackageSource = append(mapping.PackageSource[:i], mapping.PackageSource[i+1:]...)
+				i--
+			}
+		}
+		remaining = len(mapping.PackageSource)
+	}
+
+	if remaining > 0 {
+		e.removeElement(group)
+		return
+	}
+
+	section, _ := e.findElement(packageSourceMappingPath)
+	e.removeElement(section)
+	e.parseResult.Config.PackageSourceMapping = nil
+}
+
+// removePendingPattern 从尚未应用的映射分组中删除模式，删除最后一个模式时撤销整个分组
+func (e *ConfigEditor) removePendingPattern(sourceKey, pattern string) error {
+	current, exists := e.mappingPatterns(sourceKey)
+	if !exists {
+		return fmt.Errorf("未找到包源映射: %s", sourceKey)
+	}
+	if !containsString(current, pattern) {
+		return fmt.Errorf("包源%s的映射中未找到模式: %s", sourceKey, pattern)
+	}
+
+	if len(current) == 1 {
+		if !e.cancelPending("packageSourceMapping", sourceKey) {
+			return fmt.Errorf("未找到包源映射: %s", sourceKey)
+		}
+		e.removePendingMappingGroup(sourceKey)
+		return nil
+	}
+
+	var remaining []string
+	for _, p := range current {
+		if p != pattern {
+			remaining = append(remaining, p)
+		}
+	}
+	build := func(unit string) string { return e.buildMappingGroupXML(sourceKey, remaining, unit) }
+	if !e.replacePending("packageSourceMapping", sourceKey, build) {
+		return fmt.Errorf("未找到包源映射: %s", sourceKey)
+	}
+
+	mapping := e.parseResult.Config.PackageSourceMapping
+	for i, source := range mapping.PackageSource {
+		if source.Key == sourceKey {
+			patterns := make([]types.PackagePattern, 0, len(remaining))
+			for _, p := range remaining {
+				patterns = append(patterns, types.PackagePattern{Pattern: p})
+			}
+			mapping.PackageSource[i].Package = patterns
+			break
+		}
+	}
+	return nil
+}
+
+// removePendingMappingGroup 在撤销尚未应用的分组后更新内存配置，没有其他分组时一并删除已有的配置节
+func (e *ConfigEditor) removePendingMappingGroup(sourceKey string) {
+	mapping := e.parseResult.Config.PackageSourceMapping
+	for i := 0; i < len(mapping.PackageSource); i++ {
+		if mapping.PackageSource[i].Key == sourceKey {
+			mapping.PackageSource = append(mapping.PackageSource[:i], mapping.PackageSource[i+1:]...)
+			i--
+		}
+	}
+	if len(mapping.PackageSource) > 0 {
+		return
+	}
+
+	if section, exists := e.findElement(packageSourceMappingPath); exists {
+		e.removeElement(section)
+	}
+	e.parseResult.Config.PackageSourceMapping = nil
+}
+
+// mappingPatterns 返回内存配置中包源映射的模式
+func (e *ConfigEditor) mappingPatterns(sourceKey string) ([]string, bool) {
+	mapping := e.parseResult.Config.PackageSourceMapping
+	if mapping == nil {
+		return nil, false
+	}
+
+	for _, source := range mapping.PackageSource {
+		if source.Key == sourceKey {
+			patterns := make([]string, 0, len(source.Package))
+			for _, p := range source.Package {
+				patterns = append(patterns, p.Pattern)
+			}
+			return patterns, true
+		}
+	}
+	return nil, false
+}
+
+// buildMappingGroupXML 构建单个包源的映射分组文本，后续行的缩进相对于分组元素
+func (e *ConfigEditor) buildMappingGroupXML(sourceKey string, patterns []string, unit string) string {
+	var sb strings.Builder
+	sb.WriteString(e.formatStartTag("packageSource", attr{"key", sourceKey}))
+	for _, pattern := range patterns {
+		fmt.Fprintf(&sb, "\n%s%s", unit, e.formatElement("package", attr{"pattern", pattern}))
+	}
+	sb.WriteString("\n</packageSource>")
+	return sb.String()
+}
+
+// addPatternInConfig 在内存配置中为包源追加模式
+func (e *ConfigEditor) addPatternInConfig(sourceKey, pattern string) {
+	config := e.parseResult.Config
+	if config.PackageSourceMapping == nil {
+		config.PackageSourceMapping = &types.PackageSourceMapping{}
+	}
+	mapping := config.PackageSourceMapping
+
+	for i, source := range mapping.PackageSource {
+		if source.Key == sourceKey {
+			mapping.PackageSource[i].Package = append(source.Package, types.PackagePattern{Pattern: pattern})
+			return
+		}
+	}
+
+	mapping.PackageSource = append(mapping.PackageSource, types.PackageSourceMappingSource{
+		Key:     sourceKey,
+		Package: []types.PackagePattern{{Pattern: pattern}},
+	})
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+const mappingConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="contoso" value="https://contoso.com/packages/" />
+  </packageSources>
+  <packageSourceMapping>
+    <packageSource key="nuget.org">
+      <package pattern="*" />
+    </packageSource>
+    <packageSource key="contoso">
+      <package pattern="Contoso.*" />
+      <package pattern="NuGet.Common" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+func TestAddPackageSourceMapping(t *testing.T) {
+	editor := newTestEditor(t, mappingConfig)
+
+	if err := editor.AddPackageSourceMapping("contoso", "Contoso.*", "Fabrikam.*"); err != nil {
+		t.Fatalf("添加包源映射失败: %v", err)
+	}
+
+	expected := strings.Replace(mappingConfig, `      <package pattern="NuGet.Common" />
+`, `      <package pattern="NuGet.Common" />
+      <package pattern="Fabrikam.*" />
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	patterns := editor.GetConfig().PackageSourceMapping.PackageSource[1].Package
+	if len(patterns) != 3 || patterns[2].Pattern != "Fabrikam.*" {
+		t.Errorf("内存中的包源映射未更新: %+v", patterns)
+	}
+}
+
+func TestAddPackageSourceMappingCreatesSection(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.AddPackageSourceMapping("local", "Local.*", "Tools.*"); err != nil {
+		t.Fatalf("添加包源映射失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, `  </config>
+`, `  </config>
+  <packageSourceMapping>
+    <packageSource key="local">
+      <package pattern="Local.*" />
+      <package pattern="Tools.*" />
+    </packageSource>
+  </packageSourceMapping>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if editor.GetConfig().PackageSourceMapping == nil {
+		t.Error("内存中的包源映射节未创建")
+	}
+}
+
+func TestRemovePackagePattern(t *testing.T) {
+	editor := newTestEditor(t, mappingConfig)
+
+	if err := editor.RemovePackagePattern("contoso", "NuGet.Common"); err != nil {
+		t.Fatalf("删除包ID模式失败: %v", err)
+	}
+	expected := strings.Replace(mappingConfig, "\n      <package pattern=\"NuGet.Common\" />", "", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	// 删除分组中最后一个模式时删除整个分组
+	if err := editor.RemovePackagePattern("nuget.org", "*"); err != nil {
+		t.Fatalf("删除包ID模式失败: %v", err)
+	}
+	got := applyEdits(t, editor)
+	if strings.Contains(got, `<packageSource key="nuget.org">`) {
+		t.Errorf("分组应被删除:\n%s", got)
+	}
+	if len(editor.GetConfig().PackageSourceMapping.PackageSource) != 1 {
+		t.Error("内存中的分组未删除")
+	}
+
+	if err := editor.RemovePackagePattern("contoso", "missing"); err == nil {
+		t.Error("删除不存在的模式应返回错误")
+	}
+}
+
+func TestRemovePackageSourceMapping(t *testing.T) {
+	editor := newTestEditor(t, mappingConfig)
+
+	if err := editor.RemovePackageSourceMapping("nuget.org"); err != nil {
+		t.Fatalf("删除包源映射失败: %v", err)
+	}
+	got := applyEdits(t, editor)
+	if strings.Contains(got, `key="nuget.org">`) || !strings.Contains(got, `<packageSource key="contoso">`) {
+		t.Errorf("只应删除 nuget.org 的分组:\n%s", got)
+	}
+
+	// 删除最后一个分组时删除整个节
+	if err := editor.RemovePackageSourceMapping("contoso"); err != nil {
+		t.Fatalf("删除包源映射失败: %v", err)
+	}
+	expected := strings.Replace(mappingConfig, mappingConfig[strings.Index(mappingConfig, "\n  <packageSourceMapping>"):strings.Index(mappingConfig, "\n</configuration>")], "", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+	if editor.GetConfig().PackageSourceMapping != nil {
+		t.Error("内存中的包源映射节未删除")
+	}
+
+	if err := editor.RemovePackageSourceMapping("missing"); err == nil {
+		t.Error("删除不存在的映射应返回错误")
+	}
+}
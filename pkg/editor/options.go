@@ -0,0 +1,94 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const configSectionPath = "configuration/config"
+
+// SetConfigOption 设置 <config> 节中的配置选项
+//
+// 选项已存在时原地更新其值，否则追加到 <config> 节中，该节不存在时会按文件现有缩进创建。
+// 同一选项多次设置时，尚未应用的添加会被更新为新的值，而不是重复添加。
+func (e *ConfigEditor) SetConfigOption(key, value string) error {
+	if entry, exists := e.findConfigOption(key); exists {
+		valueRange, hasValue := entry.AttrRanges["value"]
+		if !hasValue {
+			return fmt.Errorf("配置选项缺少value属性: %s", key)
+		}
+		e.edits = append(e.edits, Edit{Range: valueRange, NewText: e.escapeValue(value), Type: "update"})
+		e.setConfigOptionInConfig(key, value)
+		return nil
+	}
+
+	entryXML := e.formatElement("add", attr{"key", key}, attr{"value", value})
+	if e.replacePending("config", key, func(string) string { return entryXML }) {
+		e.setConfigOptionInConfig(key, value)
+		return nil
+	}
+	if err := e.addToSection("config", key, func(string) string { return entryXML }); err != nil {
+		return err
+	}
+
+	e.setConfigOptionInConfig(key, value)
+	return nil
+}
+
+// RemoveConfigOption 删除 <config> 节中的配置选项
+//
+// 选项是由尚未应用的编辑添加的时，撤销该添加。
+func (e *ConfigEditor) RemoveConfigOption(key string) error {
+	if entry, exists := e.findConfigOption(key); exists {
+		e.removeElement(entry)
+	} else if !e.cancelPending("config", key) {
+		return fmt.Errorf("未找到配置选项: %s", key)
+	}
+
+	// 同时更新内存中的配置对象
+	if config := e.parseResult.Config.Config; config != nil {
+		for i, option := range config.Add {
+			if option.Key == key {
+				config.Add = append(config.Add[:i], config.Add[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// findConfigOption 查找 <config> 节中指定键的元素
+func (e *ConfigEditor) findConfigOption(key string) (*parser.ElementPosition, bool) {
+	section, exists := e.findElement(configSectionPath)
+	if !exists {
+		return nil, false
+	}
+
+	for _, add := range e.childElements(section, "add") {
+		if add.Attributes["key"] == key {
+			return add, true
+		}
+	}
+
+	return nil, false
+}
+
+// setConfigOptionInConfig 在内存配置中设置配置选项
+func (e *ConfigEditor) setConfigOptionInConfig(key, value string) {
+	config := e.parseResult.Config
+	if config.Config == nil {
+		config.Config = &types.Config{}
+	}
+
+	for i, option := range config.Config.Add {
+		if option.Key == key {
+			config.Config.Add[i].Value = value
+			return
+		}
+	}
+
+	config.Config.Add = append(config.Config.Add, types.ConfigOption{Key: key, Value: value})
+}
@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetConfigOption(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.SetConfigOption("globalPackagesFolder", "D:\\packages"); err != nil {
+		t.Fatalf("设置配置选项失败: %v", err)
+	}
+	if err := editor.SetConfigOption("http_proxy", "http://proxy:8080"); err != nil {
+		t.Fatalf("设置配置选项失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, `    <add key="globalPackagesFolder" value="C:\packages" />
+`, `    <add key="globalPackagesFolder" value="D:\packages" />
+    <add key="http_proxy" value="http://proxy:8080" />
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	options := editor.GetConfig().Config.Add
+	if len(options) != 2 || options[0].Value != "D:\\packages" {
+		t.Errorf("内存中的配置选项未更新: %+v", options)
+	}
+}
+
+func TestSetConfigOptionCreatesSection(t *testing.T) {
+	editor := newTestEditor(t, credentialConfig)
+
+	if err := editor.SetConfigOption("signatureValidationMode", "require"); err != nil {
+		t.Fatalf("设置配置选项失败: %v", err)
+	}
+
+	// <config> 按惯例位于其他配置节之前
+	expected := strings.Replace(credentialConfig, `<configuration>
+`, `<configuration>
+    <config>
+        <add key="signatureValidationMode" value="require" />
+    </config>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestRemoveConfigOption(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.RemoveConfigOption("globalPackagesFolder"); err != nil {
+		t.Fatalf("删除配置选项失败: %v", err)
+	}
+
+	expected := strings.Replace(testConfig, "\n    <add key=\"globalPackagesFolder\" value=\"C:\\packages\" />", "", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+	if len(editor.GetConfig().Config.Add) != 0 {
+		t.Error("内存中的配置选项未删除")
+	}
+
+	if err := editor.RemoveConfigOption("missing"); err == nil {
+		t.Error("删除不存在的配置选项应返回错误")
+	}
+}
@@ -0,0 +1,155 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// RenamePackageSource 重命名包源的键，并同步更新引用该键的所有配置节
+//
+// 除 <packageSources> 中的 key 属性外，还会更新 <disabledPackageSources>、
+// <activePackageSource>、<packageSourceMapping> 中的 key 属性，
+// 以及 <packageSourceCredentials> 中以包源键命名的元素，全部以位置编辑完成。
+func (e *ConfigEditor) RenamePackageSource(oldKey, newKey string) error {
+	if oldKey == newKey {
+		return nil
+	}
+	if newKey == "" {
+		return fmt.Errorf("包源键不能为空")
+	}
+	if _, exists := e.findSourceInConfig(newKey); exists {
+		return fmt.Errorf("包源已存在: %s", newKey)
+	}
+
+	source, exists := e.findSourceElement(oldKey)
+	if !exists {
+		return fmt.Errorf("未找到包源: %s", oldKey)
+	}
+
+	return e.Transaction(func(e *ConfigEditor) error {
+		if err := e.setAttribute(source, "key", newKey); err != nil {
+			return err
+		}
+
+		if entry, exists := e.findDisabledEntry(oldKey); exists {
+			if err := e.setAttribute(entry, "key", newKey); err != nil {
+				return err
+			}
+		}
+
+		if section, exists := e.findElement(activePackageSourcePath); exists {
+			for _, add := range e.childElements(section, "add") {
+				if add.Attributes["key"] != oldKey {
+					continue
+				}
+				if err := e.setAttribute(add, "key", newKey); err != nil {
+					return err
+				}
+			}
+		}
+
+		if group, exists := e.findMappingGroup(oldKey); exists {
+			if err := e.setAttribute(group, "key", newKey); err != nil {
+				return err
+			}
+		}
+
+		if cred, exists := e.findCredentialElement(oldKey); exists {
+			if err := e.renameElement(cred, types.EncodeElementName(newKey)); err != nil {
+				return err
+			}
+		}
+
+		e.renameSourceInConfig(oldKey, newKey)
+		return nil
+	})
+}
+
+// findSourceElement 查找 <packageSources> 中指定键的包源元素
+func (e *ConfigEditor) findSourceElement(key string) (*parser.ElementPosition, bool) {
+	section, exists := e.findElement("configuration/packageSources")
+	if !exists {
+		return nil, false
+	}
+
+	for _, add := range e.childElements(section, "add") {
+		if add.Attributes["key"] == key {
+			return add, true
+		}
+	}
+
+	return nil, false
+}
+
+// renameElement 记录修改元素标签名的编辑，成对标签的开始和结束标签都会更新
+func (e *ConfigEditor) renameElement(elemPos *parser.ElementPosition, newName string) error {
+	start := elemPos.Range.Start.Offset + 1
+	e.edits = append(e.edits, Edit{
+		Range: parser.Range{
+			Start: parser.Position{Offset: start},
+			End:   parser.Position{Offset: start + len(elemPos.TagName)},
+		},
+		NewText: newName,
+		Type:    "update",
+	})
+
+	if elemPos.SelfClose {
+		return nil
+	}
+
+	endTagOffset := e.findEndTagOffset(elemPos)
+	if endTagOffset < 0 {
+		return fmt.Errorf("未找到%s元素的结束标签", elemPos.TagName)
+	}
+
+	nameStart := endTagOffset + len("</")
+	e.edits = append(e.edits, Edit{
+		Range: parser.Range{
+			Start: parser.Position{Offset: nameStart},
+			End:   parser.Position{Offset: nameStart + len(elemPos.TagName)},
+		},
+		NewText: newName,
+		Type:    "update",
+	})
+	return nil
+}
+
+// renameSourceInConfig 在内存配置中重命名包源及其所有引用
+func (e *ConfigEditor) renameSourceInConfig(oldKey, newKey string) {
+	config := e.parseResult.Config
+
+	for i, source := range config.PackageSources.Add {
+		if source.Key == oldKey {
+			config.PackageSources.Add[i].Key = newKey
+		}
+	}
+
+	if config.DisabledPackageSources != nil {
+		for i, source := range config.DisabledPackageSources.Add {
+			if source.Key == oldKey {
+				config.DisabledPackageSources.Add[i].Key = newKey
+			}
+		}
+	}
+
+	if config.ActivePackageSource != nil && config.ActivePackageSource.Add.Key == oldKey {
+		config.ActivePackageSource.Add.Key = newKey
+	}
+
+	if config.PackageSourceMapping != nil {
+		for i, source := range config.PackageSourceMapping.PackageSource {
+			if source.Key == oldKey {
+				config.PackageSourceMapping.PackageSource[i].Key = newKey
+			}
+		}
+	}
+
+	if creds := config.PackageSourceCredentials; creds != nil {
+		if cred, exists := creds.Sources[oldKey]; exists {
+			delete(creds.Sources, oldKey)
+			creds.Sources[newKey] = cred
+		}
+	}
+}
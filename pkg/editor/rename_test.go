@@ -0,0 +1,72 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+)
+
+const renameConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="private" value="https://private.example.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="private" value="true" />
+  </disabledPackageSources>
+  <activePackageSource>
+    <add key="private" value="https://private.example.com/v3/index.json" />
+  </activePackageSource>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="alice" />
+    </private>
+  </packageSourceCredentials>
+  <packageSourceMapping>
+    <packageSource key="private">
+      <package pattern="Private.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+func TestRenamePackageSource(t *testing.T) {
+	editor := newTestEditor(t, renameConfig)
+
+	if err := editor.RenamePackageSource("private", "internal"); err != nil {
+		t.Fatalf("重命名包源失败: %v", err)
+	}
+
+	expected := strings.NewReplacer(
+		`key="private"`, `key="internal"`,
+		"<private>", "<internal>",
+		"</private>", "</internal>",
+	).Replace(renameConfig)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	config := editor.GetConfig()
+	if config.PackageSources.Add[1].Key != "internal" ||
+		config.DisabledPackageSources.Add[0].Key != "internal" ||
+		config.ActivePackageSource.Add.Key != "internal" ||
+		config.PackageSourceMapping.PackageSource[0].Key != "internal" {
+		t.Errorf("内存中的包源引用未全部更新: %+v", config)
+	}
+	if _, exists := config.PackageSourceCredentials.Sources["internal"]; !exists {
+		t.Error("内存中的凭证未重命名")
+	}
+}
+
+func TestRenamePackageSourceErrors(t *testing.T) {
+	editor := newTestEditor(t, renameConfig)
+
+	if err := editor.RenamePackageSource("missing", "other"); err == nil {
+		t.Error("重命名不存在的包源应返回错误")
+	}
+	if err := editor.RenamePackageSource("private", "nuget.org"); err == nil {
+		t.Error("重命名为已存在的键应返回错误")
+	}
+	if len(editor.edits) != 0 {
+		t.Errorf("失败的重命名不应留下编辑，实际: %d", len(editor.edits))
+	}
+}
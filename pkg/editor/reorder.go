@@ -0,0 +1,187 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// sourceSlot 表示 <packageSources> 中一个包源所占的文本位置
+type sourceSlot struct {
+	key   string
+	start int // 包含紧邻注释在内的起始偏移量
+	end   int
+}
+
+// MovePackageSource 将包源移动到指定位置
+//
+// index 为移动后该包源在文件中所有包源里的下标，从 0 开始。
+// 包源顺序决定还原时的优先级，移动时只改写 <packageSources> 中位置发生变化的条目。
+func (e *ConfigEditor) MovePackageSource(key string, index int) error {
+	slots, err := e.packageSourceSlots()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(slots))
+	found := false
+	for _, current := range e.currentSourceOrder(slots) {
+		if current.key == key {
+			found = true
+			continue
+		}
+		keys = append(keys, current.key)
+	}
+	if !found {
+		return fmt.Errorf("未找到包源: %s", key)
+	}
+	if index < 0 || index >= len(slots) {
+		return fmt.Errorf("包源位置超出范围: %d", index)
+	}
+
+	keys = append(keys[:index], append([]string{key}, keys[index:]...)...)
+	return e.ReorderPackageSources(keys)
+}
+
+// ReorderPackageSources 按给定的键顺序重新排列包源
+//
+// 未列出的包源保持原有的相对顺序，排在列出的包源之后。每个条目原有的属性文本
+// 和紧邻其上方的注释随条目一起移动，条目之间的空白保持不变。
+// 尚未写入文件的新增包源不参与排序。
+func (e *ConfigEditor) ReorderPackageSources(keys []string) error {
+	slots, err := e.packageSourceSlots()
+	if err != nil {
+		return err
+	}
+
+	byKey := make(map[string]sourceSlot, len(slots))
+	for _, slot := range slots {
+		byKey[slot.key] = slot
+	}
+
+	ordered := make([]sourceSlot, 0, len(slots))
+	listed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		slot, exists := byKey[key]
+		if !exists {
+			return fmt.Errorf("未找到包源: %s", key)
+		}
+		if listed[key] {
+			return fmt.Errorf("包源重复出现: %s", key)
+		}
+		listed[key] = true
+		ordered = append(ordered, slot)
+	}
+	for _, current := range e.currentSourceOrder(slots) {
+		if !listed[current.key] {
+			ordered = append(ordered, current)
+		}
+	}
+
+	// 只改写内容发生变化的位置，之前排序时改写过的位置需要再次改写
+	content := e.parseResult.Content
+	for i, slot := range slots {
+		moved := ordered[i]
+		r := parser.Range{
+			Start: parser.Position{Offset: slot.start},
+			End:   parser.Position{Offset: slot.end},
+		}
+		if moved.key == slot.key && !e.hasEditAt(r) {
+			continue
+		}
+		e.edits = append(e.edits, Edit{
+			Range:   r,
+			NewText: string(content[moved.start:moved.end]),
+			Type:    "update",
+		})
+	}
+
+	e.reorderSourcesInConfig(ordered)
+	return nil
+}
+
+// packageSourceSlots 返回文件中仍然有效的包源条目位置，按出现顺序排列
+//
+// 已被删除但尚未应用的包源不包含在内。
+func (e *ConfigEditor) packageSourceSlots() ([]sourceSlot, error) {
+	section, exists := e.findElement("configuration/packageSources")
+	if !exists {
+		return nil, fmt.Errorf("未找到packageSources元素")
+	}
+
+	live := make(map[string]bool)
+	for _, source := range e.parseResult.Config.PackageSources.Add {
+		live[source.Key] = true
+	}
+
+	var slots []sourceSlot
+	for _, add := range e.childElements(section, "add") {
+		key := add.Attributes["key"]
+		if !live[key] {
+			continue
+		}
+
+		slot := sourceSlot{key: key, start: add.Range.Start.Offset, end: add.Range.End.Offset}
+		if lineBreak, ok := e.precedingLineBreak(slot.start); ok && lineBreak > 0 {
+			if commentStart, ok := e.attachedCommentStart(lineBreak); ok && commentStart > section.Range.Start.Offset {
+				slot.start = commentStart
+			}
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
+
+// currentSourceOrder 按内存配置中的当前顺序返回包源条目位置
+func (e *ConfigEditor) currentSourceOrder(slots []sourceSlot) []sourceSlot {
+	byKey := make(map[string]sourceSlot, len(slots))
+	for _, slot := range slots {
+		byKey[slot.key] = slot
+	}
+
+	current := make([]sourceSlot, 0, len(slots))
+	for _, source := range e.parseResult.Config.PackageSources.Add {
+		if slot, exists := byKey[source.Key]; exists {
+			current = append(current, slot)
+			delete(byKey, source.Key)
+		}
+	}
+	return current
+}
+
+// hasEditAt 判断是否已有替换指定范围的更新编辑
+func (e *ConfigEditor) hasEditAt(r parser.Range) bool {
+	for _, edit := range e.edits {
+		if edit.Type == "update" && edit.Range == r {
+			return true
+		}
+	}
+	return false
+}
+
+// reorderSourcesInConfig 按新的顺序重新排列内存配置中的包源
+func (e *ConfigEditor) reorderSourcesInConfig(ordered []sourceSlot) {
+	sources := e.parseResult.Config.PackageSources.Add
+	byKey := make(map[string]types.PackageSource, len(sources))
+	for _, source := range sources {
+		byKey[source.Key] = source
+	}
+
+	reordered := make([]types.PackageSource, 0, len(sources))
+	placed := make(map[string]bool, len(ordered))
+	for _, slot := range ordered {
+		reordered = append(reordered, byKey[slot.key])
+		placed[slot.key] = true
+	}
+
+	// 尚未写入文件的新增包源保持在末尾
+	for _, source := range sources {
+		if !placed[source.Key] {
+			reordered = append(reordered, source)
+		}
+	}
+
+	e.parseResult.Config.PackageSources.Add = reordered
+}
@@ -0,0 +1,91 @@
+package editor
+
+import (
+	"testing"
+)
+
+const reorderConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <clear />
+    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="private"   value="https://private.example.com/v3/index.json" />
+
+    <add key="local" value="C:\LocalPackages" />
+  </packageSources>
+</configuration>`
+
+func TestMovePackageSource(t *testing.T) {
+	editor := newTestEditor(t, reorderConfig)
+
+	if err := editor.MovePackageSource("local", 0); err != nil {
+		t.Fatalf("移动包源失败: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <clear />
+    <add key="local" value="C:\LocalPackages" />
+    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+
+    <add key="private"   value="https://private.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	sources := editor.GetConfig().PackageSources.Add
+	if sources[0].Key != "local" || sources[1].Key != "nuget.org" || sources[2].Key != "private" {
+		t.Errorf("内存中的包源顺序未更新: %+v", sources)
+	}
+
+	// 再次移动基于当前顺序，移回原位后内容恢复原样
+	if err := editor.MovePackageSource("local", 2); err != nil {
+		t.Fatalf("移动包源失败: %v", err)
+	}
+	if got := applyEdits(t, editor); got != reorderConfig {
+		t.Errorf("移回原位后内容应与原始内容一致:\n%s", got)
+	}
+
+	if err := editor.MovePackageSource("missing", 0); err == nil {
+		t.Error("移动不存在的包源应返回错误")
+	}
+	if err := editor.MovePackageSource("local", 3); err == nil {
+		t.Error("移动到超出范围的位置应返回错误")
+	}
+}
+
+func TestReorderPackageSources(t *testing.T) {
+	editor := newTestEditor(t, reorderConfig)
+
+	if err := editor.RemovePackageSource("nuget.org"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+	if err := editor.ReorderPackageSources([]string{"local"}); err != nil {
+		t.Fatalf("重新排序失败: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <clear />
+    <add key="local" value="C:\LocalPackages" />
+
+    <add key="private"   value="https://private.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+
+	if err := editor.ReorderPackageSources([]string{"local", "local"}); err == nil {
+		t.Error("重复的键应返回错误")
+	}
+	if err := editor.ReorderPackageSources([]string{"nuget.org"}); err == nil {
+		t.Error("已删除的包源不应参与排序")
+	}
+}
@@ -0,0 +1,319 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+)
+
+// sectionOrder NuGet 文档中 <configuration> 下各配置节的惯例顺序，新建配置节时据此确定位置
+var sectionOrder = []string{
+	"config",
+	"bindingRedirects",
+	"packageRestore",
+	"solution",
+	"packageSources",
+	"auditSources",
+	"packageSourceCredentials",
+	"apikeys",
+	"disabledPackageSources",
+	"activePackageSource",
+	"trustedSigners",
+	"packageSourceMapping",
+}
+
+// pendingSection 记录尚未应用的编辑向配置节插入的子元素
+//
+// 同一配置节的多次插入需要合并：配置节不存在时只新建一次，之后的子元素追加到
+// 待新建的配置节中。撤销或修改尚未应用的子元素时，据此在编辑队列中找到对应的文本。
+type pendingSection struct {
+	// created 配置节是否由待应用的编辑新建
+	created bool
+	// text 新建配置节当前的完整文本，用于在编辑队列中定位
+	text string
+
+	sectionIndent string
+	childIndent   string
+	unit          string
+	children      []pendingChild
+}
+
+// pendingChild 尚未应用的子元素，xml 的后续行已包含缩进
+type pendingChild struct {
+	key string
+	xml string
+}
+
+// sectionXML 生成新建配置节的完整文本
+func (p *pendingSection) sectionXML(sectionName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%s>", sectionName)
+	for _, child := range p.children {
+		fmt.Fprintf(&sb, "\n%s%s", p.childIndent, child.xml)
+	}
+	fmt.Fprintf(&sb, "\n%s</%s>", p.sectionIndent, sectionName)
+	return sb.String()
+}
+
+// addToSection 在 <configuration> 的指定配置节末尾插入子元素
+//
+// 配置节不存在时按惯例顺序在 <configuration> 中创建，自闭合的配置节会被展开；
+// 配置节已由之前的编辑新建时，子元素追加到该配置节中。key 标识子元素，
+// 用于之后通过 cancelPending 和 replacePending 撤销或修改尚未应用的插入。
+// build 接收单级缩进并返回子元素文本，多行文本的后续行只需包含相对于子元素的缩进。
+func (e *ConfigEditor) addToSection(sectionName, key string, build func(unit string) string) error {
+	if pending := e.pendingSections[sectionName]; pending != nil && pending.created {
+		pending.children = append(pending.children, pendingChild{key, indentContinuation(build(pending.unit), pending.childIndent)})
+		e.updatePendingSection(sectionName)
+		return nil
+	}
+
+	if section, exists := e.findElement("configuration/" + sectionName); exists {
+		indent := e.childIndent(section)
+		unit := strings.TrimPrefix(indent, e.lineIndent(section.Range.Start.Offset))
+		if unit == "" {
+			unit = e.style().indent
+		}
+		childXML := indentContinuation(build(unit), indent)
+		if err := e.insertChild(section, childXML); err != nil {
+			return err
+		}
+
+		pending := e.pendingSections[sectionName]
+		if pending == nil {
+			pending = &pendingSection{childIndent: indent, unit: unit}
+			e.setPendingSection(sectionName, pending)
+		}
+		pending.children = append(pending.children, pendingChild{key, childXML})
+		return nil
+	}
+
+	root, exists := e.findElement("configuration")
+	if !exists {
+		return fmt.Errorf("未找到configuration元素")
+	}
+
+	unit := e.style().indent
+	sectionIndent := e.childIndent(root)
+	pending := &pendingSection{
+		created:       true,
+		sectionIndent: sectionIndent,
+		childIndent:   sectionIndent + unit,
+		unit:          unit,
+	}
+	pending.children = []pendingChild{{key, indentContinuation(build(unit), pending.childIndent)}}
+
+	sectionXML := pending.sectionXML(sectionName)
+	if err := e.insertSection(root, sectionName, sectionXML, sectionIndent); err != nil {
+		return err
+	}
+	pending.text = e.normalizeEOL(sectionXML)
+	e.setPendingSection(sectionName, pending)
+	return nil
+}
+
+// cancelPending 撤销尚未应用的、键为 key 的子元素插入，返回是否找到了该插入
+//
+// 新建的配置节中没有其他子元素时，整个配置节的插入一并撤销。
+func (e *ConfigEditor) cancelPending(sectionName, key string) bool {
+	pending := e.pendingSections[sectionName]
+	if pending == nil {
+		return false
+	}
+
+	for i := len(pending.children) - 1; i >= 0; i-- {
+		child := pending.children[i]
+		if child.key != key {
+			continue
+		}
+
+		if !pending.created && !e.replaceEditText(e.normalizeEOL("\n"+pending.childIndent+child.xml), "") {
+			return false
+		}
+		pending.children = append(pending.children[:i], pending.children[i+1:]...)
+		if pending.created {
+			e.updatePendingSection(sectionName)
+		}
+		return true
+	}
+
+	return false
+}
+
+// replacePending 用 build 生成的文本替换尚未应用的、键为 key 的子元素，返回是否找到了该插入
+func (e *ConfigEditor) replacePending(sectionName, key string, build func(unit string) string) bool {
+	pending := e.pendingSections[sectionName]
+	if pending == nil {
+		return false
+	}
+
+	for i := len(pending.children) - 1; i >= 0; i-- {
+		child := pending.children[i]
+		if child.key != key {
+			continue
+		}
+
+		childXML := indentContinuation(build(pending.unit), pending.childIndent)
+		if pending.created {
+			pending.children[i].xml = childXML
+			e.updatePendingSection(sectionName)
+			return true
+		}
+
+		old := e.normalizeEOL("\n" + pending.childIndent + child.xml)
+		if !e.replaceEditText(old, e.normalizeEOL("\n"+pending.childIndent+childXML)) {
+			return false
+		}
+		pending.children[i].xml = childXML
+		return true
+	}
+
+	return false
+}
+
+// discardPendingSection 撤销尚未应用的、对配置节的所有插入
+func (e *ConfigEditor) discardPendingSection(sectionName string) {
+	pending := e.pendingSections[sectionName]
+	if pending == nil {
+		return
+	}
+
+	if pending.created {
+		e.replaceEditText(pending.text, "")
+	} else {
+		for _, child := range pending.children {
+			e.replaceEditText(e.normalizeEOL("\n"+pending.childIndent+child.xml), "")
+		}
+	}
+	delete(e.pendingSections, sectionName)
+}
+
+// updatePendingSection 按当前的子元素重新生成新建配置节的文本，没有子元素时撤销该配置节
+func (e *ConfigEditor) updatePendingSection(sectionName string) {
+	pending := e.pendingSections[sectionName]
+	if len(pending.children) == 0 {
+		e.replaceEditText(pending.text, "")
+		delete(e.pendingSections, sectionName)
+		return
+	}
+
+	text := e.normalizeEOL(pending.sectionXML(sectionName))
+	e.replaceEditText(pending.text, text)
+	pending.text = text
+}
+
+// setPendingSection 记录配置节尚未应用的插入
+func (e *ConfigEditor) setPendingSection(sectionName string, pending *pendingSection) {
+	if e.pendingSections == nil {
+		e.pendingSections = make(map[string]*pendingSection)
+	}
+	e.pendingSections[sectionName] = pending
+}
+
+// replaceEditText 在最后一个包含 old 的编辑中将其替换为 new，返回是否找到了这样的编辑
+//
+// 替换后只剩空白的插入编辑会从队列中移除。
+func (e *ConfigEditor) replaceEditText(old, new string) bool {
+	for i := len(e.edits) - 1; i >= 0; i-- {
+		edit := e.edits[i]
+		if !strings.Contains(edit.NewText, old) {
+			continue
+		}
+
+		text := strings.Replace(edit.NewText, old, new, 1)
+		if strings.TrimSpace(text) == "" && edit.Range.Start.Offset == edit.Range.End.Offset {
+			e.edits = append(e.edits[:i], e.edits[i+1:]...)
+		} else {
+			e.edits[i].NewText = text
+		}
+		return true
+	}
+
+	return false
+}
+
+// clonePendingSections 复制尚未应用的配置节插入记录，用于事务快照
+func clonePendingSections(sections map[string]*pendingSection) map[string]*pendingSection {
+	if sections == nil {
+		return nil
+	}
+
+	cloned := make(map[string]*pendingSection, len(sections))
+	for name, pending := range sections {
+		copied := *pending
+		copied.children = append([]pendingChild(nil), pending.children...)
+		cloned[name] = &copied
+	}
+	return cloned
+}
+
+// insertSection 按惯例顺序将新的配置节插入 <configuration>
+//
+// 新配置节放在文件中最后一个按惯例应位于其前的配置节之后；没有这样的配置节时，
+// 放在第一个按惯例应位于其后的配置节之前；都不存在时追加到末尾。
+func (e *ConfigEditor) insertSection(root *parser.ElementPosition, sectionName, sectionXML, sectionIndent string) error {
+	rank := sectionRank(sectionName)
+
+	var after, before *parser.ElementPosition
+	for path, elemPos := range e.parseResult.Positions {
+		if strings.Count(path, "/") != 1 || !strings.HasPrefix(path, "configuration/") {
+			continue
+		}
+		r := sectionRank(elemPos.TagName)
+		if r < 0 {
+			continue
+		}
+		start := elemPos.Range.Start.Offset
+		if r < rank && (after == nil || start > after.Range.Start.Offset) {
+			after = elemPos
+		}
+		if r > rank && (before == nil || start < before.Range.Start.Offset) {
+			before = elemPos
+		}
+	}
+
+	var offset int
+	var newText string
+	switch {
+	case after != nil:
+		offset = after.Range.End.Offset
+		newText = fmt.Sprintf("\n%s%s", sectionIndent, sectionXML)
+	case before != nil:
+		lineBreak, ok := e.precedingLineBreak(before.Range.Start.Offset)
+		if !ok || lineBreak == 0 {
+			return e.insertChild(root, sectionXML)
+		}
+		// 插入到前一个换行符之后、原有缩进之前
+		offset = lineBreak + 1
+		if e.parseResult.Content[lineBreak] == '\r' {
+			offset++
+		}
+		newText = fmt.Sprintf("%s%s\n", sectionIndent, sectionXML)
+	default:
+		return e.insertChild(root, sectionXML)
+	}
+
+	pos := parser.Position{Offset: offset}
+	e.edits = append(e.edits, Edit{
+		Range:   parser.Range{Start: pos, End: pos},
+		NewText: e.normalizeEOL(newText),
+		Type:    "add",
+	})
+	return nil
+}
+
+// sectionRank 返回配置节在惯例顺序中的位置，未知配置节返回 -1
+func sectionRank(name string) int {
+	for i, section := range sectionOrder {
+		if section == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// indentContinuation 为多行文本中第一行之后的每一行添加缩进
+func indentContinuation(text, indent string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+indent)
+}
@@ -0,0 +1,270 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+)
+
+// newLenientTestEditor 解析允许缺少包源的内容并创建编辑器
+func newLenientTestEditor(t *testing.T, content string) *ConfigEditor {
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = true
+	parseResult, err := p.ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("解析配置失败: %v", err)
+	}
+	return NewConfigEditor(parseResult)
+}
+
+func TestAddPackageSourceCreatesSection(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+  </config>
+  <disabledPackageSources>
+    <add key="old" value="true" />
+  </disabledPackageSources>
+</configuration>`
+	editor := newLenientTestEditor(t, content)
+
+	if err := editor.AddPackageSource("nuget.org", "https://api.nuget.org/v3/index.json", "3"); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	expected := strings.Replace(content, `  </config>
+`, `  </config>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+  </packageSources>
+`, 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestAddPackageSourceSelfClosingSection(t *testing.T) {
+	content := "<configuration>\r\n\t<packageSources />\r\n</configuration>\r\n"
+	editor := newLenientTestEditor(t, content)
+
+	if err := editor.AddPackageSource("local", "/packages", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	expected := "<configuration>\r\n\t<packageSources>\r\n\t\t<add key=\"local\" value=\"/packages\" />\r\n\t</packageSources>\r\n</configuration>\r\n"
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%q\n期望:\n%q", got, expected)
+	}
+}
+
+func TestCreateSectionBeforeLaterSection(t *testing.T) {
+	content := "<configuration>\r\n  <packageSources>\r\n    <add key=\"a\" value=\"https://a.example.com\" />\r\n  </packageSources>\r\n" +
+		"  <packageSourceMapping>\r\n    <packageSource key=\"a\">\r\n      <package pattern=\"*\" />\r\n    </packageSource>\r\n  </packageSourceMapping>\r\n</configuration>"
+	editor := newTestEditor(t, content)
+
+	if err := editor.DisablePackageSource("a"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.SetActivePackageSource("a"); err != nil {
+		t.Fatalf("设置活跃包源失败: %v", err)
+	}
+
+	expected := strings.Replace(content, "  </packageSources>\r\n", "  </packageSources>\r\n"+
+		"  <disabledPackageSources>\r\n    <add key=\"a\" value=\"true\" />\r\n  </disabledPackageSources>\r\n"+
+		"  <activePackageSource>\r\n    <add key=\"a\" value=\"https://a.example.com\" />\r\n  </activePackageSource>\r\n", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%q\n期望:\n%q", got, expected)
+	}
+}
+
+// applyValidated 应用编辑并校验结果与内存配置一致
+func applyValidated(t *testing.T, editor *ConfigEditor) string {
+	t.Helper()
+	modified, err := editor.ApplyEditsValidated()
+	if err != nil {
+		t.Fatalf("应用编辑失败: %v", err)
+	}
+	return string(modified)
+}
+
+func TestAddToMissingSectionTwice(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com/v3/index.json" />
+    <add key="b" value="https://b.example.com/v3/index.json" />
+  </packageSources>
+</configuration>
+`
+	tests := []struct {
+		name    string
+		edit    func(e *ConfigEditor) error
+		section string
+	}{
+		{"disabledPackageSources", func(e *ConfigEditor) error {
+			if err := e.DisablePackageSource("a"); err != nil {
+				return err
+			}
+			return e.DisablePackageSource("b")
+		}, "<disabledPackageSources>"},
+		{"packageSourceCredentials", func(e *ConfigEditor) error {
+			if err := e.AddCredential("a", "alice", "secret"); err != nil {
+				return err
+			}
+			return e.AddCredential("b", "bob", "secret")
+		}, "<packageSourceCredentials>"},
+		{"config", func(e *ConfigEditor) error {
+			if err := e.SetConfigOption("http_proxy", "http://proxy"); err != nil {
+				return err
+			}
+			if err := e.SetConfigOption("globalPackagesFolder", "/packages"); err != nil {
+				return err
+			}
+			return e.SetConfigOption("http_proxy", "http://other-proxy")
+		}, "<config>"},
+		{"packageSourceMapping", func(e *ConfigEditor) error {
+			if err := e.AddPackageSourceMapping("a", "Contoso.*"); err != nil {
+				return err
+			}
+			if err := e.AddPackageSourceMapping("b", "*"); err != nil {
+				return err
+			}
+			return e.AddPackageSourceMapping("a", "Fabrikam.*", "Contoso.*")
+		}, "<packageSourceMapping>"},
+		{"activePackageSource", func(e *ConfigEditor) error {
+			if err := e.SetActivePackageSource("a"); err != nil {
+				return err
+			}
+			return e.SetActivePackageSource("b")
+		}, "<activePackageSource>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor := newTestEditor(t, content)
+			if err := tt.edit(editor); err != nil {
+				t.Fatalf("编辑失败: %v", err)
+			}
+
+			got := applyValidated(t, editor)
+			if n := strings.Count(got, tt.section); n != 1 {
+				t.Errorf("%s 出现了 %d 次:\n%s", tt.section, n, got)
+			}
+		})
+	}
+}
+
+func TestAddToMissingSectionLayout(t *testing.T) {
+	content := "<configuration>\r\n  <packageSources>\r\n    <add key=\"a\" value=\"https://a.example.com\" />\r\n  </packageSources>\r\n</configuration>\r\n"
+	editor := newTestEditor(t, content)
+
+	for _, key := range []string{"http_proxy", "signatureValidationMode"} {
+		if err := editor.SetConfigOption(key, "x"); err != nil {
+			t.Fatalf("设置配置选项失败: %v", err)
+		}
+	}
+	if err := editor.RemoveConfigOption("http_proxy"); err != nil {
+		t.Fatalf("删除尚未应用的配置选项失败: %v", err)
+	}
+
+	expected := "<configuration>\r\n  <config>\r\n    <add key=\"signatureValidationMode\" value=\"x\" />\r\n  </config>\r\n" +
+		"  <packageSources>\r\n    <add key=\"a\" value=\"https://a.example.com\" />\r\n  </packageSources>\r\n</configuration>\r\n"
+	if got := applyValidated(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%q\n期望:\n%q", got, expected)
+	}
+
+	// 撤销最后一个子元素时整个新建的配置节一并撤销
+	if err := editor.RemoveConfigOption("signatureValidationMode"); err != nil {
+		t.Fatalf("删除尚未应用的配置选项失败: %v", err)
+	}
+	if got := applyValidated(t, editor); got != content {
+		t.Errorf("撤销后的内容不符合预期:\n%q", got)
+	}
+}
+
+func TestRemovePendingPackageSource(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	if err := editor.AddPackageSource("c", "https://c.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.UpdatePackageSourceURL("c", "https://c2.example.com"); err != nil {
+		t.Fatalf("更新尚未应用的包源失败: %v", err)
+	}
+	if got := applyValidated(t, editor); !strings.Contains(got, `<add key="c" value="https://c2.example.com" />`) {
+		t.Errorf("更新尚未应用的包源后的内容:\n%s", got)
+	}
+
+	if err := editor.RemovePackageSource("c"); err != nil {
+		t.Fatalf("删除尚未应用的包源失败: %v", err)
+	}
+	if got := applyValidated(t, editor); got != content {
+		t.Errorf("撤销后的内容不符合预期:\n%s", got)
+	}
+	if err := editor.RemovePackageSource("c"); err == nil {
+		t.Error("再次删除已撤销的包源应该返回错误")
+	}
+}
+
+func TestRemovePendingMapping(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	if err := editor.AddPackageSourceMapping("a", "Contoso.*", "Fabrikam.*"); err != nil {
+		t.Fatalf("添加映射失败: %v", err)
+	}
+	if err := editor.RemovePackagePattern("a", "Contoso.*"); err != nil {
+		t.Fatalf("删除尚未应用的模式失败: %v", err)
+	}
+	got := applyValidated(t, editor)
+	if strings.Contains(got, "Contoso.*") || !strings.Contains(got, "Fabrikam.*") {
+		t.Errorf("删除尚未应用的模式后的内容:\n%s", got)
+	}
+
+	if err := editor.RemovePackageSourceMapping("a"); err != nil {
+		t.Fatalf("删除尚未应用的映射失败: %v", err)
+	}
+	if got := applyValidated(t, editor); got != content {
+		t.Errorf("撤销后的内容不符合预期:\n%s", got)
+	}
+}
+
+func TestPendingSectionRollback(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key="b" value="https://b.example.com" />
+  </packageSources>
+</configuration>`
+	editor := newTestEditor(t, content)
+
+	if err := editor.DisablePackageSource("a"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	editor.Begin()
+	if err := editor.DisablePackageSource("b"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+	if err := editor.Rollback(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+	if err := editor.SetConfigOption("http_proxy", "http://proxy"); err != nil {
+		t.Fatalf("设置配置选项失败: %v", err)
+	}
+
+	got := applyValidated(t, editor)
+	if strings.Count(got, "<disabledPackageSources>") != 1 || strings.Contains(got, `key="b" value="true"`) {
+		t.Errorf("回滚后的内容不符合预期:\n%s", got)
+	}
+}
@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// attr 表示生成XML片段时使用的属性
+type attr struct {
+	name  string
+	value string
+}
+
+// xmlStyle 描述原始文件的XML书写风格，生成新片段时沿用
+type xmlStyle struct {
+	// indent 单级缩进，如两个空格、四个空格或制表符
+	indent string
+
+	// selfClose 自闭合标签的结尾，" />" 或 "/>"
+	selfClose string
+
+	// quote 属性值使用的引号
+	quote byte
+
+	// eol 换行符，"\n" 或 "\r\n"
+	eol string
+
+	// expanded 空元素是否使用成对标签 <add ...></add>
+	expanded bool
+}
+
+// style 返回生成XML片段时使用的风格
+//
+// 设置了 ElementStyle 时，自闭合方式和引号以其为准，缩进和换行符仍沿用原始文件。
+func (e *ConfigEditor) style() xmlStyle {
+	style := e.detectStyle()
+	if e.ElementStyle != nil {
+		style.selfClose = e.ElementStyle.SelfCloseSuffix()
+		style.quote = e.ElementStyle.QuoteChar()
+		style.expanded = !e.ElementStyle.SelfClosing
+	}
+	return style
+}
+
+// detectStyle 检测并缓存原始文件的XML书写风格
+func (e *ConfigEditor) detectStyle() xmlStyle {
+	if e.detectedStyle != nil {
+		return *e.detectedStyle
+	}
+
+	content := string(e.parseResult.Content)
+	style := xmlStyle{
+		indent:    e.indentUnit(),
+		selfClose: " />",
+		quote:     '"',
+		eol:       "\n",
+	}
+
+	// 以多数为准决定使用 CRLF 还是 LF
+	crlf := strings.Count(content, "\r\n")
+	if crlf > 0 && crlf*2 >= strings.Count(content, "\n") {
+		style.eol = "\r\n"
+	}
+
+	// 以多数为准决定 "/>" 前是否有空格
+	selfCloseTotal := strings.Count(content, "/>")
+	selfCloseSpaced := strings.Count(content, " />")
+	if selfCloseTotal > 0 && selfCloseSpaced*2 < selfCloseTotal {
+		style.selfClose = "/>"
+	}
+
+	// 空元素以成对标签书写的多于自闭合标签时，新元素也使用成对标签
+	if strings.Count(content, "></") > selfCloseTotal {
+		style.expanded = true
+	}
+
+	// 以多数为准决定属性值使用单引号还是双引号
+	if strings.Count(content, "='") > strings.Count(content, `="`) {
+		style.quote = '\''
+	}
+
+	e.detectedStyle = &style
+	return style
+}
+
+// formatElement 按原始文件风格生成空元素，自闭合或成对标签
+func (e *ConfigEditor) formatElement(tagName string, attrs ...attr) string {
+	if e.style().expanded {
+		return e.formatStartTag(tagName, attrs...) + "</" + tagName + ">"
+	}
+
+	startTag := e.formatStartTag(tagName, attrs...)
+	return strings.TrimSuffix(startTag, ">") + e.style().selfClose
+}
+
+// formatStartTag 按原始文件风格生成开始标签
+func (e *ConfigEditor) formatStartTag(tagName string, attrs ...attr) string {
+	style := e.style()
+
+	var sb strings.Builder
+	sb.WriteString("<")
+	sb.WriteString(tagName)
+	for _, a := range attrs {
+		fmt.Fprintf(&sb, " %s=%c%s%c", a.name, style.quote, e.escapeValue(a.value), style.quote)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// escapeValue 按原始文件使用的引号转义属性值
+func (e *ConfigEditor) escapeValue(value string) string {
+	escaped := escapeAttr(value)
+	if e.style().quote == '\'' {
+		escaped = strings.ReplaceAll(escaped, "'", "&apos;")
+	}
+	return escaped
+}
+
+// normalizeEOL 将生成文本中的换行符转换为原始文件使用的换行符
+func (e *ConfigEditor) normalizeEOL(text string) string {
+	eol := e.style().eol
+	if eol == "\n" {
+		return text
+	}
+	return strings.ReplaceAll(text, "\n", eol)
+}
@@ -0,0 +1,201 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+)
+
+func TestAddPackageSourceMatchesStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "两个空格缩进",
+			content: `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`,
+			expected: `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key="new" value="https://new.example.com" />
+  </packageSources>
+</configuration>`,
+		},
+		{
+			name: "制表符缩进",
+			content: "<configuration>\n" +
+				"\t<packageSources>\n" +
+				"\t\t<add key=\"a\" value=\"https://a.example.com\" />\n" +
+				"\t</packageSources>\n" +
+				"</configuration>",
+			expected: "<configuration>\n" +
+				"\t<packageSources>\n" +
+				"\t\t<add key=\"a\" value=\"https://a.example.com\" />\n" +
+				"\t\t<add key=\"new\" value=\"https://new.example.com\" />\n" +
+				"\t</packageSources>\n" +
+				"</configuration>",
+		},
+		{
+			name: "单引号且自闭合无空格",
+			content: `<configuration>
+    <packageSources>
+        <add key='a' value='https://a.example.com'/>
+    </packageSources>
+</configuration>`,
+			expected: `<configuration>
+    <packageSources>
+        <add key='a' value='https://a.example.com'/>
+        <add key='new' value='https://new.example.com'/>
+    </packageSources>
+</configuration>`,
+		},
+		{
+			name: "空包源节沿用兄弟节缩进",
+			content: `<configuration>
+   <packageSources clear="true">
+   </packageSources>
+   <config>
+      <add key="x" value="y" />
+   </config>
+</configuration>`,
+			expected: `<configuration>
+   <packageSources clear="true">
+      <add key="new" value="https://new.example.com" />
+   </packageSources>
+   <config>
+      <add key="x" value="y" />
+   </config>
+</configuration>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor := newTestEditor(t, tt.content)
+			if err := editor.AddPackageSource("new", "https://new.example.com", ""); err != nil {
+				t.Fatalf("添加包源失败: %v", err)
+			}
+
+			if got := applyEdits(t, editor); got != tt.expected {
+				t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisablePackageSourceMatchesStyle(t *testing.T) {
+	content := "<configuration>\n" +
+		"\t<packageSources>\n" +
+		"\t\t<add key='a' value='https://a.example.com'/>\n" +
+		"\t</packageSources>\n" +
+		"</configuration>"
+
+	editor := newTestEditor(t, content)
+	if err := editor.DisablePackageSource("a"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+
+	expected := strings.Replace(content, "\t</packageSources>\n", "\t</packageSources>\n"+
+		"\t<disabledPackageSources>\n"+
+		"\t\t<add key='a' value='true'/>\n"+
+		"\t</disabledPackageSources>\n", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestEditsPreserveLineEndings(t *testing.T) {
+	content := "<?xml version=\"1.0\" encoding=\"utf-8\"?>\r\n" +
+		"<configuration>\r\n" +
+		"  <packageSources>\r\n" +
+		"    <add key=\"a\" value=\"https://a.example.com\" />\r\n" +
+		"  </packageSources>\r\n" +
+		"</configuration>\r\n"
+
+	editor := newTestEditor(t, content)
+	if err := editor.AddPackageSource("b", "https://b.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.AddCredential("a", "user", "pass"); err != nil {
+		t.Fatalf("添加凭证失败: %v", err)
+	}
+
+	expected := "<?xml version=\"1.0\" encoding=\"utf-8\"?>\r\n" +
+		"<configuration>\r\n" +
+		"  <packageSources>\r\n" +
+		"    <add key=\"a\" value=\"https://a.example.com\" />\r\n" +
+		"    <add key=\"b\" value=\"https://b.example.com\" />\r\n" +
+		"  </packageSources>\r\n" +
+		"  <packageSourceCredentials>\r\n" +
+		"    <a>\r\n" +
+		"      <add key=\"Username\" value=\"user\" />\r\n" +
+		"      <add key=\"ClearTextPassword\" value=\"pass\" />\r\n" +
+		"    </a>\r\n" +
+		"  </packageSourceCredentials>\r\n" +
+		"</configuration>\r\n"
+
+	got := applyEdits(t, editor)
+	if got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%q\n期望:\n%q", got, expected)
+	}
+	if strings.Count(got, "\n") != strings.Count(got, "\r\n") {
+		t.Error("修改后的内容包含混合的换行符")
+	}
+}
+
+func TestAddPackageSourceExpandedStyle(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com"></add>
+  </packageSources>
+</configuration>`
+
+	editor := newTestEditor(t, content)
+	if err := editor.AddPackageSource("new", "https://new.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	expected := strings.Replace(content, "</add>\n", "</add>\n"+
+		"    <add key=\"new\" value=\"https://new.example.com\"></add>\n", 1)
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
+
+func TestElementStyleOverridesDetectedStyle(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`
+
+	editor := newTestEditor(t, content)
+	editor.ElementStyle = &parser.ElementStyle{SelfClosing: false, Quote: '\''}
+	if err := editor.AddPackageSource("new", "https://new.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.AddPackageSourceMapping("new", "Contoso.*"); err != nil {
+		t.Fatalf("添加包源映射失败: %v", err)
+	}
+
+	expected := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key='new' value='https://new.example.com'></add>
+  </packageSources>
+  <packageSourceMapping>
+    <packageSource key='new'>
+      <package pattern='Contoso.*'></package>
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+	if got := applyEdits(t, editor); got != expected {
+		t.Errorf("修改后的内容不符合预期:\n%s\n期望:\n%s", got, expected)
+	}
+}
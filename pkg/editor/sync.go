@@ -0,0 +1,538 @@
+package editor
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// keyValue 表示 <add key="..." value="..." /> 形式的配置项
+type keyValue struct {
+	key   string
+	value string
+}
+
+// SyncFromConfig 将对 GetConfig 返回的配置对象的直接修改转换为位置编辑
+//
+// 同步时比较配置对象与解析时的原始状态，重新生成最少的文本编辑：
+// 未变化的配置节不产生编辑，已有条目原地更新，新增的包源按其在列表中的位置插入，
+// 调整顺序的包源保留原有文本。之前通过编辑器方法加入的编辑已经反映在配置对象中，
+// 会被同步结果取代。<clear /> 的增减不在同步范围内。
+//
+// 同步失败时编辑队列和配置对象保持调用前的状态。
+func (e *ConfigEditor) SyncFromConfig() error {
+	desired := e.parseResult.Config.Clone()
+	pending := append([]Edit(nil), e.edits...)
+	pendingSections := e.pendingSections
+	snapshots := e.snapshots
+
+	// 以解析时的状态为基准重新生成编辑，同步过程中内存配置保持为原始状态
+	e.restore(e.original, nil, nil)
+
+	if err := e.syncFrom(desired); err != nil {
+		e.restore(desired, pending, pendingSections)
+		e.snapshots = snapshots
+		return err
+	}
+
+	*e.parseResult.Config = *desired
+	e.snapshots = snapshots
+	return nil
+}
+
+// syncFrom 为期望配置与原始配置之间的差异生成编辑
+func (e *ConfigEditor) syncFrom(desired *types.NuGetConfig) error {
+	original := e.parseResult.Config
+
+	if !reflect.DeepEqual(normalizeSources(original), normalizeSources(desired)) {
+		if err := e.syncPackageSources(desired.PackageSources.Add); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeCredentials(original), normalizeCredentials(desired)) {
+		if err := e.syncCredentials(desired.PackageSourceCredentials); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeOptions(original), normalizeOptions(desired)) {
+		var current, items []keyValue
+		if original.Config != nil {
+			for _, option := range original.Config.Add {
+				current = append(current, keyValue{option.Key, option.Value})
+			}
+		}
+		if desired.Config != nil {
+			for _, option := range desired.Config.Add {
+				items = append(items, keyValue{option.Key, option.Value})
+			}
+		}
+		if err := e.syncKeyValueSection("config", current, items, desired.Config != nil); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeDisabled(original), normalizeDisabled(desired)) {
+		var current, items []keyValue
+		if original.DisabledPackageSources != nil {
+			for _, source := range original.DisabledPackageSources.Add {
+				current = append(current, keyValue{source.Key, source.Value})
+			}
+		}
+		if desired.DisabledPackageSources != nil {
+			for _, source := range desired.DisabledPackageSources.Add {
+				items = append(items, keyValue{source.Key, source.Value})
+			}
+		}
+		if err := e.syncKeyValueSection("disabledPackageSources", current, items, desired.DisabledPackageSources != nil); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeActive(original), normalizeActive(desired)) {
+		if err := e.syncActive(normalizeActive(desired)); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(normalizeMapping(original), normalizeMapping(desired)) {
+		if err := e.syncMapping(desired.PackageSourceMapping); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncPackageSources 同步包源列表，包括删除、更新、调整顺序和按位置插入
+func (e *ConfigEditor) syncPackageSources(desired []types.PackageSource) error {
+	slots, err := e.packageSourceSlots()
+	if err != nil {
+		// packageSources 不存在时全部新建
+		entries := make([]string, 0, len(desired))
+		for _, source := range desired {
+			entries = append(entries, e.formatSource(source))
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return e.addToSection("packageSources", "", func(string) string { return strings.Join(entries, "\n") })
+	}
+
+	original := make(map[string]types.PackageSource)
+	for _, source := range e.parseResult.Config.PackageSources.Add {
+		original[source.Key] = source
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, source := range desired {
+		wanted[source.Key] = true
+	}
+
+	slotByKey := make(map[string]sourceSlot, len(slots))
+	var keptSlots []sourceSlot
+	for _, slot := range slots {
+		slotByKey[slot.key] = slot
+		if wanted[slot.key] {
+			keptSlots = append(keptSlots, slot)
+			continue
+		}
+		if elem, exists := e.findSourceElement(slot.key); exists {
+			e.removeElement(elem)
+		}
+	}
+
+	var kept []types.PackageSource
+	for _, source := range desired {
+		if _, exists := slotByKey[source.Key]; exists {
+			kept = append(kept, source)
+		}
+	}
+
+	// 保留的包源按期望顺序放入原有位置，未移动的原地更新属性
+	content := e.parseResult.Content
+	slotIndex := make(map[string]int, len(kept))
+	for i, slot := range keptSlots {
+		source := kept[i]
+		slotIndex[source.Key] = i
+		elem, _ := e.findSourceElement(source.Key)
+
+		if source.Key == slot.key {
+			if err := e.syncSourceAttributes(elem, original[source.Key], source); err != nil {
+				return err
+			}
+			continue
+		}
+
+		moved := slotByKey[source.Key]
+		e.edits = append(e.edits, Edit{
+			Range: parser.Range{
+				Start: parser.Position{Offset: slot.start},
+				End:   parser.Position{Offset: slot.end},
+			},
+			NewText: e.movedSourceText(content, moved, elem, original[source.Key], source),
+			Type:    "update",
+		})
+	}
+
+	// 新增的包源插入到期望顺序中前一个保留包源所在位置之后
+	section, _ := e.findElement("configuration/packageSources")
+	indent := e.childIndent(section)
+	var leading []string
+	anchor := ""
+	for _, source := range desired {
+		if _, isKept := slotIndex[source.Key]; isKept {
+			anchor = source.Key
+			continue
+		}
+
+		entryXML := e.formatSource(source)
+		if anchor == "" {
+			leading = append(leading, entryXML)
+			continue
+		}
+
+		pos := parser.Position{Offset: keptSlots[slotIndex[anchor]].end}
+		e.edits = append(e.edits, Edit{
+			Range:   parser.Range{Start: pos, End: pos},
+			NewText: e.normalizeEOL("\n" + indent + entryXML),
+			Type:    "add",
+		})
+	}
+
+	if len(leading) == 0 {
+		return nil
+	}
+	if len(keptSlots) == 0 {
+		return e.addToSection("packageSources", "", func(string) string { return strings.Join(leading, "\n") })
+	}
+
+	pos := parser.Position{Offset: keptSlots[0].start}
+	e.edits = append(e.edits, Edit{
+		Range:   parser.Range{Start: pos, End: pos},
+		NewText: e.normalizeEOL(strings.Join(leading, "\n"+indent) + "\n" + indent),
+		Type:    "add",
+	})
+	return nil
+}
+
+// syncSourceAttributes 原地更新包源元素的 value 和 protocolVersion 属性
+func (e *ConfigEditor) syncSourceAttributes(elem *parser.ElementPosition, current, desired types.PackageSource) error {
+	if desired.Value != current.Value {
+		if err := e.setAttribute(elem, "value", desired.Value); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case desired.ProtocolVersion == current.ProtocolVersion:
+	case desired.ProtocolVersion == "":
+		return e.removeAttribute(elem, "protocolVersion")
+	default:
+		return e.setAttribute(elem, "protocolVersion", desired.ProtocolVersion)
+	}
+
+	return nil
+}
+
+// movedSourceText 返回移动到新位置的包源文本
+//
+// 尽量保留原有文本，只替换发生变化的属性值；需要增删属性时改为按文件风格重新生成元素，
+// 紧邻的注释仍然保留。
+func (e *ConfigEditor) movedSourceText(content []byte, slot sourceSlot, elem *parser.ElementPosition, current, desired types.PackageSource) string {
+	type replacement struct {
+		start, end int
+		text       string
+	}
+
+	var replacements []replacement
+	for _, a := range []struct{ name, current, desired string }{
+		{"value", current.Value, desired.Value},
+		{"protocolVersion", current.ProtocolVersion, desired.ProtocolVersion},
+	} {
+		if a.current == a.desired {
+			continue
+		}
+		r, exists := elem.AttrRanges[a.name]
+		if !exists || a.desired == "" {
+			prefix := string(content[slot.start:elem.Range.Start.Offset])
+			return prefix + e.formatSource(desired)
+		}
+		replacements = append(replacements, replacement{r.Start.Offset, r.End.Offset, e.escapeValue(a.desired)})
+	}
+
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start > replacements[j].start })
+	text := string(content[slot.start:slot.end])
+	for _, r := range replacements {
+		text = text[:r.start-slot.start] + r.text + text[r.end-slot.start:]
+	}
+	return text
+}
+
+// formatSource 按文件风格生成包源元素
+func (e *ConfigEditor) formatSource(source types.PackageSource) string {
+	attrs := []attr{{"key", source.Key}, {"value", source.Value}}
+	if source.ProtocolVersion != "" {
+		attrs = append(attrs, attr{"protocolVersion", source.ProtocolVersion})
+	}
+	return e.formatElement("add", attrs...)
+}
+
+// syncKeyValueSection 同步 <add key value /> 形式的配置节
+//
+// keepSection 为 false 时删除整个配置节；配置节不存在且没有新增项时不会创建空的配置节。
+func (e *ConfigEditor) syncKeyValueSection(sectionName string, current, desired []keyValue, keepSection bool) error {
+	section, exists := e.findElement("configuration/" + sectionName)
+	if !keepSection {
+		if exists {
+			e.removeElement(section)
+		}
+		return nil
+	}
+
+	currentValues := make(map[string]string, len(current))
+	for _, item := range current {
+		if _, dup := currentValues[item.key]; !dup {
+			currentValues[item.key] = item.value
+		}
+	}
+
+	elems := make(map[string]*parser.ElementPosition)
+	var order []*parser.ElementPosition
+	if exists {
+		for _, add := range e.childElements(section, "add") {
+			key := add.Attributes["key"]
+			if _, dup := elems[key]; !dup {
+				elems[key] = add
+				order = append(order, add)
+			}
+		}
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	var added []string
+	for _, item := range desired {
+		wanted[item.key] = true
+		if elem, exists := elems[item.key]; exists {
+			if currentValues[item.key] != item.value {
+				if err := e.setAttribute(elem, "value", item.value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		added = append(added, e.formatElement("add", attr{"key", item.key}, attr{"value", item.value}))
+	}
+
+	for _, elem := range order {
+		if !wanted[elem.Attributes["key"]] {
+			e.removeElement(elem)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+	return e.addToSection(sectionName, "", func(string) string { return strings.Join(added, "\n") })
+}
+
+// syncCredentials 同步包源凭证
+func (e *ConfigEditor) syncCredentials(desired *types.PackageSourceCredentials) error {
+	section, exists := e.findElement(packageSourceCredentialsPath)
+	if desired == nil {
+		if exists {
+			e.removeElement(section)
+		}
+		return nil
+	}
+
+	var original map[string]types.SourceCredential
+	if creds := e.parseResult.Config.PackageSourceCredentials; creds != nil {
+		original = creds.Sources
+	}
+
+	keys := make([]string, 0, len(desired.Sources))
+	for key := range desired.Sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var newKeys []string
+	for _, key := range keys {
+		elem, exists := e.findCredentialElement(key)
+		if !exists {
+			newKeys = append(newKeys, key)
+			continue
+		}
+		if err := e.syncCredentialItems(elem, original[key].Add, desired.Sources[key].Add); err != nil {
+			return err
+		}
+	}
+
+	removed := make([]string, 0)
+	for key := range original {
+		if _, wanted := desired.Sources[key]; !wanted {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		if elem, exists := e.findCredentialElement(key); exists {
+			e.removeElement(elem)
+		}
+	}
+
+	if len(newKeys) == 0 {
+		return nil
+	}
+	return e.addToSection("packageSourceCredentials", "", func(unit string) string {
+		blocks := make([]string, 0, len(newKeys))
+		for _, key := range newKeys {
+			blocks = append(blocks, e.buildCredentialXML(key, desired.Sources[key].Add, unit))
+		}
+		return strings.Join(blocks, "\n")
+	})
+}
+
+// syncCredentialItems 同步单个包源凭证元素中的凭证项
+func (e *ConfigEditor) syncCredentialItems(sourceElem *parser.ElementPosition, current, desired []types.Credential) error {
+	currentValues := make(map[string]string, len(current))
+	for _, cred := range current {
+		currentValues[cred.Key] = cred.Value
+	}
+
+	elems := make(map[string]*parser.ElementPosition)
+	adds := e.childElements(sourceElem, "add")
+	for _, add := range adds {
+		elems[add.Attributes["key"]] = add
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, cred := range desired {
+		wanted[cred.Key] = true
+		if elem, exists := elems[cred.Key]; exists {
+			if currentValues[cred.Key] != cred.Value {
+				if err := e.setAttribute(elem, "value", cred.Value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := e.insertChild(sourceElem, e.formatElement("add", attr{"key", cred.Key}, attr{"value", cred.Value})); err != nil {
+			return err
+		}
+	}
+
+	for _, add := range adds {
+		if !wanted[add.Attributes["key"]] {
+			e.removeElement(add)
+		}
+	}
+
+	return nil
+}
+
+// syncActive 同步活跃包源，desired 为 nil 时删除 <activePackageSource>
+func (e *ConfigEditor) syncActive(desired *types.PackageSource) error {
+	section, exists := e.findElement(activePackageSourcePath)
+	if desired == nil {
+		if exists {
+			e.removeElement(section)
+		}
+		return nil
+	}
+
+	if exists {
+		if adds := e.childElements(section, "add"); len(adds) > 0 {
+			var current types.PackageSource
+			if active := e.parseResult.Config.ActivePackageSource; active != nil {
+				current = active.Add
+			}
+			if desired.Key != current.Key {
+				if err := e.setAttribute(adds[0], "key", desired.Key); err != nil {
+					return err
+				}
+			}
+			if desired.Value != current.Value {
+				return e.setAttribute(adds[0], "value", desired.Value)
+			}
+			return nil
+		}
+	}
+
+	entryXML := e.formatElement("add", attr{"key", desired.Key}, attr{"value", desired.Value})
+	return e.addToSection("activePackageSource", "", func(string) string { return entryXML })
+}
+
+// syncMapping 同步包源映射
+func (e *ConfigEditor) syncMapping(desired *types.PackageSourceMapping) error {
+	section, exists := e.findElement(packageSourceMappingPath)
+	if desired == nil {
+		if exists {
+			e.removeElement(section)
+		}
+		return nil
+	}
+
+	wanted := make(map[string]bool, len(desired.PackageSource))
+	var newGroups []types.PackageSourceMappingSource
+	for _, group := range desired.PackageSource {
+		wanted[group.Key] = true
+
+		elem, exists := e.findMappingGroup(group.Key)
+		if !exists {
+			newGroups = append(newGroups, group)
+			continue
+		}
+
+		patterns := make(map[string]bool, len(group.Package))
+		for _, pkg := range group.Package {
+			patterns[pkg.Pattern] = true
+		}
+
+		existing := make(map[string]bool)
+		for _, pkg := range e.childElements(elem, "package") {
+			pattern := pkg.Attributes["pattern"]
+			existing[pattern] = true
+			if !patterns[pattern] {
+				e.removeElement(pkg)
+			}
+		}
+
+		for _, pkg := range group.Package {
+			if existing[pkg.Pattern] {
+				continue
+			}
+			existing[pkg.Pattern] = true
+			if err := e.insertChild(elem, e.formatElement("package", attr{"pattern", pkg.Pattern})); err != nil {
+				return err
+			}
+		}
+	}
+
+	if exists {
+		for _, group := range e.childElements(section, "packageSource") {
+			if !wanted[group.Attributes["key"]] {
+				e.removeElement(group)
+			}
+		}
+	}
+
+	if len(newGroups) == 0 {
+		return nil
+	}
+	return e.addToSection("packageSourceMapping", "", func(unit string) string {
+		blocks := make([]string, 0, len(newGroups))
+		for _, group := range newGroups {
+			patterns := make([]string, 0, len(group.Package))
+			for _, pkg := range group.Package {
+				patterns = append(patterns, pkg.Pattern)
+			}
+			blocks = append(blocks, e.buildMappingGroupXML(group.Key, patterns, unit))
+		}
+		return strings.Join(blocks, "\n")
+	})
+}
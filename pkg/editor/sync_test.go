@@ -0,0 +1,129 @@
+package editor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const syncConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="private" value="https://private.example.com/v3/index.json" />
+    <add key="old" value="https://old.example.com" />
+  </packageSources>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+    <add key="http_proxy" value="http://proxy" />
+  </config>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="secret" />
+    </private>
+  </packageSourceCredentials>
+</configuration>`
+
+func TestSyncFromConfig(t *testing.T) {
+	editor := newTestEditor(t, syncConfig)
+	config := editor.GetConfig()
+
+	// 直接修改配置对象：删除、移动、新增包源并修改选项和凭证
+	config.PackageSources.Add = []types.PackageSource{
+		{Key: "private", Value: "https://private.example.com/v3/index.json"},
+		{Key: "new", Value: "https://new.example.com"},
+		{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"},
+	}
+	config.Config.Add = []types.ConfigOption{
+		{Key: "globalPackagesFolder", Value: "/opt/packages"},
+		{Key: "dependencyVersion", Value: "Highest"},
+	}
+	config.PackageSourceCredentials.Sources["private"] = types.SourceCredential{Add: []types.Credential{
+		{Key: "Username", Value: "bob"},
+		{Key: "ClearTextPassword", Value: "secret"},
+	}}
+	config.DisabledPackageSources = &types.DisabledPackageSources{Add: []types.DisabledSource{{Key: "new", Value: "true"}}}
+
+	if err := editor.SyncFromConfig(); err != nil {
+		t.Fatalf("同步配置失败: %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="private" value="https://private.example.com/v3/index.json" />
+    <add key="new" value="https://new.example.com" />
+    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+  </packageSources>
+  <config>
+    <add key="globalPackagesFolder" value="/opt/packages" />
+    <add key="dependencyVersion" value="Highest" />
+  </config>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="bob" />
+      <add key="ClearTextPassword" value="secret" />
+    </private>
+  </packageSourceCredentials>
+  <disabledPackageSources>
+    <add key="new" value="true" />
+  </disabledPackageSources>
+</configuration>`
+	content, err := editor.ApplyEditsValidated()
+	if err != nil {
+		t.Fatalf("校验同步结果失败: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("同步后的内容不符合预期:\n%s\n期望:\n%s", content, expected)
+	}
+}
+
+func TestSyncFromConfigAttributes(t *testing.T) {
+	editor := newTestEditor(t, syncConfig)
+	config := editor.GetConfig()
+
+	config.PackageSources.Add[0].ProtocolVersion = ""
+	config.PackageSources.Add[1].ProtocolVersion = "3"
+	config.PackageSources.Add[2].Value = "https://moved.example.com"
+	config.PackageSources.Add[0], config.PackageSources.Add[2] = config.PackageSources.Add[2], config.PackageSources.Add[0]
+
+	if err := editor.SyncFromConfig(); err != nil {
+		t.Fatalf("同步配置失败: %v", err)
+	}
+
+	expected := strings.Replace(syncConfig, `    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="private" value="https://private.example.com/v3/index.json" />
+    <add key="old" value="https://old.example.com" />`, `    <add key="old" value="https://moved.example.com" />
+    <add key="private" value="https://private.example.com/v3/index.json" protocolVersion="3" />
+    <!-- public feed -->
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />`, 1)
+	content, err := editor.ApplyEditsValidated()
+	if err != nil {
+		t.Fatalf("校验同步结果失败: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("同步后的内容不符合预期:\n%s\n期望:\n%s", content, expected)
+	}
+}
+
+func TestSyncFromConfigNoChanges(t *testing.T) {
+	editor := newTestEditor(t, syncConfig)
+
+	if err := editor.AddPackageSource("extra", "https://extra.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	before := applyEdits(t, editor)
+
+	// 已通过编辑器方法完成的修改在同步后保持不变
+	if err := editor.SyncFromConfig(); err != nil {
+		t.Fatalf("同步配置失败: %v", err)
+	}
+	if got := applyEdits(t, editor); got != before {
+		t.Errorf("同步后内容不应变化:\n%s\n期望:\n%s", got, before)
+	}
+}
@@ -0,0 +1,88 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// editSnapshot 记录事务开始时的编辑器状态
+type editSnapshot struct {
+	config  *types.NuGetConfig
+	edits   []Edit
+	pending map[string]*pendingSection
+}
+
+// Begin 开始一个编辑事务
+//
+// 事务开始后的编辑可以通过 Rollback 整体撤销，内存中的配置对象也会一并恢复。
+// 事务可以嵌套，每个 Begin 都需要对应一个 Commit 或 Rollback。
+func (e *ConfigEditor) Begin() {
+	e.snapshots = append(e.snapshots, editSnapshot{
+		config:  e.parseResult.Config.Clone(),
+		edits:   append([]Edit(nil), e.edits...),
+		pending: clonePendingSections(e.pendingSections),
+	})
+}
+
+// Commit 提交当前事务，保留事务中的所有编辑
+func (e *ConfigEditor) Commit() error {
+	if len(e.snapshots) == 0 {
+		return fmt.Errorf("没有进行中的编辑事务")
+	}
+
+	e.snapshots = e.snapshots[:len(e.snapshots)-1]
+	return nil
+}
+
+// Rollback 回滚当前事务，撤销事务开始后加入的编辑并恢复内存中的配置对象
+func (e *ConfigEditor) Rollback() error {
+	if len(e.snapshots) == 0 {
+		return fmt.Errorf("没有进行中的编辑事务")
+	}
+
+	snapshot := e.snapshots[len(e.snapshots)-1]
+	e.snapshots = e.snapshots[:len(e.snapshots)-1]
+	e.restore(snapshot.config, snapshot.edits, snapshot.pending)
+	return nil
+}
+
+// InTransaction 判断是否有进行中的编辑事务
+func (e *ConfigEditor) InTransaction() bool {
+	return len(e.snapshots) > 0
+}
+
+// Transaction 在事务中执行一组编辑
+//
+// fn 返回错误时回滚其中的所有编辑并返回该错误，否则提交事务。
+// 适用于批量操作，避免部分失败时内存配置与待应用的编辑不一致。
+func (e *ConfigEditor) Transaction(fn func(e *ConfigEditor) error) error {
+	e.Begin()
+	if err := fn(e); err != nil {
+		if rollbackErr := e.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (回滚失败: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	return e.Commit()
+}
+
+// Reset 放弃所有待应用的编辑，并将内存中的配置对象恢复为解析时的状态
+//
+// 进行中的事务也会一并结束。
+func (e *ConfigEditor) Reset() {
+	e.snapshots = nil
+	e.restore(e.original, nil, nil)
+}
+
+// restore 恢复配置对象、编辑队列和尚未应用的配置节插入记录
+//
+// 配置对象原地恢复，调用方之前通过 GetConfig 获取的指针仍然有效。
+func (e *ConfigEditor) restore(config *types.NuGetConfig, edits []Edit, pending map[string]*pendingSection) {
+	if config != nil && e.parseResult.Config != nil {
+		*e.parseResult.Config = *config.Clone()
+	}
+	e.edits = append(make([]Edit, 0, len(edits)), edits...)
+	e.pendingSections = clonePendingSections(pending)
+}
@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransactionRollback(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+	config := editor.GetConfig()
+
+	if err := editor.AddPackageSource("kept", "https://kept.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	editor.Begin()
+	if !editor.InTransaction() {
+		t.Fatal("Begin 之后应处于事务中")
+	}
+	if err := editor.RemovePackageSource("local"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+	if err := editor.UpdatePackageSourceURL("nuget.org", "https://changed.example.com"); err != nil {
+		t.Fatalf("更新包源失败: %v", err)
+	}
+	if err := editor.Rollback(); err != nil {
+		t.Fatalf("回滚失败: %v", err)
+	}
+
+	if editor.InTransaction() {
+		t.Error("Rollback 之后不应处于事务中")
+	}
+	if len(editor.edits) != 1 {
+		t.Errorf("回滚后应只保留事务前的编辑，实际: %d", len(editor.edits))
+	}
+
+	// 之前获取的配置指针应反映回滚后的状态
+	if len(config.PackageSources.Add) != 3 {
+		t.Errorf("回滚后应有 3 个包源，实际: %d", len(config.PackageSources.Add))
+	}
+	if config.PackageSources.Add[0].Value != "https://api.nuget.org/v3/index.json" {
+		t.Errorf("回滚后包源URL未恢复: %s", config.PackageSources.Add[0].Value)
+	}
+
+	if err := editor.Rollback(); err == nil {
+		t.Error("没有事务时回滚应返回错误")
+	}
+	if err := editor.Commit(); err == nil {
+		t.Error("没有事务时提交应返回错误")
+	}
+}
+
+func TestTransactionNested(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	editor.Begin()
+	if err := editor.AddPackageSource("outer", "https://outer.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+
+	editor.Begin()
+	if err := editor.AddPackageSource("inner", "https://inner.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.Rollback(); err != nil {
+		t.Fatalf("回滚内层事务失败: %v", err)
+	}
+
+	if err := editor.Commit(); err != nil {
+		t.Fatalf("提交外层事务失败: %v", err)
+	}
+
+	sources := editor.GetConfig().PackageSources.Add
+	if len(sources) != 3 || sources[2].Key != "outer" {
+		t.Errorf("嵌套事务结果不符合预期: %+v", sources)
+	}
+	if len(editor.edits) != 1 {
+		t.Errorf("应只保留外层事务的编辑，实际: %d", len(editor.edits))
+	}
+}
+
+func TestTransactionFunc(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	errBatch := errors.New("batch failed")
+	err := editor.Transaction(func(e *ConfigEditor) error {
+		if err := e.AddPackageSource("first", "https://first.example.com", ""); err != nil {
+			return err
+		}
+		if err := e.RemovePackageSource("missing"); err != nil {
+			return errBatch
+		}
+		return nil
+	})
+	if !errors.Is(err, errBatch) {
+		t.Fatalf("期望返回批量操作的错误，实际: %v", err)
+	}
+	if len(editor.edits) != 0 || len(editor.GetConfig().PackageSources.Add) != 2 {
+		t.Error("批量操作失败后应回滚所有编辑")
+	}
+
+	err = editor.Transaction(func(e *ConfigEditor) error {
+		return e.AddPackageSource("first", "https://first.example.com", "")
+	})
+	if err != nil {
+		t.Fatalf("批量操作失败: %v", err)
+	}
+	if len(editor.edits) != 1 || editor.InTransaction() {
+		t.Error("批量操作成功后应提交编辑")
+	}
+}
+
+func TestReset(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.RemovePackageSource("local"); err != nil {
+		t.Fatalf("删除包源失败: %v", err)
+	}
+	editor.Begin()
+	if err := editor.DisablePackageSource("nuget.org"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+
+	editor.Reset()
+
+	if editor.InTransaction() || len(editor.edits) != 0 {
+		t.Error("Reset 之后应清空编辑和事务")
+	}
+	config := editor.GetConfig()
+	if len(config.PackageSources.Add) != 2 || config.DisabledPackageSources != nil {
+		t.Errorf("Reset 之后配置应恢复为解析时的状态: %+v", config)
+	}
+	if got := applyEdits(t, editor); got != testConfig {
+		t.Errorf("Reset 之后应用编辑应得到原始内容:\n%s", got)
+	}
+}
@@ -0,0 +1,192 @@
+package editor
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// EditValidationError 表示应用编辑后的内容未通过校验
+type EditValidationError struct {
+	// Edit 导致内容无法解析的编辑，无法定位到单个编辑时为 nil
+	Edit *Edit
+
+	// Reason 校验失败的原因
+	Reason string
+
+	// Err 底层解析错误
+	Err error
+}
+
+// Error 格式化校验错误信息
+func (e *EditValidationError) Error() string {
+	msg := "编辑结果校验失败: " + e.Reason
+	if e.Edit != nil {
+		msg += fmt.Sprintf(" (编辑 %s)", describeEdit(*e.Edit))
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": %v", e.Err)
+	}
+	return msg
+}
+
+// Unwrap 返回底层解析错误
+func (e *EditValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyEditsValidated 应用所有编辑并校验结果
+//
+// 生成的内容会被重新解析，并与编辑器内存中的配置对象比较，二者不等价时返回
+// *EditValidationError。内容无法解析时会逐个应用编辑，定位第一个导致解析失败的编辑，
+// 以便在写入磁盘前发现损坏。
+func (e *ConfigEditor) ApplyEditsValidated() ([]byte, error) {
+	content, _, err := e.applyEditsValidated()
+	return content, err
+}
+
+// applyEditsValidated 应用并校验编辑，同时返回重新解析的结果
+func (e *ConfigEditor) applyEditsValidated() ([]byte, *parser.ParseResult, error) {
+	content, err := e.ApplyEdits()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := reparse(content)
+	if err != nil {
+		return nil, nil, &EditValidationError{
+			Edit:   e.findBreakingEdit(),
+			Reason: "编辑后的内容无法解析",
+			Err:    err,
+		}
+	}
+
+	if mismatch := configMismatch(e.parseResult.Config, result.Config); mismatch != "" {
+		return nil, nil, &EditValidationError{Reason: mismatch}
+	}
+
+	return content, result, nil
+}
+
+// reparse 以位置感知方式重新解析编辑后的内容
+func reparse(content []byte) (*parser.ParseResult, error) {
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = true
+	return p.ParseFromContentWithPositions(content)
+}
+
+// findBreakingEdit 按应用顺序逐个应用编辑，返回第一个导致内容无法解析的编辑
+func (e *ConfigEditor) findBreakingEdit() *Edit {
+	edits, err := resolveEdits(e.edits)
+	if err != nil {
+		return nil
+	}
+
+	for i := range edits {
+		content, err := e.applyEditList(edits[:i+1])
+		if err != nil {
+			return &edits[i]
+		}
+		if _, err := reparse([]byte(content)); err != nil {
+			return &edits[i]
+		}
+	}
+
+	return nil
+}
+
+// configMismatch 比较两个配置对象是否等价，不等价时返回第一个差异的描述
+//
+// 空配置节与不存在的配置节视为等价。
+func configMismatch(expected, actual *types.NuGetConfig) string {
+	checks := []struct {
+		section   string
+		want, got interface{}
+	}{
+		{"packageSources", normalizeSources(expected), normalizeSources(actual)},
+		{"packageSourceCredentials", normalizeCredentials(expected), normalizeCredentials(actual)},
+		{"config", normalizeOptions(expected), normalizeOptions(actual)},
+		{"disabledPackageSources", normalizeDisabled(expected), normalizeDisabled(actual)},
+		{"activePackageSource", normalizeActive(expected), normalizeActive(actual)},
+		{"packageSourceMapping", normalizeMapping(expected), normalizeMapping(actual)},
+	}
+
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.want, c.got) {
+			return fmt.Sprintf("%s 与内存中的配置不一致: 期望 %+v, 实际 %+v", c.section, c.want, c.got)
+		}
+	}
+
+	return ""
+}
+
+// normalizedSection 规范化后的配置节，Items 为空时为 nil
+type normalizedSection struct {
+	Cleared bool
+	Items   []string
+}
+
+func normalizeSources(config *types.NuGetConfig) normalizedSection {
+	section := normalizedSection{Cleared: config.PackageSources.IsCleared()}
+	for _, source := range config.PackageSources.Add {
+		section.Items = append(section.Items, fmt.Sprintf("%s=%s;%s", source.Key, source.Value, source.ProtocolVersion))
+	}
+	return section
+}
+
+func normalizeCredentials(config *types.NuGetConfig) map[string][]types.Credential {
+	if config.PackageSourceCredentials == nil || len(config.PackageSourceCredentials.Sources) == 0 {
+		return nil
+	}
+
+	creds := make(map[string][]types.Credential, len(config.PackageSourceCredentials.Sources))
+	for key, cred := range config.PackageSourceCredentials.Sources {
+		creds[key] = append([]types.Credential(nil), cred.Add...)
+	}
+	return creds
+}
+
+func normalizeOptions(config *types.NuGetConfig) normalizedSection {
+	var section normalizedSection
+	if config.Config != nil {
+		section.Cleared = config.Config.IsCleared()
+		for _, option := range config.Config.Add {
+			section.Items = append(section.Items, option.Key+"="+option.Value)
+		}
+	}
+	return section
+}
+
+func normalizeDisabled(config *types.NuGetConfig) normalizedSection {
+	var section normalizedSection
+	if config.DisabledPackageSources != nil {
+		section.Cleared = config.DisabledPackageSources.IsCleared()
+		for _, source := range config.DisabledPackageSources.Add {
+			section.Items = append(section.Items, source.Key+"="+source.Value)
+		}
+	}
+	return section
+}
+
+func normalizeActive(config *types.NuGetConfig) *types.PackageSource {
+	if config.ActivePackageSource == nil || config.ActivePackageSource.Add == (types.PackageSource{}) {
+		return nil
+	}
+	active := config.ActivePackageSource.Add
+	return &active
+}
+
+func normalizeMapping(config *types.NuGetConfig) normalizedSection {
+	var section normalizedSection
+	if config.PackageSourceMapping != nil {
+		section.Cleared = config.PackageSourceMapping.IsCleared()
+		for _, source := range config.PackageSourceMapping.PackageSource {
+			for _, pkg := range source.Package {
+				section.Items = append(section.Items, source.Key+"="+pkg.Pattern)
+			}
+		}
+	}
+	return section
+}
@@ -0,0 +1,71 @@
+package editor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+)
+
+func TestApplyEditsValidated(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.AddPackageSource("extra", "https://extra.example.com", ""); err != nil {
+		t.Fatalf("添加包源失败: %v", err)
+	}
+	if err := editor.DisablePackageSource("local"); err != nil {
+		t.Fatalf("禁用包源失败: %v", err)
+	}
+
+	content, err := editor.ApplyEditsValidated()
+	if err != nil {
+		t.Fatalf("校验编辑结果失败: %v", err)
+	}
+	if string(content) != applyEdits(t, editor) {
+		t.Error("校验通过时应返回与 ApplyEdits 相同的内容")
+	}
+}
+
+func TestApplyEditsValidatedBreakingEdit(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	if err := editor.UpdatePackageSourceURL("local", "D:\\Packages"); err != nil {
+		t.Fatalf("更新包源失败: %v", err)
+	}
+	offset := strings.Index(testConfig, "<config>")
+	breaking := Edit{
+		Range:   parser.Range{Start: parser.Position{Offset: offset}, End: parser.Position{Offset: offset + len("<config>")}},
+		NewText: "<config",
+		Type:    "update",
+	}
+	editor.edits = append(editor.edits, breaking)
+
+	_, err := editor.ApplyEditsValidated()
+	var validationErr *EditValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回 EditValidationError，实际: %v", err)
+	}
+	if validationErr.Edit == nil || *validationErr.Edit != breaking {
+		t.Errorf("应定位到导致解析失败的编辑，实际: %+v", validationErr.Edit)
+	}
+	if validationErr.Err == nil {
+		t.Error("应包含底层解析错误")
+	}
+}
+
+func TestApplyEditsValidatedMismatch(t *testing.T) {
+	editor := newTestEditor(t, testConfig)
+
+	// 绕过编辑器直接修改内存配置，文本编辑与配置对象不再一致
+	editor.GetConfig().PackageSources.Add[0].Value = "https://changed.example.com"
+
+	_, err := editor.ApplyEditsValidated()
+	var validationErr *EditValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("期望返回 EditValidationError，实际: %v", err)
+	}
+	if !strings.Contains(validationErr.Reason, "packageSources") || validationErr.Edit != nil {
+		t.Errorf("校验错误不符合预期: %v", err)
+	}
+}
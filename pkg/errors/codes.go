@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code 稳定的错误码，形如 "NUGETCFG001"
+//
+// 错误码一经发布不再改变含义，也不会被重新分配，命令行工具、IDE 集成和 CI 注解
+// 可以依据错误码处理错误，而不必匹配可能变化的错误信息。
+//
+//   - NUGETCFG0xx: 配置内容的问题，由解析器的诊断信息、校验规则和修改配置的操作共用
+//   - NUGETCFG1xx: 配置文件的读取、格式和序列化问题
+//   - NUGETCFG2xx: 管理配置的操作失败
+type Code string
+
+// 配置内容的问题
+const (
+	// CodeDuplicateSourceKey 包源名称重复
+	CodeDuplicateSourceKey Code = "NUGETCFG001"
+	// CodeEmptySourceKey 包源名称为空
+	CodeEmptySourceKey Code = "NUGETCFG002"
+	// CodeInvalidSourceValue 包源地址为空或不是合法的 URL
+	CodeInvalidSourceValue Code = "NUGETCFG003"
+	// CodeOrphanedEntry 配置项引用了未定义的包源
+	CodeOrphanedEntry Code = "NUGETCFG004"
+	// CodeInvalidConfigValue 已知配置选项的取值不合法
+	CodeInvalidConfigValue Code = "NUGETCFG005"
+	// CodeInsecureSource 包源通过未加密的 http:// 访问
+	CodeInsecureSource Code = "NUGETCFG006"
+	// CodeUnresolvedSourcePath 本地路径包源无法解析
+	CodeUnresolvedSourcePath Code = "NUGETCFG007"
+	// CodeCleartextPassword 密码以明文存储
+	CodeCleartextPassword Code = "NUGETCFG008"
+	// CodePasswordInProjectConfig 项目级配置中包含密码
+	CodePasswordInProjectConfig Code = "NUGETCFG009"
+	// CodeMissingAuthenticationTypes 凭证没有限制认证方式
+	CodeMissingAuthenticationTypes Code = "NUGETCFG010"
+	// CodeDuplicateEntry 包源名称以外的重复项，如重复的凭证、映射或配置选项
+	CodeDuplicateEntry Code = "NUGETCFG011"
+	// CodeInvalidAttributeValue 属性值格式不正确，如不支持的 protocolVersion
+	CodeInvalidAttributeValue Code = "NUGETCFG012"
+	// CodeMissingAttribute 元素缺少必需的属性
+	CodeMissingAttribute Code = "NUGETCFG013"
+	// CodeUnknownElement 未知的配置节、元素、属性或凭证键
+	CodeUnknownElement Code = "NUGETCFG014"
+	// CodeUnknownConfigOption 未知的配置选项
+	CodeUnknownConfigOption Code = "NUGETCFG015"
+	// CodeMissingRequiredElement 缺少必需的元素，如没有定义任何包源
+	CodeMissingRequiredElement Code = "NUGETCFG016"
+	// CodeDeprecated 使用了已弃用的配置，如 nuget.org 的 V2 源
+	CodeDeprecated Code = "NUGETCFG017"
+	// CodeSuspiciousWhitespace 键名或值的首尾有空白字符，NuGet 会原样使用这些空白
+	CodeSuspiciousWhitespace Code = "NUGETCFG018"
+)
+
+// 配置文件的读取、格式和序列化问题
+const (
+	// CodeConfigFileNotFound 找不到配置文件
+	CodeConfigFileNotFound Code = "NUGETCFG101"
+	// CodeEmptyConfigFile 配置文件为空
+	CodeEmptyConfigFile Code = "NUGETCFG102"
+	// CodeInvalidConfigFormat 配置文件不是格式正确的 XML
+	CodeInvalidConfigFormat Code = "NUGETCFG103"
+	// CodeXMLParsing XML 无法解码为配置
+	CodeXMLParsing Code = "NUGETCFG104"
+	// CodeInsecureContent 配置文件包含 DOCTYPE 声明或嵌套过深等不安全内容
+	CodeInsecureContent Code = "NUGETCFG105"
+	// CodeInputTooLarge 配置内容超过允许的最大大小
+	CodeInputTooLarge Code = "NUGETCFG106"
+	// CodeIO 读写配置文件失败
+	CodeIO Code = "NUGETCFG107"
+	// CodeSerialization 配置无法序列化为 XML、JSON、YAML 或 TOML
+	CodeSerialization Code = "NUGETCFG108"
+)
+
+// 管理配置的操作失败
+const (
+	// CodeSourceNotFound 指定的包源不存在
+	CodeSourceNotFound Code = "NUGETCFG201"
+	// CodeSourceExists 指定的包源已经存在
+	CodeSourceExists Code = "NUGETCFG202"
+	// CodeIncompleteCredentials 凭证缺少用户名或密码
+	CodeIncompleteCredentials Code = "NUGETCFG203"
+	// CodeEmptyConfigOptionKey 配置选项的键名为空
+	CodeEmptyConfigOptionKey Code = "NUGETCFG204"
+	// CodeInvalidProxy 代理设置不合法
+	CodeInvalidProxy Code = "NUGETCFG205"
+	// CodeIndexOutOfRange 位置超出范围
+	CodeIndexOutOfRange Code = "NUGETCFG206"
+	// CodeMergeConflict 合并配置时同一个键的值冲突
+	CodeMergeConflict Code = "NUGETCFG207"
+	// CodeEditFailed 保留格式保存时无法将修改应用到原文件
+	CodeEditFailed Code = "NUGETCFG208"
+)
+
+// Coder 由带有错误码的错误实现
+type Coder interface {
+	// Code 返回错误码，没有时返回空字符串
+	Code() Code
+}
+
+// CodeOf 返回错误链中第一个带有错误码的错误的错误码，没有时返回空字符串
+func CodeOf(err error) Code {
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+	return ""
+}
+
+// codedError 带有错误码的错误
+type codedError struct {
+	code Code
+	err  error
+}
+
+// Error 返回底层错误的信息，错误码不会出现在信息中
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap 返回底层错误，支持 errors.Is 和 errors.As
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// Code 返回错误码
+func (e *codedError) Code() Code {
+	return e.code
+}
+
+// Errorf 创建带有错误码的错误，format 和 args 的用法与 fmt.Errorf 相同，支持 %w
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &codedError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// WithCode 为错误附加错误码，err 为 nil 时返回 nil
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// newSentinel 创建带有错误码的哨兵错误
+func newSentinel(code Code, message string) error {
+	return &codedError{code: code, err: errors.New(message)}
+}
+
+// Code 返回基础错误的错误码
+func (e *ParseError) Code() Code {
+	return CodeOf(e.BaseErr)
+}
+
+// Code 返回底层错误的错误码
+func (e *ConfigError) Code() Code {
+	return CodeOf(e.Err)
+}
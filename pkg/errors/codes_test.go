@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("plain"), ""},
+		{"sentinel", ErrConfigFileNotFound, CodeConfigFileNotFound},
+		{"wrapped sentinel", fmt.Errorf("open: %w", ErrEmptyConfigFile), CodeEmptyConfigFile},
+		{"Errorf", Errorf(CodeSourceNotFound, "package source '%s' not found", "x"), CodeSourceNotFound},
+		{"WithCode", WithCode(CodeIO, errors.New("disk full")), CodeIO},
+		{"outermost code wins", WithCode(CodeXMLParsing, ErrInvalidConfigFormat), CodeXMLParsing},
+		{"parse error", NewParseError(ErrInvalidConfigFormat, 1, 2, "bad"), CodeInvalidConfigFormat},
+		{"config error", WrapConfigError(OpRead, "NuGet.Config", ErrConfigFileNotFound), CodeConfigFileNotFound},
+		{"error list", ErrorList{errors.New("plain"), ErrEmptyConfigFile}, CodeEmptyConfigFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodedErrorPreservesChain(t *testing.T) {
+	base := errors.New("permission denied")
+	err := Errorf(CodeIO, "failed to write: %w", base)
+	if err.Error() != "failed to write: permission denied" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Error("errors.Is() should find the wrapped error")
+	}
+	if !IsNotFoundError(fmt.Errorf("load: %w", ErrConfigFileNotFound)) {
+		t.Error("IsNotFoundError() should still match the coded sentinel")
+	}
+	if WithCode(CodeIO, nil) != nil {
+		t.Error("WithCode(nil) should return nil")
+	}
+	if ErrConfigFileNotFound.Error() != "nuget config file not found" {
+		t.Errorf("sentinel message = %q", ErrConfigFileNotFound.Error())
+	}
+}
@@ -6,21 +6,34 @@ import (
 	"fmt"
 )
 
+// 哨兵错误都带有错误码，可以用 CodeOf 获取
 var (
 	// ErrInvalidConfigFormat 表示配置文件格式不正确的错误
-	ErrInvalidConfigFormat = errors.New("invalid nuget config format")
+	ErrInvalidConfigFormat = newSentinel(CodeInvalidConfigFormat, "invalid nuget config format")
 
 	// ErrConfigFileNotFound 表示找不到配置文件的错误
-	ErrConfigFileNotFound = errors.New("nuget config file not found")
+	ErrConfigFileNotFound = newSentinel(CodeConfigFileNotFound, "nuget config file not found")
 
 	// ErrEmptyConfigFile 表示配置文件为空的错误
-	ErrEmptyConfigFile = errors.New("empty nuget config file")
+	ErrEmptyConfigFile = newSentinel(CodeEmptyConfigFile, "empty nuget config file")
 
 	// ErrXMLParsing 表示XML解析错误
-	ErrXMLParsing = errors.New("xml parsing error")
+	ErrXMLParsing = newSentinel(CodeXMLParsing, "xml parsing error")
 
 	// ErrMissingRequiredElement 表示缺少必需元素的错误
-	ErrMissingRequiredElement = errors.New("missing required element in config")
+	ErrMissingRequiredElement = newSentinel(CodeMissingRequiredElement, "missing required element in config")
+
+	// ErrUnknownElement 表示严格模式下配置文件包含未知配置节、元素或属性的错误
+	ErrUnknownElement = newSentinel(CodeUnknownElement, "unknown element in config")
+
+	// ErrInsecureContent 表示配置文件包含 DOCTYPE 声明或嵌套过深等不安全内容的错误
+	ErrInsecureContent = newSentinel(CodeInsecureContent, "insecure content in config")
+
+	// ErrInputTooLarge 表示配置内容超过允许的最大大小的错误
+	ErrInputTooLarge = newSentinel(CodeInputTooLarge, "config input too large")
+
+	// ErrMergeConflict 表示合并配置时同一个键在两个配置中的值不同的错误
+	ErrMergeConflict = newSentinel(CodeMergeConflict, "merge conflict")
 )
 
 // ParseError 解析错误结构，提供额外上下文信息
@@ -28,14 +41,38 @@ type ParseError struct {
 	// BaseErr 基础错误
 	BaseErr error
 
-	// Line 出错的行号
+	// Line 出错的行号，从1开始，未知时为0
 	Line int
 
-	// Position 出错的位置
+	// Position 出错的列号，以字节计，从1开始，未知时为0
 	Position int
 
+	// Offset 出错位置的字节偏移量，从0开始，仅在 Line 大于0时有意义
+	Offset int
+
 	// Context 错误上下文信息
 	Context string
+
+	// 以下为结构化字段，便于使用方自行生成提示信息而不必解析 Context，未知时为空。
+	// 由 parser 包的诊断信息转换而来时与 parser.Diagnostic 的同名字段相同。
+
+	// Path 出错元素的路径，如 "configuration/packageSources/add[1]"
+	Path string
+
+	// Section 出错的配置节，如 "packageSources"
+	Section string
+
+	// Key 出错的 key，如包源名称
+	Key string
+
+	// Expected 期望的名称、类型或值
+	Expected string
+
+	// Actual 实际的名称或值
+	Actual string
+
+	// Rule 发现问题的规则，如 "unknown-section"
+	Rule string
 }
 
 // Error 格式化解析错误信息
@@ -64,6 +101,65 @@ func NewParseError(baseErr error, line, position int, context string) *ParseErro
 	}
 }
 
+// 配置文件操作的名称，用于 ConfigError.Op
+const (
+	// OpRead 读取配置文件
+	OpRead = "read"
+	// OpParse 解析配置文件的内容
+	OpParse = "parse"
+	// OpValidate 校验配置文件
+	OpValidate = "validate"
+)
+
+// ConfigError 记录出错的配置文件路径和操作
+//
+// 从文件解析配置的函数返回的错误都包装为 ConfigError，便于在处理多个配置文件时
+// 区分出错的文件。底层错误仍然可以用 errors.Is 和 errors.As 判断，例如
+// errors.Is(err, ErrConfigFileNotFound) 和 errors.As(err, &parseErr)。
+type ConfigError struct {
+	// Op 出错的操作，如 OpRead、OpParse
+	Op string
+
+	// Path 配置文件的路径
+	Path string
+
+	// Err 底层错误
+	Err error
+}
+
+// Error 格式化错误信息，形如 "parse /path/to/NuGet.Config: <底层错误>"
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap 返回底层错误，支持 errors.Is 和 errors.As
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// WrapConfigError 将错误包装为 ConfigError
+//
+// err 为 nil 时返回 nil；err 已经是同一文件的 ConfigError 时原样返回，避免重复包装。
+func WrapConfigError(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var configErr *ConfigError
+	if errors.As(err, &configErr) && configErr.Path == path {
+		return err
+	}
+	return &ConfigError{Op: op, Path: path, Err: err}
+}
+
+// ConfigErrorPath 返回错误链中第一个 ConfigError 的文件路径，没有时返回空字符串
+func ConfigErrorPath(err error) string {
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		return configErr.Path
+	}
+	return ""
+}
+
 // IsNotFoundError 判断是否为找不到配置文件的错误
 func IsNotFoundError(err error) bool {
 	return errors.Is(err, ErrConfigFileNotFound)
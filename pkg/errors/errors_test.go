@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -149,3 +150,41 @@ func TestErrorPredicates(t *testing.T) {
 		}
 	})
 }
+
+func TestConfigError(t *testing.T) {
+	parseErr := NewParseError(ErrInvalidConfigFormat, 3, 5, "unexpected EOF")
+	err := WrapConfigError(OpParse, "/tmp/NuGet.Config", parseErr)
+
+	want := "parse /tmp/NuGet.Config: parse error at line 3 position 5: unexpected EOF - invalid nuget config format"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if !errors.Is(err, ErrInvalidConfigFormat) || !IsFormatError(err) || !IsParseError(err) {
+		t.Error("ConfigError should unwrap to the underlying errors")
+	}
+	var target *ParseError
+	if !errors.As(err, &target) || target.Line != 3 {
+		t.Errorf("errors.As() ParseError = %v", target)
+	}
+	if path := ConfigErrorPath(fmt.Errorf("loading: %w", err)); path != "/tmp/NuGet.Config" {
+		t.Errorf("ConfigErrorPath() = %q", path)
+	}
+	if path := ConfigErrorPath(ErrXMLParsing); path != "" {
+		t.Errorf("ConfigErrorPath() without ConfigError = %q, want empty", path)
+	}
+
+	// 同一文件不重复包装，不同文件保留内层的路径
+	if again := WrapConfigError(OpRead, "/tmp/NuGet.Config", err); again != err {
+		t.Errorf("WrapConfigError() wrapped the same file twice: %v", again)
+	}
+	outer := WrapConfigError(OpParse, "/tmp/other.config", err)
+	if !strings.HasPrefix(outer.Error(), "parse /tmp/other.config: parse /tmp/NuGet.Config:") {
+		t.Errorf("WrapConfigError() for another file = %v", outer)
+	}
+	if WrapConfigError(OpParse, "/tmp/NuGet.Config", nil) != nil {
+		t.Error("WrapConfigError(nil) should return nil")
+	}
+	if !IsNotFoundError(WrapConfigError(OpRead, "/tmp/missing.config", ErrConfigFileNotFound)) {
+		t.Error("IsNotFoundError() should see through ConfigError")
+	}
+}
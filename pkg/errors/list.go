@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Locator 由能够给出出错位置的错误实现，ErrorList.Sorted 据此排序
+type Locator interface {
+	// Location 返回出错的行号和列号，从1开始，未知时为0
+	Location() (line, column int)
+}
+
+// Location 返回解析错误的行号和列号
+func (e *ParseError) Location() (line, column int) {
+	return e.Line, e.Position
+}
+
+// ErrorList 多个错误的集合
+//
+// 宽松解析、校验和批量操作可以用 ErrorList 一次报告所有问题，而不是只返回第一个。
+// ErrorList 实现了 Unwrap() []error，errors.Is 和 errors.As 会检查其中的每个错误。
+// 返回错误时应使用 Err，以免空列表被当作非 nil 的 error。
+type ErrorList []error
+
+// Add 添加错误，nil 会被忽略，嵌套的 ErrorList 会被展开
+func (l *ErrorList) Add(err error) {
+	if err == nil {
+		return
+	}
+	if nested, ok := err.(ErrorList); ok {
+		for _, e := range nested {
+			l.Add(e)
+		}
+		return
+	}
+	*l = append(*l, err)
+}
+
+// Len 返回错误的数量
+func (l ErrorList) Len() int {
+	return len(l)
+}
+
+// Err 列表为空时返回 nil，否则返回列表本身
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// Sorted 返回按文件路径、行号和列号排序的副本
+//
+// 文件路径来自错误链中的 ConfigError，位置来自实现了 Locator 的错误。
+// 没有位置的错误排在同一文件中有位置的错误之后，其余错误保持原有的相对顺序。
+func (l ErrorList) Sorted() ErrorList {
+	sorted := make(ErrorList, len(l))
+	copy(sorted, l)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, li, ci := errorLocation(sorted[i])
+		pj, lj, cj := errorLocation(sorted[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if (li == 0) != (lj == 0) {
+			return lj == 0
+		}
+		if li != lj {
+			return li < lj
+		}
+		return ci < cj
+	})
+	return sorted
+}
+
+// Error 返回错误信息，多个错误时每行一个
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d errors:", len(l))
+	for _, err := range l {
+		sb.WriteString("\n\t")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap 返回所有错误，支持 errors.Is 和 errors.As
+func (l ErrorList) Unwrap() []error {
+	return l
+}
+
+// errorLocation 返回错误的文件路径和位置，未知的部分为零值
+func errorLocation(err error) (path string, line, column int) {
+	path = ConfigErrorPath(err)
+	var locator Locator
+	if errors.As(err, &locator) {
+		line, column = locator.Location()
+	}
+	return path, line, column
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorList(t *testing.T) {
+	var list ErrorList
+	if list.Err() != nil {
+		t.Fatal("Err() of an empty list should be nil")
+	}
+
+	list.Add(nil)
+	list.Add(WrapConfigError(OpParse, "b.config", NewParseError(ErrXMLParsing, 2, 1, "second")))
+	list.Add(ErrMergeConflict)
+	list.Add(ErrorList{
+		WrapConfigError(OpParse, "a.config", ErrEmptyConfigFile),
+		WrapConfigError(OpParse, "b.config", NewParseError(ErrInvalidConfigFormat, 1, 7, "first")),
+	})
+	if list.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4 (nil ignored, nested list flattened)", list.Len())
+	}
+
+	err := list.Err()
+	if !errors.Is(err, ErrMergeConflict) || !errors.Is(err, ErrEmptyConfigFile) || !IsParseError(err) {
+		t.Error("errors.Is and errors.As should check every error in the list")
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "4 errors:\n\t") || strings.Count(msg, "\n\t") != 4 {
+		t.Errorf("Error() = %q", msg)
+	}
+	if msg := (ErrorList{ErrMergeConflict}).Error(); msg != ErrMergeConflict.Error() {
+		t.Errorf("Error() of a single error = %q", msg)
+	}
+
+	var got []string
+	for _, e := range list.Sorted() {
+		path, line, column := errorLocation(e)
+		got = append(got, fmt.Sprintf("%s:%d:%d", path, line, column))
+	}
+	want := []string{":0:0", "a.config:0:0", "b.config:1:7", "b.config:2:1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Sorted() = %v, want %v", got, want)
+	}
+	if list[0] != list.Sorted()[3] || list[1] != ErrMergeConflict {
+		t.Error("Sorted() should not modify the original list")
+	}
+}
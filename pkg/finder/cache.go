@@ -0,0 +1,85 @@
+package finder
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// lookupCache 缓存配置文件的查找结果，以所在目录的修改时间和大小判断是否失效
+type lookupCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry 一个候选路径的查找结果
+type cacheEntry struct {
+	dir    dirStamp
+	path   string
+	exists bool
+}
+
+// dirStamp 目录的状态，目录中增加、删除或重命名文件时会发生变化
+type dirStamp struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// EnableCache 开启查找结果缓存，适用于每个请求都要查找配置文件的场景
+//
+// 缓存以候选路径为键，每次查找只检查所在目录的修改时间和大小，目录未变化时
+// 直接使用上次的结果。使用 FS 时目录可能没有修改时间，修改 FS 后需要调用 Invalidate。
+// EnableCache 应在开始查找之前调用，开启后查找方法可以并发调用。
+func (f *ConfigFinder) EnableCache() {
+	f.cache = &lookupCache{entries: make(map[string]cacheEntry)}
+}
+
+// Invalidate 清空查找结果缓存，未开启缓存时不做任何操作
+func (f *ConfigFinder) Invalidate() {
+	if f.cache == nil {
+		return
+	}
+	f.cache.mu.Lock()
+	f.cache.entries = make(map[string]cacheEntry)
+	f.cache.mu.Unlock()
+}
+
+// findConfigFile 查找配置文件，开启缓存时优先使用缓存的结果
+func (f *ConfigFinder) findConfigFile(filePath string) (string, bool) {
+	if f.cache == nil {
+		return f.lookupConfigFile(filePath)
+	}
+
+	stamp := f.dirStamp(filePath)
+	f.cache.mu.Lock()
+	entry, cached := f.cache.entries[filePath]
+	f.cache.mu.Unlock()
+	if cached && entry.dir == stamp {
+		return entry.path, entry.exists
+	}
+
+	found, exists := f.lookupConfigFile(filePath)
+	f.cache.mu.Lock()
+	f.cache.entries[filePath] = cacheEntry{dir: stamp, path: found, exists: exists}
+	f.cache.mu.Unlock()
+	return found, exists
+}
+
+// dirStamp 返回文件所在目录的状态
+func (f *ConfigFinder) dirStamp(filePath string) dirStamp {
+	var info fs.FileInfo
+	var err error
+	if f.FS != nil {
+		info, err = fs.Stat(f.FS, path.Dir(filePath))
+	} else {
+		info, err = os.Stat(filepath.Dir(filePath))
+	}
+	if err != nil {
+		return dirStamp{}
+	}
+	return dirStamp{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
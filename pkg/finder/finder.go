@@ -2,8 +2,11 @@
 package finder
 
 import (
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
 	"github.com/scagogogo/nuget-config-parser/pkg/utils"
@@ -13,6 +16,40 @@ import (
 type ConfigFinder struct {
 	// 环境变量名，用于自定义配置文件位置
 	EnvVariableName string
+
+	// FS 查找时使用的文件系统，为 nil 时使用操作系统文件系统。
+	// 设置后所有路径都会转换为 fs.FS 使用的斜杠分隔的相对路径，如 "/etc/NuGet/NuGet.Config"
+	// 对应 "etc/NuGet/NuGet.Config"，返回的路径也是这种形式。
+	FS fs.FS
+
+	// Locations 替代默认搜索位置的位置列表，为 nil 时使用 constants.GetDefaultConfigLocations
+	Locations []string
+
+	// AdditionalLocations 在默认位置之前搜索的额外位置，例如容器中挂载的 /config 目录
+	AdditionalLocations []string
+
+	// BoundaryMarkers FindProjectConfig 向上查找的边界标记，支持通配符，例如 ".git" 或 "*.sln"。
+	// 包含任一标记的目录仍会被检查，但不再继续查找其父目录
+	BoundaryMarkers []string
+
+	// RootDir FindProjectConfig 向上查找的最高目录，为空时查找到文件系统的根目录
+	RootDir string
+
+	// ScanIgnore ScanTree 跳过的目录名模式，为 nil 时使用 DefaultScanIgnore
+	ScanIgnore []string
+
+	// Platform 决定用户级和机器级配置位置的操作系统信息，为 nil 时使用 SystemPlatform
+	Platform Platform
+
+	// WorkingDir 解析相对路径使用的当前目录，为空时使用进程的当前目录。
+	// 与 FS 一起使用时可以在内存文件系统中模拟项目目录
+	WorkingDir string
+
+	// HomeDir 用户主目录，为空时使用 Platform 返回的主目录
+	HomeDir string
+
+	// cache 查找结果缓存，由 EnableCache 创建
+	cache *lookupCache
 }
 
 // NewConfigFinder 创建新的配置文件查找器
@@ -22,35 +59,120 @@ func NewConfigFinder() *ConfigFinder {
 	}
 }
 
+// NewConfigFinderFS 创建在指定文件系统中查找的配置文件查找器，
+// 可用于嵌入的测试数据、内存文件系统以及只读容器
+func NewConfigFinderFS(fsys fs.FS) *ConfigFinder {
+	finder := NewConfigFinder()
+	finder.FS = fsys
+	return finder
+}
+
+// NewConfigFinderWithPaths 创建只在指定位置查找的配置文件查找器
+//
+// 位置可以是配置文件路径，也可以是目录，目录表示其中的 NuGet.Config。
+// 环境变量指定的配置文件仍然优先。
+func NewConfigFinderWithPaths(paths ...string) *ConfigFinder {
+	finder := NewConfigFinder()
+	finder.Locations = append([]string{}, paths...)
+	return finder
+}
+
 // GetConfigFileSearchLocations 获取可能的配置文件位置列表
+//
+// 顺序为环境变量指定的文件、AdditionalLocations，最后是 Locations 或默认搜索位置。
 func (f *ConfigFinder) GetConfigFileSearchLocations() []string {
-	var locations []string
+	candidates := f.searchLocations()
+	locations := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		locations = append(locations, candidate.Path)
+	}
+	return locations
+}
+
+// LocateConfigFiles 按搜索顺序返回所有候选配置文件及其级别和是否存在
+//
+// 与 FindAllConfigFiles 不同，不存在的候选位置也会返回，Exists 为 false。
+// 存在的文件的 Path 与 FindAllConfigFiles 返回的路径相同。
+func (f *ConfigFinder) LocateConfigFiles() []FoundConfig {
+	candidates := f.searchLocations()
+	for i, candidate := range candidates {
+		if absPath, ok := f.resolve(candidate.Path); ok {
+			candidates[i].Path, candidates[i].Exists = f.findConfigFile(absPath)
+		}
+	}
+	return candidates
+}
+
+// searchLocations 返回带级别的搜索位置，Exists 未设置
+func (f *ConfigFinder) searchLocations() []FoundConfig {
+	var locations []FoundConfig
 
 	// 1. 先检查环境变量
-	envPath := os.Getenv(f.EnvVariableName)
-	if envPath != "" && utils.FileExists(envPath) {
-		locations = append(locations, envPath)
+	envPath := f.getenv(f.EnvVariableName)
+	if envPath != "" && f.fileExists(envPath) {
+		locations = append(locations, FoundConfig{Path: envPath, Level: LevelEnvironment})
+	}
+
+	// 2. 添加额外的搜索位置
+	for _, location := range f.AdditionalLocations {
+		locations = append(locations, FoundConfig{Path: f.configFileIn(location), Level: LevelCustom})
 	}
 
-	// 2. 添加默认搜索位置
-	locations = append(locations, constants.GetDefaultConfigLocations()...)
+	// 3. 添加默认搜索位置
+	if f.Locations != nil {
+		for _, location := range f.Locations {
+			locations = append(locations, FoundConfig{Path: f.configFileIn(location), Level: LevelCustom})
+		}
+		return locations
+	}
+
+	// 当前目录和上层目录中的项目级配置
+	locations = append(locations,
+		FoundConfig{Path: constants.DefaultNuGetConfigFilename, Level: LevelProject},
+		FoundConfig{Path: filepath.Join("..", constants.DefaultNuGetConfigFilename), Level: LevelProject},
+	)
+	if userConfig := f.GetUserConfigFile(); userConfig != "" {
+		locations = append(locations, FoundConfig{Path: userConfig, Level: LevelUser})
+	}
+	if machineConfig := f.GetMachineConfigFile(); machineConfig != "" {
+		locations = append(locations, FoundConfig{Path: machineConfig, Level: LevelMachine})
+	}
 
 	return locations
 }
 
+// configFileIn 位置是目录时返回其中的配置文件路径，否则原样返回
+func (f *ConfigFinder) configFileIn(location string) string {
+	absPath, ok := f.resolve(location)
+	if !ok {
+		return location
+	}
+
+	var info fs.FileInfo
+	var err error
+	if f.FS != nil {
+		info, err = fs.Stat(f.FS, absPath)
+	} else {
+		info, err = os.Stat(absPath)
+	}
+	if err == nil && info.IsDir() {
+		return filepath.Join(location, constants.DefaultNuGetConfigFilename)
+	}
+	return location
+}
+
 // FindConfigFile 寻找第一个存在的配置文件
 func (f *ConfigFinder) FindConfigFile() (string, error) {
 	locations := f.GetConfigFileSearchLocations()
 
 	for _, location := range locations {
-		expandedPath := utils.ExpandEnvVars(location)
-		absPath, err := filepath.Abs(expandedPath)
-		if err != nil {
+		absPath, ok := f.resolve(location)
+		if !ok {
 			continue
 		}
 
-		if utils.FileExists(absPath) {
-			return absPath, nil
+		if configPath, exists := f.findConfigFile(absPath); exists {
+			return configPath, nil
 		}
 	}
 
@@ -63,14 +185,13 @@ func (f *ConfigFinder) FindAllConfigFiles() []string {
 	var existingFiles []string
 
 	for _, location := range locations {
-		expandedPath := utils.ExpandEnvVars(location)
-		absPath, err := filepath.Abs(expandedPath)
-		if err != nil {
+		absPath, ok := f.resolve(location)
+		if !ok {
 			continue
 		}
 
-		if utils.FileExists(absPath) {
-			existingFiles = append(existingFiles, absPath)
+		if configPath, exists := f.findConfigFile(absPath); exists {
+			existingFiles = append(existingFiles, configPath)
 		}
 	}
 
@@ -78,16 +199,52 @@ func (f *ConfigFinder) FindAllConfigFiles() []string {
 }
 
 // FindProjectConfig 在指定目录及其父目录中查找项目级配置文件
+//
+// 配置文件名不区分大小写，返回的路径使用实际的文件名，例如 nuget.config。
+// 设置了 BoundaryMarkers 或 RootDir 时，查找在到达仓库边界后停止，
+// 不会用到仓库以外无关的配置文件。
 func (f *ConfigFinder) FindProjectConfig(startDir string) (string, error) {
-	currentDir, err := filepath.Abs(startDir)
+	configs, err := f.projectConfigs(startDir, true)
 	if err != nil {
 		return "", err
 	}
+	if len(configs) == 0 {
+		return "", os.ErrNotExist
+	}
+	return configs[0], nil
+}
+
+// projectConfigs 从指定目录向上查找项目级配置文件，按从近到远的顺序返回，
+// first 为 true 时找到第一个后即停止
+func (f *ConfigFinder) projectConfigs(startDir string, first bool) ([]string, error) {
+	if f.FS != nil {
+		return f.projectConfigsFS(startDir, first)
+	}
+
+	currentDir, err := filepath.Abs(f.absolute(startDir))
+	if err != nil {
+		return nil, err
+	}
 
+	rootDir := ""
+	if f.RootDir != "" {
+		if rootDir, err = filepath.Abs(f.RootDir); err != nil {
+			return nil, err
+		}
+	}
+
+	var configs []string
 	for {
-		configPath := filepath.Join(currentDir, constants.DefaultNuGetConfigFilename)
-		if utils.FileExists(configPath) {
-			return configPath, nil
+		configPath, exists := f.findConfigFile(filepath.Join(currentDir, constants.DefaultNuGetConfigFilename))
+		if exists {
+			configs = append(configs, configPath)
+			if first {
+				break
+			}
+		}
+
+		if currentDir == rootDir || f.isBoundary(currentDir) {
+			break
 		}
 
 		// 获取父目录
@@ -99,12 +256,145 @@ func (f *ConfigFinder) FindProjectConfig(startDir string) (string, error) {
 		currentDir = parentDir
 	}
 
-	return "", os.ErrNotExist
+	return configs, nil
+}
+
+// projectConfigsFS 在 FS 中从指定目录向上查找项目级配置文件
+func (f *ConfigFinder) projectConfigsFS(startDir string, first bool) ([]string, error) {
+	currentDir, ok := toFSPath(f.absolute(startDir))
+	if !ok {
+		return nil, &fs.PathError{Op: "find", Path: startDir, Err: fs.ErrInvalid}
+	}
+
+	rootDir := ""
+	if f.RootDir != "" {
+		if rootDir, ok = toFSPath(f.RootDir); !ok {
+			return nil, &fs.PathError{Op: "find", Path: f.RootDir, Err: fs.ErrInvalid}
+		}
+	}
+
+	var configs []string
+	for {
+		configPath, exists := f.findConfigFile(path.Join(currentDir, constants.DefaultNuGetConfigFilename))
+		if exists {
+			configs = append(configs, configPath)
+			if first {
+				break
+			}
+		}
+
+		// 到达 FS 的根目录或仓库边界时停止搜索
+		if currentDir == "." || currentDir == rootDir || f.isBoundary(currentDir) {
+			break
+		}
+		currentDir = path.Dir(currentDir)
+	}
+
+	return configs, nil
+}
+
+// FindConfigHierarchy 返回 NuGet 在指定目录中会使用的所有配置文件
+//
+// 结果按优先级从低到高排列：机器级配置、用户级配置，然后是从最上层到最靠近
+// startDir 的项目级配置，可以直接按顺序合并得到最终生效的配置。
+// 只返回存在的文件，项目级配置的查找同样遵循 BoundaryMarkers 和 RootDir。
+func (f *ConfigFinder) FindConfigHierarchy(startDir string) ([]FoundConfig, error) {
+	projects, err := f.projectConfigs(startDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var hierarchy []FoundConfig
+	for _, candidate := range []FoundConfig{
+		{Path: f.GetMachineConfigFile(), Level: LevelMachine},
+		{Path: f.GetUserConfigFile(), Level: LevelUser},
+	} {
+		if candidate.Path == "" {
+			continue
+		}
+		if absPath, ok := f.resolve(candidate.Path); ok {
+			if configPath, exists := f.findConfigFile(absPath); exists {
+				hierarchy = append(hierarchy, FoundConfig{Path: configPath, Level: candidate.Level, Exists: true})
+			}
+		}
+	}
+
+	for i := len(projects) - 1; i >= 0; i-- {
+		hierarchy = append(hierarchy, FoundConfig{Path: projects[i], Level: LevelProject, Exists: true})
+	}
+	return hierarchy, nil
+}
+
+// isBoundary 判断目录中是否存在 BoundaryMarkers 中的任一标记
+func (f *ConfigFinder) isBoundary(dir string) bool {
+	for _, marker := range f.BoundaryMarkers {
+		var matches []string
+		if f.FS != nil {
+			matches, _ = fs.Glob(f.FS, path.Join(dir, marker))
+		} else {
+			matches, _ = filepath.Glob(filepath.Join(dir, marker))
+		}
+		if len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve 展开环境变量并将位置转换为查找使用的路径，
+// 操作系统文件系统中为绝对路径，FS 中为斜杠分隔的相对路径
+func (f *ConfigFinder) resolve(location string) (string, bool) {
+	expandedPath := f.absolute(utils.ExpandEnvVarsWithLookup(location, f.platform().LookupEnv))
+	if f.FS != nil {
+		return toFSPath(expandedPath)
+	}
+
+	absPath, err := filepath.Abs(expandedPath)
+	if err != nil {
+		return "", false
+	}
+	return absPath, true
+}
+
+// absolute 将相对路径按 WorkingDir 解析，未设置 WorkingDir 时原样返回
+func (f *ConfigFinder) absolute(p string) string {
+	if f.WorkingDir == "" || filepath.IsAbs(p) || strings.HasPrefix(filepath.ToSlash(p), "/") {
+		return p
+	}
+	return filepath.Join(f.WorkingDir, p)
+}
+
+// fileExists 判断文件是否存在于查找使用的文件系统中
+func (f *ConfigFinder) fileExists(filePath string) bool {
+	if f.FS == nil {
+		return utils.FileExists(filePath)
+	}
+
+	fsPath, ok := toFSPath(filePath)
+	if !ok {
+		return false
+	}
+	info, err := fs.Stat(f.FS, fsPath)
+	return err == nil && !info.IsDir()
+}
+
+// toFSPath 将操作系统路径转换为 fs.FS 使用的路径，无法表示时返回 false
+func toFSPath(osPath string) (string, bool) {
+	fsPath := filepath.ToSlash(osPath)
+	// 去掉 Windows 盘符和开头的斜杠，FS 的根目录对应文件系统的根目录
+	if volume := filepath.VolumeName(osPath); volume != "" {
+		fsPath = fsPath[len(volume):]
+	}
+	fsPath = path.Clean(strings.TrimLeft(fsPath, "/"))
+	if fsPath == "" {
+		fsPath = "."
+	}
+	return fsPath, fs.ValidPath(fsPath)
 }
 
 // GetUserConfigFile 获取用户级别的配置文件路径
 func (f *ConfigFinder) GetUserConfigFile() string {
-	userConfigDir := getUserConfigDirectory()
+	userConfigDir := f.userConfigDirectory()
 	if userConfigDir == "" {
 		return ""
 	}
@@ -114,7 +404,7 @@ func (f *ConfigFinder) GetUserConfigFile() string {
 
 // GetMachineConfigFile 获取机器级别的配置文件路径
 func (f *ConfigFinder) GetMachineConfigFile() string {
-	systemConfigDir := getSystemConfigDirectory()
+	systemConfigDir := f.systemConfigDirectory()
 	if systemConfigDir == "" {
 		return ""
 	}
@@ -122,23 +412,23 @@ func (f *ConfigFinder) GetMachineConfigFile() string {
 	return filepath.Join(systemConfigDir, constants.GlobalFolderName, constants.DefaultNuGetConfigFilename)
 }
 
-// getUserConfigDirectory 获取用户配置目录
-func getUserConfigDirectory() string {
-	switch os.Getenv("GOOS") {
+// userConfigDirectory 获取用户配置目录
+func (f *ConfigFinder) userConfigDirectory() string {
+	switch f.platform().GOOS() {
 	case "windows":
-		return os.Getenv("APPDATA")
+		return f.getenv("APPDATA")
 	case "darwin":
-		homeDir, err := os.UserHomeDir()
+		homeDir, err := f.homeDir()
 		if err != nil {
 			return ""
 		}
 		return filepath.Join(homeDir, "Library", "Application Support")
 	default:
-		homeDir, err := os.UserHomeDir()
+		homeDir, err := f.homeDir()
 		if err != nil {
 			return ""
 		}
-		configDir := os.Getenv("XDG_CONFIG_HOME")
+		configDir := f.getenv("XDG_CONFIG_HOME")
 		if configDir == "" {
 			configDir = filepath.Join(homeDir, ".config")
 		}
@@ -146,11 +436,11 @@ func getUserConfigDirectory() string {
 	}
 }
 
-// getSystemConfigDirectory 获取系统配置目录
-func getSystemConfigDirectory() string {
-	switch os.Getenv("GOOS") {
+// systemConfigDirectory 获取系统配置目录
+func (f *ConfigFinder) systemConfigDirectory() string {
+	switch f.platform().GOOS() {
 	case "windows":
-		return os.Getenv("ProgramData")
+		return f.getenv("ProgramData")
 	case "darwin":
 		return "/Library/Application Support"
 	default:
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
 	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
@@ -364,3 +365,506 @@ func pathsEqual(path1, path2 string) bool {
 	// 可以在这里添加更多的平台特定路径比较逻辑
 	return false
 }
+
+func TestConfigFinderFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"repo/NuGet.Config":          {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/src/app/main.go":       {Data: []byte("package main")},
+		"etc/NuGet/NuGet.Config":     {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"custom/env-nuget.config":    {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"other/NuGet.Config/ignored": {Data: []byte("directories are not config files")},
+	}
+
+	finder := NewConfigFinderFS(fsys)
+
+	t.Run("FindProjectConfig", func(t *testing.T) {
+		configPath, err := finder.FindProjectConfig("/repo/src/app")
+		if err != nil {
+			t.Fatalf("FindProjectConfig() error = %v", err)
+		}
+		if configPath != "repo/NuGet.Config" {
+			t.Errorf("FindProjectConfig() = %q, want %q", configPath, "repo/NuGet.Config")
+		}
+
+		if _, err := finder.FindProjectConfig("other"); err == nil {
+			t.Error("FindProjectConfig() should not match a directory named NuGet.Config")
+		}
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		cleanup := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", "/custom/env-nuget.config")
+		defer cleanup()
+
+		configPath, err := finder.FindConfigFile()
+		if err != nil {
+			t.Fatalf("FindConfigFile() error = %v", err)
+		}
+		if configPath != "custom/env-nuget.config" {
+			t.Errorf("FindConfigFile() = %q, want %q", configPath, "custom/env-nuget.config")
+		}
+	})
+
+	t.Run("does not touch the OS filesystem", func(t *testing.T) {
+		tempDir := nugetTesting.CreateTempDir(t)
+		defer os.RemoveAll(tempDir)
+		nugetTesting.CreateNuGetConfigFile(t, filepath.Join(tempDir, constants.DefaultNuGetConfigFilename), nugetTesting.ValidNuGetConfig())
+
+		if _, err := finder.FindProjectConfig(tempDir); err == nil {
+			t.Error("FindProjectConfig() should only search the configured FS")
+		}
+	})
+}
+
+func TestConfigFinderWithPaths(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	mountDir := filepath.Join(tempDir, "config")
+	if err := os.MkdirAll(mountDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	mountedConfig := filepath.Join(mountDir, constants.DefaultNuGetConfigFilename)
+	secretConfig := filepath.Join(tempDir, "secret.config")
+	nugetTesting.CreateNuGetConfigFile(t, mountedConfig, nugetTesting.ValidNuGetConfig())
+	nugetTesting.CreateNuGetConfigFile(t, secretConfig, nugetTesting.ValidNuGetConfig())
+
+	cleanup := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", "")
+	defer cleanup()
+
+	finder := NewConfigFinderWithPaths(filepath.Join(tempDir, "missing"), mountDir, secretConfig)
+
+	locations := finder.GetConfigFileSearchLocations()
+	if len(locations) != 3 || locations[1] != mountedConfig {
+		t.Errorf("GetConfigFileSearchLocations() = %v, want directory expanded to %s", locations, mountedConfig)
+	}
+
+	configPath, err := finder.FindConfigFile()
+	if err != nil {
+		t.Fatalf("FindConfigFile() error = %v", err)
+	}
+	if configPath != mountedConfig {
+		t.Errorf("FindConfigFile() = %q, want %q", configPath, mountedConfig)
+	}
+
+	if all := finder.FindAllConfigFiles(); len(all) != 2 || all[1] != secretConfig {
+		t.Errorf("FindAllConfigFiles() = %v", all)
+	}
+
+	// 额外位置在默认位置之前搜索
+	finder = NewConfigFinder()
+	finder.AdditionalLocations = []string{secretConfig}
+	locations = finder.GetConfigFileSearchLocations()
+	if len(locations) == 0 || locations[0] != secretConfig {
+		t.Errorf("GetConfigFileSearchLocations() = %v, want %s first", locations, secretConfig)
+	}
+	if !contains(locations, constants.DefaultNuGetConfigFilename) {
+		t.Error("GetConfigFileSearchLocations() should keep default locations with AdditionalLocations")
+	}
+}
+
+func TestLocateConfigFiles(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("user config location is controlled by XDG_CONFIG_HOME only on Linux")
+	}
+
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	userConfig := filepath.Join(tempDir, "xdg", constants.GlobalFolderName, constants.DefaultNuGetConfigFilename)
+	envConfig := filepath.Join(tempDir, "env.config")
+	nugetTesting.CreateNuGetConfigFile(t, userConfig, nugetTesting.ValidNuGetConfig())
+	nugetTesting.CreateNuGetConfigFile(t, envConfig, nugetTesting.ValidNuGetConfig())
+
+	restoreXDG := nugetTesting.SetupEnv(t, "XDG_CONFIG_HOME", filepath.Join(tempDir, "xdg"))
+	defer restoreXDG()
+	restoreEnv := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", envConfig)
+	defer restoreEnv()
+
+	finder := NewConfigFinder()
+	found := finder.LocateConfigFiles()
+
+	levels := make(map[ConfigLevel][]FoundConfig)
+	for _, config := range found {
+		if !filepath.IsAbs(config.Path) {
+			t.Errorf("LocateConfigFiles() returned relative path %q", config.Path)
+		}
+		levels[config.Level] = append(levels[config.Level], config)
+	}
+
+	if env := levels[LevelEnvironment]; len(env) != 1 || env[0].Path != envConfig || !env[0].Exists {
+		t.Errorf("environment level = %+v", env)
+	}
+	if user := levels[LevelUser]; len(user) != 1 || user[0].Path != userConfig || !user[0].Exists {
+		t.Errorf("user level = %+v", user)
+	}
+	if project := levels[LevelProject]; len(project) != 2 {
+		t.Errorf("project level = %+v, want current and parent directory", project)
+	}
+	if machine := levels[LevelMachine]; len(machine) != 1 || machine[0].Path != finder.GetMachineConfigFile() {
+		t.Errorf("machine level = %+v", machine)
+	}
+
+	// 存在的文件与 FindAllConfigFiles 一致
+	var existing []string
+	for _, config := range found {
+		if config.Exists {
+			existing = append(existing, config.Path)
+		}
+	}
+	if all := finder.FindAllConfigFiles(); strings.Join(all, "|") != strings.Join(existing, "|") {
+		t.Errorf("existing files = %v, FindAllConfigFiles() = %v", existing, all)
+	}
+
+	if LevelUser.String() != "user" || LevelEnvironment.String() != "env" {
+		t.Errorf("ConfigLevel.String() = %q, %q", LevelUser, LevelEnvironment)
+	}
+}
+
+func TestFindProjectConfigBoundary(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	// tempDir/NuGet.Config 位于仓库 tempDir/repo 之外
+	outsideConfig := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, outsideConfig, nugetTesting.ValidNuGetConfig())
+	repoDir := filepath.Join(tempDir, "repo")
+	startDir := filepath.Join(repoDir, "src", "app")
+	if err := os.MkdirAll(startDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	finder := NewConfigFinder()
+	if configPath, err := finder.FindProjectConfig(startDir); err != nil || configPath != outsideConfig {
+		t.Fatalf("FindProjectConfig() without boundary = %q, %v, want %q", configPath, err, outsideConfig)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "App.sln"), nil, 0644); err != nil {
+		t.Fatalf("Failed to create marker: %v", err)
+	}
+	finder.BoundaryMarkers = []string{".git", "*.sln"}
+	if configPath, err := finder.FindProjectConfig(startDir); err == nil {
+		t.Errorf("FindProjectConfig() with boundary marker = %q, want not found", configPath)
+	}
+
+	// 边界目录本身仍会被检查
+	repoConfig := filepath.Join(repoDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, repoConfig, nugetTesting.ValidNuGetConfig())
+	if configPath, err := finder.FindProjectConfig(startDir); err != nil || configPath != repoConfig {
+		t.Errorf("FindProjectConfig() = %q, %v, want %q", configPath, err, repoConfig)
+	}
+
+	finder = NewConfigFinder()
+	finder.RootDir = filepath.Join(repoDir, "src")
+	if configPath, err := finder.FindProjectConfig(startDir); err == nil {
+		t.Errorf("FindProjectConfig() with RootDir = %q, want not found", configPath)
+	}
+
+	fsFinder := NewConfigFinderFS(fstest.MapFS{
+		"NuGet.Config":           {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/.git/HEAD":         {Data: []byte("ref: refs/heads/main")},
+		"repo/src/app/main.cs":   {Data: []byte("")},
+		"other/src/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+	})
+	fsFinder.BoundaryMarkers = []string{".git"}
+	if configPath, err := fsFinder.FindProjectConfig("/repo/src/app"); err == nil {
+		t.Errorf("FindProjectConfig() in FS with boundary marker = %q, want not found", configPath)
+	}
+	if configPath, err := fsFinder.FindProjectConfig("/other/src"); err != nil || configPath != "other/src/NuGet.Config" {
+		t.Errorf("FindProjectConfig() in FS = %q, %v", configPath, err)
+	}
+}
+
+func TestFindConfigFileCaseInsensitive(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	projectDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	lowerConfig := filepath.Join(projectDir, "nuget.config")
+	nugetTesting.CreateNuGetConfigFile(t, lowerConfig, nugetTesting.ValidNuGetConfig())
+
+	finder := NewConfigFinder()
+	configPath, err := finder.FindProjectConfig(projectDir)
+	if err != nil {
+		t.Fatalf("FindProjectConfig() error = %v", err)
+	}
+	if filepath.Base(configPath) != "nuget.config" {
+		t.Errorf("FindProjectConfig() = %q, want actual file name nuget.config", configPath)
+	}
+
+	finder = NewConfigFinderWithPaths(projectDir)
+	if all := finder.FindAllConfigFiles(); len(all) != 1 || filepath.Base(all[0]) != "nuget.config" {
+		t.Errorf("FindAllConfigFiles() = %v", all)
+	}
+
+	fsFinder := NewConfigFinderFS(fstest.MapFS{
+		"a/NUGET.CONFIG": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"b/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"b/nuget.config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+	})
+	for startDir, want := range map[string]string{
+		"/a": "a/NUGET.CONFIG",
+		"/b": "b/nuget.config",
+	} {
+		if configPath, err := fsFinder.FindProjectConfig(startDir); err != nil || configPath != want {
+			t.Errorf("FindProjectConfig(%q) = %q, %v, want %q", startDir, configPath, err, want)
+		}
+	}
+}
+
+func TestScanTree(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	for _, name := range []string{
+		"NuGet.Config",
+		"src/App/nuget.config",
+		"src/Lib/Tests/NuGet.Config",
+		"node_modules/pkg/NuGet.Config",
+		"src/App/bin/Debug/NuGet.Config",
+		"src/App/obj/NuGet.Config",
+	} {
+		nugetTesting.CreateNuGetConfigFile(t, filepath.Join(tempDir, filepath.FromSlash(name)), nugetTesting.ValidNuGetConfig())
+	}
+
+	finder := NewConfigFinder()
+	found, err := finder.ScanTree(tempDir)
+	if err != nil {
+		t.Fatalf("ScanTree() error = %v", err)
+	}
+
+	want := []ScannedConfig{
+		{Path: filepath.Join(tempDir, "NuGet.Config"), Dir: tempDir, Depth: 0},
+		{Path: filepath.Join(tempDir, "src", "App", "nuget.config"), Dir: filepath.Join(tempDir, "src", "App"), Depth: 2},
+		{Path: filepath.Join(tempDir, "src", "Lib", "Tests", "NuGet.Config"), Dir: filepath.Join(tempDir, "src", "Lib", "Tests"), Depth: 3},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("ScanTree() = %+v, want %d configs", found, len(want))
+	}
+	for i := range want {
+		if found[i] != want[i] {
+			t.Errorf("ScanTree()[%d] = %+v, want %+v", i, found[i], want[i])
+		}
+	}
+
+	// 自定义忽略模式替代默认值
+	finder.ScanIgnore = []string{"src"}
+	if found, _ := finder.ScanTree(tempDir); len(found) != 2 {
+		t.Errorf("ScanTree() with custom ignore = %+v, want root and node_modules only", found)
+	}
+
+	fsFinder := NewConfigFinderFS(fstest.MapFS{
+		"repo/NuGet.Config":       {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/a/b/NuGet.Config":   {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/a/obj/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"elsewhere/NuGet.Config":  {Data: []byte(nugetTesting.ValidNuGetConfig())},
+	})
+	found, err = fsFinder.ScanTree("/repo")
+	if err != nil {
+		t.Fatalf("ScanTree() in FS error = %v", err)
+	}
+	wantFS := []ScannedConfig{
+		{Path: "repo/NuGet.Config", Dir: "repo", Depth: 0},
+		{Path: "repo/a/b/NuGet.Config", Dir: "repo/a/b", Depth: 2},
+	}
+	if len(found) != len(wantFS) || found[0] != wantFS[0] || found[1] != wantFS[1] {
+		t.Errorf("ScanTree() in FS = %+v, want %+v", found, wantFS)
+	}
+
+	if _, err := finder.ScanTree(filepath.Join(tempDir, "missing")); err == nil {
+		t.Error("ScanTree() expected error for missing root")
+	}
+}
+
+// fakePlatform 模拟指定操作系统的 Platform
+type fakePlatform struct {
+	goos string
+	home string
+	env  map[string]string
+}
+
+func (p fakePlatform) GOOS() string { return p.goos }
+
+func (p fakePlatform) LookupEnv(key string) (string, bool) {
+	value, ok := p.env[key]
+	return value, ok
+}
+
+func (p fakePlatform) UserHomeDir() (string, error) { return p.home, nil }
+
+func TestConfigFinderPlatform(t *testing.T) {
+	tests := []struct {
+		name        string
+		platform    fakePlatform
+		wantUser    string
+		wantMachine string
+	}{
+		{
+			name:        "windows",
+			platform:    fakePlatform{goos: "windows", env: map[string]string{"APPDATA": "/appdata", "ProgramData": "/programdata"}},
+			wantUser:    filepath.Join("/appdata", "NuGet", "NuGet.Config"),
+			wantMachine: filepath.Join("/programdata", "NuGet", "NuGet.Config"),
+		},
+		{
+			name:        "darwin",
+			platform:    fakePlatform{goos: "darwin", home: "/Users/dev"},
+			wantUser:    filepath.Join("/Users/dev", "Library", "Application Support", "NuGet", "NuGet.Config"),
+			wantMachine: filepath.Join("/Library/Application Support", "NuGet", "NuGet.Config"),
+		},
+		{
+			name:        "linux with XDG_CONFIG_HOME",
+			platform:    fakePlatform{goos: "linux", home: "/home/dev", env: map[string]string{"XDG_CONFIG_HOME": "/xdg"}},
+			wantUser:    filepath.Join("/xdg", "NuGet", "NuGet.Config"),
+			wantMachine: filepath.Join("/etc", "NuGet", "NuGet.Config"),
+		},
+		{
+			name:        "linux",
+			platform:    fakePlatform{goos: "linux", home: "/home/dev"},
+			wantUser:    filepath.Join("/home/dev", ".config", "NuGet", "NuGet.Config"),
+			wantMachine: filepath.Join("/etc", "NuGet", "NuGet.Config"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder := NewConfigFinder()
+			finder.Platform = tt.platform
+
+			if got := finder.GetUserConfigFile(); got != tt.wantUser {
+				t.Errorf("GetUserConfigFile() = %q, want %q", got, tt.wantUser)
+			}
+			if got := finder.GetMachineConfigFile(); got != tt.wantMachine {
+				t.Errorf("GetMachineConfigFile() = %q, want %q", got, tt.wantMachine)
+			}
+
+			locations := finder.GetConfigFileSearchLocations()
+			if !contains(locations, tt.wantUser) || !contains(locations, tt.wantMachine) {
+				t.Errorf("GetConfigFileSearchLocations() = %v", locations)
+			}
+		})
+	}
+
+	// 环境变量从 Platform 读取
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+	envConfig := filepath.Join(tempDir, "env.config")
+	nugetTesting.CreateNuGetConfigFile(t, envConfig, nugetTesting.ValidNuGetConfig())
+
+	finder := NewConfigFinder()
+	finder.Platform = fakePlatform{goos: "linux", home: tempDir, env: map[string]string{"NUGET_CONFIG_FILE": envConfig}}
+	if configPath, err := finder.FindConfigFile(); err != nil || configPath != envConfig {
+		t.Errorf("FindConfigFile() = %q, %v, want %q", configPath, err, envConfig)
+	}
+}
+
+func TestConfigFinderFSWithWorkingDirAndHome(t *testing.T) {
+	fsys := fstest.MapFS{
+		"repo/src/NuGet.Config":               {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/NuGet.Config":                   {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"home/dev/.config/NuGet/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"etc/NuGet/NuGet.Config":              {Data: []byte(nugetTesting.ValidNuGetConfig())},
+	}
+
+	finder := NewConfigFinderFS(fsys)
+	finder.Platform = fakePlatform{goos: "linux"}
+	finder.WorkingDir = "/repo/src"
+	finder.HomeDir = "/home/dev"
+
+	want := []string{
+		"repo/src/NuGet.Config",
+		"repo/NuGet.Config",
+		"home/dev/.config/NuGet/NuGet.Config",
+		"etc/NuGet/NuGet.Config",
+	}
+	if got := finder.FindAllConfigFiles(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("FindAllConfigFiles() = %v, want %v", got, want)
+	}
+
+	if configPath, err := finder.FindProjectConfig("."); err != nil || configPath != "repo/src/NuGet.Config" {
+		t.Errorf("FindProjectConfig(\".\") = %q, %v", configPath, err)
+	}
+	if configPath, err := finder.FindProjectConfig("../missing/.."); err != nil || configPath != "repo/NuGet.Config" {
+		t.Errorf("FindProjectConfig(\"../missing/..\") = %q, %v", configPath, err)
+	}
+}
+
+func TestConfigFinderCache(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	cleanup := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", "")
+	defer cleanup()
+
+	finder := NewConfigFinderWithPaths(tempDir)
+	finder.EnableCache()
+
+	if all := finder.FindAllConfigFiles(); len(all) != 0 {
+		t.Fatalf("FindAllConfigFiles() = %v, want none", all)
+	}
+
+	// 目录中新增文件后缓存自动失效
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+	if all := finder.FindAllConfigFiles(); len(all) != 1 || all[0] != configPath {
+		t.Errorf("FindAllConfigFiles() after creating file = %v", all)
+	}
+
+	// FS 中的目录没有修改时间，需要显式调用 Invalidate
+	fsys := fstest.MapFS{"app/readme.md": {Data: []byte("")}}
+	fsFinder := NewConfigFinderFS(fsys)
+	fsFinder.EnableCache()
+	if _, err := fsFinder.FindProjectConfig("/app"); err == nil {
+		t.Fatal("FindProjectConfig() expected not found")
+	}
+	fsys["app/NuGet.Config"] = &fstest.MapFile{Data: []byte(nugetTesting.ValidNuGetConfig())}
+	if _, err := fsFinder.FindProjectConfig("/app"); err == nil {
+		t.Error("FindProjectConfig() should return the cached result before Invalidate")
+	}
+	fsFinder.Invalidate()
+	if configPath, err := fsFinder.FindProjectConfig("/app"); err != nil || configPath != "app/NuGet.Config" {
+		t.Errorf("FindProjectConfig() after Invalidate = %q, %v", configPath, err)
+	}
+}
+
+func TestFindConfigHierarchy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/NuGet/NuGet.Config":              {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"home/dev/.config/NuGet/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"NuGet.Config":                        {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/NuGet.Config":                   {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/src/app/nuget.config":           {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"repo/src/lib/NuGet.Config":           {Data: []byte(nugetTesting.ValidNuGetConfig())},
+	}
+
+	finder := NewConfigFinderFS(fsys)
+	finder.Platform = fakePlatform{goos: "linux", home: "/home/dev"}
+
+	hierarchy, err := finder.FindConfigHierarchy("/repo/src/app")
+	if err != nil {
+		t.Fatalf("FindConfigHierarchy() error = %v", err)
+	}
+	want := []FoundConfig{
+		{Path: "etc/NuGet/NuGet.Config", Level: LevelMachine, Exists: true},
+		{Path: "home/dev/.config/NuGet/NuGet.Config", Level: LevelUser, Exists: true},
+		{Path: "NuGet.Config", Level: LevelProject, Exists: true},
+		{Path: "repo/NuGet.Config", Level: LevelProject, Exists: true},
+		{Path: "repo/src/app/nuget.config", Level: LevelProject, Exists: true},
+	}
+	if len(hierarchy) != len(want) {
+		t.Fatalf("FindConfigHierarchy() = %+v, want %+v", hierarchy, want)
+	}
+	for i := range want {
+		if hierarchy[i] != want[i] {
+			t.Errorf("FindConfigHierarchy()[%d] = %+v, want %+v", i, hierarchy[i], want[i])
+		}
+	}
+
+	// 仓库边界之外的项目级配置不包含在内
+	finder.RootDir = "/repo"
+	hierarchy, _ = finder.FindConfigHierarchy("/repo/src/app")
+	if len(hierarchy) != 4 || hierarchy[2].Path != "repo/NuGet.Config" {
+		t.Errorf("FindConfigHierarchy() with RootDir = %+v", hierarchy)
+	}
+}
@@ -0,0 +1,44 @@
+package finder
+
+import "fmt"
+
+// ConfigLevel 配置文件在 NuGet 配置层级中的级别
+type ConfigLevel int
+
+const (
+	// LevelProject 当前目录或上层目录中的项目级配置
+	LevelProject ConfigLevel = iota
+	// LevelUser 用户级配置，例如 ~/.config/NuGet/NuGet.Config
+	LevelUser
+	// LevelMachine 机器级配置，例如 /etc/NuGet/NuGet.Config
+	LevelMachine
+	// LevelEnvironment 环境变量指定的配置
+	LevelEnvironment
+	// LevelCustom 通过 Locations 或 AdditionalLocations 指定的配置
+	LevelCustom
+)
+
+// String 返回级别的名称
+func (l ConfigLevel) String() string {
+	switch l {
+	case LevelProject:
+		return "project"
+	case LevelUser:
+		return "user"
+	case LevelMachine:
+		return "machine"
+	case LevelEnvironment:
+		return "env"
+	case LevelCustom:
+		return "custom"
+	}
+	return fmt.Sprintf("ConfigLevel(%d)", int(l))
+}
+
+// FoundConfig 查找到的候选配置文件
+type FoundConfig struct {
+	Path  string
+	Level ConfigLevel
+	// Exists 文件是否存在
+	Exists bool
+}
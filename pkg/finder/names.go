@@ -0,0 +1,66 @@
+package finder
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+)
+
+// lookupConfigFile 查找文件，文件名为 NuGet.Config 时不区分大小写
+//
+// 在区分大小写的文件系统中，nuget.config 和 NuGet.config 等常见写法也能被找到，
+// 返回的路径使用目录中实际的文件名。同一目录中有多个匹配的文件时按
+// constants.ConfigFileNames 的顺序选择，都不在其中时选择按名称排序的第一个。
+func (f *ConfigFinder) lookupConfigFile(filePath string) (string, bool) {
+	dir, name := filepath.Split(filePath)
+	if f.FS != nil {
+		dir, name = path.Split(filePath)
+	}
+	if !strings.EqualFold(name, constants.DefaultNuGetConfigFilename) {
+		return filePath, f.fileExists(filePath)
+	}
+
+	var entries []fs.DirEntry
+	var err error
+	if f.FS != nil {
+		entries, err = fs.ReadDir(f.FS, path.Clean(dir))
+	} else {
+		entries, err = os.ReadDir(filepath.Clean(dir))
+	}
+	if err != nil {
+		return filePath, false
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(entry.Name(), constants.DefaultNuGetConfigFilename) {
+			matches = append(matches, entry.Name())
+		}
+	}
+	if len(matches) == 0 {
+		return filePath, false
+	}
+
+	match := matches[0]
+	for _, preferred := range constants.ConfigFileNames {
+		if containsName(matches, preferred) {
+			match = preferred
+			break
+		}
+	}
+	return dir + match, true
+}
+
+// containsName 判断名称列表中是否包含 name
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
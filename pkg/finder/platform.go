@@ -0,0 +1,59 @@
+package finder
+
+import (
+	"os"
+	"runtime"
+)
+
+// Platform 提供查找配置文件时依赖的操作系统信息
+//
+// 默认实现 SystemPlatform 使用 runtime.GOOS 和真实的环境变量，
+// 测试中可以替换为固定的实现来模拟其他操作系统。
+type Platform interface {
+	// GOOS 返回操作系统名称，取值与 runtime.GOOS 相同
+	GOOS() string
+	// LookupEnv 查找环境变量
+	LookupEnv(key string) (string, bool)
+	// UserHomeDir 返回当前用户的主目录
+	UserHomeDir() (string, error)
+}
+
+// SystemPlatform 当前运行的操作系统
+type SystemPlatform struct{}
+
+// GOOS 返回 runtime.GOOS
+func (SystemPlatform) GOOS() string {
+	return runtime.GOOS
+}
+
+// LookupEnv 查找真实的环境变量
+func (SystemPlatform) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// UserHomeDir 返回 os.UserHomeDir 的结果
+func (SystemPlatform) UserHomeDir() (string, error) {
+	return os.UserHomeDir()
+}
+
+// platform 返回查找使用的平台，未设置 Platform 时使用 SystemPlatform
+func (f *ConfigFinder) platform() Platform {
+	if f.Platform != nil {
+		return f.Platform
+	}
+	return SystemPlatform{}
+}
+
+// getenv 读取平台的环境变量，未定义时返回空字符串
+func (f *ConfigFinder) getenv(key string) string {
+	value, _ := f.platform().LookupEnv(key)
+	return value
+}
+
+// homeDir 返回用户主目录，HomeDir 优先于 Platform
+func (f *ConfigFinder) homeDir() (string, error) {
+	if f.HomeDir != "" {
+		return f.HomeDir, nil
+	}
+	return f.platform().UserHomeDir()
+}
@@ -0,0 +1,101 @@
+package finder
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+)
+
+// DefaultScanIgnore ScanTree 默认跳过的目录，ScanIgnore 为 nil 时使用
+var DefaultScanIgnore = []string{".git", ".vs", "node_modules", "bin", "obj", "packages"}
+
+// ScannedConfig ScanTree 找到的配置文件
+type ScannedConfig struct {
+	// Path 配置文件路径，使用实际的文件名
+	Path string
+	// Dir 配置文件所在的项目目录
+	Dir string
+	// Depth Dir 相对于扫描根目录的层数，根目录为 0
+	Depth int
+}
+
+// ScanTree 递归扫描 root 下的所有配置文件，按目录遍历顺序返回
+//
+// 名称匹配 ScanIgnore 中任一模式的目录会被跳过，模式只与目录名比较，支持通配符。
+// 文件名不区分大小写，同一目录中有多个大小写不同的配置文件时全部返回。
+// 设置了 FS 时在 FS 中扫描，root 和返回的路径都是 FS 路径。
+func (f *ConfigFinder) ScanTree(root string) ([]ScannedConfig, error) {
+	ignore := f.ScanIgnore
+	if ignore == nil {
+		ignore = DefaultScanIgnore
+	}
+
+	var found []ScannedConfig
+	visit := func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if p != root && matchesAny(entry.Name(), ignore) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(entry.Name(), constants.DefaultNuGetConfigFilename) {
+			found = append(found, ScannedConfig{Path: p})
+		}
+		return nil
+	}
+
+	if f.FS != nil {
+		var ok bool
+		if root, ok = toFSPath(root); !ok {
+			return nil, &fs.PathError{Op: "scan", Path: root, Err: fs.ErrInvalid}
+		}
+		if err := fs.WalkDir(f.FS, root, visit); err != nil {
+			return nil, err
+		}
+		for i := range found {
+			found[i].Dir = path.Dir(found[i].Path)
+			relDir := found[i].Dir
+			if root != "." {
+				relDir = strings.TrimPrefix(relDir, root)
+			}
+			found[i].Depth = pathDepth(relDir)
+		}
+		return found, nil
+	}
+
+	if err := filepath.WalkDir(root, visit); err != nil {
+		return nil, err
+	}
+	for i := range found {
+		found[i].Dir = filepath.Dir(found[i].Path)
+		if relDir, err := filepath.Rel(root, found[i].Dir); err == nil {
+			found[i].Depth = pathDepth(filepath.ToSlash(relDir))
+		}
+	}
+	return found, nil
+}
+
+// pathDepth 返回斜杠分隔的相对路径包含的目录层数，"." 和空路径为 0
+func pathDepth(relPath string) int {
+	relPath = strings.Trim(relPath, "/")
+	if relPath == "" || relPath == "." {
+		return 0
+	}
+	return strings.Count(relPath, "/") + 1
+}
+
+// matchesAny 判断名称是否匹配任一模式
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
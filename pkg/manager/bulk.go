@@ -0,0 +1,98 @@
+package manager
+
+import (
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// DuplicateSourcePolicy 批量添加包源时遇到已存在的包源名称的处理方式
+type DuplicateSourcePolicy int
+
+const (
+	// DuplicateSkip 保留已有的包源，忽略新的定义，默认值
+	DuplicateSkip DuplicateSourcePolicy = iota
+	// DuplicateUpdate 用新的定义更新已有的包源，位置保持不变
+	DuplicateUpdate
+	// DuplicateError 返回错误，配置不会被修改
+	DuplicateError
+)
+
+// AddPackageSources 批量添加包源
+//
+// 添加前会检查所有包源：名称或地址为空、同一批中名称重复时返回错误，
+// 已存在的包源按 policy 处理。检查发现多个问题时返回包含全部问题的 errors.ErrorList，
+// 任何检查失败时配置都不会被修改。新的包源按 sources 中的顺序追加在已有包源之后。
+func (m *ConfigManager) AddPackageSources(config *types.NuGetConfig, sources []types.PackageSource, policy DuplicateSourcePolicy) error {
+	var problems pkgErrors.ErrorList
+	seen := make(map[string]bool, len(sources))
+	for i, source := range sources {
+		if source.Key == "" {
+			problems.Add(pkgErrors.Errorf(pkgErrors.CodeEmptySourceKey, "package source at index %d has an empty key", i))
+			continue
+		}
+		if source.Value == "" {
+			problems.Add(pkgErrors.Errorf(pkgErrors.CodeInvalidSourceValue, "package source '%s' has an empty value", source.Key))
+		}
+		if seen[source.Key] {
+			problems.Add(pkgErrors.Errorf(pkgErrors.CodeDuplicateEntry, "package source with key '%s' is listed more than once", source.Key))
+			continue
+		}
+		seen[source.Key] = true
+
+		if policy == DuplicateError && m.GetPackageSource(config, source.Key) != nil {
+			problems.Add(pkgErrors.Errorf(pkgErrors.CodeSourceExists, "package source with key '%s' already exists", source.Key))
+		}
+	}
+	if err := problems.Err(); err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(config.PackageSources.Add))
+	for i, source := range config.PackageSources.Add {
+		if _, exists := index[source.Key]; !exists {
+			index[source.Key] = i
+		}
+	}
+
+	for _, source := range sources {
+		i, exists := index[source.Key]
+		switch {
+		case !exists:
+			config.PackageSources.Add = append(config.PackageSources.Add, source)
+		case policy == DuplicateUpdate:
+			config.PackageSources.Add[i] = source
+		}
+	}
+	return nil
+}
+
+// DisableAllExcept 禁用除 keys 以外的所有包源，并启用 keys 中的包源
+//
+// 适用于只允许使用指定包源的场景，例如只能访问内部代理源的 CI 环境。
+// keys 中的名称必须都是已定义的包源，否则返回错误且配置不会被修改，
+// 以免拼写错误导致所有包源都被禁用。
+func (m *ConfigManager) DisableAllExcept(config *types.NuGetConfig, keys ...string) error {
+	allowed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if m.GetPackageSource(config, key) == nil {
+			return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", key)
+		}
+		allowed[key] = true
+	}
+
+	for _, source := range config.PackageSources.Add {
+		if allowed[source.Key] {
+			m.EnablePackageSource(config, source.Key)
+		} else {
+			m.DisablePackageSource(config, source.Key)
+		}
+	}
+	return nil
+}
+
+// EnableAll 启用所有包源，清空禁用包源列表
+func (m *ConfigManager) EnableAll(config *types.NuGetConfig) {
+	if config.DisabledPackageSources != nil {
+		config.DisabledPackageSources.Add = []types.DisabledSource{}
+	}
+}
@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestAddPackageSources(t *testing.T) {
+	newConfig := func() *types.NuGetConfig {
+		return &types.NuGetConfig{PackageSources: types.PackageSources{Add: []types.PackageSource{
+			{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"},
+		}}}
+	}
+	sources := []types.PackageSource{
+		{Key: "internal", Value: "https://nuget.example.com/v3/index.json"},
+		{Key: "nuget.org", Value: "https://mirror.example.com/v3/index.json"},
+	}
+	manager := NewConfigManager()
+
+	tests := []struct {
+		name      string
+		policy    DuplicateSourcePolicy
+		wantErr   bool
+		wantValue string
+	}{
+		{"skip", DuplicateSkip, false, "https://api.nuget.org/v3/index.json"},
+		{"update", DuplicateUpdate, false, "https://mirror.example.com/v3/index.json"},
+		{"error", DuplicateError, true, "https://api.nuget.org/v3/index.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newConfig()
+			err := manager.AddPackageSources(config, sources, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddPackageSources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := config.PackageSources.Add[0].Value; got != tt.wantValue {
+				t.Errorf("nuget.org value = %q, want %q", got, tt.wantValue)
+			}
+			wantCount := 2
+			if tt.wantErr {
+				wantCount = 1
+			}
+			if len(config.PackageSources.Add) != wantCount {
+				t.Errorf("source count = %d, want %d", len(config.PackageSources.Add), wantCount)
+			}
+		})
+	}
+
+	invalid := [][]types.PackageSource{
+		{{Key: "", Value: "https://example.com"}},
+		{{Key: "a", Value: ""}},
+		{{Key: "a", Value: "https://a.example.com"}, {Key: "a", Value: "https://b.example.com"}},
+	}
+	for _, batch := range invalid {
+		config := newConfig()
+		if err := manager.AddPackageSources(config, batch, DuplicateUpdate); err == nil {
+			t.Errorf("AddPackageSources(%+v) should fail", batch)
+		}
+		if len(config.PackageSources.Add) != 1 {
+			t.Errorf("failed AddPackageSources(%+v) modified config", batch)
+		}
+	}
+
+	// 一次报告所有问题
+	err := manager.AddPackageSources(newConfig(), []types.PackageSource{
+		{Key: "", Value: "https://example.com"},
+		{Key: "b", Value: ""},
+		{Key: "nuget.org", Value: "https://mirror.example.com"},
+	}, DuplicateError)
+	var list pkgErrors.ErrorList
+	if !errors.As(err, &list) || list.Len() != 3 {
+		t.Errorf("AddPackageSources() error = %v, want 3 errors", err)
+	}
+}
+
+func TestDisableAllExcept(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	for _, key := range []string{"nuget.org", "internal", "local"} {
+		manager.AddPackageSource(config, key, "https://example.com/"+key, "")
+	}
+	manager.DisablePackageSource(config, "internal")
+
+	if err := manager.DisableAllExcept(config, "internal"); err != nil {
+		t.Fatalf("DisableAllExcept() error = %v", err)
+	}
+	for key, disabled := range map[string]bool{"nuget.org": true, "internal": false, "local": true} {
+		if got := manager.IsPackageSourceDisabled(config, key); got != disabled {
+			t.Errorf("IsPackageSourceDisabled(%q) = %v, want %v", key, got, disabled)
+		}
+	}
+
+	if err := manager.DisableAllExcept(config, "internl"); err == nil {
+		t.Error("DisableAllExcept() with unknown key should fail")
+	}
+	if manager.IsPackageSourceDisabled(config, "internal") {
+		t.Error("failed DisableAllExcept() modified config")
+	}
+
+	manager.EnableAll(config)
+	for _, key := range []string{"nuget.org", "internal", "local"} {
+		if manager.IsPackageSourceDisabled(config, key) {
+			t.Errorf("%s still disabled after EnableAll()", key)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// AddPackageSourceChecked 校验参数后添加或更新包源
+//
+// 与 AddPackageSource 相同，但名称不能为空，地址必须是非空的本地路径或带主机名的 URL，
+// protocolVersion 只能为空、"2" 或 "3"。开启 StrictSourceValidation 时地址还要通过
+// ValidatePackageSource 的检查，更新已有包源时沿用它的 allowInsecureConnections 设置。
+// 校验失败时配置不会被修改。
+func (m *ConfigManager) AddPackageSourceChecked(config *types.NuGetConfig, key string, value string, protocolVersion string) error {
+	if strings.TrimSpace(key) == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeEmptySourceKey, "package source key must not be empty")
+	}
+	if err := ValidateSourceValue(value); err != nil {
+		return fmt.Errorf("package source '%s': %w", key, err)
+	}
+	if m.StrictSourceValidation {
+		source := types.PackageSource{Key: key, Value: value}
+		if existing := m.GetPackageSource(config, key); existing != nil {
+			source.AllowInsecureConnections = existing.AllowInsecureConnections
+		}
+		if err := ValidatePackageSource(source, ""); err != nil {
+			return fmt.Errorf("package source '%s': %w", key, err)
+		}
+	}
+	switch protocolVersion {
+	case "", constants.NuGetV2APIProtocolVersion, constants.NuGetV3APIProtocolVersion:
+	default:
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidAttributeValue, "package source '%s': unsupported protocol version '%s'", key, protocolVersion)
+	}
+
+	m.AddPackageSource(config, key, value, protocolVersion)
+	return nil
+}
+
+// AddCredentialChecked 校验参数后添加或更新包源凭证
+//
+// 与 AddCredential 相同，但包源必须已经存在，用户名和密码都不能为空。
+func (m *ConfigManager) AddCredentialChecked(config *types.NuGetConfig, sourceKey string, username string, password string) error {
+	if m.GetPackageSource(config, sourceKey) == nil {
+		return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", sourceKey)
+	}
+	if username == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeIncompleteCredentials, "username for package source '%s' must not be empty", sourceKey)
+	}
+	if password == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeIncompleteCredentials, "password for package source '%s' must not be empty", sourceKey)
+	}
+
+	m.AddCredential(config, sourceKey, username, password)
+	return nil
+}
+
+// AddConfigOptionChecked 校验参数后添加或更新配置选项
+//
+// 与 AddConfigOption 相同，但键名不能为空，types.KnownConfigOptions 中的已知选项
+// 按其类型检查值的格式。与 SetConfigOption 不同，未知选项总是允许写入。
+func (m *ConfigManager) AddConfigOptionChecked(config *types.NuGetConfig, key string, value string) error {
+	if strings.TrimSpace(key) == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeEmptyConfigOptionKey, "config option key must not be empty")
+	}
+	if spec, known := types.LookupConfigOption(key); known {
+		if err := spec.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	m.AddConfigOption(config, key, value)
+	return nil
+}
+
+// DisablePackageSourceChecked 禁用包源，包源不存在时返回错误
+func (m *ConfigManager) DisablePackageSourceChecked(config *types.NuGetConfig, key string) error {
+	if m.GetPackageSource(config, key) == nil {
+		return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", key)
+	}
+
+	m.DisablePackageSource(config, key)
+	return nil
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"testing"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestCheckedMutations(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+
+	for _, tc := range []struct {
+		name, key, value, version string
+		code                      pkgErrors.Code
+	}{
+		{"empty key", " ", "https://api.nuget.org/v3/index.json", "3", pkgErrors.CodeEmptySourceKey},
+		{"empty value", "nuget.org", "", "3", pkgErrors.CodeInvalidSourceValue},
+		{"missing host", "nuget.org", "https:///v3/index.json", "3", pkgErrors.CodeInvalidSourceValue},
+		{"bad version", "nuget.org", "https://api.nuget.org/v3/index.json", "4", pkgErrors.CodeInvalidAttributeValue},
+	} {
+		err := manager.AddPackageSourceChecked(config, tc.key, tc.value, tc.version)
+		if err == nil {
+			t.Errorf("AddPackageSourceChecked() with %s expected error", tc.name)
+		} else if code := pkgErrors.CodeOf(err); code != tc.code {
+			t.Errorf("AddPackageSourceChecked() with %s code = %q, want %q", tc.name, code, tc.code)
+		}
+	}
+	if len(config.PackageSources.Add) != 0 {
+		t.Fatalf("failed AddPackageSourceChecked() modified config: %+v", config.PackageSources.Add)
+	}
+
+	if err := manager.AddPackageSourceChecked(config, "nuget.org", "https://api.nuget.org/v3/index.json", "3"); err != nil {
+		t.Fatalf("AddPackageSourceChecked() error = %v", err)
+	}
+	if err := manager.AddPackageSourceChecked(config, "local", "/tmp/packages", ""); err != nil {
+		t.Fatalf("AddPackageSourceChecked() with local path error = %v", err)
+	}
+
+	strict := NewConfigManager()
+	strict.StrictSourceValidation = true
+	if err := strict.AddPackageSourceChecked(config, "insecure", "http://nuget.example.com/v3/index.json", ""); err == nil {
+		t.Error("strict AddPackageSourceChecked() with http URL expected error")
+	}
+	if err := strict.AddPackageSourceChecked(config, "relative", "packages", ""); err == nil {
+		t.Error("strict AddPackageSourceChecked() with relative path expected error")
+	}
+	config.PackageSources.Add = append(config.PackageSources.Add,
+		types.PackageSource{Key: "legacy", Value: "http://old.example.com/nuget", AllowInsecureConnections: "true"})
+	if err := strict.AddPackageSourceChecked(config, "legacy", "http://new.example.com/nuget", ""); err != nil {
+		t.Errorf("strict AddPackageSourceChecked() updating an insecure source error = %v", err)
+	}
+	config.PackageSources.Add = config.PackageSources.Add[:2]
+
+	if err := manager.AddCredentialChecked(config, "missing", "user", "pass"); err == nil {
+		t.Error("AddCredentialChecked() for unknown source expected error")
+	}
+	if err := manager.AddCredentialChecked(config, "nuget.org", "", "pass"); err == nil {
+		t.Error("AddCredentialChecked() with empty username expected error")
+	}
+	if err := manager.AddCredentialChecked(config, "nuget.org", "user", ""); err == nil {
+		t.Error("AddCredentialChecked() with empty password expected error")
+	}
+	if config.PackageSourceCredentials != nil {
+		t.Error("failed AddCredentialChecked() modified config")
+	}
+	if err := manager.AddCredentialChecked(config, "nuget.org", "user", "pass"); err != nil {
+		t.Fatalf("AddCredentialChecked() error = %v", err)
+	}
+
+	if err := manager.AddConfigOptionChecked(config, "", "value"); err == nil {
+		t.Error("AddConfigOptionChecked() with empty key expected error")
+	}
+	if err := manager.AddConfigOptionChecked(config, "maxHttpRequestsPerSource", "many"); err == nil {
+		t.Error("AddConfigOptionChecked() with invalid value expected error")
+	}
+	if err := manager.AddConfigOptionChecked(config, "customOption", "anything"); err != nil {
+		t.Errorf("AddConfigOptionChecked() with unknown key error = %v", err)
+	}
+	if got := manager.GetConfigOption(config, "customOption"); got != "anything" {
+		t.Errorf("GetConfigOption() = %q, want %q", got, "anything")
+	}
+
+	if err := manager.DisablePackageSourceChecked(config, "missing"); err == nil {
+		t.Error("DisablePackageSourceChecked() for unknown source expected error")
+	}
+	if err := manager.DisablePackageSourceChecked(config, "local"); err != nil {
+		t.Fatalf("DisablePackageSourceChecked() error = %v", err)
+	}
+	if !manager.IsPackageSourceDisabled(config, "local") {
+		t.Error("DisablePackageSourceChecked() did not disable source")
+	}
+}
@@ -2,34 +2,93 @@
 package manager
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
 	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
 	"github.com/scagogogo/nuget-config-parser/pkg/finder"
 	"github.com/scagogogo/nuget-config-parser/pkg/parser"
 	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
 )
 
 // ConfigManager NuGet配置管理器
 type ConfigManager struct {
+	// PreserveFormatting 是否保留原始文件的格式
+	//
+	// 开启后 LoadConfig 会记录文件内容和位置信息，SaveConfig 保存这样加载的配置时
+	// 只改写发生变化的部分，未修改的配置保存后与原文件逐字节相同。
+	// 其他配置仍然完整序列化。
+	PreserveFormatting bool
+
+	// ValidateConfigOptions 是否在 SetConfigOption 中校验配置选项
+	//
+	// 开启后 SetConfigOption 拒绝 types.KnownConfigOptions 以外的键，
+	// 并按选项的类型检查值的格式，校验失败时配置不会被修改。
+	ValidateConfigOptions bool
+
+	// StrictSourceValidation 是否在 AddPackageSourceChecked 中严格校验包源地址
+	//
+	// 开启后地址还要通过 ValidatePackageSource 的检查：URL 只能使用 http、https 或 file 协议，
+	// http:// 地址只允许用于已设置 allowInsecureConnections="true" 的包源，本地路径必须是绝对路径。
+	StrictSourceValidation bool
+
 	parser *parser.ConfigParser
 	finder *finder.ConfigFinder
+
+	// editors 保留格式时每个已加载配置对应的编辑器，key 为 LoadConfig 返回的配置对象
+	editors map[*types.NuGetConfig]*editor.ConfigEditor
 }
 
 // NewConfigManager 创建新的配置管理器
 func NewConfigManager() *ConfigManager {
 	return &ConfigManager{
-		parser: parser.NewConfigParser(),
-		finder: finder.NewConfigFinder(),
+		parser:  parser.NewConfigParser(),
+		finder:  finder.NewConfigFinder(),
+		editors: make(map[*types.NuGetConfig]*editor.ConfigEditor),
 	}
 }
 
+// NewConfigManagerWithFinder 创建使用指定查找器的配置管理器，
+// FindAndLoadConfig 等方法将通过该查找器定位配置文件
+func NewConfigManagerWithFinder(f *finder.ConfigFinder) *ConfigManager {
+	m := NewConfigManager()
+	m.finder = f
+	return m
+}
+
 // LoadConfig 加载配置文件
 func (m *ConfigManager) LoadConfig(filePath string) (*types.NuGetConfig, error) {
-	return m.parser.ParseFromFile(filePath)
+	if !m.PreserveFormatting {
+		return m.parser.ParseFromFile(filePath)
+	}
+
+	result, err := m.parser.ParseFromFileWithPositions(filePath)
+	if err != nil {
+		return nil, err
+	}
+	m.TrackParseResult(result)
+	return result.Config, nil
+}
+
+// TrackParseResult 记录位置感知解析的结果，之后 SaveConfig 保存 result.Config 时保留原始格式
+//
+// 调用时的配置状态作为比较的基准，因此应在修改配置之前调用。
+func (m *ConfigManager) TrackParseResult(result *parser.ParseResult) {
+	if m.editors == nil {
+		m.editors = make(map[*types.NuGetConfig]*editor.ConfigEditor)
+	}
+	m.editors[result.Config] = editor.NewConfigEditor(result)
+}
+
+// Untrack 不再为配置保留原始格式，之后保存时完整序列化
+func (m *ConfigManager) Untrack(config *types.NuGetConfig) {
+	delete(m.editors, config)
 }
 
 // FindAndLoadConfig 查找并加载第一个可用的配置文件
@@ -48,10 +107,77 @@ func (m *ConfigManager) FindAndLoadConfig() (*types.NuGetConfig, string, error)
 }
 
 // SaveConfig 保存配置到文件
+//
+// 配置由保留格式的 LoadConfig 加载或经 TrackParseResult 记录时，只改写与加载时相比
+// 发生变化的部分；否则将配置完整序列化。
 func (m *ConfigManager) SaveConfig(config *types.NuGetConfig, filePath string) error {
+	if ed, tracked := m.editors[config]; tracked {
+		if err := ed.SyncFromConfig(); err != nil {
+			return pkgErrors.Errorf(pkgErrors.CodeEditFailed, "failed to sync config changes: %w", err)
+		}
+		return ed.ApplyEditsToFile(filePath)
+	}
+
 	return m.parser.SaveToFile(config, filePath)
 }
 
+// LoadConfigContext 加载配置文件，在读取和解析前检查 ctx
+//
+// ctx 已取消或超时时返回 ctx.Err()。
+func (m *ConfigManager) LoadConfigContext(ctx context.Context, filePath string) (*types.NuGetConfig, error) {
+	if !m.PreserveFormatting {
+		return m.parser.ParseFromFileContext(ctx, filePath)
+	}
+
+	result, err := m.parser.ParseFromFileWithPositionsContext(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	m.TrackParseResult(result)
+	return result.Config, nil
+}
+
+// FindAndLoadConfigContext 查找并加载第一个可用的配置文件，在查找和加载前检查 ctx
+func (m *ConfigManager) FindAndLoadConfigContext(ctx context.Context) (*types.NuGetConfig, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	configPath, err := m.finder.FindConfigFile()
+	if err != nil {
+		return nil, "", pkgErrors.ErrConfigFileNotFound
+	}
+
+	config, err := m.LoadConfigContext(ctx, configPath)
+	if err != nil {
+		return nil, configPath, err
+	}
+
+	return config, configPath, nil
+}
+
+// SaveConfigContext 保存配置到文件，在写入前检查 ctx
+//
+// ctx 在写入开始前取消时返回 ctx.Err()，文件不会被修改。写入本身不可中断，
+// 保留格式时通过原子替换完成，不会留下写了一半的文件。
+func (m *ConfigManager) SaveConfigContext(ctx context.Context, config *types.NuGetConfig, filePath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ed, tracked := m.editors[config]; tracked {
+		if err := ed.SyncFromConfig(); err != nil {
+			return pkgErrors.Errorf(pkgErrors.CodeEditFailed, "failed to sync config changes: %w", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return ed.ApplyEditsToFile(filePath)
+	}
+
+	return m.parser.SaveToFileContext(ctx, config, filePath)
+}
+
 // CreateDefaultConfig 创建默认配置
 func (m *ConfigManager) CreateDefaultConfig() *types.NuGetConfig {
 	// 创建包含默认源的配置
@@ -76,7 +202,7 @@ func (m *ConfigManager) InitializeDefaultConfig(filePath string) error {
 	// 检查文件目录是否存在，不存在则创建
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+		return pkgErrors.Errorf(pkgErrors.CodeIO, "failed to create directory: %w", err)
 	}
 
 	// 创建默认配置
@@ -113,6 +239,37 @@ func (m *ConfigManager) AddPackageSource(config *types.NuGetConfig, key string,
 	config.PackageSources.Add = append(config.PackageSources.Add, newSource)
 }
 
+// AddPackageSourceAuto 添加包源，协议版本根据地址推断
+//
+// 推断规则见 InferProtocolVersion，无法推断时不写入 protocolVersion。
+func (m *ConfigManager) AddPackageSourceAuto(config *types.NuGetConfig, key string, value string) {
+	m.AddPackageSource(config, key, value, InferProtocolVersion(value))
+}
+
+// InferProtocolVersion 根据包源地址推断协议版本
+//
+// 以 index.json 结尾的 URL 是 V3 服务索引，返回 "3"；路径中包含 /api/v2 的 URL
+// 是 V2 源，返回 "2"；本地路径和其他地址返回空字符串。
+func InferProtocolVersion(value string) string {
+	if !utils.IsURL(value) {
+		return ""
+	}
+
+	path := strings.ToLower(value)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimRight(path, "/")
+
+	switch {
+	case strings.HasSuffix(path, "/index.json"):
+		return constants.NuGetV3APIProtocolVersion
+	case strings.HasSuffix(path, "/api/v2") || strings.Contains(path, "/api/v2/"):
+		return constants.NuGetV2APIProtocolVersion
+	}
+	return ""
+}
+
 // RemovePackageSource 移除包源
 func (m *ConfigManager) RemovePackageSource(config *types.NuGetConfig, key string) bool {
 	for i, source := range config.PackageSources.Add {
@@ -125,6 +282,111 @@ func (m *ConfigManager) RemovePackageSource(config *types.NuGetConfig, key strin
 	return false
 }
 
+// RenamePackageSource 重命名包源，并同步更新引用该包源的所有配置
+//
+// 除包源本身外，活跃包源、禁用包源、凭证以及包源映射中的键也会一并更新，
+// 避免先删除再添加时这些条目失去对应的包源。newKey 与其他包源重名时返回错误，
+// 此时配置不会被修改。
+func (m *ConfigManager) RenamePackageSource(config *types.NuGetConfig, oldKey string, newKey string) error {
+	if newKey == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeEmptySourceKey, "new package source key must not be empty")
+	}
+	if m.GetPackageSource(config, oldKey) == nil {
+		return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", oldKey)
+	}
+	if oldKey == newKey {
+		return nil
+	}
+	if m.GetPackageSource(config, newKey) != nil {
+		return pkgErrors.Errorf(pkgErrors.CodeSourceExists, "package source with key '%s' already exists", newKey)
+	}
+
+	renameSource(config, oldKey, newKey)
+	return nil
+}
+
+// renameSource 将包源及其在其他配置节中的引用从 oldKey 改为 newKey
+func renameSource(config *types.NuGetConfig, oldKey string, newKey string) {
+	for i := range config.PackageSources.Add {
+		if config.PackageSources.Add[i].Key == oldKey {
+			config.PackageSources.Add[i].Key = newKey
+		}
+	}
+
+	if config.ActivePackageSource != nil && config.ActivePackageSource.Add.Key == oldKey {
+		config.ActivePackageSource.Add.Key = newKey
+	}
+
+	if config.DisabledPackageSources != nil {
+		for i := range config.DisabledPackageSources.Add {
+			if config.DisabledPackageSources.Add[i].Key == oldKey {
+				config.DisabledPackageSources.Add[i].Key = newKey
+			}
+		}
+	}
+
+	if config.PackageSourceCredentials != nil {
+		if cred, exists := config.PackageSourceCredentials.Sources[oldKey]; exists {
+			delete(config.PackageSourceCredentials.Sources, oldKey)
+			config.PackageSourceCredentials.Sources[newKey] = cred
+		}
+	}
+
+	if config.PackageSourceMapping != nil {
+		for i := range config.PackageSourceMapping.PackageSource {
+			if config.PackageSourceMapping.PackageSource[i].Key == oldKey {
+				config.PackageSourceMapping.PackageSource[i].Key = newKey
+			}
+		}
+	}
+}
+
+// MovePackageSource 将包源移动到指定位置
+//
+// NuGet 按包源在配置中出现的顺序进行还原，index 为移动后包源在列表中的下标，
+// 其余包源的相对顺序保持不变。
+func (m *ConfigManager) MovePackageSource(config *types.NuGetConfig, key string, index int) error {
+	sources := config.PackageSources.Add
+	from := -1
+	for i, source := range sources {
+		if source.Key == key {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", key)
+	}
+	if index < 0 || index >= len(sources) {
+		return pkgErrors.Errorf(pkgErrors.CodeIndexOutOfRange, "index %d out of range [0, %d)", index, len(sources))
+	}
+
+	source := sources[from]
+	if from < index {
+		copy(sources[from:index], sources[from+1:index+1])
+	} else {
+		copy(sources[index+1:from+1], sources[index:from])
+	}
+	sources[index] = source
+	return nil
+}
+
+// MoveToTop 将包源移动到列表最前面，使其优先于其他包源
+func (m *ConfigManager) MoveToTop(config *types.NuGetConfig, key string) error {
+	return m.MovePackageSource(config, key, 0)
+}
+
+// SortPackageSources 对包源排序
+//
+// less 为 nil 时按键名排序。排序是稳定的，less 认为相等的包源保持原有顺序。
+func (m *ConfigManager) SortPackageSources(config *types.NuGetConfig, less func(a, b types.PackageSource) bool) {
+	if less == nil {
+		less = func(a, b types.PackageSource) bool { return a.Key < b.Key }
+	}
+	sources := config.PackageSources.Add
+	sort.SliceStable(sources, func(i, j int) bool { return less(sources[i], sources[j]) })
+}
+
 // GetPackageSource 获取指定键的包源
 func (m *ConfigManager) GetPackageSource(config *types.NuGetConfig, key string) *types.PackageSource {
 	for _, source := range config.PackageSources.Add {
@@ -152,7 +414,7 @@ func (m *ConfigManager) SetActivePackageSource(config *types.NuGetConfig, key st
 	}
 
 	if source == nil {
-		return fmt.Errorf("package source with key '%s' not found", key)
+		return pkgErrors.Errorf(pkgErrors.CodeSourceNotFound, "package source with key '%s' not found", key)
 	}
 
 	// 如果 ActivePackageSource 为 nil，则初始化
@@ -167,6 +429,23 @@ func (m *ConfigManager) SetActivePackageSource(config *types.NuGetConfig, key st
 
 // AddCredential 添加包源凭证
 func (m *ConfigManager) AddCredential(config *types.NuGetConfig, sourceKey string, username string, password string) {
+	m.setCredential(config, sourceKey, username, "ClearTextPassword", password)
+}
+
+// AddEncryptedCredential 添加包源凭证，密码为已加密的值，以 Password 项存储
+//
+// encryptedPassword 通常由 credentials.Cipher 加密得到。
+func (m *ConfigManager) AddEncryptedCredential(config *types.NuGetConfig, sourceKey string, username string, encryptedPassword string) {
+	m.setCredential(config, sourceKey, username, "Password", encryptedPassword)
+}
+
+// AddUsernameCredential 添加只有用户名的包源凭证，密码保存在配置文件之外，如系统凭证存储中
+func (m *ConfigManager) AddUsernameCredential(config *types.NuGetConfig, sourceKey string, username string) {
+	m.setCredential(config, sourceKey, username, "", "")
+}
+
+// setCredential 将包源凭证设置为用户名和 passwordKey 对应的密码项，passwordKey 为空时只设置用户名
+func (m *ConfigManager) setCredential(config *types.NuGetConfig, sourceKey, username, passwordKey, password string) {
 	// 如果 PackageSourceCredentials 为 nil，则初始化
 	if config.PackageSourceCredentials == nil {
 		config.PackageSourceCredentials = &types.PackageSourceCredentials{
@@ -184,10 +463,12 @@ func (m *ConfigManager) AddCredential(config *types.NuGetConfig, sourceKey strin
 	})
 
 	// 添加密码
-	credentials = append(credentials, types.Credential{
-		Key:   "ClearTextPassword",
-		Value: password,
-	})
+	if passwordKey != "" {
+		credentials = append(credentials, types.Credential{
+			Key:   passwordKey,
+			Value: password,
+		})
+	}
 
 	// 设置凭证
 	sourceCredential := types.SourceCredential{
@@ -211,6 +492,19 @@ func (m *ConfigManager) RemoveCredential(config *types.NuGetConfig, sourceKey st
 	return true
 }
 
+// ClearAllCredentials 移除所有包源的凭证，配置中原本有凭证时返回 true
+//
+// 适用于提交到版本库前清理配置中的用户名和密码。
+func (m *ConfigManager) ClearAllCredentials(config *types.NuGetConfig) bool {
+	if config.PackageSourceCredentials == nil {
+		return false
+	}
+
+	removed := len(config.PackageSourceCredentials.Sources) > 0
+	config.PackageSourceCredentials = nil
+	return removed
+}
+
 // DisablePackageSource 禁用包源
 func (m *ConfigManager) DisablePackageSource(config *types.NuGetConfig, key string) {
 	// 如果 DisabledPackageSources 为 nil，则初始化
@@ -295,6 +589,25 @@ func (m *ConfigManager) AddConfigOption(config *types.NuGetConfig, key string, v
 	})
 }
 
+// SetConfigOption 设置配置选项，ValidateConfigOptions 开启时先校验键和值
+func (m *ConfigManager) SetConfigOption(config *types.NuGetConfig, key string, value string) error {
+	if m.ValidateConfigOptions {
+		spec, known := types.LookupConfigOption(key)
+		if !known {
+			if suggestion := types.SuggestConfigOption(key); suggestion != "" {
+				return pkgErrors.Errorf(pkgErrors.CodeUnknownConfigOption, "unknown config option '%s', did you mean '%s'?", key, suggestion)
+			}
+			return pkgErrors.Errorf(pkgErrors.CodeUnknownConfigOption, "unknown config option '%s'", key)
+		}
+		if err := spec.Validate(value); err != nil {
+			return err
+		}
+	}
+
+	m.AddConfigOption(config, key, value)
+	return nil
+}
+
 // RemoveConfigOption 移除配置选项
 func (m *ConfigManager) RemoveConfigOption(config *types.NuGetConfig, key string) bool {
 	if config.Config == nil {
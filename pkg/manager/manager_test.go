@@ -1,13 +1,18 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/finder"
 	"github.com/scagogogo/nuget-config-parser/pkg/parser"
 	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
 	"github.com/scagogogo/nuget-config-parser/pkg/types"
@@ -87,6 +92,10 @@ func TestLoadConfig(t *testing.T) {
 	if err == nil {
 		t.Error("LoadConfig() should return error when config file doesn't exist")
 	}
+	var configErr *pkgErrors.ConfigError
+	if !errors.As(err, &configErr) || configErr.Path != configPath || !errors.Is(err, pkgErrors.ErrConfigFileNotFound) {
+		t.Errorf("LoadConfig() error = %v, want a ConfigError for %s", err, configPath)
+	}
 }
 
 func TestGetNuGetConfigFromPath(t *testing.T) {
@@ -204,6 +213,26 @@ func TestFindAndLoadConfig(t *testing.T) {
 	}
 }
 
+func TestNewConfigManagerWithFinder(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	cleanup := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", "")
+	defer cleanup()
+
+	manager := NewConfigManagerWithFinder(finder.NewConfigFinderWithPaths(tempDir))
+	config, foundPath, err := manager.FindAndLoadConfig()
+	if err != nil {
+		t.Fatalf("FindAndLoadConfig() error = %v", err)
+	}
+	if config == nil || foundPath != configPath {
+		t.Errorf("FindAndLoadConfig() found path = %q, want %q", foundPath, configPath)
+	}
+}
+
 func TestSaveConfig(t *testing.T) {
 	// 创建临时目录
 	tempDir := nugetTesting.CreateTempDir(t)
@@ -324,3 +353,211 @@ func TestInitializeDefaultConfig(t *testing.T) {
 		t.Errorf("Initialized source key = %q, want %q", config.PackageSources.Add[0].Key, "nuget.org")
 	}
 }
+
+func TestRenamePackageSource(t *testing.T) {
+	manager := NewConfigManager()
+	config := manager.CreateDefaultConfig()
+	manager.AddPackageSource(config, "old-feed", "https://example.com/feed", "3")
+	manager.AddPackageSource(config, "other", "https://example.com/other", "3")
+	if err := manager.SetActivePackageSource(config, "old-feed"); err != nil {
+		t.Fatalf("SetActivePackageSource() error = %v", err)
+	}
+	manager.DisablePackageSource(config, "old-feed")
+	manager.AddCredential(config, "old-feed", "user", "pass")
+	config.PackageSourceMapping = &types.PackageSourceMapping{
+		PackageSource: []types.PackageSourceMappingSource{
+			{Key: "old-feed", Package: []types.PackagePattern{{Pattern: "Contoso.*"}}},
+		},
+	}
+
+	if err := manager.RenamePackageSource(config, "old-feed", "new-feed"); err != nil {
+		t.Fatalf("RenamePackageSource() error = %v", err)
+	}
+
+	if manager.GetPackageSource(config, "old-feed") != nil {
+		t.Error("old key still present in packageSources")
+	}
+	if source := manager.GetPackageSource(config, "new-feed"); source == nil || source.Value != "https://example.com/feed" {
+		t.Errorf("GetPackageSource(new-feed) = %+v", source)
+	}
+	if config.ActivePackageSource.Add.Key != "new-feed" {
+		t.Errorf("active source key = %q, want %q", config.ActivePackageSource.Add.Key, "new-feed")
+	}
+	if !manager.IsPackageSourceDisabled(config, "new-feed") || manager.IsPackageSourceDisabled(config, "old-feed") {
+		t.Error("disabledPackageSources was not renamed")
+	}
+	if _, exists := config.PackageSourceCredentials.Sources["old-feed"]; exists {
+		t.Error("credentials still keyed by old name")
+	}
+	if _, exists := config.PackageSourceCredentials.Sources["new-feed"]; !exists {
+		t.Error("credentials not moved to new name")
+	}
+	if config.PackageSourceMapping.PackageSource[0].Key != "new-feed" {
+		t.Errorf("mapping key = %q, want %q", config.PackageSourceMapping.PackageSource[0].Key, "new-feed")
+	}
+
+	if err := manager.RenamePackageSource(config, "missing", "x"); err == nil {
+		t.Error("RenamePackageSource() with missing source should fail")
+	}
+	if err := manager.RenamePackageSource(config, "new-feed", "other"); err == nil {
+		t.Error("RenamePackageSource() onto existing key should fail")
+	}
+	if manager.GetPackageSource(config, "new-feed") == nil {
+		t.Error("failed rename modified the config")
+	}
+	if err := manager.RenamePackageSource(config, "new-feed", "new-feed"); err != nil {
+		t.Errorf("RenamePackageSource() to same key error = %v", err)
+	}
+}
+
+func TestMovePackageSource(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		manager.AddPackageSource(config, key, "https://example.com/"+key, "")
+	}
+	keys := func() string {
+		var result []string
+		for _, source := range config.PackageSources.Add {
+			result = append(result, source.Key)
+		}
+		return strings.Join(result, ",")
+	}
+
+	if err := manager.MovePackageSource(config, "a", 2); err != nil {
+		t.Fatalf("MovePackageSource() error = %v", err)
+	}
+	if got := keys(); got != "b,c,a,d" {
+		t.Errorf("after moving down: %s", got)
+	}
+	if err := manager.MovePackageSource(config, "d", 1); err != nil {
+		t.Fatalf("MovePackageSource() error = %v", err)
+	}
+	if got := keys(); got != "b,d,c,a" {
+		t.Errorf("after moving up: %s", got)
+	}
+	if err := manager.MoveToTop(config, "a"); err != nil {
+		t.Fatalf("MoveToTop() error = %v", err)
+	}
+	if got := keys(); got != "a,b,d,c" {
+		t.Errorf("after MoveToTop: %s", got)
+	}
+
+	if err := manager.MovePackageSource(config, "missing", 0); err == nil {
+		t.Error("MovePackageSource() with missing key should fail")
+	}
+	if err := manager.MovePackageSource(config, "a", 4); err == nil {
+		t.Error("MovePackageSource() with out of range index should fail")
+	}
+
+	manager.SortPackageSources(config, nil)
+	if got := keys(); got != "a,b,c,d" {
+		t.Errorf("after SortPackageSources: %s", got)
+	}
+	manager.SortPackageSources(config, func(a, b types.PackageSource) bool { return a.Key > b.Key })
+	if got := keys(); got != "d,c,b,a" {
+		t.Errorf("after custom sort: %s", got)
+	}
+}
+
+func TestClearAllCredentials(t *testing.T) {
+	manager := NewConfigManager()
+	config := manager.CreateDefaultConfig()
+	manager.AddCredential(config, "nuget.org", "user", "pass")
+
+	if !manager.ClearAllCredentials(config) {
+		t.Error("ClearAllCredentials() = false, want true")
+	}
+	if config.PackageSourceCredentials != nil {
+		t.Error("credentials were not removed")
+	}
+	if manager.ClearAllCredentials(config) {
+		t.Error("ClearAllCredentials() on config without credentials = true, want false")
+	}
+}
+
+func TestAddEncryptedCredential(t *testing.T) {
+	manager := NewConfigManager()
+	config := manager.CreateDefaultConfig()
+	manager.AddCredential(config, "nuget.org", "user", "pass")
+	manager.AddEncryptedCredential(config, "nuget.org", "user", "AQAAANCMnd8B")
+
+	want := []types.Credential{{Key: "Username", Value: "user"}, {Key: "Password", Value: "AQAAANCMnd8B"}}
+	if got := config.PackageSourceCredentials.Sources["nuget.org"].Add; !reflect.DeepEqual(got, want) {
+		t.Errorf("credentials = %+v, want %+v", got, want)
+	}
+
+	manager.AddUsernameCredential(config, "nuget.org", "ci")
+	want = []types.Credential{{Key: "Username", Value: "ci"}}
+	if got := config.PackageSourceCredentials.Sources["nuget.org"].Add; !reflect.DeepEqual(got, want) {
+		t.Errorf("credentials = %+v, want %+v", got, want)
+	}
+}
+
+func TestInferProtocolVersion(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"https://api.nuget.org/v3/index.json", "3"},
+		{"https://pkgs.example.com/feed/Index.JSON?x=1", "3"},
+		{"https://www.nuget.org/api/v2", "2"},
+		{"https://legacy.example.com/api/v2/", "2"},
+		{"https://legacy.example.com/nuget/api/v2/package", "2"},
+		{"https://example.com/feed", ""},
+		{"/var/packages", ""},
+		{`C:\packages`, ""},
+	}
+	for _, tt := range tests {
+		if got := InferProtocolVersion(tt.value); got != tt.want {
+			t.Errorf("InferProtocolVersion(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	manager.AddPackageSourceAuto(config, "nuget.org", "https://api.nuget.org/v3/index.json")
+	if got := config.PackageSources.Add[0].ProtocolVersion; got != "3" {
+		t.Errorf("AddPackageSourceAuto() protocolVersion = %q, want %q", got, "3")
+	}
+}
+
+func TestManagerContextVariants(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	manager := NewConfigManager()
+	manager.PreserveFormatting = true
+
+	config, err := manager.LoadConfigContext(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigContext() error = %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := manager.LoadConfigContext(canceled, configPath); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadConfigContext() error = %v, want context.Canceled", err)
+	}
+	if _, _, err := manager.FindAndLoadConfigContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindAndLoadConfigContext() error = %v, want context.Canceled", err)
+	}
+
+	manager.AddPackageSource(config, "added", "https://added.example.com/v3/index.json", "3")
+	if err := manager.SaveConfigContext(canceled, config, configPath); !errors.Is(err, context.Canceled) {
+		t.Errorf("SaveConfigContext() error = %v, want context.Canceled", err)
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "added") {
+		t.Error("SaveConfigContext() with canceled context modified the file")
+	}
+
+	if err := manager.SaveConfigContext(context.Background(), config, configPath); err != nil {
+		t.Fatalf("SaveConfigContext() error = %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); !strings.Contains(string(data), "added") {
+		t.Error("SaveConfigContext() did not write the change")
+	}
+}
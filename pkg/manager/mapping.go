@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"math"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// PackageSourceMatch 包ID在包源映射中的匹配结果
+type PackageSourceMatch struct {
+	// MappingEnabled 配置是否定义了包源映射，未定义时所有启用的包源都可以提供任何包
+	MappingEnabled bool
+	// Pattern 匹配包ID的最具体的模式，没有模式匹配或未定义包源映射时为空
+	Pattern string
+	// Sources 可以提供该包的包源名称。定义了包源映射时按映射中的顺序排列，
+	// 可能包含未定义或已禁用的包源；否则为按配置顺序排列的所有启用的包源
+	Sources []string
+}
+
+// FindSourcesForPackage 按包源映射规则查找可以提供指定包的包源
+//
+// 规则与 NuGet 一致：包ID不区分大小写，完全匹配的模式优先于前缀模式，
+// 前缀模式（以 * 结尾）中前缀越长越优先，单独的 * 匹配所有包。
+// 映射了最具体模式的所有包源都可以提供该包。定义了包源映射但没有模式匹配时，
+// 返回的 Sources 为空，即该包无法还原。
+func (m *ConfigManager) FindSourcesForPackage(config *types.NuGetConfig, packageID string) PackageSourceMatch {
+	mapping := config.PackageSourceMapping
+	if mapping == nil || len(mapping.PackageSource) == 0 {
+		var match PackageSourceMatch
+		for _, source := range m.GetEnabledPackageSources(config) {
+			match.Sources = append(match.Sources, source.Key)
+		}
+		return match
+	}
+
+	match := PackageSourceMatch{MappingEnabled: true}
+	best := -1
+	for _, group := range mapping.PackageSource {
+		for _, pkg := range group.Package {
+			if rank, ok := patternRank(pkg.Pattern, packageID); ok && rank > best {
+				best = rank
+				match.Pattern = strings.TrimSpace(pkg.Pattern)
+			}
+		}
+	}
+	if best < 0 {
+		return match
+	}
+
+	for _, group := range mapping.PackageSource {
+		for _, pkg := range group.Package {
+			if strings.EqualFold(strings.TrimSpace(pkg.Pattern), match.Pattern) && !containsFold(match.Sources, group.Key) {
+				match.Sources = append(match.Sources, group.Key)
+			}
+		}
+	}
+	return match
+}
+
+// patternRank 判断模式是否匹配包ID，并返回其具体程度，值越大越具体
+func patternRank(pattern, packageID string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	packageID = strings.ToLower(packageID)
+
+	if prefix, isPrefix := strings.CutSuffix(pattern, "*"); isPrefix {
+		return len(prefix), strings.HasPrefix(packageID, prefix)
+	}
+	return math.MaxInt, pattern == packageID
+}
+
+// containsFold 判断列表中是否包含 s，不区分大小写
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestFindSourcesForPackage(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{Add: []types.PackageSource{
+			{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+			{Key: "contoso", Value: "https://contoso.com/v3/index.json"},
+			{Key: "mirror", Value: "https://mirror.contoso.com/v3/index.json"},
+		}},
+		PackageSourceMapping: &types.PackageSourceMapping{PackageSource: []types.PackageSourceMappingSource{
+			{Key: "nuget.org", Package: []types.PackagePattern{{Pattern: "*"}, {Pattern: "Contoso.Public"}}},
+			{Key: "contoso", Package: []types.PackagePattern{{Pattern: "Contoso.*"}}},
+			{Key: "mirror", Package: []types.PackagePattern{{Pattern: "contoso.*"}, {Pattern: "Contoso.Internal.*"}}},
+		}},
+	}
+
+	tests := []struct {
+		packageID string
+		want      PackageSourceMatch
+	}{
+		{"Newtonsoft.Json", PackageSourceMatch{MappingEnabled: true, Pattern: "*", Sources: []string{"nuget.org"}}},
+		{"contoso.core", PackageSourceMatch{MappingEnabled: true, Pattern: "Contoso.*", Sources: []string{"contoso", "mirror"}}},
+		{"Contoso.Internal.Auth", PackageSourceMatch{MappingEnabled: true, Pattern: "Contoso.Internal.*", Sources: []string{"mirror"}}},
+		{"CONTOSO.PUBLIC", PackageSourceMatch{MappingEnabled: true, Pattern: "Contoso.Public", Sources: []string{"nuget.org"}}},
+	}
+
+	m := NewConfigManager()
+	for _, tt := range tests {
+		if got := m.FindSourcesForPackage(config, tt.packageID); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FindSourcesForPackage(%q) = %+v, want %+v", tt.packageID, got, tt.want)
+		}
+	}
+
+	// 没有模式匹配时包无法还原
+	config.PackageSourceMapping.PackageSource = config.PackageSourceMapping.PackageSource[1:]
+	if got := m.FindSourcesForPackage(config, "Newtonsoft.Json"); !got.MappingEnabled || got.Pattern != "" || len(got.Sources) != 0 {
+		t.Errorf("FindSourcesForPackage() without a matching pattern = %+v", got)
+	}
+
+	// 未定义包源映射时使用所有启用的包源
+	config.PackageSourceMapping = nil
+	config.DisabledPackageSources = &types.DisabledPackageSources{Add: []types.DisabledSource{{Key: "mirror", Value: "true"}}}
+	want := PackageSourceMatch{Sources: []string{"nuget.org", "contoso"}}
+	if got := m.FindSourcesForPackage(config, "Newtonsoft.Json"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindSourcesForPackage() without mapping = %+v, want %+v", got, want)
+	}
+}
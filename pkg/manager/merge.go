@@ -0,0 +1,226 @@
+package manager
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// MergePolicy 合并配置时同一个键在两个配置中取值不同的处理方式
+type MergePolicy int
+
+const (
+	// MergeOverlayWins 以 overlay 中的值为准，默认值
+	MergeOverlayWins MergePolicy = iota
+	// MergeErrorOnConflict 返回包装 errors.ErrMergeConflict 的错误
+	MergeErrorOnConflict
+)
+
+// MergeConfigs 将 overlay 合并到 base 之上，返回新的配置，两个参数都不会被修改
+//
+// 合并规则与 NuGet 合并配置文件层级时一致，overlay 相当于优先级更高的文件：
+//   - overlay 中包含 <clear /> 的配置节完全替换 base 中的同名配置节，并保留 <clear />
+//   - 否则按 key 合并，base 中的项保持原有顺序，overlay 中的新项追加在后
+//   - 凭证和包源映射以包源名称为单位整体替换，活跃包源以 overlay 为准
+//
+// 同一个键在两个配置中取值相同时不算冲突。policy 为 MergeErrorOnConflict 时，
+// 返回的错误会列出所有冲突的键。
+func MergeConfigs(base, overlay *types.NuGetConfig, policy MergePolicy) (*types.NuGetConfig, error) {
+	if base == nil {
+		base = &types.NuGetConfig{}
+	}
+	if overlay == nil {
+		overlay = &types.NuGetConfig{}
+	}
+
+	m := &merger{policy: policy}
+	merged := &types.NuGetConfig{
+		PackageSources:           m.mergePackageSources(base.PackageSources, overlay.PackageSources),
+		PackageSourceCredentials: m.mergeCredentials(base.PackageSourceCredentials, overlay.PackageSourceCredentials),
+		Config:                   m.mergeConfig(base.Config, overlay.Config),
+		DisabledPackageSources:   m.mergeDisabledSources(base.DisabledPackageSources, overlay.DisabledPackageSources),
+		ActivePackageSource:      m.mergeActiveSource(base.ActivePackageSource, overlay.ActivePackageSource),
+		PackageSourceMapping:     m.mergeMapping(base.PackageSourceMapping, overlay.PackageSourceMapping),
+	}
+
+	if len(m.conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %s", pkgErrors.ErrMergeConflict, strings.Join(m.conflicts, "; "))
+	}
+	return merged, nil
+}
+
+// merger 记录合并过程中发现的冲突
+type merger struct {
+	policy    MergePolicy
+	conflicts []string
+}
+
+// conflict 记录 section 中 key 的一处冲突
+func (m *merger) conflict(section, key string) {
+	if m.policy == MergeErrorOnConflict {
+		m.conflicts = append(m.conflicts, fmt.Sprintf("<%s> key '%s' differs", section, key))
+	}
+}
+
+// mergeByKey 按 key 合并两个列表，base 的顺序保持不变，overlay 中的新项追加在后
+func mergeByKey[T any](m *merger, section string, base, overlay []T, key func(T) string) []T {
+	merged := append([]T(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, item := range merged {
+		index[key(item)] = i
+	}
+
+	for _, item := range overlay {
+		k := key(item)
+		i, exists := index[k]
+		if !exists {
+			index[k] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+		if !reflect.DeepEqual(merged[i], item) {
+			m.conflict(section, k)
+		}
+		merged[i] = item
+	}
+	return merged
+}
+
+// mergePackageSources 合并包源
+func (m *merger) mergePackageSources(base, overlay types.PackageSources) types.PackageSources {
+	if overlay.IsCleared() {
+		return types.PackageSources{
+			Clear:        overlay.Clear,
+			ClearElement: cloneClear(overlay.ClearElement),
+			Add:          append([]types.PackageSource(nil), overlay.Add...),
+		}
+	}
+
+	return types.PackageSources{
+		Clear:        base.Clear,
+		ClearElement: cloneClear(base.ClearElement),
+		Add: mergeByKey(m, "packageSources", base.Add, overlay.Add,
+			func(s types.PackageSource) string { return s.Key }),
+	}
+}
+
+// mergeCredentials 合并包源凭证，同一包源的凭证整体替换
+func (m *merger) mergeCredentials(base, overlay *types.PackageSourceCredentials) *types.PackageSourceCredentials {
+	if (base == nil || len(base.Sources) == 0) && (overlay == nil || len(overlay.Sources) == 0) {
+		return nil
+	}
+
+	merged := &types.PackageSourceCredentials{Sources: make(map[string]types.SourceCredential)}
+	for _, creds := range []*types.PackageSourceCredentials{base, overlay} {
+		if creds == nil {
+			continue
+		}
+		for name, cred := range creds.Sources {
+			if existing, exists := merged.Sources[name]; exists && !reflect.DeepEqual(existing.Add, cred.Add) {
+				m.conflict("packageSourceCredentials", name)
+			}
+			merged.Sources[name] = types.SourceCredential{Add: append([]types.Credential(nil), cred.Add...)}
+		}
+	}
+	return merged
+}
+
+// mergeConfig 合并配置选项
+func (m *merger) mergeConfig(base, overlay *types.Config) *types.Config {
+	switch {
+	case overlay.IsCleared():
+		return &types.Config{ClearElement: &types.ClearElement{}, Add: append([]types.ConfigOption(nil), overlay.Add...)}
+	case base == nil && overlay == nil:
+		return nil
+	}
+
+	merged := &types.Config{}
+	var baseAdd, overlayAdd []types.ConfigOption
+	if base != nil {
+		merged.ClearElement = cloneClear(base.ClearElement)
+		baseAdd = base.Add
+	}
+	if overlay != nil {
+		overlayAdd = overlay.Add
+	}
+	merged.Add = mergeByKey(m, "config", baseAdd, overlayAdd, func(o types.ConfigOption) string { return o.Key })
+	return merged
+}
+
+// mergeDisabledSources 合并禁用的包源
+func (m *merger) mergeDisabledSources(base, overlay *types.DisabledPackageSources) *types.DisabledPackageSources {
+	switch {
+	case overlay.IsCleared():
+		return &types.DisabledPackageSources{ClearElement: &types.ClearElement{}, Add: append([]types.DisabledSource(nil), overlay.Add...)}
+	case base == nil && overlay == nil:
+		return nil
+	}
+
+	merged := &types.DisabledPackageSources{}
+	var baseAdd, overlayAdd []types.DisabledSource
+	if base != nil {
+		merged.ClearElement = cloneClear(base.ClearElement)
+		baseAdd = base.Add
+	}
+	if overlay != nil {
+		overlayAdd = overlay.Add
+	}
+	merged.Add = mergeByKey(m, "disabledPackageSources", baseAdd, overlayAdd, func(d types.DisabledSource) string { return d.Key })
+	return merged
+}
+
+// mergeActiveSource 合并活跃包源，overlay 设置了活跃包源时以其为准
+func (m *merger) mergeActiveSource(base, overlay *types.ActivePackageSource) *types.ActivePackageSource {
+	switch {
+	case overlay != nil && overlay.Add.Key != "":
+		if base != nil && base.Add.Key != "" && base.Add != overlay.Add {
+			m.conflict("activePackageSource", overlay.Add.Key)
+		}
+		return &types.ActivePackageSource{Add: overlay.Add}
+	case base != nil:
+		return &types.ActivePackageSource{Add: base.Add}
+	}
+	return nil
+}
+
+// mergeMapping 合并包源映射，同一包源的映射规则整体替换
+func (m *merger) mergeMapping(base, overlay *types.PackageSourceMapping) *types.PackageSourceMapping {
+	clone := func(sources []types.PackageSourceMappingSource) []types.PackageSourceMappingSource {
+		cloned := make([]types.PackageSourceMappingSource, len(sources))
+		for i, source := range sources {
+			cloned[i] = types.PackageSourceMappingSource{Key: source.Key, Package: append([]types.PackagePattern(nil), source.Package...)}
+		}
+		return cloned
+	}
+
+	switch {
+	case overlay.IsCleared():
+		return &types.PackageSourceMapping{ClearElement: &types.ClearElement{}, PackageSource: clone(overlay.PackageSource)}
+	case base == nil && overlay == nil:
+		return nil
+	}
+
+	merged := &types.PackageSourceMapping{}
+	var baseSources, overlaySources []types.PackageSourceMappingSource
+	if base != nil {
+		merged.ClearElement = cloneClear(base.ClearElement)
+		baseSources = clone(base.PackageSource)
+	}
+	if overlay != nil {
+		overlaySources = clone(overlay.PackageSource)
+	}
+	merged.PackageSource = mergeByKey(m, "packageSourceMapping", baseSources, overlaySources,
+		func(s types.PackageSourceMappingSource) string { return s.Key })
+	return merged
+}
+
+// cloneClear 复制 <clear /> 元素
+func cloneClear(clear *types.ClearElement) *types.ClearElement {
+	if clear == nil {
+		return nil
+	}
+	return &types.ClearElement{}
+}
@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func mergeTestConfigs() (*types.NuGetConfig, *types.NuGetConfig) {
+	base := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{
+				{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"},
+				{Key: "team", Value: "https://team.example.com/v3/index.json"},
+			},
+		},
+		PackageSourceCredentials: &types.PackageSourceCredentials{
+			Sources: map[string]types.SourceCredential{
+				"team": {Add: []types.Credential{{Key: "Username", Value: "base"}}},
+			},
+		},
+		Config: &types.Config{
+			Add: []types.ConfigOption{{Key: "globalPackagesFolder", Value: "/base"}},
+		},
+		DisabledPackageSources: &types.DisabledPackageSources{
+			Add: []types.DisabledSource{{Key: "team", Value: "true"}},
+		},
+	}
+
+	overlay := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{
+				{Key: "team", Value: "https://staging.example.com/v3/index.json"},
+				{Key: "local", Value: "/feed"},
+			},
+		},
+		PackageSourceCredentials: &types.PackageSourceCredentials{
+			Sources: map[string]types.SourceCredential{
+				"team": {Add: []types.Credential{{Key: "Username", Value: "overlay"}}},
+			},
+		},
+		Config: &types.Config{
+			ClearElement: &types.ClearElement{},
+			Add:          []types.ConfigOption{{Key: "http_proxy", Value: "http://proxy"}},
+		},
+		DisabledPackageSources: &types.DisabledPackageSources{
+			Add: []types.DisabledSource{{Key: "team", Value: "true"}},
+		},
+	}
+	return base, overlay
+}
+
+func TestMergeConfigsOverlayWins(t *testing.T) {
+	base, overlay := mergeTestConfigs()
+
+	merged, err := MergeConfigs(base, overlay, MergeOverlayWins)
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+
+	expectedSources := []types.PackageSource{
+		{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"},
+		{Key: "team", Value: "https://staging.example.com/v3/index.json"},
+		{Key: "local", Value: "/feed"},
+	}
+	if !reflect.DeepEqual(merged.PackageSources.Add, expectedSources) {
+		t.Errorf("merged package sources = %+v", merged.PackageSources.Add)
+	}
+	if got := merged.PackageSourceCredentials.Sources["team"].Add[0].Value; got != "overlay" {
+		t.Errorf("merged credentials username = %q, want overlay", got)
+	}
+	if !merged.Config.IsCleared() || len(merged.Config.Add) != 1 || merged.Config.Add[0].Key != "http_proxy" {
+		t.Errorf("a cleared overlay section should replace the base section, got %+v", merged.Config)
+	}
+	if len(merged.DisabledPackageSources.Add) != 1 {
+		t.Errorf("identical entries should be merged, got %+v", merged.DisabledPackageSources.Add)
+	}
+
+	// 修改合并结果不应影响输入
+	merged.PackageSources.Add[0].Value = "changed"
+	merged.PackageSourceCredentials.Sources["team"].Add[0].Value = "changed"
+	if base.PackageSources.Add[0].Value == "changed" || overlay.PackageSourceCredentials.Sources["team"].Add[0].Value == "changed" {
+		t.Error("MergeConfigs() result should not share memory with its inputs")
+	}
+}
+
+func TestMergeConfigsErrorOnConflict(t *testing.T) {
+	base, overlay := mergeTestConfigs()
+
+	_, err := MergeConfigs(base, overlay, MergeErrorOnConflict)
+	if !errors.Is(err, pkgErrors.ErrMergeConflict) {
+		t.Fatalf("MergeConfigs() error = %v, want ErrMergeConflict", err)
+	}
+	for _, want := range []string{"<packageSources> key 'team'", "<packageSourceCredentials> key 'team'"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "disabledPackageSources") || strings.Contains(err.Error(), "<config>") {
+		t.Errorf("identical entries and cleared sections should not conflict: %v", err)
+	}
+
+	overlay.PackageSources.Add[0].Value = base.PackageSources.Add[1].Value
+	overlay.PackageSourceCredentials = nil
+	if _, err := MergeConfigs(base, overlay, MergeErrorOnConflict); err != nil {
+		t.Errorf("MergeConfigs() without conflicts error = %v", err)
+	}
+}
+
+func TestMergeConfigsClearedPackageSources(t *testing.T) {
+	base, overlay := mergeTestConfigs()
+	overlay.PackageSources.Clear = true
+
+	merged, err := MergeConfigs(base, overlay, MergeOverlayWins)
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	if !merged.PackageSources.IsCleared() || len(merged.PackageSources.Add) != 2 || merged.PackageSources.Add[0].Key != "team" {
+		t.Errorf("cleared overlay package sources should replace the base, got %+v", merged.PackageSources)
+	}
+
+	if merged, err := MergeConfigs(nil, nil, MergeOverlayWins); err != nil || merged.Config != nil || merged.PackageSourceCredentials != nil {
+		t.Errorf("MergeConfigs(nil, nil) = %+v, %v", merged, err)
+	}
+}
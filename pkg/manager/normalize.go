@@ -0,0 +1,221 @@
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// SourceChange 表示规范化时对包源的一处修改
+type SourceChange struct {
+	// Key 修改后的包源名称
+	Key string
+	// Field 被修改的字段，"key" 或 "value"
+	Field string
+	Old   string
+	New   string
+}
+
+// RemovedSource 表示规范化时移除的重复包源
+type RemovedSource struct {
+	Key   string
+	Value string
+	// DuplicateOf 保留下来的包源名称
+	DuplicateOf string
+}
+
+// NormalizeReport 记录 NormalizeSources 所做的修改
+type NormalizeReport struct {
+	Changed []SourceChange
+	Removed []RemovedSource
+}
+
+// HasChanges 规范化是否修改了配置
+func (r *NormalizeReport) HasChanges() bool {
+	return len(r.Changed) > 0 || len(r.Removed) > 0
+}
+
+// NormalizeSources 规范化包源并移除重复的包源
+//
+// 包源名称和地址会去掉首尾空白；URL 的协议和主机名转为小写并去掉路径末尾的斜杠；
+// 本地路径会被清理，baseDir 不为空时相对路径按 baseDir 解析为绝对路径，
+// 通常传入配置文件所在的目录。包含 %VAR% 的路径保持不变。
+//
+// 名称相同或规范化后地址相同的包源只保留第一个。被移除的包源在活跃包源、
+// 凭证和包源映射中的引用转移到保留的包源上，保留的包源已有凭证时丢弃被移除包源的凭证。
+func (m *ConfigManager) NormalizeSources(config *types.NuGetConfig, baseDir string) *NormalizeReport {
+	report := &NormalizeReport{}
+
+	for i := range config.PackageSources.Add {
+		source := &config.PackageSources.Add[i]
+
+		if key := strings.TrimSpace(source.Key); key != source.Key && key != "" {
+			old := source.Key
+			if m.GetPackageSource(config, key) == nil {
+				renameSource(config, old, key)
+			} else {
+				// 与已有包源重名，交给下面的去重处理
+				source.Key = key
+			}
+			report.Changed = append(report.Changed, SourceChange{Key: key, Field: "key", Old: old, New: key})
+		}
+
+		if value := normalizeSourceValue(source.Value, baseDir); value != source.Value {
+			report.Changed = append(report.Changed, SourceChange{Key: source.Key, Field: "value", Old: source.Value, New: value})
+			source.Value = value
+		}
+	}
+
+	keys := make(map[string]bool)
+	values := make(map[string]string)
+	kept := config.PackageSources.Add[:0]
+	var removed []RemovedSource
+	for _, source := range config.PackageSources.Add {
+		duplicateOf := ""
+		if keys[source.Key] {
+			duplicateOf = source.Key
+		} else if key, exists := values[source.Value]; exists && source.Value != "" {
+			duplicateOf = key
+		}
+
+		if duplicateOf != "" {
+			removed = append(removed, RemovedSource{Key: source.Key, Value: source.Value, DuplicateOf: duplicateOf})
+			continue
+		}
+
+		keys[source.Key] = true
+		if _, exists := values[source.Value]; !exists {
+			values[source.Value] = source.Key
+		}
+		kept = append(kept, source)
+	}
+	config.PackageSources.Add = kept
+
+	for _, source := range removed {
+		if source.Key != source.DuplicateOf && m.GetPackageSource(config, source.Key) == nil {
+			redirectSource(config, source.Key, source.DuplicateOf)
+		}
+	}
+	report.Removed = removed
+
+	return report
+}
+
+// normalizeSourceValue 规范化包源地址
+func normalizeSourceValue(value string, baseDir string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return value
+	}
+
+	if utils.IsURL(value) {
+		return normalizeURL(value)
+	}
+	if strings.Contains(value, "://") || strings.Contains(value, "%") {
+		return value
+	}
+
+	if baseDir != "" && !utils.IsAbsolutePath(value) {
+		return utils.ResolvePath(baseDir, value)
+	}
+	return filepath.Clean(value)
+}
+
+// normalizeURL 将URL的协议和主机名转为小写并去掉路径末尾的斜杠
+//
+// 路径、查询参数和用户信息区分大小写，保持不变。
+func normalizeURL(value string) string {
+	schemeEnd := strings.Index(value, "://") + len("://")
+	rest := value[schemeEnd:]
+
+	hostEnd := strings.IndexAny(rest, "/?#")
+	if hostEnd < 0 {
+		hostEnd = len(rest)
+	}
+	host := rest[:hostEnd]
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[:at+1] + strings.ToLower(host[at+1:])
+	} else {
+		host = strings.ToLower(host)
+	}
+
+	path := rest[hostEnd:]
+	suffix := ""
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path, suffix = path[:i], path[i:]
+	}
+	path = strings.TrimRight(path, "/")
+
+	return strings.ToLower(value[:schemeEnd]) + host + path + suffix
+}
+
+// redirectSource 将已移除的包源 from 在其他配置节中的引用转移到包源 to
+func redirectSource(config *types.NuGetConfig, from string, to string) {
+	if config.ActivePackageSource != nil && config.ActivePackageSource.Add.Key == from {
+		config.ActivePackageSource.Add.Key = to
+	}
+
+	if config.DisabledPackageSources != nil {
+		disabled := config.DisabledPackageSources.Add[:0]
+		for _, source := range config.DisabledPackageSources.Add {
+			if source.Key != from {
+				disabled = append(disabled, source)
+			}
+		}
+		config.DisabledPackageSources.Add = disabled
+	}
+
+	if config.PackageSourceCredentials != nil {
+		if cred, exists := config.PackageSourceCredentials.Sources[from]; exists {
+			delete(config.PackageSourceCredentials.Sources, from)
+			if _, exists := config.PackageSourceCredentials.Sources[to]; !exists {
+				config.PackageSourceCredentials.Sources[to] = cred
+			}
+		}
+	}
+
+	if config.PackageSourceMapping != nil {
+		mapping := config.PackageSourceMapping
+		var patterns []types.PackagePattern
+		sources := mapping.PackageSource[:0]
+		for _, source := range mapping.PackageSource {
+			if source.Key == from {
+				patterns = append(patterns, source.Package...)
+			} else {
+				sources = append(sources, source)
+			}
+		}
+		mapping.PackageSource = sources
+
+		if len(patterns) > 0 {
+			target := -1
+			for i, source := range mapping.PackageSource {
+				if source.Key == to {
+					target = i
+					break
+				}
+			}
+			if target < 0 {
+				mapping.PackageSource = append(mapping.PackageSource, types.PackageSourceMappingSource{Key: to})
+				target = len(mapping.PackageSource) - 1
+			}
+			for _, pattern := range patterns {
+				if !hasPattern(mapping.PackageSource[target].Package, pattern.Pattern) {
+					mapping.PackageSource[target].Package = append(mapping.PackageSource[target].Package, pattern)
+				}
+			}
+		}
+	}
+}
+
+// hasPattern 判断包匹配模式列表中是否已有 pattern
+func hasPattern(patterns []types.PackagePattern, pattern string) bool {
+	for _, p := range patterns {
+		if p.Pattern == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestNormalizeSources(t *testing.T) {
+	baseDir := filepath.Join(string(filepath.Separator), "repo")
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{Add: []types.PackageSource{
+			{Key: " nuget.org ", Value: "HTTPS://API.NuGet.org/v3/index.json"},
+			{Key: "local", Value: "./packages/"},
+			{Key: "mirror", Value: " https://api.nuget.org/v3/index.json/ "},
+			{Key: "local", Value: "/other"},
+			{Key: "feed", Value: "https://user@Example.com/Feed/?x=1"},
+		}},
+		ActivePackageSource: &types.ActivePackageSource{Add: types.PackageSource{Key: "mirror"}},
+		DisabledPackageSources: &types.DisabledPackageSources{Add: []types.DisabledSource{
+			{Key: "mirror", Value: "true"},
+		}},
+		PackageSourceCredentials: &types.PackageSourceCredentials{Sources: map[string]types.SourceCredential{
+			"mirror": {Add: []types.Credential{{Key: "Username", Value: "user"}}},
+		}},
+		PackageSourceMapping: &types.PackageSourceMapping{PackageSource: []types.PackageSourceMappingSource{
+			{Key: " nuget.org ", Package: []types.PackagePattern{{Pattern: "*"}}},
+			{Key: "mirror", Package: []types.PackagePattern{{Pattern: "*"}, {Pattern: "Contoso.*"}}},
+		}},
+	}
+
+	manager := NewConfigManager()
+	report := manager.NormalizeSources(config, baseDir)
+
+	want := []types.PackageSource{
+		{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+		{Key: "local", Value: filepath.Join(baseDir, "packages")},
+		{Key: "feed", Value: "https://user@example.com/Feed?x=1"},
+	}
+	if len(config.PackageSources.Add) != len(want) {
+		t.Fatalf("sources = %+v, want %+v", config.PackageSources.Add, want)
+	}
+	for i := range want {
+		if config.PackageSources.Add[i] != want[i] {
+			t.Errorf("source[%d] = %+v, want %+v", i, config.PackageSources.Add[i], want[i])
+		}
+	}
+
+	if len(report.Removed) != 2 {
+		t.Fatalf("Removed = %+v, want 2 entries", report.Removed)
+	}
+	if report.Removed[0] != (RemovedSource{Key: "mirror", Value: "https://api.nuget.org/v3/index.json", DuplicateOf: "nuget.org"}) {
+		t.Errorf("Removed[0] = %+v", report.Removed[0])
+	}
+	if report.Removed[1].Key != "local" || report.Removed[1].DuplicateOf != "local" {
+		t.Errorf("Removed[1] = %+v", report.Removed[1])
+	}
+	if !report.HasChanges() || len(report.Changed) != 5 {
+		t.Errorf("Changed = %+v, want 5 entries", report.Changed)
+	}
+
+	if config.ActivePackageSource.Add.Key != "nuget.org" {
+		t.Errorf("active source = %q, want nuget.org", config.ActivePackageSource.Add.Key)
+	}
+	if len(config.DisabledPackageSources.Add) != 0 {
+		t.Errorf("disabled sources = %+v, want none", config.DisabledPackageSources.Add)
+	}
+	if _, exists := config.PackageSourceCredentials.Sources["nuget.org"]; !exists {
+		t.Error("credentials were not moved to the kept source")
+	}
+	mapping := config.PackageSourceMapping.PackageSource
+	if len(mapping) != 1 || mapping[0].Key != "nuget.org" || len(mapping[0].Package) != 2 {
+		t.Errorf("mapping = %+v, want patterns merged into nuget.org", mapping)
+	}
+
+	if again := manager.NormalizeSources(config, baseDir); again.HasChanges() {
+		t.Errorf("second NormalizeSources() changed config: %+v", again)
+	}
+}
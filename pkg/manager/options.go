@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// GetConfigOptionBool 获取布尔类型的配置选项
+//
+// 与 NuGet 一致，只接受不区分大小写的 true 和 false。
+// 选项未设置时 ok 为 false；值无法解析时返回错误。
+func (m *ConfigManager) GetConfigOptionBool(config *types.NuGetConfig, key string) (value bool, ok bool, err error) {
+	raw, ok := m.lookupConfigOption(config, key)
+	if !ok {
+		return false, false, nil
+	}
+
+	switch strings.ToLower(raw) {
+	case "true":
+		return true, true, nil
+	case "false":
+		return false, true, nil
+	}
+	return false, true, pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' is not a valid boolean: %q", key, raw)
+}
+
+// GetConfigOptionInt 获取整数类型的配置选项
+//
+// 选项未设置时 ok 为 false；值无法解析时返回错误。
+func (m *ConfigManager) GetConfigOptionInt(config *types.NuGetConfig, key string) (value int, ok bool, err error) {
+	raw, ok := m.lookupConfigOption(config, key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	value, err = strconv.Atoi(raw)
+	if err != nil {
+		return 0, true, pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' is not a valid integer: %q", key, raw)
+	}
+	return value, true, nil
+}
+
+// GetConfigOptionDuration 获取时长类型的配置选项
+//
+// NuGet 的超时类选项以秒为单位，因此纯数字按秒解析；也接受 "1m30s" 这样的 Go 时长格式。
+// 选项未设置时 ok 为 false；值无法解析或为负数时返回错误。
+func (m *ConfigManager) GetConfigOptionDuration(config *types.NuGetConfig, key string) (value time.Duration, ok bool, err error) {
+	raw, ok := m.lookupConfigOption(config, key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	if seconds, convErr := strconv.Atoi(raw); convErr == nil {
+		value = time.Duration(seconds) * time.Second
+	} else if value, err = time.ParseDuration(raw); err != nil {
+		return 0, true, pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' is not a valid duration: %q", key, raw)
+	}
+
+	if value < 0 {
+		return 0, true, pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' must not be negative: %q", key, raw)
+	}
+	return value, true, nil
+}
+
+// GetConfigOptionPath 获取路径类型的配置选项，并展开其中的环境变量
+//
+// 支持 %VAR%、$VAR 和 ${VAR}，值中引用了未定义的环境变量时返回错误。
+// 相对路径保持不变，需要按配置文件位置解析时请使用 ResolveConfigOptionPath。
+// 选项未设置时 ok 为 false。
+func (m *ConfigManager) GetConfigOptionPath(config *types.NuGetConfig, key string) (value string, ok bool, err error) {
+	raw, ok := m.lookupConfigOption(config, key)
+	if !ok {
+		return "", false, nil
+	}
+
+	undefined := ""
+	value = utils.ExpandEnvVarsWithLookup(raw, func(name string) (string, bool) {
+		env, exists := os.LookupEnv(name)
+		if !exists && undefined == "" {
+			undefined = name
+		}
+		return env, exists
+	})
+	if undefined != "" {
+		return value, true, pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' references undefined environment variable '%s'", key, undefined)
+	}
+	return value, true, nil
+}
+
+// lookupConfigOption 获取去掉首尾空白的配置选项值，选项未设置或为空时返回 false
+func (m *ConfigManager) lookupConfigOption(config *types.NuGetConfig, key string) (string, bool) {
+	raw := strings.TrimSpace(m.GetConfigOption(config, key))
+	return raw, raw != ""
+}
+
+// MaxHTTPRequestsPerSourceKey 限制每个包源并发HTTP请求数的配置选项
+const MaxHTTPRequestsPerSourceKey = "maxHttpRequestsPerSource"
+
+// GetMaxHTTPRequestsPerSource 获取每个包源的最大并发HTTP请求数
+//
+// 选项未设置时 ok 为 false，此时 NuGet 使用平台默认值；值不是正整数时返回错误。
+func (m *ConfigManager) GetMaxHTTPRequestsPerSource(config *types.NuGetConfig) (value int, ok bool, err error) {
+	value, ok, err = m.GetConfigOptionInt(config, MaxHTTPRequestsPerSourceKey)
+	if err != nil || !ok {
+		return value, ok, err
+	}
+	if err := validateIntOption(MaxHTTPRequestsPerSourceKey, value); err != nil {
+		return 0, true, err
+	}
+	return value, true, nil
+}
+
+// SetMaxHTTPRequestsPerSource 设置每个包源的最大并发HTTP请求数，value 必须是正整数
+func (m *ConfigManager) SetMaxHTTPRequestsPerSource(config *types.NuGetConfig, value int) error {
+	return m.SetConfigOptionInt(config, MaxHTTPRequestsPerSourceKey, value)
+}
+
+// SetConfigOptionInt 设置整数类型的配置选项
+//
+// key 是 types.KnownConfigOptions 中的整数选项时按其取值范围检查，超出范围时返回错误且配置不会被修改。
+func (m *ConfigManager) SetConfigOptionInt(config *types.NuGetConfig, key string, value int) error {
+	if err := validateIntOption(key, value); err != nil {
+		return err
+	}
+	m.AddConfigOption(config, key, strconv.Itoa(value))
+	return nil
+}
+
+// SetConfigOptionDuration 设置时长类型的配置选项，以整数秒写入
+//
+// value 必须是非负的整秒数，与 GetConfigOptionDuration 读取的格式一致。
+func (m *ConfigManager) SetConfigOptionDuration(config *types.NuGetConfig, key string, value time.Duration) error {
+	if value < 0 {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' must not be negative: %s", key, value)
+	}
+	if value%time.Second != 0 {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidConfigValue, "config option '%s' must be a whole number of seconds: %s", key, value)
+	}
+	m.AddConfigOption(config, key, strconv.FormatInt(int64(value/time.Second), 10))
+	return nil
+}
+
+// validateIntOption 按已知整数选项的取值范围检查值，未知选项不做检查
+func validateIntOption(key string, value int) error {
+	spec, known := types.LookupConfigOption(key)
+	if !known || spec.Kind != types.OptionInt {
+		return nil
+	}
+	return spec.Validate(strconv.Itoa(value))
+}
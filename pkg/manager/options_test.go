@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestTypedConfigOptions(t *testing.T) {
+	restore := nugetTesting.SetupEnv(t, "NUGET_TEST_ROOT", "/data")
+	defer restore()
+
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	for key, value := range map[string]string{
+		"signatureValidation":  " True ",
+		"badBool":              "yes",
+		"maxHttpRequests":      "16",
+		"badInt":               "many",
+		"timeoutSeconds":       "90",
+		"timeoutGo":            "1m30s",
+		"negative":             "-5",
+		"globalPackagesFolder": "%NUGET_TEST_ROOT%/packages",
+		"missingVarPath":       "%NUGET_TEST_UNDEFINED%/packages",
+	} {
+		manager.AddConfigOption(config, key, value)
+	}
+
+	if value, ok, err := manager.GetConfigOptionBool(config, "signatureValidation"); !value || !ok || err != nil {
+		t.Errorf("GetConfigOptionBool() = %v, %v, %v", value, ok, err)
+	}
+	if _, ok, err := manager.GetConfigOptionBool(config, "badBool"); !ok || err == nil {
+		t.Error("GetConfigOptionBool(badBool) should fail")
+	}
+	if _, ok, err := manager.GetConfigOptionBool(config, "unset"); ok || err != nil {
+		t.Errorf("GetConfigOptionBool(unset) = %v, %v", ok, err)
+	}
+
+	if value, ok, err := manager.GetConfigOptionInt(config, "maxHttpRequests"); value != 16 || !ok || err != nil {
+		t.Errorf("GetConfigOptionInt() = %v, %v, %v", value, ok, err)
+	}
+	if _, _, err := manager.GetConfigOptionInt(config, "badInt"); err == nil {
+		t.Error("GetConfigOptionInt(badInt) should fail")
+	}
+
+	for _, key := range []string{"timeoutSeconds", "timeoutGo"} {
+		if value, ok, err := manager.GetConfigOptionDuration(config, key); value != 90*time.Second || !ok || err != nil {
+			t.Errorf("GetConfigOptionDuration(%s) = %v, %v, %v", key, value, ok, err)
+		}
+	}
+	if _, _, err := manager.GetConfigOptionDuration(config, "negative"); err == nil {
+		t.Error("GetConfigOptionDuration(negative) should fail")
+	}
+
+	if value, ok, err := manager.GetConfigOptionPath(config, "globalPackagesFolder"); value != "/data/packages" || !ok || err != nil {
+		t.Errorf("GetConfigOptionPath() = %v, %v, %v", value, ok, err)
+	}
+	if _, _, err := manager.GetConfigOptionPath(config, "missingVarPath"); err == nil {
+		t.Error("GetConfigOptionPath() with undefined variable should fail")
+	}
+}
+
+func TestSetConfigOptionValidation(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+
+	// 默认不校验
+	if err := manager.SetConfigOption(config, "customKey", "value"); err != nil {
+		t.Fatalf("SetConfigOption() error = %v", err)
+	}
+
+	manager.ValidateConfigOptions = true
+	if err := manager.SetConfigOption(config, "maxHttpRequestsPerSource", "8"); err != nil {
+		t.Errorf("SetConfigOption(valid) error = %v", err)
+	}
+	if err := manager.SetConfigOption(config, "globalPackageFolder", "/packages"); err == nil {
+		t.Error("SetConfigOption() with unknown key should fail")
+	} else if !strings.Contains(err.Error(), "did you mean 'globalPackagesFolder'?") {
+		t.Errorf("SetConfigOption() with misspelled key error = %v, want a suggestion", err)
+	}
+	if err := manager.SetConfigOption(config, "maxHttpRequestsPerSource", "-1"); err == nil {
+		t.Error("SetConfigOption() with out of range value should fail")
+	}
+	if got := manager.GetConfigOption(config, "maxHttpRequestsPerSource"); got != "8" {
+		t.Errorf("failed SetConfigOption() modified config: %q", got)
+	}
+}
+
+func TestNumericConfigOptions(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+
+	if _, ok, err := manager.GetMaxHTTPRequestsPerSource(config); ok || err != nil {
+		t.Errorf("GetMaxHTTPRequestsPerSource() on empty config = %v, %v", ok, err)
+	}
+	if err := manager.SetMaxHTTPRequestsPerSource(config, 32); err != nil {
+		t.Fatalf("SetMaxHTTPRequestsPerSource() error = %v", err)
+	}
+	if value, ok, err := manager.GetMaxHTTPRequestsPerSource(config); value != 32 || !ok || err != nil {
+		t.Errorf("GetMaxHTTPRequestsPerSource() = %v, %v, %v", value, ok, err)
+	}
+	if err := manager.SetMaxHTTPRequestsPerSource(config, 0); err == nil {
+		t.Error("SetMaxHTTPRequestsPerSource(0) should fail")
+	}
+
+	manager.AddConfigOption(config, MaxHTTPRequestsPerSourceKey, "-3")
+	if _, ok, err := manager.GetMaxHTTPRequestsPerSource(config); !ok || err == nil {
+		t.Error("GetMaxHTTPRequestsPerSource() with out of range value should fail")
+	}
+
+	if err := manager.SetConfigOptionDuration(config, "customTimeout", 2*time.Minute); err != nil {
+		t.Fatalf("SetConfigOptionDuration() error = %v", err)
+	}
+	if value, _, err := manager.GetConfigOptionDuration(config, "customTimeout"); value != 2*time.Minute || err != nil {
+		t.Errorf("GetConfigOptionDuration() = %v, %v", value, err)
+	}
+	for _, invalid := range []time.Duration{-time.Second, 1500 * time.Millisecond} {
+		if err := manager.SetConfigOptionDuration(config, "customTimeout", invalid); err == nil {
+			t.Errorf("SetConfigOptionDuration(%v) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// aggregateSource activePackageSource 中表示"所有包源"的特殊值
+const aggregateSource = "(Aggregate source)"
+
+// OrphanedEntry 引用了未定义包源的配置项
+type OrphanedEntry struct {
+	// Section 配置项所在的配置节：disabledPackageSources、packageSourceCredentials、
+	// activePackageSource 或 packageSourceMapping
+	Section string
+	// Key 被引用的包源名称
+	Key string
+}
+
+// FindOrphanedEntries 查找引用了 packageSources 中未定义的包源的配置项
+//
+// 检查禁用包源、凭证、活跃包源和包源映射，包源名称不区分大小写。NuGet 会静默忽略
+// 这些配置项，通常是包源改名或删除后遗留的。包源可以定义在配置层级中的其他文件里，
+// 因此应当对合并后的配置调用，结果按上面列出的配置节顺序排列。
+func (m *ConfigManager) FindOrphanedEntries(config *types.NuGetConfig) []OrphanedEntry {
+	defined := make(map[string]bool)
+	for _, source := range config.PackageSources.Add {
+		defined[strings.ToLower(strings.TrimSpace(source.Key))] = true
+	}
+
+	var orphans []OrphanedEntry
+	check := func(section, key string) {
+		if !defined[strings.ToLower(strings.TrimSpace(key))] {
+			orphans = append(orphans, OrphanedEntry{Section: section, Key: key})
+		}
+	}
+
+	if disabled := config.DisabledPackageSources; disabled != nil {
+		for _, source := range disabled.Add {
+			check("disabledPackageSources", source.Key)
+		}
+	}
+	if creds := config.PackageSourceCredentials; creds != nil {
+		names := make([]string, 0, len(creds.Sources))
+		for name := range creds.Sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			check("packageSourceCredentials", name)
+		}
+	}
+	if active := config.ActivePackageSource; active != nil && active.Add.Key != "" && active.Add.Value != aggregateSource {
+		check("activePackageSource", active.Add.Key)
+	}
+	if mapping := config.PackageSourceMapping; mapping != nil {
+		for _, group := range mapping.PackageSource {
+			check("packageSourceMapping", group.Key)
+		}
+	}
+	return orphans
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestFindOrphanedEntries(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{Add: []types.PackageSource{
+			{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+		}},
+		DisabledPackageSources: &types.DisabledPackageSources{Add: []types.DisabledSource{
+			{Key: "NuGet.org", Value: "true"},
+			{Key: "old-feed", Value: "true"},
+		}},
+		PackageSourceCredentials: &types.PackageSourceCredentials{Sources: map[string]types.SourceCredential{
+			"nuget.org": {},
+			"ghost":     {},
+		}},
+		ActivePackageSource: &types.ActivePackageSource{Add: types.PackageSource{Key: "renamed", Value: "https://renamed.example.com"}},
+		PackageSourceMapping: &types.PackageSourceMapping{PackageSource: []types.PackageSourceMappingSource{
+			{Key: "nuget.org", Package: []types.PackagePattern{{Pattern: "*"}}},
+			{Key: "contoso", Package: []types.PackagePattern{{Pattern: "Contoso.*"}}},
+		}},
+	}
+
+	want := []OrphanedEntry{
+		{Section: "disabledPackageSources", Key: "old-feed"},
+		{Section: "packageSourceCredentials", Key: "ghost"},
+		{Section: "activePackageSource", Key: "renamed"},
+		{Section: "packageSourceMapping", Key: "contoso"},
+	}
+	m := NewConfigManager()
+	if got := m.FindOrphanedEntries(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindOrphanedEntries() = %+v, want %+v", got, want)
+	}
+
+	// 聚合包源不引用具体的包源
+	config.ActivePackageSource.Add = types.PackageSource{Key: "All", Value: "(Aggregate source)"}
+	if got := m.FindOrphanedEntries(config); len(got) != 3 {
+		t.Errorf("FindOrphanedEntries() with aggregate source = %+v", got)
+	}
+}
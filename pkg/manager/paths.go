@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// PathOptionKeys config 配置节中值为路径的选项
+//
+// NuGet 将这些选项中的相对路径按定义它们的配置文件所在目录解析。
+var PathOptionKeys = []string{"globalPackagesFolder", "repositoryPath"}
+
+// ResolveConfigPath 将配置文件中的路径值解析为绝对路径
+//
+// 值中的 %VAR%、$VAR 和 ${VAR} 会先被展开，未定义的变量保持原样；
+// 展开后仍是相对路径时按 configPath 所在目录解析。value 为空时返回空字符串。
+func (m *ConfigManager) ResolveConfigPath(configPath string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	value = utils.ExpandEnvVarsWithLookup(value, os.LookupEnv)
+	if filepath.IsAbs(value) {
+		return filepath.Clean(value)
+	}
+
+	baseDir := filepath.Dir(configPath)
+	if absDir, err := filepath.Abs(baseDir); err == nil {
+		baseDir = absDir
+	}
+	return utils.ResolvePath(baseDir, value)
+}
+
+// ResolveConfigOptionPath 获取路径类配置选项解析后的绝对路径
+//
+// configPath 是 config 所在的配置文件路径，例如 FindAndLoadConfig 返回的路径。
+// 选项未设置时第二个返回值为 false。
+func (m *ConfigManager) ResolveConfigOptionPath(config *types.NuGetConfig, configPath string, key string) (string, bool) {
+	value := m.GetConfigOption(config, key)
+	if value == "" {
+		return "", false
+	}
+	return m.ResolveConfigPath(configPath, value), true
+}
+
+// ResolveConfigPaths 解析配置中所有已设置的路径类选项，返回选项键到绝对路径的映射
+func (m *ConfigManager) ResolveConfigPaths(config *types.NuGetConfig, configPath string) map[string]string {
+	paths := make(map[string]string)
+	for _, key := range PathOptionKeys {
+		if path, ok := m.ResolveConfigOptionPath(config, configPath, key); ok {
+			paths[key] = path
+		}
+	}
+	return paths
+}
@@ -0,0 +1,39 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestResolveConfigPaths(t *testing.T) {
+	restore := nugetTesting.SetupEnv(t, "NUGET_TEST_ROOT", "/data")
+	defer restore()
+
+	configDir := filepath.Join(string(filepath.Separator), "repo", "src")
+	configPath := filepath.Join(configDir, "NuGet.Config")
+
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	manager.AddConfigOption(config, "repositoryPath", "../packages")
+	manager.AddConfigOption(config, "globalPackagesFolder", "%NUGET_TEST_ROOT%/nuget")
+
+	path, ok := manager.ResolveConfigOptionPath(config, configPath, "repositoryPath")
+	if want := filepath.Join(string(filepath.Separator), "repo", "packages"); !ok || path != want {
+		t.Errorf("ResolveConfigOptionPath(repositoryPath) = %q, %v, want %q", path, ok, want)
+	}
+	if _, ok := manager.ResolveConfigOptionPath(config, configPath, "missing"); ok {
+		t.Error("ResolveConfigOptionPath(missing) should report not set")
+	}
+
+	paths := manager.ResolveConfigPaths(config, configPath)
+	if len(paths) != 2 || paths["globalPackagesFolder"] != filepath.FromSlash("/data/nuget") {
+		t.Errorf("ResolveConfigPaths() = %v", paths)
+	}
+
+	if got := manager.ResolveConfigPath(configPath, ""); got != "" {
+		t.Errorf("ResolveConfigPath(\"\") = %q, want empty", got)
+	}
+}
@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"net/url"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// 代理相关的配置选项键
+const (
+	proxyKey         = "http_proxy"
+	proxyUserKey     = "http_proxy.user"
+	proxyPasswordKey = "http_proxy.password"
+)
+
+// ProxySettings HTTP代理设置
+type ProxySettings struct {
+	URL      string
+	Username string
+	// Password 配置文件中保存的密码，NuGet 在 Windows 上保存的是加密后的值
+	Password string
+}
+
+// SetProxy 设置HTTP代理及其凭证
+//
+// http_proxy、http_proxy.user 和 http_proxy.password 三个配置选项作为一个整体更新：
+// username 为空时移除已有的用户名和密码。proxyURL 必须是带主机名的 http 或 https 地址；
+// 校验失败时配置不会被修改。
+func (m *ConfigManager) SetProxy(config *types.NuGetConfig, proxyURL string, username string, password string) error {
+	if err := validateProxyURL(proxyURL); err != nil {
+		return err
+	}
+	if username == "" && password != "" {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidProxy, "proxy password requires a username")
+	}
+
+	m.AddConfigOption(config, proxyKey, proxyURL)
+	if username == "" {
+		m.RemoveConfigOption(config, proxyUserKey)
+		m.RemoveConfigOption(config, proxyPasswordKey)
+		return nil
+	}
+
+	m.AddConfigOption(config, proxyUserKey, username)
+	if password == "" {
+		m.RemoveConfigOption(config, proxyPasswordKey)
+	} else {
+		m.AddConfigOption(config, proxyPasswordKey, password)
+	}
+	return nil
+}
+
+// GetProxy 获取HTTP代理设置，未配置代理时返回 nil
+func (m *ConfigManager) GetProxy(config *types.NuGetConfig) *ProxySettings {
+	proxyURL := m.GetConfigOption(config, proxyKey)
+	if proxyURL == "" {
+		return nil
+	}
+	return &ProxySettings{
+		URL:      proxyURL,
+		Username: m.GetConfigOption(config, proxyUserKey),
+		Password: m.GetConfigOption(config, proxyPasswordKey),
+	}
+}
+
+// RemoveProxy 移除HTTP代理及其凭证，配置中原本有代理相关选项时返回 true
+func (m *ConfigManager) RemoveProxy(config *types.NuGetConfig) bool {
+	removed := false
+	for _, key := range []string{proxyKey, proxyUserKey, proxyPasswordKey} {
+		if m.RemoveConfigOption(config, key) {
+			removed = true
+		}
+	}
+	return removed
+}
+
+// validateProxyURL 检查代理地址是否为带主机名的 http 或 https 地址
+func validateProxyURL(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidProxy, "invalid proxy URL '%s': %w", proxyURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidProxy, "invalid proxy URL '%s': scheme must be http or https", proxyURL)
+	}
+	if u.Host == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidProxy, "invalid proxy URL '%s': missing host", proxyURL)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestProxySettings(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+
+	if proxy := manager.GetProxy(config); proxy != nil {
+		t.Errorf("GetProxy() on empty config = %+v, want nil", proxy)
+	}
+
+	if err := manager.SetProxy(config, "http://proxy.example.com:8080", "user", "secret"); err != nil {
+		t.Fatalf("SetProxy() error = %v", err)
+	}
+	want := ProxySettings{URL: "http://proxy.example.com:8080", Username: "user", Password: "secret"}
+	if proxy := manager.GetProxy(config); proxy == nil || *proxy != want {
+		t.Errorf("GetProxy() = %+v, want %+v", proxy, want)
+	}
+
+	// 不带用户名时移除原有凭证
+	if err := manager.SetProxy(config, "https://proxy.example.com", "", ""); err != nil {
+		t.Fatalf("SetProxy() error = %v", err)
+	}
+	want = ProxySettings{URL: "https://proxy.example.com"}
+	if proxy := manager.GetProxy(config); proxy == nil || *proxy != want {
+		t.Errorf("GetProxy() = %+v, want %+v", proxy, want)
+	}
+	if len(config.Config.Add) != 1 {
+		t.Errorf("config options = %+v, want only http_proxy", config.Config.Add)
+	}
+
+	for _, invalid := range []string{"", "proxy.example.com:8080", "ftp://proxy.example.com", "http://", "http://%zz"} {
+		if err := manager.SetProxy(config, invalid, "", ""); err == nil {
+			t.Errorf("SetProxy(%q) should fail", invalid)
+		}
+	}
+	if err := manager.SetProxy(config, "http://proxy.example.com", "", "secret"); err == nil {
+		t.Error("SetProxy() with password but no username should fail")
+	}
+	if manager.GetProxy(config).URL != "https://proxy.example.com" {
+		t.Error("failed SetProxy() modified config")
+	}
+
+	if !manager.RemoveProxy(config) {
+		t.Error("RemoveProxy() = false, want true")
+	}
+	if manager.GetProxy(config) != nil || manager.RemoveProxy(config) {
+		t.Error("proxy settings not removed")
+	}
+}
@@ -0,0 +1,232 @@
+package manager
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+var (
+	roundTripSourceKeys = []string{"alpha-feed", "bravo-feed", "charlie-feed", "delta-feed", "echo-feed"}
+	roundTripOptionKeys = []string{"globalPackagesFolder", "http_proxy", "signatureValidationMode"}
+)
+
+// roundTripCase 随机生成的配置文件，格式细节（缩进、换行符、引号、自闭合方式、注释等）各不相同
+type roundTripCase struct {
+	Content string
+	Sources []string
+	Options []string
+	// Op 要执行的修改，见 apply
+	Op   int
+	Pick int
+}
+
+// Generate 实现 quick.Generator
+func (roundTripCase) Generate(r *rand.Rand, _ int) reflect.Value {
+	indent := []string{"  ", "    ", "\t"}[r.Intn(3)]
+	eol := []string{"\n", "\r\n"}[r.Intn(2)]
+	quote := []string{`"`, `'`}[r.Intn(2)]
+	selfClose := []string{" />", "/>"}[r.Intn(2)]
+
+	attr := func(name, value string) string {
+		return fmt.Sprintf(" %s=%s%s%s", name, quote, value, quote)
+	}
+
+	var sb strings.Builder
+	if r.Intn(2) == 0 {
+		sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + eol)
+	}
+	sb.WriteString("<configuration>" + eol)
+	if r.Intn(2) == 0 {
+		sb.WriteString(indent + "<!-- generated for round-trip tests -->" + eol)
+	}
+
+	c := roundTripCase{Op: r.Intn(5)}
+
+	sb.WriteString(indent + "<packageSources>" + eol)
+	for i, key := range roundTripSourceKeys[:1+r.Intn(len(roundTripSourceKeys))] {
+		sb.WriteString(indent + indent + "<add" + attr("key", key) + attr("value", fmt.Sprintf("https://feed%d.example.com/v3/index.json", i)))
+		if r.Intn(3) == 0 {
+			sb.WriteString(attr("protocolVersion", "3"))
+		}
+		sb.WriteString(selfClose + eol)
+		c.Sources = append(c.Sources, key)
+	}
+	sb.WriteString(indent + "</packageSources>" + eol)
+
+	if r.Intn(2) == 0 {
+		if r.Intn(2) == 0 {
+			sb.WriteString(eol)
+		}
+		sb.WriteString(indent + "<config>" + eol)
+		for _, key := range roundTripOptionKeys[:1+r.Intn(len(roundTripOptionKeys))] {
+			sb.WriteString(indent + indent + "<add" + attr("key", key) + attr("value", "v-"+key) + selfClose + eol)
+			c.Options = append(c.Options, key)
+		}
+		sb.WriteString(indent + "</config>" + eol)
+	}
+
+	sb.WriteString("</configuration>")
+	if r.Intn(2) == 0 {
+		sb.WriteString(eol)
+	}
+
+	c.Content = sb.String()
+	c.Pick = r.Intn(100)
+	return reflect.ValueOf(c)
+}
+
+// apply 通过 ConfigManager 修改配置，返回受影响的 key
+func (c roundTripCase) apply(m *ConfigManager, config *types.NuGetConfig) string {
+	source := c.Sources[c.Pick%len(c.Sources)]
+	switch c.Op {
+	case 0:
+		m.AddPackageSource(config, source, "https://updated.example.com/v3/index.json", "")
+		return source
+	case 1:
+		m.RemovePackageSource(config, source)
+		return source
+	case 2:
+		m.AddPackageSource(config, "zulu-feed", "https://zulu.example.com/v3/index.json", "3")
+		return "zulu-feed"
+	default:
+		if len(c.Options) == 0 {
+			m.AddPackageSource(config, source, "https://updated.example.com/v3/index.json", "")
+			return source
+		}
+		if c.Op == 3 {
+			option := c.Options[c.Pick%len(c.Options)]
+			m.AddConfigOption(config, option, "updated")
+			return option
+		}
+		m.AddConfigOption(config, "defaultPushSource", "https://push.example.com")
+		return "defaultPushSource"
+	}
+}
+
+// linesWithout 返回不包含 key 的所有行
+func linesWithout(content, key string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.Contains(line, key) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func newRoundTripFile(t *testing.T, content string) string {
+	tempDir := nugetTesting.CreateTempDir(t)
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return configPath
+}
+
+func TestRoundTripWithoutEditsIsByteIdentical(t *testing.T) {
+	property := func(c roundTripCase) bool {
+		configPath := newRoundTripFile(t, c.Content)
+
+		m := NewConfigManager()
+		m.PreserveFormatting = true
+		config, err := m.LoadConfig(configPath)
+		if err != nil {
+			t.Logf("LoadConfig() error = %v\n%s", err, c.Content)
+			return false
+		}
+
+		savedPath := configPath + ".saved"
+		if err := m.SaveConfig(config, savedPath); err != nil {
+			t.Logf("SaveConfig() error = %v", err)
+			return false
+		}
+
+		saved, _ := os.ReadFile(savedPath)
+		if string(saved) != c.Content {
+			t.Logf("saved content differs:\n%q\n%q", saved, c.Content)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoundTripEditsOnlyTouchEditedRanges(t *testing.T) {
+	property := func(c roundTripCase) bool {
+		configPath := newRoundTripFile(t, c.Content)
+
+		m := NewConfigManager()
+		m.PreserveFormatting = true
+		config, err := m.LoadConfig(configPath)
+		if err != nil {
+			t.Logf("LoadConfig() error = %v\n%s", err, c.Content)
+			return false
+		}
+
+		key := c.apply(m, config)
+		expected := *config
+		if err := m.SaveConfig(config, configPath); err != nil {
+			t.Logf("SaveConfig() error = %v\n%s", err, c.Content)
+			return false
+		}
+
+		saved, _ := os.ReadFile(configPath)
+		if !reflect.DeepEqual(linesWithout(string(saved), key), linesWithout(c.Content, key)) {
+			t.Logf("lines unrelated to %q changed:\n%s\n---\n%s", key, saved, c.Content)
+			return false
+		}
+
+		p := parser.NewConfigParser()
+		p.AllowEmptyPackageSources = true
+		reparsed, err := p.ParseFromContent(saved)
+		if err != nil {
+			t.Logf("saved content cannot be parsed: %v", err)
+			return false
+		}
+		sameSources := len(reparsed.PackageSources.Add) == 0 && len(expected.PackageSources.Add) == 0 ||
+			reflect.DeepEqual(reparsed.PackageSources.Add, expected.PackageSources.Add)
+		if !sameSources || !reflect.DeepEqual(reparsed.Config, expected.Config) {
+			t.Logf("saved config differs from the in-memory config:\n%s", saved)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSaveConfigWithoutPreserveFormattingReserializes(t *testing.T) {
+	content := "<configuration>\r\n\t<packageSources>\r\n\t\t<add key='a' value='https://a.example.com'/>\r\n\t</packageSources>\r\n</configuration>"
+	configPath := newRoundTripFile(t, content)
+
+	m := NewConfigManager()
+	config, err := m.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if err := m.SaveConfig(config, configPath); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	saved, _ := os.ReadFile(configPath)
+	if string(saved) == content {
+		t.Error("SaveConfig() without PreserveFormatting should reserialize the config")
+	}
+}
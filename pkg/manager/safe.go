@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"sync"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// SafeManager 持有一个已加载的配置，可在多个 goroutine 之间共享
+//
+// 读操作可以并发执行，写操作和保存相互串行。Update 在配置的副本上执行修改，
+// 回调返回错误时丢弃所有修改，其他 goroutine 不会看到修改了一半的配置。
+//
+// SafeManager 独占传入的 ConfigManager，创建后不应再直接使用它。
+type SafeManager struct {
+	mu      sync.RWMutex
+	manager *ConfigManager
+	config  *types.NuGetConfig
+	path    string
+}
+
+// NewSafeManager 使用已加载的配置创建 SafeManager，path 为保存时写入的文件
+func NewSafeManager(manager *ConfigManager, config *types.NuGetConfig, path string) *SafeManager {
+	return &SafeManager{
+		manager: manager,
+		config:  config,
+		path:    path,
+	}
+}
+
+// LoadSafeManager 使用 manager 加载配置文件并创建 SafeManager
+func LoadSafeManager(manager *ConfigManager, path string) (*SafeManager, error) {
+	config, err := manager.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSafeManager(manager, config, path), nil
+}
+
+// Path 返回配置文件路径
+func (s *SafeManager) Path() string {
+	return s.path
+}
+
+// Read 在读锁下调用 fn，fn 不得修改或在返回后继续持有 config
+func (s *SafeManager) Read(fn func(config *types.NuGetConfig) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.config)
+}
+
+// Snapshot 返回当前配置的深拷贝，调用方可以自由使用
+func (s *SafeManager) Snapshot() *types.NuGetConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config.Clone()
+}
+
+// Update 在写锁下修改配置
+//
+// fn 接收配置的副本，可以使用 m 上的方法修改它；fn 返回 nil 时修改生效，
+// 返回错误时配置保持不变并返回该错误。修改只在内存中生效，需要调用 Save 写入文件。
+func (s *SafeManager) Update(fn func(m *ConfigManager, config *types.NuGetConfig) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	working := s.config.Clone()
+	if err := fn(s.manager, working); err != nil {
+		return err
+	}
+
+	// 保持配置对象不变，以便保留格式的保存仍能找到对应的编辑器
+	*s.config = *working
+	return nil
+}
+
+// Save 将当前配置写入配置文件
+func (s *SafeManager) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.manager.SaveConfig(s.config, s.path)
+}
+
+// Reload 重新从配置文件加载配置，丢弃未保存的修改
+func (s *SafeManager) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	config, err := s.manager.LoadConfig(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.manager.Untrack(s.config)
+	s.config = config
+	return nil
+}
@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestSafeManagerConcurrentAccess(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	m := NewConfigManager()
+	m.PreserveFormatting = true
+	safe, err := LoadSafeManager(m, configPath)
+	if err != nil {
+		t.Fatalf("LoadSafeManager() error = %v", err)
+	}
+	initial := len(safe.Snapshot().PackageSources.Add)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			err := safe.Update(func(m *ConfigManager, config *types.NuGetConfig) error {
+				m.AddPackageSource(config, fmt.Sprintf("feed%d", i), fmt.Sprintf("https://feed%d.example.com/v3/index.json", i), "3")
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			_ = safe.Read(func(config *types.NuGetConfig) error {
+				_ = len(config.PackageSources.Add)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := len(safe.Snapshot().PackageSources.Add); got != initial+20 {
+		t.Errorf("source count = %d, want %d", got, initial+20)
+	}
+
+	// 回调失败时修改被丢弃
+	failure := errors.New("rejected")
+	err = safe.Update(func(m *ConfigManager, config *types.NuGetConfig) error {
+		m.RemovePackageSource(config, "feed0")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Update() error = %v, want %v", err, failure)
+	}
+	if m.GetPackageSource(safe.Snapshot(), "feed0") == nil {
+		t.Error("failed Update() modified config")
+	}
+
+	if err := safe.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "feed19") {
+		t.Error("Save() did not write the updates")
+	}
+
+	if err := safe.Update(func(m *ConfigManager, config *types.NuGetConfig) error {
+		m.RemovePackageSource(config, "feed1")
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := safe.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if m.GetPackageSource(safe.Snapshot(), "feed1") == nil {
+		t.Error("Reload() did not discard unsaved changes")
+	}
+}
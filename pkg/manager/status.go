@@ -0,0 +1,46 @@
+package manager
+
+import "github.com/scagogogo/nuget-config-parser/pkg/types"
+
+// SourceStatus 包源及其在其他配置节中的状态
+type SourceStatus struct {
+	Key             string
+	Value           string
+	ProtocolVersion string
+	// Enabled 包源未在 disabledPackageSources 中被禁用
+	Enabled bool
+	// HasCredentials 包源在 packageSourceCredentials 中有凭证
+	HasCredentials bool
+	// Active 包源是 activePackageSource 中指定的活跃包源
+	Active bool
+}
+
+// GetPackageSourceStatuses 按配置中的顺序返回所有包源的状态
+func (m *ConfigManager) GetPackageSourceStatuses(config *types.NuGetConfig) []SourceStatus {
+	statuses := make([]SourceStatus, 0, len(config.PackageSources.Add))
+	for _, source := range config.PackageSources.Add {
+		status := SourceStatus{
+			Key:             source.Key,
+			Value:           source.Value,
+			ProtocolVersion: source.ProtocolVersion,
+			Enabled:         !m.IsPackageSourceDisabled(config, source.Key),
+			Active:          config.ActivePackageSource != nil && config.ActivePackageSource.Add.Key == source.Key,
+		}
+		if config.PackageSourceCredentials != nil {
+			_, status.HasCredentials = config.PackageSourceCredentials.Sources[source.Key]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// GetEnabledPackageSources 按配置中的顺序返回所有未被禁用的包源
+func (m *ConfigManager) GetEnabledPackageSources(config *types.NuGetConfig) []types.PackageSource {
+	var sources []types.PackageSource
+	for _, source := range config.PackageSources.Add {
+		if !m.IsPackageSourceDisabled(config, source.Key) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
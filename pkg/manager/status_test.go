@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestGetPackageSourceStatuses(t *testing.T) {
+	manager := NewConfigManager()
+	config := &types.NuGetConfig{}
+	manager.AddPackageSource(config, "nuget.org", "https://api.nuget.org/v3/index.json", "3")
+	manager.AddPackageSource(config, "private", "https://private.example.com/v3/index.json", "")
+	manager.AddPackageSource(config, "legacy", "https://legacy.example.com/api/v2", "2")
+	manager.DisablePackageSource(config, "legacy")
+	manager.AddCredential(config, "private", "user", "pass")
+	if err := manager.SetActivePackageSource(config, "nuget.org"); err != nil {
+		t.Fatalf("SetActivePackageSource() error = %v", err)
+	}
+
+	want := []SourceStatus{
+		{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3", Enabled: true, Active: true},
+		{Key: "private", Value: "https://private.example.com/v3/index.json", Enabled: true, HasCredentials: true},
+		{Key: "legacy", Value: "https://legacy.example.com/api/v2", ProtocolVersion: "2"},
+	}
+	got := manager.GetPackageSourceStatuses(config)
+	if len(got) != len(want) {
+		t.Fatalf("GetPackageSourceStatuses() returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("status[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	enabled := manager.GetEnabledPackageSources(config)
+	if len(enabled) != 2 || enabled[0].Key != "nuget.org" || enabled[1].Key != "private" {
+		t.Errorf("GetEnabledPackageSources() = %+v", enabled)
+	}
+}
@@ -0,0 +1,218 @@
+package manager
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// IssueSeverity 校验问题的严重程度
+type IssueSeverity int
+
+const (
+	// SeverityError NuGet 无法正确使用的配置
+	SeverityError IssueSeverity = iota
+	// SeverityWarning NuGet 可以容忍但很可能是错误的配置
+	SeverityWarning
+)
+
+// String 返回严重程度的名称
+func (s IssueSeverity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("IssueSeverity(%d)", int(s))
+}
+
+// ValidationIssue 配置校验发现的一个问题
+type ValidationIssue struct {
+	Severity IssueSeverity
+	// Code 问题的错误码，与 validate 包中对应规则的错误码相同
+	Code pkgErrors.Code
+	// Section 问题所在的配置节，例如 "packageSources"
+	Section string
+	// Key 问题涉及的包源名称或选项键名，可能为空
+	Key     string
+	Message string
+}
+
+// String 返回问题的单行描述
+func (i ValidationIssue) String() string {
+	if i.Key == "" {
+		return fmt.Sprintf("%s: <%s>: %s", i.Severity, i.Section, i.Message)
+	}
+	return fmt.Sprintf("%s: <%s> '%s': %s", i.Severity, i.Section, i.Key, i.Message)
+}
+
+// HasErrors 判断问题列表中是否有 SeverityError 级别的问题
+func HasErrors(issues []ValidationIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateConfig 检查配置中的问题，按配置节的顺序返回发现的所有问题
+//
+// 检查的内容包括：包源名称为空或重复（不区分大小写）、包源地址为空或不是合法的 URL、
+// 凭证对应的包源不存在，以及 types.KnownConfigOptions 中已知选项的取值不合法。
+// 配置没有问题时返回空列表。
+func (m *ConfigManager) ValidateConfig(config *types.NuGetConfig) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(severity IssueSeverity, code pkgErrors.Code, section, key, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Severity: severity, Code: code, Section: section, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[string]string)
+	for _, source := range config.PackageSources.Add {
+		key := strings.TrimSpace(source.Key)
+		if key == "" {
+			add(SeverityError, pkgErrors.CodeEmptySourceKey, "packageSources", "", "package source with value %q has an empty key", source.Value)
+			continue
+		}
+		if first, exists := seen[strings.ToLower(key)]; exists {
+			add(SeverityError, pkgErrors.CodeDuplicateSourceKey, "packageSources", source.Key, "duplicate package source key, already defined as '%s'", first)
+		} else {
+			seen[strings.ToLower(key)] = source.Key
+		}
+		if err := ValidateSourceValue(source.Value); err != nil {
+			add(SeverityError, pkgErrors.CodeOf(err), "packageSources", source.Key, "%v", err)
+		}
+	}
+
+	if config.PackageSourceCredentials != nil {
+		names := make([]string, 0, len(config.PackageSourceCredentials.Sources))
+		for name := range config.PackageSourceCredentials.Sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, exists := seen[strings.ToLower(name)]; !exists {
+				add(SeverityWarning, pkgErrors.CodeOrphanedEntry, "packageSourceCredentials", name, "credentials for unknown package source")
+			}
+		}
+	}
+
+	if config.Config != nil {
+		for _, option := range config.Config.Add {
+			spec, known := types.LookupConfigOption(option.Key)
+			if !known {
+				continue
+			}
+			if err := spec.Validate(option.Value); err != nil {
+				add(SeverityError, pkgErrors.CodeOf(err), "config", option.Key, "%v", err)
+			}
+		}
+	}
+
+	return issues
+}
+
+// ValidateSourceValue 检查包源地址，URL 必须带主机名，本地路径不能为空
+func ValidateSourceValue(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidSourceValue, "value must not be empty")
+	}
+	if !strings.Contains(value, "://") {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidSourceValue, "invalid URL '%s': %w", value, err)
+	}
+	if u.Host == "" && u.Scheme != "file" {
+		return pkgErrors.Errorf(pkgErrors.CodeInvalidSourceValue, "invalid URL '%s': missing host", value)
+	}
+	return nil
+}
+
+// ValidateSourceURL 检查包源地址的格式
+//
+// 在 ValidateSourceValue 的基础上，URL 只能使用 http、https 或 file 协议。
+func ValidateSourceURL(value string) error {
+	if err := ValidateSourceValue(value); err != nil {
+		return err
+	}
+	if !strings.Contains(value, "://") {
+		return nil
+	}
+
+	u, _ := url.Parse(value)
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "file":
+		return nil
+	}
+	return pkgErrors.Errorf(pkgErrors.CodeInvalidSourceValue, "invalid URL '%s': unsupported scheme '%s', expected http, https or file", value, u.Scheme)
+}
+
+// CheckInsecureSource 检查包源是否通过未加密的 http:// 访问
+//
+// 设置了 allowInsecureConnections="true" 的包源不会被报告。
+func CheckInsecureSource(source types.PackageSource) error {
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(source.Value)), "http://") || source.InsecureConnectionsAllowed() {
+		return nil
+	}
+	return pkgErrors.Errorf(pkgErrors.CodeInsecureSource, "insecure URL '%s': plain HTTP is not encrypted, use HTTPS or set allowInsecureConnections=\"true\"", source.Value)
+}
+
+// CheckSourcePath 检查本地路径包源能否被解析
+//
+// 绝对路径总是可以接受，包括在其他平台上编写的 Windows 盘符路径和 UNC 路径；
+// 相对路径按 configPath 所在目录解析，解析后的路径必须存在。configPath 为空时
+// 无法解析相对路径，相对路径会被拒绝。URL 和包含未定义环境变量的路径不做检查。
+func CheckSourcePath(value string, configPath string) error {
+	if strings.Contains(value, "://") {
+		return nil
+	}
+
+	expanded := utils.ExpandEnvVarsWithLookup(strings.TrimSpace(value), os.LookupEnv)
+	if expanded == "" || strings.ContainsAny(expanded, "%$") || isAbsoluteSourcePath(expanded) {
+		return nil
+	}
+	if configPath == "" {
+		return pkgErrors.Errorf(pkgErrors.CodeUnresolvedSourcePath, "relative path '%s' cannot be resolved without the config file location, use an absolute path", value)
+	}
+
+	resolved := NewConfigManager().ResolveConfigPath(configPath, expanded)
+	if _, err := os.Stat(resolved); err != nil {
+		return pkgErrors.Errorf(pkgErrors.CodeUnresolvedSourcePath, "relative path '%s' resolves to '%s', which does not exist", value, resolved)
+	}
+	return nil
+}
+
+// isAbsoluteSourcePath 判断路径是否为当前平台或 Windows 上的绝对路径
+func isAbsoluteSourcePath(path string) bool {
+	if filepath.IsAbs(path) || strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	if len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return false
+	}
+	drive := path[0] | 0x20
+	return 'a' <= drive && drive <= 'z'
+}
+
+// ValidatePackageSource 严格校验包源，依次进行 ValidateSourceURL、CheckInsecureSource
+// 和 CheckSourcePath 的检查，返回第一个错误
+func ValidatePackageSource(source types.PackageSource, configPath string) error {
+	if err := ValidateSourceURL(source.Value); err != nil {
+		return err
+	}
+	if err := CheckInsecureSource(source); err != nil {
+		return err
+	}
+	return CheckSourcePath(source.Value, configPath)
+}
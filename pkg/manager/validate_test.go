@@ -0,0 +1,111 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pkgErrors "github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestValidateConfig(t *testing.T) {
+	manager := NewConfigManager()
+
+	valid := manager.CreateDefaultConfig()
+	if issues := manager.ValidateConfig(valid); len(issues) != 0 {
+		t.Errorf("ValidateConfig() on default config = %v, want no issues", issues)
+	}
+
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{
+				{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+				{Key: "NuGet.org", Value: "https://other.example.com/v3/index.json"},
+				{Key: "", Value: "/tmp/packages"},
+				{Key: "broken", Value: "https:///v3/index.json"},
+				{Key: "empty", Value: " "},
+				{Key: "local", Value: "/tmp/packages"},
+			},
+		},
+		PackageSourceCredentials: &types.PackageSourceCredentials{
+			Sources: map[string]types.SourceCredential{
+				"nuget.org": {},
+				"missing":   {},
+			},
+		},
+		Config: &types.Config{
+			Add: []types.ConfigOption{
+				{Key: "maxHttpRequestsPerSource", Value: "0"},
+				{Key: "dependencyVersion", Value: "Highest"},
+				{Key: "customOption", Value: "anything"},
+			},
+		},
+	}
+
+	want := []struct {
+		severity IssueSeverity
+		code     pkgErrors.Code
+		section  string
+		key      string
+	}{
+		{SeverityError, pkgErrors.CodeDuplicateSourceKey, "packageSources", "NuGet.org"},
+		{SeverityError, pkgErrors.CodeEmptySourceKey, "packageSources", ""},
+		{SeverityError, pkgErrors.CodeInvalidSourceValue, "packageSources", "broken"},
+		{SeverityError, pkgErrors.CodeInvalidSourceValue, "packageSources", "empty"},
+		{SeverityWarning, pkgErrors.CodeOrphanedEntry, "packageSourceCredentials", "missing"},
+		{SeverityError, pkgErrors.CodeInvalidConfigValue, "config", "maxHttpRequestsPerSource"},
+	}
+
+	issues := manager.ValidateConfig(config)
+	if len(issues) != len(want) {
+		t.Fatalf("ValidateConfig() returned %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.Severity != w.severity || got.Code != w.code || got.Section != w.section || got.Key != w.key {
+			t.Errorf("issue[%d] = %v (%s), want %s %s in <%s> for '%s'", i, got, got.Code, w.severity, w.code, w.section, w.key)
+		}
+	}
+	if !HasErrors(issues) {
+		t.Error("HasErrors() = false, want true")
+	}
+	if HasErrors(issues[4:5]) {
+		t.Error("HasErrors() = true for warnings only")
+	}
+}
+
+func TestValidatePackageSource(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "NuGet.Config")
+	if err := os.Mkdir(filepath.Join(dir, "feed"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		source     types.PackageSource
+		configPath string
+		wantErr    bool
+	}{
+		{"https", types.PackageSource{Key: "a", Value: "https://api.nuget.org/v3/index.json"}, "", false},
+		{"http", types.PackageSource{Key: "a", Value: "HTTP://nuget.example.com/v3/index.json"}, "", true},
+		{"http allowed", types.PackageSource{Key: "a", Value: "http://nuget.example.com/v3/index.json", AllowInsecureConnections: "True"}, "", false},
+		{"http not allowed", types.PackageSource{Key: "a", Value: "http://nuget.example.com/v3/index.json", AllowInsecureConnections: "false"}, "", true},
+		{"unsupported scheme", types.PackageSource{Key: "a", Value: "ftp://nuget.example.com/feed"}, "", true},
+		{"missing host", types.PackageSource{Key: "a", Value: "https:///v3/index.json"}, "", true},
+		{"file URL", types.PackageSource{Key: "a", Value: "file:///srv/feed"}, "", false},
+		{"absolute path", types.PackageSource{Key: "a", Value: "/srv/feed"}, "", false},
+		{"windows path", types.PackageSource{Key: "a", Value: `C:\packages`}, "", false},
+		{"UNC path", types.PackageSource{Key: "a", Value: `\\server\share\feed`}, "", false},
+		{"relative without config", types.PackageSource{Key: "a", Value: "feed"}, "", true},
+		{"relative existing", types.PackageSource{Key: "a", Value: "./feed"}, configPath, false},
+		{"relative missing", types.PackageSource{Key: "a", Value: "../missing-feed"}, configPath, true},
+		{"undefined variable", types.PackageSource{Key: "a", Value: "%NUGET_TEST_UNDEFINED_FEED%/feed"}, configPath, false},
+	} {
+		err := ValidatePackageSource(tc.source, tc.configPath)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: ValidatePackageSource() error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -2,13 +2,23 @@
 package nuget
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
 	"io"
+	"os"
+	"time"
 
+	"github.com/scagogogo/nuget-config-parser/pkg/credentials"
 	"github.com/scagogogo/nuget-config-parser/pkg/editor"
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
 	"github.com/scagogogo/nuget-config-parser/pkg/finder"
 	"github.com/scagogogo/nuget-config-parser/pkg/manager"
 	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/settings"
 	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/validate"
+	"github.com/scagogogo/nuget-config-parser/pkg/watcher"
 )
 
 // API 提供NuGet配置文件解析的所有功能
@@ -16,6 +26,14 @@ type API struct {
 	Parser  *parser.ConfigParser
 	Finder  *finder.ConfigFinder
 	Manager *manager.ConfigManager
+
+	// CredentialStore AddStoredCredential 等方法使用的系统凭证存储，为 nil 时使用 credentials.DefaultStore
+	CredentialStore credentials.Store
+
+	// parser、finder 和 store 由 NewAPIWithDependencies 注入，为 nil 时使用上面的默认实现
+	parser ConfigParser
+	finder ConfigFinder
+	store  ConfigStore
 }
 
 // NewAPI 创建新的API实例
@@ -54,6 +72,9 @@ func NewAPI() *API {
 //   - error: 如果解析过程中发生错误，则返回相应的错误；如果成功则为 nil
 //
 // 错误:
+//
+// 返回的错误都包装为 *errors.ConfigError，其中记录了文件路径和出错的操作（读取或解析），
+// 可以用 errors.Is 和 errors.As 判断以下底层错误：
 //   - errors.ErrConfigFileNotFound: 当指定的文件不存在时
 //   - errors.ErrEmptyConfigFile: 当文件存在但内容为空时
 //   - errors.ErrInvalidConfigFormat: 当文件内容不是有效的 XML 时
@@ -68,7 +89,7 @@ func NewAPI() *API {
 //	config, err := api.ParseFromFile("/path/to/NuGet.Config")
 //	if err != nil {
 //	    if errors.IsNotFoundError(err) {
-//	        fmt.Println("配置文件不存在")
+//	        fmt.Printf("配置文件 %s 不存在\n", errors.ConfigErrorPath(err))
 //	    } else if errors.IsFormatError(err) {
 //	        fmt.Println("配置文件格式无效")
 //	    } else {
@@ -82,7 +103,32 @@ func NewAPI() *API {
 //	    fmt.Printf("包源: %s - %s\n", source.Key, source.Value)
 //	}
 func (a *API) ParseFromFile(filePath string) (*types.NuGetConfig, error) {
-	return a.Parser.ParseFromFile(filePath)
+	return a.configParser().ParseFromFile(filePath)
+}
+
+// ParseFromFileContext 从文件解析NuGet配置，支持取消和超时
+//
+// ParseFromFileContext 与 ParseFromFile 相同，但在读取和解析前检查 ctx。
+//
+// 参数:
+//   - ctx: 控制取消和超时的上下文
+//   - filePath: 配置文件的路径
+//
+// 返回值:
+//   - *types.NuGetConfig: 解析后的配置对象，如果解析失败则为 nil
+//   - error: ctx 已取消或超时时返回 ctx.Err()，其他错误与 ParseFromFile 相同
+//
+// 示例:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//
+//	config, err := api.ParseFromFileContext(ctx, "/path/to/NuGet.Config")
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    fmt.Println("解析超时")
+//	}
+func (a *API) ParseFromFileContext(ctx context.Context, filePath string) (*types.NuGetConfig, error) {
+	return a.Parser.ParseFromFileContext(ctx, filePath)
 }
 
 // ParseFromString 从字符串解析NuGet配置
@@ -125,7 +171,7 @@ func (a *API) ParseFromFile(filePath string) (*types.NuGetConfig, error) {
 //	// 输出包源数量
 //	fmt.Printf("包含 %d 个包源\n", len(config.PackageSources.Add))
 func (a *API) ParseFromString(content string) (*types.NuGetConfig, error) {
-	return a.Parser.ParseFromString(content)
+	return a.configParser().ParseFromString(content)
 }
 
 // ParseFromReader 从io.Reader解析NuGet配置
@@ -172,7 +218,7 @@ func (a *API) ParseFromString(content string) (*types.NuGetConfig, error) {
 //	    fmt.Printf("活跃包源: %s\n", config.ActivePackageSource.Add.Key)
 //	}
 func (a *API) ParseFromReader(reader io.Reader) (*types.NuGetConfig, error) {
-	return a.Parser.ParseFromReader(reader)
+	return a.configParser().ParseFromReader(reader)
 }
 
 // FindConfigFile 查找配置文件
@@ -205,7 +251,7 @@ func (a *API) ParseFromReader(reader io.Reader) (*types.NuGetConfig, error) {
 //	    return
 //	}
 func (a *API) FindConfigFile() (string, error) {
-	return a.Finder.FindConfigFile()
+	return a.configFinder().FindConfigFile()
 }
 
 // FindAllConfigFiles 查找所有配置文件
@@ -244,7 +290,79 @@ func (a *API) FindConfigFile() (string, error) {
 //	    fmt.Printf("%s 包含 %d 个包源\n", path, len(config.PackageSources.Add))
 //	}
 func (a *API) FindAllConfigFiles() []string {
-	return a.Finder.FindAllConfigFiles()
+	return a.configFinder().FindAllConfigFiles()
+}
+
+// LocateConfigFiles 按搜索顺序列出所有候选配置文件及其级别
+//
+// LocateConfigFiles 与 FindAllConfigFiles 使用相同的搜索顺序，但每个结果都带有
+// 级别（项目、用户、机器、环境变量或自定义位置）和文件是否存在，
+// 便于调用方展示查找结果，或者选择应当修改的文件。
+//
+// 返回值:
+//   - []finder.FoundConfig: 所有候选位置，不存在的位置 Exists 为 false
+//
+// 示例:
+//
+//	for _, found := range api.LocateConfigFiles() {
+//	    if found.Exists {
+//	        fmt.Printf("[%s] %s\n", found.Level, found.Path)
+//	    }
+//	}
+func (a *API) LocateConfigFiles() []finder.FoundConfig {
+	return a.Finder.LocateConfigFiles()
+}
+
+// ScanTree 递归扫描目录树中的所有配置文件
+//
+// ScanTree 适用于需要盘点整个 monorepo 中包源使用情况的审计工具。
+// 默认跳过 node_modules、bin、obj 等目录，可以通过 a.Finder.ScanIgnore 修改。
+//
+// 参数:
+//   - root: 扫描的根目录
+//
+// 返回值:
+//   - []finder.ScannedConfig: 找到的配置文件及其所在目录和深度
+//   - error: 根目录不存在或无法读取时返回错误
+//
+// 示例:
+//
+//	configs, err := api.ScanTree("/path/to/monorepo")
+//	if err != nil {
+//	    fmt.Printf("扫描失败: %v\n", err)
+//	    return
+//	}
+//	for _, c := range configs {
+//	    fmt.Printf("%s (深度 %d)\n", c.Path, c.Depth)
+//	}
+func (a *API) ScanTree(root string) ([]finder.ScannedConfig, error) {
+	return a.Finder.ScanTree(root)
+}
+
+// FindConfigHierarchy 返回指定目录下 NuGet 会使用的配置文件链
+//
+// FindConfigHierarchy 按优先级从低到高返回机器级配置、用户级配置和从上到下的
+// 项目级配置，只包含存在的文件。结果可以作为合并配置的输入，后面的文件覆盖前面的文件。
+//
+// 参数:
+//   - startDir: 项目目录
+//
+// 返回值:
+//   - []finder.FoundConfig: 按优先级从低到高排列的配置文件
+//   - error: startDir 无法解析时返回错误
+//
+// 示例:
+//
+//	chain, err := api.FindConfigHierarchy(".")
+//	if err != nil {
+//	    fmt.Printf("查找失败: %v\n", err)
+//	    return
+//	}
+//	for _, c := range chain {
+//	    fmt.Printf("[%s] %s\n", c.Level, c.Path)
+//	}
+func (a *API) FindConfigHierarchy(startDir string) ([]finder.FoundConfig, error) {
+	return a.Finder.FindConfigHierarchy(startDir)
 }
 
 // FindProjectConfig 在项目目录中查找配置文件
@@ -286,7 +404,7 @@ func (a *API) FindAllConfigFiles() []string {
 //	    fmt.Printf("  - %s: %s\n", source.Key, source.Value)
 //	}
 func (a *API) FindProjectConfig(startDir string) (string, error) {
-	return a.Finder.FindProjectConfig(startDir)
+	return a.configFinder().FindProjectConfig(startDir)
 }
 
 // FindAndParseConfig 查找并解析配置文件
@@ -327,7 +445,22 @@ func (a *API) FindProjectConfig(startDir string) (string, error) {
 //	    fmt.Printf("活跃包源: %s\n", config.ActivePackageSource.Add.Key)
 //	}
 func (a *API) FindAndParseConfig() (*types.NuGetConfig, string, error) {
-	return a.Manager.FindAndLoadConfig()
+	return a.configStore().FindAndLoadConfig()
+}
+
+// FindAndParseConfigContext 查找并解析NuGet配置，支持取消和超时
+//
+// FindAndParseConfigContext 与 FindAndParseConfig 相同，但在查找、读取和解析前检查 ctx。
+//
+// 参数:
+//   - ctx: 控制取消和超时的上下文
+//
+// 返回值:
+//   - *types.NuGetConfig: 解析后的配置对象
+//   - string: 找到的配置文件路径
+//   - error: ctx 已取消或超时时返回 ctx.Err()，其他错误与 FindAndParseConfig 相同
+func (a *API) FindAndParseConfigContext(ctx context.Context) (*types.NuGetConfig, string, error) {
+	return a.Manager.FindAndLoadConfigContext(ctx)
 }
 
 // SaveConfig 保存配置到文件
@@ -363,7 +496,100 @@ func (a *API) FindAndParseConfig() (*types.NuGetConfig, string, error) {
 //
 //	fmt.Println("配置已成功保存")
 func (a *API) SaveConfig(config *types.NuGetConfig, filePath string) error {
-	return a.Manager.SaveConfig(config, filePath)
+	return a.configStore().SaveConfig(config, filePath)
+}
+
+// SaveConfigContext 保存配置到文件，支持取消和超时
+//
+// SaveConfigContext 与 SaveConfig 相同，但在写入前检查 ctx；
+// ctx 在写入开始前取消时文件不会被修改。
+//
+// 参数:
+//   - ctx: 控制取消和超时的上下文
+//   - config: 要保存的 NuGet 配置对象
+//   - filePath: 保存的目标文件路径
+//
+// 返回值:
+//   - error: ctx 已取消或超时时返回 ctx.Err()，其他错误与 SaveConfig 相同
+func (a *API) SaveConfigContext(ctx context.Context, config *types.NuGetConfig, filePath string) error {
+	return a.Manager.SaveConfigContext(ctx, config, filePath)
+}
+
+// LoadSafeManager 加载配置文件并创建可在多个 goroutine 之间共享的 SafeManager
+//
+// LoadSafeManager 适用于 Web 服务等需要在多个请求处理器之间共享同一份配置的场景：
+// 读操作可以并发执行，修改和保存相互串行。返回的 SafeManager 使用独立的 ConfigManager，
+// 并继承 a.Manager 的 PreserveFormatting、ValidateConfigOptions 和 StrictSourceValidation 设置。
+//
+// 参数:
+//   - filePath: 配置文件的路径
+//
+// 返回值:
+//   - *manager.SafeManager: 持有已加载配置的并发安全管理器
+//   - error: 如果加载失败则返回相应的错误
+//
+// 示例:
+//
+//	safe, err := api.LoadSafeManager("/path/to/NuGet.Config")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	// 在请求处理器中并发读取
+//	safe.Read(func(config *types.NuGetConfig) error {
+//	    fmt.Println(len(config.PackageSources.Add))
+//	    return nil
+//	})
+//
+//	// 串行修改并保存
+//	err = safe.Update(func(m *manager.ConfigManager, config *types.NuGetConfig) error {
+//	    return m.SetActivePackageSource(config, "nuget.org")
+//	})
+//	if err == nil {
+//	    err = safe.Save()
+//	}
+func (a *API) LoadSafeManager(filePath string) (*manager.SafeManager, error) {
+	m := manager.NewConfigManager()
+	m.PreserveFormatting = a.Manager.PreserveFormatting
+	m.ValidateConfigOptions = a.Manager.ValidateConfigOptions
+	m.StrictSourceValidation = a.Manager.StrictSourceValidation
+	return manager.LoadSafeManager(m, filePath)
+}
+
+// WatchConfig 监视配置文件，文件变化后重新解析并调用 handler
+//
+// WatchConfig 适用于需要感知其他进程对配置文件修改的长期运行的工具。
+// 连续的多次写入只触发一次回调；文件解析失败或被删除时，handler 收到对应的错误。
+// 重新解析使用 a.Parser。检查间隔和防抖时长可以通过 watcher.NewWatcher 自定义。
+//
+// 参数:
+//   - filePath: 要监视的配置文件路径
+//   - handler: 文件变化后的回调，在监视 goroutine 中依次调用
+//
+// 返回值:
+//   - *watcher.Watcher: 已启动的监视器，不再需要时调用 Stop
+//   - error: 如果文件不存在则返回 errors.ErrConfigFileNotFound
+//
+// 示例:
+//
+//	w, err := api.WatchConfig("/path/to/NuGet.Config", func(config *types.NuGetConfig, err error) {
+//	    if err != nil {
+//	        log.Printf("配置无效: %v", err)
+//	        return
+//	    }
+//	    log.Printf("配置已更新，共 %d 个包源", len(config.PackageSources.Add))
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Stop()
+func (a *API) WatchConfig(filePath string, handler watcher.Handler) (*watcher.Watcher, error) {
+	w := watcher.NewWatcher(filePath, handler)
+	w.Parser = a.Parser
+	if err := w.Start(); err != nil {
+		return nil, err
+	}
+	return w, nil
 }
 
 // CreateDefaultConfig 创建默认配置
@@ -483,6 +709,57 @@ func (a *API) AddPackageSource(config *types.NuGetConfig, key string, value stri
 	a.Manager.AddPackageSource(config, key, value, protocolVersion)
 }
 
+// AddPackageSourceChecked 校验参数后添加或更新包源
+//
+// AddPackageSourceChecked 与 AddPackageSource 相同，但会拒绝空名称、空地址、
+// 缺少主机名的 URL 和不支持的协议版本，适合需要尽早失败的自动化脚本。
+// 将 a.Manager.StrictSourceValidation 设为 true 后还会拒绝 http://、ftp:// 等
+// 非 HTTPS 地址（已设置 allowInsecureConnections 的包源除外）和相对路径。
+// 校验失败时配置不会被修改。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 包源的唯一标识符/名称
+//   - value: 包源的 URL 或本地路径
+//   - protocolVersion: 协议版本，只能为空、"2" 或 "3"
+//
+// 返回值:
+//   - error: 参数不合法时返回描述问题的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	if err := api.AddPackageSourceChecked(config, "company", "https://nuget.company.com/v3/index.json", "3"); err != nil {
+//	    log.Fatalf("添加包源失败: %v", err)
+//	}
+//
+//	// 只允许 HTTPS 包源
+//	api.Manager.StrictSourceValidation = true
+//	err := api.AddPackageSourceChecked(config, "legacy", "http://nuget.example.com/nuget", "")
+//	// err: package source 'legacy': insecure URL 'http://nuget.example.com/nuget': ...
+func (a *API) AddPackageSourceChecked(config *types.NuGetConfig, key string, value string, protocolVersion string) error {
+	return a.Manager.AddPackageSourceChecked(config, key, value, protocolVersion)
+}
+
+// AddPackageSourceAuto 添加包源，协议版本根据地址自动推断
+//
+// AddPackageSourceAuto 与 AddPackageSource 相同，但不需要调用方指定协议版本：
+// 以 index.json 结尾的 URL 使用 "3"，路径中包含 /api/v2 的 URL 使用 "2"，
+// 本地路径和其他地址不写入 protocolVersion。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 包源的唯一标识符/名称
+//   - value: 包源的 URL 或本地路径
+//
+// 示例:
+//
+//	api.AddPackageSourceAuto(config, "nuget.org", "https://api.nuget.org/v3/index.json") // protocolVersion="3"
+//	api.AddPackageSourceAuto(config, "legacy", "https://legacy.example.com/api/v2")      // protocolVersion="2"
+//	api.AddPackageSourceAuto(config, "local", "/var/packages")                            // 不写入 protocolVersion
+func (a *API) AddPackageSourceAuto(config *types.NuGetConfig, key string, value string) {
+	a.Manager.AddPackageSourceAuto(config, key, value)
+}
+
 // RemovePackageSource 移除包源
 //
 // RemovePackageSource 从配置中移除指定键名的包源。
@@ -523,6 +800,167 @@ func (a *API) RemovePackageSource(config *types.NuGetConfig, key string) bool {
 	return a.Manager.RemovePackageSource(config, key)
 }
 
+// RenamePackageSource 重命名包源
+//
+// RenamePackageSource 修改包源的键名，并同步更新活跃包源、禁用包源、凭证
+// 和包源映射中对该包源的引用。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - oldKey: 包源当前的标识符/名称
+//   - newKey: 包源新的标识符/名称
+//
+// 返回值:
+//   - error: 如果包源不存在或新名称已被其他包源使用则返回错误，此时配置不会被修改；如果成功则为 nil
+//
+// 示例:
+//
+//	err := api.RenamePackageSource(config, "old-feed", "team-feed")
+//	if err != nil {
+//	    fmt.Printf("重命名失败: %v\n", err)
+//	    return
+//	}
+//
+//	// 凭证和禁用状态会随包源一起迁移
+//	err = api.SaveConfig(config, "/path/to/NuGet.Config")
+func (a *API) RenamePackageSource(config *types.NuGetConfig, oldKey string, newKey string) error {
+	return a.Manager.RenamePackageSource(config, oldKey, newKey)
+}
+
+// MovePackageSource 调整包源顺序
+//
+// MovePackageSource 将包源移动到列表中的指定位置。NuGet 按包源在配置中出现的
+// 顺序进行还原，因此包源的顺序会影响还原行为。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 要移动的包源的标识符/名称
+//   - index: 移动后包源在列表中的下标
+//
+// 返回值:
+//   - error: 如果包源不存在或下标越界则返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	// 让内部源排在第二位
+//	err := api.MovePackageSource(config, "internal", 1)
+func (a *API) MovePackageSource(config *types.NuGetConfig, key string, index int) error {
+	return a.Manager.MovePackageSource(config, key, index)
+}
+
+// MoveToTop 将包源移动到最前面
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 要移动的包源的标识符/名称
+//
+// 返回值:
+//   - error: 如果包源不存在则返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	err := api.MoveToTop(config, "internal")
+func (a *API) MoveToTop(config *types.NuGetConfig, key string) error {
+	return a.Manager.MoveToTop(config, key)
+}
+
+// SortPackageSources 对包源排序
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - less: 比较函数，为 nil 时按键名排序；排序是稳定的
+//
+// 示例:
+//
+//	// 按URL排序
+//	api.SortPackageSources(config, func(a, b types.PackageSource) bool {
+//	    return a.Value < b.Value
+//	})
+func (a *API) SortPackageSources(config *types.NuGetConfig, less func(a, b types.PackageSource) bool) {
+	a.Manager.SortPackageSources(config, less)
+}
+
+// NormalizeSources 规范化包源并移除重复的包源
+//
+// NormalizeSources 去掉包源名称和地址的首尾空白，统一URL的协议和主机名大小写、
+// 去掉末尾斜杠，解析本地相对路径，并移除指向同一个源的重复包源。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - baseDir: 解析相对路径时使用的目录，通常为配置文件所在目录；为空时相对路径只做清理
+//
+// 返回值:
+//   - *manager.NormalizeReport: 修改和移除的包源列表
+//
+// 示例:
+//
+//	report := api.NormalizeSources(config, filepath.Dir(configPath))
+//	for _, removed := range report.Removed {
+//	    fmt.Printf("移除重复包源 %s（与 %s 相同）\n", removed.Key, removed.DuplicateOf)
+//	}
+//	if report.HasChanges() {
+//	    err = api.SaveConfig(config, configPath)
+//	}
+func (a *API) NormalizeSources(config *types.NuGetConfig, baseDir string) *manager.NormalizeReport {
+	return a.Manager.NormalizeSources(config, baseDir)
+}
+
+// AddPackageSources 批量添加包源
+//
+// AddPackageSources 一次添加多个包源，适用于需要安装整套包源的配置工具。
+// 所有包源会先经过检查，任何检查失败时配置都不会被修改。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - sources: 要添加的包源，按顺序追加在已有包源之后
+//   - policy: 包源名称已存在时的处理方式，可选 manager.DuplicateSkip、manager.DuplicateUpdate 或 manager.DuplicateError
+//
+// 返回值:
+//   - error: 如果包源名称或地址为空、同一批中名称重复，或 policy 为 DuplicateError 时包源已存在，则返回错误
+//
+// 示例:
+//
+//	err := api.AddPackageSources(config, []types.PackageSource{
+//	    {Key: "internal", Value: "https://nuget.example.com/v3/index.json", ProtocolVersion: "3"},
+//	    {Key: "local", Value: "./packages"},
+//	}, manager.DuplicateUpdate)
+func (a *API) AddPackageSources(config *types.NuGetConfig, sources []types.PackageSource, policy manager.DuplicateSourcePolicy) error {
+	return a.Manager.AddPackageSources(config, sources, policy)
+}
+
+// DisableAllExcept 只保留指定的包源为启用状态
+//
+// DisableAllExcept 禁用除 keys 以外的所有包源，并启用 keys 中的包源。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - keys: 允许使用的包源名称
+//
+// 返回值:
+//   - error: 如果 keys 中有未定义的包源则返回错误，此时配置不会被修改
+//
+// 示例:
+//
+//	// CI 中只允许使用内部代理源
+//	if err := api.DisableAllExcept(config, "internal-proxy"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (a *API) DisableAllExcept(config *types.NuGetConfig, keys ...string) error {
+	return a.Manager.DisableAllExcept(config, keys...)
+}
+
+// EnableAll 启用所有包源
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//
+// 示例:
+//
+//	api.EnableAll(config)
+func (a *API) EnableAll(config *types.NuGetConfig) {
+	a.Manager.EnableAll(config)
+}
+
 // GetPackageSource 获取包源
 //
 // GetPackageSource 根据键名从配置中获取特定的包源。
@@ -609,6 +1047,68 @@ func (a *API) GetAllPackageSources(config *types.NuGetConfig) []types.PackageSou
 	return a.Manager.GetAllPackageSources(config)
 }
 
+// GetPackageSourceStatuses 获取所有包源及其状态
+//
+// GetPackageSourceStatuses 将包源与禁用包源、凭证和活跃包源的信息合并返回，
+// 调用方不需要自己对照多个配置节。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//
+// 返回值:
+//   - []manager.SourceStatus: 按配置中的顺序排列的包源状态
+//
+// 示例:
+//
+//	for _, status := range api.GetPackageSourceStatuses(config) {
+//	    fmt.Printf("%s (%s) 启用: %v 凭证: %v\n",
+//	        status.Key, status.Value, status.Enabled, status.HasCredentials)
+//	}
+func (a *API) GetPackageSourceStatuses(config *types.NuGetConfig) []manager.SourceStatus {
+	return a.Manager.GetPackageSourceStatuses(config)
+}
+
+// GetEnabledPackageSources 获取所有未被禁用的包源
+//
+// 参数:
+//   - config: NuGet 配置对象
+//
+// 返回值:
+//   - []types.PackageSource: 按配置中的顺序排列的已启用包源
+//
+// 示例:
+//
+//	for _, source := range api.GetEnabledPackageSources(config) {
+//	    fmt.Println(source.Key)
+//	}
+func (a *API) GetEnabledPackageSources(config *types.NuGetConfig) []types.PackageSource {
+	return a.Manager.GetEnabledPackageSources(config)
+}
+
+// FindSourcesForPackage 按包源映射规则查找可以提供指定包的包源
+//
+// FindSourcesForPackage 使用与 NuGet 相同的规则：完全匹配的模式优先于前缀模式，
+// 前缀越长越优先。配置未定义包源映射时，所有启用的包源都可以提供该包。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//   - packageID: 包ID，不区分大小写
+//
+// 返回值:
+//   - manager.PackageSourceMatch: 匹配的模式和可以提供该包的包源名称
+//
+// 示例:
+//
+//	match := api.FindSourcesForPackage(config, "Contoso.Utilities")
+//	if match.MappingEnabled && len(match.Sources) == 0 {
+//	    fmt.Println("没有包源映射到该包，无法还原")
+//	    return
+//	}
+//	fmt.Printf("模式 %q 匹配，包源: %v\n", match.Pattern, match.Sources)
+func (a *API) FindSourcesForPackage(config *types.NuGetConfig, packageID string) manager.PackageSourceMatch {
+	return a.Manager.FindSourcesForPackage(config, packageID)
+}
+
 // SetActivePackageSource 设置活跃包源
 //
 // SetActivePackageSource 将指定的包源设置为活跃包源。
@@ -665,7 +1165,7 @@ func (a *API) SetActivePackageSource(config *types.NuGetConfig, key string) erro
 //   - password: 身份验证密码（会以明文形式存储在配置文件中，使用 ClearTextPassword 键名）
 //
 // 注意:
-//   - 配置文件中的密码是以明文方式存储的，请确保文件安全
+//   - 配置文件中的密码是以明文方式存储的，请确保文件安全；不希望密码出现在文件中时使用 AddStoredCredential
 //   - 某些 NuGet 服务器可能需要特殊的凭证类型，如 API 密钥
 //
 // 示例:
@@ -700,17 +1200,255 @@ func (a *API) AddCredential(config *types.NuGetConfig, sourceKey string, usernam
 	a.Manager.AddCredential(config, sourceKey, username, password)
 }
 
-// RemoveCredential 移除包源凭证
+// AddCredentialChecked 校验参数后添加或更新包源凭证
 //
-// RemoveCredential 从配置中移除指定包源的身份验证凭证。
-// 如果指定的包源没有凭证，则不做任何更改。
+// AddCredentialChecked 与 AddCredential 相同，但包源必须已经存在，
+// 用户名和密码都不能为空。校验失败时配置不会被修改。
 //
 // 参数:
 //   - config: 要修改的 NuGet 配置对象
-//   - sourceKey: 要移除凭证的包源名称
+//   - sourceKey: 包源的名称
+//   - username: 用户名
+//   - password: 密码
 //
 // 返回值:
-//   - bool: 如果成功移除返回 true，如果指定的包源没有凭证则返回 false
+//   - error: 包源不存在或参数为空时返回描述问题的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	if err := api.AddCredentialChecked(config, "company", "user", os.Getenv("FEED_TOKEN")); err != nil {
+//	    log.Fatalf("添加凭证失败: %v", err)
+//	}
+func (a *API) AddCredentialChecked(config *types.NuGetConfig, sourceKey string, username string, password string) error {
+	return a.Manager.AddCredentialChecked(config, sourceKey, username, password)
+}
+
+// AddEncryptedCredential 添加包源凭证，密码加密后存储
+//
+// AddEncryptedCredential 使用 credentials.DefaultCipher 加密密码，并以 Password 键名存储，
+// 与 nuget.exe 和 Visual Studio 在 Windows 上写入的格式相同。加密结果只能由同一台机器上的
+// 同一用户解密。其他平台不支持加密，返回 credentials.ErrEncryptionUnsupported，此时应改用
+// AddCredential 以明文存储。如果包源已有凭证，将被替换。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - sourceKey: 要添加凭证的包源名称
+//   - username: 身份验证用户名
+//   - password: 身份验证密码的明文
+//
+// 返回值:
+//   - error: 当前平台不支持加密或加密失败时返回错误，配置不会被修改；如果成功则为 nil
+//
+// 示例:
+//
+//	err := api.AddEncryptedCredential(config, "private-source", "username", "p@ssw0rd")
+//	if errors.Is(err, credentials.ErrEncryptionUnsupported) {
+//	    // 非 Windows 平台只能以明文存储
+//	    api.AddCredential(config, "private-source", "username", "p@ssw0rd")
+//	} else if err != nil {
+//	    log.Fatalf("添加凭证失败: %v", err)
+//	}
+func (a *API) AddEncryptedCredential(config *types.NuGetConfig, sourceKey string, username string, password string) error {
+	cipher, err := credentials.DefaultCipher()
+	if err != nil {
+		return err
+	}
+	encrypted, err := cipher.Encrypt(password)
+	if err != nil {
+		return err
+	}
+	a.Manager.AddEncryptedCredential(config, sourceKey, username, encrypted)
+	return nil
+}
+
+// AddStoredCredential 添加包源凭证，密码保存在系统凭证存储中
+//
+// AddStoredCredential 将密码写入 CredentialStore（为 nil 时使用 credentials.DefaultStore，
+// 即 macOS 钥匙串、Windows 凭据管理器或 Secret Service），服务名为 credentials.ServiceName(sourceKey)，
+// 账户为用户名。配置文件中只保留 Username，GetCredentialPassword 会从凭证存储中读取密码。
+// 如果包源已有凭证，将被替换。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - sourceKey: 要添加凭证的包源名称
+//   - username: 身份验证用户名，不能为空
+//   - password: 身份验证密码的明文
+//
+// 返回值:
+//   - error: 用户名为空、没有可用的凭证存储（credentials.ErrStoreUnavailable）或写入失败时返回错误，
+//     配置不会被修改；如果成功则为 nil
+//
+// 示例:
+//
+//	err := api.AddStoredCredential(config, "private-source", "username", os.Getenv("FEED_TOKEN"))
+//	if errors.Is(err, credentials.ErrStoreUnavailable) {
+//	    log.Fatal("没有可用的系统凭证存储")
+//	} else if err != nil {
+//	    log.Fatalf("添加凭证失败: %v", err)
+//	}
+//
+//	// 配置文件中只有 <add key="Username" value="username" />
+//	err = api.SaveConfig(config, "/path/to/NuGet.Config")
+func (a *API) AddStoredCredential(config *types.NuGetConfig, sourceKey string, username string, password string) error {
+	if username == "" {
+		return fmt.Errorf("username must not be empty")
+	}
+	store, err := a.credentialStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Set(credentials.ServiceName(sourceKey), username, password); err != nil {
+		return err
+	}
+	a.Manager.AddUsernameCredential(config, sourceKey, username)
+	return nil
+}
+
+// RemoveStoredCredential 移除包源凭证以及系统凭证存储中的密码
+//
+// RemoveStoredCredential 在 RemoveCredential 的基础上，同时从 CredentialStore 中删除
+// AddStoredCredential 保存的密码。凭证存储中没有密码或没有可用的凭证存储时只移除配置中的凭证。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - sourceKey: 包源的名称
+//
+// 返回值:
+//   - bool: 配置中存在该包源的凭证并已移除时为 true
+//   - error: 从凭证存储中删除密码失败时返回错误，此时配置不会被修改
+//
+// 示例:
+//
+//	removed, err := api.RemoveStoredCredential(config, "private-source")
+//	if err != nil {
+//	    log.Fatalf("移除凭证失败: %v", err)
+//	}
+func (a *API) RemoveStoredCredential(config *types.NuGetConfig, sourceKey string) (bool, error) {
+	if config.PackageSourceCredentials == nil {
+		return false, nil
+	}
+	cred, exists := config.PackageSourceCredentials.Sources[sourceKey]
+	if !exists {
+		return false, nil
+	}
+	if username, _ := credentials.Username(cred); username != "" {
+		if store, err := a.credentialStore(); err == nil {
+			if err := store.Delete(credentials.ServiceName(sourceKey), username); err != nil {
+				return false, err
+			}
+		}
+	}
+	return a.Manager.RemoveCredential(config, sourceKey), nil
+}
+
+// credentialStore 返回 CredentialStore，为 nil 时返回 credentials.DefaultStore
+func (a *API) credentialStore() (credentials.Store, error) {
+	if a.CredentialStore != nil {
+		return a.CredentialStore, nil
+	}
+	return credentials.DefaultStore()
+}
+
+// GetCredentialPassword 获取包源凭证中的密码
+//
+// GetCredentialPassword 优先返回 ClearTextPassword 的值；只有加密的 Password 时使用
+// credentials.DefaultCipher 解密，因此在非 Windows 平台上读取加密密码会返回
+// credentials.ErrEncryptionUnsupported。凭证中只有 Username 时，从 CredentialStore
+// 中读取 AddStoredCredential 保存的密码。用户名和密码为 %VAR% 或 $VAR 形式的占位符时，
+// 使用环境变量的值，变量未定义时返回包装 credentials.ErrUnresolvedPlaceholder 的错误。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//   - sourceKey: 包源的名称
+//
+// 返回值:
+//   - string: 密码的明文
+//   - error: 包源没有凭证或凭证中没有密码、无法解密时返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	password, err := api.GetCredentialPassword(config, "private-source")
+//	if err != nil {
+//	    log.Fatalf("读取密码失败: %v", err)
+//	}
+func (a *API) GetCredentialPassword(config *types.NuGetConfig, sourceKey string) (string, error) {
+	if config.PackageSourceCredentials == nil {
+		return "", fmt.Errorf("no credentials for package source '%s'", sourceKey)
+	}
+	cred, exists := config.PackageSourceCredentials.Sources[sourceKey]
+	if !exists {
+		return "", fmt.Errorf("no credentials for package source '%s'", sourceKey)
+	}
+	cred, err := credentials.Resolve(cred, os.LookupEnv)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password for package source '%s': %w", sourceKey, err)
+	}
+
+	// 非 Windows 平台上 cipher 为 nil，明文密码仍然可以读取
+	cipher, _ := credentials.DefaultCipher()
+	password, found, err := credentials.Password(cred, cipher)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password for package source '%s': %w", sourceKey, err)
+	}
+	if found {
+		return password, nil
+	}
+
+	// 密码可能保存在系统凭证存储中
+	if username, _ := credentials.Username(cred); username != "" {
+		if store, err := a.credentialStore(); err == nil {
+			password, err := store.Get(credentials.ServiceName(sourceKey), username)
+			if err == nil {
+				return password, nil
+			}
+			if !stderrors.Is(err, credentials.ErrSecretNotFound) {
+				return "", fmt.Errorf("failed to read password for package source '%s': %w", sourceKey, err)
+			}
+		}
+	}
+	return "", fmt.Errorf("credentials for package source '%s' have no password", sourceKey)
+}
+
+// ResolveCredentials 获取替换了环境变量占位符的包源凭证
+//
+// 凭证值可以是 %NUGET_FEED_PAT%、$NUGET_FEED_PAT 或 ${NUGET_FEED_PAT} 形式的环境变量占位符，
+// 占位符会原样保留在配置文件中，解析和保存都不会改变它。ResolveCredentials 返回一份用当前环境变量
+// 替换了占位符的凭证副本，供运行时使用，config 本身不会被修改。只有整个值是占位符时才会替换，
+// 密码中间的 $ 和 % 保持原样。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//
+// 返回值:
+//   - *types.PackageSourceCredentials: 替换后的凭证，配置中没有凭证时为 nil
+//   - error: 有占位符引用的环境变量未定义时返回包装 credentials.ErrUnresolvedPlaceholder 的错误，
+//     其中列出所有未定义的变量；此时仍返回替换了其余占位符的凭证
+//
+// 示例:
+//
+//	// NuGet.Config 中: <add key="ClearTextPassword" value="%NUGET_FEED_PAT%" />
+//	creds, err := api.ResolveCredentials(config)
+//	if err != nil {
+//	    log.Fatalf("解析凭证失败: %v", err)
+//	}
+//	for source, cred := range creds.Sources {
+//	    fmt.Printf("%s: %d 项凭证\n", source, len(cred.Add))
+//	}
+func (a *API) ResolveCredentials(config *types.NuGetConfig) (*types.PackageSourceCredentials, error) {
+	return credentials.ResolveAll(config.PackageSourceCredentials, os.LookupEnv)
+}
+
+// RemoveCredential 移除包源凭证
+//
+// RemoveCredential 从配置中移除指定包源的身份验证凭证。
+// 如果指定的包源没有凭证，则不做任何更改。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - sourceKey: 要移除凭证的包源名称
+//
+// 返回值:
+//   - bool: 如果成功移除返回 true，如果指定的包源没有凭证则返回 false
 //
 // 示例:
 //
@@ -742,6 +1480,26 @@ func (a *API) RemoveCredential(config *types.NuGetConfig, sourceKey string) bool
 	return a.Manager.RemoveCredential(config, sourceKey)
 }
 
+// ClearAllCredentials 移除所有包源凭证
+//
+// ClearAllCredentials 删除配置中的整个 packageSourceCredentials 节，
+// 适用于提交到版本库前清理配置中的用户名和密码。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//
+// 返回值:
+//   - bool: 如果配置中原本有凭证返回 true，否则返回 false
+//
+// 示例:
+//
+//	if api.ClearAllCredentials(config) {
+//	    err = api.SaveConfig(config, "/path/to/NuGet.Config")
+//	}
+func (a *API) ClearAllCredentials(config *types.NuGetConfig) bool {
+	return a.Manager.ClearAllCredentials(config)
+}
+
 // 禁用包源操作
 
 // DisablePackageSource 禁用包源
@@ -786,6 +1544,24 @@ func (a *API) DisablePackageSource(config *types.NuGetConfig, key string) {
 	a.Manager.DisablePackageSource(config, key)
 }
 
+// DisablePackageSourceChecked 禁用包源，包源不存在时返回错误
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 要禁用的包源名称
+//
+// 返回值:
+//   - error: 包源不存在时返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	if err := api.DisablePackageSourceChecked(config, "nuget.org"); err != nil {
+//	    log.Fatalf("禁用包源失败: %v", err)
+//	}
+func (a *API) DisablePackageSourceChecked(config *types.NuGetConfig, key string) error {
+	return a.Manager.DisablePackageSourceChecked(config, key)
+}
+
 // EnablePackageSource 启用包源
 //
 // EnablePackageSource 从配置中的禁用列表中移除指定的包源，使其恢复启用状态。
@@ -928,6 +1704,53 @@ func (a *API) AddConfigOption(config *types.NuGetConfig, key string, value strin
 	a.Manager.AddConfigOption(config, key, value)
 }
 
+// AddConfigOptionChecked 校验参数后添加或更新配置选项
+//
+// AddConfigOptionChecked 与 AddConfigOption 相同，但键名不能为空，
+// 已知选项（见 types.KnownConfigOptions）会按类型检查值的格式。
+// 与 SetConfigOption 不同，未知选项总是允许写入，不受 Manager.ValidateConfigOptions 影响。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 配置选项的键名
+//   - value: 配置选项的值
+//
+// 返回值:
+//   - error: 键名为空或已知选项的值不合法时返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	if err := api.AddConfigOptionChecked(config, "maxHttpRequestsPerSource", "16"); err != nil {
+//	    log.Fatalf("设置配置选项失败: %v", err)
+//	}
+func (a *API) AddConfigOptionChecked(config *types.NuGetConfig, key string, value string) error {
+	return a.Manager.AddConfigOptionChecked(config, key, value)
+}
+
+// SetConfigOption 设置配置选项，可选地校验键和值
+//
+// 当 a.Manager.ValidateConfigOptions 为 true 时，SetConfigOption 只接受
+// types.KnownConfigOptions 中的键，并检查值的格式（布尔值、整数范围、枚举值、URL 等），
+// 校验失败时返回错误且配置不会被修改；否则与 AddConfigOption 相同。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 配置选项的键名
+//   - value: 配置选项的值
+//
+// 返回值:
+//   - error: 校验失败时返回错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api.Manager.ValidateConfigOptions = true
+//
+//	// 返回错误：maxHttpRequestsPerSource 必须是正整数
+//	err := api.SetConfigOption(config, "maxHttpRequestsPerSource", "lots")
+func (a *API) SetConfigOption(config *types.NuGetConfig, key string, value string) error {
+	return a.Manager.SetConfigOption(config, key, value)
+}
+
 // RemoveConfigOption 移除配置选项
 //
 // RemoveConfigOption 从配置中移除指定的全局配置选项。
@@ -1010,114 +1833,684 @@ func (a *API) GetConfigOption(config *types.NuGetConfig, key string) string {
 	return a.Manager.GetConfigOption(config, key)
 }
 
-// SerializeToXML 将配置序列化为XML字符串
-//
-// SerializeToXML 将 NuGet 配置对象序列化为标准格式的 XML 字符串。
-// 生成的 XML 包含适当的缩进和 XML 声明。
+// GetConfigOptionBool 获取布尔类型的配置选项
 //
 // 参数:
-//   - config: 要序列化的 NuGet 配置对象
+//   - config: NuGet 配置对象
+//   - key: 配置选项的键名
 //
 // 返回值:
-//   - string: 序列化后的 XML 字符串
-//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//   - bool: 选项的值
+//   - bool: 选项是否已设置
+//   - error: 如果值不是 true 或 false（不区分大小写）则返回错误
 //
 // 示例:
 //
-//	api := nuget.NewAPI()
-//
-//	// 创建或加载配置
-//	config := api.CreateDefaultConfig()
-//	api.AddPackageSource(config, "custom-source", "https://custom-source/v3/index.json", "3")
-//
-//	// 序列化为 XML
-//	xmlString, err := api.SerializeToXML(config)
-//	if err != nil {
-//	    fmt.Printf("序列化失败: %v\n", err)
-//	    return
-//	}
+//	enabled, ok, err := api.GetConfigOptionBool(config, "signatureValidation")
+func (a *API) GetConfigOptionBool(config *types.NuGetConfig, key string) (bool, bool, error) {
+	return a.Manager.GetConfigOptionBool(config, key)
+}
+
+// GetConfigOptionInt 获取整数类型的配置选项
 //
-//	// 显示生成的 XML
-//	fmt.Println("生成的 XML 配置:")
-//	fmt.Println(xmlString)
+// 参数:
+//   - config: NuGet 配置对象
+//   - key: 配置选项的键名
 //
-//	// 也可以将生成的 XML 用于其他目的，例如传递给其他系统
-//	// 或在不写入文件的情况下使用
+// 返回值:
+//   - int: 选项的值
+//   - bool: 选项是否已设置
+//   - error: 如果值不是整数则返回错误
 //
-//	// 解析序列化后的 XML 以验证其有效性
-//	parsedConfig, err := api.ParseFromString(xmlString)
-//	if err != nil {
-//	    fmt.Printf("验证失败: %v\n", err)
-//	    return
-//	}
+// 示例:
 //
-//	fmt.Println("XML 有效且可以正确解析")
-func (a *API) SerializeToXML(config *types.NuGetConfig) (string, error) {
-	return a.Parser.SerializeToXML(config)
+//	limit, ok, err := api.GetConfigOptionInt(config, "maxHttpRequestsPerSource")
+func (a *API) GetConfigOptionInt(config *types.NuGetConfig, key string) (int, bool, error) {
+	return a.Manager.GetConfigOptionInt(config, key)
 }
 
-// ParseFromFileWithPositions 从文件解析配置并记录位置信息
+// GetConfigOptionDuration 获取时长类型的配置选项
 //
-// ParseFromFileWithPositions 使用位置感知解析器读取指定路径的文件内容，
-// 并将其解析为 NuGet 配置对象，同时记录每个元素的位置信息。
-// 这个方法返回的 ParseResult 可以用于创建位置感知的编辑器。
+// 纯数字按秒解析，也接受 "1m30s" 这样的 Go 时长格式。
 //
 // 参数:
-//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//   - config: NuGet 配置对象
+//   - key: 配置选项的键名
 //
 // 返回值:
-//   - *parser.ParseResult: 包含配置对象、位置信息和原始内容的解析结果
-//   - error: 如果解析过程中发生错误，则返回相应的错误；如果成功则为 nil
+//   - time.Duration: 选项的值
+//   - bool: 选项是否已设置
+//   - error: 如果值无法解析或为负数则返回错误
+func (a *API) GetConfigOptionDuration(config *types.NuGetConfig, key string) (time.Duration, bool, error) {
+	return a.Manager.GetConfigOptionDuration(config, key)
+}
+
+// GetConfigOptionPath 获取路径类型的配置选项，并展开其中的环境变量
 //
-// 示例:
+// 参数:
+//   - config: NuGet 配置对象
+//   - key: 配置选项的键名
 //
-//	api := nuget.NewAPI()
+// 返回值:
+//   - string: 展开环境变量后的路径，相对路径保持不变
+//   - bool: 选项是否已设置
+//   - error: 如果引用了未定义的环境变量则返回错误
 //
-//	// 解析配置文件并获取位置信息
-//	parseResult, err := api.ParseFromFileWithPositions("/path/to/NuGet.Config")
-//	if err != nil {
-//	    fmt.Printf("解析失败: %v\n", err)
-//	    return
-//	}
+// 示例:
 //
-//	// 创建位置感知编辑器
-//	editor := api.CreatePositionAwareEditor(parseResult)
+//	folder, ok, err := api.GetConfigOptionPath(config, "globalPackagesFolder")
+func (a *API) GetConfigOptionPath(config *types.NuGetConfig, key string) (string, bool, error) {
+	return a.Manager.GetConfigOptionPath(config, key)
+}
+
+// GetMaxHTTPRequestsPerSource 获取每个包源的最大并发HTTP请求数
 //
-//	// 执行编辑操作
-//	err = editor.AddPackageSource("new-source", "https://example.com/v3/index.json", "3")
-//	if err != nil {
-//	    fmt.Printf("添加包源失败: %v\n", err)
-//	    return
-//	}
+// 参数:
+//   - config: NuGet 配置对象
 //
-//	// 应用编辑并获取修改后的内容
-//	modifiedContent, err := editor.ApplyEdits()
-//	if err != nil {
-//	    fmt.Printf("应用编辑失败: %v\n", err)
-//	    return
-//	}
-func (a *API) ParseFromFileWithPositions(filePath string) (*parser.ParseResult, error) {
-	positionAwareParser := parser.NewPositionAwareParser()
-	return positionAwareParser.ParseFromFileWithPositions(filePath)
+// 返回值:
+//   - int: 最大并发请求数
+//   - bool: 选项是否已设置，未设置时 NuGet 使用平台默认值
+//   - error: 如果值不是正整数则返回错误
+func (a *API) GetMaxHTTPRequestsPerSource(config *types.NuGetConfig) (int, bool, error) {
+	return a.Manager.GetMaxHTTPRequestsPerSource(config)
 }
 
-// CreateConfigEditor 创建位置感知编辑器
-//
-// CreateConfigEditor 基于解析结果创建一个位置感知的配置编辑器。
-// 这个编辑器可以进行精确的文本编辑，保持原始文件的格式和缩进，
-// 最小化编辑后的diff。
+// SetMaxHTTPRequestsPerSource 设置每个包源的最大并发HTTP请求数
 //
 // 参数:
-//   - parseResult: 包含位置信息的解析结果
+//   - config: 要修改的 NuGet 配置对象
+//   - value: 最大并发请求数，必须是正整数
 //
 // 返回值:
-//   - *editor.ConfigEditor: 位置感知编辑器实例
+//   - error: 如果 value 不是正整数则返回错误，此时配置不会被修改
 //
 // 示例:
 //
-//	api := nuget.NewAPI()
+//	if err := api.SetMaxHTTPRequestsPerSource(config, 16); err != nil {
+//	    log.Fatal(err)
+//	}
+func (a *API) SetMaxHTTPRequestsPerSource(config *types.NuGetConfig, value int) error {
+	return a.Manager.SetMaxHTTPRequestsPerSource(config, value)
+}
+
+// SetConfigOptionInt 设置整数类型的配置选项
 //
-//	// 解析配置文件
+// 已知的整数选项（见 types.KnownConfigOptions）会按其取值范围检查。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 配置选项的键名
+//   - value: 配置选项的值
+//
+// 返回值:
+//   - error: 如果值超出已知选项的取值范围则返回错误
+func (a *API) SetConfigOptionInt(config *types.NuGetConfig, key string, value int) error {
+	return a.Manager.SetConfigOptionInt(config, key, value)
+}
+
+// SetConfigOptionDuration 设置时长类型的配置选项，以整数秒写入
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - key: 配置选项的键名
+//   - value: 非负的整秒时长
+//
+// 返回值:
+//   - error: 如果时长为负数或不是整秒则返回错误
+func (a *API) SetConfigOptionDuration(config *types.NuGetConfig, key string, value time.Duration) error {
+	return a.Manager.SetConfigOptionDuration(config, key, value)
+}
+
+// ResolveConfigOptionPath 获取路径类配置选项解析后的绝对路径
+//
+// NuGet 将 repositoryPath、globalPackagesFolder 等选项中的相对路径按配置文件所在目录解析。
+// ResolveConfigOptionPath 展开值中的环境变量，并按相同规则将其解析为绝对路径。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//   - configPath: 配置对象所在的配置文件路径，例如 FindAndParseConfig 返回的路径
+//   - key: 配置选项的键名
+//
+// 返回值:
+//   - string: 解析后的绝对路径
+//   - bool: 选项是否已设置
+//
+// 示例:
+//
+//	config, configPath, err := api.FindAndParseConfig()
+//	if err != nil {
+//	    return err
+//	}
+//	if repoPath, ok := api.ResolveConfigOptionPath(config, configPath, "repositoryPath"); ok {
+//	    fmt.Printf("包目录: %s\n", repoPath)
+//	}
+func (a *API) ResolveConfigOptionPath(config *types.NuGetConfig, configPath string, key string) (string, bool) {
+	return a.Manager.ResolveConfigOptionPath(config, configPath, key)
+}
+
+// ResolveConfigPaths 解析配置中所有已设置的路径类选项
+//
+// 参数:
+//   - config: NuGet 配置对象
+//   - configPath: 配置对象所在的配置文件路径
+//
+// 返回值:
+//   - map[string]string: 选项键到绝对路径的映射，只包含已设置的选项
+func (a *API) ResolveConfigPaths(config *types.NuGetConfig, configPath string) map[string]string {
+	return a.Manager.ResolveConfigPaths(config, configPath)
+}
+
+// SetProxy 设置HTTP代理
+//
+// SetProxy 将 http_proxy、http_proxy.user 和 http_proxy.password 三个配置选项作为一个整体设置。
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//   - proxyURL: 代理地址，必须是带主机名的 http 或 https 地址
+//   - username: 代理用户名，为空时移除已有的用户名和密码
+//   - password: 代理密码，为空时移除已有的密码
+//
+// 返回值:
+//   - error: 如果代理地址无效，或只提供了密码而没有用户名，则返回错误，此时配置不会被修改
+//
+// 示例:
+//
+//	err := api.SetProxy(config, "http://proxy.example.com:8080", "user", "password")
+//	if err != nil {
+//	    fmt.Printf("设置代理失败: %v\n", err)
+//	}
+func (a *API) SetProxy(config *types.NuGetConfig, proxyURL string, username string, password string) error {
+	return a.Manager.SetProxy(config, proxyURL, username, password)
+}
+
+// GetProxy 获取HTTP代理设置
+//
+// 参数:
+//   - config: NuGet 配置对象
+//
+// 返回值:
+//   - *manager.ProxySettings: 代理地址和凭证；未配置代理时为 nil
+//
+// 示例:
+//
+//	if proxy := api.GetProxy(config); proxy != nil {
+//	    fmt.Printf("HTTP 代理: %s\n", proxy.URL)
+//	}
+func (a *API) GetProxy(config *types.NuGetConfig) *manager.ProxySettings {
+	return a.Manager.GetProxy(config)
+}
+
+// RemoveProxy 移除HTTP代理及其凭证
+//
+// 参数:
+//   - config: 要修改的 NuGet 配置对象
+//
+// 返回值:
+//   - bool: 如果配置中原本有代理相关选项返回 true，否则返回 false
+func (a *API) RemoveProxy(config *types.NuGetConfig) bool {
+	return a.Manager.RemoveProxy(config)
+}
+
+// ValidateConfig 检查配置中的问题
+//
+// ValidateConfig 一次性返回发现的所有问题，而不是在第一个问题处返回错误。
+// 检查内容包括重复或为空的包源名称、不合法的包源地址、没有对应包源的凭证，
+// 以及已知配置选项的非法取值。每个问题带有严重程度、所在配置节和相关的键名。
+//
+// 参数:
+//   - config: NuGet 配置对象
+//
+// 返回值:
+//   - []manager.ValidationIssue: 发现的问题，配置没有问题时为空
+//
+// 示例:
+//
+//	issues := api.ValidateConfig(config)
+//	for _, issue := range issues {
+//	    fmt.Println(issue)
+//	}
+//	if manager.HasErrors(issues) {
+//	    os.Exit(1)
+//	}
+func (a *API) ValidateConfig(config *types.NuGetConfig) []manager.ValidationIssue {
+	return a.Manager.ValidateConfig(config)
+}
+
+// ValidateConfigFile 解析配置文件并检查其中的问题
+//
+// 参数:
+//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//
+// 返回值:
+//   - []manager.ValidationIssue: 发现的问题，配置没有问题时为空
+//   - error: 文件不存在或无法解析时返回相应的错误，此时不进行检查
+//
+// 示例:
+//
+//	issues, err := api.ValidateConfigFile("/path/to/NuGet.Config")
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//	for _, issue := range issues {
+//	    fmt.Printf("[%s] %s\n", issue.Severity, issue.Message)
+//	}
+func (a *API) ValidateConfigFile(filePath string) ([]manager.ValidationIssue, error) {
+	config, err := a.configParser().ParseFromFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return a.Manager.ValidateConfig(config), nil
+}
+
+// ValidateConfigFileWithRules 使用可配置的规则集校验配置文件，问题带有行列号
+//
+// 与 ValidateConfigFile 不同，规则可以单独启用、禁用或调整严重程度，也可以添加自定义规则，
+// 参见 validate 包。文件使用位置感知解析器解析，报告中的每个问题指向所在的元素。
+//
+// 参数:
+//   - filePath: 配置文件的路径
+//   - validator: 使用的校验器，为 nil 时使用 validate.NewValidator() 的默认规则集
+//
+// 返回值:
+//   - *validate.Report: 校验报告，有位置信息的问题按位置排列
+//   - error: 文件不存在或无法解析时返回相应的错误，此时不进行检查
+//
+// 示例:
+//
+//	v := validate.NewValidator()
+//	v.Disable(validate.RuleOrphanedEntry)
+//	v.SetSeverity(validate.RuleInvalidSourceValue, validate.SeverityWarning)
+//
+//	report, err := api.ValidateConfigFileWithRules("/path/to/NuGet.Config", v)
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//	for _, issue := range report.Issues {
+//	    fmt.Println(issue)
+//	}
+func (a *API) ValidateConfigFileWithRules(filePath string, validator *validate.Validator) (*validate.Report, error) {
+	result, err := a.ParseFromFileWithPositions(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		validator = validate.NewValidator()
+	}
+	return validator.ValidateResult(filePath, result), nil
+}
+
+// ValidateConfigHierarchy 校验指定目录下 NuGet 会使用的整条配置文件链
+//
+// ValidateConfigHierarchy 使用 FindConfigHierarchy 查找配置文件，合并后逐个校验。
+// 依赖其他文件内容的规则（如 validate.RuleOrphanedEntry）按合并后的生效配置判断，
+// 因此项目级配置中引用用户级配置所定义包源的映射和凭证不会被报告。
+//
+// 参数:
+//   - startDir: 项目目录
+//   - validator: 使用的校验器，为 nil 时使用 validate.NewValidator() 的默认规则集
+//
+// 返回值:
+//   - []*validate.Report: 按优先级从低到高排列的各文件的校验报告
+//   - error: 查找配置文件失败或任一文件无法解析时返回错误
+//
+// 示例:
+//
+//	reports, err := api.ValidateConfigHierarchy(".", nil)
+//	if err != nil {
+//	    fmt.Printf("校验失败: %v\n", err)
+//	    return
+//	}
+//	for _, report := range reports {
+//	    for _, issue := range report.Issues {
+//	        fmt.Printf("%s:%s\n", report.Path, issue)
+//	    }
+//	}
+func (a *API) ValidateConfigHierarchy(startDir string, validator *validate.Validator) ([]*validate.Report, error) {
+	chain, err := a.Finder.FindConfigHierarchy(startDir)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		validator = validate.NewValidator()
+	}
+
+	paths := make([]string, len(chain))
+	for i, c := range chain {
+		paths[i] = c.Path
+	}
+	return validator.ValidateFiles(paths...)
+}
+
+// SerializeToXML 将配置序列化为XML字符串
+//
+// SerializeToXML 将 NuGet 配置对象序列化为标准格式的 XML 字符串。
+// 生成的 XML 包含适当的缩进和 XML 声明。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象
+//
+// 返回值:
+//   - string: 序列化后的 XML 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	// 创建或加载配置
+//	config := api.CreateDefaultConfig()
+//	api.AddPackageSource(config, "custom-source", "https://custom-source/v3/index.json", "3")
+//
+//	// 序列化为 XML
+//	xmlString, err := api.SerializeToXML(config)
+//	if err != nil {
+//	    fmt.Printf("序列化失败: %v\n", err)
+//	    return
+//	}
+//
+//	// 显示生成的 XML
+//	fmt.Println("生成的 XML 配置:")
+//	fmt.Println(xmlString)
+//
+//	// 也可以将生成的 XML 用于其他目的，例如传递给其他系统
+//	// 或在不写入文件的情况下使用
+//
+//	// 解析序列化后的 XML 以验证其有效性
+//	parsedConfig, err := api.ParseFromString(xmlString)
+//	if err != nil {
+//	    fmt.Printf("验证失败: %v\n", err)
+//	    return
+//	}
+//
+//	fmt.Println("XML 有效且可以正确解析")
+func (a *API) SerializeToXML(config *types.NuGetConfig) (string, error) {
+	return a.Parser.SerializeToXML(config)
+}
+
+// SerializeToXMLWithOptions 按指定选项将配置序列化为XML字符串
+//
+// SerializeToXMLWithOptions 可以控制缩进、换行符、XML声明和属性顺序，
+// 使生成的配置文件符合团队约定。设置 opts.RedactSecrets 时密码、API 密钥和令牌被替换为
+// types.RedactedValue，输出可以安全地写入日志或问题报告。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象
+//   - opts: 序列化选项，通常以 parser.DefaultSerializeOptions() 为基础修改
+//
+// 返回值:
+//   - string: 序列化后的 XML 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	opts := parser.DefaultSerializeOptions()
+//	opts.Indent = "\t"
+//	opts.Newline = "\r\n"
+//	opts.AttributeOrder = []string{"key", "value"}
+//
+//	xmlString, err := api.SerializeToXMLWithOptions(config, opts)
+//	if err != nil {
+//	    fmt.Printf("序列化失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Println(xmlString)
+func (a *API) SerializeToXMLWithOptions(config *types.NuGetConfig, opts parser.SerializeOptions) (string, error) {
+	return a.Parser.SerializeToXMLWithOptions(config, opts)
+}
+
+// SerializeCanonical 将配置序列化为规范形式的XML字符串
+//
+// SerializeCanonical 对包源、配置选项、凭证等按 key 排序，并固定属性顺序和空白格式，
+// 语义相同的配置总是得到相同的输出。规范形式适合比较配置差异或作为缓存键计算哈希，
+// 但不保留包源的优先顺序，不应用于写回配置文件。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象，不会被修改
+//
+// 返回值:
+//   - string: 规范形式的 XML 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	left, _ := api.SerializeCanonical(configA)
+//	right, _ := api.SerializeCanonical(configB)
+//	if left == right {
+//	    fmt.Println("两个配置在语义上相同")
+//	}
+func (a *API) SerializeCanonical(config *types.NuGetConfig) (string, error) {
+	return a.Parser.SerializeCanonical(config)
+}
+
+// SerializeToJSON 将配置序列化为JSON字符串
+//
+// SerializeToJSON 输出带缩进的 JSON，字段名与 NuGet.Config 中的元素名和属性名一致，
+// 便于在仪表盘、REST API 或 jq 等工具中使用。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象
+//
+// 返回值:
+//   - string: JSON 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	jsonString, err := api.SerializeToJSON(config)
+//	if err != nil {
+//	    fmt.Printf("序列化失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Println(jsonString)
+func (a *API) SerializeToJSON(config *types.NuGetConfig) (string, error) {
+	return a.Parser.SerializeToJSON(config)
+}
+
+// ParseFromJSON 从JSON字符串解析配置
+//
+// ParseFromJSON 解析 SerializeToJSON 生成的 JSON，包含未知字段时返回错误。
+//
+// 参数:
+//   - content: JSON 字符串
+//
+// 返回值:
+//   - *types.NuGetConfig: 解析后的配置对象
+//   - error: 如果 JSON 格式错误或不包含任何包源则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	config, err := api.ParseFromJSON(`{"packageSources": {"add": [{"key": "nuget.org", "value": "https://api.nuget.org/v3/index.json"}]}}`)
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Printf("包源数量: %d\n", len(config.PackageSources.Add))
+func (a *API) ParseFromJSON(content string) (*types.NuGetConfig, error) {
+	return a.Parser.ParseFromJSON([]byte(content))
+}
+
+// SerializeToTOML 将配置序列化为TOML字符串
+//
+// SerializeToTOML 使用与 SerializeToJSON 相同的字段名，包源、凭证和配置选项
+// 等列表写作 TOML 表数组，便于与以 TOML 为标准配置格式的工具集成。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象
+//
+// 返回值:
+//   - string: TOML 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	tomlString, err := api.SerializeToTOML(config)
+//	if err != nil {
+//	    fmt.Printf("序列化失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Println(tomlString)
+func (a *API) SerializeToTOML(config *types.NuGetConfig) (string, error) {
+	return a.Parser.SerializeToTOML(config)
+}
+
+// ParseFromTOML 从TOML字符串解析配置
+//
+// ParseFromTOML 解析 SerializeToTOML 生成的 TOML，支持 TOML 的常用子集，
+// 不支持多行字符串和内联表。
+//
+// 参数:
+//   - content: TOML 字符串
+//
+// 返回值:
+//   - *types.NuGetConfig: 解析后的配置对象
+//   - error: 如果 TOML 格式错误、包含未知字段或不包含任何包源则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	config, err := api.ParseFromTOML(`
+//	[[packageSources.add]]
+//	key = "nuget.org"
+//	value = "https://api.nuget.org/v3/index.json"
+//	`)
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Printf("包源数量: %d\n", len(config.PackageSources.Add))
+func (a *API) ParseFromTOML(content string) (*types.NuGetConfig, error) {
+	return a.Parser.ParseFromTOML([]byte(content))
+}
+
+// SerializeToYAML 将配置序列化为YAML字符串
+//
+// SerializeToYAML 使用与 SerializeToJSON 相同的字段名，包源、凭证和配置选项
+// 等列表写作 YAML 序列，便于在流水线中以 YAML 模板维护配置。
+//
+// 参数:
+//   - config: 要序列化的 NuGet 配置对象
+//
+// 返回值:
+//   - string: YAML 字符串
+//   - error: 如果序列化过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	yamlString, err := api.SerializeToYAML(config)
+//	if err != nil {
+//	    fmt.Printf("序列化失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Println(yamlString)
+func (a *API) SerializeToYAML(config *types.NuGetConfig) (string, error) {
+	return a.Parser.SerializeToYAML(config)
+}
+
+// ParseFromYAML 从YAML字符串解析配置
+//
+// ParseFromYAML 解析 SerializeToYAML 生成的 YAML，支持 YAML 块格式的常用子集，
+// 不支持锚点、别名、标签、多行标量和多文档。
+//
+// 参数:
+//   - content: YAML 字符串
+//
+// 返回值:
+//   - *types.NuGetConfig: 解析后的配置对象
+//   - error: 如果 YAML 格式错误、包含未知字段或不包含任何包源则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	config, err := api.ParseFromYAML(`
+//	packageSources:
+//	  add:
+//	    - key: nuget.org
+//	      value: https://api.nuget.org/v3/index.json
+//	`)
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Printf("包源数量: %d\n", len(config.PackageSources.Add))
+func (a *API) ParseFromYAML(content string) (*types.NuGetConfig, error) {
+	return a.Parser.ParseFromYAML([]byte(content))
+}
+
+// ParseFromFileWithPositions 从文件解析配置并记录位置信息
+//
+// ParseFromFileWithPositions 使用位置感知解析器读取指定路径的文件内容，
+// 并将其解析为 NuGet 配置对象，同时记录每个元素的位置信息。
+// 这个方法返回的 ParseResult 可以用于创建位置感知的编辑器。
+//
+// Manager.PreserveFormatting 开启时，解析结果会被记录下来，之后通过 SaveConfig
+// 保存 ParseResult.Config 只改写发生变化的部分，未修改时保存结果与原文件逐字节相同。
+//
+// 参数:
+//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//
+// 返回值:
+//   - *parser.ParseResult: 包含配置对象、位置信息和原始内容的解析结果
+//   - error: 如果解析过程中发生错误，则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	// 解析配置文件并获取位置信息
+//	parseResult, err := api.ParseFromFileWithPositions("/path/to/NuGet.Config")
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//
+//	// 创建位置感知编辑器
+//	editor := api.CreateConfigEditor(parseResult)
+//
+//	// 执行编辑操作
+//	err = editor.AddPackageSource("new-source", "https://example.com/v3/index.json", "3")
+//	if err != nil {
+//	    fmt.Printf("添加包源失败: %v\n", err)
+//	    return
+//	}
+//
+//	// 应用编辑并获取修改后的内容
+//	modifiedContent, err := editor.ApplyEdits()
+//	if err != nil {
+//	    fmt.Printf("应用编辑失败: %v\n", err)
+//	    return
+//	}
+func (a *API) ParseFromFileWithPositions(filePath string) (*parser.ParseResult, error) {
+	positionAwareParser := parser.NewPositionAwareParser()
+	positionAwareParser.AllowEmptyPackageSources = a.Parser.AllowEmptyPackageSources
+	result, err := positionAwareParser.ParseFromFileWithPositions(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Manager.PreserveFormatting {
+		a.Manager.TrackParseResult(result)
+	}
+	return result, nil
+}
+
+// CreateConfigEditor 创建位置感知编辑器
+//
+// CreateConfigEditor 基于解析结果创建一个位置感知的配置编辑器。
+// 这个编辑器可以进行精确的文本编辑，保持原始文件的格式和缩进，
+// 最小化编辑后的diff。
+//
+// 参数:
+//   - parseResult: 包含位置信息的解析结果
+//
+// 返回值:
+//   - *editor.ConfigEditor: 位置感知编辑器实例
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	// 解析配置文件
 //	parseResult, err := api.ParseFromFileWithPositions("/path/to/NuGet.Config")
 //	if err != nil {
 //	    fmt.Printf("解析失败: %v\n", err)
@@ -1141,18 +2534,317 @@ func (a *API) ParseFromFileWithPositions(filePath string) (*parser.ParseResult,
 //	    return
 //	}
 //
-//	// 应用所有编辑
-//	modifiedContent, err := editor.ApplyEdits()
+//	// 应用所有编辑并以原子方式写回文件
+//	err = editor.ApplyEditsToFile("/path/to/NuGet.Config")
 //	if err != nil {
-//	    fmt.Printf("应用编辑失败: %v\n", err)
+//	    fmt.Printf("保存文件失败: %v\n", err)
+//	}
+func (a *API) CreateConfigEditor(parseResult *parser.ParseResult) *editor.ConfigEditor {
+	return editor.NewConfigEditor(parseResult)
+}
+
+// ParseWithPositions 从文件解析配置并记录位置信息
+//
+// ParseWithPositions 是 ParseFromFileWithPositions 的简写，行为完全相同。
+//
+// 参数:
+//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//
+// 返回值:
+//   - *parser.ParseResult: 包含配置对象、位置信息和原始内容的解析结果
+//   - error: 如果解析过程中发生错误，则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	result, err := api.ParseWithPositions("/path/to/NuGet.Config")
+//	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
 //	    return
 //	}
+//	if pos, ok := result.Positions["configuration/packageSources"]; ok {
+//	    fmt.Printf("packageSources 位于第 %d 行\n", pos.Range.Start.Line)
+//	}
+func (a *API) ParseWithPositions(filePath string) (*parser.ParseResult, error) {
+	return a.ParseFromFileWithPositions(filePath)
+}
+
+// CreateEditor 解析配置文件并创建位置感知编辑器
+//
+// CreateEditor 合并了 ParseWithPositions 和 CreateConfigEditor 两步，
+// 返回的编辑器独立于 Manager，编辑完成后调用 ApplyEditsToFile 写回文件。
+// 是否允许文件不定义包源与 Parser.AllowEmptyPackageSources 一致。
+//
+// 参数:
+//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//
+// 返回值:
+//   - *editor.ConfigEditor: 位置感知编辑器实例，如果解析失败则为 nil
+//   - error: 如果解析过程中发生错误，则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
 //
-//	// 保存修改后的内容
-//	err = os.WriteFile("/path/to/NuGet.Config", modifiedContent, 0644)
+//	ed, err := api.CreateEditor("/path/to/NuGet.Config")
 //	if err != nil {
+//	    fmt.Printf("解析失败: %v\n", err)
+//	    return
+//	}
+//
+//	if err := ed.DisablePackageSource("nuget.org"); err != nil {
+//	    fmt.Printf("禁用包源失败: %v\n", err)
+//	    return
+//	}
+//
+//	if err := ed.ApplyEditsToFile("/path/to/NuGet.Config"); err != nil {
 //	    fmt.Printf("保存文件失败: %v\n", err)
 //	}
-func (a *API) CreateConfigEditor(parseResult *parser.ParseResult) *editor.ConfigEditor {
-	return editor.NewConfigEditor(parseResult)
+func (a *API) CreateEditor(filePath string) (*editor.ConfigEditor, error) {
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = a.Parser.AllowEmptyPackageSources
+	result, err := p.ParseFromFileWithPositions(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return editor.NewConfigEditor(result), nil
+}
+
+// UpdateConfigFile 以保留格式的方式修改配置文件
+//
+// UpdateConfigFile 将位置感知解析、创建编辑器、应用编辑、校验和写回文件合并为一次调用。
+// 回调函数在编辑器上执行编辑操作，返回错误时文件保持不变；回调成功后编辑结果经过
+// 校验，再以原子方式写回原文件，未修改的部分保持原有格式和注释。
+//
+// 参数:
+//   - filePath: 配置文件的路径，可以是绝对路径或相对路径
+//   - fn: 执行编辑操作的回调函数
+//
+// 返回值:
+//   - error: 解析、回调、校验或写入失败时返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	err := api.UpdateConfigFile("/path/to/NuGet.Config", func(ed *editor.ConfigEditor) error {
+//	    if err := ed.AddPackageSource("company", "https://nuget.company.com/v3/index.json", "3"); err != nil {
+//	        return err
+//	    }
+//	    return ed.UpdatePackageSourceURL("localSource", "/new/path/to/packages")
+//	})
+//	if err != nil {
+//	    fmt.Printf("修改配置失败: %v\n", err)
+//	}
+func (a *API) UpdateConfigFile(filePath string, fn func(ed *editor.ConfigEditor) error) error {
+	ed, err := a.CreateEditor(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(ed); err != nil {
+		return err
+	}
+	return ed.ApplyEditsToFile(filePath)
+}
+
+// LoadSettings 加载当前环境下的层级配置
+//
+// LoadSettings 查找所有存在的配置文件，并按优先级从高到低组合为一个
+// settings.Settings 实现。读取值时离项目最近的配置文件优先生效，
+// 写入值时修改优先级最高的配置文件。
+//
+// 返回值:
+//   - *settings.HierarchySettings: 层级配置对象
+//   - error: 如果没有找到配置文件或加载失败则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	s, err := api.LoadSettings()
+//	if err != nil {
+//	    fmt.Printf("加载配置失败: %v\n", err)
+//	    return
+//	}
+//
+//	// 读取全局包文件夹
+//	if folder, ok := s.GetValue(settings.SectionConfig, "globalPackagesFolder"); ok {
+//	    fmt.Printf("全局包文件夹: %s\n", folder)
+//	}
+func (a *API) LoadSettings() (*settings.HierarchySettings, error) {
+	paths := a.configFinder().FindAllConfigFiles()
+	if len(paths) == 0 {
+		return nil, errors.ErrConfigFileNotFound
+	}
+
+	return settings.LoadHierarchySettings(paths...)
+}
+
+// GetEffectivePackageSources 获取最终生效的已启用包源
+//
+// GetEffectivePackageSources 查找所有存在的配置文件并按层级合并，
+// 应用每个层级中的 <clear /> 和 disabledPackageSources，
+// 返回与 `dotnet nuget list source` 中已启用包源一致的有序列表。
+//
+// 返回值:
+//   - []types.PackageSource: 生效的已启用包源列表
+//   - error: 如果没有找到配置文件或加载失败则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	sources, err := api.GetEffectivePackageSources()
+//	if err != nil {
+//	    fmt.Printf("获取包源失败: %v\n", err)
+//	    return
+//	}
+//
+//	for i, source := range sources {
+//	    fmt.Printf("%d. %s [%s]\n", i+1, source.Key, source.Value)
+//	}
+func (a *API) GetEffectivePackageSources() ([]types.PackageSource, error) {
+	s, err := a.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.EffectivePackageSources(s), nil
+}
+
+// GetCredentialForSource 获取指定包源最终生效的凭证
+//
+// GetCredentialForSource 按项目级 → 用户级 → 机器级的顺序在所有配置文件中
+// 查找指定包源的凭证，返回第一个找到的凭证及其所在的配置文件。
+// 这适用于包源定义在项目配置中、凭证保存在用户级配置中的常见场景。
+//
+// 参数:
+//   - sourceKey: 包源名称
+//
+// 返回值:
+//   - *settings.CredentialLookup: 生效的凭证及其来源文件
+//   - error: 如果没有找到配置文件、加载失败或没有找到该包源的凭证则返回相应的错误
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	lookup, err := api.GetCredentialForSource("private-feed")
+//	if err != nil {
+//	    fmt.Printf("获取凭证失败: %v\n", err)
+//	    return
+//	}
+//
+//	fmt.Printf("凭证来自: %s\n", lookup.Origin)
+//	for _, cred := range lookup.Credential.Add {
+//	    fmt.Printf("  %s\n", cred.Key)
+//	}
+func (a *API) GetCredentialForSource(sourceKey string) (*settings.CredentialLookup, error) {
+	s, err := a.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	lookup, ok := s.FindCredential(sourceKey)
+	if !ok {
+		return nil, fmt.Errorf("no credential found for package source '%s'", sourceKey)
+	}
+
+	return lookup, nil
+}
+
+// ExplainConfigChain 生成配置层级的解释报告
+//
+// ExplainConfigChain 查找所有存在的配置文件，按优先级从高到低列出每个文件，
+// 以及每个文件贡献的配置项是最终生效、被覆盖还是被 <clear /> 清除。
+// 这可用于排查"为什么还原时使用了某个包源"之类的问题。
+//
+// 返回值:
+//   - *settings.ConfigChainReport: 配置层级报告
+//   - error: 如果没有找到配置文件或加载失败则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	report, err := api.ExplainConfigChain()
+//	if err != nil {
+//	    fmt.Printf("生成报告失败: %v\n", err)
+//	    return
+//	}
+//
+//	fmt.Print(report.String())
+func (a *API) ExplainConfigChain() (*settings.ConfigChainReport, error) {
+	s, err := a.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Explain(), nil
+}
+
+// LoadEffectiveSettings 加载叠加了环境变量覆盖的层级配置
+//
+// LoadEffectiveSettings 在 LoadSettings 返回的层级配置之上叠加 NuGet 环境变量
+// （如 NUGET_PACKAGES、NUGET_HTTP_CACHE_PATH、NUGET_PLUGIN_PATHS），
+// 读取生效值时环境变量优先于配置文件和默认值。
+//
+// 返回值:
+//   - *settings.EnvironmentOverlay: 叠加了环境变量的配置对象
+//   - error: 如果没有找到配置文件或加载失败则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	s, err := api.LoadEffectiveSettings()
+//	if err != nil {
+//	    fmt.Printf("加载配置失败: %v\n", err)
+//	    return
+//	}
+//
+//	fmt.Printf("全局包文件夹: %s\n", s.GetGlobalPackagesFolder())
+//	fmt.Printf("HTTP缓存: %s\n", s.GetHTTPCachePath())
+func (a *API) LoadEffectiveSettings() (*settings.EnvironmentOverlay, error) {
+	s, err := a.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.NewEnvironmentOverlay(s), nil
+}
+
+// GetGlobalPackagesFolder 获取生效的全局包文件夹
+//
+// GetGlobalPackagesFolder 按 NuGet 的规则确定全局包文件夹：NUGET_PACKAGES 环境变量优先，
+// 其次是配置层级中的 globalPackagesFolder（展开其中的环境变量，相对路径按定义该值的
+// 配置文件所在目录解析），都未设置时使用平台默认位置 ~/.nuget/packages。
+// 没有找到任何配置文件时不算错误，直接使用环境变量或默认位置。
+//
+// 返回值:
+//   - string: 全局包文件夹路径
+//   - error: 如果配置文件加载失败则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	api := nuget.NewAPI()
+//
+//	folder, err := api.GetGlobalPackagesFolder()
+//	if err != nil {
+//	    fmt.Printf("获取全局包文件夹失败: %v\n", err)
+//	    return
+//	}
+//	fmt.Printf("全局包文件夹: %s\n", folder)
+func (a *API) GetGlobalPackagesFolder() (string, error) {
+	s, err := a.LoadEffectiveSettings()
+	if stderrors.Is(err, errors.ErrConfigFileNotFound) {
+		s, err = settings.NewEnvironmentOverlay(settings.NewHierarchySettings()), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return s.GetGlobalPackagesFolder(), nil
 }
@@ -1,6 +1,7 @@
 package nuget
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -8,8 +9,11 @@ import (
 	"testing"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/credentials"
+	"github.com/scagogogo/nuget-config-parser/pkg/editor"
 	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
 	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/validate"
 )
 
 func TestNewAPI(t *testing.T) {
@@ -383,6 +387,32 @@ func TestAPICredentialOperations(t *testing.T) {
 		}
 	}
 
+	if password, err := api.GetCredentialPassword(config, "test-source"); err != nil || password != "password" {
+		t.Errorf("GetCredentialPassword() = %q, %v", password, err)
+	}
+	if _, err := api.GetCredentialPassword(config, "missing"); err == nil {
+		t.Error("GetCredentialPassword() for a source without credentials should fail")
+	}
+
+	// 加密密码只有 Windows 支持
+	err := api.AddEncryptedCredential(config, "encrypted-source", "username", "secret")
+	if runtime.GOOS != "windows" {
+		if !errors.Is(err, credentials.ErrEncryptionUnsupported) {
+			t.Errorf("AddEncryptedCredential() error = %v, want ErrEncryptionUnsupported", err)
+		}
+		if _, exists := config.PackageSourceCredentials.Sources["encrypted-source"]; exists {
+			t.Error("failed AddEncryptedCredential() modified the config")
+		}
+		config.PackageSourceCredentials.Sources["encrypted-source"] = types.SourceCredential{Add: []types.Credential{{Key: "Password", Value: "AQAAANCMnd8B"}}}
+		if _, err := api.GetCredentialPassword(config, "encrypted-source"); !errors.Is(err, credentials.ErrEncryptionUnsupported) {
+			t.Errorf("GetCredentialPassword() of an encrypted password error = %v, want ErrEncryptionUnsupported", err)
+		}
+	} else if err != nil {
+		t.Errorf("AddEncryptedCredential() error = %v", err)
+	} else if password, err := api.GetCredentialPassword(config, "encrypted-source"); err != nil || password != "secret" {
+		t.Errorf("GetCredentialPassword() of an encrypted password = %q, %v", password, err)
+	}
+
 	// 测试移除凭证
 	removed := api.RemoveCredential(config, "test-source")
 	if !removed {
@@ -395,6 +425,122 @@ func TestAPICredentialOperations(t *testing.T) {
 	}
 }
 
+func TestAPICredentialPlaceholders(t *testing.T) {
+	t.Setenv("NUGET_CONFIG_TEST_PAT", "s3cret")
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="private" value="https://example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="ci" />
+      <add key="ClearTextPassword" value="%NUGET_CONFIG_TEST_PAT%" />
+    </private>
+  </packageSourceCredentials>
+</configuration>`
+
+	api := NewAPI()
+	config, err := api.ParseFromString(content)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+
+	// 占位符原样保存
+	xml, err := api.SerializeToXML(config)
+	if err != nil {
+		t.Fatalf("SerializeToXML() error = %v", err)
+	}
+	if !strings.Contains(xml, `value="%NUGET_CONFIG_TEST_PAT%"`) || strings.Contains(xml, "s3cret") {
+		t.Errorf("placeholder was not preserved:\n%s", xml)
+	}
+
+	creds, err := api.ResolveCredentials(config)
+	if err != nil {
+		t.Fatalf("ResolveCredentials() error = %v", err)
+	}
+	if got := creds.Sources["private"].Add[1].Value; got != "s3cret" {
+		t.Errorf("resolved password = %q", got)
+	}
+	if got := config.PackageSourceCredentials.Sources["private"].Add[1].Value; got != "%NUGET_CONFIG_TEST_PAT%" {
+		t.Errorf("ResolveCredentials() modified the config: %q", got)
+	}
+	if password, err := api.GetCredentialPassword(config, "private"); err != nil || password != "s3cret" {
+		t.Errorf("GetCredentialPassword() = %q, %v", password, err)
+	}
+
+	api.AddCredential(config, "private", "ci", "$NUGET_CONFIG_TEST_UNSET")
+	if _, err := api.GetCredentialPassword(config, "private"); !errors.Is(err, credentials.ErrUnresolvedPlaceholder) {
+		t.Errorf("GetCredentialPassword() error = %v, want ErrUnresolvedPlaceholder", err)
+	}
+	if _, err := api.ResolveCredentials(config); !errors.Is(err, credentials.ErrUnresolvedPlaceholder) {
+		t.Errorf("ResolveCredentials() error = %v, want ErrUnresolvedPlaceholder", err)
+	}
+}
+
+// mapStore 测试用的内存凭证存储
+type mapStore map[string]string
+
+func (s mapStore) Get(service, account string) (string, error) {
+	secret, ok := s[service+"|"+account]
+	if !ok {
+		return "", credentials.ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+func (s mapStore) Set(service, account, secret string) error {
+	s[service+"|"+account] = secret
+	return nil
+}
+
+func (s mapStore) Delete(service, account string) error {
+	delete(s, service+"|"+account)
+	return nil
+}
+
+func TestAPIStoredCredential(t *testing.T) {
+	store := mapStore{}
+	api := NewAPI()
+	api.CredentialStore = store
+	config := api.CreateDefaultConfig()
+
+	if err := api.AddStoredCredential(config, "private", "", "secret"); err == nil {
+		t.Error("AddStoredCredential() with an empty username should fail")
+	}
+	if err := api.AddStoredCredential(config, "private", "ci", "secret"); err != nil {
+		t.Fatalf("AddStoredCredential() error = %v", err)
+	}
+	if store["nuget:private|ci"] != "secret" {
+		t.Errorf("store = %v, want the secret under nuget:private", store)
+	}
+
+	xml, err := api.SerializeToXML(config)
+	if err != nil {
+		t.Fatalf("SerializeToXML() error = %v", err)
+	}
+	if strings.Contains(xml, "secret") || !strings.Contains(xml, `<add key="Username" value="ci"`) {
+		t.Errorf("serialized config should only contain the username:\n%s", xml)
+	}
+
+	if password, err := api.GetCredentialPassword(config, "private"); err != nil || password != "secret" {
+		t.Errorf("GetCredentialPassword() = %q, %v", password, err)
+	}
+
+	removed, err := api.RemoveStoredCredential(config, "private")
+	if err != nil || !removed {
+		t.Errorf("RemoveStoredCredential() = %v, %v", removed, err)
+	}
+	if len(store) != 0 {
+		t.Errorf("RemoveStoredCredential() left %v in the store", store)
+	}
+
+	config.PackageSourceCredentials.Sources["private"] = types.SourceCredential{Add: []types.Credential{{Key: "Username", Value: "ci"}}}
+	if _, err := api.GetCredentialPassword(config, "private"); err == nil {
+		t.Error("GetCredentialPassword() without a stored secret should fail")
+	}
+}
+
 func TestAPIConfigOptionOperations(t *testing.T) {
 	// 创建 API
 	api := NewAPI()
@@ -505,3 +651,314 @@ func pathsEqual(path1, path2 string) bool {
 	// 可以在这里添加更多的平台特定路径比较逻辑
 	return false
 }
+
+// setupConfigHierarchy 在临时目录中创建项目级和用户级配置文件，并切换到项目目录
+func setupConfigHierarchy(t *testing.T, projectXML, userXML string) (string, func()) {
+	tempDir := nugetTesting.CreateTempDir(t)
+
+	projectDir := filepath.Join(tempDir, "project")
+	xdgDir := filepath.Join(tempDir, "xdg")
+	nugetTesting.CreateNuGetConfigFile(t, filepath.Join(projectDir, constants.DefaultNuGetConfigFilename), projectXML)
+	nugetTesting.CreateNuGetConfigFile(t, filepath.Join(xdgDir, constants.GlobalFolderName, constants.DefaultNuGetConfigFilename), userXML)
+
+	restoreXDG := nugetTesting.SetupEnv(t, "XDG_CONFIG_HOME", xdgDir)
+	restoreEnvFile := nugetTesting.SetupEnv(t, "NUGET_CONFIG_FILE", "")
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(projectDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	return tempDir, func() {
+		os.Chdir(currentDir)
+		restoreEnvFile()
+		restoreXDG()
+		os.RemoveAll(tempDir)
+	}
+}
+
+func TestAPIGetEffectivePackageSources(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("user config location is controlled by XDG_CONFIG_HOME only on Linux")
+	}
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="project-feed" value="https://project.example.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="localSource" value="true" />
+  </disabledPackageSources>
+</configuration>`
+
+	userXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="localSource" value="/packages" />
+  </packageSources>
+</configuration>`
+
+	_, cleanup := setupConfigHierarchy(t, projectXML, userXML)
+	defer cleanup()
+
+	api := NewAPI()
+	sources, err := api.GetEffectivePackageSources()
+	if err != nil {
+		t.Fatalf("GetEffectivePackageSources() error = %v", err)
+	}
+
+	wantKeys := []string{"project-feed", "nuget.org"}
+	if len(sources) != len(wantKeys) {
+		t.Fatalf("GetEffectivePackageSources() = %+v, want keys %v", sources, wantKeys)
+	}
+	for i, key := range wantKeys {
+		if sources[i].Key != key {
+			t.Errorf("sources[%d].Key = %q, want %q", i, sources[i].Key, key)
+		}
+	}
+}
+
+func TestAPIGetCredentialForSource(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("user config location is controlled by XDG_CONFIG_HOME only on Linux")
+	}
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="private" value="https://private.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`
+
+	userXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="secret" />
+    </private>
+  </packageSourceCredentials>
+</configuration>`
+
+	tempDir, cleanup := setupConfigHierarchy(t, projectXML, userXML)
+	defer cleanup()
+
+	api := NewAPI()
+	lookup, err := api.GetCredentialForSource("private")
+	if err != nil {
+		t.Fatalf("GetCredentialForSource() error = %v", err)
+	}
+
+	if !strings.HasPrefix(lookup.Origin, tempDir) || !strings.Contains(lookup.Origin, "xdg") {
+		t.Errorf("Origin = %q, want user-level config", lookup.Origin)
+	}
+
+	if _, err := api.GetCredentialForSource("missing"); err == nil {
+		t.Error("GetCredentialForSource(missing) should return error")
+	}
+}
+
+func TestAPIGetGlobalPackagesFolder(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("user config location is controlled by XDG_CONFIG_HOME only on Linux")
+	}
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="project-feed" value="https://project.example.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <add key="globalPackagesFolder" value="packages" />
+  </config>
+</configuration>`
+
+	userXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>`
+
+	tempDir, cleanup := setupConfigHierarchy(t, projectXML, userXML)
+	defer cleanup()
+	restorePackages := nugetTesting.SetupEnv(t, constants.EnvNuGetPackages, "")
+	defer restorePackages()
+
+	api := NewAPI()
+	folder, err := api.GetGlobalPackagesFolder()
+	if err != nil {
+		t.Fatalf("GetGlobalPackagesFolder() error = %v", err)
+	}
+	if want := filepath.Join(tempDir, "project", "packages"); folder != want {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want %q", folder, want)
+	}
+
+	os.Setenv(constants.EnvNuGetPackages, "/env/packages")
+	if folder, _ := api.GetGlobalPackagesFolder(); folder != "/env/packages" {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want NUGET_PACKAGES value", folder)
+	}
+}
+
+func TestAPIUpdateConfigFile(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	original := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <!-- 公司包源 -->
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>`
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	api := NewAPI()
+
+	// 回调失败时文件保持不变
+	callbackErr := errors.New("abort")
+	err := api.UpdateConfigFile(configPath, func(ed *editor.ConfigEditor) error {
+		if err := ed.AddPackageSource("company", "https://nuget.company.com/v3/index.json", "3"); err != nil {
+			return err
+		}
+		return callbackErr
+	})
+	if err != callbackErr {
+		t.Fatalf("UpdateConfigFile() error = %v, want callback error", err)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != original {
+		t.Errorf("UpdateConfigFile() modified file after callback error:\n%s", data)
+	}
+
+	err = api.UpdateConfigFile(configPath, func(ed *editor.ConfigEditor) error {
+		return ed.AddPackageSource("company", "https://nuget.company.com/v3/index.json", "3")
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfigFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if !strings.Contains(string(data), "<!-- 公司包源 -->") {
+		t.Errorf("UpdateConfigFile() lost comment:\n%s", data)
+	}
+
+	config, err := api.ParseFromFile(configPath)
+	if err != nil {
+		t.Fatalf("ParseFromFile() error = %v", err)
+	}
+	if api.GetPackageSource(config, "company") == nil {
+		t.Error("UpdateConfigFile() did not add package source")
+	}
+
+	if err := api.UpdateConfigFile(filepath.Join(tempDir, "missing.config"), func(*editor.ConfigEditor) error { return nil }); err == nil {
+		t.Error("UpdateConfigFile() expected error for missing file")
+	}
+}
+
+func TestAPICreateEditor(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	original := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>`
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	api := NewAPI()
+
+	result, err := api.ParseWithPositions(configPath)
+	if err != nil {
+		t.Fatalf("ParseWithPositions() error = %v", err)
+	}
+	if _, ok := result.Positions["configuration/packageSources"]; !ok {
+		t.Error("ParseWithPositions() missing position for packageSources")
+	}
+
+	ed, err := api.CreateEditor(configPath)
+	if err != nil {
+		t.Fatalf("CreateEditor() error = %v", err)
+	}
+	if err := ed.UpdatePackageSourceVersion("nuget.org", "3"); err != nil {
+		t.Fatalf("UpdatePackageSourceVersion() error = %v", err)
+	}
+	if err := ed.ApplyEditsToFile(configPath); err != nil {
+		t.Fatalf("ApplyEditsToFile() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	want := strings.Replace(original, `index.json" />`, `index.json" protocolVersion="3" />`, 1)
+	if string(data) != want {
+		t.Errorf("CreateEditor() edit result =\n%s\nwant\n%s", data, want)
+	}
+
+	if _, err := api.CreateEditor(filepath.Join(tempDir, "missing.config")); err == nil {
+		t.Error("CreateEditor() expected error for missing file")
+	}
+}
+
+func TestAPIValidateConfigFile(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="nuget.org" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <add key="signatureValidationMode" value="sometimes" />
+  </config>
+</configuration>`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	api := NewAPI()
+	issues, err := api.ValidateConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("ValidateConfigFile() error = %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("ValidateConfigFile() returned %d issues, want 2: %v", len(issues), issues)
+	}
+	if issues[0].Section != "packageSources" || issues[1].Section != "config" {
+		t.Errorf("ValidateConfigFile() issues = %v", issues)
+	}
+
+	if _, err := api.ValidateConfigFile(filepath.Join(tempDir, "missing.config")); err == nil {
+		t.Error("ValidateConfigFile() expected error for missing file")
+	}
+
+	report, err := api.ValidateConfigFileWithRules(configPath, nil)
+	if err != nil {
+		t.Fatalf("ValidateConfigFileWithRules() error = %v", err)
+	}
+	if len(report.Issues) != 2 || report.Issues[0].Line != 5 || report.Issues[1].Rule != validate.RuleInvalidConfigValue {
+		t.Errorf("ValidateConfigFileWithRules() issues = %v", report.Issues)
+	}
+
+	v := validate.NewValidator()
+	v.Disable(validate.RuleDuplicateSourceKey)
+	if report, _ := api.ValidateConfigFileWithRules(configPath, v); len(report.Issues) != 1 {
+		t.Errorf("ValidateConfigFileWithRules() with a disabled rule = %v", report.Issues)
+	}
+}
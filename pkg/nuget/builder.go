@@ -0,0 +1,178 @@
+package nuget
+
+import (
+	"fmt"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// ConfigBuilder 以链式调用的方式构建NuGet配置
+//
+// 每一步都会检查参数，例如凭证和禁用操作引用的包源必须已经添加。
+// 第一个错误会被记录下来，之后的调用不再生效，由 Build 统一返回。
+//
+// 示例:
+//
+//	config, err := nuget.NewConfigBuilder().
+//	    AddSource("nuget.org", "https://api.nuget.org/v3/index.json").
+//	    AddSource("internal", "https://nuget.example.com/v3/index.json").
+//	    WithCredential("internal", "user", "token").
+//	    DisableSource("nuget.org").
+//	    Build()
+type ConfigBuilder struct {
+	manager *manager.ConfigManager
+	config  *types.NuGetConfig
+	err     error
+}
+
+// NewConfigBuilder 创建空的配置构建器
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{
+		manager: manager.NewConfigManager(),
+		config:  &types.NuGetConfig{},
+	}
+}
+
+// AddSource 添加包源，协议版本根据地址推断
+func (b *ConfigBuilder) AddSource(key, value string) *ConfigBuilder {
+	return b.AddSourceWithVersion(key, value, manager.InferProtocolVersion(value))
+}
+
+// AddSourceWithVersion 添加指定协议版本的包源，protocolVersion 为空时不写入
+func (b *ConfigBuilder) AddSourceWithVersion(key, value, protocolVersion string) *ConfigBuilder {
+	return b.apply(func() error {
+		if key == "" {
+			return fmt.Errorf("package source key must not be empty")
+		}
+		if err := manager.ValidateSourceValue(value); err != nil {
+			return fmt.Errorf("package source '%s': %w", key, err)
+		}
+		if b.manager.GetPackageSource(b.config, key) != nil {
+			return fmt.Errorf("package source with key '%s' already exists", key)
+		}
+		b.manager.AddPackageSource(b.config, key, value, protocolVersion)
+		return nil
+	})
+}
+
+// ClearSources 添加 <clear />，使配置不继承更低优先级配置文件中的包源
+func (b *ConfigBuilder) ClearSources() *ConfigBuilder {
+	return b.apply(func() error {
+		b.config.PackageSources.ClearElement = &types.ClearElement{}
+		return nil
+	})
+}
+
+// WithCredential 为已添加的包源设置用户名和明文密码
+func (b *ConfigBuilder) WithCredential(sourceKey, username, password string) *ConfigBuilder {
+	return b.apply(func() error {
+		if err := b.requireSource(sourceKey); err != nil {
+			return err
+		}
+		if username == "" {
+			return fmt.Errorf("credential for package source '%s' requires a username", sourceKey)
+		}
+		b.manager.AddCredential(b.config, sourceKey, username, password)
+		return nil
+	})
+}
+
+// DisableSource 禁用已添加的包源
+func (b *ConfigBuilder) DisableSource(key string) *ConfigBuilder {
+	return b.apply(func() error {
+		if err := b.requireSource(key); err != nil {
+			return err
+		}
+		b.manager.DisablePackageSource(b.config, key)
+		return nil
+	})
+}
+
+// SetActiveSource 将已添加的包源设为活跃包源
+func (b *ConfigBuilder) SetActiveSource(key string) *ConfigBuilder {
+	return b.apply(func() error {
+		return b.manager.SetActivePackageSource(b.config, key)
+	})
+}
+
+// SetOption 设置 <config> 选项，已知选项的值按 types.KnownConfigOptions 检查
+func (b *ConfigBuilder) SetOption(key, value string) *ConfigBuilder {
+	return b.apply(func() error {
+		if key == "" {
+			return fmt.Errorf("config option key must not be empty")
+		}
+		if spec, known := types.LookupConfigOption(key); known {
+			if err := spec.Validate(value); err != nil {
+				return err
+			}
+		}
+		b.manager.AddConfigOption(b.config, key, value)
+		return nil
+	})
+}
+
+// MapPackages 为已添加的包源添加包源映射规则
+func (b *ConfigBuilder) MapPackages(sourceKey string, patterns ...string) *ConfigBuilder {
+	return b.apply(func() error {
+		if err := b.requireSource(sourceKey); err != nil {
+			return err
+		}
+		if len(patterns) == 0 {
+			return fmt.Errorf("package source mapping for '%s' requires at least one pattern", sourceKey)
+		}
+
+		if b.config.PackageSourceMapping == nil {
+			b.config.PackageSourceMapping = &types.PackageSourceMapping{}
+		}
+		mapping := b.config.PackageSourceMapping
+		index := -1
+		for i, source := range mapping.PackageSource {
+			if source.Key == sourceKey {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			mapping.PackageSource = append(mapping.PackageSource, types.PackageSourceMappingSource{Key: sourceKey})
+			index = len(mapping.PackageSource) - 1
+		}
+		for _, pattern := range patterns {
+			if pattern == "" {
+				return fmt.Errorf("package source mapping for '%s' contains an empty pattern", sourceKey)
+			}
+			mapping.PackageSource[index].Package = append(mapping.PackageSource[index].Package, types.PackagePattern{Pattern: pattern})
+		}
+		return nil
+	})
+}
+
+// Build 返回构建好的配置，或构建过程中遇到的第一个错误
+//
+// 与解析配置文件时一样，没有添加任何包源且没有 <clear /> 时返回错误。
+// 每次调用都返回独立的副本，构建器可以继续使用。
+func (b *ConfigBuilder) Build() (*types.NuGetConfig, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.config.PackageSources.Add) == 0 && !b.config.PackageSources.IsCleared() {
+		return nil, fmt.Errorf("no package sources defined")
+	}
+	return b.config.Clone(), nil
+}
+
+// apply 在没有出错时执行一步构建
+func (b *ConfigBuilder) apply(step func() error) *ConfigBuilder {
+	if b.err == nil {
+		b.err = step()
+	}
+	return b
+}
+
+// requireSource 检查包源已经添加
+func (b *ConfigBuilder) requireSource(key string) error {
+	if b.manager.GetPackageSource(b.config, key) == nil {
+		return fmt.Errorf("package source with key '%s' not found", key)
+	}
+	return nil
+}
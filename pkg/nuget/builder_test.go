@@ -0,0 +1,83 @@
+package nuget
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigBuilder(t *testing.T) {
+	builder := NewConfigBuilder().
+		AddSource("nuget.org", "https://api.nuget.org/v3/index.json").
+		AddSource("internal", "https://nuget.example.com/v3/index.json").
+		AddSourceWithVersion("local", "/var/packages", "").
+		WithCredential("internal", "user", "token").
+		DisableSource("nuget.org").
+		SetActiveSource("internal").
+		SetOption("globalPackagesFolder", "/packages").
+		MapPackages("internal", "Contoso.*").
+		MapPackages("nuget.org", "*")
+
+	config, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if len(config.PackageSources.Add) != 3 || config.PackageSources.Add[0].ProtocolVersion != "3" {
+		t.Errorf("package sources = %+v", config.PackageSources.Add)
+	}
+	if _, ok := config.PackageSourceCredentials.Sources["internal"]; !ok {
+		t.Error("credential not added")
+	}
+	if len(config.DisabledPackageSources.Add) != 1 || config.DisabledPackageSources.Add[0].Key != "nuget.org" {
+		t.Errorf("disabled sources = %+v", config.DisabledPackageSources)
+	}
+	if config.ActivePackageSource.Add.Key != "internal" {
+		t.Errorf("active source = %+v", config.ActivePackageSource)
+	}
+	if len(config.PackageSourceMapping.PackageSource) != 2 {
+		t.Errorf("mapping = %+v", config.PackageSourceMapping)
+	}
+
+	// Build 返回独立的副本
+	config.PackageSources.Add = nil
+	if again, _ := builder.Build(); len(again.PackageSources.Add) != 3 {
+		t.Error("Build() result shares state with the builder")
+	}
+
+	xml, err := NewAPI().SerializeToXML(config)
+	if err != nil || !strings.Contains(xml, "disabledPackageSources") {
+		t.Errorf("SerializeToXML() = %q, %v", xml, err)
+	}
+}
+
+func TestConfigBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ConfigBuilder
+		wantErr string
+	}{
+		{"no sources", NewConfigBuilder(), "no package sources"},
+		{"empty key", NewConfigBuilder().AddSource("", "https://a.example.com"), "key must not be empty"},
+		{"bad url", NewConfigBuilder().AddSource("a", "https://"), "missing host"},
+		{"duplicate", NewConfigBuilder().AddSource("a", "/a").AddSource("a", "/b"), "already exists"},
+		{"unknown credential source", NewConfigBuilder().AddSource("a", "/a").WithCredential("b", "u", "p"), "'b' not found"},
+		{"unknown disabled source", NewConfigBuilder().AddSource("a", "/a").DisableSource("b"), "'b' not found"},
+		{"bad option", NewConfigBuilder().AddSource("a", "/a").SetOption("maxHttpRequestsPerSource", "0"), "maxHttpRequestsPerSource"},
+		{"first error wins", NewConfigBuilder().DisableSource("x").AddSource("", ""), "'x' not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if config != nil {
+				t.Error("Build() returned config with error")
+			}
+		})
+	}
+
+	if config, err := NewConfigBuilder().ClearSources().Build(); err != nil || !config.PackageSources.IsCleared() {
+		t.Errorf("Build() with only <clear /> = %+v, %v", config, err)
+	}
+}
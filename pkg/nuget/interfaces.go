@@ -0,0 +1,82 @@
+package nuget
+
+import (
+	"io"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/finder"
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// ConfigParser 将配置内容解析为配置对象
+//
+// *parser.ConfigParser 和 *API 都实现了该接口。
+type ConfigParser interface {
+	ParseFromFile(filePath string) (*types.NuGetConfig, error)
+	ParseFromString(content string) (*types.NuGetConfig, error)
+	ParseFromReader(reader io.Reader) (*types.NuGetConfig, error)
+}
+
+// ConfigFinder 查找配置文件
+//
+// *finder.ConfigFinder 和 *API 都实现了该接口。
+type ConfigFinder interface {
+	FindConfigFile() (string, error)
+	FindAllConfigFiles() []string
+	FindProjectConfig(startDir string) (string, error)
+}
+
+// ConfigStore 加载和保存配置文件
+//
+// *manager.ConfigManager 实现了该接口。
+type ConfigStore interface {
+	LoadConfig(filePath string) (*types.NuGetConfig, error)
+	FindAndLoadConfig() (*types.NuGetConfig, string, error)
+	SaveConfig(config *types.NuGetConfig, filePath string) error
+}
+
+var (
+	_ ConfigParser = (*parser.ConfigParser)(nil)
+	_ ConfigParser = (*API)(nil)
+	_ ConfigFinder = (*finder.ConfigFinder)(nil)
+	_ ConfigFinder = (*API)(nil)
+	_ ConfigStore  = (*manager.ConfigManager)(nil)
+)
+
+// NewAPIWithDependencies 使用指定的解析器、查找器和存储创建API实例
+//
+// 解析、查找、加载和保存配置的方法使用传入的实现，便于在测试中注入假实现，
+// 或者替换为从远程读取配置的实现。参数为 nil 时使用默认实现。
+// 序列化、位置感知编辑和层级配置等其他功能仍使用 Parser、Finder 和 Manager 字段。
+func NewAPIWithDependencies(configParser ConfigParser, configFinder ConfigFinder, store ConfigStore) *API {
+	a := NewAPI()
+	a.parser = configParser
+	a.finder = configFinder
+	a.store = store
+	return a
+}
+
+// configParser 返回解析配置使用的实现
+func (a *API) configParser() ConfigParser {
+	if a.parser != nil {
+		return a.parser
+	}
+	return a.Parser
+}
+
+// configFinder 返回查找配置文件使用的实现
+func (a *API) configFinder() ConfigFinder {
+	if a.finder != nil {
+		return a.finder
+	}
+	return a.Finder
+}
+
+// configStore 返回加载和保存配置使用的实现
+func (a *API) configStore() ConfigStore {
+	if a.store != nil {
+		return a.store
+	}
+	return a.Manager
+}
@@ -0,0 +1,74 @@
+package nuget
+
+import (
+	"io"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+type fakeParser struct{ config *types.NuGetConfig }
+
+func (p *fakeParser) ParseFromFile(string) (*types.NuGetConfig, error)      { return p.config, nil }
+func (p *fakeParser) ParseFromString(string) (*types.NuGetConfig, error)    { return p.config, nil }
+func (p *fakeParser) ParseFromReader(io.Reader) (*types.NuGetConfig, error) { return p.config, nil }
+
+type fakeFinder struct{ paths []string }
+
+func (f *fakeFinder) FindConfigFile() (string, error)          { return f.paths[0], nil }
+func (f *fakeFinder) FindAllConfigFiles() []string             { return f.paths }
+func (f *fakeFinder) FindProjectConfig(string) (string, error) { return f.paths[0], nil }
+
+type fakeStore struct {
+	configs map[string]*types.NuGetConfig
+}
+
+func (s *fakeStore) LoadConfig(filePath string) (*types.NuGetConfig, error) {
+	return s.configs[filePath], nil
+}
+
+func (s *fakeStore) FindAndLoadConfig() (*types.NuGetConfig, string, error) {
+	return s.configs["/remote/NuGet.Config"], "/remote/NuGet.Config", nil
+}
+
+func (s *fakeStore) SaveConfig(config *types.NuGetConfig, filePath string) error {
+	s.configs[filePath] = config
+	return nil
+}
+
+func TestNewAPIWithDependencies(t *testing.T) {
+	parsed := &types.NuGetConfig{}
+	stored := &types.NuGetConfig{}
+	store := &fakeStore{configs: map[string]*types.NuGetConfig{"/remote/NuGet.Config": stored}}
+	api := NewAPIWithDependencies(&fakeParser{config: parsed}, &fakeFinder{paths: []string{"/remote/NuGet.Config"}}, store)
+
+	if config, err := api.ParseFromFile("/does/not/exist"); err != nil || config != parsed {
+		t.Errorf("ParseFromFile() = %p, %v, want injected parser result", config, err)
+	}
+	if config, err := api.ParseFromString("not xml"); err != nil || config != parsed {
+		t.Errorf("ParseFromString() = %p, %v, want injected parser result", config, err)
+	}
+	if path, err := api.FindConfigFile(); err != nil || path != "/remote/NuGet.Config" {
+		t.Errorf("FindConfigFile() = %q, %v", path, err)
+	}
+	if paths := api.FindAllConfigFiles(); len(paths) != 1 {
+		t.Errorf("FindAllConfigFiles() = %v", paths)
+	}
+	if config, path, err := api.FindAndParseConfig(); err != nil || config != stored || path != "/remote/NuGet.Config" {
+		t.Errorf("FindAndParseConfig() = %p, %q, %v", config, path, err)
+	}
+
+	saved := &types.NuGetConfig{}
+	if err := api.SaveConfig(saved, "/remote/other.config"); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if store.configs["/remote/other.config"] != saved {
+		t.Error("SaveConfig() did not use injected store")
+	}
+
+	// 参数为 nil 时使用默认实现
+	api = NewAPIWithDependencies(nil, nil, nil)
+	if _, err := api.ParseFromString("not xml"); err == nil {
+		t.Error("ParseFromString() with default parser expected error")
+	}
+}
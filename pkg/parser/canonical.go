@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// CanonicalSerializeOptions 返回规范形式使用的序列化选项：两个空格缩进、"\n" 换行、
+// 带 encoding="utf-8" 的XML声明、key 和 value 属性在前且其余属性按名称排序、
+// 空元素使用 " />" 自闭合、属性值使用双引号
+func CanonicalSerializeOptions() SerializeOptions {
+	style := DefaultElementStyle()
+	return SerializeOptions{
+		Indent:         "  ",
+		Newline:        "\n",
+		Encoding:       "utf-8",
+		AttributeOrder: []string{"key", "value"},
+		SortAttributes: true,
+		ElementStyle:   &style,
+	}
+}
+
+// SerializeCanonical 将配置序列化为规范形式的XML字符串
+//
+// 规范形式中包源、配置选项、禁用的包源、凭证和包源映射都按 key 排序，
+// 属性顺序和空白格式固定，不包含任何项且未清除继承项的可选配置节会被省略，
+// <packageSources clear="true"> 统一写作 <clear /> 子元素。语义相同的配置总是得到
+// 相同的输出，适用于比较配置差异或以内容哈希作为缓存键。
+//
+// 注意包源的顺序决定了 NuGet 查询包源的先后，规范形式不保留这一顺序。
+// SerializeCanonical 不会修改传入的配置。
+func (p *ConfigParser) SerializeCanonical(config *types.NuGetConfig) (string, error) {
+	return p.SerializeToXMLWithOptions(canonicalConfig(config), CanonicalSerializeOptions())
+}
+
+// canonicalConfig 返回配置的规范化副本
+func canonicalConfig(config *types.NuGetConfig) *types.NuGetConfig {
+	canonical := &types.NuGetConfig{}
+
+	canonical.PackageSources.Add = append([]types.PackageSource(nil), config.PackageSources.Add...)
+	sort.SliceStable(canonical.PackageSources.Add, func(i, j int) bool {
+		return canonical.PackageSources.Add[i].Key < canonical.PackageSources.Add[j].Key
+	})
+	if config.PackageSources.IsCleared() {
+		canonical.PackageSources.ClearElement = &types.ClearElement{}
+	}
+
+	if creds := config.PackageSourceCredentials; creds != nil && len(creds.Sources) > 0 {
+		canonical.PackageSourceCredentials = &types.PackageSourceCredentials{
+			Sources: make(map[string]types.SourceCredential, len(creds.Sources)),
+		}
+		for name, cred := range creds.Sources {
+			adds := append([]types.Credential(nil), cred.Add...)
+			sort.SliceStable(adds, func(i, j int) bool { return adds[i].Key < adds[j].Key })
+			canonical.PackageSourceCredentials.Sources[name] = types.SourceCredential{Add: adds}
+		}
+	}
+
+	if c := config.Config; c != nil && (c.IsCleared() || len(c.Add) > 0) {
+		canonical.Config = &types.Config{Add: append([]types.ConfigOption(nil), c.Add...)}
+		sort.SliceStable(canonical.Config.Add, func(i, j int) bool {
+			return canonical.Config.Add[i].Key < canonical.Config.Add[j].Key
+		})
+		if c.IsCleared() {
+			canonical.Config.ClearElement = &types.ClearElement{}
+		}
+	}
+
+	if d := config.DisabledPackageSources; d != nil && (d.IsCleared() || len(d.Add) > 0) {
+		canonical.DisabledPackageSources = &types.DisabledPackageSources{Add: append([]types.DisabledSource(nil), d.Add...)}
+		sort.SliceStable(canonical.DisabledPackageSources.Add, func(i, j int) bool {
+			return canonical.DisabledPackageSources.Add[i].Key < canonical.DisabledPackageSources.Add[j].Key
+		})
+		if d.IsCleared() {
+			canonical.DisabledPackageSources.ClearElement = &types.ClearElement{}
+		}
+	}
+
+	if active := config.ActivePackageSource; active != nil && active.Add.Key != "" {
+		canonical.ActivePackageSource = &types.ActivePackageSource{Add: active.Add}
+	}
+
+	if m := config.PackageSourceMapping; m != nil && (m.IsCleared() || len(m.PackageSource) > 0) {
+		canonical.PackageSourceMapping = &types.PackageSourceMapping{}
+		if m.IsCleared() {
+			canonical.PackageSourceMapping.ClearElement = &types.ClearElement{}
+		}
+		for _, source := range m.PackageSource {
+			patterns := append([]types.PackagePattern(nil), source.Package...)
+			sort.SliceStable(patterns, func(i, j int) bool { return patterns[i].Pattern < patterns[j].Pattern })
+			canonical.PackageSourceMapping.PackageSource = append(canonical.PackageSourceMapping.PackageSource,
+				types.PackageSourceMappingSource{Key: source.Key, Package: patterns})
+		}
+		sort.SliceStable(canonical.PackageSourceMapping.PackageSource, func(i, j int) bool {
+			return canonical.PackageSourceMapping.PackageSource[i].Key < canonical.PackageSourceMapping.PackageSource[j].Key
+		})
+	}
+
+	return canonical
+}
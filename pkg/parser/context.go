@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"context"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// ParseFromFileContext 从文件解析配置，在读取和解析前检查 ctx
+//
+// ctx 已取消或超时时返回 ctx.Err()，可以用 errors.Is 与 context.Canceled
+// 和 context.DeadlineExceeded 比较。其他错误与 ParseFromFile 相同，包装为 *errors.ConfigError。
+func (p *ConfigParser) ParseFromFileContext(ctx context.Context, filePath string) (*types.NuGetConfig, error) {
+	data, err := p.readFileContext(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := p.ParseFromContent(data)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpParse, filePath, err)
+	}
+	return config, nil
+}
+
+// ParseFromFileWithPositionsContext 从文件解析配置并记录位置信息，在读取和解析前检查 ctx
+func (p *ConfigParser) ParseFromFileWithPositionsContext(ctx context.Context, filePath string) (*ParseResult, error) {
+	data, err := p.readFileContext(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.ParseFromContentWithPositions(data)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpParse, filePath, err)
+	}
+	return result, nil
+}
+
+// SaveToFileContext 将配置保存到文件，在序列化和写入前检查 ctx
+func (p *ConfigParser) SaveToFileContext(ctx context.Context, config *types.NuGetConfig, filePath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	xmlString, err := p.SerializeToXML(config)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return errors.WithCode(errors.CodeIO, utils.WriteToFile(filePath, EncodeContent([]byte(xmlString), p.OutputEncoding)))
+}
+
+// readFileContext 在读取文件前后检查 ctx
+func (p *ConfigParser) readFileContext(ctx context.Context, filePath string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := p.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
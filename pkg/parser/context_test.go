@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+)
+
+func TestParseAndSaveContext(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	p := NewConfigParser()
+	config, err := p.ParseFromFileContext(context.Background(), configPath)
+	if err != nil {
+		t.Fatalf("ParseFromFileContext() error = %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := p.ParseFromFileContext(canceled, configPath); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseFromFileContext() with canceled context error = %v, want context.Canceled", err)
+	}
+	if _, err := p.ParseFromFileWithPositionsContext(canceled, configPath); !errors.Is(err, context.Canceled) {
+		t.Errorf("ParseFromFileWithPositionsContext() with canceled context error = %v, want context.Canceled", err)
+	}
+
+	outPath := filepath.Join(tempDir, "out.config")
+	if err := p.SaveToFileContext(canceled, config, outPath); !errors.Is(err, context.Canceled) {
+		t.Errorf("SaveToFileContext() with canceled context error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+		t.Error("SaveToFileContext() with canceled context wrote the file")
+	}
+	if err := p.SaveToFileContext(context.Background(), config, outPath); err != nil {
+		t.Fatalf("SaveToFileContext() error = %v", err)
+	}
+	if _, err := p.ParseFromFileContext(context.Background(), outPath); err != nil {
+		t.Errorf("ParseFromFileContext() of saved file error = %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// Encoding 配置文件的字符编码
+type Encoding int
+
+const (
+	// EncodingUTF8 不带BOM的UTF-8，默认值
+	EncodingUTF8 Encoding = iota
+	// EncodingUTF8BOM 带BOM的UTF-8
+	EncodingUTF8BOM
+	// EncodingUTF16LE 带BOM的UTF-16小端序，Visual Studio 保存 NuGet.Config 时常用
+	EncodingUTF16LE
+	// EncodingUTF16BE 带BOM的UTF-16大端序
+	EncodingUTF16BE
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// String 返回编码名称
+func (enc Encoding) String() string {
+	switch enc {
+	case EncodingUTF8:
+		return "utf-8"
+	case EncodingUTF8BOM:
+		return "utf-8 with BOM"
+	case EncodingUTF16LE:
+		return "utf-16le"
+	case EncodingUTF16BE:
+		return "utf-16be"
+	default:
+		return fmt.Sprintf("encoding(%d)", int(enc))
+	}
+}
+
+// DetectEncoding 根据BOM检测内容的编码
+//
+// 没有BOM时按XML规范附录F根据前两个字符 "<?" 或 "<c" 的字节模式识别UTF-16，
+// 其余情况视为UTF-8。
+func DetectEncoding(content []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return EncodingUTF8BOM
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return EncodingUTF16BE
+	case len(content) >= 4 && content[0] == '<' && content[1] == 0 && content[2] != 0 && content[3] == 0:
+		return EncodingUTF16LE
+	case len(content) >= 4 && content[0] == 0 && content[1] == '<' && content[2] == 0 && content[3] != 0:
+		return EncodingUTF16BE
+	default:
+		return EncodingUTF8
+	}
+}
+
+// DecodeContent 去除BOM并将内容转换为UTF-8，同时返回检测到的原始编码
+func DecodeContent(content []byte) ([]byte, Encoding, error) {
+	enc := DetectEncoding(content)
+	switch enc {
+	case EncodingUTF8BOM:
+		return content[len(bomUTF8):], enc, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		decoded, err := decodeUTF16(content, enc)
+		return decoded, enc, err
+	default:
+		return content, enc, nil
+	}
+}
+
+// EncodeContent 将UTF-8内容按指定编码输出，必要时添加BOM
+//
+// XML声明中的 encoding 属性会同步修改为目标编码，避免声明与实际编码不一致。
+func EncodeContent(content []byte, enc Encoding) []byte {
+	content = bytes.TrimPrefix(content, bomUTF8)
+
+	switch enc {
+	case EncodingUTF8BOM:
+		content = setDeclaredEncoding(content, "utf-8")
+		return append(append([]byte{}, bomUTF8...), content...)
+	case EncodingUTF16LE, EncodingUTF16BE:
+		return encodeUTF16(setDeclaredEncoding(content, "utf-16"), enc)
+	default:
+		return setDeclaredEncoding(content, "utf-8")
+	}
+}
+
+// decodeUTF16 将UTF-16内容转换为UTF-8
+func decodeUTF16(content []byte, enc Encoding) ([]byte, error) {
+	if bytes.HasPrefix(content, bomUTF16LE) || bytes.HasPrefix(content, bomUTF16BE) {
+		content = content[2:]
+	}
+	if len(content)%2 != 0 {
+		return nil, fmt.Errorf("invalid %s content: odd number of bytes", enc)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if enc == EncodingUTF16BE {
+		order = binary.BigEndian
+	}
+
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		units[i] = order.Uint16(content[2*i:])
+	}
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+// encodeUTF16 将UTF-8内容转换为带BOM的UTF-16
+func encodeUTF16(content []byte, enc Encoding) []byte {
+	var order binary.AppendByteOrder = binary.LittleEndian
+	bom := bomUTF16LE
+	if enc == EncodingUTF16BE {
+		order = binary.BigEndian
+		bom = bomUTF16BE
+	}
+
+	units := utf16.Encode([]rune(string(content)))
+	out := make([]byte, len(bom), len(bom)+2*len(units))
+	copy(out, bom)
+	for _, u := range units {
+		out = order.AppendUint16(out, u)
+	}
+	return out
+}
+
+// declaredEncodingPattern 匹配XML声明中的 encoding 属性值
+var declaredEncodingPattern = regexp.MustCompile(`^(<\?xml[^>]*?\sencoding\s*=\s*["'])([^"']*)(["'])`)
+
+// setDeclaredEncoding 修改XML声明中的 encoding 属性，没有声明或未指定编码时保持不变
+func setDeclaredEncoding(content []byte, name string) []byte {
+	match := declaredEncodingPattern.FindSubmatchIndex(content)
+	if match == nil || strings.EqualFold(string(content[match[4]:match[5]]), name) {
+		return content
+	}
+
+	out := make([]byte, 0, len(content)+len(name))
+	out = append(out, content[:match[4]]...)
+	out = append(out, name...)
+	return append(out, content[match[5]:]...)
+}
+
+// newDecoder 创建用于解析已转换为UTF-8内容的 Decoder
+//
+// 内容已由 DecodeContent 转换为UTF-8，XML声明中的 utf-16 等编码只是原始文件的编码，
+// 因此直接读取；其他编码则不支持。
+func newDecoder(content []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(label) {
+		case "utf-8", "utf8", "utf-16", "utf-16le", "utf-16be", "unicode", "us-ascii", "ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported encoding %q", label)
+	}
+	return decoder
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+)
+
+const utf16Config = `<?xml version="1.0" encoding="utf-16"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="本地" value="C:\包" />
+  </packageSources>
+</configuration>`
+
+func TestParseEncodings(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding Encoding
+	}{
+		{"utf-8", EncodingUTF8},
+		{"utf-8 with BOM", EncodingUTF8BOM},
+		{"utf-16le", EncodingUTF16LE},
+		{"utf-16be", EncodingUTF16BE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := EncodeContent([]byte(utf16Config), tt.encoding)
+			if got := DetectEncoding(encoded); got != tt.encoding {
+				t.Errorf("DetectEncoding() = %v, want %v", got, tt.encoding)
+			}
+
+			config, err := NewConfigParser().ParseFromContent(encoded)
+			if err != nil {
+				t.Fatalf("ParseFromContent() error = %v", err)
+			}
+			if len(config.PackageSources.Add) != 2 || config.PackageSources.Add[1].Key != "本地" {
+				t.Errorf("unexpected package sources: %+v", config.PackageSources.Add)
+			}
+
+			result, err := NewPositionAwareParser().ParseFromContentWithPositions(encoded)
+			if err != nil {
+				t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+			}
+			if result.Encoding != tt.encoding {
+				t.Errorf("ParseResult.Encoding = %v, want %v", result.Encoding, tt.encoding)
+			}
+			elemPos := result.Positions["configuration/packageSources/add[1]"]
+			valueRange := elemPos.AttrRanges["value"]
+			if got := string(result.Content[valueRange.Start.Offset:valueRange.End.Offset]); got != `C:\包` {
+				t.Errorf("value range covers %q", got)
+			}
+
+			// 编码后再解码应得到相同的内容
+			decoded, enc, err := DecodeContent(encoded)
+			if err != nil {
+				t.Fatalf("DecodeContent() error = %v", err)
+			}
+			if enc != tt.encoding || !bytes.Equal(EncodeContent(decoded, enc), encoded) {
+				t.Errorf("round trip through %v changed the content", tt.encoding)
+			}
+		})
+	}
+}
+
+func TestDetectEncodingWithoutBOM(t *testing.T) {
+	le := EncodeContent([]byte(utf16Config), EncodingUTF16LE)[2:]
+	if got := DetectEncoding(le); got != EncodingUTF16LE {
+		t.Errorf("DetectEncoding() = %v, want %v", got, EncodingUTF16LE)
+	}
+	if _, err := NewConfigParser().ParseFromContent(le); err != nil {
+		t.Errorf("ParseFromContent() error = %v", err)
+	}
+}
+
+func TestEncodeContentUpdatesDeclaration(t *testing.T) {
+	content := []byte(`<?xml version="1.0" encoding="utf-16"?><configuration />`)
+	if got := string(EncodeContent(content, EncodingUTF8)); got != `<?xml version="1.0" encoding="utf-8"?><configuration />` {
+		t.Errorf("EncodeContent() = %s", got)
+	}
+
+	decoded, _, err := DecodeContent(EncodeContent([]byte(`<?xml version="1.0" encoding="utf-8"?><configuration />`), EncodingUTF16LE))
+	if err != nil {
+		t.Fatalf("DecodeContent() error = %v", err)
+	}
+	if string(decoded) != `<?xml version="1.0" encoding="utf-16"?><configuration />` {
+		t.Errorf("UTF-16 output should declare utf-16, got %s", decoded)
+	}
+}
+
+func TestSaveToFileOutputEncoding(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	p := NewConfigParser()
+	config, err := p.ParseFromString(nugetTesting.ValidNuGetConfig())
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+
+	p.OutputEncoding = EncodingUTF16LE
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	if err := p.SaveToFile(config, configPath); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if DetectEncoding(data) != EncodingUTF16LE {
+		t.Errorf("saved file should be UTF-16LE with BOM")
+	}
+	saved, err := p.ParseFromFile(configPath)
+	if err != nil {
+		t.Fatalf("ParseFromFile() error = %v", err)
+	}
+	if len(saved.PackageSources.Add) != len(config.PackageSources.Add) {
+		t.Errorf("package sources count mismatch after saving as UTF-16")
+	}
+}
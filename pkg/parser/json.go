@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// SerializeToJSON 将配置序列化为带缩进的JSON字符串
+//
+// 字段名与XML中的元素名和属性名一致，格式见 types 包中的说明。
+func (p *ConfigParser) SerializeToJSON(config *types.NuGetConfig) (string, error) {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// ParseFromJSON 从 SerializeToJSON 生成的JSON解析配置
+//
+// 与解析XML时一样，未定义任何包源时返回错误，除非设置了 AllowEmptyPackageSources。
+// 语法错误返回的 *errors.ParseError 带有出错位置。
+func (p *ConfigParser) ParseFromJSON(content []byte) (*types.NuGetConfig, error) {
+	if err := p.checkInputSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, errors.ErrEmptyConfigFile
+	}
+	return p.configFromJSON(content)
+}
+
+// configFromJSON 将JSON解码为配置并检查必需的字段
+func (p *ConfigParser) configFromJSON(content []byte) (*types.NuGetConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	var config types.NuGetConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, jsonParseError(content, err)
+	}
+
+	if len(config.PackageSources.Add) == 0 && !p.AllowEmptyPackageSources && !config.PackageSources.IsCleared() {
+		return nil, noPackageSourcesError()
+	}
+
+	return &config, nil
+}
+
+// scalarsToStrings 将手写格式中的布尔值和数字转换为字符串
+//
+// 配置中除 clear 外的字段都是字符串，手写的 value: true 或 protocolVersion = 3 这样的
+// 值按字符串读取；clear 的值保持原样，由之后的严格解码检查其类型。
+func scalarsToStrings(value interface{}, key string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = scalarsToStrings(item, k)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = scalarsToStrings(item, key)
+		}
+	case bool:
+		if key != "clear" {
+			return strconv.FormatBool(v)
+		}
+	case json.Number:
+		if key != "clear" {
+			return v.String()
+		}
+	}
+	return value
+}
+
+// jsonParseError 将 encoding/json 的错误转换为解析错误，语法错误带有出错位置
+//
+// 类型错误可能发生在自定义的 UnmarshalJSON 中，其偏移量相对于子对象，因此不记录位置。
+func jsonParseError(content []byte, err error) error {
+	offset := -1
+	var syntaxErr *json.SyntaxError
+	if stderrors.As(err, &syntaxErr) {
+		// Offset 是读取出错字符之后的位置
+		offset = int(syntaxErr.Offset) - 1
+	}
+
+	if offset < 0 || offset > len(content) {
+		return errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, fmt.Sprintf("json decode error: %v", err))
+	}
+
+	pos := newLineIndex(content).position(offset)
+	parseErr := errors.NewParseError(errors.ErrInvalidConfigFormat, pos.Line, pos.Column, fmt.Sprintf("json decode error: %v", err))
+	parseErr.Offset = pos.Offset
+	return parseErr
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestSerializeToJSONRoundTrip(t *testing.T) {
+	p := NewConfigParser()
+	config, err := p.ParseFromString(`<configuration>
+  <packageSources>
+    <clear />
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+  </packageSources>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="user" />
+    </nuget.org>
+  </packageSourceCredentials>
+</configuration>`)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+
+	jsonString, err := p.SerializeToJSON(config)
+	if err != nil {
+		t.Fatalf("SerializeToJSON() error = %v", err)
+	}
+
+	parsed, err := p.ParseFromJSON([]byte(jsonString))
+	if err != nil {
+		t.Fatalf("ParseFromJSON() error = %v\n%s", err, jsonString)
+	}
+	if !parsed.PackageSources.IsCleared() || len(parsed.PackageSources.Add) != 1 ||
+		parsed.PackageSources.Add[0].ProtocolVersion != "3" {
+		t.Errorf("unexpected package sources: %+v", parsed.PackageSources)
+	}
+	if cred := parsed.PackageSourceCredentials.Sources["nuget.org"]; len(cred.Add) != 1 || cred.Add[0].Value != "user" {
+		t.Errorf("unexpected credentials: %+v", parsed.PackageSourceCredentials)
+	}
+}
+
+func TestParseFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  error
+		wantLine int
+		wantCol  int
+	}{
+		{"空内容", "  ", errors.ErrEmptyConfigFile, 0, 0},
+		{"语法错误", "{\n  \"packageSources\": {\"add\": [}\n}", errors.ErrInvalidConfigFormat, 2, 30},
+		{"类型错误", "{\n  \"packageSources\": {\"add\": \"x\"}\n}", errors.ErrInvalidConfigFormat, 0, 0},
+		{"未知字段", `{"packageSource": {}}`, errors.ErrInvalidConfigFormat, 0, 0},
+		{"没有包源", `{"packageSources": {}}`, errors.ErrMissingRequiredElement, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigParser().ParseFromJSON([]byte(tt.content))
+			if !stderrors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFromJSON() error = %v, want %v", err, tt.wantErr)
+			}
+			var parseErr *errors.ParseError
+			if stderrors.As(err, &parseErr) && (parseErr.Line != tt.wantLine || parseErr.Position != tt.wantCol) {
+				t.Errorf("error position = %d:%d, want %d:%d", parseErr.Line, parseErr.Position, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
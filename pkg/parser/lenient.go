@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// Severity 诊断信息的严重程度
+type Severity int
+
+const (
+	// SeverityWarning 不影响解析结果的问题，如未知元素、重复的 key
+	SeverityWarning Severity = iota
+	// SeverityError 导致部分内容被忽略或结果不可靠的问题，如XML语法错误、缺少必需属性
+	SeverityError
+)
+
+// String 返回严重程度的名称
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// 诊断信息的规则名称，用于 Diagnostic.Rule 和 errors.ParseError.Rule
+//
+// 规则比错误码更细，例如 RuleUnknownSection 和 RuleUnknownAttribute 的错误码都是
+// errors.CodeUnknownElement。使用方可以根据规则和结构化字段生成自己的提示信息。
+const (
+	// RuleXMLSyntax 内容不是格式正确的XML
+	RuleXMLSyntax = "xml-syntax"
+	// RuleRootElement 根元素不是 <configuration>，Expected 为 "configuration"，Actual 为实际的根元素
+	RuleRootElement = "root-element"
+	// RuleUnknownSection 未知的配置节，Actual 为配置节名称
+	RuleUnknownSection = "unknown-section"
+	// RuleUnknownElement 配置节中未知的元素，Actual 为元素名称
+	RuleUnknownElement = "unknown-element"
+	// RuleUnknownAttribute 严格模式下未知的属性，Actual 为属性名称
+	RuleUnknownAttribute = "unknown-attribute"
+	// RuleUnknownCredentialKey 未知的凭证键，Key 为包源名称，Actual 为凭证键
+	RuleUnknownCredentialKey = "unknown-credential-key"
+	// RuleMissingAttribute 缺少必需的属性，Expected 为属性名称
+	RuleMissingAttribute = "missing-attribute"
+	// RuleInvalidAttributeValue 属性值格式不正确，Expected 为期望的类型，Actual 为实际的值
+	RuleInvalidAttributeValue = "invalid-attribute-value"
+	// RuleDuplicateKey 配置节中重复的 <add> key
+	RuleDuplicateKey = "duplicate-key"
+	// RuleDuplicateEntry 重复的凭证、凭证键、包源映射或包名模式
+	RuleDuplicateEntry = "duplicate-entry"
+	// RuleDeprecatedFeed 使用了已弃用的 nuget.org V2 源，Expected 为 V3 源的地址
+	RuleDeprecatedFeed = "deprecated-feed"
+	// RuleWhitespace 键名或值的首尾有空白字符，键名有空白时 Expected 为去除空白后的键名
+	RuleWhitespace = "whitespace"
+	// RuleNoPackageSources 没有定义任何包源
+	RuleNoPackageSources = "no-package-sources"
+)
+
+// Diagnostic 宽松解析过程中发现的一个问题
+//
+// Message 是供人阅读的英文描述；需要自行生成提示信息（例如本地化）时应使用 Rule、
+// Section、Key、Expected 和 Actual 等结构化字段，而不是解析 Message。
+type Diagnostic struct {
+	// Severity 严重程度
+	Severity Severity
+
+	// Code 问题的错误码，见 errors.Code
+	Code errors.Code
+
+	// Rule 发现问题的规则，见 RuleXMLSyntax 等常量
+	Rule string
+
+	// Message 问题描述
+	Message string
+
+	// Path 问题所在元素的路径，与 ParseResult.Positions 的 key 一致，无法定位到元素时为空
+	Path string
+
+	// Section 问题所在的配置节，如 "packageSources"，可能为空
+	Section string
+
+	// Key 问题涉及的 key，如包源名称或凭证所属的包源名称，可能为空
+	Key string
+
+	// Expected 期望的名称、类型或值，含义取决于 Rule，可能为空
+	Expected string
+
+	// Actual 实际的名称或值，含义取决于 Rule，可能为空。密码等敏感值不会出现在这里
+	Actual string
+
+	// Position 问题所在位置
+	Position Position
+
+	// Related 与问题相关的所有位置，如重复 key 的每一处定义，没有时为空
+	Related []Position
+
+	// Suggestion 未知的配置节、元素、属性或凭证键最可能对应的已知名称，没有时为空
+	Suggestion string
+}
+
+// String 格式化诊断信息，如 "3:5: warning: duplicate key "a" in <packageSources>"
+func (d Diagnostic) String() string {
+	if d.Position.Line > 0 {
+		return fmt.Sprintf("%d:%d: %s: %s", d.Position.Line, d.Position.Column, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+}
+
+// DiagnosticErrors 将 SeverityError 级别的诊断信息转换为 errors.ErrorList，没有时返回 nil
+//
+// 每个错误都是包装 errors.ErrInvalidConfigFormat 的 *errors.ParseError，带有诊断信息的位置，
+// errors.CodeOf 返回诊断信息的错误码。适合在宽松解析后一次报告所有错误。
+func DiagnosticErrors(diagnostics []Diagnostic) error {
+	var list errors.ErrorList
+	for _, d := range diagnostics {
+		if d.Severity != SeverityError {
+			continue
+		}
+		baseErr := errors.ErrInvalidConfigFormat
+		if d.Code != "" {
+			baseErr = errors.WithCode(d.Code, baseErr)
+		}
+		list.Add(d.parseError(baseErr))
+	}
+	return list.Err()
+}
+
+// ParseFromContentLenient 以宽松模式解析配置
+//
+// 与 ParseFromContent 在遇到第一个问题时即返回错误不同，宽松模式会跳过可恢复的问题
+// （未知元素、重复的 key、缺失或格式错误的属性、未加引号的属性值、缺失的结束标签等），
+// 返回尽可能完整的配置以及按位置排序的诊断信息。只有内容为空、包含不安全内容或无法恢复时才返回错误。
+// 设置了 StrictSchema 时还会报告未知属性，且未知内容的诊断信息均为 SeverityError。
+func (p *ConfigParser) ParseFromContentLenient(content []byte) (*types.NuGetConfig, []Diagnostic, error) {
+	if err := p.checkInputSize(int64(len(content))); err != nil {
+		return nil, nil, err
+	}
+
+	content, _, err := DecodeContent(content)
+	if err != nil {
+		return nil, nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil, errors.ErrEmptyConfigFile
+	}
+
+	// 不安全的内容不可恢复
+	if err := p.checkSecurity(content); err != nil {
+		return nil, nil, err
+	}
+
+	var diagnostics []Diagnostic
+	if err := checkXMLSyntax(content); err != nil {
+		diagnostics = append(diagnostics, syntaxDiagnostic(err))
+	}
+
+	checker, err := checkSchema(content, p.StrictSchema)
+	if err != nil {
+		return nil, diagnostics, errors.NewParseError(errors.ErrXMLParsing, 0, 0, fmt.Sprintf("unrecoverable XML error: %v", err))
+	}
+	diagnostics = append(diagnostics, checker.diagnostics...)
+
+	var config types.NuGetConfig
+	decoder := newDecoder(content)
+	decoder.Strict = false
+	if err := decoder.Decode(&config); err != nil {
+		return nil, diagnostics, errors.NewParseError(errors.ErrXMLParsing, 0, 0, fmt.Sprintf("xml decode error: %v", err))
+	}
+
+	if len(config.PackageSources.Add) == 0 && !config.PackageSources.IsCleared() && !p.AllowEmptyPackageSources {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Code:     errors.CodeMissingRequiredElement,
+			Rule:     RuleNoPackageSources,
+			Message:  "no package sources defined",
+			Section:  "packageSources",
+		})
+	}
+
+	sortDiagnostics(diagnostics)
+	return &config, diagnostics, nil
+}
+
+// sortDiagnostics 按位置排序诊断信息，无法定位的诊断信息排在最后
+func sortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		pi, pj := diagnostics[i].Position, diagnostics[j].Position
+		if (pi.Line == 0) != (pj.Line == 0) {
+			return pj.Line == 0
+		}
+		return pi.Offset < pj.Offset
+	})
+}
+
+// syntaxDiagnostic 将XML语法错误转换为诊断信息
+func syntaxDiagnostic(err error) Diagnostic {
+	d := Diagnostic{Severity: SeverityError, Code: errors.CodeInvalidConfigFormat, Rule: RuleXMLSyntax, Message: err.Error()}
+	if parseErr, ok := err.(*errors.ParseError); ok {
+		d.Message = "xml syntax error: " + parseErr.Context
+		d.Position = Position{Line: parseErr.Line, Column: parseErr.Position, Offset: parseErr.Offset}
+	}
+	return d
+}
+
+// parseError 将诊断信息转换为包装 baseErr 的 *errors.ParseError，保留位置和结构化字段
+func (d Diagnostic) parseError(baseErr error) *errors.ParseError {
+	parseErr := errors.NewParseError(baseErr, d.Position.Line, d.Position.Column, d.Message)
+	parseErr.Offset = d.Position.Offset
+	parseErr.Path = d.Path
+	parseErr.Section = d.Section
+	parseErr.Key = d.Key
+	parseErr.Expected = d.Expected
+	parseErr.Actual = d.Actual
+	parseErr.Rule = d.Rule
+	return parseErr
+}
@@ -0,0 +1,307 @@
+package parser
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestParseFromContentLenient(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key="a" value="https://a2.example.com" />
+    <add value="https://nokey.example.com" />
+    <add key="b" value="https://b.example.com" protocolVersion="three" />
+    <source key="c" value="https://c.example.com" />
+  </packageSources>
+  <unknownSection>
+    <add key="x" value="y" />
+  </unknownSection>
+  <packageSourceCredentials>
+    <a>
+      <add key="Username" value="user" />
+      <add key="Token" value="secret" />
+    </a>
+  </packageSourceCredentials>
+  <packageSourceMapping>
+    <packageSource key="a">
+      <package pattern="A.*" />
+      <package pattern="A.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+	config, diagnostics, err := NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+
+	if len(config.PackageSources.Add) != 4 {
+		t.Errorf("expected 4 package sources in best-effort config, got %d", len(config.PackageSources.Add))
+	}
+	if cred, ok := config.PackageSourceCredentials.Sources["a"]; !ok || len(cred.Add) != 2 {
+		t.Errorf("expected credentials for a to be parsed, got %+v", config.PackageSourceCredentials)
+	}
+
+	expected := []struct {
+		line    int
+		code    errors.Code
+		message string
+	}{
+		{4, errors.CodeDuplicateSourceKey, `duplicate key "a" in <packageSources> is defined 2 times; NuGet uses this definition`},
+		{5, errors.CodeDuplicateSourceKey, `duplicate key "a" in <packageSources> is ignored; NuGet uses the definition at line 4, column 5`},
+		{6, errors.CodeMissingAttribute, "<add> is missing the key attribute"},
+		{7, errors.CodeInvalidAttributeValue, `protocolVersion "three" of package source "b" is not a number`},
+		{8, errors.CodeUnknownElement, "unknown element <source> in <packageSources>"},
+		{10, errors.CodeUnknownElement, "unknown section <unknownSection>"},
+		{16, errors.CodeUnknownElement, `unknown credential key "Token" for source "a"`},
+		{22, errors.CodeDuplicateEntry, `duplicate package pattern "A.*"`},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Position.Line != want.line || got.Code != want.code || got.Message != want.message {
+			t.Errorf("diagnostic %d = %v (%s), want line %d %s %q", i, got, got.Code, want.line, want.code, want.message)
+		}
+	}
+	if diagnostics[2].Severity != SeverityError || diagnostics[0].Severity != SeverityWarning {
+		t.Errorf("unexpected severities: %v, %v", diagnostics[0].Severity, diagnostics[2].Severity)
+	}
+	if diagnostics[1].Path != "configuration/packageSources/add[1]" {
+		t.Errorf("unexpected path for duplicate key: %s", diagnostics[1].Path)
+	}
+
+	err = DiagnosticErrors(diagnostics)
+	list, ok := err.(errors.ErrorList)
+	if !ok || list.Len() != 1 || !stderrors.Is(err, errors.ErrInvalidConfigFormat) {
+		t.Fatalf("DiagnosticErrors() = %v, want one format error", err)
+	}
+	if errors.CodeOf(list[0]) != errors.CodeMissingAttribute {
+		t.Errorf("DiagnosticErrors() code = %q, want %q", errors.CodeOf(list[0]), errors.CodeMissingAttribute)
+	}
+	if line, column := list[0].(*errors.ParseError).Location(); line != 6 || column != 5 {
+		t.Errorf("DiagnosticErrors() location = %d:%d, want 6:5", line, column)
+	}
+	if DiagnosticErrors(diagnostics[:2]) != nil {
+		t.Error("DiagnosticErrors() with only warnings should be nil")
+	}
+}
+
+func TestParseFromContentLenientDuplicateKeys(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a1.example.com" />
+    <add key="b" value="https://b.example.com" />
+    <add key="a" value="https://a2.example.com" />
+    <add key="a" value="https://a3.example.com" />
+  </packageSources>
+  <config>
+    <add key="http_proxy" value="http://old" />
+    <remove key="http_proxy" />
+    <add key="http_proxy" value="http://new" />
+    <add key="globalPackagesFolder" value="/one" />
+    <clear />
+    <add key="globalPackagesFolder" value="/two" />
+  </config>
+  <disabledPackageSources>
+    <add key="b" value="true" />
+    <add key="b" value="false" />
+  </disabledPackageSources>
+</configuration>`
+
+	_, diagnostics, err := NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+
+	expected := []struct {
+		line, column int
+		path         string
+		message      string
+	}{
+		{3, 5, "configuration/packageSources/add", `duplicate key "a" in <packageSources> is defined 3 times; NuGet uses this definition`},
+		{5, 5, "configuration/packageSources/add[2]", `duplicate key "a" in <packageSources> is ignored; NuGet uses the definition at line 3, column 5`},
+		{6, 5, "configuration/packageSources/add[3]", `duplicate key "a" in <packageSources> is ignored; NuGet uses the definition at line 3, column 5`},
+		{17, 5, "configuration/disabledPackageSources/add", `duplicate key "b" in <disabledPackageSources> is defined 2 times; NuGet uses this definition`},
+		{18, 5, "configuration/disabledPackageSources/add[1]", `duplicate key "b" in <disabledPackageSources> is ignored; NuGet uses the definition at line 17, column 5`},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Position.Line != want.line || got.Position.Column != want.column || got.Path != want.path || got.Message != want.message {
+			t.Errorf("diagnostic %d = %v (%s), want %d:%d %q (%s)", i, got, got.Path, want.line, want.column, want.message, want.path)
+		}
+	}
+
+	if related := diagnostics[0].Related; len(related) != 3 || related[0].Line != 3 || related[1].Line != 5 || related[2].Line != 6 {
+		t.Errorf("unexpected related positions: %+v", related)
+	}
+}
+
+func TestParseFromContentLenientRecoversSyntaxErrors(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key=a value="https://a.example.com" />
+    <add key="b" value="https://b.example.com">
+  </packageSources>
+</configuration>`
+
+	if _, err := NewConfigParser().ParseFromString(content); err == nil {
+		t.Fatal("ParseFromString() expected error for malformed XML")
+	}
+
+	config, diagnostics, err := NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+
+	if len(config.PackageSources.Add) != 2 || config.PackageSources.Add[0].Key != "a" {
+		t.Errorf("unexpected package sources: %+v", config.PackageSources.Add)
+	}
+	if len(diagnostics) == 0 || diagnostics[0].Severity != SeverityError ||
+		!strings.HasPrefix(diagnostics[0].Message, "xml syntax error") || diagnostics[0].Position.Line != 3 {
+		t.Errorf("expected a syntax error diagnostic on line 3, got %v", diagnostics)
+	}
+}
+
+func TestParseFromContentLenientEmpty(t *testing.T) {
+	if _, _, err := NewConfigParser().ParseFromContentLenient([]byte("  \n")); err == nil {
+		t.Error("ParseFromContentLenient() expected error for empty content")
+	}
+}
+
+func TestParseFromContentLenientSuggestions(t *testing.T) {
+	content := `<configuration>
+  <packagesources>
+    <add key="a" value="https://a.example.com" />
+  </packagesources>
+  <packageSources>
+    <add key="a" value="https://a.example.com" protocolversion="3" />
+    <ad key="b" value="https://b.example.com" />
+  </packageSources>
+  <packageSourceCredentials>
+    <a>
+      <add key="ClearTextPasword" value="secret" />
+    </a>
+  </packageSourceCredentials>
+  <trustedSingers />
+</configuration>`
+
+	p := NewConfigParser()
+	p.StrictSchema = true
+	_, diagnostics, err := p.ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+
+	expected := []struct {
+		suggestion string
+		message    string
+	}{
+		{"packageSources", `unknown section <packagesources>; did you mean "packageSources"?`},
+		{"protocolVersion", `unknown attribute "protocolversion" on <add>; did you mean "protocolVersion"?`},
+		{"add", `unknown element <ad> in <packageSources>; did you mean "add"?`},
+		{"ClearTextPassword", `unknown credential key "ClearTextPasword" for source "a"; did you mean "ClearTextPassword"?`},
+		{"trustedSigners", `unknown section <trustedSingers>; did you mean "trustedSigners"?`},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		if got := diagnostics[i]; got.Suggestion != want.suggestion || got.Message != want.message {
+			t.Errorf("diagnostic %d = %q (suggestion %q), want %q", i, got.Message, got.Suggestion, want.message)
+		}
+	}
+
+	_, err = p.ParseFromContent([]byte(content))
+	if err == nil || !strings.Contains(err.Error(), `did you mean "packageSources"?`) {
+		t.Errorf("ParseFromContent() with StrictSchema error = %v, want a suggestion", err)
+	}
+}
+
+func TestDiagnosticStructuredFields(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" protocolVersion="three" />
+    <add key="a" value="https://a2.example.com" />
+    <add value="https://nokey.example.com" />
+  </packageSources>
+  <packageSourceCredentials>
+    <a>
+      <add key="Pasword" value="secret" />
+    </a>
+  </packageSourceCredentials>
+  <packageSourceMaping />
+</configuration>`
+
+	_, diagnostics, err := NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+
+	expected := []Diagnostic{
+		{Rule: RuleInvalidAttributeValue, Section: "packageSources", Key: "a", Expected: "number", Actual: "three"},
+		{Rule: RuleDuplicateKey, Section: "packageSources", Key: "a"},
+		{Rule: RuleDuplicateKey, Section: "packageSources", Key: "a"},
+		{Rule: RuleMissingAttribute, Section: "packageSources", Expected: "key"},
+		{Rule: RuleUnknownCredentialKey, Section: "packageSourceCredentials", Key: "a", Expected: "Password", Actual: "Pasword"},
+		{Rule: RuleUnknownSection, Section: "packageSourceMaping", Expected: "packageSourceMapping", Actual: "packageSourceMaping"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, want := range expected {
+		got := diagnostics[i]
+		if got.Rule != want.Rule || got.Section != want.Section || got.Key != want.Key || got.Expected != want.Expected || got.Actual != want.Actual {
+			t.Errorf("diagnostic %d = {Rule:%q Section:%q Key:%q Expected:%q Actual:%q}, want %+v",
+				i, got.Rule, got.Section, got.Key, got.Expected, got.Actual, want)
+		}
+	}
+
+	var parseErr *errors.ParseError
+	if !stderrors.As(DiagnosticErrors(diagnostics), &parseErr) || parseErr.Rule != RuleMissingAttribute ||
+		parseErr.Section != "packageSources" || parseErr.Expected != "key" || parseErr.Path != diagnostics[3].Path {
+		t.Errorf("DiagnosticErrors() = %+v, want the structured fields of the missing key diagnostic", parseErr)
+	}
+}
+
+func TestEncodedCredentialElementNames(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="My Feed" value="https://feed.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <My_x0020_Feed>
+      <add key="Username" value="ci" />
+      <add key="Pasword" value="secret" />
+    </My_x0020_Feed>
+  </packageSourceCredentials>
+</configuration>`
+
+	config, diagnostics, err := NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+	if _, exists := config.PackageSourceCredentials.Sources["My Feed"]; !exists {
+		t.Errorf("Sources = %v, want the decoded source name", config.PackageSourceCredentials.Sources)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Key != "My Feed" || !strings.Contains(diagnostics[0].Message, `"My Feed"`) {
+		t.Errorf("diagnostics = %v, want one diagnostic for source \"My Feed\"", diagnostics)
+	}
+
+	xmlString, err := NewConfigParser().SerializeToXML(config)
+	if err != nil {
+		t.Fatalf("SerializeToXML() error = %v", err)
+	}
+	if !strings.Contains(xmlString, "<My_x0020_Feed>") {
+		t.Errorf("SerializeToXML() should encode the source name:\n%s", xmlString)
+	}
+}
@@ -2,11 +2,15 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/xml"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/constants"
@@ -29,20 +33,29 @@ type Range struct {
 }
 
 // ElementPosition 记录XML元素的位置信息
+//
+// 元素路径由各级标签名以 "/" 连接而成，同一父元素下同名的第二个及之后的元素在名称后
+// 加上从1开始的索引，如 "configuration/packageSourceMapping/packageSource[1]/package[2]"。
 type ElementPosition struct {
-	TagName    string            // 标签名
-	Attributes map[string]string // 属性
-	Range      Range             // 元素范围
-	AttrRanges map[string]Range  // 属性值的范围
-	Content    string            // 元素内容
-	SelfClose  bool              // 是否自闭合标签
+	TagName        string            // 标签名
+	Attributes     map[string]string // 属性
+	Range          Range             // 元素范围
+	AttrRanges     map[string]Range  // 属性值的范围，不含引号
+	AttrNameRanges map[string]Range  // 属性名的范围
+	ContentRange   Range             // 开始标签与结束标签之间的范围，自闭合标签为零值
+	Content        string            // 元素直接包含的文本，去除首尾空白
+	SelfClose      bool              // 是否自闭合标签
 }
 
 // ParseResult 解析结果，包含配置和位置信息
 type ParseResult struct {
 	Config    *types.NuGetConfig          // 解析后的配置
 	Positions map[string]*ElementPosition // 元素位置信息，key为元素路径
-	Content   []byte                      // 原始内容
+	Content   []byte                      // 转换为UTF-8之后的原始内容
+	Encoding  Encoding                    // 原始内容的编码
+	// Diagnostics 解析成功时发现的非致命问题，如重复的 key、未知元素、已弃用的配置和
+	// 首尾带空白的值，按位置排序
+	Diagnostics []Diagnostic
 }
 
 // ConfigParser NuGet 配置文件解析器
@@ -51,6 +64,30 @@ type ConfigParser struct {
 	DefaultConfigSearchPaths []string
 	// TrackPositions 是否跟踪位置信息
 	TrackPositions bool
+	// AllowEmptyPackageSources 是否允许配置文件不定义任何包源，
+	// 用户级或机器级配置文件中常常只包含凭证或全局选项
+	AllowEmptyPackageSources bool
+	// StrictSchema 是否拒绝 NuGet 配置文件结构中未定义的配置节、元素和属性，
+	// 用于需要强制配置文件保持整洁的校验流程
+	StrictSchema bool
+	// DoctypePolicy 遇到 DOCTYPE 声明时的处理方式，默认拒绝
+	DoctypePolicy DoctypePolicy
+	// MaxDepth 元素嵌套深度上限，为0时使用 DefaultMaxDepth
+	MaxDepth int
+	// MaxInputSize 允许解析的最大输入字节数，为0时不限制。
+	// 长时间运行的服务可以设置该值，以免异常或恶意的大文件耗尽内存
+	MaxInputSize int64
+	// OutputEncoding SaveToFile 写入文件时使用的编码，默认为不带BOM的UTF-8
+	OutputEncoding Encoding
+	// ElementStyle 序列化时使用的元素风格，为 nil 时保持 encoding/xml 的默认输出
+	ElementStyle *ElementStyle
+	// SerializeOptions SerializeToXML 和 SaveToFile 使用的序列化选项，
+	// 为 nil 时使用 DefaultSerializeOptions
+	SerializeOptions *SerializeOptions
+	// OnWarning 不为 nil 时，解析成功后对发现的每个非致命问题按位置顺序调用一次，
+	// 包括 NuGet 能够容忍的重复 key、未知元素、已弃用的配置和首尾带空白的值。
+	// 解析失败时不会调用
+	OnWarning func(Diagnostic)
 }
 
 // NewConfigParser 创建一个新的配置解析器
@@ -70,102 +107,243 @@ func NewPositionAwareParser() *ConfigParser {
 }
 
 // ParseFromFile 从文件解析配置
+//
+// 返回的错误包装为 *errors.ConfigError，记录出错的文件路径和操作，
+// 可以用 errors.Is 和 errors.As 判断底层的错误。
 func (p *ConfigParser) ParseFromFile(filePath string) (*types.NuGetConfig, error) {
+	data, err := p.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := p.ParseFromContent(data)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpParse, filePath, err)
+	}
+	return config, nil
+}
+
+// ParseFromFileWithPositions 从文件解析配置并记录位置信息
+func (p *ConfigParser) ParseFromFileWithPositions(filePath string) (*ParseResult, error) {
+	data, err := p.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.ParseFromContentWithPositions(data)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpParse, filePath, err)
+	}
+	return result, nil
+}
+
+// readFile 读取配置文件，返回的错误包装为带有文件路径的 *errors.ConfigError
+func (p *ConfigParser) readFile(filePath string) ([]byte, error) {
+	data, err := p.readFileData(filePath)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpRead, filePath, err)
+	}
+	return data, nil
+}
+
+// readFileData 读取配置文件，超过 MaxInputSize 的文件不会被读入内存
+func (p *ConfigParser) readFileData(filePath string) ([]byte, error) {
 	// 检查文件是否存在
 	if !utils.FileExists(filePath) {
 		return nil, errors.ErrConfigFileNotFound
 	}
 
+	if info, err := os.Stat(filePath); err == nil {
+		if err := p.checkInputSize(info.Size()); err != nil {
+			return nil, err
+		}
+	}
+
 	// 读取文件内容
 	data, err := utils.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, errors.Errorf(errors.CodeIO, "failed to read config file: %w", err)
 	}
 
 	if len(data) == 0 {
 		return nil, errors.ErrEmptyConfigFile
 	}
 
-	return p.ParseFromContent(data)
+	return data, nil
 }
 
-// ParseFromFileWithPositions 从文件解析配置并记录位置信息
-func (p *ConfigParser) ParseFromFileWithPositions(filePath string) (*ParseResult, error) {
-	// 检查文件是否存在
-	if !utils.FileExists(filePath) {
-		return nil, errors.ErrConfigFileNotFound
+// ParseFromFS 从 fs.FS 中的文件解析配置
+//
+// path 为 fs.FS 使用的斜杠分隔的相对路径，可用于 embed.FS、fstest.MapFS 等不依赖操作系统文件系统的场景。
+func (p *ConfigParser) ParseFromFS(fsys fs.FS, path string) (*types.NuGetConfig, error) {
+	data, err := p.readFromFS(fsys, path)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpRead, path, err)
 	}
 
-	// 读取文件内容
-	data, err := utils.ReadFile(filePath)
+	config, err := p.ParseFromContent(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, errors.WrapConfigError(errors.OpParse, path, err)
+	}
+	return config, nil
+}
+
+// ParseFromFSWithPositions 从 fs.FS 中的文件解析配置并记录位置信息
+func (p *ConfigParser) ParseFromFSWithPositions(fsys fs.FS, path string) (*ParseResult, error) {
+	data, err := p.readFromFS(fsys, path)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpRead, path, err)
+	}
+
+	result, err := p.ParseFromContentWithPositions(data)
+	if err != nil {
+		return nil, errors.WrapConfigError(errors.OpParse, path, err)
+	}
+	return result, nil
+}
+
+// readFromFS 读取 fs.FS 中的配置文件
+func (p *ConfigParser) readFromFS(fsys fs.FS, path string) ([]byte, error) {
+	if info, err := fs.Stat(fsys, path); err == nil {
+		if err := p.checkInputSize(info.Size()); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil, errors.ErrConfigFileNotFound
+		}
+		return nil, errors.Errorf(errors.CodeIO, "failed to read config file: %w", err)
 	}
 
 	if len(data) == 0 {
 		return nil, errors.ErrEmptyConfigFile
 	}
 
-	return p.ParseFromContentWithPositions(data)
+	return data, nil
 }
 
 // ParseFromContent 从内容解析配置
+//
+// 带BOM的UTF-8以及UTF-16编码的内容会先转换为UTF-8再解析。
 func (p *ConfigParser) ParseFromContent(content []byte) (*types.NuGetConfig, error) {
-	// 验证内容是否为有效的XML
-	if !utils.IsValidXML(string(content)) {
-		return nil, errors.ErrInvalidConfigFormat
+	parsed, err := p.parseContent(content, p.OnWarning != nil)
+	if err != nil {
+		return nil, err
 	}
+	p.reportWarnings(parsed.diagnostics)
+	return parsed.config, nil
+}
 
-	// 解析XML
-	var config types.NuGetConfig
-	err := xml.Unmarshal(content, &config)
+// ParseFromContentWithPositions 从内容解析配置并记录位置信息
+//
+// 位置信息基于转换为UTF-8之后的内容，即 ParseResult.Content，原始编码记录在 ParseResult.Encoding 中。
+// 解析过程中发现的非致命问题记录在 ParseResult.Diagnostics 中。
+func (p *ConfigParser) ParseFromContentWithPositions(content []byte) (*ParseResult, error) {
+	parsed, err := p.parseContent(content, true)
 	if err != nil {
-		return nil, errors.NewParseError(errors.ErrXMLParsing, 0, 0, fmt.Sprintf("xml.Unmarshal error: %v", err))
+		return nil, err
 	}
 
-	// 验证必需的字段
-	if len(config.PackageSources.Add) == 0 {
-		// 如果没有定义包源但有 clear 属性为 true，这可能是正常的情况
-		if !config.PackageSources.Clear {
-			return nil, errors.NewParseError(errors.ErrMissingRequiredElement, 0, 0, "no package sources defined")
-		}
+	// 跟踪位置信息
+	positions, err := p.trackPositions(parsed.content)
+	if err != nil {
+		return nil, errors.Errorf(errors.CodeXMLParsing, "failed to track positions: %w", err)
 	}
 
-	return &config, nil
+	p.reportWarnings(parsed.diagnostics)
+	return &ParseResult{
+		Config:      parsed.config,
+		Positions:   positions,
+		Content:     parsed.content,
+		Encoding:    parsed.encoding,
+		Diagnostics: parsed.diagnostics,
+	}, nil
 }
 
-// ParseFromContentWithPositions 从内容解析配置并记录位置信息
-func (p *ConfigParser) ParseFromContentWithPositions(content []byte) (*ParseResult, error) {
+// parsedContent parseContent 的结果
+type parsedContent struct {
+	config *types.NuGetConfig
+	// content 转换为UTF-8的内容
+	content []byte
+	// encoding 内容的原始编码
+	encoding Encoding
+	// diagnostics 非致命问题，只在要求收集时才有
+	diagnostics []Diagnostic
+}
+
+// parseContent 检查并解析内容
+//
+// withDiagnostics 为 true 时同时收集非致命问题；设置了 StrictSchema 时检查结构的同时也会收集。
+func (p *ConfigParser) parseContent(content []byte, withDiagnostics bool) (*parsedContent, error) {
+	if err := p.checkInputSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+
+	content, enc, err := DecodeContent(content)
+	if err != nil {
+		return nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+
+	if err := p.checkSecurity(content); err != nil {
+		return nil, err
+	}
+
 	// 验证内容是否为有效的XML
-	if !utils.IsValidXML(string(content)) {
-		return nil, errors.ErrInvalidConfigFormat
+	if err := checkXMLSyntax(content); err != nil {
+		return nil, err
 	}
 
-	// 先进行标准解析
+	var diagnostics []Diagnostic
+	if p.StrictSchema || withDiagnostics {
+		checker, err := checkSchema(content, p.StrictSchema)
+		if err != nil {
+			return nil, errors.NewParseError(errors.ErrXMLParsing, 0, 0, err.Error())
+		}
+		if p.StrictSchema {
+			if err := checker.strictError(); err != nil {
+				return nil, err
+			}
+		}
+		diagnostics = checker.diagnostics
+	}
+
+	// 解析XML
 	var config types.NuGetConfig
-	err := xml.Unmarshal(content, &config)
-	if err != nil {
-		return nil, errors.NewParseError(errors.ErrXMLParsing, 0, 0, fmt.Sprintf("xml.Unmarshal error: %v", err))
+	if err := newDecoder(content).Decode(&config); err != nil {
+		return nil, errors.NewParseError(errors.ErrXMLParsing, 0, 0, fmt.Sprintf("xml decode error: %v", err))
 	}
 
 	// 验证必需的字段
-	if len(config.PackageSources.Add) == 0 {
-		if !config.PackageSources.Clear {
-			return nil, errors.NewParseError(errors.ErrMissingRequiredElement, 0, 0, "no package sources defined")
+	if len(config.PackageSources.Add) == 0 && !p.AllowEmptyPackageSources {
+		// 如果没有定义包源但有 clear 属性为 true，这可能是正常的情况
+		if !config.PackageSources.IsCleared() {
+			return nil, noPackageSourcesError()
 		}
 	}
 
-	// 跟踪位置信息
-	positions, err := p.trackPositions(content)
-	if err != nil {
-		return nil, fmt.Errorf("failed to track positions: %w", err)
-	}
+	sortDiagnostics(diagnostics)
+	return &parsedContent{config: &config, content: content, encoding: enc, diagnostics: diagnostics}, nil
+}
 
-	return &ParseResult{
-		Config:    &config,
-		Positions: positions,
-		Content:   content,
-	}, nil
+// noPackageSourcesError 创建没有定义任何包源的错误
+func noPackageSourcesError() error {
+	parseErr := errors.NewParseError(errors.ErrMissingRequiredElement, 0, 0, "no package sources defined")
+	parseErr.Section = "packageSources"
+	parseErr.Rule = RuleNoPackageSources
+	return parseErr
+}
+
+// reportWarnings 将非致命问题逐个传给 OnWarning
+func (p *ConfigParser) reportWarnings(diagnostics []Diagnostic) {
+	if p.OnWarning == nil {
+		return
+	}
+	for _, d := range diagnostics {
+		p.OnWarning(d)
+	}
 }
 
 // FindAndParseConfig 查找并解析配置文件
@@ -191,10 +369,15 @@ func (p *ConfigParser) FindAndParseConfig() (*types.NuGetConfig, string, error)
 
 // ParseFromReader 从io.Reader解析配置
 func (p *ConfigParser) ParseFromReader(reader io.Reader) (*types.NuGetConfig, error) {
+	// 设置了大小上限时最多多读一个字节，用于判断是否超限
+	if p.MaxInputSize > 0 {
+		reader = io.LimitReader(reader, p.MaxInputSize+1)
+	}
+
 	// 读取内容
 	content, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read from reader: %w", err)
+		return nil, errors.Errorf(errors.CodeIO, "failed to read from reader: %w", err)
 	}
 
 	return p.ParseFromContent(content)
@@ -205,15 +388,19 @@ func (p *ConfigParser) ParseFromString(content string) (*types.NuGetConfig, erro
 	return p.ParseFromContent([]byte(content))
 }
 
-// SerializeToXML 将配置序列化为XML字符串
-func (p *ConfigParser) SerializeToXML(config *types.NuGetConfig) (string, error) {
-	data, err := xml.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal config to XML: %w", err)
+// checkInputSize 检查输入大小是否超过 MaxInputSize
+func (p *ConfigParser) checkInputSize(size int64) error {
+	if p.MaxInputSize > 0 && size > p.MaxInputSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", errors.ErrInputTooLarge, size, p.MaxInputSize)
 	}
+	return nil
+}
 
-	xmlHeader := `<?xml version="1.0" encoding="utf-8"?>` + "\n"
-	return xmlHeader + string(data), nil
+// SerializeToXML 将配置序列化为XML字符串
+//
+// 输出格式由 SerializeOptions 决定，设置了 ElementStyle 时，空元素和属性引号按该风格输出。
+func (p *ConfigParser) SerializeToXML(config *types.NuGetConfig) (string, error) {
+	return p.SerializeToXMLWithOptions(config, p.serializeOptions())
 }
 
 // SaveToFile 将配置保存到文件
@@ -223,169 +410,244 @@ func (p *ConfigParser) SaveToFile(config *types.NuGetConfig, filePath string) er
 		return err
 	}
 
-	return utils.WriteToFile(filePath, []byte(xmlString))
+	return errors.WithCode(errors.CodeIO, utils.WriteToFile(filePath, EncodeContent([]byte(xmlString), p.OutputEncoding)))
+}
+
+// checkXMLSyntax 检查内容是否为格式正确的XML
+//
+// 存在语法错误时返回的 *errors.ParseError 包装 errors.ErrInvalidConfigFormat，
+// 并带有出错位置的行号、列号和字节偏移量。
+func checkXMLSyntax(content []byte) error {
+	if len(content) == 0 {
+		return errors.ErrInvalidConfigFormat
+	}
+
+	decoder := newDecoder(content)
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			pos := newLineIndex(content).position(int(decoder.InputOffset()))
+			message := err.Error()
+			if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+				message = syntaxErr.Msg
+			}
+			parseErr := errors.NewParseError(errors.ErrInvalidConfigFormat, pos.Line, pos.Column, message)
+			parseErr.Offset = pos.Offset
+			parseErr.Rule = RuleXMLSyntax
+			return parseErr
+		}
+	}
 }
 
 // trackPositions 跟踪XML中所有元素的位置
+//
+// 基于 encoding/xml 的 Decoder 逐个读取原始标记，并用 InputOffset 记录每个标记在内容中的
+// 字节范围，因此属性值中的 ">"、CDATA、包含标签的注释以及跨行属性都能正确处理。
 func (p *ConfigParser) trackPositions(content []byte) (map[string]*ElementPosition, error) {
 	positions := make(map[string]*ElementPosition)
-	contentStr := string(content)
-
-	var elementStack []string
-	line := 1
-	column := 1
-
-	for i := 0; i < len(contentStr); i++ {
-		if contentStr[i] == '\n' {
-			line++
-			column = 1
-			continue
+	lines := newLineIndex(content)
+
+	decoder := newDecoder(content)
+	var paths pathIndexer
+	var openElements []*ElementPosition
+	var texts []*strings.Builder
+
+	for {
+		tokenStart := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
 		}
+		tokenEnd := int(decoder.InputOffset())
 
-		if contentStr[i] == '<' {
-			// 查找标签结束
-			tagEnd := p.findTagEnd(contentStr, i)
-			if tagEnd == -1 {
-				return nil, fmt.Errorf("未找到标签结束: 位置 %d", i)
-			}
+		switch t := token.(type) {
+		case xml.StartElement:
+			tagName := qualifiedName(t.Name)
+			elementPath := paths.push(tagName)
 
-			tagContent := contentStr[i+1 : tagEnd]
+			attributes := make(map[string]string, len(t.Attr))
+			for _, a := range t.Attr {
+				attributes[qualifiedName(a.Name)] = a.Value
+			}
 
-			// 跳过注释和声明
-			if strings.HasPrefix(tagContent, "!") || strings.HasPrefix(tagContent, "?") {
-				p.advancePosition(contentStr, i, tagEnd+1, &line, &column)
-				i = tagEnd
-				continue
+			tag := content[tokenStart:tokenEnd]
+			nameRanges, valueRanges := attributeRanges(tag, tokenStart, lines)
+			elemPos := &ElementPosition{
+				TagName:        tagName,
+				Attributes:     attributes,
+				AttrRanges:     valueRanges,
+				AttrNameRanges: nameRanges,
+				Range: Range{
+					Start: lines.position(tokenStart),
+					End:   lines.position(tokenEnd),
+				},
+				SelfClose: bytes.HasSuffix(tag, []byte("/>")),
+			}
+			if !elemPos.SelfClose {
+				elemPos.ContentRange.Start = elemPos.Range.End
 			}
+			positions[elementPath] = elemPos
+			openElements = append(openElements, elemPos)
+			texts = append(texts, &strings.Builder{})
 
-			if strings.HasPrefix(tagContent, "/") {
-				// 结束标签
-				if len(elementStack) > 0 {
-					elementPath := strings.Join(elementStack, "/")
-					if elemPos, exists := positions[elementPath]; exists {
-						elemPos.Range.End = Position{
-							Line:   line,
-							Column: column,
-							Offset: tagEnd + 1,
-						}
-					}
-					elementStack = elementStack[:len(elementStack)-1]
-				}
-			} else {
-				// 开始标签
-				startPos := Position{
-					Line:   line,
-					Column: column,
-					Offset: i,
-				}
-
-				tagName, attributes, attrRanges, selfClose := p.parseTagWithRanges(tagContent, i+1)
-				elementStack = append(elementStack, tagName)
-				elementPath := strings.Join(elementStack, "/")
-
-				// 为重复元素添加索引
-				finalPath := elementPath
-				if _, exists := positions[elementPath]; exists {
-					// 如果路径已存在，添加索引
-					index := 1
-					for {
-						indexedPath := fmt.Sprintf("%s[%d]", elementPath, index)
-						if _, exists := positions[indexedPath]; !exists {
-							finalPath = indexedPath
-							break
-						}
-						index++
-					}
-				}
-
-				positions[finalPath] = &ElementPosition{
-					TagName:    tagName,
-					Attributes: attributes,
-					AttrRanges: attrRanges,
-					Range: Range{
-						Start: startPos,
-						End:   Position{Line: line, Column: column, Offset: tagEnd + 1},
-					},
-					SelfClose: selfClose,
-				}
-
-				if selfClose {
-					elementStack = elementStack[:len(elementStack)-1]
-				}
+		case xml.CharData:
+			if len(texts) > 0 {
+				texts[len(texts)-1].Write(t)
 			}
 
-			p.advancePosition(contentStr, i, tagEnd+1, &line, &column)
-			i = tagEnd
-		} else {
-			column++
+		case xml.EndElement:
+			if len(openElements) == 0 {
+				return nil, fmt.Errorf("多余的结束标签: %s", qualifiedName(t.Name))
+			}
+			// 自闭合标签的结束标记由 Decoder 合成，其范围已在开始标签处记录
+			elemPos := openElements[len(openElements)-1]
+			if !elemPos.SelfClose {
+				elemPos.Range.End = lines.position(tokenEnd)
+				elemPos.ContentRange.End = lines.position(tokenStart)
+			}
+			elemPos.Content = strings.TrimSpace(texts[len(texts)-1].String())
+			paths.pop()
+			openElements = openElements[:len(openElements)-1]
+			texts = texts[:len(texts)-1]
 		}
 	}
 
 	return positions, nil
 }
 
-// findTagEnd 查找标签结束位置
-func (p *ConfigParser) findTagEnd(content string, start int) int {
-	for i := start + 1; i < len(content); i++ {
-		if content[i] == '>' {
-			return i
-		}
+// pathIndexer 在遍历元素时生成元素路径，同一父元素下重复的同名元素按出现顺序编号
+type pathIndexer struct {
+	paths  []string
+	counts []map[string]int
+	root   map[string]int
+}
+
+// push 进入名为 name 的元素并返回其路径
+func (pi *pathIndexer) push(name string) string {
+	parent, counts := "", pi.root
+	if len(pi.paths) > 0 {
+		parent = pi.paths[len(pi.paths)-1] + "/"
+		counts = pi.counts[len(pi.counts)-1]
+	} else if counts == nil {
+		pi.root = make(map[string]int)
+		counts = pi.root
+	}
+
+	path := parent + name
+	if index := counts[name]; index > 0 {
+		path = fmt.Sprintf("%s[%d]", path, index)
 	}
-	return -1
+	counts[name]++
+
+	pi.paths = append(pi.paths, path)
+	pi.counts = append(pi.counts, make(map[string]int))
+	return path
 }
 
-// advancePosition 更新位置信息
-func (p *ConfigParser) advancePosition(content string, start, end int, line, column *int) {
-	for i := start; i < end && i < len(content); i++ {
-		if content[i] == '\n' {
-			*line++
-			*column = 1
-		} else {
-			*column++
+// pop 离开当前元素
+func (pi *pathIndexer) pop() {
+	if len(pi.paths) == 0 {
+		return
+	}
+	pi.paths = pi.paths[:len(pi.paths)-1]
+	pi.counts = pi.counts[:len(pi.counts)-1]
+}
+
+// qualifiedName 返回带前缀的原始名称
+func qualifiedName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// attributeRanges 扫描原始开始标签，记录每个属性名和属性值（不包括引号）的范围
+//
+// tag 是 Decoder 已确认格式正确的开始标签，属性值总是由成对的引号界定。
+func attributeRanges(tag []byte, baseOffset int, lines lineIndex) (names, values map[string]Range) {
+	names = make(map[string]Range)
+	values = make(map[string]Range)
+
+	// 跳过 "<" 和标签名
+	i := 1
+	for i < len(tag) && !isXMLSpace(tag[i]) && tag[i] != '/' && tag[i] != '>' {
+		i++
+	}
+
+	for i < len(tag) {
+		for i < len(tag) && isXMLSpace(tag[i]) {
+			i++
 		}
+		if i >= len(tag) || tag[i] == '/' || tag[i] == '>' {
+			break
+		}
+
+		nameStart := i
+		for i < len(tag) && tag[i] != '=' && !isXMLSpace(tag[i]) {
+			i++
+		}
+		name := string(tag[nameStart:i])
+
+		for i < len(tag) && (isXMLSpace(tag[i]) || tag[i] == '=') {
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+
+		quote := tag[i]
+		valueStart := i + 1
+		valueEnd := bytes.IndexByte(tag[valueStart:], quote)
+		if valueEnd < 0 {
+			break
+		}
+		valueEnd += valueStart
+
+		names[name] = Range{
+			Start: lines.position(baseOffset + nameStart),
+			End:   lines.position(baseOffset + nameStart + len(name)),
+		}
+		values[name] = Range{
+			Start: lines.position(baseOffset + valueStart),
+			End:   lines.position(baseOffset + valueEnd),
+		}
+		i = valueEnd + 1
 	}
+
+	return names, values
 }
 
-// parseTagWithRanges 解析标签内容并记录属性范围
-func (p *ConfigParser) parseTagWithRanges(tagContent string, baseOffset int) (string, map[string]string, map[string]Range, bool) {
-	tagContent = strings.TrimSpace(tagContent)
-	selfClose := strings.HasSuffix(tagContent, "/")
-	if selfClose {
-		tagContent = strings.TrimSuffix(tagContent, "/")
-		tagContent = strings.TrimSpace(tagContent)
-	}
-
-	parts := strings.Fields(tagContent)
-	if len(parts) == 0 {
-		return "", make(map[string]string), make(map[string]Range), selfClose
-	}
-
-	tagName := parts[0]
-	attributes := make(map[string]string)
-	attrRanges := make(map[string]Range)
-
-	// 解析属性并记录位置
-	attrStr := strings.Join(parts[1:], " ")
-	if attrStr != "" {
-		// 查找属性值的位置
-		re := regexp.MustCompile(`(\w+)="([^"]*)"`)
-		matches := re.FindAllStringSubmatchIndex(attrStr, -1)
-		for _, match := range matches {
-			if len(match) >= 6 {
-				attrName := attrStr[match[2]:match[3]]
-				attrValue := attrStr[match[4]:match[5]]
-				attributes[attrName] = attrValue
-
-				// 记录属性值的范围（不包括引号）
-				valueStart := baseOffset + strings.Index(tagContent, attrStr) + match[4]
-				valueEnd := baseOffset + strings.Index(tagContent, attrStr) + match[5]
-				attrRanges[attrName] = Range{
-					Start: Position{Offset: valueStart},
-					End:   Position{Offset: valueEnd},
-				}
-			}
+// isXMLSpace 判断字节是否为XML空白字符
+func isXMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// lineIndex 记录每一行起始的字节偏移量，用于将偏移量换算为行列号
+type lineIndex []int
+
+// newLineIndex 为内容建立行索引
+func newLineIndex(content []byte) lineIndex {
+	lines := lineIndex{0}
+	for i, b := range content {
+		if b == '\n' {
+			lines = append(lines, i+1)
 		}
 	}
+	return lines
+}
 
-	return tagName, attributes, attrRanges, selfClose
+// position 将字节偏移量换算为行列号
+func (l lineIndex) position(offset int) Position {
+	line := sort.Search(len(l), func(i int) bool { return l[i] > offset })
+	return Position{
+		Line:   line,
+		Column: offset - l[line-1] + 1,
+		Offset: offset,
+	}
 }
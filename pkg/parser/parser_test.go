@@ -2,14 +2,17 @@ package parser
 
 import (
 	"bytes"
+	stderrors "errors"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/scagogogo/nuget-config-parser/pkg/errors"
 	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
 )
 
 func TestNewConfigParser(t *testing.T) {
@@ -66,6 +69,10 @@ func TestParseFromFile(t *testing.T) {
 		if !errors.IsFormatError(err) && !errors.IsParseError(err) {
 			t.Errorf("Expected format or parse error, got %v", err)
 		}
+		var configErr *errors.ConfigError
+		if !stderrors.As(err, &configErr) || configErr.Op != errors.OpParse || configErr.Path != invalidFile {
+			t.Errorf("Expected ConfigError for parsing %s, got %#v", invalidFile, err)
+		}
 	})
 
 	// 测试空文件
@@ -74,7 +81,7 @@ func TestParseFromFile(t *testing.T) {
 		if err == nil {
 			t.Fatal("ParseFromFile() expected error for empty file")
 		}
-		if err != errors.ErrEmptyConfigFile {
+		if !stderrors.Is(err, errors.ErrEmptyConfigFile) {
 			t.Errorf("Expected empty file error, got %v", err)
 		}
 	})
@@ -85,9 +92,12 @@ func TestParseFromFile(t *testing.T) {
 		if err == nil {
 			t.Fatal("ParseFromFile() expected error for non-existent file")
 		}
-		if err != errors.ErrConfigFileNotFound {
+		if !stderrors.Is(err, errors.ErrConfigFileNotFound) {
 			t.Errorf("Expected file not found error, got %v", err)
 		}
+		if path := errors.ConfigErrorPath(err); path != nonExistentFile {
+			t.Errorf("ConfigErrorPath() = %q, want %q", path, nonExistentFile)
+		}
 	})
 }
 
@@ -393,3 +403,369 @@ type errorReader struct {
 func (r *errorReader) Read(p []byte) (n int, err error) {
 	return 0, r.err
 }
+
+func TestTrackPositionsRepeatedElements(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+  <packageSourceMapping>
+    <packageSource key="a">
+      <package pattern="A.*" />
+    </packageSource>
+    <packageSource key="b">
+      <package pattern="B.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+	result, err := NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+	}
+
+	// 重复元素的结束位置应对应各自的结束标签
+	for path, key := range map[string]string{
+		"configuration/packageSourceMapping/packageSource":    "a",
+		"configuration/packageSourceMapping/packageSource[1]": "b",
+	} {
+		elemPos, exists := result.Positions[path]
+		if !exists {
+			t.Fatalf("position for %s not found", path)
+		}
+		text := content[elemPos.Range.Start.Offset:elemPos.Range.End.Offset]
+		expectedPrefix := `<packageSource key="` + key + `">`
+		if !strings.HasPrefix(text, expectedPrefix) || !strings.HasSuffix(text, "</packageSource>") ||
+			strings.Count(text, "<packageSource ") != 1 {
+			t.Errorf("unexpected range for %s: %q", path, text)
+		}
+	}
+}
+
+func TestSerializeToXMLElementStyle(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		style    *ElementStyle
+		expected string
+	}{
+		{
+			name:     "默认输出成对标签",
+			style:    nil,
+			expected: `<add key="nuget.org" value="https://api.nuget.org/v3/index.json"></add>`,
+		},
+		{
+			name:     "自闭合且斜杠前有空格",
+			style:    &ElementStyle{SelfClosing: true, SpaceBeforeSlash: true},
+			expected: `<add key="nuget.org" value="https://api.nuget.org/v3/index.json" />`,
+		},
+		{
+			name:     "自闭合单引号",
+			style:    &ElementStyle{SelfClosing: true, Quote: '\''},
+			expected: `<add key='nuget.org' value='https://api.nuget.org/v3/index.json'/>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewConfigParser()
+			p.ElementStyle = tt.style
+
+			xmlString, err := p.SerializeToXML(config)
+			if err != nil {
+				t.Fatalf("SerializeToXML() error = %v", err)
+			}
+			if !strings.Contains(xmlString, tt.expected) {
+				t.Errorf("SerializeToXML() = %s, want element %s", xmlString, tt.expected)
+			}
+			if !strings.HasPrefix(xmlString, `<?xml version="1.0" encoding="utf-8"?>`) {
+				t.Errorf("SerializeToXML() should keep the XML declaration unchanged")
+			}
+
+			if _, err := p.ParseFromString(xmlString); err != nil {
+				t.Errorf("Failed to parse serialized XML: %v", err)
+			}
+		})
+	}
+}
+
+func TestTrackPositionsTrickyMarkup(t *testing.T) {
+	content := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <!-- <packageSources><add key="commented" value="x" /></packageSources> -->
+  <packageSources>
+    <add key="a>b" value="https://a.example.com/?x=1&amp;y=2" />
+    <add
+        key="multi"
+        value='https://multi.example.com'/>
+  </packageSources>
+  <config>
+    <add key="note" value="v"><![CDATA[<not-an-element>]]></add>
+  </config>
+</configuration>`
+
+	result, err := NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+	}
+
+	if _, exists := result.Positions["configuration/packageSources[1]"]; exists {
+		t.Errorf("elements inside comments should not be tracked")
+	}
+	if _, exists := result.Positions["configuration/config/add/not-an-element"]; exists {
+		t.Errorf("CDATA content should not be tracked as an element")
+	}
+
+	first := result.Positions["configuration/packageSources/add"]
+	if first == nil {
+		t.Fatalf("position for first add not found")
+	}
+	if got := first.Attributes["key"]; got != "a>b" {
+		t.Errorf("key attribute = %q, want %q", got, "a>b")
+	}
+	valueRange := first.AttrRanges["value"]
+	if got := content[valueRange.Start.Offset:valueRange.End.Offset]; got != "https://a.example.com/?x=1&amp;y=2" {
+		t.Errorf("value range covers %q", got)
+	}
+	if !first.SelfClose || !strings.HasSuffix(content[:first.Range.End.Offset], `y=2" />`) {
+		t.Errorf("unexpected range for first add: %q", content[first.Range.Start.Offset:first.Range.End.Offset])
+	}
+
+	multi := result.Positions["configuration/packageSources/add[1]"]
+	if multi == nil {
+		t.Fatalf("position for multi-line add not found")
+	}
+	if multi.Range.Start.Line != 6 || multi.Range.End.Line != 8 {
+		t.Errorf("multi-line add spans lines %d-%d, want 6-8", multi.Range.Start.Line, multi.Range.End.Line)
+	}
+	keyRange := multi.AttrRanges["key"]
+	if got := content[keyRange.Start.Offset:keyRange.End.Offset]; got != "multi" {
+		t.Errorf("key range covers %q", got)
+	}
+	if keyRange.Start.Line != 7 || keyRange.Start.Column != 14 {
+		t.Errorf("key range starts at %d:%d, want 7:14", keyRange.Start.Line, keyRange.Start.Column)
+	}
+	valueRange = multi.AttrRanges["value"]
+	if got := content[valueRange.Start.Offset:valueRange.End.Offset]; got != "https://multi.example.com" {
+		t.Errorf("single-quoted value range covers %q", got)
+	}
+
+	note := result.Positions["configuration/config/add"]
+	if note == nil {
+		t.Fatalf("position for config add not found")
+	}
+	if note.SelfClose || !strings.HasSuffix(content[note.Range.Start.Offset:note.Range.End.Offset], "]]></add>") {
+		t.Errorf("unexpected range for config add: %q", content[note.Range.Start.Offset:note.Range.End.Offset])
+	}
+}
+
+func TestTrackPositionsAllSections(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+    <add key="b" value="https://b.example.com" />
+  </packageSources>
+  <packageSourceCredentials>
+    <a>
+      <add key="Username" value="user" />
+      <add key="ClearTextPassword" value="secret" />
+    </a>
+  </packageSourceCredentials>
+  <disabledPackageSources>
+    <add key="b" value="true" />
+  </disabledPackageSources>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+  </config>
+  <packageSourceMapping>
+    <packageSource key="a">
+      <package pattern="A.*" />
+    </packageSource>
+    <packageSource key="b">
+      <package pattern="B.*" />
+      <package pattern="C.*" />
+    </packageSource>
+  </packageSourceMapping>
+  <notes>  some text  </notes>
+</configuration>`
+
+	result, err := NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"configuration/packageSources/add[1]":                            `key="b"`,
+		"configuration/packageSourceCredentials/a/add":                   `key="Username"`,
+		"configuration/packageSourceCredentials/a/add[1]":                `key="ClearTextPassword"`,
+		"configuration/disabledPackageSources/add":                       `key="b" value="true"`,
+		"configuration/config/add":                                       `key="globalPackagesFolder"`,
+		"configuration/packageSourceMapping/packageSource/package":       `pattern="A.*"`,
+		"configuration/packageSourceMapping/packageSource[1]/package":    `pattern="B.*"`,
+		"configuration/packageSourceMapping/packageSource[1]/package[1]": `pattern="C.*"`,
+	} {
+		elemPos, exists := result.Positions[path]
+		if !exists {
+			t.Errorf("position for %s not found", path)
+			continue
+		}
+		if text := content[elemPos.Range.Start.Offset:elemPos.Range.End.Offset]; !strings.Contains(text, want) {
+			t.Errorf("unexpected range for %s: %q", path, text)
+		}
+	}
+
+	password := result.Positions["configuration/packageSourceCredentials/a/add[1]"]
+	nameRange := password.AttrNameRanges["value"]
+	if got := content[nameRange.Start.Offset:nameRange.End.Offset]; got != "value" {
+		t.Errorf("value name range covers %q", got)
+	}
+	if nameRange.Start.Line != 9 || nameRange.Start.Column != 36 {
+		t.Errorf("value name range starts at %d:%d, want 9:36", nameRange.Start.Line, nameRange.Start.Column)
+	}
+	if password.ContentRange != (Range{}) {
+		t.Errorf("self-closing element should have an empty content range, got %+v", password.ContentRange)
+	}
+
+	notes := result.Positions["configuration/notes"]
+	if notes == nil {
+		t.Fatalf("position for notes not found")
+	}
+	if got := content[notes.ContentRange.Start.Offset:notes.ContentRange.End.Offset]; got != "  some text  " {
+		t.Errorf("content range covers %q", got)
+	}
+	if notes.Content != "some text" {
+		t.Errorf("Content = %q, want %q", notes.Content, "some text")
+	}
+
+	credentials := result.Positions["configuration/packageSourceCredentials/a"]
+	inner := content[credentials.ContentRange.Start.Offset:credentials.ContentRange.End.Offset]
+	if !strings.HasPrefix(inner, "\n      <add") || !strings.HasSuffix(inner, "/>\n    ") {
+		t.Errorf("content range of credentials covers %q", inner)
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		column  int
+	}{
+		{
+			name: "mismatched end tag",
+			content: "<configuration>\n" +
+				"  <packageSources>\n" +
+				"    <add key=\"a\" value=\"https://a.example.com\" />\n" +
+				"  </packageSource>\n" +
+				"</configuration>",
+			line:   4,
+			column: 19,
+		},
+		{
+			name: "unquoted attribute",
+			content: "<configuration>\n" +
+				"  <packageSources>\n" +
+				"    <add key=a value=\"https://a.example.com\" />\n",
+			line:   3,
+			column: 15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigParser().ParseFromString(tt.content)
+			if err == nil {
+				t.Fatal("ParseFromString() expected error")
+			}
+			if !errors.IsFormatError(err) {
+				t.Errorf("Expected format error, got %v", err)
+			}
+
+			var parseErr *errors.ParseError
+			if !stderrors.As(err, &parseErr) {
+				t.Fatalf("Expected *errors.ParseError, got %T", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Position != tt.column {
+				t.Errorf("error at %d:%d, want %d:%d (%v)", parseErr.Line, parseErr.Position, tt.line, tt.column, err)
+			}
+
+			lineStart := strings.LastIndex(tt.content[:parseErr.Offset], "\n") + 1
+			if parseErr.Offset-lineStart+1 != parseErr.Position {
+				t.Errorf("offset %d does not match column %d", parseErr.Offset, parseErr.Position)
+			}
+		})
+	}
+}
+
+func TestParseFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"configs/NuGet.Config": {Data: []byte(nugetTesting.ValidNuGetConfig())},
+		"configs/empty.config": {Data: []byte{}},
+	}
+	parser := NewConfigParser()
+
+	config, err := parser.ParseFromFS(fsys, "configs/NuGet.Config")
+	if err != nil {
+		t.Fatalf("ParseFromFS() error = %v", err)
+	}
+	if len(config.PackageSources.Add) == 0 {
+		t.Error("PackageSources.Add should not be empty")
+	}
+
+	result, err := NewPositionAwareParser().ParseFromFSWithPositions(fsys, "configs/NuGet.Config")
+	if err != nil {
+		t.Fatalf("ParseFromFSWithPositions() error = %v", err)
+	}
+	if _, exists := result.Positions["configuration/packageSources"]; !exists {
+		t.Error("ParseFromFSWithPositions() should track positions")
+	}
+
+	if _, err := parser.ParseFromFS(fsys, "configs/missing.config"); !stderrors.Is(err, errors.ErrConfigFileNotFound) {
+		t.Errorf("Expected file not found error, got %v", err)
+	}
+	if _, err := parser.ParseFromFS(fsys, "configs/empty.config"); !stderrors.Is(err, errors.ErrEmptyConfigFile) {
+		t.Errorf("Expected empty file error, got %v", err)
+	}
+}
+
+func TestMaxInputSize(t *testing.T) {
+	content := nugetTesting.ValidNuGetConfig()
+
+	p := NewConfigParser()
+	p.MaxInputSize = int64(len(content))
+	if _, err := p.ParseFromString(content); err != nil {
+		t.Fatalf("ParseFromString() at the limit error = %v", err)
+	}
+	if _, err := p.ParseFromReader(strings.NewReader(content)); err != nil {
+		t.Fatalf("ParseFromReader() at the limit error = %v", err)
+	}
+
+	p.MaxInputSize = int64(len(content)) - 1
+	if _, err := p.ParseFromString(content); !stderrors.Is(err, errors.ErrInputTooLarge) {
+		t.Errorf("ParseFromString() expected input too large error, got %v", err)
+	}
+	if _, err := p.ParseFromReader(strings.NewReader(content)); !stderrors.Is(err, errors.ErrInputTooLarge) {
+		t.Errorf("ParseFromReader() expected input too large error, got %v", err)
+	}
+	if _, _, err := p.ParseFromContentLenient([]byte(content)); !stderrors.Is(err, errors.ErrInputTooLarge) {
+		t.Errorf("ParseFromContentLenient() expected input too large error, got %v", err)
+	}
+
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	nugetTesting.CreateNuGetConfigFile(t, configPath, content)
+	if _, err := p.ParseFromFileWithPositions(configPath); !stderrors.Is(err, errors.ErrInputTooLarge) {
+		t.Errorf("ParseFromFileWithPositions() expected input too large error, got %v", err)
+	}
+
+	fsys := fstest.MapFS{"NuGet.Config": {Data: []byte(content)}}
+	if _, err := p.ParseFromFS(fsys, "NuGet.Config"); !stderrors.Is(err, errors.ErrInputTooLarge) {
+		t.Errorf("ParseFromFS() expected input too large error, got %v", err)
+	}
+}
@@ -0,0 +1,538 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// sectionKind 配置节的结构类型
+type sectionKind int
+
+const (
+	// addListSection 由 <add key="..." value="..." /> 组成的配置节
+	addListSection sectionKind = iota
+	// credentialsSection <packageSourceCredentials>，子元素以包源名称命名
+	credentialsSection
+	// mappingSection <packageSourceMapping>，由 <packageSource> 和 <package> 组成
+	mappingSection
+	// opaqueSection 结构较复杂、不逐项检查的配置节，如 <trustedSigners>
+	opaqueSection
+)
+
+// knownSections NuGet 文档中定义的 <configuration> 下的配置节
+var knownSections = map[string]sectionKind{
+	"config":                   addListSection,
+	"bindingRedirects":         addListSection,
+	"packageRestore":           addListSection,
+	"solution":                 addListSection,
+	"packageSources":           addListSection,
+	"auditSources":             addListSection,
+	"packageSourceCredentials": credentialsSection,
+	"apikeys":                  addListSection,
+	"disabledPackageSources":   addListSection,
+	"activePackageSource":      addListSection,
+	"packageManagement":        addListSection,
+	"fallbackPackageFolders":   addListSection,
+	"trustedSigners":           opaqueSection,
+	"packageSourceMapping":     mappingSection,
+}
+
+// knownCredentialKeys 凭证项允许的 key
+var knownCredentialKeys = map[string]bool{
+	"Username":                 true,
+	"Password":                 true,
+	"ClearTextPassword":        true,
+	"ValidAuthenticationTypes": true,
+}
+
+// packageSourceAttributes <packageSources> 中 <add> 元素允许的属性
+var packageSourceAttributes = []string{"key", "value", "protocolVersion", "allowInsecureConnections", "disableTLSCertificateValidation"}
+
+// schemaFrame 遍历过程中一个打开的元素
+type schemaFrame struct {
+	name string
+	path string
+	// keys 已出现的子元素 key，用于检测重复
+	keys map[string]bool
+	// adds 配置节中每个 key 的 <add> 定义，按出现顺序排列，离开配置节时报告重复的 key
+	adds map[string][]keyDefinition
+	// addOrder key 第一次出现的顺序，使诊断信息的顺序保持稳定
+	addOrder []string
+	// skip 为 true 时不再检查其子元素，用于未知元素和不逐项检查的配置节
+	skip bool
+}
+
+// keyDefinition 配置节中一个 <add> 元素的位置
+type keyDefinition struct {
+	path string
+	pos  Position
+}
+
+// schemaChecker 按 NuGet 配置文件的结构逐个检查元素并收集诊断信息
+type schemaChecker struct {
+	lines       lineIndex
+	diagnostics []Diagnostic
+	// strict 为 true 时同时检查未知属性，并将未知内容视为错误
+	strict bool
+	// unknown 未知的配置节、元素和属性，同时也包含在 diagnostics 中
+	unknown []Diagnostic
+	// paths 生成与 trackPositions 一致的元素路径
+	paths pathIndexer
+	stack []*schemaFrame
+	// skipChildren 当前元素的子元素是否跳过检查
+	skipChildren bool
+}
+
+// checkSchema 以非严格模式遍历内容并返回发现的问题
+//
+// 非严格模式下缺失的结束标签会被补齐，未加引号的属性值也会被接受，
+// 因此即使内容不是格式正确的XML，也能尽量检查其余部分。strict 为 true 时还会检查未知属性。
+func checkSchema(content []byte, strict bool) (*schemaChecker, error) {
+	c := &schemaChecker{
+		lines:  newLineIndex(content),
+		strict: strict,
+	}
+
+	decoder := newDecoder(content)
+	decoder.Strict = false
+
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			c.startElement(t, c.lines.position(offset))
+		case xml.EndElement:
+			if len(c.stack) > 0 {
+				c.reportDuplicateKeys(c.stack[len(c.stack)-1])
+				c.stack = c.stack[:len(c.stack)-1]
+				c.paths.pop()
+			}
+		}
+	}
+}
+
+// strictError 返回严格模式下发现的未知内容，没有时返回 nil
+//
+// 返回的 *errors.ParseError 包装 errors.ErrUnknownElement，并指向第一处未知内容。
+func (c *schemaChecker) strictError() error {
+	if len(c.unknown) == 0 {
+		return nil
+	}
+
+	first := c.unknown[0]
+	message := first.Message
+	if more := len(c.unknown) - 1; more > 0 {
+		message += fmt.Sprintf(" (and %d more)", more)
+	}
+	parseErr := first.parseError(errors.ErrUnknownElement)
+	parseErr.Context = message
+	return parseErr
+}
+
+// startElement 检查一个开始标签并将其压入栈中
+func (c *schemaChecker) startElement(elem xml.StartElement, pos Position) {
+	name := qualifiedName(elem.Name)
+	path := c.paths.push(name)
+
+	frame := &schemaFrame{name: name, path: path, keys: make(map[string]bool)}
+	if len(c.stack) > 0 && c.stack[len(c.stack)-1].skip {
+		frame.skip = true
+	} else {
+		c.skipChildren = false
+		c.checkElement(elem, path, pos)
+		frame.skip = c.skipChildren
+	}
+	c.stack = append(c.stack, frame)
+}
+
+// checkElement 按元素在文档中的层级检查其名称和属性
+func (c *schemaChecker) checkElement(elem xml.StartElement, path string, pos Position) {
+	name := qualifiedName(elem.Name)
+	attrs := make(map[string]string, len(elem.Attr))
+	for _, a := range elem.Attr {
+		attrs[qualifiedName(a.Name)] = a.Value
+	}
+
+	switch len(c.stack) {
+	case 0:
+		if name != "configuration" {
+			c.reportUnknown(SeverityError, detail{rule: RuleRootElement, expected: "configuration", actual: name}, path, pos,
+				"root element must be <configuration>, found <%s>", name)
+			c.skipChildren = true
+			return
+		}
+		c.checkAttributes(attrs, name, path, pos)
+	case 1:
+		kind, known := knownSections[name]
+		if !known {
+			c.unknownElement(detail{rule: RuleUnknownSection, section: name, expected: suggest(name, sortedKeys(knownSections)...), actual: name},
+				path, pos, "unknown section <%s>", name)
+			return
+		}
+		switch {
+		case kind == opaqueSection:
+			c.skipChildren = true
+		case name == "packageSources":
+			c.checkAttributes(attrs, name, path, pos, "clear")
+		default:
+			c.checkAttributes(attrs, name, path, pos)
+		}
+	case 2:
+		c.checkSectionChild(name, attrs, path, pos)
+	case 3:
+		c.checkNestedChild(name, attrs, path, pos)
+	default:
+		c.unknownElement(detail{rule: RuleUnknownElement, section: c.stack[1].name, actual: name}, path, pos,
+			"unknown element <%s> in <%s>", name, c.stack[len(c.stack)-1].name)
+	}
+}
+
+// checkSectionChild 检查配置节的直接子元素
+func (c *schemaChecker) checkSectionChild(name string, attrs map[string]string, path string, pos Position) {
+	section := c.stack[1]
+
+	switch knownSections[section.name] {
+	case addListSection:
+		switch name {
+		case "add":
+			if section.name == "packageSources" {
+				c.checkAttributes(attrs, name, path, pos, packageSourceAttributes...)
+			} else {
+				c.checkAttributes(attrs, name, path, pos, "key", "value")
+			}
+			key, ok := c.requireAttr(attrs, "key", name, path, pos)
+			if !ok {
+				return
+			}
+			c.addDefinition(section, key, keyDefinition{path: path, pos: pos})
+			if _, hasValue := attrs["value"]; !hasValue {
+				c.report(SeverityError, errors.CodeMissingAttribute, detail{rule: RuleMissingAttribute, section: section.name, key: key, expected: "value"},
+					path, pos, "<add key=%q> in <%s> is missing the value attribute", key, section.name)
+			}
+			c.checkWhitespace(attrs, section.name, key, "<"+section.name+">", path, pos)
+			c.checkAddValue(section.name, key, attrs, path, pos)
+		case "remove":
+			c.checkAttributes(attrs, name, path, pos, "key")
+			if key, ok := c.requireAttr(attrs, "key", name, path, pos); ok {
+				// 被移除的定义不再生效，之后的同名定义不算重复
+				c.reportDuplicateKey(section, key)
+				delete(section.adds, key)
+			}
+		case "clear":
+			c.checkAttributes(attrs, name, path, pos)
+			c.reportDuplicateKeys(section)
+			section.adds = nil
+			section.addOrder = nil
+		default:
+			c.unknownElement(detail{rule: RuleUnknownElement, section: section.name, expected: suggest(name, "add", "remove", "clear"), actual: name},
+				path, pos, "unknown element <%s> in <%s>", name, section.name)
+		}
+
+	case credentialsSection:
+		// 元素名是编码后的包源名称，"My_x0020_Feed" 和 "My Feed" 是同一个包源
+		source := types.DecodeElementName(name)
+		c.checkAttributes(attrs, name, path, pos)
+		c.checkDuplicate(section, source, path, pos, "duplicate credentials for source %q", source)
+
+	case mappingSection:
+		switch name {
+		case "packageSource":
+			c.checkAttributes(attrs, name, path, pos, "key")
+			if key, ok := c.requireAttr(attrs, "key", name, path, pos); ok {
+				c.checkDuplicate(section, key, path, pos, "duplicate package source mapping for %q", key)
+			}
+		case "clear":
+			c.checkAttributes(attrs, name, path, pos)
+		default:
+			c.unknownElement(detail{rule: RuleUnknownElement, section: section.name, expected: suggest(name, "packageSource", "clear"), actual: name},
+				path, pos, "unknown element <%s> in <%s>", name, section.name)
+		}
+	}
+}
+
+// checkAddValue 检查特定配置节中 <add> 元素属性值的格式
+func (c *schemaChecker) checkAddValue(section, key string, attrs map[string]string, path string, pos Position) {
+	switch section {
+	case "packageSources":
+		if version, ok := attrs["protocolVersion"]; ok {
+			if _, err := strconv.Atoi(version); err != nil {
+				c.report(SeverityWarning, errors.CodeInvalidAttributeValue, detail{rule: RuleInvalidAttributeValue, section: section, key: key, expected: "number", actual: version},
+					path, pos, "protocolVersion %q of package source %q is not a number", version, key)
+			}
+		}
+		if isNuGetV2Feed(attrs["value"]) {
+			c.report(SeverityWarning, errors.CodeDeprecated, detail{rule: RuleDeprecatedFeed, section: section, key: key, expected: nugetV3Feed, actual: attrs["value"]},
+				path, pos, "package source %q uses the deprecated nuget.org V2 feed, use %s instead", key, nugetV3Feed)
+		}
+	case "disabledPackageSources":
+		if value, ok := attrs["value"]; ok {
+			if _, err := strconv.ParseBool(value); err != nil {
+				c.report(SeverityWarning, errors.CodeInvalidAttributeValue, detail{rule: RuleInvalidAttributeValue, section: section, key: key, expected: "boolean", actual: value},
+					path, pos, "value %q of disabled package source %q is not a boolean", value, key)
+			}
+		}
+	}
+}
+
+// checkNestedChild 检查凭证和包源映射中第三层的元素
+func (c *schemaChecker) checkNestedChild(name string, attrs map[string]string, path string, pos Position) {
+	section := c.stack[1]
+	parent := c.stack[2]
+
+	switch knownSections[section.name] {
+	case addListSection:
+		c.unknownElement(detail{rule: RuleUnknownElement, section: section.name, actual: name}, path, pos,
+			"unknown element <%s> in <%s>", name, parent.name)
+
+	case credentialsSection:
+		source := types.DecodeElementName(parent.name)
+		if name != "add" {
+			c.unknownElement(detail{rule: RuleUnknownElement, section: section.name, key: source, expected: suggest(name, "add"), actual: name},
+				path, pos, "unknown element <%s> in credentials for %q", name, source)
+			return
+		}
+		c.checkAttributes(attrs, name, path, pos, "key", "value")
+		key, ok := c.requireAttr(attrs, "key", name, path, pos)
+		if !ok {
+			return
+		}
+		if !knownCredentialKeys[key] {
+			c.reportUnknown(SeverityWarning, detail{rule: RuleUnknownCredentialKey, section: section.name, key: source,
+				expected: suggest(key, sortedKeys(knownCredentialKeys)...), actual: key},
+				path, pos, "unknown credential key %q for source %q", key, source)
+		}
+		c.checkWhitespace(attrs, section.name, source, fmt.Sprintf("credentials for %q", source), path, pos)
+		c.checkDuplicate(parent, key, path, pos, "duplicate credential key %q for source %q", key, source)
+
+	case mappingSection:
+		if name != "package" {
+			c.unknownElement(detail{rule: RuleUnknownElement, section: section.name, key: parent.name, expected: suggest(name, "package"), actual: name},
+				path, pos, "unknown element <%s> in <%s>", name, parent.name)
+			return
+		}
+		c.checkAttributes(attrs, name, path, pos, "pattern")
+		if pattern, ok := c.requireAttr(attrs, "pattern", name, path, pos); ok {
+			c.checkDuplicate(parent, pattern, path, pos, "duplicate package pattern %q", pattern)
+		}
+	}
+}
+
+// checkAttributes 在严格模式下检查元素是否包含 allowed 之外的属性，命名空间声明除外
+func (c *schemaChecker) checkAttributes(attrs map[string]string, elemName, path string, pos Position, allowed ...string) {
+	if !c.strict {
+		return
+	}
+
+	var unknown []string
+	for attrName := range attrs {
+		if attrName == "xmlns" || strings.HasPrefix(attrName, "xmlns:") || containsString(allowed, attrName) {
+			continue
+		}
+		unknown = append(unknown, attrName)
+	}
+
+	sort.Strings(unknown)
+	for _, attrName := range unknown {
+		c.reportUnknown(SeverityWarning, detail{rule: RuleUnknownAttribute, section: c.sectionName(elemName), key: strings.TrimSpace(attrs["key"]),
+			expected: suggest(attrName, allowed...), actual: attrName},
+			path, pos, "unknown attribute %q on <%s>", attrName, elemName)
+	}
+}
+
+// checkWhitespace 检查 <add> 元素的 key 和 value 属性首尾是否有空白字符
+//
+// NuGet 不会去除这些空白，带空白的包源名称或凭证通常是复制粘贴时引入的错误。
+// 值可能是密码，消息中不包含值本身。
+func (c *schemaChecker) checkWhitespace(attrs map[string]string, section, key, where, path string, pos Position) {
+	entry := strings.TrimSpace(attrs["key"])
+	if raw := attrs["key"]; raw != entry {
+		c.report(SeverityWarning, errors.CodeSuspiciousWhitespace, detail{rule: RuleWhitespace, section: section, key: key, expected: entry, actual: raw},
+			path, pos, "key %q in %s has leading or trailing whitespace", raw, where)
+	}
+	if value := attrs["value"]; value != strings.TrimSpace(value) && strings.TrimSpace(value) != "" {
+		c.report(SeverityWarning, errors.CodeSuspiciousWhitespace, detail{rule: RuleWhitespace, section: section, key: key},
+			path, pos, "value of %q in %s has leading or trailing whitespace", entry, where)
+	}
+}
+
+// nugetV3Feed nuget.org V3 源的地址
+const nugetV3Feed = "https://api.nuget.org/v3/index.json"
+
+// isNuGetV2Feed 判断包源地址是否为已弃用的 nuget.org V2 源
+func isNuGetV2Feed(value string) bool {
+	value = strings.ToLower(strings.TrimRight(strings.TrimSpace(value), "/"))
+	for _, prefix := range []string{"https://", "http://"} {
+		if rest, ok := strings.CutPrefix(value, prefix); ok {
+			return rest == "www.nuget.org/api/v2" || rest == "nuget.org/api/v2"
+		}
+	}
+	return false
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAttr 检查元素是否包含非空的必需属性
+func (c *schemaChecker) requireAttr(attrs map[string]string, attrName, elemName, path string, pos Position) (string, bool) {
+	value := strings.TrimSpace(attrs[attrName])
+	if value == "" {
+		c.report(SeverityError, errors.CodeMissingAttribute, detail{rule: RuleMissingAttribute, section: c.sectionName(elemName), expected: attrName},
+			path, pos, "<%s> is missing the %s attribute", elemName, attrName)
+		return "", false
+	}
+	return value, true
+}
+
+// checkDuplicate 检查 key 是否已在 frame 的子元素中出现过
+func (c *schemaChecker) checkDuplicate(frame *schemaFrame, key, path string, pos Position, format string, args ...interface{}) {
+	if frame.keys[key] {
+		c.report(SeverityWarning, errors.CodeDuplicateEntry, detail{rule: RuleDuplicateEntry, section: c.stack[1].name, key: key}, path, pos, format, args...)
+		return
+	}
+	frame.keys[key] = true
+}
+
+// addDefinition 记录配置节中 key 的一个 <add> 定义
+func (c *schemaChecker) addDefinition(section *schemaFrame, key string, def keyDefinition) {
+	if section.adds == nil {
+		section.adds = make(map[string][]keyDefinition)
+	}
+	if _, exists := section.adds[key]; !exists {
+		section.addOrder = append(section.addOrder, key)
+	}
+	section.adds[key] = append(section.adds[key], def)
+}
+
+// reportDuplicateKeys 报告配置节中所有重复定义的 key 并清空已记录的定义
+func (c *schemaChecker) reportDuplicateKeys(section *schemaFrame) {
+	for _, key := range section.addOrder {
+		c.reportDuplicateKey(section, key)
+	}
+	section.adds = nil
+	section.addOrder = nil
+}
+
+// reportDuplicateKey 报告配置节中重复定义的 key
+//
+// 同一配置节中 key 重复时 NuGet 使用第一个定义（settings 和 manager 包同样以第一个为准），
+// 因此每个被忽略的定义都指向第一个定义，第一个定义则说明它是实际生效的定义。
+func (c *schemaChecker) reportDuplicateKey(section *schemaFrame, key string) {
+	defs := section.adds[key]
+	if len(defs) < 2 {
+		return
+	}
+
+	code := errors.CodeDuplicateEntry
+	if section.name == "packageSources" {
+		code = errors.CodeDuplicateSourceKey
+	}
+	honored := defs[0]
+	related := make([]Position, len(defs))
+	for i, def := range defs {
+		related[i] = def.pos
+	}
+	c.report(SeverityWarning, code, detail{rule: RuleDuplicateKey, section: section.name, key: key}, honored.path, honored.pos,
+		"duplicate key %q in <%s> is defined %d times; NuGet uses this definition",
+		key, section.name, len(defs))
+	c.diagnostics[len(c.diagnostics)-1].Related = related
+	for _, def := range defs[1:] {
+		c.report(SeverityWarning, code, detail{rule: RuleDuplicateKey, section: section.name, key: key}, def.path, def.pos,
+			"duplicate key %q in <%s> is ignored; NuGet uses the definition at line %d, column %d",
+			key, section.name, honored.pos.Line, honored.pos.Column)
+		c.diagnostics[len(c.diagnostics)-1].Related = related
+	}
+}
+
+// unknownElement 记录一个未知元素，并跳过其子元素的检查
+func (c *schemaChecker) unknownElement(d detail, path string, pos Position, format string, args ...interface{}) {
+	c.reportUnknown(SeverityWarning, d, path, pos, format, args...)
+	c.skipChildren = true
+}
+
+// reportUnknown 记录一条关于未知内容的诊断信息
+//
+// 严格模式下未知内容一律视为错误。d.expected 是最接近的已知名称，不为空时作为建议附加在消息末尾。
+// 根元素不是 <configuration> 时 d.expected 总是 "configuration"，消息中已经说明，不再重复。
+func (c *schemaChecker) reportUnknown(severity Severity, d detail, path string, pos Position, format string, args ...interface{}) {
+	if c.strict {
+		severity = SeverityError
+	}
+	if d.expected != "" && d.rule != RuleRootElement {
+		format += "; did you mean %q?"
+		args = append(args, d.expected)
+	}
+	c.report(severity, errors.CodeUnknownElement, d, path, pos, format, args...)
+	c.diagnostics[len(c.diagnostics)-1].Suggestion = d.expected
+	c.unknown = append(c.unknown, c.diagnostics[len(c.diagnostics)-1])
+}
+
+// sectionName 返回当前所在的配置节名称，位于 <configuration> 或根元素上时返回 elemName
+func (c *schemaChecker) sectionName(elemName string) string {
+	if len(c.stack) >= 2 {
+		return c.stack[1].name
+	}
+	return elemName
+}
+
+// suggest 返回与未知名称最接近的已知名称，没有时返回空字符串
+func suggest(name string, known ...string) string {
+	return utils.ClosestMatch(name, known)
+}
+
+// sortedKeys 返回按字母顺序排列的 map 键，使建议的结果保持稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// detail 诊断信息的结构化字段，含义见 Diagnostic 中的同名字段
+type detail struct {
+	rule     string
+	section  string
+	key      string
+	expected string
+	actual   string
+}
+
+// report 记录一条诊断信息
+func (c *schemaChecker) report(severity Severity, code errors.Code, d detail, path string, pos Position, format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Rule:     d.rule,
+		Message:  fmt.Sprintf(format, args...),
+		Path:     path,
+		Section:  d.section,
+		Key:      d.key,
+		Expected: d.expected,
+		Actual:   d.actual,
+		Position: pos,
+	})
+}
@@ -0,0 +1,212 @@
+package parser
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestStrictSchema(t *testing.T) {
+	clean := `<configuration>
+  <packageSources>
+    <clear />
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+  </packageSources>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="user" />
+    </nuget.org>
+  </packageSourceCredentials>
+  <trustedSigners>
+    <author name="microsoft">
+      <certificate fingerprint="abc" hashAlgorithm="SHA256" allowUntrustedRoot="false" />
+    </author>
+  </trustedSigners>
+  <packageSourceMapping>
+    <packageSource key="nuget.org">
+      <package pattern="*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		message string
+		rule    string
+		actual  string
+	}{
+		{
+			name:    "known content",
+			content: clean,
+		},
+		{
+			name: "unknown section",
+			content: `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+  <myTool>
+    <setting name="x" />
+  </myTool>
+</configuration>`,
+			line:    5,
+			message: "unknown section <myTool>",
+			rule:    RuleUnknownSection,
+			actual:  "myTool",
+		},
+		{
+			name: "unknown element",
+			content: `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com">
+      <note />
+    </add>
+  </packageSources>
+</configuration>`,
+			line:    4,
+			message: "unknown element <note> in <add>",
+			rule:    RuleUnknownElement,
+			actual:  "note",
+		},
+		{
+			name: "unknown attributes",
+			content: `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" priority="1" enabled="true" />
+  </packageSources>
+</configuration>`,
+			line:    3,
+			message: `unknown attribute "enabled" on <add> (and 1 more)`,
+			rule:    RuleUnknownAttribute,
+			actual:  "enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewConfigParser()
+			p.StrictSchema = true
+
+			_, err := p.ParseFromString(tt.content)
+			if tt.message == "" {
+				if err != nil {
+					t.Fatalf("ParseFromString() error = %v", err)
+				}
+				return
+			}
+
+			if !stderrors.Is(err, errors.ErrUnknownElement) {
+				t.Fatalf("Expected unknown element error, got %v", err)
+			}
+			var parseErr *errors.ParseError
+			if !stderrors.As(err, &parseErr) {
+				t.Fatalf("Expected *errors.ParseError, got %T", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Context != tt.message {
+				t.Errorf("error = line %d %q, want line %d %q", parseErr.Line, parseErr.Context, tt.line, tt.message)
+			}
+			if parseErr.Rule != tt.rule || parseErr.Actual != tt.actual || parseErr.Path == "" {
+				t.Errorf("error fields = rule %q actual %q path %q, want rule %q actual %q", parseErr.Rule, parseErr.Actual, parseErr.Path, tt.rule, tt.actual)
+			}
+
+			// 非严格模式下同样的内容可以正常解析
+			if _, err := NewConfigParser().ParseFromString(tt.content); err != nil {
+				t.Errorf("non-strict ParseFromString() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestStrictSchemaLenient(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" priority="1" />
+  </packageSources>
+</configuration>`
+
+	p := NewConfigParser()
+	p.StrictSchema = true
+	_, diagnostics, err := p.ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityError {
+		t.Errorf("expected one error diagnostic for the unknown attribute, got %v", diagnostics)
+	}
+
+	_, diagnostics, err = NewConfigParser().ParseFromContentLenient([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentLenient() error = %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("unknown attributes should only be reported in strict mode, got %v", diagnostics)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	content := `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://www.nuget.org/api/v2/" />
+    <add key="feed " value="https://feed.example.com/v3/index.json" />
+    <add key="mirror" value=" https://mirror.example.com/v3/index.json" />
+    <add key="mirror" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <mirror>
+      <add key="Username" value="user " />
+    </mirror>
+  </packageSourceCredentials>
+</configuration>`
+
+	expected := []struct {
+		line int
+		code errors.Code
+	}{
+		{3, errors.CodeDeprecated},
+		{4, errors.CodeSuspiciousWhitespace},
+		{5, errors.CodeSuspiciousWhitespace},
+		{5, errors.CodeDuplicateSourceKey},
+		{6, errors.CodeDuplicateSourceKey},
+		{10, errors.CodeSuspiciousWhitespace},
+	}
+	check := func(name string, diagnostics []Diagnostic) {
+		t.Helper()
+		if len(diagnostics) != len(expected) {
+			t.Fatalf("%s: expected %d diagnostics, got %d: %v", name, len(expected), len(diagnostics), diagnostics)
+		}
+		for i, want := range expected {
+			if got := diagnostics[i]; got.Position.Line != want.line || got.Code != want.code || got.Severity != SeverityWarning {
+				t.Errorf("%s: diagnostic %d = %v (%s), want line %d %s", name, i, got, got.Code, want.line, want.code)
+			}
+		}
+	}
+
+	var warnings []Diagnostic
+	p := NewConfigParser()
+	p.OnWarning = func(d Diagnostic) { warnings = append(warnings, d) }
+	if _, err := p.ParseFromContent([]byte(content)); err != nil {
+		t.Fatalf("ParseFromContent() error = %v", err)
+	}
+	check("OnWarning", warnings)
+	if warnings[2].Message != `value of "mirror" in <packageSources> has leading or trailing whitespace` {
+		t.Errorf("whitespace message = %q", warnings[2].Message)
+	}
+
+	result, err := NewPositionAwareParser().ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+	}
+	check("ParseResult.Diagnostics", result.Diagnostics)
+
+	// 解析失败时不调用 OnWarning
+	warnings = nil
+	if _, err := p.ParseFromContent([]byte(`<configuration><packageSources><add key="a " value="b" /></packageSources>`)); err == nil {
+		t.Fatal("ParseFromContent() with malformed XML should fail")
+	}
+	if len(warnings) != 0 {
+		t.Errorf("OnWarning called for a failed parse: %v", warnings)
+	}
+}
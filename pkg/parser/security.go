@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+// DefaultMaxDepth 元素嵌套深度的默认上限，NuGet 配置文件的实际深度不超过5层
+const DefaultMaxDepth = 64
+
+// DoctypePolicy 遇到 DOCTYPE 声明时的处理方式
+//
+// encoding/xml 不会解析DTD，也不会加载外部实体或展开DTD中声明的实体，对这些实体的引用
+// 会作为语法错误被拒绝。NuGet 配置文件从不需要 DOCTYPE，因此默认直接拒绝，
+// 以便尽早发现来自不可信仓库的可疑配置文件。
+type DoctypePolicy int
+
+const (
+	// DoctypeReject 拒绝包含 DOCTYPE 声明的内容，默认值
+	DoctypeReject DoctypePolicy = iota
+	// DoctypeIgnore 忽略 DOCTYPE 声明，其中声明的实体仍不会被展开
+	DoctypeIgnore
+)
+
+// checkSecurity 检查内容中是否包含不安全的构造
+//
+// 以非严格模式逐个读取原始标记，检查 DOCTYPE 声明和元素嵌套深度。格式错误的内容
+// 在出错位置停止检查，由后续的语法检查报告。
+func (p *ConfigParser) checkSecurity(content []byte) error {
+	maxDepth := p.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	decoder := newDecoder(content)
+	decoder.Strict = false
+
+	depth := 0
+	for {
+		offset := int(decoder.InputOffset())
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nil
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			directive := strings.TrimSpace(string(t))
+			if p.DoctypePolicy == DoctypeReject && len(directive) >= 7 && strings.EqualFold(directive[:7], "DOCTYPE") {
+				return securityError(content, offset, "DOCTYPE declarations are not allowed")
+			}
+		case xml.StartElement:
+			depth++
+			if depth > maxDepth {
+				return securityError(content, offset, fmt.Sprintf("element nesting exceeds maximum depth %d", maxDepth))
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// securityError 创建指向 offset 处的不安全内容错误
+func securityError(content []byte, offset int, message string) error {
+	pos := newLineIndex(content).position(offset)
+	parseErr := errors.NewParseError(errors.ErrInsecureContent, pos.Line, pos.Column, message)
+	parseErr.Offset = pos.Offset
+	return parseErr
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+const billionLaughs = `<?xml version="1.0"?>
+<!DOCTYPE configuration [
+  <!ENTITY lol "lol">
+  <!ENTITY lol2 "&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;&lol;">
+  <!ENTITY lol3 "&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;&lol2;">
+]>
+<configuration>
+  <packageSources>
+    <add key="&lol3;" value="https://a.example.com" />
+  </packageSources>
+</configuration>`
+
+const externalEntity = `<?xml version="1.0"?>
+<!DOCTYPE configuration [
+  <!ENTITY xxe SYSTEM "file:///etc/passwd">
+]>
+<configuration>
+  <packageSources>
+    <add key="a" value="&xxe;" />
+  </packageSources>
+</configuration>`
+
+func TestDoctypeRejected(t *testing.T) {
+	for name, content := range map[string]string{"billion laughs": billionLaughs, "external entity": externalEntity} {
+		t.Run(name, func(t *testing.T) {
+			p := NewPositionAwareParser()
+
+			_, err := p.ParseFromString(content)
+			if !stderrors.Is(err, errors.ErrInsecureContent) {
+				t.Fatalf("ParseFromString() expected insecure content error, got %v", err)
+			}
+			var parseErr *errors.ParseError
+			if !stderrors.As(err, &parseErr) || parseErr.Line != 2 {
+				t.Errorf("expected error on line 2, got %v", err)
+			}
+
+			if _, err := p.ParseFromContentWithPositions([]byte(content)); !stderrors.Is(err, errors.ErrInsecureContent) {
+				t.Errorf("ParseFromContentWithPositions() expected insecure content error, got %v", err)
+			}
+			if _, _, err := p.ParseFromContentLenient([]byte(content)); !stderrors.Is(err, errors.ErrInsecureContent) {
+				t.Errorf("ParseFromContentLenient() expected insecure content error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDoctypeIgnoredEntitiesNotExpanded(t *testing.T) {
+	for name, content := range map[string]string{"billion laughs": billionLaughs, "external entity": externalEntity} {
+		t.Run(name, func(t *testing.T) {
+			p := NewConfigParser()
+			p.DoctypePolicy = DoctypeIgnore
+
+			// DTD 中声明的实体不会被展开，引用它们属于语法错误
+			_, err := p.ParseFromString(content)
+			if err == nil || stderrors.Is(err, errors.ErrInsecureContent) {
+				t.Fatalf("ParseFromString() expected a syntax error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "entity") {
+				t.Errorf("expected an entity error, got %v", err)
+			}
+		})
+	}
+
+	p := NewConfigParser()
+	p.DoctypePolicy = DoctypeIgnore
+	content := `<?xml version="1.0"?>
+<!DOCTYPE configuration>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+</configuration>`
+	if _, err := p.ParseFromString(content); err != nil {
+		t.Errorf("ParseFromString() error = %v", err)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	content := "<configuration><packageSources><add key=\"a\" value=\"b\" /></packageSources>" +
+		"<trustedSigners>" + strings.Repeat("<a>", 100) + strings.Repeat("</a>", 100) + "</trustedSigners></configuration>"
+
+	if _, err := NewConfigParser().ParseFromString(content); !stderrors.Is(err, errors.ErrInsecureContent) {
+		t.Errorf("ParseFromString() expected insecure content error, got %v", err)
+	}
+
+	p := NewConfigParser()
+	p.MaxDepth = 200
+	if _, err := p.ParseFromString(content); err != nil {
+		t.Errorf("ParseFromString() with raised MaxDepth error = %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// SerializeOptions 控制序列化生成的XML格式，用于使生成的配置文件符合团队约定
+type SerializeOptions struct {
+	// Indent 每一级缩进使用的字符串，为空时不缩进
+	Indent string
+
+	// Newline 换行符，"\n" 或 "\r\n"，为空时使用 "\n"
+	Newline string
+
+	// OmitXMLDeclaration 是否省略开头的XML声明
+	OmitXMLDeclaration bool
+
+	// Encoding XML声明中 encoding 属性的值，为空时使用 "utf-8"。
+	// SaveToFile 写入文件时会将其修改为与 OutputEncoding 一致
+	Encoding string
+
+	// OmitEncoding 是否省略XML声明中的 encoding 属性
+	OmitEncoding bool
+
+	// AttributeOrder 属性的输出顺序，列出的属性按列表顺序排在最前，
+	// 其余属性保持原有顺序，如 []string{"value", "key"}
+	AttributeOrder []string
+
+	// SortAttributes 是否将 AttributeOrder 中未列出的属性按名称排序
+	SortAttributes bool
+
+	// ElementStyle 元素风格，为 nil 时使用 ConfigParser.ElementStyle
+	ElementStyle *ElementStyle
+
+	// RedactSecrets 是否将密码、API 密钥和令牌替换为 types.RedactedValue，见 types.NuGetConfig.Redacted。
+	// 用于输出到日志或问题报告，不应用于保存实际使用的配置文件
+	RedactSecrets bool
+}
+
+// DefaultSerializeOptions 返回默认的序列化选项：两个空格缩进、"\n" 换行、
+// 带 encoding="utf-8" 的XML声明、属性保持定义顺序
+func DefaultSerializeOptions() SerializeOptions {
+	return SerializeOptions{
+		Indent:   "  ",
+		Newline:  "\n",
+		Encoding: "utf-8",
+	}
+}
+
+// SerializeToXMLWithOptions 按指定选项将配置序列化为XML字符串
+func (p *ConfigParser) SerializeToXMLWithOptions(config *types.NuGetConfig, opts SerializeOptions) (string, error) {
+	if opts.RedactSecrets {
+		config = config.Redacted()
+	}
+
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to XML: %w", err)
+	}
+
+	root, err := buildXMLTree(data)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to format XML: %w", err)
+	}
+
+	if opts.ElementStyle == nil {
+		opts.ElementStyle = p.ElementStyle
+	}
+	if opts.Newline == "" {
+		opts.Newline = "\n"
+	}
+
+	w := &xmlWriter{opts: opts}
+	if !opts.OmitXMLDeclaration {
+		w.sb.WriteString(`<?xml version="1.0"`)
+		if !opts.OmitEncoding {
+			encoding := opts.Encoding
+			if encoding == "" {
+				encoding = "utf-8"
+			}
+			w.sb.WriteString(` encoding="` + encoding + `"`)
+		}
+		w.sb.WriteString("?>" + opts.Newline)
+	}
+	w.writeNode(root, 0)
+
+	return w.sb.String(), nil
+}
+
+// serializeOptions 返回 SerializeToXML 使用的序列化选项
+func (p *ConfigParser) serializeOptions() SerializeOptions {
+	if p.SerializeOptions != nil {
+		return *p.SerializeOptions
+	}
+	return DefaultSerializeOptions()
+}
+
+// xmlNode 序列化过程中的一个元素
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+// buildXMLTree 将 encoding/xml 生成的XML读取为元素树，元素之间的空白会被丢弃
+func buildXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 && len(bytes.TrimSpace(t)) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+// xmlWriter 按序列化选项输出元素树
+type xmlWriter struct {
+	opts SerializeOptions
+	sb   strings.Builder
+}
+
+// writeNode 输出一个元素及其子元素，根元素之后不输出换行符
+func (w *xmlWriter) writeNode(node *xmlNode, depth int) {
+	indent := strings.Repeat(w.opts.Indent, depth)
+	quote := `"`
+	if w.opts.ElementStyle != nil {
+		quote = string(w.opts.ElementStyle.QuoteChar())
+	}
+
+	w.sb.WriteString(indent + "<" + node.name)
+	for _, attr := range w.orderAttributes(node.attrs) {
+		w.sb.WriteString(" " + attr.Name.Local + "=" + quote + escapeXML(attr.Value) + quote)
+	}
+
+	switch {
+	case len(node.children) == 0 && node.text == "":
+		if w.opts.ElementStyle != nil && w.opts.ElementStyle.SelfClosing {
+			w.sb.WriteString(w.opts.ElementStyle.SelfCloseSuffix())
+		} else {
+			w.sb.WriteString("></" + node.name + ">")
+		}
+	case len(node.children) == 0:
+		w.sb.WriteString(">" + escapeXML(node.text) + "</" + node.name + ">")
+	default:
+		w.sb.WriteString(">" + escapeXML(node.text) + w.opts.Newline)
+		for _, child := range node.children {
+			w.writeNode(child, depth+1)
+			w.sb.WriteString(w.opts.Newline)
+		}
+		w.sb.WriteString(indent + "</" + node.name + ">")
+	}
+}
+
+// orderAttributes 按 AttributeOrder 排列属性
+func (w *xmlWriter) orderAttributes(attrs []xml.Attr) []xml.Attr {
+	if len(w.opts.AttributeOrder) == 0 && !w.opts.SortAttributes {
+		return attrs
+	}
+
+	rank := func(name string) int {
+		for i, ordered := range w.opts.AttributeOrder {
+			if ordered == name {
+				return i
+			}
+		}
+		return len(w.opts.AttributeOrder)
+	}
+
+	ordered := append([]xml.Attr(nil), attrs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		ri, rj := rank(ordered[i].Name.Local), rank(ordered[j].Name.Local)
+		if ri != rj || !w.opts.SortAttributes {
+			return ri < rj
+		}
+		return ordered[i].Name.Local < ordered[j].Name.Local
+	})
+	return ordered
+}
+
+// escapeXML 转义属性值和文本，引号一律转义，因此可以使用任意一种引号定界属性值
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestSerializeToXMLWithOptions(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"}},
+		},
+		Config: &types.Config{
+			Add: []types.ConfigOption{{Key: "http_proxy", Value: `http://proxy?a=1&b="2"`}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     SerializeOptions
+		expected string
+	}{
+		{
+			name: "默认选项",
+			opts: DefaultSerializeOptions(),
+			expected: `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3"></add>
+  </packageSources>
+  <config>
+    <add key="http_proxy" value="http://proxy?a=1&amp;b=&#34;2&#34;"></add>
+  </config>
+</configuration>`,
+		},
+		{
+			name: "制表符缩进与CRLF换行",
+			opts: SerializeOptions{
+				Indent:       "\t",
+				Newline:      "\r\n",
+				OmitEncoding: true,
+				ElementStyle: &ElementStyle{SelfClosing: true, SpaceBeforeSlash: true, Quote: '\''},
+			},
+			expected: "<?xml version=\"1.0\"?>\r\n<configuration>\r\n\t<packageSources>\r\n" +
+				"\t\t<add key='nuget.org' value='https://api.nuget.org/v3/index.json' protocolVersion='3' />\r\n" +
+				"\t</packageSources>\r\n\t<config>\r\n" +
+				"\t\t<add key='http_proxy' value='http://proxy?a=1&amp;b=&#34;2&#34;' />\r\n" +
+				"\t</config>\r\n</configuration>",
+		},
+		{
+			name: "省略声明且调整属性顺序",
+			opts: SerializeOptions{
+				OmitXMLDeclaration: true,
+				AttributeOrder:     []string{"protocolVersion", "value"},
+			},
+			expected: "<configuration>\n<packageSources>\n" +
+				`<add protocolVersion="3" value="https://api.nuget.org/v3/index.json" key="nuget.org"></add>` + "\n" +
+				"</packageSources>\n<config>\n" +
+				`<add value="http://proxy?a=1&amp;b=&#34;2&#34;" key="http_proxy"></add>` + "\n" +
+				"</config>\n</configuration>",
+		},
+		{
+			name: "自定义声明编码",
+			opts: SerializeOptions{Indent: " ", Encoding: "UTF-8"},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+ <packageSources>
+  <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3"></add>
+ </packageSources>
+ <config>
+  <add key="http_proxy" value="http://proxy?a=1&amp;b=&#34;2&#34;"></add>
+ </config>
+</configuration>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewConfigParser()
+			xmlString, err := p.SerializeToXMLWithOptions(config, tt.opts)
+			if err != nil {
+				t.Fatalf("SerializeToXMLWithOptions() error = %v", err)
+			}
+			if xmlString != tt.expected {
+				t.Errorf("SerializeToXMLWithOptions() =\n%s\nwant\n%s", xmlString, tt.expected)
+			}
+
+			parsed, err := p.ParseFromString(xmlString)
+			if err != nil {
+				t.Fatalf("Failed to parse serialized XML: %v", err)
+			}
+			if parsed.Config.Add[0].Value != config.Config.Add[0].Value {
+				t.Errorf("value after round trip = %q, want %q", parsed.Config.Add[0].Value, config.Config.Add[0].Value)
+			}
+		})
+	}
+}
+
+func TestSerializeToXMLUsesParserOptions(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{{Key: "a", Value: "https://a.example.com"}},
+		},
+	}
+
+	p := NewConfigParser()
+	p.SerializeOptions = &SerializeOptions{Indent: "\t", Newline: "\r\n"}
+
+	xmlString, err := p.SerializeToXML(config)
+	if err != nil {
+		t.Fatalf("SerializeToXML() error = %v", err)
+	}
+	if !strings.Contains(xmlString, "\r\n\t\t<add key=\"a\"") || strings.Contains(xmlString, "  ") {
+		t.Errorf("SerializeToXML() should use the parser's SerializeOptions, got %q", xmlString)
+	}
+}
+
+func TestSerializeRootElement(t *testing.T) {
+	p := NewConfigParser()
+	parsed, err := p.ParseFromContent([]byte(`<configuration><packageSources><add key="a" value="https://a.example.com" /></packageSources></configuration>`))
+	if err != nil {
+		t.Fatalf("ParseFromContent() error = %v", err)
+	}
+	built := &types.NuGetConfig{PackageSources: types.PackageSources{Add: []types.PackageSource{{Key: "a", Value: "https://a.example.com"}}}}
+
+	serializers := map[string]func(*types.NuGetConfig) (string, error){
+		"SerializeToXML": p.SerializeToXML,
+		"SerializeToXMLWithOptions": func(c *types.NuGetConfig) (string, error) {
+			opts := DefaultSerializeOptions()
+			opts.OmitXMLDeclaration = true
+			return p.SerializeToXMLWithOptions(c, opts)
+		},
+		"SerializeCanonical": p.SerializeCanonical,
+	}
+	for name, serialize := range serializers {
+		for _, config := range []*types.NuGetConfig{parsed, built} {
+			xmlString, err := serialize(config)
+			if err != nil {
+				t.Fatalf("%s() error = %v", name, err)
+			}
+			body := strings.TrimSpace(xmlString)
+			if i := strings.Index(body, "?>"); i >= 0 {
+				body = strings.TrimSpace(body[i+2:])
+			}
+			if !strings.HasPrefix(body, "<configuration>") || !strings.HasSuffix(body, "</configuration>") {
+				t.Errorf("%s() root element is not <configuration>:\n%s", name, xmlString)
+			}
+		}
+	}
+
+	if parsed.XMLName != (xml.Name{}) {
+		t.Errorf("parsed XMLName = %v, want it empty so parsed and built configs compare equal", parsed.XMLName)
+	}
+}
+
+func TestSerializeRedactSecrets(t *testing.T) {
+	config := &types.NuGetConfig{
+		PackageSources: types.PackageSources{
+			Add: []types.PackageSource{{Key: "a", Value: "https://a.example.com/v3/index.json"}},
+		},
+		PackageSourceCredentials: &types.PackageSourceCredentials{Sources: map[string]types.SourceCredential{
+			"a": {Add: []types.Credential{{Key: "Username", Value: "ci"}, {Key: "ClearTextPassword", Value: "s3cret"}}},
+		}},
+	}
+
+	opts := DefaultSerializeOptions()
+	opts.RedactSecrets = true
+	xmlString, err := NewConfigParser().SerializeToXMLWithOptions(config, opts)
+	if err != nil {
+		t.Fatalf("SerializeToXMLWithOptions() error = %v", err)
+	}
+	if strings.Contains(xmlString, "s3cret") || !strings.Contains(xmlString, `<add key="ClearTextPassword" value="***">`) {
+		t.Errorf("RedactSecrets output:\n%s", xmlString)
+	}
+	if config.PackageSourceCredentials.Sources["a"].Add[1].Value != "s3cret" {
+		t.Error("RedactSecrets modified the config")
+	}
+}
+
+func TestSerializeCanonical(t *testing.T) {
+	first := `<configuration>
+  <packageSources clear="true">
+    <add key="b" value="https://b.example.com" protocolVersion="3" />
+    <add key="a" value="https://a.example.com" />
+  </packageSources>
+  <packageSourceCredentials>
+    <b>
+      <add key="Username" value="user" />
+      <add key="ClearTextPassword" value="secret" />
+    </b>
+    <a>
+      <add key="Username" value="user-a" />
+    </a>
+  </packageSourceCredentials>
+  <config>
+    <add key="http_proxy" value="http://proxy" />
+    <add key="globalPackagesFolder" value="/packages" />
+  </config>
+  <packageSourceMapping>
+    <packageSource key="b">
+      <package pattern="B.*" />
+      <package pattern="Another.*" />
+    </packageSource>
+    <packageSource key="a">
+      <package pattern="*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+	second := "<?xml version='1.0'?>\r\n<configuration>\r\n" +
+		"\t<packageSourceMapping><packageSource key='a'><package pattern='*'/></packageSource>" +
+		"<packageSource key='b'><package pattern='Another.*'/><package pattern='B.*'/></packageSource></packageSourceMapping>\r\n" +
+		"\t<config><add value='/packages' key='globalPackagesFolder'/><add value='http://proxy' key='http_proxy'/></config>\r\n" +
+		"\t<packageSources><clear/><add value='https://a.example.com' key='a'/>" +
+		"<add protocolVersion='3' value='https://b.example.com' key='b'/></packageSources>\r\n" +
+		"\t<disabledPackageSources></disabledPackageSources>\r\n" +
+		"\t<packageSourceCredentials><a><add key='Username' value='user-a'/></a>" +
+		"<b><add key='ClearTextPassword' value='secret'/><add key='Username' value='user'/></b></packageSourceCredentials>\r\n" +
+		"</configuration>"
+
+	p := NewConfigParser()
+	var outputs []string
+	for _, content := range []string{first, second} {
+		config, err := p.ParseFromString(content)
+		if err != nil {
+			t.Fatalf("ParseFromString() error = %v", err)
+		}
+		before := config.PackageSources.Add[0].Key
+
+		canonical, err := p.SerializeCanonical(config)
+		if err != nil {
+			t.Fatalf("SerializeCanonical() error = %v", err)
+		}
+		if config.PackageSources.Add[0].Key != before {
+			t.Errorf("SerializeCanonical() should not modify the config")
+		}
+		outputs = append(outputs, canonical)
+	}
+
+	if outputs[0] != outputs[1] {
+		t.Fatalf("canonical output differs for equivalent configs:\n%s\n---\n%s", outputs[0], outputs[1])
+	}
+
+	expected := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <clear />
+    <add key="a" value="https://a.example.com" />
+    <add key="b" value="https://b.example.com" protocolVersion="3" />
+  </packageSources>
+  <packageSourceCredentials>
+    <a>
+      <add key="Username" value="user-a" />
+    </a>
+    <b>
+      <add key="ClearTextPassword" value="secret" />
+      <add key="Username" value="user" />
+    </b>
+  </packageSourceCredentials>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+    <add key="http_proxy" value="http://proxy" />
+  </config>
+  <packageSourceMapping>
+    <packageSource key="a">
+      <package pattern="*" />
+    </packageSource>
+    <packageSource key="b">
+      <package pattern="Another.*" />
+      <package pattern="B.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+	if outputs[0] != expected {
+		t.Errorf("SerializeCanonical() =\n%s\nwant\n%s", outputs[0], expected)
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+// ElementStyle 描述生成XML元素时的书写风格
+type ElementStyle struct {
+	// SelfClosing 空元素是否使用自闭合标签 <add ... />，为 false 时使用 <add ...></add>
+	SelfClosing bool
+
+	// SpaceBeforeSlash 自闭合标签的 "/>" 前是否加空格
+	SpaceBeforeSlash bool
+
+	// Quote 属性值使用的引号，'"' 或 '\''，为 0 时使用双引号
+	Quote byte
+}
+
+// DefaultElementStyle 返回 NuGet 官方配置文件使用的风格：自闭合标签、"/>" 前加空格、双引号
+func DefaultElementStyle() ElementStyle {
+	return ElementStyle{
+		SelfClosing:      true,
+		SpaceBeforeSlash: true,
+		Quote:            '"',
+	}
+}
+
+// SelfCloseSuffix 返回自闭合标签的结尾，" />" 或 "/>"
+func (s ElementStyle) SelfCloseSuffix() string {
+	if s.SpaceBeforeSlash {
+		return " />"
+	}
+	return "/>"
+}
+
+// QuoteChar 返回属性值使用的引号，未设置时为双引号
+func (s ElementStyle) QuoteChar() byte {
+	if s.Quote == '\'' {
+		return '\''
+	}
+	return '"'
+}
@@ -0,0 +1,611 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// TOML 表示与 JSON 表示使用相同的字段名，包源、配置选项等列表写作表数组，例如：
+//
+//	[packageSources]
+//	clear = true
+//
+//	[[packageSources.add]]
+//	key = "nuget.org"
+//	value = "https://api.nuget.org/v3/index.json"
+//
+//	[[packageSourceCredentials."nuget.org".add]]
+//	key = "Username"
+//	value = "user"
+//
+// 解析时支持 TOML 的常用子集：表、表数组、点分隔的键、基本字符串、字面量字符串、
+// 布尔值、数字、单行数组以及注释，不支持多行字符串和内联表。除 clear 外的字段都是字符串，
+// 写作布尔值或数字时按其文本读取，例如 protocolVersion = 3 与 protocolVersion = "3" 等价。
+
+// SerializeToTOML 将配置序列化为TOML字符串
+func (p *ConfigParser) SerializeToTOML(config *types.NuGetConfig) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to TOML: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := readJSONValue(decoder)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to TOML: %w", err)
+	}
+	table, ok := root.(*orderedTable)
+	if !ok {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to TOML: unexpected JSON value %T", root)
+	}
+
+	var sb strings.Builder
+	if err := writeTOMLTable(&sb, nil, table, false); err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to TOML: %w", err)
+	}
+	return strings.TrimPrefix(sb.String(), "\n"), nil
+}
+
+// ParseFromTOML 从 SerializeToTOML 生成的TOML解析配置
+//
+// 与解析JSON时一样，包含未知字段或未定义任何包源时返回错误。
+// 语法错误返回的 *errors.ParseError 带有出错的行号和列号。
+func (p *ConfigParser) ParseFromTOML(content []byte) (*types.NuGetConfig, error) {
+	if err := p.checkInputSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+	content, _, err := DecodeContent(content)
+	if err != nil {
+		return nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, errors.ErrEmptyConfigFile
+	}
+
+	root, err := parseTOML(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(root) == 0 {
+		return nil, errors.ErrEmptyConfigFile
+	}
+
+	data, err := json.Marshal(scalarsToStrings(root, ""))
+	if err != nil {
+		return nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+	return p.configFromJSON(data)
+}
+
+// orderedTable 保持键顺序的JSON对象，用于按字段定义顺序输出TOML
+type orderedTable struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// readJSONValue 读取一个JSON值，对象读取为 *orderedTable
+func readJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			table := &orderedTable{values: make(map[string]interface{})}
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyToken.(string)
+				value, err := readJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				table.keys = append(table.keys, key)
+				table.values[key] = value
+			}
+			_, err := decoder.Token()
+			return table, err
+		case '[':
+			var items []interface{}
+			for decoder.More() {
+				item, err := readJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			_, err := decoder.Token()
+			return items, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	default:
+		return t, nil
+	}
+}
+
+// writeTOMLTable 输出一个表，先输出其中的值，再依次输出子表和表数组
+func writeTOMLTable(sb *strings.Builder, path []string, table *orderedTable, arrayItem bool) error {
+	var scalars, children []string
+	for _, key := range table.keys {
+		switch value := table.values[key].(type) {
+		case nil:
+		case *orderedTable:
+			children = append(children, key)
+		case []interface{}:
+			if isTableArray(value) {
+				children = append(children, key)
+			} else {
+				scalars = append(scalars, key)
+			}
+		default:
+			scalars = append(scalars, key)
+		}
+	}
+
+	switch {
+	case arrayItem:
+		sb.WriteString("\n[[" + tomlPath(path) + "]]\n")
+	case len(path) > 0 && (len(scalars) > 0 || len(children) == 0):
+		sb.WriteString("\n[" + tomlPath(path) + "]\n")
+	}
+
+	for _, key := range scalars {
+		value, err := tomlValue(table.values[key])
+		if err != nil {
+			return err
+		}
+		sb.WriteString(tomlKey(key) + " = " + value + "\n")
+	}
+
+	for _, key := range children {
+		childPath := append(append([]string(nil), path...), key)
+		switch value := table.values[key].(type) {
+		case *orderedTable:
+			if err := writeTOMLTable(sb, childPath, value, false); err != nil {
+				return err
+			}
+		case []interface{}:
+			for _, item := range value {
+				if err := writeTOMLTable(sb, childPath, item.(*orderedTable), true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isTableArray 判断数组是否应输出为表数组
+func isTableArray(items []interface{}) bool {
+	if len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if _, ok := item.(*orderedTable); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlValue 将标量或标量数组格式化为TOML值
+func tomlValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return tomlQuote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			part, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	default:
+		return "", fmt.Errorf("unsupported TOML value %T", value)
+	}
+}
+
+// tomlPath 将表路径格式化为点分隔的键
+func tomlPath(path []string) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = tomlKey(key)
+	}
+	return strings.Join(parts, ".")
+}
+
+// tomlKey 格式化键，只包含字母、数字、下划线和连字符的键无需加引号
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !isBareKeyChar(r) {
+			return tomlQuote(key)
+		}
+	}
+	return key
+}
+
+// tomlQuote 将字符串格式化为TOML基本字符串
+func tomlQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// isBareKeyChar 判断字符是否可以出现在不加引号的键中
+func isBareKeyChar(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-'
+}
+
+// tomlParser 逐行解析TOML内容
+type tomlParser struct {
+	line    []byte
+	lineNum int
+	col     int
+}
+
+// parseTOML 将TOML内容解析为由 map 和切片组成的值
+func parseTOML(content []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	current := root
+	p := &tomlParser{}
+
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		p.line = bytes.TrimRight(line, "\r")
+		p.lineNum++
+		p.col = 0
+
+		p.skipSpace()
+		if p.eol() {
+			continue
+		}
+
+		var err error
+		switch {
+		case bytes.HasPrefix(p.line[p.col:], []byte("[[")):
+			current, err = p.parseTableArrayHeader(root)
+		case p.line[p.col] == '[':
+			current, err = p.parseTableHeader(root)
+		default:
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// parseTableHeader 解析 [a.b] 并返回对应的表
+func (p *tomlParser) parseTableHeader(root map[string]interface{}) (map[string]interface{}, error) {
+	p.col++
+	keys, err := p.parseKeys(']')
+	if err != nil {
+		return nil, err
+	}
+	p.col++
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return p.descend(root, keys)
+}
+
+// parseTableArrayHeader 解析 [[a.b]]，在表数组末尾追加一个表并返回该表
+func (p *tomlParser) parseTableArrayHeader(root map[string]interface{}) (map[string]interface{}, error) {
+	p.col += 2
+	keys, err := p.parseKeys(']')
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(p.line[p.col:], []byte("]]")) {
+		return nil, p.errorf("expected ]]")
+	}
+	p.col += 2
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+
+	parent, err := p.descend(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := keys[len(keys)-1]
+	table := make(map[string]interface{})
+	switch existing := parent[last].(type) {
+	case nil:
+		parent[last] = []interface{}{table}
+	case []interface{}:
+		parent[last] = append(existing, table)
+	default:
+		return nil, p.errorf("key %q is already defined", last)
+	}
+	return table, nil
+}
+
+// parseKeyValue 解析 key = value 并写入当前表
+func (p *tomlParser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := p.parseKeys('=')
+	if err != nil {
+		return err
+	}
+	p.col++
+	p.skipSpace()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	if err := p.expectEnd(); err != nil {
+		return err
+	}
+
+	parent, err := p.descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("key %q is already defined", last)
+	}
+	parent[last] = value
+	return nil
+}
+
+// parseKeys 解析点分隔的键，直到遇到 end 字符
+func (p *tomlParser) parseKeys(end byte) ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		if p.eol() {
+			return nil, p.errorf("expected key")
+		}
+
+		var key string
+		switch p.line[p.col] {
+		case '"', '\'':
+			var err error
+			if key, err = p.parseString(); err != nil {
+				return nil, err
+			}
+		default:
+			start := p.col
+			for !p.eol() && isBareKeyChar(rune(p.line[p.col])) {
+				p.col++
+			}
+			if p.col == start {
+				return nil, p.errorf("invalid character %q in key", p.line[p.col])
+			}
+			key = string(p.line[start:p.col])
+		}
+		keys = append(keys, key)
+
+		p.skipSpace()
+		switch {
+		case p.eol():
+			return nil, p.errorf("expected %q", end)
+		case p.line[p.col] == '.':
+			p.col++
+		case p.line[p.col] == end:
+			return keys, nil
+		default:
+			return nil, p.errorf("unexpected character %q after key", p.line[p.col])
+		}
+	}
+}
+
+// parseValue 解析字符串、布尔值、数字或单行数组
+func (p *tomlParser) parseValue() (interface{}, error) {
+	if p.eol() {
+		return nil, p.errorf("expected value")
+	}
+
+	switch c := p.line[p.col]; {
+	case c == '"' || c == '\'':
+		if bytes.HasPrefix(p.line[p.col:], []byte(`"""`)) || bytes.HasPrefix(p.line[p.col:], []byte("'''")) {
+			return nil, p.errorf("multi-line strings are not supported")
+		}
+		return p.parseString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return nil, p.errorf("inline tables are not supported")
+	}
+
+	start := p.col
+	for !p.eol() && p.line[p.col] != ',' && p.line[p.col] != ']' && p.line[p.col] != '#' &&
+		p.line[p.col] != ' ' && p.line[p.col] != '\t' {
+		p.col++
+	}
+	word := string(p.line[start:p.col])
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	number := strings.ReplaceAll(word, "_", "")
+	if _, err := strconv.ParseFloat(number, 64); err == nil {
+		return json.Number(number), nil
+	}
+	p.col = start
+	return nil, p.errorf("invalid value %q", word)
+}
+
+// parseArray 解析单行数组
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.col++
+	items := []interface{}{}
+	for {
+		p.skipSpace()
+		if p.eol() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.line[p.col] == ']' {
+			p.col++
+			return items, nil
+		}
+
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		p.skipSpace()
+		if !p.eol() && p.line[p.col] == ',' {
+			p.col++
+		}
+	}
+}
+
+// parseString 解析基本字符串或字面量字符串
+func (p *tomlParser) parseString() (string, error) {
+	quote := p.line[p.col]
+	start := p.col
+	p.col++
+
+	var sb strings.Builder
+	for p.col < len(p.line) {
+		c := p.line[p.col]
+		switch {
+		case c == quote:
+			p.col++
+			return sb.String(), nil
+		case c == '\\' && quote == '"':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+			p.col++
+		}
+	}
+
+	p.col = start
+	return "", p.errorf("unterminated string")
+}
+
+// parseEscape 解析基本字符串中的转义序列
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	if p.col+1 >= len(p.line) {
+		return p.errorf("invalid escape sequence")
+	}
+
+	escapes := map[byte]string{'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", '"': `"`, '\\': `\`}
+	c := p.line[p.col+1]
+	if s, ok := escapes[c]; ok {
+		sb.WriteString(s)
+		p.col += 2
+		return nil
+	}
+
+	size := map[byte]int{'u': 4, 'U': 8}[c]
+	if size == 0 || p.col+2+size > len(p.line) {
+		return p.errorf("invalid escape sequence")
+	}
+	code, err := strconv.ParseUint(string(p.line[p.col+2:p.col+2+size]), 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return p.errorf("invalid escape sequence")
+	}
+	sb.WriteRune(rune(code))
+	p.col += 2 + size
+	return nil
+}
+
+// descend 从 table 开始按键逐级进入子表，不存在时创建，遇到表数组时进入其最后一个表
+func (p *tomlParser) descend(table map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			child := make(map[string]interface{})
+			table[key] = child
+			table = child
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			last, ok := next[len(next)-1].(map[string]interface{})
+			if !ok {
+				return nil, p.errorf("key %q is not a table", key)
+			}
+			table = last
+		default:
+			return nil, p.errorf("key %q is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// expectEnd 检查行的剩余部分只有空白和注释
+func (p *tomlParser) expectEnd() error {
+	p.skipSpace()
+	if !p.eol() {
+		return p.errorf("unexpected content %q", p.line[p.col:])
+	}
+	return nil
+}
+
+// skipSpace 跳过空白
+func (p *tomlParser) skipSpace() {
+	for p.col < len(p.line) && (p.line[p.col] == ' ' || p.line[p.col] == '\t') {
+		p.col++
+	}
+}
+
+// eol 判断是否已到达行尾或注释
+func (p *tomlParser) eol() bool {
+	return p.col >= len(p.line) || p.line[p.col] == '#'
+}
+
+// errorf 创建指向当前位置的解析错误
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return errors.NewParseError(errors.ErrInvalidConfigFormat, p.lineNum, p.col+1, fmt.Sprintf("toml: "+format, args...))
+}
@@ -0,0 +1,160 @@
+package parser
+
+import (
+	stderrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestSerializeToTOML(t *testing.T) {
+	p := NewConfigParser()
+	config, err := p.ParseFromString(`<configuration>
+  <packageSources>
+    <clear />
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="local" value="C:\packages &quot;x&quot;" />
+  </packageSources>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="user" />
+    </nuget.org>
+  </packageSourceCredentials>
+  <config>
+    <add key="globalPackagesFolder" value="/gp" />
+  </config>
+</configuration>`)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+
+	tomlString, err := p.SerializeToTOML(config)
+	if err != nil {
+		t.Fatalf("SerializeToTOML() error = %v", err)
+	}
+
+	expected := `[packageSources]
+clear = true
+
+[[packageSources.add]]
+key = "nuget.org"
+value = "https://api.nuget.org/v3/index.json"
+protocolVersion = "3"
+
+[[packageSources.add]]
+key = "local"
+value = "C:\\packages \"x\""
+
+[[packageSourceCredentials."nuget.org".add]]
+key = "Username"
+value = "user"
+
+[[config.add]]
+key = "globalPackagesFolder"
+value = "/gp"
+`
+	if tomlString != expected {
+		t.Errorf("SerializeToTOML() =\n%s\nwant\n%s", tomlString, expected)
+	}
+
+	parsed, err := p.ParseFromTOML([]byte(tomlString))
+	if err != nil {
+		t.Fatalf("ParseFromTOML() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, config) {
+		t.Errorf("TOML round trip mismatch:\n%+v\n%+v", parsed, config)
+	}
+}
+
+func TestParseFromTOML(t *testing.T) {
+	content := `# team NuGet settings
+[packageSources]
+[[packageSources.add]]
+key = 'nuget.org'   # literal string
+value = "https://api.nuget.org/v3/index.json"
+
+[config]
+add = [ ]
+
+[disabledPackageSources]
+add = []
+`
+	config, err := NewConfigParser().ParseFromTOML([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromTOML() error = %v", err)
+	}
+	if len(config.PackageSources.Add) != 1 || config.PackageSources.Add[0].Key != "nuget.org" {
+		t.Errorf("unexpected package sources: %+v", config.PackageSources)
+	}
+	if config.Config == nil || len(config.Config.Add) != 0 {
+		t.Errorf("unexpected config: %+v", config.Config)
+	}
+}
+
+func TestParseFromTOMLNonStringValues(t *testing.T) {
+	content := `[packageSources]
+clear = true
+
+[[packageSources.add]]
+key = "nuget.org"
+value = "https://api.nuget.org/v3/index.json"
+protocolVersion = 3
+allowInsecureConnections = false
+
+[[disabledPackageSources.add]]
+key = "nuget.org"
+value = true
+
+[[config.add]]
+key = "maxHttpRequestsPerSource"
+value = 1_6
+`
+	config, err := NewConfigParser().ParseFromTOML([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromTOML() error = %v", err)
+	}
+	if !config.PackageSources.IsCleared() {
+		t.Error("clear = true should still be read as a boolean")
+	}
+	source := config.PackageSources.Add[0]
+	if source.ProtocolVersion != "3" || source.AllowInsecureConnections != "false" {
+		t.Errorf("unexpected package source: %+v", source)
+	}
+	if disabled := config.DisabledPackageSources; disabled == nil || len(disabled.Add) != 1 || disabled.Add[0].Value != "true" {
+		t.Errorf("unexpected disabled sources: %+v", disabled)
+	}
+	if options := config.Config; options == nil || len(options.Add) != 1 || options.Add[0].Value != "16" {
+		t.Errorf("unexpected config: %+v", options)
+	}
+}
+
+func TestParseFromTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  error
+		wantLine int
+		wantCol  int
+	}{
+		{"空内容", "\n# only a comment\n", errors.ErrEmptyConfigFile, 0, 0},
+		{"未闭合的字符串", "[[packageSources.add]]\nkey = \"nuget.org\n", errors.ErrInvalidConfigFormat, 2, 7},
+		{"重复的键", "[[packageSources.add]]\nkey = \"a\"\nkey = \"b\"\n", errors.ErrInvalidConfigFormat, 3, 10},
+		{"内联表", "packageSources = {}\n", errors.ErrInvalidConfigFormat, 1, 18},
+		{"未知字段", "[[packageSources.source]]\nkey = \"a\"\n", errors.ErrInvalidConfigFormat, 0, 0},
+		{"没有包源", "[packageSources]\n", errors.ErrMissingRequiredElement, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigParser().ParseFromTOML([]byte(tt.content))
+			if !stderrors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFromTOML() error = %v, want %v", err, tt.wantErr)
+			}
+			var parseErr *errors.ParseError
+			if stderrors.As(err, &parseErr) && (parseErr.Line != tt.wantLine || parseErr.Position != tt.wantCol) {
+				t.Errorf("error position = %d:%d, want %d:%d (%v)", parseErr.Line, parseErr.Position, tt.wantLine, tt.wantCol, err)
+			}
+		})
+	}
+}
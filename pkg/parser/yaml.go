@@ -0,0 +1,503 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// YAML 表示与 JSON 表示使用相同的字段名，包源、配置选项等列表写作序列，例如：
+//
+//	packageSources:
+//	  clear: true
+//	  add:
+//	    - key: nuget.org
+//	      value: https://api.nuget.org/v3/index.json
+//	packageSourceCredentials:
+//	  nuget.org:
+//	    add:
+//	      - key: Username
+//	        value: user
+//
+// 解析时支持 YAML 块格式的常用子集：映射、序列、普通标量、单引号和双引号字符串、
+// 空的流式集合 [] 和 {} 以及注释，不支持锚点、别名、标签、多行标量和多文档。
+// 普通标量 true 和 false 只在 clear 中解析为布尔值，在其他字段中与数字一样按字符串读取，
+// 因此手写的 value: true 与 value: "true" 等价；null 和 ~ 解析为空值。
+
+// SerializeToYAML 将配置序列化为YAML字符串
+func (p *ConfigParser) SerializeToYAML(config *types.NuGetConfig) (string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to YAML: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := readJSONValue(decoder)
+	if err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to YAML: %w", err)
+	}
+	table, ok := root.(*orderedTable)
+	if !ok {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to YAML: unexpected JSON value %T", root)
+	}
+
+	var sb strings.Builder
+	if err := writeYAMLMapping(&sb, table, 0); err != nil {
+		return "", errors.Errorf(errors.CodeSerialization, "failed to marshal config to YAML: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// ParseFromYAML 从 SerializeToYAML 生成的YAML解析配置
+//
+// 与解析JSON时一样，包含未知字段或未定义任何包源时返回错误。
+// 语法错误返回的 *errors.ParseError 带有出错的行号和列号。
+func (p *ConfigParser) ParseFromYAML(content []byte) (*types.NuGetConfig, error) {
+	if err := p.checkInputSize(int64(len(content))); err != nil {
+		return nil, err
+	}
+	content, _, err := DecodeContent(content)
+	if err != nil {
+		return nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+
+	root, err := parseYAML(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(root) == 0 {
+		return nil, errors.ErrEmptyConfigFile
+	}
+
+	data, err := json.Marshal(scalarsToStrings(root, ""))
+	if err != nil {
+		return nil, errors.NewParseError(errors.ErrInvalidConfigFormat, 0, 0, err.Error())
+	}
+	return p.configFromJSON(data)
+}
+
+// writeYAMLMapping 以指定缩进输出映射
+func writeYAMLMapping(sb *strings.Builder, table *orderedTable, indent int) error {
+	for _, key := range table.keys {
+		sb.WriteString(strings.Repeat(" ", indent) + yamlString(key) + ":")
+		if err := writeYAMLValue(sb, table.values[key], indent+2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAMLSequence 以指定缩进输出序列，集合类型的项与 "- " 写在同一行
+func writeYAMLSequence(sb *strings.Builder, items []interface{}, indent int) error {
+	for _, item := range items {
+		prefix := strings.Repeat(" ", indent) + "-"
+
+		var nested strings.Builder
+		var err error
+		switch v := item.(type) {
+		case *orderedTable:
+			if len(v.keys) > 0 {
+				err = writeYAMLMapping(&nested, v, indent+2)
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				err = writeYAMLSequence(&nested, v, indent+2)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if nested.Len() > 0 {
+			sb.WriteString(prefix + " " + nested.String()[indent+2:])
+			continue
+		}
+		sb.WriteString(prefix)
+		if err := writeYAMLValue(sb, item, indent+2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAMLValue 输出键或序列项之后的值，非空的集合另起一行
+func writeYAMLValue(sb *strings.Builder, value interface{}, indent int) error {
+	switch v := value.(type) {
+	case *orderedTable:
+		if len(v.keys) == 0 {
+			sb.WriteString(" {}\n")
+			return nil
+		}
+		sb.WriteString("\n")
+		return writeYAMLMapping(sb, v, indent)
+	case []interface{}:
+		if len(v) == 0 {
+			sb.WriteString(" []\n")
+			return nil
+		}
+		sb.WriteString("\n")
+		return writeYAMLSequence(sb, v, indent)
+	case nil:
+		sb.WriteString(" null\n")
+	case string:
+		sb.WriteString(" " + yamlString(v) + "\n")
+	case bool:
+		sb.WriteString(" " + strconv.FormatBool(v) + "\n")
+	case json.Number:
+		sb.WriteString(" " + v.String() + "\n")
+	default:
+		return fmt.Errorf("unsupported YAML value %T", value)
+	}
+	return nil
+}
+
+// yamlString 格式化字符串，可能被误解为其他类型或包含特殊字符时使用双引号
+func yamlString(s string) string {
+	if isPlainYAML(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// isPlainYAML 判断字符串能否不加引号写作普通标量
+func isPlainYAML(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~", ".inf", "-.inf", "+.inf", ".nan":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return false
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])) {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) || strings.ContainsRune(",[]{}\"\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlLine YAML内容中去掉缩进和注释后的一行
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlParser 按缩进解析YAML内容
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML 将YAML内容解析为由 map 和切片组成的值，顶层必须是映射
+func parseYAML(content []byte) (map[string]interface{}, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(string(content), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return nil, yamlError(i+1, indent+1, "tabs are not allowed in indentation")
+		}
+
+		text = strings.TrimRight(stripYAMLComment(text), " \t")
+		if text == "" {
+			continue
+		}
+		if indent == 0 && (text == "---" || text == "...") {
+			if len(p.lines) > 0 && text == "---" {
+				return nil, yamlError(i+1, 1, "multiple documents are not supported")
+			}
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: indent, text: text})
+	}
+
+	if len(p.lines) == 0 {
+		return nil, errors.ErrEmptyConfigFile
+	}
+	first := p.lines[0]
+	if isYAMLSequenceItem(first.text) {
+		return nil, yamlError(first.num, first.indent+1, "top level must be a mapping")
+	}
+
+	root, err := p.parseMapping(first.indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		return nil, yamlError(line.num, line.indent+1, "unexpected indentation")
+	}
+	return root, nil
+}
+
+// parseBlock 解析从当前行开始、缩进为 indent 的映射或序列
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+// parseMapping 解析缩进为 indent 的映射
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	mapping := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, yamlError(line.num, line.indent+1, "unexpected indentation")
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, yamlError(line.num, line.indent+1, "expected a mapping key")
+		}
+
+		key, rest, restCol, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, yamlError(line.num, line.indent+1, fmt.Sprintf("key %q is already defined", key))
+		}
+		p.pos++
+
+		var value interface{}
+		if rest == "" {
+			value, err = p.parseNested(indent, true)
+		} else {
+			value, err = parseYAMLScalar(rest, line.num, restCol)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+	}
+	return mapping, nil
+}
+
+// parseSequence 解析缩进为 indent 的序列
+func (p *yamlParser) parseSequence(indent int) ([]interface{}, error) {
+	items := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isYAMLSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, yamlError(line.num, line.indent+1, "unexpected indentation")
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			item, err := p.parseNested(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		// 将 "- " 之后的内容视为缩进更深的一行，使同一项的后续行可以对齐到它
+		itemIndent := indent + len(line.text) - len(rest)
+		if !isYAMLSequenceItem(rest) && !isYAMLMappingEntry(rest) {
+			p.pos++
+			item, err := parseYAMLScalar(rest, line.num, itemIndent+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		p.lines[p.pos] = yamlLine{num: line.num, indent: itemIndent, text: rest}
+		item, err := p.parseBlock(itemIndent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseNested 解析键或序列项之后另起一行的值，没有更深缩进的内容时为空值
+//
+// sameIndentSequence 为 true 时，与父级缩进相同的序列也属于该值，如 "add:\n- key: a"。
+func (p *yamlParser) parseNested(parentIndent int, sameIndentSequence bool) (interface{}, error) {
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	switch {
+	case next.indent > parentIndent:
+		return p.parseBlock(next.indent)
+	case next.indent == parentIndent && sameIndentSequence && isYAMLSequenceItem(next.text):
+		return p.parseSequence(parentIndent)
+	}
+	return nil, nil
+}
+
+// isYAMLSequenceItem 判断一行是否为序列项
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// isYAMLMappingEntry 判断一行是否为 key: value 形式的映射项
+func isYAMLMappingEntry(text string) bool {
+	_, _, _, err := splitYAMLKey(yamlLine{text: text})
+	return err == nil
+}
+
+// splitYAMLKey 将映射项拆分为键和值，同时返回值所在的列号
+func splitYAMLKey(line yamlLine) (key, rest string, restCol int, err error) {
+	text := line.text
+	end := -1
+	if text[0] == '"' || text[0] == '\'' {
+		closing := quotedYAMLEnd(text)
+		if closing < 0 {
+			return "", "", 0, yamlError(line.num, line.indent+1, "unterminated string")
+		}
+		if closing+1 < len(text) && text[closing+1] == ':' {
+			end = closing + 1
+		}
+	} else {
+		for i := 0; i < len(text); i++ {
+			if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 || (end+1 < len(text) && text[end+1] != ' ') {
+		return "", "", 0, yamlError(line.num, line.indent+1, "expected key: value")
+	}
+
+	keyText := strings.TrimSpace(text[:end])
+	if keyText == "" {
+		return "", "", 0, yamlError(line.num, line.indent+1, "expected key")
+	}
+	keyValue, err := parseYAMLScalar(keyText, line.num, line.indent+1)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if key, err = yamlKeyString(keyValue, keyText); err != nil {
+		return "", "", 0, yamlError(line.num, line.indent+1, err.Error())
+	}
+
+	after := text[end+1:]
+	rest = strings.TrimLeft(after, " ")
+	return key, rest, line.indent + end + 2 + len(after) - len(rest), nil
+}
+
+// yamlKeyString 将解析后的键转换为字符串，普通标量形式的布尔值和空值按原文处理
+func yamlKeyString(value interface{}, text string) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool, nil:
+		return text, nil
+	}
+	return "", fmt.Errorf("invalid key %q", text)
+}
+
+// quotedYAMLEnd 返回以引号开头的字符串中闭合引号的位置，未闭合时返回 -1
+func quotedYAMLEnd(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote:
+			if quote == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// parseYAMLScalar 解析标量或空的流式集合，col 为其所在的列号
+func parseYAMLScalar(text string, line, col int) (interface{}, error) {
+	switch text[0] {
+	case '"', '\'':
+		end := quotedYAMLEnd(text)
+		if end < 0 {
+			return nil, yamlError(line, col, "unterminated string")
+		}
+		if end != len(text)-1 {
+			return nil, yamlError(line, col+end+1, fmt.Sprintf("unexpected content %q after string", text[end+1:]))
+		}
+		if text[0] == '\'' {
+			return strings.ReplaceAll(text[1:end], "''", "'"), nil
+		}
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, yamlError(line, col, "invalid escape sequence in string")
+		}
+		return s, nil
+	case '[', '{':
+		switch text {
+		case "[]":
+			return []interface{}{}, nil
+		case "{}":
+			return map[string]interface{}{}, nil
+		}
+		return nil, yamlError(line, col, "flow collections are not supported")
+	case '&', '*', '!', '|', '>':
+		return nil, yamlError(line, col, "anchors, aliases, tags and block scalars are not supported")
+	}
+
+	switch text {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	}
+	return text, nil
+}
+
+// stripYAMLComment 去掉行尾注释，引号中的 # 不算注释
+func stripYAMLComment(text string) string {
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		case (c == '"' || c == '\'') && (i == 0 || text[i-1] == ' '):
+			if end := quotedYAMLEnd(text[i:]); end > 0 {
+				i += end
+			}
+		}
+	}
+	return text
+}
+
+// yamlError 创建指向指定位置的解析错误
+func yamlError(line, col int, message string) error {
+	return errors.NewParseError(errors.ErrInvalidConfigFormat, line, col, "yaml: "+message)
+}
@@ -0,0 +1,183 @@
+package parser
+
+import (
+	stderrors "errors"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestSerializeToYAML(t *testing.T) {
+	p := NewConfigParser()
+	config, err := p.ParseFromString(`<configuration>
+  <packageSources>
+    <clear />
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="local" value="C:\packages &quot;x&quot;" />
+  </packageSources>
+  <packageSourceCredentials>
+    <nuget.org>
+      <add key="Username" value="user" />
+      <add key="ClearTextPassword" value="p@ss: #1" />
+    </nuget.org>
+  </packageSourceCredentials>
+  <config>
+    <add key="signatureValidationMode" value="true" />
+  </config>
+  <packageSourceMapping>
+    <packageSource key="nuget.org">
+      <package pattern="*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+
+	yamlString, err := p.SerializeToYAML(config)
+	if err != nil {
+		t.Fatalf("SerializeToYAML() error = %v", err)
+	}
+
+	expected := `packageSources:
+  clear: true
+  add:
+    - key: nuget.org
+      value: https://api.nuget.org/v3/index.json
+      protocolVersion: "3"
+    - key: local
+      value: "C:\\packages \"x\""
+packageSourceCredentials:
+  nuget.org:
+    add:
+      - key: Username
+        value: user
+      - key: ClearTextPassword
+        value: "p@ss: #1"
+config:
+  add:
+    - key: signatureValidationMode
+      value: "true"
+packageSourceMapping:
+  packageSource:
+    - key: nuget.org
+      package:
+        - pattern: "*"
+`
+	if yamlString != expected {
+		t.Errorf("SerializeToYAML() =\n%s\nwant\n%s", yamlString, expected)
+	}
+
+	parsed, err := p.ParseFromYAML([]byte(yamlString))
+	if err != nil {
+		t.Fatalf("ParseFromYAML() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, config) {
+		t.Errorf("YAML round trip mismatch:\n%+v\n%+v", parsed, config)
+	}
+}
+
+func TestParseFromYAML(t *testing.T) {
+	content := `---
+# team NuGet settings
+packageSources:
+  add:
+  - key: 'nuget.org'   # single-quoted string
+    value: "https://api.nuget.org/v3/index.json"
+    protocolVersion: 3
+  -
+    key: it's local
+    value: /packages # trailing comment
+config:
+  add: []
+disabledPackageSources: {}
+`
+	config, err := NewConfigParser().ParseFromYAML([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromYAML() error = %v", err)
+	}
+	sources := config.PackageSources.Add
+	if len(sources) != 2 || sources[0].Key != "nuget.org" || sources[0].ProtocolVersion != "3" ||
+		sources[1].Key != "it's local" || sources[1].Value != "/packages" {
+		t.Errorf("unexpected package sources: %+v", config.PackageSources)
+	}
+	if config.Config == nil || len(config.Config.Add) != 0 {
+		t.Errorf("unexpected config: %+v", config.Config)
+	}
+}
+
+func TestParseFromYAMLPlainScalars(t *testing.T) {
+	content := `packageSources:
+  clear: true
+  add:
+    - key: nuget.org
+      value: https://api.nuget.org/v3/index.json
+      protocolVersion: 3
+      allowInsecureConnections: false
+disabledPackageSources:
+  add:
+    - key: nuget.org
+      value: true
+config:
+  add:
+    - key: maxHttpRequestsPerSource
+      value: 16
+`
+	config, err := NewConfigParser().ParseFromYAML([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromYAML() error = %v", err)
+	}
+	if !config.PackageSources.IsCleared() {
+		t.Error("clear: true should still be read as a boolean")
+	}
+	source := config.PackageSources.Add[0]
+	if source.ProtocolVersion != "3" || source.AllowInsecureConnections != "false" {
+		t.Errorf("unexpected package source: %+v", source)
+	}
+	if disabled := config.DisabledPackageSources; disabled == nil || len(disabled.Add) != 1 || disabled.Add[0].Value != "true" {
+		t.Errorf("unexpected disabled sources: %+v", disabled)
+	}
+	if options := config.Config; options == nil || len(options.Add) != 1 || options.Add[0].Value != "16" {
+		t.Errorf("unexpected config: %+v", options)
+	}
+
+	// clear 只接受布尔值
+	if _, err := NewConfigParser().ParseFromYAML([]byte("packageSources:\n  clear: sometimes\n")); !stderrors.Is(err, errors.ErrInvalidConfigFormat) {
+		t.Errorf("ParseFromYAML() with a non-boolean clear error = %v", err)
+	}
+}
+
+func TestParseFromYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  error
+		wantLine int
+		wantCol  int
+	}{
+		{"空内容", "\n# only a comment\n", errors.ErrEmptyConfigFile, 0, 0},
+		{"未闭合的字符串", "packageSources:\n  add:\n    - key: \"nuget.org\n", errors.ErrInvalidConfigFormat, 3, 12},
+		{"重复的键", "packageSources: {}\npackageSources: {}\n", errors.ErrInvalidConfigFormat, 2, 1},
+		{"错误的缩进", "packageSources:\n  add: []\n    clear: true\n", errors.ErrInvalidConfigFormat, 3, 5},
+		{"制表符缩进", "packageSources:\n\tadd: []\n", errors.ErrInvalidConfigFormat, 2, 1},
+		{"流式集合", "packageSources: {add: []}\n", errors.ErrInvalidConfigFormat, 1, 17},
+		{"顶层为序列", "- key: a\n", errors.ErrInvalidConfigFormat, 1, 1},
+		{"多文档", "packageSources: {}\n---\nconfig: {}\n", errors.ErrInvalidConfigFormat, 2, 1},
+		{"未知字段", "packageSources:\n  source:\n    - key: a\n", errors.ErrInvalidConfigFormat, 0, 0},
+		{"没有包源", "packageSources: {}\n", errors.ErrMissingRequiredElement, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfigParser().ParseFromYAML([]byte(tt.content))
+			if !stderrors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFromYAML() error = %v, want %v", err, tt.wantErr)
+			}
+			var parseErr *errors.ParseError
+			if stderrors.As(err, &parseErr) && (parseErr.Line != tt.wantLine || parseErr.Position != tt.wantCol) {
+				t.Errorf("error position = %d:%d, want %d:%d (%v)", parseErr.Line, parseErr.Position, tt.wantLine, tt.wantCol, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// EffectivePackageSources 计算最终生效的已启用包源
+//
+// 包源和禁用包源均按 Settings 的合并规则计算（包括 <clear /> 语义），
+// 任意层级中被禁用的包源都会被排除，返回的顺序与合并后的包源顺序一致。
+func EffectivePackageSources(s Settings) []types.PackageSource {
+	section := s.GetSection(SectionPackageSources)
+	if section == nil {
+		return nil
+	}
+
+	disabled := make(map[string]bool)
+	if disabledSection := s.GetSection(SectionDisabledPackageSources); disabledSection != nil {
+		for _, item := range disabledSection.Items {
+			if strings.EqualFold(item.Value, "true") {
+				disabled[item.Key] = true
+			}
+		}
+	}
+
+	var sources []types.PackageSource
+	for _, item := range section.Items {
+		if disabled[item.Key] {
+			continue
+		}
+		sources = append(sources, types.PackageSource{
+			Key:                      item.Key,
+			Value:                    item.Value,
+			ProtocolVersion:          item.Attributes["protocolVersion"],
+			AllowInsecureConnections: item.Attributes["allowInsecureConnections"],
+		})
+	}
+
+	return sources
+}
+
+// CredentialLookup 表示在配置层级中查找到的包源凭证
+type CredentialLookup struct {
+	// SourceKey 包源名称
+	SourceKey string
+
+	// Credential 生效的凭证
+	Credential types.SourceCredential
+
+	// Origin 凭证所在的配置文件路径
+	Origin string
+}
+
+// FindCredential 在配置层级中查找指定包源生效的凭证
+//
+// 按优先级从高到低查找，第一个为该包源定义了凭证的配置文件胜出，
+// 不同文件中的凭证项不会相互合并。
+func (h *HierarchySettings) FindCredential(sourceKey string) (*CredentialLookup, bool) {
+	for _, file := range h.Files {
+		if file.Config == nil || file.Config.PackageSourceCredentials == nil {
+			continue
+		}
+
+		cred, exists := file.Config.PackageSourceCredentials.Sources[sourceKey]
+		if !exists {
+			continue
+		}
+
+		return &CredentialLookup{
+			SourceKey:  sourceKey,
+			Credential: cred,
+			Origin:     file.Path,
+		}, true
+	}
+
+	return nil, false
+}
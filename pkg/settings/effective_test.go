@@ -0,0 +1,164 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+)
+
+func TestEffectivePackageSources(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="project-feed" value="https://project.example.com/v3/index.json" protocolVersion="3" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="nuget.org" value="True" />
+  </disabledPackageSources>
+</configuration>`
+
+	userXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" protocolVersion="3" />
+    <add key="localSource" value="/packages" />
+    <add key="team" value="https://team.example.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="localSource" value="true" />
+    <add key="team" value="false" />
+  </disabledPackageSources>
+</configuration>`
+
+	projectPath := filepath.Join(tempDir, "project", constants.DefaultNuGetConfigFilename)
+	userPath := filepath.Join(tempDir, "user", constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, projectPath, projectXML)
+	nugetTesting.CreateNuGetConfigFile(t, userPath, userXML)
+
+	h, err := LoadHierarchySettings(projectPath, userPath)
+	if err != nil {
+		t.Fatalf("LoadHierarchySettings() error = %v", err)
+	}
+
+	sources := EffectivePackageSources(h)
+	wantKeys := []string{"project-feed", "team"}
+	if len(sources) != len(wantKeys) {
+		t.Fatalf("EffectivePackageSources() = %+v, want keys %v", sources, wantKeys)
+	}
+	for i, key := range wantKeys {
+		if sources[i].Key != key {
+			t.Errorf("sources[%d].Key = %q, want %q", i, sources[i].Key, key)
+		}
+	}
+	if sources[0].ProtocolVersion != "3" {
+		t.Errorf("sources[0].ProtocolVersion = %q, want %q", sources[0].ProtocolVersion, "3")
+	}
+
+	if got := EffectivePackageSources(NewHierarchySettings()); got != nil {
+		t.Errorf("EffectivePackageSources() on empty hierarchy = %+v, want nil", got)
+	}
+}
+
+func TestFindCredential(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="private" value="https://private.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <team>
+      <add key="Username" value="project-user" />
+    </team>
+  </packageSourceCredentials>
+</configuration>`
+
+	// 用户级配置只包含凭证
+	userXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSourceCredentials>
+    <private>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="secret" />
+    </private>
+    <team>
+      <add key="Username" value="user-level" />
+    </team>
+  </packageSourceCredentials>
+</configuration>`
+
+	projectPath := filepath.Join(tempDir, "project", constants.DefaultNuGetConfigFilename)
+	userPath := filepath.Join(tempDir, "user", constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, projectPath, projectXML)
+	nugetTesting.CreateNuGetConfigFile(t, userPath, userXML)
+
+	h, err := LoadHierarchySettings(projectPath, userPath)
+	if err != nil {
+		t.Fatalf("LoadHierarchySettings() error = %v", err)
+	}
+
+	lookup, ok := h.FindCredential("private")
+	if !ok {
+		t.Fatal("FindCredential(private) should find user-level credential")
+	}
+	if lookup.Origin != userPath {
+		t.Errorf("Origin = %q, want %q", lookup.Origin, userPath)
+	}
+	if len(lookup.Credential.Add) != 2 {
+		t.Errorf("Credential.Add = %+v, want 2 items", lookup.Credential.Add)
+	}
+
+	lookup, ok = h.FindCredential("team")
+	if !ok || lookup.Origin != projectPath || lookup.Credential.Add[0].Value != "project-user" {
+		t.Errorf("FindCredential(team) = %+v, want project-level credential", lookup)
+	}
+
+	if _, ok := h.FindCredential("missing"); ok {
+		t.Error("FindCredential(missing) should return false")
+	}
+}
+
+func TestDuplicateKeysFirstDefinitionWins(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	// 与 NuGet、parser 的重复键诊断和 manager.NormalizeSources 一致，以第一个定义为准
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, configPath, `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="a" value="https://first.example.com/v3/index.json" />
+    <add key="a" value="https://second.example.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <add key="http_proxy" value="http://first-proxy" />
+    <add key="http_proxy" value="http://second-proxy" />
+  </config>
+</configuration>`)
+
+	s, err := LoadFileSettings(configPath)
+	if err != nil {
+		t.Fatalf("LoadFileSettings() error = %v", err)
+	}
+	if value, _ := s.GetValue(SectionConfig, "http_proxy"); value != "http://first-proxy" {
+		t.Errorf("GetValue(config, http_proxy) = %q, want the first definition", value)
+	}
+
+	sources := EffectivePackageSources(s)
+	if len(sources) != 1 || sources[0].Value != "https://first.example.com/v3/index.json" {
+		t.Errorf("EffectivePackageSources() = %+v, want only the first definition", sources)
+	}
+
+	h := NewHierarchySettings(s)
+	if sources := EffectivePackageSources(h); len(sources) != 1 || sources[0].Value != "https://first.example.com/v3/index.json" {
+		t.Errorf("EffectivePackageSources(hierarchy) = %+v, want only the first definition", sources)
+	}
+}
@@ -0,0 +1,202 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// envOverrides 可被环境变量覆盖的 config 配置节键名及对应的环境变量
+var envOverrides = map[string]string{
+	"globalPackagesFolder": constants.EnvNuGetPackages,
+}
+
+// EnvironmentOverlay 在 Settings 之上叠加 NuGet 环境变量覆盖
+//
+// NuGet 会优先使用 NUGET_PACKAGES 等环境变量，而不是配置文件中的值。
+// EnvironmentOverlay 在读取 config 配置节时先查询这些环境变量，
+// 写入和移除操作则直接委托给底层的 Settings。
+type EnvironmentOverlay struct {
+	// Settings 底层配置
+	Settings Settings
+
+	// LookupEnv 查询环境变量的函数，默认为 os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+}
+
+var _ Settings = (*EnvironmentOverlay)(nil)
+
+// NewEnvironmentOverlay 创建叠加了环境变量覆盖的 Settings
+func NewEnvironmentOverlay(s Settings) *EnvironmentOverlay {
+	return &EnvironmentOverlay{
+		Settings:  s,
+		LookupEnv: os.LookupEnv,
+	}
+}
+
+// GetSection 获取配置节，config 配置节中的值会被环境变量覆盖
+func (o *EnvironmentOverlay) GetSection(name string) *Section {
+	sec := o.Settings.GetSection(name)
+	if name != SectionConfig {
+		return sec
+	}
+
+	overlaid := &Section{Name: name}
+	seen := make(map[string]bool)
+	if sec != nil {
+		overlaid.Cleared = sec.Cleared
+		for _, item := range sec.Items {
+			if value, ok := o.envValue(item.Key); ok {
+				item.Value = value
+			}
+			seen[item.Key] = true
+			overlaid.Items = append(overlaid.Items, item)
+		}
+	}
+
+	for key := range envOverrides {
+		if seen[key] {
+			continue
+		}
+		if value, ok := o.envValue(key); ok {
+			overlaid.Items = append(overlaid.Items, Item{Key: key, Value: value})
+		}
+	}
+
+	if sec == nil && len(overlaid.Items) == 0 {
+		return nil
+	}
+	return overlaid
+}
+
+// GetValue 获取配置值，config 配置节中的值优先取自环境变量
+func (o *EnvironmentOverlay) GetValue(section, key string) (string, bool) {
+	if section == SectionConfig {
+		if value, ok := o.envValue(key); ok {
+			return value, true
+		}
+	}
+
+	return o.Settings.GetValue(section, key)
+}
+
+// SetValue 在底层配置中设置值
+func (o *EnvironmentOverlay) SetValue(section, key, value string) error {
+	return o.Settings.SetValue(section, key, value)
+}
+
+// Remove 从底层配置中移除值
+func (o *EnvironmentOverlay) Remove(section, key string) bool {
+	return o.Settings.Remove(section, key)
+}
+
+// GetGlobalPackagesFolder 获取生效的全局包文件夹
+//
+// 依次检查 NUGET_PACKAGES 环境变量、配置中的 globalPackagesFolder，
+// 都未设置时返回平台默认位置 ~/.nuget/packages。
+// 配置值中的 %VAR%、$VAR 和 ${VAR} 会被展开，未定义的变量保持原样；
+// 展开后仍是相对路径时，按定义该值的配置文件所在目录解析。
+func (o *EnvironmentOverlay) GetGlobalPackagesFolder() string {
+	if value, ok := o.envValue("globalPackagesFolder"); ok {
+		return value
+	}
+
+	if value, ok := o.Settings.GetValue(SectionConfig, "globalPackagesFolder"); ok && value != "" {
+		value = o.expandEnv(value)
+		if origin := valueOrigin(o.Settings, SectionConfig, "globalPackagesFolder"); origin != "" && !filepath.IsAbs(value) {
+			value = filepath.Join(filepath.Dir(origin), value)
+		}
+		return filepath.Clean(value)
+	}
+
+	return constants.GetDefaultGlobalPackagesFolder()
+}
+
+// GetHTTPCachePath 获取生效的HTTP缓存路径
+//
+// 设置了 NUGET_HTTP_CACHE_PATH 时使用该值，否则返回平台默认位置。
+func (o *EnvironmentOverlay) GetHTTPCachePath() string {
+	if value, ok := o.lookup(constants.EnvNuGetHTTPCachePath); ok {
+		return value
+	}
+
+	return constants.GetDefaultHTTPCachePath()
+}
+
+// GetPluginPaths 获取 NUGET_PLUGIN_PATHS 中指定的插件路径，未设置时返回 nil
+func (o *EnvironmentOverlay) GetPluginPaths() []string {
+	value, ok := o.lookup(constants.EnvNuGetPluginPaths)
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range strings.Split(value, string(filepath.ListSeparator)) {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// envValue 查询覆盖指定 config 键的环境变量
+func (o *EnvironmentOverlay) envValue(key string) (string, bool) {
+	envName, exists := envOverrides[key]
+	if !exists {
+		return "", false
+	}
+
+	return o.lookup(envName)
+}
+
+// expandEnv 展开值中的 %VAR%、$VAR 和 ${VAR} 形式的环境变量，未定义的变量保持原样
+func (o *EnvironmentOverlay) expandEnv(value string) string {
+	return utils.ExpandEnvVarsWithLookup(value, o.lookup)
+}
+
+// valueOrigin 返回定义了指定配置值的配置文件路径，无法确定时返回空字符串
+func valueOrigin(s Settings, section, key string) string {
+	var files []*FileSettings
+	switch s := s.(type) {
+	case *FileSettings:
+		files = []*FileSettings{s}
+	case *HierarchySettings:
+		files = s.Files
+	}
+
+	for _, file := range files {
+		sec := file.GetSection(section)
+		if sec == nil {
+			continue
+		}
+		for _, item := range sec.Items {
+			if item.Key == key {
+				return file.Path
+			}
+		}
+		if sec.Cleared {
+			break
+		}
+	}
+
+	return ""
+}
+
+// lookup 查询非空的环境变量值
+func (o *EnvironmentOverlay) lookup(name string) (string, bool) {
+	lookupEnv := o.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	value, ok := lookupEnv(name)
+	if !ok || value == "" {
+		return "", false
+	}
+
+	return value, true
+}
@@ -0,0 +1,120 @@
+package settings
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// fakeEnv 返回基于映射表的环境变量查询函数
+func fakeEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestEnvironmentOverlay(t *testing.T) {
+	config := &types.NuGetConfig{
+		Config: &types.Config{
+			Add: []types.ConfigOption{
+				{Key: "globalPackagesFolder", Value: "/config/packages"},
+				{Key: "dependencyVersion", Value: "Highest"},
+			},
+		},
+	}
+	base := NewFileSettings("NuGet.Config", config)
+
+	// 未设置环境变量时使用配置值
+	overlay := NewEnvironmentOverlay(base)
+	overlay.LookupEnv = fakeEnv(nil)
+	if got := overlay.GetGlobalPackagesFolder(); got != "/config/packages" {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want config value", got)
+	}
+	if got := overlay.GetHTTPCachePath(); got != constants.GetDefaultHTTPCachePath() {
+		t.Errorf("GetHTTPCachePath() = %q, want default", got)
+	}
+	if got := overlay.GetPluginPaths(); got != nil {
+		t.Errorf("GetPluginPaths() = %v, want nil", got)
+	}
+
+	// 环境变量优先于配置值
+	pluginPaths := "/plugins/a" + string(filepath.ListSeparator) + "/plugins/b"
+	overlay.LookupEnv = fakeEnv(map[string]string{
+		constants.EnvNuGetPackages:      "/env/packages",
+		constants.EnvNuGetHTTPCachePath: "/env/http-cache",
+		constants.EnvNuGetPluginPaths:   pluginPaths,
+	})
+	if got := overlay.GetGlobalPackagesFolder(); got != "/env/packages" {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want env value", got)
+	}
+	if got := overlay.GetHTTPCachePath(); got != "/env/http-cache" {
+		t.Errorf("GetHTTPCachePath() = %q, want env value", got)
+	}
+	if got := overlay.GetPluginPaths(); !reflect.DeepEqual(got, []string{"/plugins/a", "/plugins/b"}) {
+		t.Errorf("GetPluginPaths() = %v", got)
+	}
+
+	sec := overlay.GetSection(SectionConfig)
+	if sec == nil || len(sec.Items) != 2 || sec.Items[0].Value != "/env/packages" {
+		t.Errorf("GetSection(config) = %+v, want overlaid globalPackagesFolder", sec)
+	}
+
+	// 写入委托给底层配置，不影响环境变量优先级
+	if err := overlay.SetValue(SectionConfig, "globalPackagesFolder", "/new/packages"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	if value, _ := base.GetValue(SectionConfig, "globalPackagesFolder"); value != "/new/packages" {
+		t.Errorf("base globalPackagesFolder = %q, want %q", value, "/new/packages")
+	}
+
+	// 都未设置时使用平台默认值
+	empty := NewEnvironmentOverlay(NewFileSettings("NuGet.Config", &types.NuGetConfig{}))
+	empty.LookupEnv = fakeEnv(nil)
+	if got := empty.GetGlobalPackagesFolder(); got != constants.GetDefaultGlobalPackagesFolder() {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want default", got)
+	}
+	if empty.GetSection(SectionConfig) != nil {
+		t.Error("GetSection(config) should be nil without config and env overrides")
+	}
+}
+
+func TestGetGlobalPackagesFolderExpandsConfigValue(t *testing.T) {
+	configDir := filepath.Join(string(filepath.Separator), "repo")
+	newOverlay := func(value string) *EnvironmentOverlay {
+		config := &types.NuGetConfig{
+			Config: &types.Config{Add: []types.ConfigOption{{Key: "globalPackagesFolder", Value: value}}},
+		}
+		file := NewFileSettings(filepath.Join(configDir, "NuGet.Config"), config)
+		overlay := NewEnvironmentOverlay(NewHierarchySettings(file))
+		overlay.LookupEnv = fakeEnv(map[string]string{"ROOT": "/data", "HOME": "/home/user"})
+		return overlay
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"%ROOT%/packages", "/data/packages"},
+		{"$HOME/.nuget/custom", "/home/user/.nuget/custom"},
+		{"${ROOT}/pkgs", "/data/pkgs"},
+		{"packages", filepath.Join(configDir, "packages")},
+		{"%MISSING%/packages", filepath.Join(configDir, "%MISSING%", "packages")},
+		{"/abs/./packages/", "/abs/packages"},
+	}
+	for _, tt := range tests {
+		if got := newOverlay(tt.value).GetGlobalPackagesFolder(); got != filepath.FromSlash(tt.want) {
+			t.Errorf("GetGlobalPackagesFolder() with %q = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	// NUGET_PACKAGES 优先于配置值
+	overlay := newOverlay("%ROOT%/packages")
+	overlay.LookupEnv = fakeEnv(map[string]string{constants.EnvNuGetPackages: "/env/packages", "ROOT": "/data"})
+	if got := overlay.GetGlobalPackagesFolder(); got != "/env/packages" {
+		t.Errorf("GetGlobalPackagesFolder() = %q, want env value", got)
+	}
+}
@@ -0,0 +1,193 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// EntryStatus 表示配置项在层级合并后的状态
+type EntryStatus string
+
+const (
+	// EntryEffective 配置项最终生效
+	EntryEffective EntryStatus = "effective"
+
+	// EntryOverridden 配置项被更高优先级文件中的同名配置项覆盖
+	EntryOverridden EntryStatus = "overridden"
+
+	// EntryCleared 配置项被更高优先级文件中的 <clear /> 清除
+	EntryCleared EntryStatus = "cleared"
+)
+
+// ReportEntry 表示某个配置文件贡献的一个配置项
+type ReportEntry struct {
+	// Section 配置节名称
+	Section string
+
+	// Key 配置项键名
+	Key string
+
+	// Value 配置项的值，凭证和配置选项中的密码等敏感值为 types.RedactedValue
+	Value string
+
+	// Status 配置项在合并后的状态
+	Status EntryStatus
+
+	// ShadowedBy 覆盖或清除该配置项的配置文件路径，生效时为空
+	ShadowedBy string
+}
+
+// FileReport 表示单个配置文件在层级中的贡献
+type FileReport struct {
+	// Path 配置文件路径
+	Path string
+
+	// Entries 该文件中定义的所有配置项
+	Entries []ReportEntry
+}
+
+// ConfigChainReport 描述配置层级中每个文件的贡献以及被覆盖的情况
+type ConfigChainReport struct {
+	// Files 按优先级从高到低排列的配置文件报告
+	Files []FileReport
+}
+
+// reportSections 报告中按顺序列出的普通配置节
+var reportSections = []string{
+	SectionPackageSources,
+	SectionDisabledPackageSources,
+	SectionActivePackageSource,
+	SectionConfig,
+}
+
+// Explain 生成配置层级的解释报告
+//
+// 对于 packageSources、disabledPackageSources 和 config，同名键以优先级高的文件为准；
+// activePackageSource 和每个包源的凭证则整体由优先级最高的定义生效。
+// 报告用于日志和问题排查，凭证和配置选项中 types.IsSecretKey 判定为敏感的值会被替换为 types.RedactedValue。
+func (h *HierarchySettings) Explain() *ConfigChainReport {
+	report := &ConfigChainReport{
+		Files: make([]FileReport, len(h.Files)),
+	}
+	for i, file := range h.Files {
+		report.Files[i].Path = file.Path
+	}
+
+	for _, name := range reportSections {
+		h.explainSection(report, name, name == SectionActivePackageSource)
+	}
+
+	for _, name := range h.credentialSectionNames() {
+		h.explainSection(report, name, true)
+	}
+
+	return report
+}
+
+// explainSection 计算单个配置节在每个文件中的配置项状态
+//
+// whole 为 true 时整个配置节作为一个整体参与覆盖，而不是按键覆盖。
+func (h *HierarchySettings) explainSection(report *ConfigChainReport, name string, whole bool) {
+	winners := make(map[string]string)
+	wholeWinner := ""
+	clearedBy := ""
+
+	for i, file := range h.Files {
+		sec := file.GetSection(name)
+		if sec == nil {
+			continue
+		}
+
+		for _, item := range sec.Items {
+			entry := ReportEntry{
+				Section: name,
+				Key:     item.Key,
+				Value:   reportValue(name, item.Key, item.Value),
+				Status:  EntryEffective,
+			}
+
+			switch {
+			case clearedBy != "":
+				entry.Status = EntryCleared
+				entry.ShadowedBy = clearedBy
+			case whole && wholeWinner != "":
+				entry.Status = EntryOverridden
+				entry.ShadowedBy = wholeWinner
+			case !whole && winners[item.Key] != "":
+				entry.Status = EntryOverridden
+				entry.ShadowedBy = winners[item.Key]
+			case !whole:
+				winners[item.Key] = file.Path
+			}
+
+			report.Files[i].Entries = append(report.Files[i].Entries, entry)
+		}
+
+		if whole && wholeWinner == "" && clearedBy == "" && len(sec.Items) > 0 {
+			wholeWinner = file.Path
+		}
+		if sec.Cleared && clearedBy == "" {
+			clearedBy = file.Path
+		}
+	}
+}
+
+// reportValue 返回报告中显示的值，凭证和配置选项中的敏感值替换为 types.RedactedValue
+func reportValue(section, key, value string) string {
+	secretSection := section == SectionConfig || strings.HasPrefix(section, SectionPackageSourceCredentials+"/")
+	if secretSection && value != "" && types.IsSecretKey(key) {
+		return types.RedactedValue
+	}
+	return value
+}
+
+// credentialSectionNames 返回层级中所有包源凭证配置节的名称，按名称排序
+func (h *HierarchySettings) credentialSectionNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, file := range h.Files {
+		if file.Config == nil || file.Config.PackageSourceCredentials == nil {
+			continue
+		}
+		for source := range file.Config.PackageSourceCredentials.Sources {
+			name := SectionPackageSourceCredentials + "/" + source
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// String 以可读的文本形式输出报告
+func (r *ConfigChainReport) String() string {
+	var sb strings.Builder
+
+	for i, file := range r.Files {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, file.Path)
+		if len(file.Entries) == 0 {
+			sb.WriteString("   (no settings)\n")
+			continue
+		}
+
+		for _, entry := range file.Entries {
+			fmt.Fprintf(&sb, "   [%s] %s = %s", entry.Section, entry.Key, entry.Value)
+			switch entry.Status {
+			case EntryOverridden:
+				fmt.Fprintf(&sb, " (overridden by %s)", entry.ShadowedBy)
+			case EntryCleared:
+				fmt.Fprintf(&sb, " (cleared by %s)", entry.ShadowedBy)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+func TestExplain(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	projectXML := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <clear />
+    <add key="dependencyVersion" value="Highest" />
+    <add key="http_proxy.password" value="proxy-secret" />
+  </config>
+</configuration>`
+
+	projectPath := filepath.Join(tempDir, "project", constants.DefaultNuGetConfigFilename)
+	userPath := filepath.Join(tempDir, "user", constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, projectPath, projectXML)
+	nugetTesting.CreateNuGetConfigFile(t, userPath, nugetTesting.ValidNuGetConfig())
+
+	h, err := LoadHierarchySettings(projectPath, userPath)
+	if err != nil {
+		t.Fatalf("LoadHierarchySettings() error = %v", err)
+	}
+
+	report := h.Explain()
+	if len(report.Files) != 2 {
+		t.Fatalf("len(report.Files) = %d, want 2", len(report.Files))
+	}
+
+	find := func(file FileReport, section, key string) *ReportEntry {
+		for i, entry := range file.Entries {
+			if entry.Section == section && entry.Key == key {
+				return &file.Entries[i]
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		file       int
+		section    string
+		key        string
+		status     EntryStatus
+		shadowedBy string
+	}{
+		{0, SectionPackageSources, "nuget.org", EntryEffective, ""},
+		{0, SectionConfig, "dependencyVersion", EntryEffective, ""},
+		{1, SectionPackageSources, "nuget.org", EntryOverridden, projectPath},
+		{1, SectionPackageSources, "localSource", EntryEffective, ""},
+		{1, SectionConfig, "globalPackagesFolder", EntryCleared, projectPath},
+		{1, SectionActivePackageSource, "nuget.org", EntryEffective, ""},
+		{1, SectionPackageSourceCredentials + "/nuget.org", "Username", EntryEffective, ""},
+	}
+
+	for _, tt := range tests {
+		entry := find(report.Files[tt.file], tt.section, tt.key)
+		if entry == nil {
+			t.Errorf("entry %s/%s not found in file %d", tt.section, tt.key, tt.file)
+			continue
+		}
+		if entry.Status != tt.status || entry.ShadowedBy != tt.shadowedBy {
+			t.Errorf("entry %s/%s in file %d = %s by %q, want %s by %q",
+				tt.section, tt.key, tt.file, entry.Status, entry.ShadowedBy, tt.status, tt.shadowedBy)
+		}
+	}
+
+	text := report.String()
+	if !strings.Contains(text, "(overridden by "+projectPath+")") {
+		t.Errorf("report text should mention overridden entries:\n%s", text)
+	}
+	if !strings.Contains(text, "(cleared by "+projectPath+")") {
+		t.Errorf("report text should mention cleared entries:\n%s", text)
+	}
+
+	// 密码等敏感值不能出现在报告中
+	if entry := find(report.Files[1], SectionPackageSourceCredentials+"/nuget.org", "ClearTextPassword"); entry == nil || entry.Value != types.RedactedValue {
+		t.Errorf("ClearTextPassword entry = %+v, want a redacted value", entry)
+	}
+	if entry := find(report.Files[0], SectionConfig, "http_proxy.password"); entry == nil || entry.Value != types.RedactedValue {
+		t.Errorf("http_proxy.password entry = %+v, want a redacted value", entry)
+	}
+	if entry := find(report.Files[1], SectionPackageSourceCredentials+"/nuget.org", "Username"); entry == nil || entry.Value == types.RedactedValue {
+		t.Errorf("Username entry = %+v, want the username", entry)
+	}
+	if strings.Contains(text, "testpass") || strings.Contains(text, "proxy-secret") {
+		t.Errorf("report text leaks secrets:\n%s", text)
+	}
+}
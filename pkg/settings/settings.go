@@ -0,0 +1,527 @@
+// Package settings 提供对单个或多个 NuGet 配置文件的统一抽象访问
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const (
+	// SectionPackageSources 包源配置节
+	SectionPackageSources = "packageSources"
+
+	// SectionDisabledPackageSources 禁用包源配置节
+	SectionDisabledPackageSources = "disabledPackageSources"
+
+	// SectionActivePackageSource 活跃包源配置节
+	SectionActivePackageSource = "activePackageSource"
+
+	// SectionConfig 全局配置选项节
+	SectionConfig = "config"
+
+	// SectionPackageSourceCredentials 包源凭证配置节，
+	// 访问某个包源的凭证时使用 "packageSourceCredentials/<源名称>" 形式的节名
+	SectionPackageSourceCredentials = "packageSourceCredentials"
+)
+
+// Item 表示配置节中的一个键值项
+type Item struct {
+	// Key 配置项键名
+	Key string
+
+	// Value 配置项的值
+	Value string
+
+	// Attributes 除 key 和 value 以外的其他属性，如 protocolVersion
+	Attributes map[string]string
+}
+
+// Section 表示一个配置节及其包含的配置项
+type Section struct {
+	// Name 配置节名称
+	Name string
+
+	// Items 配置节中的配置项，保持文件中的顺序
+	Items []Item
+
+	// Cleared 配置节是否包含 <clear />，为 true 时不再继承更低优先级文件中的配置项
+	Cleared bool
+}
+
+// Settings 是 NuGet 配置的抽象接口
+//
+// Settings 既可以由单个配置文件支撑（FileSettings），也可以由按优先级排列的
+// 配置文件层级支撑（HierarchySettings），使下游工具无需关心具体的配置结构体。
+type Settings interface {
+	// GetSection 获取指定名称的配置节，不存在时返回 nil
+	GetSection(name string) *Section
+
+	// GetValue 获取指定配置节中键对应的值，第二个返回值表示该键是否存在
+	GetValue(section, key string) (string, bool)
+
+	// SetValue 设置指定配置节中键对应的值，键不存在时新增
+	SetValue(section, key, value string) error
+
+	// Remove 移除指定配置节中的键，返回是否确实移除了配置项
+	Remove(section, key string) bool
+}
+
+var (
+	_ Settings = (*FileSettings)(nil)
+	_ Settings = (*HierarchySettings)(nil)
+)
+
+// FileSettings 由单个配置文件支撑的 Settings 实现
+type FileSettings struct {
+	// Path 配置文件路径
+	Path string
+
+	// Config 配置文件解析后的配置对象
+	Config *types.NuGetConfig
+
+	dirty bool
+}
+
+// NewFileSettings 基于已解析的配置对象创建 FileSettings
+func NewFileSettings(path string, config *types.NuGetConfig) *FileSettings {
+	return &FileSettings{
+		Path:   path,
+		Config: config,
+	}
+}
+
+// LoadFileSettings 从文件加载 FileSettings
+//
+// 与直接解析不同，这里允许配置文件不包含任何包源，
+// 因为层级中的用户级配置文件往往只保存凭证等信息。
+func LoadFileSettings(path string) (*FileSettings, error) {
+	p := parser.NewConfigParser()
+	p.AllowEmptyPackageSources = true
+
+	config, err := p.ParseFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileSettings(path, config), nil
+}
+
+// GetSection 获取指定名称的配置节
+//
+// 同一配置节中 key 重复时与 NuGet 一样只保留第一个定义。
+func (s *FileSettings) GetSection(name string) *Section {
+	return sectionFromConfig(s.Config, name)
+}
+
+// GetValue 获取指定配置节中键对应的值
+func (s *FileSettings) GetValue(section, key string) (string, bool) {
+	sec := s.GetSection(section)
+	if sec == nil {
+		return "", false
+	}
+
+	for _, item := range sec.Items {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SetValue 设置指定配置节中键对应的值
+func (s *FileSettings) SetValue(section, key, value string) error {
+	if err := setValueInConfig(s.Config, section, key, value); err != nil {
+		return err
+	}
+
+	s.dirty = true
+	return nil
+}
+
+// Remove 移除指定配置节中的键
+func (s *FileSettings) Remove(section, key string) bool {
+	if !removeValueFromConfig(s.Config, section, key) {
+		return false
+	}
+
+	s.dirty = true
+	return true
+}
+
+// IsDirty 返回自加载或上次保存以来配置是否被修改过
+func (s *FileSettings) IsDirty() bool {
+	return s.dirty
+}
+
+// Save 将配置写回文件
+func (s *FileSettings) Save() error {
+	if err := parser.NewConfigParser().SaveToFile(s.Config, s.Path); err != nil {
+		return err
+	}
+
+	s.dirty = false
+	return nil
+}
+
+// HierarchySettings 由多个配置文件组成的层级 Settings 实现
+//
+// Files 按优先级从高到低排列，即离项目最近的配置文件在前，
+// 机器级配置文件在后。读取时优先级高的文件中的值生效，
+// 写入时修改优先级最高的文件。
+type HierarchySettings struct {
+	// Files 按优先级从高到低排列的配置文件
+	Files []*FileSettings
+}
+
+// NewHierarchySettings 基于按优先级从高到低排列的 FileSettings 创建层级配置
+func NewHierarchySettings(files ...*FileSettings) *HierarchySettings {
+	return &HierarchySettings{
+		Files: files,
+	}
+}
+
+// LoadHierarchySettings 按优先级从高到低的路径列表加载层级配置
+func LoadHierarchySettings(paths ...string) (*HierarchySettings, error) {
+	files := make([]*FileSettings, 0, len(paths))
+	for _, path := range paths {
+		file, err := LoadFileSettings(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load settings from %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+
+	return NewHierarchySettings(files...), nil
+}
+
+// GetSection 获取合并后的配置节
+//
+// 合并时同名键以优先级最高的文件为准，配置项顺序为优先级高的文件在前。
+// 如果某个文件中的配置节包含 <clear />，则忽略所有更低优先级文件中的该配置节。
+func (h *HierarchySettings) GetSection(name string) *Section {
+	var merged *Section
+	seen := make(map[string]bool)
+
+	for _, file := range h.Files {
+		sec := file.GetSection(name)
+		if sec == nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = &Section{Name: name}
+		}
+
+		for _, item := range sec.Items {
+			if seen[item.Key] {
+				continue
+			}
+			seen[item.Key] = true
+			merged.Items = append(merged.Items, item)
+		}
+
+		if sec.Cleared {
+			merged.Cleared = true
+			break
+		}
+	}
+
+	return merged
+}
+
+// GetValue 获取指定配置节中键对应的值，以优先级最高的文件为准
+//
+// 遇到包含 <clear /> 的配置节后不再查找更低优先级的文件。
+func (h *HierarchySettings) GetValue(section, key string) (string, bool) {
+	for _, file := range h.Files {
+		sec := file.GetSection(section)
+		if sec == nil {
+			continue
+		}
+
+		for _, item := range sec.Items {
+			if item.Key == key {
+				return item.Value, true
+			}
+		}
+
+		if sec.Cleared {
+			break
+		}
+	}
+
+	return "", false
+}
+
+// SetValue 在优先级最高的配置文件中设置值
+func (h *HierarchySettings) SetValue(section, key, value string) error {
+	if len(h.Files) == 0 {
+		return fmt.Errorf("no config file available to write setting '%s'", key)
+	}
+
+	return h.Files[0].SetValue(section, key, value)
+}
+
+// Remove 从优先级最高的配置文件中移除指定的键
+//
+// 与 SetValue 一样只修改 Files[0]，其他层级中的同名键不受影响，移除后 GetValue
+// 可能返回较低层级中的值。需要从所有层级移除时使用 RemoveFromAllLevels。
+func (h *HierarchySettings) Remove(section, key string) bool {
+	if len(h.Files) == 0 {
+		return false
+	}
+
+	return h.Files[0].Remove(section, key)
+}
+
+// RemoveFromAllLevels 从层级中所有配置文件移除指定的键，返回是否有文件确实移除了配置项
+func (h *HierarchySettings) RemoveFromAllLevels(section, key string) bool {
+	removed := false
+	for _, file := range h.Files {
+		if file.Remove(section, key) {
+			removed = true
+		}
+	}
+
+	return removed
+}
+
+// Save 保存层级中所有被修改过的配置文件
+func (h *HierarchySettings) Save() error {
+	for _, file := range h.Files {
+		if !file.IsDirty() {
+			continue
+		}
+		if err := file.Save(); err != nil {
+			return fmt.Errorf("failed to save settings to %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// splitSectionName 拆分 "packageSourceCredentials/<源名称>" 形式的节名
+func splitSectionName(name string) (string, string) {
+	if idx := strings.Index(name, "/"); idx >= 0 {
+		return name[:idx], name[idx+1:]
+	}
+	return name, ""
+}
+
+// sectionFromConfig 将配置对象中的指定部分转换为 Section
+func sectionFromConfig(config *types.NuGetConfig, name string) *Section {
+	if config == nil {
+		return nil
+	}
+
+	sectionName, sub := splitSectionName(name)
+	section := &Section{Name: name}
+
+	switch sectionName {
+	case SectionPackageSources:
+		if sub != "" {
+			return nil
+		}
+		section.Cleared = config.PackageSources.IsCleared()
+		for _, source := range config.PackageSources.Add {
+			item := Item{Key: source.Key, Value: source.Value}
+			if source.ProtocolVersion != "" {
+				item.Attributes = map[string]string{"protocolVersion": source.ProtocolVersion}
+			}
+			section.Items = append(section.Items, item)
+		}
+	case SectionDisabledPackageSources:
+		if sub != "" || config.DisabledPackageSources == nil {
+			return nil
+		}
+		section.Cleared = config.DisabledPackageSources.IsCleared()
+		for _, source := range config.DisabledPackageSources.Add {
+			section.Items = append(section.Items, Item{Key: source.Key, Value: source.Value})
+		}
+	case SectionActivePackageSource:
+		if sub != "" || config.ActivePackageSource == nil {
+			return nil
+		}
+		section.Items = append(section.Items, Item{
+			Key:   config.ActivePackageSource.Add.Key,
+			Value: config.ActivePackageSource.Add.Value,
+		})
+	case SectionConfig:
+		if sub != "" || config.Config == nil {
+			return nil
+		}
+		section.Cleared = config.Config.IsCleared()
+		for _, option := range config.Config.Add {
+			section.Items = append(section.Items, Item{Key: option.Key, Value: option.Value})
+		}
+	case SectionPackageSourceCredentials:
+		if sub == "" || config.PackageSourceCredentials == nil {
+			return nil
+		}
+		cred, exists := config.PackageSourceCredentials.Sources[sub]
+		if !exists {
+			return nil
+		}
+		for _, add := range cred.Add {
+			section.Items = append(section.Items, Item{Key: add.Key, Value: add.Value})
+		}
+	default:
+		return nil
+	}
+
+	section.Items = firstDefinitions(section.Items)
+	return section
+}
+
+// setValueInConfig 在配置对象的指定部分设置键值
+func setValueInConfig(config *types.NuGetConfig, name, key, value string) error {
+	if config == nil {
+		return fmt.Errorf("config is nil")
+	}
+
+	sectionName, sub := splitSectionName(name)
+
+	switch {
+	case sectionName == SectionPackageSources && sub == "":
+		for i, source := range config.PackageSources.Add {
+			if source.Key == key {
+				config.PackageSources.Add[i].Value = value
+				return nil
+			}
+		}
+		config.PackageSources.Add = append(config.PackageSources.Add, types.PackageSource{Key: key, Value: value})
+	case sectionName == SectionDisabledPackageSources && sub == "":
+		if config.DisabledPackageSources == nil {
+			config.DisabledPackageSources = &types.DisabledPackageSources{}
+		}
+		for i, source := range config.DisabledPackageSources.Add {
+			if source.Key == key {
+				config.DisabledPackageSources.Add[i].Value = value
+				return nil
+			}
+		}
+		config.DisabledPackageSources.Add = append(config.DisabledPackageSources.Add, types.DisabledSource{Key: key, Value: value})
+	case sectionName == SectionActivePackageSource && sub == "":
+		config.ActivePackageSource = &types.ActivePackageSource{
+			Add: types.PackageSource{Key: key, Value: value},
+		}
+	case sectionName == SectionConfig && sub == "":
+		if config.Config == nil {
+			config.Config = &types.Config{}
+		}
+		for i, option := range config.Config.Add {
+			if option.Key == key {
+				config.Config.Add[i].Value = value
+				return nil
+			}
+		}
+		config.Config.Add = append(config.Config.Add, types.ConfigOption{Key: key, Value: value})
+	case sectionName == SectionPackageSourceCredentials && sub != "":
+		if config.PackageSourceCredentials == nil {
+			config.PackageSourceCredentials = &types.PackageSourceCredentials{}
+		}
+		if config.PackageSourceCredentials.Sources == nil {
+			config.PackageSourceCredentials.Sources = make(map[string]types.SourceCredential)
+		}
+		cred := config.PackageSourceCredentials.Sources[sub]
+		updated := false
+		for i, add := range cred.Add {
+			if add.Key == key {
+				cred.Add[i].Value = value
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			cred.Add = append(cred.Add, types.Credential{Key: key, Value: value})
+		}
+		config.PackageSourceCredentials.Sources[sub] = cred
+	default:
+		return fmt.Errorf("unsupported settings section '%s'", name)
+	}
+
+	return nil
+}
+
+// removeValueFromConfig 从配置对象的指定部分移除键
+func removeValueFromConfig(config *types.NuGetConfig, name, key string) bool {
+	if config == nil {
+		return false
+	}
+
+	sectionName, sub := splitSectionName(name)
+
+	switch {
+	case sectionName == SectionPackageSources && sub == "":
+		for i, source := range config.PackageSources.Add {
+			if source.Key == key {
+				config.PackageSources.Add = append(config.PackageSources.Add[:i], config.PackageSources.Add[i+1:]...)
+				return true
+			}
+		}
+	case sectionName == SectionDisabledPackageSources && sub == "":
+		if config.DisabledPackageSources == nil {
+			return false
+		}
+		for i, source := range config.DisabledPackageSources.Add {
+			if source.Key == key {
+				config.DisabledPackageSources.Add = append(config.DisabledPackageSources.Add[:i], config.DisabledPackageSources.Add[i+1:]...)
+				return true
+			}
+		}
+	case sectionName == SectionActivePackageSource && sub == "":
+		if config.ActivePackageSource != nil && config.ActivePackageSource.Add.Key == key {
+			config.ActivePackageSource = nil
+			return true
+		}
+	case sectionName == SectionConfig && sub == "":
+		if config.Config == nil {
+			return false
+		}
+		for i, option := range config.Config.Add {
+			if option.Key == key {
+				config.Config.Add = append(config.Config.Add[:i], config.Config.Add[i+1:]...)
+				return true
+			}
+		}
+	case sectionName == SectionPackageSourceCredentials && sub != "":
+		if config.PackageSourceCredentials == nil {
+			return false
+		}
+		cred, exists := config.PackageSourceCredentials.Sources[sub]
+		if !exists {
+			return false
+		}
+		for i, add := range cred.Add {
+			if add.Key == key {
+				cred.Add = append(cred.Add[:i], cred.Add[i+1:]...)
+				if len(cred.Add) == 0 {
+					delete(config.PackageSourceCredentials.Sources, sub)
+				} else {
+					config.PackageSourceCredentials.Sources[sub] = cred
+				}
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// firstDefinitions 去掉 key 重复的配置项，只保留每个 key 的第一个定义
+func firstDefinitions(items []Item) []Item {
+	seen := make(map[string]bool, len(items))
+	kept := items[:0]
+	for _, item := range items {
+		if seen[item.Key] {
+			continue
+		}
+		seen[item.Key] = true
+		kept = append(kept, item)
+	}
+	return kept
+}
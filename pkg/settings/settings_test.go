@@ -0,0 +1,232 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/constants"
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+)
+
+const projectConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="project-feed" value="https://project.example.com/v3/index.json" protocolVersion="3" />
+    <add key="nuget.org" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <config>
+    <add key="dependencyVersion" value="Highest" />
+  </config>
+</configuration>`
+
+func TestFileSettings(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	s, err := LoadFileSettings(configPath)
+	if err != nil {
+		t.Fatalf("LoadFileSettings() error = %v", err)
+	}
+
+	// 读取配置节
+	sources := s.GetSection(SectionPackageSources)
+	if sources == nil || len(sources.Items) != 2 {
+		t.Fatalf("GetSection(packageSources) = %+v, want 2 items", sources)
+	}
+	if sources.Items[0].Attributes["protocolVersion"] != "3" {
+		t.Errorf("Items[0].Attributes[protocolVersion] = %q, want %q", sources.Items[0].Attributes["protocolVersion"], "3")
+	}
+
+	if s.GetSection("unknownSection") != nil {
+		t.Error("GetSection(unknownSection) should return nil")
+	}
+
+	// 读取凭证
+	if value, ok := s.GetValue(SectionPackageSourceCredentials+"/nuget.org", "Username"); !ok || value != "testuser" {
+		t.Errorf("GetValue(credentials, Username) = %q, %v; want %q, true", value, ok, "testuser")
+	}
+
+	// 设置并移除值
+	if err := s.SetValue(SectionConfig, "http_proxy", "http://proxy.example.com"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	if !s.IsDirty() {
+		t.Error("IsDirty() should be true after SetValue")
+	}
+	if err := s.SetValue("unknownSection", "key", "value"); err == nil {
+		t.Error("SetValue() on unknown section should return error")
+	}
+	if !s.Remove(SectionDisabledPackageSources, "localSource") {
+		t.Error("Remove(disabledPackageSources, localSource) should return true")
+	}
+	if s.Remove(SectionDisabledPackageSources, "localSource") {
+		t.Error("Remove() of missing key should return false")
+	}
+
+	// 保存并重新加载
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if s.IsDirty() {
+		t.Error("IsDirty() should be false after Save")
+	}
+
+	reloaded, err := LoadFileSettings(configPath)
+	if err != nil {
+		t.Fatalf("LoadFileSettings() after save error = %v", err)
+	}
+	if value, ok := reloaded.GetValue(SectionConfig, "http_proxy"); !ok || value != "http://proxy.example.com" {
+		t.Errorf("GetValue(config, http_proxy) = %q, %v after reload", value, ok)
+	}
+	if _, ok := reloaded.GetValue(SectionDisabledPackageSources, "localSource"); ok {
+		t.Error("localSource should no longer be disabled after reload")
+	}
+}
+
+func TestHierarchySettings(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	projectPath := filepath.Join(tempDir, "project", constants.DefaultNuGetConfigFilename)
+	userPath := filepath.Join(tempDir, "user", constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, projectPath, projectConfig)
+	nugetTesting.CreateNuGetConfigFile(t, userPath, nugetTesting.ValidNuGetConfig())
+
+	h, err := LoadHierarchySettings(projectPath, userPath)
+	if err != nil {
+		t.Fatalf("LoadHierarchySettings() error = %v", err)
+	}
+
+	// 合并后的包源：项目级在前，同名键以项目级为准
+	sources := h.GetSection(SectionPackageSources)
+	if sources == nil {
+		t.Fatal("GetSection(packageSources) returned nil")
+	}
+	wantKeys := []string{"project-feed", "nuget.org", "localSource"}
+	if len(sources.Items) != len(wantKeys) {
+		t.Fatalf("merged sources = %+v, want keys %v", sources.Items, wantKeys)
+	}
+	for i, key := range wantKeys {
+		if sources.Items[i].Key != key {
+			t.Errorf("Items[%d].Key = %q, want %q", i, sources.Items[i].Key, key)
+		}
+	}
+
+	if value, _ := h.GetValue(SectionPackageSources, "nuget.org"); value != "https://mirror.example.com/v3/index.json" {
+		t.Errorf("GetValue(packageSources, nuget.org) = %q, want project override", value)
+	}
+
+	// 只在用户级定义的值也能读到
+	if value, ok := h.GetValue(SectionConfig, "globalPackagesFolder"); !ok || value == "" {
+		t.Error("GetValue(config, globalPackagesFolder) should fall back to user config")
+	}
+
+	// 写入优先级最高的文件
+	if err := h.SetValue(SectionConfig, "signatureValidationMode", "require"); err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+	if !h.Files[0].IsDirty() || h.Files[1].IsDirty() {
+		t.Error("SetValue() should only modify the highest priority file")
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := LoadFileSettings(projectPath)
+	if err != nil {
+		t.Fatalf("LoadFileSettings() error = %v", err)
+	}
+	if value, _ := reloaded.GetValue(SectionConfig, "signatureValidationMode"); value != "require" {
+		t.Errorf("project config signatureValidationMode = %q, want %q", value, "require")
+	}
+
+	// Remove 只作用于优先级最高的文件，较低层级的值重新生效
+	if !h.Remove(SectionPackageSources, "nuget.org") {
+		t.Error("Remove(packageSources, nuget.org) should return true")
+	}
+	if h.Files[1].IsDirty() {
+		t.Error("Remove() should not modify lower priority files")
+	}
+	if value, ok := h.GetValue(SectionPackageSources, "nuget.org"); !ok || value == "https://mirror.example.com/v3/index.json" {
+		t.Errorf("GetValue(packageSources, nuget.org) = %q, %v after Remove, want user value", value, ok)
+	}
+	if h.Remove(SectionPackageSources, "nuget.org") {
+		t.Error("Remove() of a key only defined in a lower level should return false")
+	}
+
+	// RemoveFromAllLevels 作用于所有层级
+	if !h.RemoveFromAllLevels(SectionPackageSources, "nuget.org") {
+		t.Error("RemoveFromAllLevels(packageSources, nuget.org) should return true")
+	}
+	if _, ok := h.GetValue(SectionPackageSources, "nuget.org"); ok {
+		t.Error("nuget.org should be removed from every level")
+	}
+
+	empty := NewHierarchySettings()
+	if err := empty.SetValue(SectionConfig, "key", "value"); err == nil {
+		t.Error("SetValue() on empty hierarchy should return error")
+	}
+	if empty.Remove(SectionConfig, "key") {
+		t.Error("Remove() on empty hierarchy should return false")
+	}
+}
+
+func TestHierarchySettingsClear(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	clearedConfig := `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <clear />
+    <add key="internal" value="https://internal.example.com/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <clear />
+  </disabledPackageSources>
+  <config>
+    <clear />
+    <add key="dependencyVersion" value="Lowest" />
+  </config>
+</configuration>`
+
+	projectPath := filepath.Join(tempDir, "project", constants.DefaultNuGetConfigFilename)
+	userPath := filepath.Join(tempDir, "user", constants.DefaultNuGetConfigFilename)
+	nugetTesting.CreateNuGetConfigFile(t, projectPath, clearedConfig)
+	nugetTesting.CreateNuGetConfigFile(t, userPath, nugetTesting.ValidNuGetConfig())
+
+	h, err := LoadHierarchySettings(projectPath, userPath)
+	if err != nil {
+		t.Fatalf("LoadHierarchySettings() error = %v", err)
+	}
+
+	sources := h.GetSection(SectionPackageSources)
+	if sources == nil || len(sources.Items) != 1 || sources.Items[0].Key != "internal" {
+		t.Fatalf("GetSection(packageSources) = %+v, want only internal", sources)
+	}
+	if !sources.Cleared {
+		t.Error("merged packageSources should be marked as cleared")
+	}
+
+	if _, ok := h.GetValue(SectionPackageSources, "nuget.org"); ok {
+		t.Error("nuget.org from user config should be cleared")
+	}
+
+	disabled := h.GetSection(SectionDisabledPackageSources)
+	if disabled == nil || len(disabled.Items) != 0 {
+		t.Errorf("GetSection(disabledPackageSources) = %+v, want empty", disabled)
+	}
+
+	if _, ok := h.GetValue(SectionConfig, "globalPackagesFolder"); ok {
+		t.Error("globalPackagesFolder from user config should be cleared")
+	}
+
+	// 未清除的配置节仍然继承
+	if _, ok := h.GetValue(SectionPackageSourceCredentials+"/nuget.org", "Username"); !ok {
+		t.Error("credentials from user config should still be inherited")
+	}
+}
@@ -0,0 +1,74 @@
+package types
+
+// Clone 深拷贝配置，包括凭证映射和所有指针类型的配置节
+//
+// 返回的配置与原配置不共享任何切片、映射或指针，修改其中一个不会影响另一个。
+// 原配置中为 nil 的切片和映射在副本中仍为 nil，空的切片和映射仍为空。
+func (c *NuGetConfig) Clone() *NuGetConfig {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	clone.PackageSources.Add = cloneSlice(c.PackageSources.Add)
+	clone.PackageSources.ClearElement = c.PackageSources.ClearElement.clone()
+
+	if c.PackageSourceCredentials != nil {
+		creds := &PackageSourceCredentials{}
+		if c.PackageSourceCredentials.Sources != nil {
+			creds.Sources = make(map[string]SourceCredential, len(c.PackageSourceCredentials.Sources))
+			for key, cred := range c.PackageSourceCredentials.Sources {
+				creds.Sources[key] = SourceCredential{Add: cloneSlice(cred.Add)}
+			}
+		}
+		clone.PackageSourceCredentials = creds
+	}
+
+	if c.Config != nil {
+		clone.Config = &Config{
+			ClearElement: c.Config.ClearElement.clone(),
+			Add:          cloneSlice(c.Config.Add),
+		}
+	}
+
+	if c.DisabledPackageSources != nil {
+		clone.DisabledPackageSources = &DisabledPackageSources{
+			ClearElement: c.DisabledPackageSources.ClearElement.clone(),
+			Add:          cloneSlice(c.DisabledPackageSources.Add),
+		}
+	}
+
+	if c.ActivePackageSource != nil {
+		active := *c.ActivePackageSource
+		clone.ActivePackageSource = &active
+	}
+
+	if c.PackageSourceMapping != nil {
+		mapping := &PackageSourceMapping{ClearElement: c.PackageSourceMapping.ClearElement.clone()}
+		if c.PackageSourceMapping.PackageSource != nil {
+			mapping.PackageSource = make([]PackageSourceMappingSource, len(c.PackageSourceMapping.PackageSource))
+			for i, source := range c.PackageSourceMapping.PackageSource {
+				mapping.PackageSource[i] = PackageSourceMappingSource{Key: source.Key, Package: cloneSlice(source.Package)}
+			}
+		}
+		clone.PackageSourceMapping = mapping
+	}
+
+	return &clone
+}
+
+// clone 复制 <clear /> 元素
+func (c *ClearElement) clone() *ClearElement {
+	if c == nil {
+		return nil
+	}
+	return &ClearElement{}
+}
+
+// cloneSlice 复制切片，nil 切片仍返回 nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNuGetConfigClone(t *testing.T) {
+	config := &NuGetConfig{
+		PackageSources: PackageSources{
+			Clear:        true,
+			ClearElement: &ClearElement{},
+			Add:          []PackageSource{{Key: "a", Value: "https://a.example.com", ProtocolVersion: "3"}},
+		},
+		PackageSourceCredentials: &PackageSourceCredentials{
+			Sources: map[string]SourceCredential{
+				"a": {Add: []Credential{{Key: "Username", Value: "user"}}},
+			},
+		},
+		Config: &Config{
+			ClearElement: &ClearElement{},
+			Add:          []ConfigOption{{Key: "k", Value: "v"}},
+		},
+		DisabledPackageSources: &DisabledPackageSources{
+			Add: []DisabledSource{},
+		},
+		ActivePackageSource: &ActivePackageSource{Add: PackageSource{Key: "a", Value: "https://a.example.com"}},
+		PackageSourceMapping: &PackageSourceMapping{
+			PackageSource: []PackageSourceMappingSource{{Key: "a", Package: []PackagePattern{{Pattern: "*"}}}},
+		},
+	}
+
+	clone := config.Clone()
+	if !reflect.DeepEqual(clone, config) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, config)
+	}
+
+	clone.PackageSources.Add[0].Value = "changed"
+	clone.PackageSourceCredentials.Sources["a"].Add[0].Value = "changed"
+	clone.PackageSourceCredentials.Sources["b"] = SourceCredential{}
+	clone.Config.Add[0].Value = "changed"
+	clone.ActivePackageSource.Add.Key = "changed"
+	clone.PackageSourceMapping.PackageSource[0].Package[0].Pattern = "changed"
+
+	if config.PackageSources.Add[0].Value == "changed" ||
+		config.PackageSourceCredentials.Sources["a"].Add[0].Value == "changed" ||
+		len(config.PackageSourceCredentials.Sources) != 1 ||
+		config.Config.Add[0].Value == "changed" ||
+		config.ActivePackageSource.Add.Key == "changed" ||
+		config.PackageSourceMapping.PackageSource[0].Package[0].Pattern == "changed" {
+		t.Error("modifying the clone should not affect the original config")
+	}
+
+	var nilConfig *NuGetConfig
+	if nilConfig.Clone() != nil {
+		t.Error("Clone() of a nil config should return nil")
+	}
+}
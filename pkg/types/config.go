@@ -4,54 +4,101 @@ package types
 import (
 	"encoding/xml"
 	"io"
+	"sort"
+	"strings"
 )
 
 // NuGetConfig 表示一个完整的 NuGet 配置文件
 type NuGetConfig struct {
+	// XMLName 根元素的名称，序列化时总是 <configuration>
+	XMLName xml.Name `xml:"configuration" json:"-"`
+
 	// PackageSources 定义可用的包源
-	PackageSources PackageSources `xml:"packageSources"`
+	PackageSources PackageSources `xml:"packageSources" json:"packageSources"`
 
 	// PackageSourceCredentials 定义包源凭证信息
-	PackageSourceCredentials *PackageSourceCredentials `xml:"packageSourceCredentials,omitempty"`
+	PackageSourceCredentials *PackageSourceCredentials `xml:"packageSourceCredentials,omitempty" json:"packageSourceCredentials,omitempty"`
 
 	// Config 定义全局配置选项
-	Config *Config `xml:"config,omitempty"`
+	Config *Config `xml:"config,omitempty" json:"config,omitempty"`
 
 	// DisabledPackageSources 定义被禁用的包源
-	DisabledPackageSources *DisabledPackageSources `xml:"disabledPackageSources,omitempty"`
+	DisabledPackageSources *DisabledPackageSources `xml:"disabledPackageSources,omitempty" json:"disabledPackageSources,omitempty"`
 
 	// ActivePackageSource 定义当前活跃的包源
-	ActivePackageSource *ActivePackageSource `xml:"activePackageSource,omitempty"`
+	ActivePackageSource *ActivePackageSource `xml:"activePackageSource,omitempty" json:"activePackageSource,omitempty"`
+
+	// PackageSourceMapping 定义包ID与包源之间的映射
+	PackageSourceMapping *PackageSourceMapping `xml:"packageSourceMapping,omitempty" json:"packageSourceMapping,omitempty"`
+}
+
+// UnmarshalXML 自定义NuGetConfig的XML反序列化
+//
+// 根元素的名称不在这里检查，不是 <configuration> 时由解析器的结构检查报告。
+// 解码后 XMLName 保持为空，使解析得到的配置与代码中构造的配置可以直接比较。
+func (c *NuGetConfig) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain NuGetConfig
+	start.Name = xml.Name{Local: "configuration"}
+	if err := d.DecodeElement((*plain)(c), &start); err != nil {
+		return err
+	}
+	c.XMLName = xml.Name{}
+	return nil
 }
 
 // PackageSources 定义包源列表
 type PackageSources struct {
 	// Clear 如果存在并且为 true，则清除之前的所有包源
-	Clear bool `xml:"clear,attr,omitempty"`
+	Clear bool `xml:"clear,attr,omitempty" json:"-"`
+
+	// ClearElement 对应 <clear /> 子元素，与 Clear 属性效果相同
+	ClearElement *ClearElement `xml:"clear,omitempty" json:"-"`
 
 	// Add 表示添加的包源列表
-	Add []PackageSource `xml:"add"`
+	Add []PackageSource `xml:"add" json:"add,omitempty"`
 }
 
+// IsCleared 判断是否清除了之前配置文件中继承的包源
+func (p *PackageSources) IsCleared() bool {
+	return p.Clear || p.ClearElement != nil
+}
+
+// ClearElement 表示配置节中的 <clear /> 元素
+//
+// NuGet 在合并配置文件层级时，遇到 <clear /> 会丢弃来自更高层级
+// （如用户级、机器级）配置文件中同一配置节的所有项。
+type ClearElement struct{}
+
 // PackageSource 定义单个包源
 type PackageSource struct {
 	// Key 包源的唯一标识符
-	Key string `xml:"key,attr"`
+	Key string `xml:"key,attr" json:"key"`
 
 	// Value 包源的 URL 或路径
-	Value string `xml:"value,attr"`
+	Value string `xml:"value,attr" json:"value"`
 
 	// ProtocolVersion 包源使用的协议版本
-	ProtocolVersion string `xml:"protocolVersion,attr,omitempty"`
+	ProtocolVersion string `xml:"protocolVersion,attr,omitempty" json:"protocolVersion,omitempty"`
+
+	// AllowInsecureConnections 是否允许通过 http:// 访问包源，NuGet 6.8 起对
+	// 未设置该属性的 http 包源发出警告
+	AllowInsecureConnections string `xml:"allowInsecureConnections,attr,omitempty" json:"allowInsecureConnections,omitempty"`
+}
+
+// InsecureConnectionsAllowed 判断包源是否设置了 allowInsecureConnections="true"，不区分大小写
+func (s PackageSource) InsecureConnectionsAllowed() bool {
+	return strings.EqualFold(strings.TrimSpace(s.AllowInsecureConnections), "true")
 }
 
 // PackageSourceCredentials 定义包源凭证
 type PackageSourceCredentials struct {
 	// 键为包源名称，值为该包源的凭证
-	Sources map[string]SourceCredential `xml:"-"` // 不直接序列化
+	Sources map[string]SourceCredential `xml:"-" json:"-"` // 不直接序列化
 }
 
 // MarshalXML 自定义PackageSourceCredentials的XML序列化
+//
+// 每个包源的凭证以包源名称为元素名，名称使用 EncodeElementName 编码，UnmarshalXML 读取时解码。
 func (p *PackageSourceCredentials) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if p == nil || len(p.Sources) == 0 {
 		return nil
@@ -61,10 +108,17 @@ func (p *PackageSourceCredentials) MarshalXML(e *xml.Encoder, start xml.StartEle
 		return err
 	}
 
-	// 遍历所有凭证源
-	for key, cred := range p.Sources {
-		// 为每个凭证源创建一个元素
-		sourceElem := xml.StartElement{Name: xml.Name{Local: key}}
+	// 按包源名称排序，使输出保持稳定
+	keys := make([]string, 0, len(p.Sources))
+	for key := range p.Sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cred := p.Sources[key]
+		// 为每个凭证源创建一个元素，包源名称中不能用于元素名的字符按 NuGet 的方式编码
+		sourceElem := xml.StartElement{Name: xml.Name{Local: EncodeElementName(key)}}
 		if err := e.EncodeToken(sourceElem); err != nil {
 			return err
 		}
@@ -109,8 +163,8 @@ func (p *PackageSourceCredentials) UnmarshalXML(d *xml.Decoder, start xml.StartE
 		switch tt := t.(type) {
 		case xml.StartElement:
 			if tt.Name.Local != "add" {
-				// 这是一个包源名称元素
-				sourceName := tt.Name.Local
+				// 这是一个包源名称元素，名称可能经过 EncodeElementName 编码
+				sourceName := DecodeElementName(tt.Name.Local)
 				var sourceCred SourceCredential
 
 				// 解析这个源的所有凭证
@@ -133,50 +187,97 @@ func (p *PackageSourceCredentials) UnmarshalXML(d *xml.Decoder, start xml.StartE
 // SourceCredential 定义源凭证信息
 type SourceCredential struct {
 	// Add 凭证列表，通常包含用户名和密码
-	Add []Credential `xml:"add"`
+	Add []Credential `xml:"add" json:"add,omitempty"`
 }
 
 // Credential 定义单个凭证键值对
 type Credential struct {
 	// Key 凭证键名，如 Username、Password、ClearTextPassword 等
-	Key string `xml:"key,attr"`
+	Key string `xml:"key,attr" json:"key"`
 
 	// Value 凭证值
-	Value string `xml:"value,attr"`
+	Value string `xml:"value,attr" json:"value"`
 }
 
 // DisabledPackageSources 定义被禁用的包源
 type DisabledPackageSources struct {
+	// ClearElement 对应 <clear /> 子元素，清除继承的禁用包源
+	ClearElement *ClearElement `xml:"clear,omitempty" json:"-"`
+
 	// Add 表示禁用的包源列表
-	Add []DisabledSource `xml:"add"`
+	Add []DisabledSource `xml:"add" json:"add,omitempty"`
+}
+
+// IsCleared 判断是否清除了之前配置文件中继承的禁用包源
+func (d *DisabledPackageSources) IsCleared() bool {
+	return d != nil && d.ClearElement != nil
 }
 
 // DisabledSource 定义被禁用的单个包源
 type DisabledSource struct {
 	// Key 包源的标识符
-	Key string `xml:"key,attr"`
+	Key string `xml:"key,attr" json:"key"`
 
 	// Value 通常为 "true"，表示该源被禁用
-	Value string `xml:"value,attr"`
+	Value string `xml:"value,attr" json:"value"`
 }
 
 // ActivePackageSource 定义当前使用的包源
 type ActivePackageSource struct {
 	// Add 表示活跃的包源，通常只有一个
-	Add PackageSource `xml:"add"`
+	Add PackageSource `xml:"add" json:"add"`
+}
+
+// PackageSourceMapping 定义包源映射
+//
+// 启用包源映射后，NuGet 只会从与包ID匹配的包源还原该包。
+type PackageSourceMapping struct {
+	// ClearElement 对应 <clear /> 子元素，清除继承的包源映射
+	ClearElement *ClearElement `xml:"clear,omitempty" json:"-"`
+
+	// PackageSource 各包源的映射规则
+	PackageSource []PackageSourceMappingSource `xml:"packageSource" json:"packageSource,omitempty"`
+}
+
+// IsCleared 判断是否清除了之前配置文件中继承的包源映射
+func (m *PackageSourceMapping) IsCleared() bool {
+	return m != nil && m.ClearElement != nil
+}
+
+// PackageSourceMappingSource 定义单个包源的映射规则
+type PackageSourceMappingSource struct {
+	// Key 包源的标识符，对应 packageSources 中的键
+	Key string `xml:"key,attr" json:"key"`
+
+	// Package 映射到该包源的包ID模式列表
+	Package []PackagePattern `xml:"package" json:"package,omitempty"`
+}
+
+// PackagePattern 定义包ID模式
+type PackagePattern struct {
+	// Pattern 包ID模式，支持以 * 结尾的前缀匹配，如 Contoso.*
+	Pattern string `xml:"pattern,attr" json:"pattern"`
 }
 
 // Config 定义全局配置选项
 type Config struct {
+	// ClearElement 对应 <clear /> 子元素，清除继承的配置选项
+	ClearElement *ClearElement `xml:"clear,omitempty" json:"-"`
+
 	// Add 配置选项列表
-	Add []ConfigOption `xml:"add"`
+	Add []ConfigOption `xml:"add" json:"add,omitempty"`
+}
+
+// IsCleared 判断是否清除了之前配置文件中继承的配置选项
+func (c *Config) IsCleared() bool {
+	return c != nil && c.ClearElement != nil
 }
 
 // ConfigOption 定义配置选项
 type ConfigOption struct {
 	// Key 配置键名
-	Key string `xml:"key,attr"`
+	Key string `xml:"key,attr" json:"key"`
 
 	// Value 配置值
-	Value string `xml:"value,attr"`
+	Value string `xml:"value,attr" json:"value"`
 }
@@ -185,6 +185,55 @@ func TestNuGetConfigXMLMarshaling(t *testing.T) {
 	}
 }
 
+func TestClearElement(t *testing.T) {
+	xmlData := `<configuration>
+  <packageSources>
+    <clear />
+    <add key="local" value="/packages" />
+  </packageSources>
+  <disabledPackageSources>
+    <clear />
+  </disabledPackageSources>
+  <config>
+    <clear />
+  </config>
+</configuration>`
+
+	var config NuGetConfig
+	if err := xml.Unmarshal([]byte(xmlData), &config); err != nil {
+		t.Fatalf("Failed to unmarshal XML: %v", err)
+	}
+
+	if !config.PackageSources.IsCleared() {
+		t.Error("PackageSources.IsCleared() should be true")
+	}
+	if !config.DisabledPackageSources.IsCleared() {
+		t.Error("DisabledPackageSources.IsCleared() should be true")
+	}
+	if !config.Config.IsCleared() {
+		t.Error("Config.IsCleared() should be true")
+	}
+
+	// 序列化后 <clear /> 元素应当保留
+	data, err := xml.Marshal(&config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+
+	var roundTrip NuGetConfig
+	if err := xml.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("Failed to unmarshal marshaled XML: %v", err)
+	}
+	if !roundTrip.PackageSources.IsCleared() || !roundTrip.Config.IsCleared() {
+		t.Errorf("clear elements lost after round trip: %s", data)
+	}
+
+	var nilConfig *Config
+	if nilConfig.IsCleared() {
+		t.Error("nil Config should not be cleared")
+	}
+}
+
 func TestStructTagsXML(t *testing.T) {
 	// 检查 NuGetConfig 结构体字段的 XML 标签
 	t.Run("NuGetConfig", func(t *testing.T) {
@@ -230,3 +279,39 @@ func checkFieldXMLTag(t *testing.T, typ reflect.Type, fieldName, expectedTag str
 		t.Errorf("Field %s in %s has XML tag %q, want %q", fieldName, typ.Name(), tag, expectedTag)
 	}
 }
+
+func TestPackageSourceMapping(t *testing.T) {
+	xmlData := `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="contoso" value="https://contoso.com/packages/" />
+  </packageSources>
+  <packageSourceMapping>
+    <packageSource key="nuget.org">
+      <package pattern="*" />
+    </packageSource>
+    <packageSource key="contoso">
+      <package pattern="Contoso.*" />
+      <package pattern="NuGet.Common" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`
+
+	var config NuGetConfig
+	if err := xml.Unmarshal([]byte(xmlData), &config); err != nil {
+		t.Fatalf("Failed to unmarshal XML: %v", err)
+	}
+
+	mapping := config.PackageSourceMapping
+	if mapping == nil || len(mapping.PackageSource) != 2 {
+		t.Fatalf("Expected 2 mapped sources, got %+v", mapping)
+	}
+	if mapping.IsCleared() {
+		t.Error("PackageSourceMapping.IsCleared() should be false")
+	}
+
+	contoso := mapping.PackageSource[1]
+	if contoso.Key != "contoso" || len(contoso.Package) != 2 || contoso.Package[0].Pattern != "Contoso.*" {
+		t.Errorf("Unexpected mapping for contoso: %+v", contoso)
+	}
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSON 表示中字段名与XML中的元素名和属性名一致，<clear /> 元素和 clear 属性
+// 都表示为 "clear": true，凭证以包源名称为键。各配置节中出现未知字段时反序列化失败，
+// 以免拼写错误的字段被静默忽略。例如：
+//
+//	{
+//	  "packageSources": {"clear": true, "add": [{"key": "nuget.org", "value": "https://api.nuget.org/v3/index.json"}]},
+//	  "packageSourceCredentials": {"nuget.org": {"add": [{"key": "Username", "value": "user"}]}}
+//	}
+
+// MarshalJSON 自定义PackageSources的JSON序列化
+func (p PackageSources) MarshalJSON() ([]byte, error) {
+	type plain PackageSources
+	return json.Marshal(struct {
+		Clear bool `json:"clear,omitempty"`
+		plain
+	}{Clear: p.IsCleared(), plain: plain(p)})
+}
+
+// UnmarshalJSON 自定义PackageSources的JSON反序列化，"clear": true 对应 <clear /> 元素
+func (p *PackageSources) UnmarshalJSON(data []byte) error {
+	type plain PackageSources
+	var v struct {
+		Clear bool `json:"clear"`
+		plain
+	}
+	if err := unmarshalStrict(data, &v); err != nil {
+		return err
+	}
+
+	*p = PackageSources(v.plain)
+	p.ClearElement = clearElement(v.Clear)
+	return nil
+}
+
+// MarshalJSON 自定义PackageSourceCredentials的JSON序列化，输出以包源名称为键的对象
+func (p PackageSourceCredentials) MarshalJSON() ([]byte, error) {
+	if p.Sources == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p.Sources)
+}
+
+// UnmarshalJSON 自定义PackageSourceCredentials的JSON反序列化
+func (p *PackageSourceCredentials) UnmarshalJSON(data []byte) error {
+	sources := make(map[string]SourceCredential)
+	if err := unmarshalStrict(data, &sources); err != nil {
+		return err
+	}
+	p.Sources = sources
+	return nil
+}
+
+// MarshalJSON 自定义DisabledPackageSources的JSON序列化
+func (d DisabledPackageSources) MarshalJSON() ([]byte, error) {
+	type plain DisabledPackageSources
+	return json.Marshal(struct {
+		Clear bool `json:"clear,omitempty"`
+		plain
+	}{Clear: d.ClearElement != nil, plain: plain(d)})
+}
+
+// UnmarshalJSON 自定义DisabledPackageSources的JSON反序列化
+func (d *DisabledPackageSources) UnmarshalJSON(data []byte) error {
+	type plain DisabledPackageSources
+	var v struct {
+		Clear bool `json:"clear"`
+		plain
+	}
+	if err := unmarshalStrict(data, &v); err != nil {
+		return err
+	}
+
+	*d = DisabledPackageSources(v.plain)
+	d.ClearElement = clearElement(v.Clear)
+	return nil
+}
+
+// MarshalJSON 自定义PackageSourceMapping的JSON序列化
+func (m PackageSourceMapping) MarshalJSON() ([]byte, error) {
+	type plain PackageSourceMapping
+	return json.Marshal(struct {
+		Clear bool `json:"clear,omitempty"`
+		plain
+	}{Clear: m.ClearElement != nil, plain: plain(m)})
+}
+
+// UnmarshalJSON 自定义PackageSourceMapping的JSON反序列化
+func (m *PackageSourceMapping) UnmarshalJSON(data []byte) error {
+	type plain PackageSourceMapping
+	var v struct {
+		Clear bool `json:"clear"`
+		plain
+	}
+	if err := unmarshalStrict(data, &v); err != nil {
+		return err
+	}
+
+	*m = PackageSourceMapping(v.plain)
+	m.ClearElement = clearElement(v.Clear)
+	return nil
+}
+
+// MarshalJSON 自定义Config的JSON序列化
+func (c Config) MarshalJSON() ([]byte, error) {
+	type plain Config
+	return json.Marshal(struct {
+		Clear bool `json:"clear,omitempty"`
+		plain
+	}{Clear: c.ClearElement != nil, plain: plain(c)})
+}
+
+// UnmarshalJSON 自定义Config的JSON反序列化
+func (c *Config) UnmarshalJSON(data []byte) error {
+	type plain Config
+	var v struct {
+		Clear bool `json:"clear"`
+		plain
+	}
+	if err := unmarshalStrict(data, &v); err != nil {
+		return err
+	}
+
+	*c = Config(v.plain)
+	c.ClearElement = clearElement(v.Clear)
+	return nil
+}
+
+// clearElement 根据 JSON 中的 clear 字段返回对应的 <clear /> 元素
+func clearElement(clear bool) *ClearElement {
+	if clear {
+		return &ClearElement{}
+	}
+	return nil
+}
+
+// unmarshalStrict 反序列化JSON，遇到未知字段时返回错误
+func unmarshalStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
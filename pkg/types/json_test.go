@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNuGetConfigJSONRoundTrip(t *testing.T) {
+	config := &NuGetConfig{
+		PackageSources: PackageSources{
+			ClearElement: &ClearElement{},
+			Add: []PackageSource{
+				{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json", ProtocolVersion: "3"},
+				{Key: "local", Value: "/packages"},
+			},
+		},
+		PackageSourceCredentials: &PackageSourceCredentials{
+			Sources: map[string]SourceCredential{
+				"local": {Add: []Credential{{Key: "Username", Value: "user"}}},
+			},
+		},
+		Config: &Config{
+			ClearElement: &ClearElement{},
+			Add:          []ConfigOption{{Key: "globalPackagesFolder", Value: "/gp"}},
+		},
+		DisabledPackageSources: &DisabledPackageSources{
+			Add: []DisabledSource{{Key: "local", Value: "true"}},
+		},
+		ActivePackageSource: &ActivePackageSource{
+			Add: PackageSource{Key: "nuget.org", Value: "https://api.nuget.org/v3/index.json"},
+		},
+		PackageSourceMapping: &PackageSourceMapping{
+			ClearElement: &ClearElement{},
+			PackageSource: []PackageSourceMappingSource{
+				{Key: "nuget.org", Package: []PackagePattern{{Pattern: "*"}}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"packageSources":{"clear":true,"add":[{"key":"nuget.org","value":"https://api.nuget.org/v3/index.json","protocolVersion":"3"},{"key":"local","value":"/packages"}]}`,
+		`"packageSourceCredentials":{"local":{"add":[{"key":"Username","value":"user"}]}}`,
+		`"config":{"clear":true,"add":[{"key":"globalPackagesFolder","value":"/gp"}]}`,
+		`"disabledPackageSources":{"add":[{"key":"local","value":"true"}]}`,
+		`"packageSourceMapping":{"clear":true,"packageSource":[{"key":"nuget.org","package":[{"pattern":"*"}]}]}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON output missing %s\n%s", want, data)
+		}
+	}
+
+	var decoded NuGetConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, config) {
+		t.Errorf("JSON round trip mismatch:\n%+v\n%+v", decoded, *config)
+	}
+}
+
+func TestPackageSourcesClearAttributeJSON(t *testing.T) {
+	data, err := json.Marshal(PackageSources{Clear: true})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `{"clear":true}` {
+		t.Errorf("json.Marshal() = %s, want {\"clear\":true}", data)
+	}
+
+	var sources PackageSources
+	if err := json.Unmarshal([]byte(`{"clear":false}`), &sources); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if sources.IsCleared() {
+		t.Error(`"clear": false should not clear inherited package sources`)
+	}
+}
+
+func TestJSONRejectsUnknownFields(t *testing.T) {
+	for _, content := range []string{
+		`{"packageSources": {"sources": []}}`,
+		`{"config": {"add": [{"key": "a", "value": "b", "extra": 1}]}}`,
+		`{"packageSourceCredentials": {"a": {"add": [], "password": "x"}}}`,
+	} {
+		var config NuGetConfig
+		if err := json.Unmarshal([]byte(content), &config); err == nil {
+			t.Errorf("json.Unmarshal(%s) should reject unknown fields", content)
+		}
+	}
+}
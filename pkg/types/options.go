@@ -0,0 +1,124 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/utils"
+)
+
+// OptionKind 配置选项值的类型
+type OptionKind int
+
+const (
+	// OptionString 任意字符串
+	OptionString OptionKind = iota
+	// OptionBool true 或 false，不区分大小写
+	OptionBool
+	// OptionInt 整数，可以限定取值范围
+	OptionInt
+	// OptionEnum 预定义值之一，不区分大小写
+	OptionEnum
+	// OptionPath 文件系统路径
+	OptionPath
+	// OptionURL 带主机名的 http 或 https 地址
+	OptionURL
+)
+
+// ConfigOptionSpec 描述 <config> 配置节中一个已知选项的取值规则
+type ConfigOptionSpec struct {
+	Key  string
+	Kind OptionKind
+	// Values OptionEnum 允许的取值
+	Values []string
+	// Min 和 Max OptionInt 的取值范围，Max 为 0 表示没有上限
+	Min int
+	Max int
+}
+
+// KnownConfigOptions NuGet 文档中定义的 <config> 选项
+var KnownConfigOptions = []ConfigOptionSpec{
+	{Key: "defaultPushSource", Kind: OptionString},
+	{Key: "dependencyVersion", Kind: OptionEnum, Values: []string{"Lowest", "HighestPatch", "HighestMinor", "Highest", "Ignore"}},
+	{Key: "globalPackagesFolder", Kind: OptionPath},
+	{Key: "http_proxy", Kind: OptionURL},
+	{Key: "http_proxy.password", Kind: OptionString},
+	{Key: "http_proxy.user", Kind: OptionString},
+	{Key: "maxHttpRequestsPerSource", Kind: OptionInt, Min: 1},
+	{Key: "no_proxy", Kind: OptionString},
+	{Key: "repositoryPath", Kind: OptionPath},
+	{Key: "signatureValidationMode", Kind: OptionEnum, Values: []string{"accept", "require"}},
+	{Key: "updatePackageLastAccessTime", Kind: OptionBool},
+}
+
+// LookupConfigOption 按键名查找已知的配置选项，键名不区分大小写
+func LookupConfigOption(key string) (ConfigOptionSpec, bool) {
+	for _, spec := range KnownConfigOptions {
+		if strings.EqualFold(spec.Key, key) {
+			return spec, true
+		}
+	}
+	return ConfigOptionSpec{}, false
+}
+
+// SuggestConfigOption 返回与未知键名最接近的已知选项键名，用于提示拼写错误
+//
+// key 本身是已知选项或没有足够接近的选项时返回空字符串。
+func SuggestConfigOption(key string) string {
+	if _, known := LookupConfigOption(key); known {
+		return ""
+	}
+	keys := make([]string, len(KnownConfigOptions))
+	for i, spec := range KnownConfigOptions {
+		keys[i] = spec.Key
+	}
+	return utils.ClosestMatch(key, keys)
+}
+
+// Validate 检查值是否符合选项的取值规则
+func (s ConfigOptionSpec) Validate(value string) error {
+	trimmed := strings.TrimSpace(value)
+
+	switch s.Kind {
+	case OptionBool:
+		if !strings.EqualFold(trimmed, "true") && !strings.EqualFold(trimmed, "false") {
+			return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must be true or false, got %q", s.Key, value)
+		}
+	case OptionInt:
+		n, err := strconv.Atoi(trimmed)
+		if err != nil {
+			return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must be an integer, got %q", s.Key, value)
+		}
+		if n < s.Min || (s.Max != 0 && n > s.Max) {
+			return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must be %s, got %d", s.Key, s.rangeText(), n)
+		}
+	case OptionEnum:
+		for _, allowed := range s.Values {
+			if strings.EqualFold(trimmed, allowed) {
+				return nil
+			}
+		}
+		return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must be one of %s, got %q", s.Key, strings.Join(s.Values, ", "), value)
+	case OptionPath:
+		if trimmed == "" {
+			return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must not be empty", s.Key)
+		}
+	case OptionURL:
+		u, err := url.Parse(trimmed)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf(errors.CodeInvalidConfigValue, "config option '%s' must be an http or https URL, got %q", s.Key, value)
+		}
+	}
+	return nil
+}
+
+// rangeText 返回整数取值范围的描述
+func (s ConfigOptionSpec) rangeText() string {
+	if s.Max == 0 {
+		return fmt.Sprintf("at least %d", s.Min)
+	}
+	return fmt.Sprintf("between %d and %d", s.Min, s.Max)
+}
@@ -0,0 +1,57 @@
+package types
+
+import "testing"
+
+func TestConfigOptionSpecValidate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"dependencyVersion", "highest", false},
+		{"dependencyVersion", "Newest", true},
+		{"maxHttpRequestsPerSource", "16", false},
+		{"maxHttpRequestsPerSource", "0", true},
+		{"maxHttpRequestsPerSource", "lots", true},
+		{"updatePackageLastAccessTime", "TRUE", false},
+		{"updatePackageLastAccessTime", "1", true},
+		{"http_proxy", "http://proxy:8080", false},
+		{"http_proxy", "proxy:8080", true},
+		{"globalPackagesFolder", "", true},
+		{"defaultPushSource", "anything", false},
+	}
+	for _, tt := range tests {
+		spec, ok := LookupConfigOption(tt.key)
+		if !ok {
+			t.Fatalf("LookupConfigOption(%q) not found", tt.key)
+		}
+		if err := spec.Validate(tt.value); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%s=%q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+
+	if spec, ok := LookupConfigOption("GLOBALPACKAGESFOLDER"); !ok || spec.Key != "globalPackagesFolder" {
+		t.Error("LookupConfigOption should be case-insensitive")
+	}
+	if _, ok := LookupConfigOption("globalPackageFolder"); ok {
+		t.Error("LookupConfigOption(globalPackageFolder) should not be found")
+	}
+}
+
+func TestSuggestConfigOption(t *testing.T) {
+	tests := map[string]string{
+		"globalPackageFolder":  "globalPackagesFolder",
+		"maxHttpRequestPerSrc": "maxHttpRequestsPerSource",
+		"http-proxy":           "http_proxy",
+		"globalPackagesFolder": "",
+		"GLOBALPACKAGESFOLDER": "",
+		"signatureValidation":  "signatureValidationMode",
+		"dependancyVersion":    "dependencyVersion",
+		"customToolSetting":    "",
+	}
+	for key, want := range tests {
+		if got := SuggestConfigOption(key); got != want {
+			t.Errorf("SuggestConfigOption(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"net/url"
+	"strings"
+)
+
+// RedactedValue Redacted 代替密码等敏感值的内容
+const RedactedValue = "***"
+
+// IsSecretKey 判断凭证项、配置选项或 URL 查询参数的名称是否表示敏感信息，
+// 如 ClearTextPassword、http_proxy.password、api_key，不区分大小写
+func IsSecretKey(key string) bool {
+	key = strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+	for _, word := range []string{"password", "secret", "token", "apikey"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted 返回隐藏了敏感信息的配置副本，用于日志、差异和问题报告
+//
+// 凭证中的密码、令牌等项以及 http_proxy.password 等敏感配置选项的值被替换为 RedactedValue；
+// 包源地址和 http_proxy 中 URL 的密码部分以及 token、sig 等查询参数的值也会被替换。
+// 用户名和其他配置保持不变。原配置不会被修改，副本不应被保存为实际使用的配置文件。
+func (c *NuGetConfig) Redacted() *NuGetConfig {
+	clone := c.Clone()
+	if clone == nil {
+		return nil
+	}
+
+	for i := range clone.PackageSources.Add {
+		clone.PackageSources.Add[i].Value = redactURL(clone.PackageSources.Add[i].Value)
+	}
+	if clone.ActivePackageSource != nil {
+		clone.ActivePackageSource.Add.Value = redactURL(clone.ActivePackageSource.Add.Value)
+	}
+
+	if clone.PackageSourceCredentials != nil {
+		for _, cred := range clone.PackageSourceCredentials.Sources {
+			// cred.Add 已经是副本，可以直接修改
+			for i, item := range cred.Add {
+				if item.Value != "" && IsSecretKey(item.Key) {
+					cred.Add[i].Value = RedactedValue
+				}
+			}
+		}
+	}
+
+	if clone.Config != nil {
+		for i, option := range clone.Config.Add {
+			switch {
+			case option.Value != "" && IsSecretKey(option.Key):
+				clone.Config.Add[i].Value = RedactedValue
+			case strings.EqualFold(option.Key, "http_proxy"):
+				clone.Config.Add[i].Value = redactURL(option.Value)
+			}
+		}
+	}
+
+	return clone
+}
+
+// redactURL 隐藏 URL 中的密码和敏感查询参数，其他内容（包括查询参数的顺序）保持不变
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	// url.UserPassword 会转义 *，因此先去掉密码，最后再拼接 RedactedValue
+	_, redactPassword := u.User.Password()
+	if redactPassword {
+		u.User = url.User(u.User.Username())
+	}
+	changed := redactPassword
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			name, _, hasValue := strings.Cut(param, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if hasValue && (IsSecretKey(name) || strings.EqualFold(name, "sig")) {
+				params[i] = param[:strings.IndexByte(param, '=')+1] + RedactedValue
+				changed = true
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+
+	if !changed {
+		return value
+	}
+	result := u.String()
+	if redactPassword {
+		prefix := u.Scheme + "://" + u.User.String()
+		result = prefix + ":" + RedactedValue + result[len(prefix):]
+	}
+	return result
+}
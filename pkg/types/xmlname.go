@@ -0,0 +1,108 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// EncodeElementName 将包源名称编码为合法的XML元素名，与 .NET 的 XmlConvert.EncodeLocalName 相同
+//
+// NuGet 以包源名称作为 <packageSourceCredentials> 子元素的名称，名称中不能出现在XML元素名中的字符
+// （如空格、冒号、开头的数字）被编码为 _xHHHH_，基本多文种平面之外的字符编码为 _xHHHHHHHH_，
+// 本身形如 _xHHHH_ 的内容中的下划线编码为 _x005F_，以便解码时还原。例如 "My Feed" 编码为 "My_x0020_Feed"。
+func EncodeElementName(name string) string {
+	var sb strings.Builder
+	first := true
+	for i, r := range name {
+		switch {
+		case r == '_' && escapeAt(name, i) > 0:
+			sb.WriteString("_x005F_")
+		case r != ':' && (isNameStartChar(r) || !first && isNameChar(r)):
+			sb.WriteRune(r)
+		case r > 0xFFFF:
+			fmt.Fprintf(&sb, "_x%08X_", r)
+		default:
+			fmt.Fprintf(&sb, "_x%04X_", r)
+		}
+		first = false
+	}
+	return sb.String()
+}
+
+// DecodeElementName 还原 EncodeElementName 编码的元素名，与 .NET 的 XmlConvert.DecodeName 相同
+//
+// 不是合法编码的 _x 序列保持原样，因此未编码的名称解码后不变。
+func DecodeElementName(name string) string {
+	if !strings.Contains(name, "_x") && !strings.Contains(name, "_X") {
+		return name
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(name); {
+		n := escapeAt(name, i)
+		if n == 0 {
+			sb.WriteByte(name[i])
+			i++
+			continue
+		}
+		code, _ := strconv.ParseUint(name[i+2:i+n-1], 16, 32)
+		r := rune(code)
+		i += n
+
+		// 4 位编码的代理对由两个连续的转义序列组成
+		if utf16.IsSurrogate(r) {
+			if m := escapeAt(name, i); m == 7 {
+				low, _ := strconv.ParseUint(name[i+2:i+m-1], 16, 32)
+				if combined := utf16.DecodeRune(r, rune(low)); combined != unicode.ReplacementChar {
+					r = combined
+					i += m
+				}
+			}
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// escapeAt 返回 name 中从 i 开始的 _xHHHH_ 或 _xHHHHHHHH_ 转义序列的长度，不是转义序列时返回 0
+func escapeAt(name string, i int) int {
+	if i+7 > len(name) || name[i] != '_' || (name[i+1] != 'x' && name[i+1] != 'X') {
+		return 0
+	}
+	for _, n := range []int{11, 7} {
+		if i+n <= len(name) && name[i+n-1] == '_' && isHex(name[i+2:i+n-1]) {
+			if n == 11 {
+				if code, _ := strconv.ParseUint(name[i+2:i+10], 16, 32); code > unicode.MaxRune {
+					continue
+				}
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+// isHex 判断字符串是否只由十六进制数字组成
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isNameStartChar 判断字符能否作为XML元素名的第一个字符
+func isNameStartChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isNameChar 判断字符能否出现在XML元素名中第一个字符之后
+func isNameChar(r rune) bool {
+	return isNameStartChar(r) || unicode.IsDigit(r) || r == '-' || r == '.' || r == 0xB7 ||
+		unicode.In(r, unicode.Mn, unicode.Mc)
+}
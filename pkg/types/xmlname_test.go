@@ -0,0 +1,78 @@
+package types
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestEncodeElementName(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"nuget.org", "nuget.org"},
+		{"My Feed", "My_x0020_Feed"},
+		{"1feed", "_x0031_feed"},
+		{"-feed", "_x002D_feed"},
+		{"a:b", "a_x003A_b"},
+		{"feed/v3", "feed_x002F_v3"},
+		{"公司源", "公司源"},
+		{"_x0020_", "_x005F_x0020_"},
+		{"a_b", "a_b"},
+		{"feed😀", "feed_x0001F600_"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := EncodeElementName(tt.name); got != tt.encoded {
+			t.Errorf("EncodeElementName(%q) = %q, want %q", tt.name, got, tt.encoded)
+		}
+		if got := DecodeElementName(tt.encoded); got != tt.name {
+			t.Errorf("DecodeElementName(%q) = %q, want %q", tt.encoded, got, tt.name)
+		}
+	}
+}
+
+func TestDecodeElementName(t *testing.T) {
+	tests := []struct {
+		encoded string
+		name    string
+	}{
+		{"My_X0020_Feed", "My Feed"},
+		{"_xD83D__xDE00_", "😀"},
+		{"_x00zz_", "_x00zz_"},
+		{"_x0020", "_x0020"},
+		{"feed_x", "feed_x"},
+	}
+	for _, tt := range tests {
+		if got := DecodeElementName(tt.encoded); got != tt.name {
+			t.Errorf("DecodeElementName(%q) = %q, want %q", tt.encoded, got, tt.name)
+		}
+	}
+}
+
+func TestCredentialElementNameRoundTrip(t *testing.T) {
+	creds := &PackageSourceCredentials{Sources: map[string]SourceCredential{
+		"My Feed": {Add: []Credential{{Key: "Username", Value: "ci"}}},
+	}}
+	data, err := xml.Marshal(struct {
+		XMLName xml.Name                  `xml:"configuration"`
+		Creds   *PackageSourceCredentials `xml:"packageSourceCredentials"`
+	}{Creds: creds})
+	if err != nil {
+		t.Fatalf("xml.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "<My_x0020_Feed>") {
+		t.Errorf("marshaled XML = %s, want an encoded element name", data)
+	}
+
+	var decoded struct {
+		Creds PackageSourceCredentials `xml:"packageSourceCredentials"`
+	}
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("xml.Unmarshal() error = %v", err)
+	}
+	if _, exists := decoded.Creds.Sources["My Feed"]; !exists {
+		t.Errorf("Sources = %v, want the decoded source name", decoded.Creds.Sources)
+	}
+}
@@ -277,6 +277,94 @@ func ExpandEnvVars(path string) string {
 	return os.ExpandEnv(path)
 }
 
+// ExpandEnvVarsWithLookup 使用指定的查询函数展开 NuGet 配置值中的环境变量
+//
+// 与 ExpandEnvVars 不同，%VAR% 形式在所有平台上都会被展开，这与 NuGet 处理配置值的方式一致；
+// 同时也支持 $VAR 和 ${VAR}。lookup 返回 false 的变量保持原样，而不是被替换为空字符串，
+// 以免路径被意外改写。
+//
+// 参数:
+//   - value: 包含环境变量的配置值
+//   - lookup: 查询环境变量的函数，通常为 os.LookupEnv
+//
+// 返回值:
+//   - string: 环境变量被替换后的值
+//
+// 示例:
+//
+//	folder := utils.ExpandEnvVarsWithLookup("%USERPROFILE%/.nuget/packages", os.LookupEnv)
+func ExpandEnvVarsWithLookup(value string, lookup func(string) (string, bool)) string {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(value, '%')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(value[start+1:], '%')
+		if end < 0 {
+			break
+		}
+		end += start + 1
+
+		name := value[start+1 : end]
+		if env, ok := lookup(name); ok && name != "" {
+			sb.WriteString(value[:start])
+			sb.WriteString(env)
+			value = value[end+1:]
+		} else {
+			// 未定义的变量保持原样，结尾的 % 可能是下一个变量的开头
+			sb.WriteString(value[:end])
+			value = value[end:]
+		}
+	}
+	sb.WriteString(value)
+
+	return expandShellVars(sb.String(), lookup)
+}
+
+// expandShellVars 展开 $VAR 和 ${VAR} 形式的环境变量
+//
+// 与 os.Expand 不同，lookup 返回 false 的变量保留原文，$VAR 不会被改写为 ${VAR}。
+func expandShellVars(value string, lookup func(string) (string, bool)) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+
+		var name string
+		end := i + 1
+		if value[i+1] == '{' {
+			closing := strings.IndexByte(value[i+2:], '}')
+			if closing < 0 {
+				sb.WriteByte(value[i])
+				continue
+			}
+			name = value[i+2 : i+2+closing]
+			end = i + 3 + closing
+		} else {
+			for end < len(value) && isShellNameByte(value[end]) {
+				end++
+			}
+			name = value[i+1 : end]
+		}
+
+		if env, ok := lookup(name); ok && name != "" {
+			sb.WriteString(env)
+		} else {
+			sb.WriteString(value[i:end])
+		}
+		i = end - 1
+	}
+	return sb.String()
+}
+
+// isShellNameByte 判断字节是否可以出现在 $VAR 形式的变量名中
+func isShellNameByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
 // IsURL 判断字符串是否为URL
 //
 // IsURL 检查给定的字符串是否是有效的 HTTP 或 HTTPS URL。
@@ -416,6 +504,74 @@ func WriteToFile(filePath string, data []byte) error {
 	return os.WriteFile(filePath, data, 0644)
 }
 
+// WriteFileAtomic 以原子方式将内容写入文件
+//
+// WriteFileAtomic 先将数据写入目标文件所在目录中的临时文件并同步到磁盘，
+// 再通过重命名原子地替换目标文件。这样即使写入过程中进程崩溃或磁盘写满，
+// 目标文件也只会是旧内容或新内容之一，而不会是写了一半的内容。
+// 如果目标文件已存在，会保留其权限位；否则使用 0644。
+//
+// 参数:
+//   - filePath: 要写入的文件路径
+//   - data: 要写入的数据
+//
+// 返回值:
+//   - error: 如果写入过程中发生错误则返回相应的错误；如果成功则为 nil
+//
+// 示例:
+//
+//	// 安全地覆盖配置文件
+//	err := utils.WriteFileAtomic("/path/to/NuGet.Config", modifiedContent)
+//	if err != nil {
+//	    fmt.Printf("保存配置文件失败: %v\n", err)
+//	    return
+//	}
+func WriteFileAtomic(filePath string, data []byte) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(filePath); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tempFile, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+
+	// 任何步骤失败时清理临时文件
+	success := false
+	defer func() {
+		if !success {
+			tempFile.Close()
+			os.Remove(tempPath)
+		}
+	}()
+
+	if _, err := tempFile.Write(data); err != nil {
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		return err
+	}
+
+	success = true
+	return nil
+}
+
 // TrimWhitespace 去除字符串首尾的空白字符
 //
 // TrimWhitespace 移除字符串开头和结尾的所有空白字符，包括空格、制表符、换行符等。
@@ -517,3 +673,64 @@ func TrimWhitespace(s string) string {
 func IsEmpty(s string) bool {
 	return TrimWhitespace(s) == ""
 }
+
+// ClosestMatch 在候选名称中查找与 name 最接近的一个
+//
+// ClosestMatch 用于在遇到未知的配置节、选项键名等时给出 "did you mean" 提示。
+// 比较不区分大小写，使用编辑距离衡量相似度；距离超过 name 长度的三分之一（至少为1）时
+// 认为没有足够接近的候选项。距离相同时返回 candidates 中靠前的一个。
+//
+// 参数:
+//   - name: 未知的名称
+//   - candidates: 已知的名称
+//
+// 返回值:
+//   - string: 最接近的候选名称，没有足够接近的候选项或 name 本身就是候选项时返回空字符串
+//
+// 示例:
+//
+//	utils.ClosestMatch("packagesources", []string{"config", "packageSources"})
+//	// 返回 "packageSources"
+//
+//	utils.ClosestMatch("globalPackageFolder", []string{"globalPackagesFolder", "repositoryPath"})
+//	// 返回 "globalPackagesFolder"
+func ClosestMatch(name string, candidates []string) string {
+	lower := strings.ToLower(name)
+	maxDistance := len(lower) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if candidate == name {
+			return ""
+		}
+		if d := editDistance(lower, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串之间的 Levenshtein 编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -440,6 +441,52 @@ func TestWriteToFile(t *testing.T) {
 	})
 }
 
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "nuget-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// 写入新文件
+	filePath := filepath.Join(tempDir, "sub", "NuGet.Config")
+	if err := WriteFileAtomic(filePath, []byte("first")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil || string(content) != "first" {
+		t.Fatalf("ReadFile() = %q, %v; want %q", content, err, "first")
+	}
+
+	// 覆盖已有文件时保留权限位
+	if err := os.Chmod(filePath, 0600); err != nil {
+		t.Fatalf("Failed to chmod: %v", err)
+	}
+	if err := WriteFileAtomic(filePath, []byte("second")); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	content, _ = os.ReadFile(filePath)
+	if string(content) != "second" {
+		t.Errorf("content = %q, want %q", content, "second")
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// 不应留下临时文件
+	entries, err := os.ReadDir(filepath.Dir(filePath))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory contains %d entries, want 1", len(entries))
+	}
+}
+
 func TestTrimWhitespace(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -530,3 +577,56 @@ func TestIsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandEnvVarsWithLookup(t *testing.T) {
+	env := map[string]string{"ROOT": "/data", "NAME": "pkgs"}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"%ROOT%/%NAME%", "/data/pkgs"},
+		{"$ROOT/${NAME}", "/data/pkgs"},
+		{"%MISSING%/%NAME%", "%MISSING%/pkgs"},
+		{"$MISSING/x", "$MISSING/x"},
+		{"${MISSING}/x", "${MISSING}/x"},
+		{"$UNSET_ZZ/foo", "$UNSET_ZZ/foo"},
+		{"$ROOT$NAME", "/datapkgs"},
+		{"cost $5 ${", "cost $5 ${"},
+		{"a$", "a$"},
+		{"100%", "100%"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := ExpandEnvVarsWithLookup(tt.value, lookup); got != tt.want {
+			t.Errorf("ExpandEnvVarsWithLookup(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"config", "packageSources", "packageSourceMapping", "globalPackagesFolder"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"packagesources", "packageSources"},
+		{"PackageSource", "packageSources"},
+		{"globalPackageFolder", "globalPackagesFolder"},
+		{"packageSourceMaping", "packageSourceMapping"},
+		{"confg", "config"},
+		{"packageSources", ""},
+		{"trustedSigners", ""},
+		{"x", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ClosestMatch(tt.name, candidates); got != tt.want {
+			t.Errorf("ClosestMatch(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
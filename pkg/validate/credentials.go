@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/finder"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// 凭证相关规则的名称
+const (
+	RuleCleartextPassword          = "cleartext-password"
+	RulePasswordInProjectConfig    = "password-in-project-config"
+	RuleMissingAuthenticationTypes = "missing-authentication-types"
+)
+
+// envReference 匹配整个值为环境变量引用的密码，如 %NUGET_PASSWORD% 或 $NUGET_PASSWORD
+var envReference = regexp.MustCompile(`^(%[A-Za-z_][A-Za-z0-9_]*%|\$[A-Za-z_][A-Za-z0-9_]*|\$\{[A-Za-z_][A-Za-z0-9_]*\})$`)
+
+// CredentialRules 返回检查凭证存储方式的规则
+//
+//   - cleartext-password: ClearTextPassword 中直接写入了密码，而不是引用环境变量
+//   - password-in-project-config: 项目级配置中包含密码，这类文件通常会提交到版本库
+//   - missing-authentication-types: 凭证没有用 ValidAuthenticationTypes 限制认证方式
+func CredentialRules() []Rule {
+	return []Rule{
+		NewRule(RuleCleartextPassword, "passwords should not be stored in clear text", SeverityWarning, checkCleartextPassword),
+		NewRule(RulePasswordInProjectConfig, "project-level configs should not contain passwords", SeverityError, checkProjectPassword),
+		NewRule(RuleMissingAuthenticationTypes, "credentials should restrict ValidAuthenticationTypes", SeverityInfo, checkAuthenticationTypes),
+	}
+}
+
+// IsProjectConfig 判断配置文件是否为项目级配置，即不是当前用户的用户级或机器级配置文件
+func IsProjectConfig(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return true
+	}
+
+	f := finder.NewConfigFinder()
+	for _, global := range []string{f.GetUserConfigFile(), f.GetMachineConfigFile()} {
+		if global == "" {
+			continue
+		}
+		if absGlobal, err := filepath.Abs(global); err == nil && strings.EqualFold(filepath.Clean(absGlobal), filepath.Clean(absPath)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPasswordKey 判断凭证项是否为密码
+func isPasswordKey(key string) bool {
+	return strings.EqualFold(key, "Password") || strings.EqualFold(key, "ClearTextPassword")
+}
+
+// literalSecret 判断值是否为直接写入的密码，空值和环境变量引用不算
+func literalSecret(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && !envReference.MatchString(value)
+}
+
+// eachCredential 按包源名称的顺序遍历凭证
+func eachCredential(config *types.NuGetConfig, fn func(source string, credential types.SourceCredential)) {
+	creds := config.PackageSourceCredentials
+	if creds == nil {
+		return
+	}
+	names := make([]string, 0, len(creds.Sources))
+	for name := range creds.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fn(name, creds.Sources[name])
+	}
+}
+
+// checkCleartextPassword 检查以明文存储的密码
+func checkCleartextPassword(ctx *Context) []Finding {
+	var findings []Finding
+	eachCredential(ctx.Config, func(source string, credential types.SourceCredential) {
+		for _, item := range credential.Add {
+			if !strings.EqualFold(item.Key, "ClearTextPassword") || !literalSecret(item.Value) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Section: "packageSourceCredentials",
+				Key:     source,
+				Message: "password is stored in clear text",
+				Hint:    "store an encrypted Password, or reference an environment variable such as %NUGET_PASSWORD% instead",
+				Element: ctx.CredentialItem(source, item.Key),
+			})
+		}
+	})
+	return findings
+}
+
+// checkProjectPassword 检查项目级配置中的密码
+func checkProjectPassword(ctx *Context) []Finding {
+	if !ctx.ProjectLevel {
+		return nil
+	}
+
+	var findings []Finding
+	eachCredential(ctx.Config, func(source string, credential types.SourceCredential) {
+		for _, item := range credential.Add {
+			if !isPasswordKey(item.Key) || !literalSecret(item.Value) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Section: "packageSourceCredentials",
+				Key:     source,
+				Message: "password is stored in a project-level config, which is usually committed to source control",
+				Hint:    "move the credentials to the user-level NuGet.Config, or reference an environment variable instead",
+				Element: ctx.CredentialItem(source, item.Key),
+			})
+		}
+	})
+	return findings
+}
+
+// checkAuthenticationTypes 检查没有设置 ValidAuthenticationTypes 的凭证
+func checkAuthenticationTypes(ctx *Context) []Finding {
+	var findings []Finding
+	eachCredential(ctx.Config, func(source string, credential types.SourceCredential) {
+		for _, item := range credential.Add {
+			if strings.EqualFold(item.Key, "ValidAuthenticationTypes") && strings.TrimSpace(item.Value) != "" {
+				return
+			}
+		}
+		findings = append(findings, Finding{
+			Section: "packageSourceCredentials",
+			Key:     source,
+			Message: "credentials may be sent with any authentication scheme the server offers",
+			Hint:    `add <add key="ValidAuthenticationTypes" value="basic" /> to restrict the schemes`,
+		})
+	})
+	return findings
+}
@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const credentialsConfig = `<configuration>
+  <packageSources>
+    <add key="team" value="https://team.example.com/v3/index.json" />
+    <add key="ci" value="https://ci.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <team>
+      <add key="Username" value="alice" />
+      <add key="ClearTextPassword" value="hunter2" />
+    </team>
+    <ci>
+      <add key="Username" value="bot" />
+      <add key="ClearTextPassword" value="%CI_PASSWORD%" />
+      <add key="ValidAuthenticationTypes" value="basic" />
+    </ci>
+  </packageSourceCredentials>
+</configuration>
+`
+
+func TestCredentialRules(t *testing.T) {
+	v := NewValidatorWithRules(CredentialRules()...)
+
+	result := parseWithPositions(t, credentialsConfig)
+	report := v.ValidateResult("", result)
+	want := []string{
+		RuleMissingAuthenticationTypes + "@7:5",
+		RuleCleartextPassword + "@9:7",
+	}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateResult() issues = %v, want %v", got, want)
+	}
+	for _, issue := range report.Issues {
+		if issue.Key != "team" || issue.Hint == "" {
+			t.Errorf("issue = %+v, want key team with a hint", issue)
+		}
+	}
+
+	// 项目级配置中的密码，环境变量引用不算
+	path := filepath.Join(t.TempDir(), "NuGet.Config")
+	if err := os.WriteFile(path, []byte(credentialsConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := v.ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	want = []string{
+		RuleMissingAuthenticationTypes + "@7:5",
+		RuleCleartextPassword + "@9:7",
+		RulePasswordInProjectConfig + "@9:7",
+	}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateFile() issues = %v, want %v", got, want)
+	}
+}
+
+func TestIsProjectConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	userConfig := filepath.Join(home, ".config", "NuGet", "NuGet.Config")
+	if IsProjectConfig(userConfig) {
+		t.Errorf("IsProjectConfig(%q) = true for the user config", userConfig)
+	}
+	if !IsProjectConfig(filepath.Join(home, "src", "NuGet.Config")) {
+		t.Error("IsProjectConfig() = false for a project config")
+	}
+}
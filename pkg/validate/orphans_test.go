@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+)
+
+func TestOrphanedEntriesInHierarchy(t *testing.T) {
+	dir := t.TempDir()
+	userConfig := filepath.Join(dir, "user.config")
+	projectConfig := filepath.Join(dir, "NuGet.Config")
+	os.WriteFile(userConfig, []byte(`<configuration>
+  <packageSources>
+    <add key="company" value="https://nuget.company.com/v3/index.json" />
+  </packageSources>
+</configuration>`), 0644)
+	os.WriteFile(projectConfig, []byte(`<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+  <disabledPackageSources>
+    <add key="old-feed" value="true" />
+  </disabledPackageSources>
+  <packageSourceMapping>
+    <packageSource key="company">
+      <package pattern="Company.*" />
+    </packageSource>
+    <packageSource key="contoso">
+      <package pattern="Contoso.*" />
+    </packageSource>
+  </packageSourceMapping>
+</configuration>`), 0644)
+
+	v := NewValidatorWithRules(DefaultRules()[:5]...)
+
+	// 单独校验项目级配置时，company 在本文件中未定义
+	report, err := v.ValidateFile(projectConfig)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	want := []string{RuleOrphanedEntry + "@6:5", RuleOrphanedEntry + "@9:5", RuleOrphanedEntry + "@12:5"}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateFile() issues = %v, want %v", got, want)
+	}
+
+	// 合并配置层级后，company 由用户级配置定义
+	reports, err := v.ValidateFiles(userConfig, projectConfig)
+	if err != nil {
+		t.Fatalf("ValidateFiles() error = %v", err)
+	}
+	if len(reports) != 2 || len(reports[0].Issues) != 0 || reports[1].Path != projectConfig {
+		t.Fatalf("ValidateFiles() reports = %+v", reports)
+	}
+	want = []string{RuleOrphanedEntry + "@6:5", RuleOrphanedEntry + "@12:5"}
+	if got := summarize(reports[1].Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateFiles() issues = %v, want %v", got, want)
+	}
+	if issue := reports[1].Issues[1]; issue.Section != "packageSourceMapping" || issue.Key != "contoso" {
+		t.Errorf("mapping issue = %+v", issue)
+	}
+
+	_, err = v.ValidateFiles(filepath.Join(dir, "missing.config"), userConfig, filepath.Join(dir, "other.config"))
+	if list, ok := err.(errors.ErrorList); !ok || list.Len() != 2 {
+		t.Errorf("ValidateFiles() with missing files error = %v, want both files reported", err)
+	}
+}
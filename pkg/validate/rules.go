@@ -0,0 +1,191 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// 内置规则的名称
+const (
+	RuleEmptySourceKey      = "empty-source-key"
+	RuleDuplicateSourceKey  = "duplicate-source-key"
+	RuleInvalidSourceValue  = "invalid-source-value"
+	RuleOrphanedEntry       = "orphaned-entry"
+	RuleInvalidConfigValue  = "invalid-config-value"
+	RuleUnknownConfigOption = "unknown-config-option"
+)
+
+// ruleCodes 内置规则的错误码
+var ruleCodes = map[string]errors.Code{
+	RuleEmptySourceKey:             errors.CodeEmptySourceKey,
+	RuleDuplicateSourceKey:         errors.CodeDuplicateSourceKey,
+	RuleInvalidSourceValue:         errors.CodeInvalidSourceValue,
+	RuleOrphanedEntry:              errors.CodeOrphanedEntry,
+	RuleInvalidConfigValue:         errors.CodeInvalidConfigValue,
+	RuleUnknownConfigOption:        errors.CodeUnknownConfigOption,
+	RuleInsecureSource:             errors.CodeInsecureSource,
+	RuleUnresolvedSourcePath:       errors.CodeUnresolvedSourcePath,
+	RuleCleartextPassword:          errors.CodeCleartextPassword,
+	RulePasswordInProjectConfig:    errors.CodePasswordInProjectConfig,
+	RuleMissingAuthenticationTypes: errors.CodeMissingAuthenticationTypes,
+}
+
+// RuleCode 返回内置规则的错误码，不是内置规则时返回空字符串
+func RuleCode(name string) errors.Code {
+	return ruleCodes[name]
+}
+
+// ruleCode 返回规则的错误码，实现了 errors.Coder 的规则使用自己的错误码
+func ruleCode(rule Rule) errors.Code {
+	if coder, ok := rule.(errors.Coder); ok {
+		return coder.Code()
+	}
+	return RuleCode(rule.Name())
+}
+
+// DefaultRules 返回默认规则集
+//
+// 包括 manager.ConfigManager.ValidateConfig 的全部检查、未知配置选项的检查，以及 SourceRules 中的
+// 包源地址检查和 CredentialRules 中的凭证检查。
+func DefaultRules() []Rule {
+	rules := []Rule{
+		NewRule(RuleEmptySourceKey, "package sources must have a non-empty key", SeverityError, checkEmptySourceKey),
+		NewRule(RuleDuplicateSourceKey, "package source keys must be unique, ignoring case", SeverityError, checkDuplicateSourceKey),
+		NewRule(RuleInvalidSourceValue, "package source values must be an http, https or file URL with a host, or a non-empty path", SeverityError, checkSourceValue),
+		NewRule(RuleOrphanedEntry, "disabled sources, credentials, the active source and mappings should refer to a defined package source", SeverityWarning, checkOrphanedEntries),
+		NewRule(RuleInvalidConfigValue, "known config options must have a valid value", SeverityError, checkConfigValue),
+		NewRule(RuleUnknownConfigOption, "config option keys should be options known to NuGet", SeverityWarning, checkUnknownConfigOption),
+	}
+	rules = append(rules, SourceRules()...)
+	return append(rules, CredentialRules()...)
+}
+
+// checkEmptySourceKey 检查名称为空的包源
+func checkEmptySourceKey(ctx *Context) []Finding {
+	var findings []Finding
+	empty := ctx.Elements("packageSources", "")
+	for _, source := range ctx.Config.PackageSources.Add {
+		if strings.TrimSpace(source.Key) != "" {
+			continue
+		}
+		f := Finding{Section: "packageSources", Message: fmt.Sprintf("package source with value %q has an empty key", source.Value)}
+		if len(empty) > 0 {
+			f.Element, empty = empty[0], empty[1:]
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// checkDuplicateSourceKey 检查重复的包源名称，问题指向第二个及之后出现的元素
+func checkDuplicateSourceKey(ctx *Context) []Finding {
+	var findings []Finding
+	first := make(map[string]string)
+	seen := make(map[string]int)
+	for _, source := range ctx.Config.PackageSources.Add {
+		key := strings.ToLower(strings.TrimSpace(source.Key))
+		if key == "" {
+			continue
+		}
+		seen[key]++
+		if _, exists := first[key]; !exists {
+			first[key] = source.Key
+			continue
+		}
+
+		f := Finding{Section: "packageSources", Key: source.Key,
+			Message: fmt.Sprintf("duplicate package source key, already defined as '%s'", first[key])}
+		if elements := ctx.Elements("packageSources", source.Key); seen[key]-1 < len(elements) {
+			f.Element = elements[seen[key]-1]
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// checkSourceValue 检查包源地址
+func checkSourceValue(ctx *Context) []Finding {
+	var findings []Finding
+	for _, source := range ctx.Config.PackageSources.Add {
+		if strings.TrimSpace(source.Key) == "" {
+			continue
+		}
+		if err := manager.ValidateSourceURL(source.Value); err != nil {
+			findings = append(findings, Finding{Section: "packageSources", Key: source.Key, Message: err.Error()})
+		}
+	}
+	return findings
+}
+
+// orphanMessages 各配置节中引用了未定义包源的配置项的描述
+var orphanMessages = map[string]string{
+	"disabledPackageSources":   "disabled package source is not defined",
+	"packageSourceCredentials": "credentials for unknown package source",
+	"activePackageSource":      "active package source is not defined",
+	"packageSourceMapping":     "package source mapping refers to an undefined package source",
+}
+
+// checkOrphanedEntries 检查引用了未定义包源的配置项
+//
+// 包源可以定义在配置层级中的其他文件里，设置了 Context.Effective 时按生效配置中的包源判断。
+func checkOrphanedEntries(ctx *Context) []Finding {
+	probe := *ctx.Config
+	probe.PackageSources = ctx.effective().PackageSources
+
+	var findings []Finding
+	for _, orphan := range manager.NewConfigManager().FindOrphanedEntries(&probe) {
+		findings = append(findings, Finding{
+			Section: orphan.Section,
+			Key:     orphan.Key,
+			Message: orphanMessages[orphan.Section],
+			Hint:    "define the package source in <packageSources> or remove this entry",
+		})
+	}
+	return findings
+}
+
+// checkConfigValue 检查 types.KnownConfigOptions 中已知选项的取值
+func checkConfigValue(ctx *Context) []Finding {
+	if ctx.Config.Config == nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, option := range ctx.Config.Config.Add {
+		spec, known := types.LookupConfigOption(option.Key)
+		if !known {
+			continue
+		}
+		if err := spec.Validate(option.Value); err != nil {
+			findings = append(findings, Finding{Section: "config", Key: option.Key, Message: err.Error()})
+		}
+	}
+	return findings
+}
+
+// checkUnknownConfigOption 检查 types.KnownConfigOptions 以外的选项，NuGet 会忽略这些选项
+//
+// 键名与某个已知选项足够接近时，在建议中给出最可能的正确拼写。
+func checkUnknownConfigOption(ctx *Context) []Finding {
+	if ctx.Config.Config == nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, option := range ctx.Config.Config.Add {
+		if _, known := types.LookupConfigOption(option.Key); known || strings.TrimSpace(option.Key) == "" {
+			continue
+		}
+		f := Finding{Section: "config", Key: option.Key, Message: "unknown config option, NuGet ignores it"}
+		if suggestion := types.SuggestConfigOption(option.Key); suggestion != "" {
+			f.Message = fmt.Sprintf("unknown config option, did you mean '%s'?", suggestion)
+			f.Hint = fmt.Sprintf("rename the key to '%s'", suggestion)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
@@ -0,0 +1,74 @@
+package validate
+
+import (
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// 包源地址相关规则的名称
+const (
+	RuleInsecureSource       = "insecure-source"
+	RuleUnresolvedSourcePath = "unresolved-source-path"
+)
+
+// SourceRules 返回检查包源地址的规则
+//
+//   - insecure-source: 包源通过 http:// 访问，且没有设置 allowInsecureConnections="true"
+//   - unresolved-source-path: 本地路径包源是相对路径，且按配置文件所在目录解析后不存在
+//
+// 地址格式不正确的包源由 invalid-source-value 报告，这里不再检查。
+func SourceRules() []Rule {
+	return []Rule{
+		NewRule(RuleInsecureSource, "package sources should use HTTPS unless allowInsecureConnections is set", SeverityWarning, checkInsecureSource),
+		NewRule(RuleUnresolvedSourcePath, "local package sources should be absolute paths or resolve relative to the config file", SeverityWarning, checkSourcePath),
+	}
+}
+
+// eachWellFormedSource 遍历名称不为空且地址格式正确的包源
+func eachWellFormedSource(ctx *Context, fn func(source types.PackageSource)) {
+	for _, source := range ctx.Config.PackageSources.Add {
+		if strings.TrimSpace(source.Key) == "" || manager.ValidateSourceURL(source.Value) != nil {
+			continue
+		}
+		fn(source)
+	}
+}
+
+// sourceFinding 创建指向包源在文件中第一次出现的位置的问题
+func sourceFinding(ctx *Context, key string, err error, hint string) Finding {
+	f := Finding{Section: "packageSources", Key: key, Message: err.Error(), Hint: hint}
+	if elements := ctx.Elements("packageSources", key); len(elements) > 0 {
+		f.Element = elements[0]
+	}
+	return f
+}
+
+// checkInsecureSource 检查使用 http:// 的包源
+func checkInsecureSource(ctx *Context) []Finding {
+	var findings []Finding
+	eachWellFormedSource(ctx, func(source types.PackageSource) {
+		if err := manager.CheckInsecureSource(source); err != nil {
+			findings = append(findings, sourceFinding(ctx, source.Key, err,
+				`switch the feed to https://, or add allowInsecureConnections="true" to the source if HTTP is intended`))
+		}
+	})
+	return findings
+}
+
+// checkSourcePath 检查无法解析的本地路径包源，没有配置文件路径时无法解析相对路径，不进行检查
+func checkSourcePath(ctx *Context) []Finding {
+	if ctx.Path == "" {
+		return nil
+	}
+
+	var findings []Finding
+	eachWellFormedSource(ctx, func(source types.PackageSource) {
+		if err := manager.CheckSourcePath(source.Value, ctx.Path); err != nil {
+			findings = append(findings, sourceFinding(ctx, source.Key, err,
+				"use an absolute path, or a path relative to the directory of this config file"))
+		}
+	})
+	return findings
+}
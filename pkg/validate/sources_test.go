@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sourcesConfig = `<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="legacy" value="http://legacy.example.com/nuget" />
+    <add key="intranet" value="http://intranet.example.com/nuget" allowInsecureConnections="true" />
+    <add key="local" value="feed" />
+    <add key="missing" value="../missing-feed" />
+    <add key="shared" value="/srv/nuget" />
+    <add key="ftp" value="ftp://ftp.example.com/nuget" />
+  </packageSources>
+</configuration>
+`
+
+func TestSourceRules(t *testing.T) {
+	v := NewValidatorWithRules(SourceRules()...)
+
+	// 没有配置文件路径时只检查 http 包源
+	report := v.ValidateResult("", parseWithPositions(t, sourcesConfig))
+	want := []string{RuleInsecureSource + "@4:5"}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateResult() issues = %v, want %v", got, want)
+	}
+	if issue := report.Issues[0]; issue.Key != "legacy" || issue.Hint == "" {
+		t.Errorf("issue = %+v, want key legacy with a hint", issue)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "NuGet.Config")
+	if err := os.WriteFile(path, []byte(sourcesConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "feed"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	report, err := v.ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	want = []string{RuleInsecureSource + "@4:5", RuleUnresolvedSourcePath + "@7:5"}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateFile() issues = %v, want %v", got, want)
+	}
+
+	// 不支持的协议由 invalid-source-value 报告
+	report = NewValidator().ValidateResult("", parseWithPositions(t, sourcesConfig))
+	if report.Count(SeverityError) != 1 || report.Issues[len(report.Issues)-1].Key != "ftp" {
+		t.Errorf("NewValidator() issues = %v", report.Issues)
+	}
+}
@@ -0,0 +1,466 @@
+// Package validate 以可插拔的规则检查 NuGet 配置
+//
+// 每条规则有唯一的名称和默认严重程度，Validator 可以单独启用、禁用规则或调整其严重程度。
+// 校验位置感知解析器的结果时，报告中的问题带有所在元素的行列号。
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/manager"
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// Severity 问题的严重程度
+type Severity int
+
+const (
+	// SeverityError NuGet 无法正确使用的配置
+	SeverityError Severity = iota
+	// SeverityWarning NuGet 可以容忍但很可能是错误的配置
+	SeverityWarning
+	// SeverityInfo 值得注意但不一定需要修改的配置
+	SeverityInfo
+)
+
+// String 返回严重程度的名称
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity 解析严重程度的名称：error、warning 或 info
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	}
+	return 0, fmt.Errorf("unknown severity '%s', expected error, warning or info", name)
+}
+
+// Finding 规则发现的一个问题，规则名称、严重程度和位置由 Validator 补充
+type Finding struct {
+	// Section 问题所在的配置节，例如 "packageSources"
+	Section string
+	// Key 问题涉及的包源名称或选项键名，可能为空
+	Key     string
+	Message string
+	// Hint 修复问题的建议，可能为空
+	Hint string
+	// Element 问题所在的元素，为 nil 时 Validator 按 Section 和 Key 查找
+	Element *parser.ElementPosition
+}
+
+// Rule 校验规则
+//
+// 规则可以同时实现 errors.Coder，为发现的问题指定错误码。
+type Rule interface {
+	// Name 规则名称，在同一个 Validator 中唯一，例如 "duplicate-source-key"
+	Name() string
+	// Description 规则检查内容的简短说明
+	Description() string
+	// DefaultSeverity 规则发现的问题的默认严重程度
+	DefaultSeverity() Severity
+	// Check 检查配置并返回发现的问题
+	Check(ctx *Context) []Finding
+}
+
+// NewRule 用函数创建规则
+func NewRule(name, description string, severity Severity, check func(ctx *Context) []Finding) Rule {
+	return &funcRule{name: name, description: description, severity: severity, check: check}
+}
+
+// funcRule 以函数实现的规则
+type funcRule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(ctx *Context) []Finding
+}
+
+func (r *funcRule) Name() string                 { return r.name }
+func (r *funcRule) Description() string          { return r.description }
+func (r *funcRule) DefaultSeverity() Severity    { return r.severity }
+func (r *funcRule) Check(ctx *Context) []Finding { return r.check(ctx) }
+
+// Context 规则检查时可以使用的信息
+type Context struct {
+	// Config 要检查的配置
+	Config *types.NuGetConfig
+	// Path 配置文件路径，校验内存中的配置时为空
+	Path string
+	// Result 位置感知解析器的结果，没有位置信息时为 nil
+	Result *parser.ParseResult
+	// ProjectLevel 配置文件是项目级配置，通常随源代码一起提交。
+	// 由 IsProjectConfig 根据 Path 判断，校验内存中的配置时为 false
+	ProjectLevel bool
+	// Effective 合并配置层级后生效的配置，为 nil 时规则只能看到 Config 本身。
+	// 引用其他文件中定义的包源的配置项需要根据它判断
+	Effective *types.NuGetConfig
+}
+
+// effective 返回生效的配置，没有合并配置层级时返回 Config
+func (c *Context) effective() *types.NuGetConfig {
+	if c.Effective != nil {
+		return c.Effective
+	}
+	return c.Config
+}
+
+// Elements 返回配置节中与键名对应的元素，按在文件中出现的顺序排列，没有位置信息时返回 nil
+//
+// 凭证以包源名称作为元素名，包源映射中的 <packageSource> 元素和其他配置节中的 <add> 元素
+// 以 key 属性标识，不区分大小写。
+func (c *Context) Elements(section, key string) []*parser.ElementPosition {
+	if c.Result == nil {
+		return nil
+	}
+	prefix := "configuration/" + section + "/"
+
+	var elements []*parser.ElementPosition
+	for path, elem := range c.Result.Positions {
+		if !strings.HasPrefix(path, prefix) || strings.Contains(path[len(prefix):], "/") {
+			continue
+		}
+		name := elem.Attributes["key"]
+		switch {
+		case section == "packageSourceCredentials":
+			name = elem.TagName
+		case section == "packageSourceMapping":
+			if elem.TagName != "packageSource" {
+				continue
+			}
+		case elem.TagName != "add":
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(key)) {
+			elements = append(elements, elem)
+		}
+	}
+
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].Range.Start.Offset < elements[j].Range.Start.Offset
+	})
+	return elements
+}
+
+// CredentialItem 返回包源凭证中指定键名的 <add> 元素，不区分大小写，没有位置信息或不存在时返回 nil
+func (c *Context) CredentialItem(source, key string) *parser.ElementPosition {
+	credentials := c.Elements("packageSourceCredentials", source)
+	if len(credentials) == 0 {
+		return nil
+	}
+	credential := credentials[0]
+
+	var found *parser.ElementPosition
+	for _, elem := range c.Result.Positions {
+		if elem.TagName != "add" || !strings.EqualFold(elem.Attributes["key"], key) {
+			continue
+		}
+		inside := elem.Range.Start.Offset > credential.Range.Start.Offset && elem.Range.End.Offset <= credential.Range.End.Offset
+		if inside && (found == nil || elem.Range.Start.Offset < found.Range.Start.Offset) {
+			found = elem
+		}
+	}
+	return found
+}
+
+// Section 返回配置节元素，没有位置信息或配置节不存在时返回 nil
+func (c *Context) Section(section string) *parser.ElementPosition {
+	if c.Result == nil {
+		return nil
+	}
+	return c.Result.Positions["configuration/"+section]
+}
+
+// Issue 报告中的一个问题
+type Issue struct {
+	// Rule 发现问题的规则名称
+	Rule     string
+	Severity Severity
+	// Code 问题的错误码，见 RuleCode，没有错误码的自定义规则为空
+	Code    errors.Code
+	Section string
+	Key     string
+	Message string
+	// Hint 修复问题的建议，可能为空
+	Hint string
+	// Line 和 Column 问题所在元素的行列号，从1开始，没有位置信息时为 0
+	Line   int
+	Column int
+}
+
+// String 返回问题的单行描述，有位置信息时以 "行:列: " 开头
+func (i Issue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", i.Line, i.Column)
+	}
+	if i.Key == "" {
+		fmt.Fprintf(&b, "%s: <%s>: %s", i.Severity, i.Section, i.Message)
+	} else {
+		fmt.Fprintf(&b, "%s: <%s> '%s': %s", i.Severity, i.Section, i.Key, i.Message)
+	}
+	fmt.Fprintf(&b, " [%s]", i.Rule)
+	return b.String()
+}
+
+// Error 与 String 相同，使 Issue 可以作为 error 返回
+func (i Issue) Error() string {
+	return i.String()
+}
+
+// Location 返回问题的行号和列号，实现 errors.Locator
+func (i Issue) Location() (line, column int) {
+	return i.Line, i.Column
+}
+
+// Report 一次校验的结果
+type Report struct {
+	// Path 被校验的配置文件，校验内存中的配置时为空
+	Path string
+	// Issues 发现的问题，有位置信息时按位置排列，否则按规则的顺序排列
+	Issues []Issue
+}
+
+// HasErrors 判断报告中是否有 SeverityError 级别的问题
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Err 将 SeverityError 级别的问题转换为 errors.ErrorList，没有时返回 nil
+//
+// errors.CodeOf 返回问题的错误码；Path 不为空时，每个问题包装为记录了文件路径的 *errors.ConfigError。
+func (r *Report) Err() error {
+	var list errors.ErrorList
+	for _, issue := range r.Issues {
+		if issue.Severity != SeverityError {
+			continue
+		}
+		var err error = issue
+		if issue.Code != "" {
+			err = errors.WithCode(issue.Code, err)
+		}
+		if r.Path != "" {
+			err = errors.WrapConfigError(errors.OpValidate, r.Path, err)
+		}
+		list.Add(err)
+	}
+	return list.Err()
+}
+
+// Count 返回指定严重程度的问题数量
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Validator 按规则集校验配置
+type Validator struct {
+	rules    []Rule
+	disabled map[string]bool
+	severity map[string]Severity
+}
+
+// NewValidator 创建使用 DefaultRules 的校验器
+func NewValidator() *Validator {
+	return NewValidatorWithRules(DefaultRules()...)
+}
+
+// NewValidatorWithRules 创建使用指定规则的校验器，名称重复的规则只保留第一个
+func NewValidatorWithRules(rules ...Rule) *Validator {
+	v := &Validator{disabled: make(map[string]bool), severity: make(map[string]Severity)}
+	for _, rule := range rules {
+		v.AddRule(rule)
+	}
+	return v
+}
+
+// AddRule 添加规则，已有同名规则时返回错误
+func (v *Validator) AddRule(rule Rule) error {
+	if v.rule(rule.Name()) != nil {
+		return fmt.Errorf("rule '%s' already exists", rule.Name())
+	}
+	v.rules = append(v.rules, rule)
+	return nil
+}
+
+// Rules 返回所有规则，包括被禁用的规则
+func (v *Validator) Rules() []Rule {
+	return append([]Rule(nil), v.rules...)
+}
+
+// Enable 启用规则
+func (v *Validator) Enable(name string) error {
+	if v.rule(name) == nil {
+		return fmt.Errorf("unknown rule '%s'", name)
+	}
+	delete(v.disabled, name)
+	return nil
+}
+
+// Disable 禁用规则
+func (v *Validator) Disable(name string) error {
+	if v.rule(name) == nil {
+		return fmt.Errorf("unknown rule '%s'", name)
+	}
+	v.disabled[name] = true
+	return nil
+}
+
+// IsEnabled 判断规则是否存在且已启用
+func (v *Validator) IsEnabled(name string) bool {
+	return v.rule(name) != nil && !v.disabled[name]
+}
+
+// SetSeverity 设置规则发现的问题的严重程度，覆盖规则的默认值
+func (v *Validator) SetSeverity(name string, severity Severity) error {
+	if v.rule(name) == nil {
+		return fmt.Errorf("unknown rule '%s'", name)
+	}
+	v.severity[name] = severity
+	return nil
+}
+
+// Severity 返回规则当前使用的严重程度，规则不存在时返回 false
+func (v *Validator) Severity(name string) (Severity, bool) {
+	rule := v.rule(name)
+	if rule == nil {
+		return 0, false
+	}
+	if severity, ok := v.severity[name]; ok {
+		return severity, true
+	}
+	return rule.DefaultSeverity(), true
+}
+
+// Validate 校验内存中的配置，报告中的问题没有位置信息
+func (v *Validator) Validate(config *types.NuGetConfig) *Report {
+	return v.ValidateContext(&Context{Config: config})
+}
+
+// ValidateResult 校验位置感知解析器的结果，path 为配置文件路径，可以为空
+func (v *Validator) ValidateResult(path string, result *parser.ParseResult) *Report {
+	return v.ValidateContext(&Context{Config: result.Config, Path: path, Result: result, ProjectLevel: path != "" && IsProjectConfig(path)})
+}
+
+// ValidateFiles 校验配置层级中的每个文件，paths 按优先级从低到高排列
+//
+// 每个文件都在合并了所有文件的生效配置下校验，例如项目级配置中的包源映射可以引用
+// 用户级配置中定义的包源。返回的报告与 paths 一一对应；有文件无法解析时不进行校验，
+// 返回包含所有文件的解析错误的 errors.ErrorList。
+func (v *Validator) ValidateFiles(paths ...string) ([]*Report, error) {
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = true
+
+	var parseErrors errors.ErrorList
+	results := make([]*parser.ParseResult, len(paths))
+	for i, path := range paths {
+		result, err := p.ParseFromFileWithPositions(path)
+		parseErrors.Add(err)
+		results[i] = result
+	}
+	if err := parseErrors.Err(); err != nil {
+		return nil, err
+	}
+
+	effective := &types.NuGetConfig{}
+	for _, result := range results {
+		var err error
+		if effective, err = manager.MergeConfigs(effective, result.Config, manager.MergeOverlayWins); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := make([]*Report, len(paths))
+	for i, path := range paths {
+		reports[i] = v.ValidateContext(&Context{Config: results[i].Config, Path: path, Result: results[i],
+			ProjectLevel: IsProjectConfig(path), Effective: effective})
+	}
+	return reports, nil
+}
+
+// ValidateFile 使用位置感知解析器解析配置文件并校验，文件无法解析时返回错误
+//
+// 不定义包源的配置文件（常见于用户级和机器级配置）可以正常校验。
+func (v *Validator) ValidateFile(path string) (*Report, error) {
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = true
+	result, err := p.ParseFromFileWithPositions(path)
+	if err != nil {
+		return nil, err
+	}
+	return v.ValidateResult(path, result), nil
+}
+
+// ValidateContext 使用调用方准备的上下文校验，用于需要自行设置 Effective 等字段的场景
+func (v *Validator) ValidateContext(ctx *Context) *Report {
+	report := &Report{Path: ctx.Path, Issues: []Issue{}}
+	for _, rule := range v.rules {
+		if v.disabled[rule.Name()] {
+			continue
+		}
+		severity, _ := v.Severity(rule.Name())
+		for _, f := range rule.Check(ctx) {
+			issue := Issue{Rule: rule.Name(), Severity: severity, Code: ruleCode(rule), Section: f.Section, Key: f.Key, Message: f.Message, Hint: f.Hint}
+			if elem := locate(ctx, f); elem != nil {
+				issue.Line, issue.Column = elem.Range.Start.Line, elem.Range.Start.Column
+			}
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	if ctx.Result != nil {
+		sort.SliceStable(report.Issues, func(i, j int) bool {
+			a, b := report.Issues[i], report.Issues[j]
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Column < b.Column
+		})
+	}
+	return report
+}
+
+// locate 返回问题所在的元素：规则指定的元素、第一个同名元素或所在的配置节
+func locate(ctx *Context, f Finding) *parser.ElementPosition {
+	if f.Element != nil {
+		return f.Element
+	}
+	if f.Key != "" {
+		if elements := ctx.Elements(f.Section, f.Key); len(elements) > 0 {
+			return elements[0]
+		}
+	}
+	return ctx.Section(f.Section)
+}
+
+// rule 按名称查找规则
+func (v *Validator) rule(name string) Rule {
+	for _, rule := range v.rules {
+		if rule.Name() == name {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package validate
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const testConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+    <add key="broken" value="https:///index.json" />
+    <add key="NuGet.org" value="https://mirror.example.com/v3/index.json" />
+  </packageSources>
+  <packageSourceCredentials>
+    <ghost>
+      <add key="Username" value="user" />
+    </ghost>
+  </packageSourceCredentials>
+  <config>
+    <add key="signatureValidationMode" value="sometimes" />
+  </config>
+</configuration>
+`
+
+// parseWithPositions 使用位置感知解析器解析配置
+func parseWithPositions(t *testing.T, content string) *parser.ParseResult {
+	t.Helper()
+	p := parser.NewPositionAwareParser()
+	p.AllowEmptyPackageSources = true
+	result, err := p.ParseFromContentWithPositions([]byte(content))
+	if err != nil {
+		t.Fatalf("ParseFromContentWithPositions() error = %v", err)
+	}
+	return result
+}
+
+// summarize 将问题简化为 "规则@行:列" 的形式
+func summarize(issues []Issue) []string {
+	var out []string
+	for _, issue := range issues {
+		out = append(out, fmt.Sprintf("%s@%d:%d", issue.Rule, issue.Line, issue.Column))
+	}
+	return out
+}
+
+func TestValidateResultPositions(t *testing.T) {
+	report := NewValidator().ValidateResult("NuGet.Config", parseWithPositions(t, testConfig))
+
+	want := []string{
+		RuleInvalidSourceValue + "@5:5",
+		RuleDuplicateSourceKey + "@6:5",
+		RuleOrphanedEntry + "@9:5",
+		RuleMissingAuthenticationTypes + "@9:5",
+		RuleInvalidConfigValue + "@14:5",
+	}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateResult() issues = %v, want %v", got, want)
+	}
+	if !report.HasErrors() || report.Count(SeverityError) != 3 || report.Count(SeverityWarning) != 1 {
+		t.Errorf("report counts: errors %d, warnings %d", report.Count(SeverityError), report.Count(SeverityWarning))
+	}
+	if got := report.Issues[1].String(); got != "6:5: error: <packageSources> 'NuGet.org': duplicate package source key, already defined as 'nuget.org' [duplicate-source-key]" {
+		t.Errorf("Issue.String() = %q", got)
+	}
+}
+
+func TestValidateWithoutPositions(t *testing.T) {
+	config := &types.NuGetConfig{PackageSources: types.PackageSources{Add: []types.PackageSource{
+		{Key: "", Value: "https://example.com/v3/index.json"},
+		{Key: "a", Value: "https://a.example.com/v3/index.json"},
+		{Key: "A", Value: "https://b.example.com/v3/index.json"},
+	}}}
+
+	report := NewValidator().Validate(config)
+	if len(report.Issues) != 2 || report.Issues[0].Rule != RuleEmptySourceKey || report.Issues[1].Rule != RuleDuplicateSourceKey {
+		t.Fatalf("Validate() issues = %+v", report.Issues)
+	}
+	if report.Issues[0].Line != 0 || strings.HasPrefix(report.Issues[0].String(), "0:") {
+		t.Errorf("issue without positions = %+v", report.Issues[0])
+	}
+}
+
+func TestValidatorConfiguration(t *testing.T) {
+	v := NewValidator()
+	if err := v.Disable(RuleDuplicateSourceKey); err != nil {
+		t.Fatalf("Disable() error = %v", err)
+	}
+	if err := v.SetSeverity(RuleInvalidSourceValue, SeverityInfo); err != nil {
+		t.Fatalf("SetSeverity() error = %v", err)
+	}
+	if v.IsEnabled(RuleDuplicateSourceKey) || !v.IsEnabled(RuleInvalidSourceValue) {
+		t.Error("IsEnabled() does not reflect Disable()")
+	}
+
+	report := v.ValidateResult("", parseWithPositions(t, testConfig))
+	for _, issue := range report.Issues {
+		if issue.Rule == RuleDuplicateSourceKey {
+			t.Errorf("disabled rule reported %+v", issue)
+		}
+		if issue.Rule == RuleInvalidSourceValue && issue.Severity != SeverityInfo {
+			t.Errorf("severity override ignored: %+v", issue)
+		}
+	}
+
+	if err := v.Enable(RuleDuplicateSourceKey); err != nil || !v.IsEnabled(RuleDuplicateSourceKey) {
+		t.Errorf("Enable() error = %v", err)
+	}
+	for _, err := range []error{v.Disable("missing"), v.Enable("missing"), v.SetSeverity("missing", SeverityError)} {
+		if err == nil {
+			t.Error("configuring an unknown rule should fail")
+		}
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	rule := NewRule("no-local-sources", "package sources must be remote", SeverityWarning, func(ctx *Context) []Finding {
+		var findings []Finding
+		for _, source := range ctx.Config.PackageSources.Add {
+			if !strings.Contains(source.Value, "://") {
+				findings = append(findings, Finding{Section: "packageSources", Key: source.Key, Message: "local package source"})
+			}
+		}
+		return findings
+	})
+
+	v := NewValidatorWithRules(rule)
+	if err := v.AddRule(rule); err == nil {
+		t.Error("AddRule() with a duplicate name should fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "NuGet.Config")
+	os.WriteFile(path, []byte(`<configuration>
+  <packageSources>
+    <add key="local" value="/packages" />
+  </packageSources>
+</configuration>`), 0644)
+	report, err := v.ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	want := []Issue{{Rule: "no-local-sources", Severity: SeverityWarning, Section: "packageSources", Key: "local",
+		Message: "local package source", Line: 3, Column: 5}}
+	if report.Path != path || !reflect.DeepEqual(report.Issues, want) {
+		t.Errorf("ValidateFile() = %+v", report)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []Severity{SeverityError, SeverityWarning, SeverityInfo} {
+		if got, err := ParseSeverity(strings.ToUpper(s.String())); err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity() with an unknown name should fail")
+	}
+}
+
+func TestReportErr(t *testing.T) {
+	report := NewValidator().ValidateResult("", parseWithPositions(t, testConfig))
+	err := report.Err()
+	var list errors.ErrorList
+	if !stderrors.As(err, &list) || list.Len() != report.Count(SeverityError) {
+		t.Fatalf("Err() = %v, want %d errors", err, report.Count(SeverityError))
+	}
+	var issue Issue
+	if !stderrors.As(list[0], &issue) || issue.Line != 5 {
+		t.Errorf("Err()[0] = %v, want the issue at line 5", list[0])
+	}
+
+	report.Path = "NuGet.Config"
+	if path := errors.ConfigErrorPath(report.Err().(errors.ErrorList)[0]); path != "NuGet.Config" {
+		t.Errorf("ConfigErrorPath() = %q, want NuGet.Config", path)
+	}
+
+	clean := &Report{Issues: []Issue{{Rule: "x", Severity: SeverityWarning}}}
+	if clean.Err() != nil {
+		t.Error("Err() with only warnings should be nil")
+	}
+}
+
+// codedRule 带有自定义错误码的规则
+type codedRule struct {
+	Rule
+}
+
+func (codedRule) Code() errors.Code { return "CUSTOM001" }
+
+func TestIssueCodes(t *testing.T) {
+	report := NewValidator().ValidateResult("", parseWithPositions(t, testConfig))
+	for _, issue := range report.Issues {
+		if issue.Code == "" || issue.Code != RuleCode(issue.Rule) {
+			t.Errorf("issue %+v: Code = %q, want %q", issue, issue.Code, RuleCode(issue.Rule))
+		}
+	}
+	if err := report.Err(); errors.CodeOf(err.(errors.ErrorList)[0]) != report.Issues[0].Code {
+		t.Errorf("CodeOf(Err()[0]) = %q, want %q", errors.CodeOf(err.(errors.ErrorList)[0]), report.Issues[0].Code)
+	}
+	if RuleCode("no-such-rule") != "" {
+		t.Error("RuleCode() of an unknown rule should be empty")
+	}
+
+	rule := codedRule{NewRule("custom", "custom rule", SeverityError, func(ctx *Context) []Finding {
+		return []Finding{{Section: "packageSources", Message: "custom finding"}}
+	})}
+	report = NewValidatorWithRules(rule).ValidateResult("", parseWithPositions(t, testConfig))
+	if len(report.Issues) != 1 || report.Issues[0].Code != "CUSTOM001" {
+		t.Errorf("custom rule issues = %+v", report.Issues)
+	}
+}
+
+func TestUnknownConfigOption(t *testing.T) {
+	v := NewValidatorWithRules(NewRule(RuleUnknownConfigOption, "", SeverityWarning, checkUnknownConfigOption))
+	report := v.ValidateResult("", parseWithPositions(t, `<configuration>
+  <config>
+    <add key="globalPackagesFolder" value="/packages" />
+    <add key="globalPackageFolder" value="/packages" />
+    <add key="myToolSetting" value="on" />
+  </config>
+</configuration>`))
+
+	want := []string{RuleUnknownConfigOption + "@4:5", RuleUnknownConfigOption + "@5:5"}
+	if got := summarize(report.Issues); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ValidateResult() issues = %v, want %v", got, want)
+	}
+	if issue := report.Issues[0]; issue.Message != "unknown config option, did you mean 'globalPackagesFolder'?" || issue.Hint == "" {
+		t.Errorf("misspelled option issue = %+v", issue)
+	}
+	if issue := report.Issues[1]; issue.Message != "unknown config option, NuGet ignores it" || issue.Hint != "" {
+		t.Errorf("unknown option issue = %+v", issue)
+	}
+}
@@ -0,0 +1,159 @@
+// Package watcher 提供监视NuGet配置文件变化的功能
+//
+// 监视基于定期轮询而不是 fsnotify 等文件系统通知：本模块只依赖标准库，轮询在
+// 网络文件系统、容器挂载卷和编辑器"写临时文件再重命名"的保存方式下行为一致。
+// NuGet 配置文件很小，每次检查除修改时间和大小外还比较内容摘要，因此即使重写后
+// 大小不变、修改时间因文件系统精度不足而相同，变化也不会被漏掉。
+package watcher
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	"github.com/scagogogo/nuget-config-parser/pkg/parser"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+const (
+	// DefaultPollInterval 默认的检查间隔
+	DefaultPollInterval = 500 * time.Millisecond
+
+	// DefaultDebounce 默认的防抖时长
+	DefaultDebounce = 200 * time.Millisecond
+)
+
+// Handler 配置文件变化时的回调
+//
+// 文件重新解析成功时 config 为新的配置，err 为 nil；解析失败时 config 为 nil，
+// err 为解析错误；文件被删除时 err 为 errors.ErrConfigFileNotFound。
+type Handler func(config *types.NuGetConfig, err error)
+
+// Watcher 监视配置文件，在文件变化后重新解析并调用回调
+//
+// Watcher 定期检查文件的修改时间、大小和内容摘要，不依赖平台相关的文件系统通知，
+// 因此在网络文件系统和容器挂载卷上同样可用。连续的多次写入在文件保持
+// Debounce 时长不变后只触发一次回调。回调在 Watcher 的 goroutine 中依次调用。
+type Watcher struct {
+	// PollInterval 检查文件的间隔，Start 之前设置，默认为 DefaultPollInterval
+	PollInterval time.Duration
+
+	// Debounce 文件变化后需要保持不变的时长，Start 之前设置，默认为 DefaultDebounce
+	Debounce time.Duration
+
+	// Parser 重新解析文件时使用的解析器，Start 之前设置，默认为 parser.NewConfigParser()
+	Parser *parser.ConfigParser
+
+	path     string
+	handler  Handler
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// fileState 文件的修改时间、大小和内容摘要，用于判断文件是否变化
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// NewWatcher 创建监视 path 的 Watcher，调用 Start 后开始监视
+func NewWatcher(path string, handler Handler) *Watcher {
+	return &Watcher{
+		PollInterval: DefaultPollInterval,
+		Debounce:     DefaultDebounce,
+		Parser:       parser.NewConfigParser(),
+		path:         path,
+		handler:      handler,
+	}
+}
+
+// Start 开始监视，文件不存在时返回 errors.ErrConfigFileNotFound
+func (w *Watcher) Start() error {
+	state := statFile(w.path)
+	if !state.exists {
+		return errors.ErrConfigFileNotFound
+	}
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(state)
+	return nil
+}
+
+// Stop 停止监视并等待正在执行的回调返回，可以重复调用
+func (w *Watcher) Stop() {
+	if w.stop == nil {
+		return
+	}
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// run 定期检查文件，文件变化并稳定后调用回调
+func (w *Watcher) run(last fileState) {
+	defer close(w.done)
+
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pending := false
+	var changedAt time.Time
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			state := statFile(w.path)
+			if state != last {
+				last = state
+				pending = true
+				changedAt = now
+				continue
+			}
+			if pending && now.Sub(changedAt) >= w.Debounce {
+				pending = false
+				w.notify(state)
+			}
+		}
+	}
+}
+
+// notify 重新解析文件并调用回调
+func (w *Watcher) notify(state fileState) {
+	if !state.exists {
+		w.handler(nil, errors.ErrConfigFileNotFound)
+		return
+	}
+
+	p := w.Parser
+	if p == nil {
+		p = parser.NewConfigParser()
+	}
+	config, err := p.ParseFromFile(w.path)
+	w.handler(config, err)
+}
+
+// statFile 获取文件当前的状态
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	state := fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	// 读取失败时摘要保持为零值，下次读取成功后仍会被视为变化
+	if data, err := os.ReadFile(path); err == nil {
+		state.sum = sha256.Sum256(data)
+	}
+	return state
+}
@@ -0,0 +1,151 @@
+package watcher
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scagogogo/nuget-config-parser/pkg/errors"
+	nugetTesting "github.com/scagogogo/nuget-config-parser/pkg/testing"
+	"github.com/scagogogo/nuget-config-parser/pkg/types"
+)
+
+// event 回调收到的结果
+type event struct {
+	config *types.NuGetConfig
+	err    error
+}
+
+func TestWatcher(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	nugetTesting.CreateNuGetConfigFile(t, configPath, nugetTesting.ValidNuGetConfig())
+
+	events := make(chan event, 10)
+	w := NewWatcher(configPath, func(config *types.NuGetConfig, err error) {
+		events <- event{config, err}
+	})
+	w.PollInterval = 5 * time.Millisecond
+	w.Debounce = 20 * time.Millisecond
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	wait := func() event {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(2 * time.Second):
+			t.Fatal("no change notification received")
+			return event{}
+		}
+	}
+
+	// 连续多次写入只触发一次回调
+	for i := 0; i < 3; i++ {
+		content := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <!-- write %d -->
+  <packageSources>
+    <add key="changed" value="https://changed.example.com/v3/index.json" />
+  </packageSources>
+</configuration>`, i)
+		writeFile(t, configPath, content)
+		time.Sleep(2 * time.Millisecond)
+	}
+	e := wait()
+	if e.err != nil {
+		t.Fatalf("callback error = %v", e.err)
+	}
+	if len(e.config.PackageSources.Add) != 1 || e.config.PackageSources.Add[0].Key != "changed" {
+		t.Errorf("callback config = %+v", e.config.PackageSources)
+	}
+	select {
+	case extra := <-events:
+		t.Errorf("unexpected extra notification: %+v", extra)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	writeFile(t, configPath, "<configuration><packageSources>")
+	if e := wait(); e.err == nil || e.config != nil {
+		t.Errorf("invalid content callback = %+v, want parse error", e)
+	}
+
+	os.Remove(configPath)
+	if e := wait(); !stderrors.Is(e.err, errors.ErrConfigFileNotFound) {
+		t.Errorf("removed file callback error = %v, want ErrConfigFileNotFound", e.err)
+	}
+
+	w.Stop()
+	w.Stop()
+}
+
+func TestWatcherSameSizeRewrite(t *testing.T) {
+	tempDir := nugetTesting.CreateTempDir(t)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "NuGet.Config")
+	template := `<configuration><packageSources><add key="%s" value="https://a.example.com/v3/index.json" /></packageSources></configuration>`
+	nugetTesting.CreateNuGetConfigFile(t, configPath, fmt.Sprintf(template, "first"))
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	events := make(chan event, 10)
+	w := NewWatcher(configPath, func(config *types.NuGetConfig, err error) {
+		events <- event{config, err}
+	})
+	w.PollInterval = 5 * time.Millisecond
+	w.Debounce = 20 * time.Millisecond
+	if err := w.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop()
+
+	// 大小相同、修改时间也被还原的重写
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(template, "other")), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	os.Chtimes(configPath, info.ModTime(), info.ModTime())
+
+	select {
+	case e := <-events:
+		if e.err != nil {
+			t.Fatalf("callback error = %v", e.err)
+		}
+		if len(e.config.PackageSources.Add) != 1 || e.config.PackageSources.Add[0].Key != "other" {
+			t.Errorf("callback config = %+v", e.config.PackageSources)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("same-size rewrite was not detected")
+	}
+}
+
+func TestWatcherMissingFile(t *testing.T) {
+	w := NewWatcher(filepath.Join(t.TempDir(), "missing.config"), func(*types.NuGetConfig, error) {})
+	if err := w.Start(); !stderrors.Is(err, errors.ErrConfigFileNotFound) {
+		t.Errorf("Start() error = %v, want ErrConfigFileNotFound", err)
+	}
+	w.Stop()
+}
+
+// writeFile 写入文件，并推后修改时间以免文件系统时间精度不足导致变化被忽略
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	info, _ := os.Stat(path)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if info != nil {
+		later := info.ModTime().Add(time.Second)
+		os.Chtimes(path, later, later)
+	}
+}